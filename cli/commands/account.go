@@ -34,6 +34,7 @@ import (
 	"github.com/swapnilsparsh/devsVPN/cli/flags"
 	"github.com/swapnilsparsh/devsVPN/cli/helpers"
 	"github.com/swapnilsparsh/devsVPN/daemon/api/types"
+	protocolTypes "github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/srverrors"
 	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
 	"golang.org/x/term"
@@ -255,6 +256,12 @@ func checkStatus() error {
 		return fmt.Errorf("API error: %v %v", stat.APIStatus, stat.APIErrorMessage)
 	}
 
+	setJSONResult(struct {
+		AccountID  string
+		DeviceName string `json:",omitempty"`
+		Status     protocolTypes.SessionStatusResp
+	}{helloResp.Session.AccountID, helloResp.Session.DeviceName, stat})
+
 	acc := stat.Account
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
 
@@ -36,6 +36,8 @@ type CmdAutoConnect struct {
 	flags.CmdInfo
 	status        bool
 	on_launch_val string // on/off
+	profile       string
+	profile_last  bool
 }
 
 func (c *CmdAutoConnect) Init() {
@@ -44,6 +46,8 @@ func (c *CmdAutoConnect) Init() {
 	c.Initialize("autoconnect", "Manage VPN auto-connection parameters")
 	c.BoolVar(&c.status, "status", false, "(default) Show settings")
 	c.StringVar(&c.on_launch_val, "on_launch", "", "[on/off]", "Autoconnect on daemon launch\nThis enables the VPN tunnel to startup as quickly as possible\nas the daemon is started early in the operating system boot process\nand before the privateLINE app (The GUI)")
+	c.StringVar(&c.profile, "profile", "", "NAME", "Use the connection profile for auto-connection (see 'profile' command)")
+	c.BoolVar(&c.profile_last, "profile_last", false, "Use the last connection parameters for auto-connection (do not use a connection profile)")
 
 }
 
@@ -63,6 +67,16 @@ func (c *CmdAutoConnect) Run() error {
 		isChanged = true
 	}
 
+	if len(c.profile) > 0 && c.profile_last {
+		return flags.ConflictingParameters{}
+	}
+	if len(c.profile) > 0 || c.profile_last {
+		if err := _proto.SetPreferences(string(service_types.Prefs_AutoconnectProfile), c.profile); err != nil {
+			return err
+		}
+		isChanged = true
+	}
+
 	// -status
 
 	// request updated daemon settings
@@ -112,6 +126,11 @@ func (c *CmdAutoConnect) printAutoconnectSettings(w *tabwriter.Writer) *tabwrite
 	}
 
 	daemonSettings := _proto.GetHelloResponse().DaemonSettings
+	setJSONResult(struct {
+		IsAutoconnectOnLaunch       bool
+		IsAutoconnectOnLaunchDaemon bool
+		AutoconnectProfile          string
+	}{daemonSettings.IsAutoconnectOnLaunch, daemonSettings.IsAutoconnectOnLaunchDaemon, daemonSettings.AutoconnectProfile})
 
 	aol := "Disabled"
 	if daemonSettings.IsAutoconnectOnLaunch && daemonSettings.IsAutoconnectOnLaunchDaemon {
@@ -119,6 +138,12 @@ func (c *CmdAutoConnect) printAutoconnectSettings(w *tabwriter.Writer) *tabwrite
 	}
 	fmt.Fprintf(w, "Autoconnect on daemon launch\t:\t%v\n", aol)
 
+	profile := "(last connection parameters)"
+	if len(daemonSettings.AutoconnectProfile) > 0 {
+		profile = daemonSettings.AutoconnectProfile
+	}
+	fmt.Fprintf(w, "Connection profile\t:\t%v\n", profile)
+
 	//inBackground := "Disabled"
 	//if daemonSettings.IsAutoconnectOnLaunchDaemon {
 	//	inBackground = "Enabled"
@@ -23,6 +23,8 @@
 package commands
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
@@ -32,10 +34,12 @@ import (
 	"time"
 
 	"github.com/swapnilsparsh/devsVPN/cli/cliplatform"
+	"github.com/swapnilsparsh/devsVPN/cli/flags"
 	"github.com/swapnilsparsh/devsVPN/cli/protocol"
 	apitypes "github.com/swapnilsparsh/devsVPN/daemon/api/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/srverrors"
 	"github.com/swapnilsparsh/devsVPN/daemon/splittun"
 	"github.com/swapnilsparsh/devsVPN/daemon/v2r"
 	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
@@ -48,6 +52,138 @@ func Initialize(proto *protocol.Client) {
 	_proto = proto
 }
 
+// Exit codes of the CLI process
+const (
+	ExitCodeSuccess           = 0
+	ExitCodeError             = 1 // general error
+	ExitCodeBadParameter      = 2 // bad/conflicting command line arguments or unknown command
+	ExitCodeDaemonUnavailable = 3 // unable to connect to the daemon
+	ExitCodeDaemonError       = 4 // the daemon responded with error
+	ExitCodeNotLoggedIn       = 5
+	ExitCodePermissionDenied  = 6 // the access token role does not allow the operation
+	ExitCodeSettingLocked     = 7 // the setting is locked by the daemon configuration file
+	ExitCodeEaaPassword       = 8 // Enhanced App Authentication password is wrong or not defined
+)
+
+// JSON output mode (global '-json' option).
+// All human-readable output is redirected to stderr; stdout receives only a single JSON document (JSONOutput).
+var (
+	_isJSONOutput bool
+	_jsonStdout   *os.File
+	_jsonResult   any
+)
+
+// JSONError - structured error info (JSON output mode)
+type JSONError struct {
+	Code     string // machine-readable error code (e.g. "bad_parameter", "not_logged_in")
+	ExitCode int
+	Message  string
+}
+
+// JSONOutput - the document printed to stdout in JSON output mode
+type JSONOutput struct {
+	Command string
+	Success bool
+	Data    any        `json:",omitempty"` // command result (based on daemon response types)
+	Error   *JSONError `json:",omitempty"`
+}
+
+// DaemonNotAvailable error - unable to connect to the daemon
+type DaemonNotAvailable struct {
+	Err error
+}
+
+func (e DaemonNotAvailable) Error() string {
+	return fmt.Sprintf("unable to connect to service: %v", e.Err)
+}
+
+func (e DaemonNotAvailable) Unwrap() error {
+	return e.Err
+}
+
+// EnableJSONOutput switches CLI to JSON output mode. Must be called before running a command.
+func EnableJSONOutput() {
+	if _isJSONOutput {
+		return
+	}
+	_isJSONOutput = true
+	_jsonStdout = os.Stdout
+	os.Stdout = os.Stderr
+}
+
+// IsJSONOutput returns true when CLI is in JSON output mode
+func IsJSONOutput() bool {
+	return _isJSONOutput
+}
+
+// setJSONResult saves the command result to be printed in JSON output mode
+func setJSONResult(v any) {
+	_jsonResult = v
+}
+
+// ErrorInfo converts an error to error code and process exit code
+func ErrorInfo(err error) (code string, exitCode int) {
+	if err == nil {
+		return "", ExitCodeSuccess
+	}
+
+	var (
+		errBadParam    flags.BadParameter
+		errConflicting flags.ConflictingParameters
+		errNoDaemon    DaemonNotAvailable
+		errNotLoggedIn srverrors.ErrorNotLoggedIn
+		errEaa         EaaEnabledOptionNotApplicable
+		errResp        types.ErrorResp
+	)
+
+	switch {
+	case errors.As(err, &errBadParam), errors.As(err, &errConflicting):
+		return "bad_parameter", ExitCodeBadParameter
+	case errors.As(err, &errNoDaemon):
+		return "daemon_unavailable", ExitCodeDaemonUnavailable
+	case errors.As(err, &errNotLoggedIn):
+		return "not_logged_in", ExitCodeNotLoggedIn
+	case errors.As(err, &errEaa):
+		return "eaa_not_applicable", ExitCodeBadParameter
+	case errors.As(err, &errResp):
+		switch errResp.ErrorType {
+		case types.ErrorPermissionDenied:
+			return "permission_denied", ExitCodePermissionDenied
+		case types.ErrorManagedSettingLocked:
+			return "setting_locked", ExitCodeSettingLocked
+		case types.ErrorParanoidModePasswordError:
+			return "eaa_password", ExitCodeEaaPassword
+		}
+		return "daemon_error", ExitCodeDaemonError
+	}
+	return "error", ExitCodeError
+}
+
+// PrintJSONOutput prints the result of the command (or the error) to stdout as JSON document.
+// Returns the process exit code.
+func PrintJSONOutput(command string, err error) int {
+	out := JSONOutput{Command: command, Success: err == nil}
+	code, exitCode := ErrorInfo(err)
+	if err != nil {
+		out.Error = &JSONError{Code: code, ExitCode: exitCode, Message: err.Error()}
+	} else {
+		out.Data = _jsonResult
+	}
+
+	data, e := json.MarshalIndent(out, "", "  ")
+	if e != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to serialize JSON output: %v\n", e)
+		return ExitCodeError
+	}
+
+	stdout := _jsonStdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	fmt.Fprintln(stdout, string(data))
+	return exitCode
+}
+
 func printAccountInfo(w *tabwriter.Writer, accountID string) *tabwriter.Writer {
 	if w == nil {
 		w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
@@ -103,6 +239,9 @@ func printState(w *tabwriter.Writer, state vpn.State, connected types.ConnectedR
 	}
 
 	fmt.Fprintf(w, "    Protocol\t:\t%v\n", protocol)
+	if len(connected.CustomServer) > 0 {
+		fmt.Fprintf(w, "    Custom server\t:\t%v\n", connected.CustomServer)
+	}
 	fmt.Fprintf(w, "    Local IP\t:\t%v\n", connected.ClientIP)
 	if len(connected.ClientIPv6) > 0 {
 		fmt.Fprintf(w, "    Local IPv6\t:\t%v\n", connected.ClientIPv6)
@@ -118,6 +257,9 @@ func printState(w *tabwriter.Writer, state vpn.State, connected types.ConnectedR
 		portInfo += fmt.Sprintf("%d)", connected.ServerPort)
 	}
 	fmt.Fprintf(w, "    Server IP\t:\t%v%v\n", connected.ServerIP, portInfo)
+	if connected.PathMtu > 0 {
+		fmt.Fprintf(w, "    MTU\t:\t%v (path MTU discovery)\n", connected.PathMtu)
+	}
 
 	fmt.Fprintf(w, "    Connected\t:\t%v\n", since)
 
@@ -363,6 +505,8 @@ func printParanoidModeState(w *tabwriter.Writer, helloResp types.HelloResp) *tab
 		w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
 	}
 
+	setJSONResult(helloResp.ParanoidMode)
+
 	pModeStatusText := "Disabled"
 	if helloResp.ParanoidMode.IsEnabled {
 		pModeStatusText = "Enabled"
@@ -0,0 +1,65 @@
+//
+//  IVPN command line interface (CLI)
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Created by Stelnykovych Alexandr.
+//  Copyright (c) 2023 IVPN Limited.
+//
+//  This file is part of the IVPN command line interface.
+//
+//  The IVPN command line interface is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The IVPN command line interface is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the IVPN command line interface. If not, see <https://www.gnu.org/licenses/>.
+//
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/swapnilsparsh/devsVPN/cli/flags"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/srverrors"
+)
+
+func TestErrorInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantCode     string
+		wantExitCode int
+	}{
+		{"no error", nil, "", ExitCodeSuccess},
+		{"bad parameter", flags.BadParameter{Message: "x"}, "bad_parameter", ExitCodeBadParameter},
+		{"conflicting parameters", flags.ConflictingParameters{Message: "x"}, "bad_parameter", ExitCodeBadParameter},
+		{"wrapped bad parameter", fmt.Errorf("parsing: %w", flags.BadParameter{}), "bad_parameter", ExitCodeBadParameter},
+		{"daemon not available", DaemonNotAvailable{Err: errors.New("refused")}, "daemon_unavailable", ExitCodeDaemonUnavailable},
+		{"not logged in", srverrors.ErrorNotLoggedIn{}, "not_logged_in", ExitCodeNotLoggedIn},
+		{"eaa not applicable", EaaEnabledOptionNotApplicable{}, "eaa_not_applicable", ExitCodeBadParameter},
+		{"permission denied", types.ErrorResp{ErrorType: types.ErrorPermissionDenied}, "permission_denied", ExitCodePermissionDenied},
+		{"setting locked", types.ErrorResp{ErrorType: types.ErrorManagedSettingLocked}, "setting_locked", ExitCodeSettingLocked},
+		{"eaa password", types.ErrorResp{ErrorType: types.ErrorParanoidModePasswordError}, "eaa_password", ExitCodeEaaPassword},
+		{"daemon error", types.ErrorResp{ErrorMessage: "failed"}, "daemon_error", ExitCodeDaemonError},
+		{"wrapped daemon error", fmt.Errorf("request: %w", types.ErrorResp{ErrorType: types.ErrorManagedSettingLocked}), "setting_locked", ExitCodeSettingLocked},
+		{"other error", errors.New("failed"), "error", ExitCodeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, exitCode := ErrorInfo(tt.err)
+			if code != tt.wantCode || exitCode != tt.wantExitCode {
+				t.Errorf("ErrorInfo() = (%q, %d), want (%q, %d)", code, exitCode, tt.wantCode, tt.wantExitCode)
+			}
+		})
+	}
+}
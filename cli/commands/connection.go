@@ -86,7 +86,7 @@ func (c *CmdDisconnect) Run() error {
 }
 
 // -----------------------------------------------
-const AllowedObfsproxyValues = "'obfs4' (default), 'obfs3', 'obfs4_iat' (or 'obfs4_iat1'), 'obfs4_iat_paranoid' (or 'obfs4_iat2')"
+const AllowedObfsproxyValues = "'obfs4' (default), 'obfs3', 'obfs4_iat' (or 'obfs4_iat1'), 'obfs4_iat_paranoid' (or 'obfs4_iat2'),\n  'meek', 'webtunnel' (WireGuard only; require '-obfsproxy_url')"
 
 func parseObfsproxyParam(param string) (obfsproxy.Config, error) {
 	switch strings.ToLower(param) {
@@ -100,6 +100,10 @@ func parseObfsproxyParam(param string) (obfsproxy.Config, error) {
 		return obfsproxy.Config{Version: obfsproxy.OBFS4, Obfs4Iat: obfsproxy.Obfs4IatOn}, nil
 	case "obfs4_iat2", "obfs4_iat_paranoid":
 		return obfsproxy.Config{Version: obfsproxy.OBFS4, Obfs4Iat: obfsproxy.Obfs4IatOnParanoid}, nil
+	case "meek", "meek_lite":
+		return obfsproxy.Config{Version: obfsproxy.MEEK}, nil
+	case "webtunnel":
+		return obfsproxy.Config{Version: obfsproxy.WEBTUNNEL}, nil
 	}
 
 	return obfsproxy.Config{}, fmt.Errorf("unsupported obfsproxy value '%s' (acceptable values: %s)", param, AllowedObfsproxyValues)
@@ -113,25 +117,67 @@ func parseV2RayParam(param string) (v2r.V2RayTransportType, error) {
 		return v2r.QUIC, nil
 	case "tcp":
 		return v2r.TCP, nil
+	case "ws", "websocket":
+		return v2r.WebSocket, nil
+	case "grpc":
+		return v2r.GRPC, nil
+	case "h2", "http2":
+		return v2r.HTTP2, nil
 	}
 
-	return v2r.None, fmt.Errorf("unsupported v2ray value '%s' (acceptable values: 'quic' or 'tcp')", param)
+	return v2r.None, fmt.Errorf("unsupported v2ray value '%s' (acceptable values: 'quic', 'tcp', 'ws', 'grpc' or 'h2')", param)
+}
+
+// parseV2RayOptions returns options for the TLS-based V2Ray transports (WebSocket, gRPC, HTTP/2)
+func (c *CmdConnect) parseV2RayOptions(v2rayType v2r.V2RayTransportType) (v2r.TransportOptions, error) {
+	opts := v2r.TransportOptions{
+		ServerName:  strings.TrimSpace(c.v2raySni),
+		Host:        strings.TrimSpace(c.v2rayHost),
+		Path:        strings.TrimSpace(c.v2rayPath),
+		Fingerprint: strings.ToLower(strings.TrimSpace(c.v2rayFingerprint)),
+	}
+	if reality := strings.TrimSpace(c.v2rayReality); len(reality) > 0 {
+		pbk, sid, _ := strings.Cut(reality, ":")
+		if len(pbk) == 0 {
+			return opts, fmt.Errorf("REALITY public key is not defined")
+		}
+		opts.Reality = &v2r.RealityOptions{PublicKey: pbk, ShortId: sid}
+	}
+
+	if opts == (v2r.TransportOptions{}) {
+		return opts, nil
+	}
+	if !v2rayType.IsTlsTransport() {
+		return opts, fmt.Errorf("V2Ray transport options are applicable only for 'ws', 'grpc' and 'h2' transports")
+	}
+	if opts.Reality != nil && v2rayType == v2r.WebSocket {
+		return opts, fmt.Errorf("REALITY is not applicable for 'ws' transport")
+	}
+	return opts, nil
 }
 
 type CmdConnect struct {
 	flags.CmdInfo
-	last            bool
-	gateway         string
-	port            string
-	portsShow       bool
-	any             bool
-	obfsproxy       string // 'obfs4' (default), 'obfs3', 'obfs4_iat' (or 'obfs4_iat1'), 'obfs4_iat_paranoid' (or 'obfs4_iat2')
-	v2rayProxy      string // `quic` or `tcp`
-	firewallOff     bool
-	dns             string
-	antitracker     bool
-	antitrackerHard bool
-	isIPv6Tunnel    bool
+	last       bool
+	gateway    string
+	port       string
+	portsShow  bool
+	any        bool
+	obfsproxy  string // 'obfs4' (default), 'obfs3', 'obfs4_iat' (or 'obfs4_iat1'), 'obfs4_iat_paranoid' (or 'obfs4_iat2'), 'meek', 'webtunnel'
+	obfsUrl    string // URL of HTTP transport ('meek', 'webtunnel')
+	obfsFront  string // front domain ('meek')
+	v2rayProxy string // `quic`, `tcp`, `ws`, `grpc` or `h2`
+
+	v2raySni         string
+	v2rayHost        string
+	v2rayPath        string
+	v2rayFingerprint string
+	v2rayReality     string // PUBLIC_KEY[:SHORT_ID]
+	firewallOff      bool
+	dns              string
+	antitracker      bool
+	antitrackerHard  bool
+	isIPv6Tunnel     bool
 
 	mtu int // MTU value (applicable only for WireGuard)
 
@@ -145,6 +191,12 @@ type CmdConnect struct {
 	multihopExitSvr string
 
 	fastest bool
+
+	autoTransport bool
+
+	profile string // name of the connection profile
+
+	custom string // ID or name of the user-imported server
 }
 
 func (c *CmdConnect) Init() {
@@ -168,6 +220,8 @@ func (c *CmdConnect) Init() {
 	c.BoolVar(&c.fastest, "fastest", false, "Connect to fastest server")
 	c.BoolVar(&c.last, "last", false, "Connect with the last used connection parameters")
 	c.BoolVar(&c.any, "any", false, "Use a random server from the found results to connect")
+	c.StringVar(&c.profile, "profile", "", "NAME", "Connect with the parameters of the connection profile (see 'profile' command)")
+	c.StringVar(&c.custom, "custom", "", "ID_OR_NAME", "Connect to the user-imported server (see 'custom' command)\n  (applicable options: '-fw_off', '-antitracker', '-antitracker_hard')")
 
 	// Multi-Hop
 	c.StringVar(&c.multihopExitSvr, "exit_svr", "", "LOCATION", "Exit-server for Multi-Hop connection\n  (use full serverID as a parameter, servers filtering not applicable for it)")
@@ -191,10 +245,18 @@ func (c *CmdConnect) Init() {
 	c.BoolVar(&c.antitrackerHard, "antitracker_hard", false, "Enable 'Hard Core' AntiTracker for this connection")
 
 	// Obfuscation flags
-	obfsproxyUsage := fmt.Sprintf("Use obfsproxy (OpenVPN only)\n  Acceptable values: %s", AllowedObfsproxyValues)
+	obfsproxyUsage := fmt.Sprintf("Use obfsproxy (for WireGuard: Single-Hop only)\n  Acceptable values: %s", AllowedObfsproxyValues)
 	c.StringVar(&c.obfsproxy, "o", "", "TYPE", obfsproxyUsage)
 	c.StringVar(&c.obfsproxy, "obfsproxy", "", "TYPE", obfsproxyUsage)
-	c.StringVar(&c.v2rayProxy, "v2ray", "", "TYPE", "Use V2Ray obfuscation (this option takes precedence over the '-obfsproxy' option)\n  Acceptable values: 'quic' (VMESS/QUIC) or 'tcp' (VMESS/TCP)")
+	c.StringVar(&c.obfsUrl, "obfsproxy_url", "", "URL", "URL of the 'meek' or 'webtunnel' transport endpoint (e.g. 'https://example.com/path')")
+	c.StringVar(&c.obfsFront, "obfsproxy_front", "", "DOMAIN", "Front domain for 'meek' transport (used for DNS and TLS SNI instead of the URL host)")
+	c.StringVar(&c.v2rayProxy, "v2ray", "", "TYPE", "Use V2Ray obfuscation (this option takes precedence over the '-obfsproxy' option)\n  Acceptable values: 'quic' (VMESS/QUIC), 'tcp' (VMESS/TCP),\n  'ws' (VMESS/WebSocket over TLS), 'grpc' (VMESS/gRPC over TLS) or 'h2' (VMESS/HTTP2 over TLS)")
+	c.StringVar(&c.v2raySni, "v2ray_sni", "", "NAME", "TLS server name (SNI) for 'ws', 'grpc' and 'h2' V2Ray transports\n  (default: DNS name of the server)")
+	c.StringVar(&c.v2rayHost, "v2ray_host", "", "HOST", "HTTP Host header for 'ws' and 'h2' V2Ray transports (default: SNI value)\n  (can differ from SNI when the connection is fronted by CDN)")
+	c.StringVar(&c.v2rayPath, "v2ray_path", "", "PATH", "Path for 'ws' and 'h2' V2Ray transports (default: '/')\n  or service name for 'grpc' transport")
+	c.StringVar(&c.v2rayFingerprint, "v2ray_fingerprint", "", "NAME", "TLS client fingerprint for 'ws', 'grpc' and 'h2' V2Ray transports\n  (e.g. 'chrome', 'firefox', 'safari', 'random'; requires Xray-core compatible V2Ray binary)")
+	c.StringVar(&c.v2rayReality, "v2ray_reality", "", "PUBLIC_KEY[:SHORT_ID]", "Use REALITY instead of TLS for 'grpc' and 'h2' V2Ray transports\n  (requires Xray-core compatible V2Ray binary)")
+	c.BoolVar(&c.autoTransport, "auto_transport", false, "Automatic transport mode (Single-Hop only): try WireGuard, V2Ray (QUIC, TCP) and OpenVPN over obfs4\n  until one of them connects; the transport which worked is remembered for the current network")
 }
 
 func (c *CmdConnect) preParse(arguments []string) ([]string, error) {
@@ -239,6 +301,18 @@ func (c *CmdConnect) preParse(arguments []string) ([]string, error) {
 
 // Run executes command
 func (c *CmdConnect) Run() (retError error) {
+	if len(c.profile) > 0 {
+		if len(c.gateway) > 0 || c.fastest || c.any || c.last || c.portsShow {
+			return flags.ConflictingParameters{}
+		}
+		return c.connectProfile()
+	}
+	if len(c.custom) > 0 {
+		if len(c.gateway) > 0 || c.fastest || c.any || c.last || c.portsShow || c.autoTransport || len(c.multihopExitSvr) > 0 {
+			return flags.ConflictingParameters{}
+		}
+		return c.connectCustom()
+	}
 
 	if len(c.gateway) == 0 && !c.fastest && !c.any && !c.last && !c.portsShow {
 		return flags.BadParameter{}
@@ -246,6 +320,9 @@ func (c *CmdConnect) Run() (retError error) {
 	if c.v2rayProxy != "" && c.obfsproxy != "" {
 		return flags.BadParameter{Message: "cannot use both '-v2ray' and '-obfsproxy' options"}
 	}
+	if c.autoTransport && (c.v2rayProxy != "" || c.obfsproxy != "" || len(c.multihopExitSvr) > 0) {
+		return flags.BadParameter{Message: "'-auto_transport' cannot be used with '-v2ray', '-obfsproxy' or '-exit_svr' options"}
+	}
 
 	// connection request
 	req := types.Connect{}
@@ -264,11 +341,27 @@ func (c *CmdConnect) Run() (retError error) {
 	if err != nil {
 		return flags.BadParameter{Message: err.Error()}
 	}
+	if len(c.obfsUrl) > 0 || len(c.obfsFront) > 0 {
+		if !obfsproxyCfg.IsHttpTransport() {
+			return flags.BadParameter{Message: "'-obfsproxy_url' and '-obfsproxy_front' are applicable only for 'meek' and 'webtunnel' obfsproxy"}
+		}
+		obfsproxyCfg.Url = c.obfsUrl
+		obfsproxyCfg.Front = c.obfsFront
+	}
+	if obfsproxyCfg.IsObfsproxy() {
+		if err := obfsproxyCfg.Validate(); err != nil {
+			return flags.BadParameter{Message: err.Error()}
+		}
+	}
 
 	v2rayCfg, err := parseV2RayParam(c.v2rayProxy)
 	if err != nil {
 		return flags.BadParameter{Message: err.Error()}
 	}
+	v2rayOpts, err := c.parseV2RayOptions(v2rayCfg)
+	if err != nil {
+		return flags.BadParameter{Message: err.Error()}
+	}
 
 	// check is logged-in
 	helloResp := _proto.GetHelloResponse()
@@ -287,12 +380,15 @@ func (c *CmdConnect) Run() (retError error) {
 	allowedPortsOvpn := servers.Config.Ports.OpenVPN
 
 	// Modify allowed ports according to V2Ray configuration
-	if v2rayCfg == v2r.TCP {
+	if v2rayCfg.IsTcpOutbound() {
 		if len(c.port) == 0 {
-			// If no port specified - use default V2Ray port for TCP
+			// If no port specified - use default V2Ray port: 80 for TCP; 443 for transports over TLS
 			c.port = "TCP:80"
+			if v2rayCfg.IsTlsTransport() {
+				c.port = "TCP:443"
+			}
 		}
-		// "V2Ray (VMESS/TCP)" connections are always TCP. So we modify port type for WireGuard allowed ports (v2ray listens on the same ports as WireGuard but on both UDP and TCP)
+		// "V2Ray (VMESS/TCP)" (and VMESS over TLS) connections are always TCP. So we modify port type for WireGuard allowed ports (v2ray listens on the same ports as WireGuard but on both UDP and TCP)
 		allowedPortsWg = []apitypes.PortInfo{}
 		for _, p := range servers.Config.Ports.WireGuard {
 			p.Type = "TCP"
@@ -514,6 +610,10 @@ func (c *CmdConnect) Run() (retError error) {
 					if v2rayCfg != v2r.None {
 						fmt.Println("V2Ray configuration: " + v2rayCfg.ToString())
 						req.Params.WireGuardParameters.V2RayProxy = v2rayCfg
+						req.Params.WireGuardParameters.V2RayOptions = v2rayOpts
+					} else if obfsproxyCfg.IsObfsproxy() { // Set obfsproxy config
+						fmt.Println("obfsproxy configuration: " + obfsproxyCfg.ToString())
+						req.Params.WireGuardParameters.Obfsproxy = obfsproxyCfg
 					}
 
 					req.Params.VpnType = vpn.WireGuard
@@ -607,7 +707,11 @@ func (c *CmdConnect) Run() (retError error) {
 					if v2rayCfg != v2r.None {
 						fmt.Println("V2Ray configuration: " + v2rayCfg.ToString())
 						req.Params.OpenVpnParameters.V2RayProxy = v2rayCfg
+						req.Params.OpenVpnParameters.V2RayOptions = v2rayOpts
 					} else if obfsproxyCfg.IsObfsproxy() { // Set obfsproxy config
+						if obfsproxyCfg.IsHttpTransport() {
+							return flags.BadParameter{Message: fmt.Sprintf("'%s' obfsproxy is applicable only for WireGuard connections", obfsproxyCfg.TransportName())}
+						}
 						fmt.Println("obfsproxy configuration: " + obfsproxyCfg.ToString())
 						req.Params.OpenVpnParameters.Obfs4proxy = obfsproxyCfg
 					}
@@ -722,6 +826,11 @@ func (c *CmdConnect) Run() (retError error) {
 		}
 	}
 
+	if c.autoTransport {
+		fmt.Println("Automatic transport mode: trying WireGuard, V2Ray and OpenVPN over obfs4 transports")
+		req.Params.AutoTransport = true
+	}
+
 	fmt.Println("Connecting...")
 	_, err = _proto.ConnectVPN(req)
 	if err != nil {
@@ -736,6 +845,60 @@ func (c *CmdConnect) Run() (retError error) {
 	return nil
 }
 
+// connectProfile connects with the parameters of the connection profile
+func (c *CmdConnect) connectProfile() error {
+	fmt.Printf("Connecting with profile '%s'...\n", c.profile)
+	if _, err := _proto.ConnectVPN(types.Connect{Profile: c.profile}); err != nil {
+		err = fmt.Errorf("failed to connect: %w", err)
+		fmt.Printf("Disconnecting...\n")
+		if err2 := _proto.DisconnectVPN(); err2 != nil {
+			fmt.Printf("Failed to disconnect: %v\n", err2)
+		}
+		return err
+	}
+
+	showState()
+	return nil
+}
+
+// connectCustom connects to the user-imported server
+func (c *CmdConnect) connectCustom() error {
+	req := types.Connect{}
+	req.Params.CustomServerID = c.custom
+
+	// Firewall for current connection
+	req.Params.FirewallOnDuringConnection = true
+	if c.firewallOff {
+		state, err := _proto.FirewallStatus()
+		if err != nil {
+			return fmt.Errorf("unable to check Firewall state: %w", err)
+		}
+		if !state.IsEnabled {
+			req.Params.FirewallOnDuringConnection = false
+		} else {
+			fmt.Println("WARNING! Firewall option ignored (Firewall already enabled manually)")
+		}
+	}
+
+	if c.antitracker || c.antitrackerHard {
+		req.Params.Metadata.AntiTracker.Enabled = true
+		req.Params.Metadata.AntiTracker.Hardcore = c.antitrackerHard
+	}
+
+	fmt.Printf("Connecting to custom server '%s'...\n", c.custom)
+	if _, err := _proto.ConnectVPN(req); err != nil {
+		err = fmt.Errorf("failed to connect: %w", err)
+		fmt.Printf("Disconnecting...\n")
+		if err2 := _proto.DisconnectVPN(); err2 != nil {
+			fmt.Printf("Failed to disconnect: %v\n", err2)
+		}
+		return err
+	}
+
+	showState()
+	return nil
+}
+
 func getPort(portInfo string, allowedPorts []apitypes.PortInfo) (port, error) {
 	var err error
 	var portPtr *int
@@ -758,7 +921,7 @@ func getPort(portInfo string, allowedPorts []apitypes.PortInfo) (port, error) {
 
 	if len(allowedPorts) > 0 {
 		if !isPortAllowed(allowedPorts[:], retPort) {
-			return port{}, fmt.Errorf(fmt.Sprintf("not allowed port '%s'", retPort.String()))
+			return port{}, fmt.Errorf("not allowed port '%s'", retPort.String())
 		}
 	}
 
@@ -766,12 +929,15 @@ func getPort(portInfo string, allowedPorts []apitypes.PortInfo) (port, error) {
 }
 
 func printAllowedPorts(allowedPortsWg, allowedOvpnPorts []apitypes.PortInfo, v2rayType v2r.V2RayTransportType) {
+	setJSONResult(struct {
+		WireGuard []apitypes.PortInfo
+		OpenVPN   []apitypes.PortInfo
+	}{allowedPortsWg, allowedOvpnPorts})
+
 	fmt.Printf("Allowed ports:\n")
 	v2RayPrefix := ""
-	if v2rayType == v2r.QUIC {
-		v2RayPrefix = " V2Ray(VMESS/QUIC)"
-	} else if v2rayType == v2r.TCP {
-		v2RayPrefix = " V2Ray(VMESS/TCP)"
+	if v2rayType != v2r.None {
+		v2RayPrefix = fmt.Sprintf(" V2Ray(VMESS/%s)", v2rayType.ToString())
 	}
 
 	if allowedPortsWg != nil {
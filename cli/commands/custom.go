@@ -0,0 +1,164 @@
+//  privateLINE Connect command line interface (CLI)
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the privateLINE Connect command line interface.
+//
+//  The privateLINE Connect command line interface is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The privateLINE Connect command line interface is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the privateLINE Connect command line interface. If not, see <https://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/swapnilsparsh/devsVPN/cli/cliplatform"
+	"github.com/swapnilsparsh/devsVPN/cli/flags"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+	service_types "github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+type CmdCustom struct {
+	flags.CmdInfo
+	list     bool
+	show     string
+	importF  string
+	name     string
+	username string
+	password string
+	remove   string
+}
+
+func (c *CmdCustom) Init() {
+	c.KeepArgsOrderInHelp = true
+	c.Initialize("custom", "Manage user-imported servers (\"bring your own server\")\nStandard WireGuard ('.conf') and OpenVPN ('.ovpn') configuration files can be imported\nTo connect to the imported server use 'connect -custom ID_OR_NAME'")
+	c.BoolVar(&c.list, "list", false, "(default) Show imported servers")
+	c.StringVar(&c.show, "show", "", "ID_OR_NAME", "Show details of the imported server")
+	c.StringVar(&c.importF, "import", "", "FILE", "Import WireGuard or OpenVPN configuration file\nExample:\n    "+cliplatform.CliExeName+" custom -import ~/wg0.conf -name 'home router'")
+	c.StringVar(&c.name, "name", "", "NAME", "Name of the imported server (use with '-import'; default: file name)")
+	c.StringVar(&c.username, "user", "", "USERNAME", "(OpenVPN) username, required when the configuration contains 'auth-user-pass' (use with '-import')")
+	c.StringVar(&c.password, "pass", "", "PASSWORD", "(OpenVPN) password (use with '-import')")
+	c.StringVar(&c.remove, "remove", "", "ID_OR_NAME", "Remove the imported server")
+}
+
+func (c *CmdCustom) Run() (err error) {
+	if countNonEmpty(c.show, c.importF, c.remove) > 1 || (c.list && countNonEmpty(c.show, c.importF, c.remove) > 0) {
+		return flags.ConflictingParameters{}
+	}
+	if len(c.importF) == 0 && countNonEmpty(c.name, c.username, c.password) > 0 {
+		return flags.BadParameter{Message: "'-name', '-user' and '-pass' are applicable only with '-import'"}
+	}
+
+	var resp types.CustomServersResp
+	switch {
+	case len(c.importF) > 0:
+		config, err := os.ReadFile(c.importF)
+		if err != nil {
+			return fmt.Errorf("failed to read configuration file: %w", err)
+		}
+		name := c.name
+		if len(name) == 0 {
+			name = strings.TrimSuffix(filepath.Base(c.importF), filepath.Ext(c.importF))
+		}
+		if resp, err = _proto.CustomServerImport(name, string(config), c.username, c.password); err != nil {
+			return err
+		}
+		if resp.Imported != nil {
+			setJSONResult(resp.Imported)
+			printCustomServer(nil, *resp.Imported).Flush()
+			return nil
+		}
+	case len(c.remove) > 0:
+		resp, err = _proto.CustomServerDelete(c.remove)
+	default:
+		resp, err = _proto.CustomServersGet()
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(c.show) > 0 {
+		idOrName := strings.TrimSpace(c.show)
+		for _, s := range resp.Servers {
+			if s.ID == idOrName || strings.EqualFold(s.Name, idOrName) {
+				setJSONResult(s)
+				printCustomServer(nil, s).Flush()
+				return nil
+			}
+		}
+		return fmt.Errorf("custom server '%s' not found", idOrName)
+	}
+
+	setJSONResult(resp)
+	printCustomServers(nil, resp.Servers).Flush()
+	return nil
+}
+
+// printCustomServers prints the list of user-imported servers
+func printCustomServers(w *tabwriter.Writer, servers []service_types.CustomServerInfo) *tabwriter.Writer {
+	if w == nil {
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	}
+
+	if len(servers) == 0 {
+		fmt.Fprintln(w, "No custom servers imported")
+		return w
+	}
+
+	fmt.Fprintln(w, "ID\tNAME\tPROTOCOL\tSERVER\tIMPORTED\t")
+	for _, s := range servers {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", s.ID, s.Name, s.VpnType, customServerAddressStr(s), s.Imported.Local().Format("2006-01-02 15:04"))
+	}
+	return w
+}
+
+// printCustomServer prints details of the user-imported server
+func printCustomServer(w *tabwriter.Writer, s service_types.CustomServerInfo) *tabwriter.Writer {
+	if w == nil {
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	}
+
+	fmt.Fprintf(w, "ID\t:\t%s\n", s.ID)
+	fmt.Fprintf(w, "Name\t:\t%s\n", s.Name)
+	fmt.Fprintf(w, "Protocol\t:\t%s\n", s.VpnType)
+	fmt.Fprintf(w, "Server\t:\t%s\n", customServerAddressStr(s))
+	if len(s.EndpointIP) > 0 && s.EndpointIP != s.Host {
+		fmt.Fprintf(w, "    Resolved IP\t:\t%s\n", s.EndpointIP)
+	}
+	if s.VpnType == vpn.WireGuard {
+		if len(s.AllowedIPs) > 0 {
+			fmt.Fprintf(w, "Allowed IPs\t:\t%s\n", s.AllowedIPs)
+		}
+		if len(s.DnsServers) > 0 {
+			fmt.Fprintf(w, "DNS\t:\t%s\n", strings.Join(s.DnsServers, ", "))
+		}
+	}
+	fmt.Fprintf(w, "Imported\t:\t%s\n", s.Imported.Local().Format("2006-01-02 15:04:05"))
+	for _, opt := range s.Ignored {
+		fmt.Fprintf(w, "Ignored option\t:\t%s\n", opt)
+	}
+	return w
+}
+
+func customServerAddressStr(s service_types.CustomServerInfo) string {
+	proto := "UDP"
+	if s.IsTCP {
+		proto = "TCP"
+	}
+	return fmt.Sprintf("%s:%d (%s)", s.Host, s.Port, proto)
+}
@@ -23,6 +23,7 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -33,6 +34,7 @@ import (
 	"github.com/swapnilsparsh/devsVPN/cli/cliplatform"
 	"github.com/swapnilsparsh/devsVPN/cli/flags"
 	apitypes "github.com/swapnilsparsh/devsVPN/daemon/api/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
 	service_types "github.com/swapnilsparsh/devsVPN/daemon/service/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
@@ -63,18 +65,18 @@ const (
 func IsParamApplicable_LinuxForceModifyResolvconf() (bool, error) {
 	// "force_use_resolvconf" is applicable only for linux AND only if both types of DNS management can be applied
 	if runtime.GOOS != "linux" {
-		return false, fmt.Errorf(fmt.Sprintf("functionality not applicable for %s", runtime.GOOS))
+		return false, fmt.Errorf("functionality not applicable for %s", runtime.GOOS)
 	}
 
 	if _proto != nil {
 		hr := _proto.GetHelloResponse()
 
 		if len(hr.DisabledFunctions.Platform.Linux.DnsMgmtOldResolvconfError) > 0 {
-			return false, fmt.Errorf(hr.DisabledFunctions.Platform.Linux.DnsMgmtOldResolvconfError)
+			return false, errors.New(hr.DisabledFunctions.Platform.Linux.DnsMgmtOldResolvconfError)
 		}
 
 		if len(hr.DisabledFunctions.Platform.Linux.DnsMgmtNewResolvectlError) > 0 {
-			return false, fmt.Errorf(hr.DisabledFunctions.Platform.Linux.DnsMgmtNewResolvectlError)
+			return false, errors.New(hr.DisabledFunctions.Platform.Linux.DnsMgmtNewResolvectlError)
 		}
 	}
 
@@ -305,7 +307,14 @@ func (c *CmdAntitracker) Run() error {
 		return err
 	}
 
+	result := struct {
+		VpnState           string
+		Dns                *types.DnsStatus `json:",omitempty"` // only when VPN is connected
+		DefaultAntiTracker service_types.AntiTrackerMetadata
+	}{VpnState: state.String(), DefaultAntiTracker: defConnCfg.Params.Metadata.AntiTracker}
+
 	if state == vpn.CONNECTED {
+		result.Dns = &connected.Dns
 		servers, _ := _proto.GetServers()
 		w = printDNSState(w, connected.Dns, &servers)
 	} else {
@@ -313,6 +322,7 @@ func (c *CmdAntitracker) Run() error {
 	}
 	w.Flush()
 
+	setJSONResult(result)
 	return nil
 }
 
@@ -400,6 +410,7 @@ func isAcceptableDnsBlockListName(atDnsServers []apitypes.AntiTrackerPlusServer)
 }*/
 
 func printBlockLists(atDnsServers []apitypes.AntiTrackerPlusServer) error {
+	setJSONResult(atDnsServers)
 	if len(atDnsServers) == 0 {
 		fmt.Println("No DNS block lists available")
 		return nil
@@ -24,8 +24,15 @@ package commands
 
 import (
 	"fmt"
+	"net"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode"
 
+	"github.com/swapnilsparsh/devsVPN/cli/cliplatform"
 	"github.com/swapnilsparsh/devsVPN/cli/flags"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
 )
 
 type CmdFirewall struct {
@@ -41,27 +48,67 @@ type CmdFirewall struct {
 	persistentOn       bool
 	persistentOff      bool
 	exceptions         string
-	//allowLanMulticast bool
-	//blockLanMulticast bool
+	exceptionAdd       string // 'add' subcommand
+	exceptionRemove    string // 'remove' subcommand
+	exceptionsList     bool   // 'list' subcommand
+	allowLanMulticast  bool
+	blockLanMulticast  bool
 }
 
 const StringValueNoData = "<!NO DATA!>"
 
 func (c *CmdFirewall) Init() {
-	c.Initialize("firewall", "Firewall management")
+	c.SetPreParseFunc(c.preParse)
+	c.Initialize("firewall", "Firewall management\nSubcommands:\n"+
+		"  add IP[/MASK]     Add IP address or subnet (using CIDR notation) to the firewall exceptions\n"+
+		"  remove IP[/MASK]  Remove IP address or subnet from the firewall exceptions\n"+
+		"  list              Show firewall exceptions (one per line)\n"+
+		"Examples:\n"+
+		"  "+cliplatform.CliExeName+" firewall add 192.0.2.0/24\n"+
+		"  "+cliplatform.CliExeName+" firewall remove 192.0.2.0/24\n"+
+		"  "+cliplatform.CliExeName+" firewall list")
 	c.BoolVar(&c.status, "status", false, "(default) Show info about current firewall status")
 	c.BoolVar(&c.off, "off", false, "Switch-off firewall")
 	c.BoolVar(&c.on, "on", false, "Switch-on firewall")
 	c.BoolVar(&c.cleanup, "cleanup", false, "Switch-off firewall and clean up all firewall objects")
 	c.BoolVar(&c.allowLan, "lan_allow", false, "Set configuration: allow LAN communication (take effect when firewall enabled)")
 	c.BoolVar(&c.blockLan, "lan_block", false, "Set configuration: block LAN communication (take effect when firewall enabled)")
+	c.BoolVar(&c.allowLanMulticast, "lan_multicast_allow", false, "Same as 'lan_allow' + allow multicast communication ")
+	c.BoolVar(&c.blockLanMulticast, "lan_multicast_block", false, "Same as 'lan_block' + block multicast communication")
 	c.BoolVar(&c.ivpnSvrAccessAllow, "ivpn_access_allow", false, "Allow access to IVPN servers when Firewall is enabled")
 	c.BoolVar(&c.ivpnSvrAccessBlock, "ivpn_access_block", false, "Block access to IVPN servers when Firewall is enabled")
 	c.BoolVar(&c.persistentOff, "persistent_off", false, "Persistent firewall (Always-on firewall): disable")
 	c.BoolVar(&c.persistentOn, "persistent_on", false, "Persistent firewall (Always-on firewall): enable. When the option is enabled the IVPN Firewall is started during system boot")
-	c.StringVar(&c.exceptions, "exceptions", StringValueNoData, "EXCEPTIONS", "Set configuration: comma-separated list of IP addresses or subnets (using CIDR notation)\nthat will be allowed through the firewall when enabled\nExamples:\n\tivpn firewall -exceptions '192.0.2.0/24, 198.51.100.1'\n\tivpn firewall -exceptions ''")
-	//c.BoolVar(&c.allowLanMulticast, "lan_multicast_allow", false, "Same as 'lan_allow' + allow multicast communication ")
-	//c.BoolVar(&c.blockLanMulticast, "lan_multicast_block", false, "Same as 'lan_block' + block multicast communication")
+	c.StringVar(&c.exceptions, "exceptions", StringValueNoData, "EXCEPTIONS", "Set configuration: comma-separated list of IP addresses or subnets (using CIDR notation)\nthat will be allowed through the firewall when enabled (replaces the whole list)\nExamples:\n\t"+cliplatform.CliExeName+" firewall -exceptions '192.0.2.0/24, 198.51.100.1'\n\t"+cliplatform.CliExeName+" firewall -exceptions ''")
+}
+
+// preParse takes the subcommand (with its argument) defined before the flags
+// (e.g. 'firewall add 192.0.2.0/24', 'firewall remove 192.0.2.0/24', 'firewall list')
+func (c *CmdFirewall) preParse(arguments []string) ([]string, error) {
+	if len(arguments) == 0 || strings.HasPrefix(arguments[0], "-") {
+		return arguments, nil
+	}
+
+	subcommand := strings.ToLower(arguments[0])
+	arguments = arguments[1:]
+
+	switch subcommand {
+	case "list":
+		c.exceptionsList = true
+		return arguments, nil
+	case "add", "remove":
+		if len(arguments) == 0 || strings.HasPrefix(arguments[0], "-") {
+			return nil, flags.BadParameter{Message: fmt.Sprintf("IP address or subnet expected for '%s'", subcommand)}
+		}
+		if subcommand == "add" {
+			c.exceptionAdd = arguments[0]
+		} else {
+			c.exceptionRemove = arguments[0]
+		}
+		return arguments[1:], nil
+	default:
+		return nil, flags.BadParameter{Message: fmt.Sprintf("unknown subcommand '%s' (expected: 'add', 'remove' or 'list')", subcommand)}
+	}
 }
 func (c *CmdFirewall) Run() error {
 	if c.on && c.off {
@@ -84,9 +131,17 @@ func (c *CmdFirewall) Run() error {
 		return flags.BadParameter{}
 	}
 
-	//if c.allowLanMulticast && c.blockLanMulticast {
-	//	return flags.BadParameter{}
-	//}
+	if c.allowLanMulticast && c.blockLanMulticast {
+		return flags.BadParameter{}
+	}
+
+	if (c.allowLanMulticast && c.blockLan) || (c.blockLanMulticast && c.allowLan) {
+		return flags.BadParameter{}
+	}
+
+	if c.exceptions != StringValueNoData && (len(c.exceptionAdd) > 0 || len(c.exceptionRemove) > 0) {
+		return flags.BadParameter{Message: "option 'exceptions' cannot be combined with 'add' or 'remove' subcommands"}
+	}
 
 	if c.ivpnSvrAccessAllow {
 		if err := _proto.FirewallAllowApiServers(true); err != nil {
@@ -98,32 +153,41 @@ func (c *CmdFirewall) Run() error {
 		}
 	}
 
-	if c.allowLan {
+	if c.allowLan || c.allowLanMulticast {
 		if err := _proto.FirewallAllowLan(true); err != nil {
 			return err
 		}
-	} else if c.blockLan {
+	} else if c.blockLan || c.blockLanMulticast {
 		if err := _proto.FirewallAllowLan(false); err != nil {
 			return err
 		}
 	}
 
-	//if c.allowLanMulticast {
-	//	if err := _proto.FirewallAllowLanMulticast(true); err != nil {
-	//		return err
-	//	}
-	//} else if c.blockLanMulticast {
-	//	if err := _proto.FirewallAllowLanMulticast(false); err != nil {
-	//		return err
-	//	}
-	//}
+	if c.allowLanMulticast {
+		if err := _proto.FirewallAllowLanMulticast(true); err != nil {
+			return err
+		}
+	} else if c.blockLanMulticast {
+		if err := _proto.FirewallAllowLanMulticast(false); err != nil {
+			return err
+		}
+	}
 
 	if c.exceptions != StringValueNoData {
+		if _, err := parseFirewallExceptions(c.exceptions); err != nil {
+			return flags.BadParameter{Message: err.Error()}
+		}
 		if err := _proto.FirewallSetUserExceptions(c.exceptions); err != nil {
 			return err
 		}
 	}
 
+	if len(c.exceptionAdd) > 0 || len(c.exceptionRemove) > 0 {
+		if err := c.updateExceptions(); err != nil {
+			return err
+		}
+	}
+
 	if c.persistentOn {
 		if err := _proto.FirewallPersistentSet(true); err != nil {
 			return err
@@ -160,7 +224,18 @@ func (c *CmdFirewall) Run() error {
 		return err
 	}
 
-	w := printFirewallState(nil, state.IsEnabled, state.IsPersistent, state.IsAllowLAN, state.IsAllowMulticast, state.IsAllowApiServers, state.WeHaveTopFirewallPriority, state.UserExceptions, nil)
+	if c.exceptionsList {
+		exceptions, _ := parseFirewallExceptions(state.UserExceptions)
+		setJSONResult(exceptions)
+		for _, e := range exceptions {
+			fmt.Println(e)
+		}
+		return nil
+	}
+
+	setJSONResult(state.KillSwitchStatus)
+
+	w := printFirewallStatusDetails(nil, state)
 	w.Flush()
 
 	// TIPS
@@ -174,3 +249,154 @@ func (c *CmdFirewall) Run() error {
 
 	return nil
 }
+
+// updateExceptions adds/removes single entries to/from the current list of firewall exceptions
+func (c *CmdFirewall) updateExceptions() error {
+	state, err := _proto.FirewallStatus()
+	if err != nil {
+		return err
+	}
+
+	exceptions, err := parseFirewallExceptions(state.UserExceptions)
+	if err != nil {
+		return fmt.Errorf("failed to parse current firewall exceptions: %w", err)
+	}
+
+	if len(c.exceptionRemove) > 0 {
+		toRemove, err := normalizeFirewallException(c.exceptionRemove)
+		if err != nil {
+			return flags.BadParameter{Message: err.Error()}
+		}
+		idx := indexOfString(exceptions, toRemove)
+		if idx < 0 {
+			return fmt.Errorf("'%s' is not in the firewall exceptions list", c.exceptionRemove)
+		}
+		exceptions = append(exceptions[:idx], exceptions[idx+1:]...)
+	}
+
+	if len(c.exceptionAdd) > 0 {
+		toAdd, err := normalizeFirewallException(c.exceptionAdd)
+		if err != nil {
+			return flags.BadParameter{Message: err.Error()}
+		}
+		if indexOfString(exceptions, toAdd) >= 0 {
+			fmt.Printf("'%s' is already in the firewall exceptions list\n", toAdd)
+		} else {
+			exceptions = append(exceptions, toAdd)
+		}
+	}
+
+	return _proto.FirewallSetUserExceptions(strings.Join(exceptions, ", "))
+}
+
+// parseFirewallExceptions splits the firewall exceptions string (the same way the daemon does)
+// into a list of normalized IP addresses/subnets without duplicates
+func parseFirewallExceptions(exceptions string) ([]string, error) {
+	splitFunc := func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsNumber(c) && c != rune('/') && c != rune('.') && c != rune(':')
+	}
+
+	ret := make([]string, 0)
+	for _, e := range strings.FieldsFunc(exceptions, splitFunc) {
+		n, err := normalizeFirewallException(e)
+		if err != nil {
+			return nil, err
+		}
+		if indexOfString(ret, n) < 0 {
+			ret = append(ret, n)
+		}
+	}
+	return ret, nil
+}
+
+// normalizeFirewallException validates IP address or subnet (CIDR notation) and returns it in canonical form.
+// Single addresses are returned without mask; subnets are returned as network address with mask.
+func normalizeFirewallException(exception string) (string, error) {
+	exception = strings.TrimSpace(exception)
+	if strings.Contains(exception, "/") {
+		_, n, err := net.ParseCIDR(exception)
+		if err != nil {
+			return "", fmt.Errorf("'%s' is not a valid subnet (CIDR notation expected)", exception)
+		}
+		if ones, bits := n.Mask.Size(); ones == bits {
+			return n.IP.String(), nil
+		}
+		return n.String(), nil
+	}
+
+	ip := net.ParseIP(exception)
+	if ip == nil {
+		return "", fmt.Errorf("'%s' is not a valid IP address", exception)
+	}
+	return ip.String(), nil
+}
+
+func indexOfString(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func printFirewallStatusDetails(w *tabwriter.Writer, state types.KillSwitchStatusResp) *tabwriter.Writer {
+	if w == nil {
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	}
+
+	fwState := "Disabled"
+	if state.IsEnabled {
+		fwState = "Enabled"
+	}
+	fmt.Fprintf(w, "Firewall\t:\t%v\n", fwState)
+	fmt.Fprintf(w, "    Persistent\t:\t%v\n", state.IsPersistent)
+	fmt.Fprintf(w, "    Have Top Firewall Priority\t:\t%v\n", state.WeHaveTopFirewallPriority)
+	if state.IsEnabled {
+		if state.WeHaveTopFirewallPriority {
+			fmt.Fprintf(w, "    VPN coexistence\t:\tGOOD\n")
+		} else {
+			fmt.Fprintf(w, "    VPN coexistence\t:\tFAILED\n")
+		}
+	}
+
+	fmt.Fprintf(w, "    Allow LAN\t:\t%v\n", state.IsAllowLAN)
+	fmt.Fprintf(w, "    Allow LAN multicast\t:\t%v\n", state.IsAllowMulticast)
+	fmt.Fprintf(w, "    Allow PL servers\t:\t%v\n", state.IsAllowApiServers)
+
+	exceptions, err := parseFirewallExceptions(state.UserExceptions)
+	if err != nil || len(exceptions) == 0 {
+		exceptions = []string{strings.TrimSpace(state.UserExceptions)}
+	}
+	if len(exceptions[0]) == 0 {
+		fmt.Fprintf(w, "    Exceptions\t:\t-\n")
+	} else {
+		for i, e := range exceptions {
+			if i == 0 {
+				fmt.Fprintf(w, "    Exceptions\t:\t%v\n", e)
+			} else {
+				fmt.Fprintf(w, "    \t\t%v\n", e)
+			}
+		}
+	}
+
+	if len(state.OtherVpnID) > 0 || len(state.OtherVpnName) > 0 {
+		otherVpn := state.OtherVpnName
+		if len(state.OtherVpnDescription) > 0 {
+			otherVpn += " (" + state.OtherVpnDescription + ")"
+		}
+		fmt.Fprintf(w, "Other VPN with top priority\t:\t%v\n", strings.TrimSpace(otherVpn))
+		if len(state.OtherVpnID) > 0 {
+			fmt.Fprintf(w, "    ID\t:\t%v\n", state.OtherVpnID)
+		}
+	}
+
+	if state.ReconfigurableOtherVpnsDetected {
+		fmt.Fprintf(w, "Other VPNs detected\t:\t%v\n", strings.Join(state.ReconfigurableOtherVpnsNames, ", "))
+	}
+	if state.NordVpnUpOnWindows {
+		fmt.Fprintf(w, "    NordVPN\t:\tmanual configuration required (see the app UI for instructions)\n")
+	}
+
+	return w
+}
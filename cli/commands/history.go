@@ -0,0 +1,105 @@
+//  privateLINE Connect command line interface (CLI)
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the privateLINE Connect command line interface.
+//
+//  The privateLINE Connect command line interface is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The privateLINE Connect command line interface is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the privateLINE Connect command line interface. If not, see <https://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/cli/flags"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/history"
+)
+
+type CmdHistory struct {
+	flags.CmdInfo
+	since string
+	types string
+	count int
+}
+
+func (c *CmdHistory) Init() {
+	c.Initialize("history", "Show connection events history\nEvent types: vpn_state, disconnected, autoconnect, firewall, pause, resume, healthcheck, schedule, failover, mtu, transport")
+	c.StringVar(&c.since, "since", "", "DURATION", "Show events for the time period (e.g. '30m', '12h', '168h')")
+	c.StringVar(&c.types, "type", "", "TYPES", "Show only events of specified types (comma-separated list)")
+	c.IntVar(&c.count, "n", 50, "COUNT", "Maximum number of latest events to show (0 - no limit)")
+}
+
+func (c *CmdHistory) Run() error {
+	var from time.Time
+	if len(c.since) > 0 {
+		period, err := time.ParseDuration(c.since)
+		if err != nil || period <= 0 {
+			return flags.BadParameter{Message: fmt.Sprintf("bad time period '%s' (expected e.g. '30m', '12h')", c.since)}
+		}
+		from = time.Now().Add(-period)
+	}
+
+	if c.count < 0 {
+		return flags.BadParameter{Message: "the number of events must not be negative"}
+	}
+
+	eventTypes, err := history.ParseEventTypes(c.types)
+	if err != nil {
+		return flags.BadParameter{Message: err.Error()}
+	}
+	typeNames := make([]string, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		typeNames = append(typeNames, string(t))
+	}
+
+	events, err := _proto.HistoryGet(from, time.Time{}, typeNames, c.count)
+	if err != nil {
+		return err
+	}
+	setJSONResult(events)
+	if len(events) == 0 {
+		fmt.Println("No events")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tEVENT\tDETAILS")
+	for _, e := range events {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e.Time.Local().Format("2006-01-02 15:04:05"), e.Type, eventDetails(e))
+	}
+	w.Flush()
+	return nil
+}
+
+func eventDetails(e history.Event) string {
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	details := make([]string, 0, len(keys)+1)
+	for _, k := range keys {
+		details = append(details, fmt.Sprintf("%s=%s", k, e.Fields[k]))
+	}
+	if len(e.Message) > 0 {
+		details = append(details, fmt.Sprintf("(%s)", e.Message))
+	}
+	return strings.Join(details, " ")
+}
@@ -27,24 +27,39 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/swapnilsparsh/devsVPN/cli/flags"
+	"github.com/swapnilsparsh/devsVPN/daemon/logger"
 	service_types "github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/platform"
 )
 
 type CmdLogs struct {
 	flags.CmdInfo
-	show    bool
-	enable  bool
-	disable bool
+	show      bool
+	enable    bool
+	disable   bool
+	component string
+	level     string
+	format    string
+	levels    string
+	rotation  string
+	since     string
 }
 
 func (c *CmdLogs) Init() {
 	c.Initialize("logs", "Logging management")
 	c.BoolVar(&c.show, "show", false, "(default) Show logs")
+	c.StringVar(&c.component, "component", "", "NAME", "Show only records of the component(s) (comma separated logger names, e.g. 'frwl,prefs')")
+	c.StringVar(&c.level, "level", "", "LEVEL", "Show only records with the level or higher: trace, debug, info, warning, error")
+	c.StringVar(&c.since, "since", "", "DURATION", "Show records for the time period (e.g. '30m', '12h'), including compressed archives")
 	c.BoolVar(&c.enable, "on", false, "Enable logging")
 	c.BoolVar(&c.disable, "off", false, "Disable logging")
+	c.StringVar(&c.format, "format", "", "FORMAT", "Set log format: 'text' or 'json' (structured JSON lines)")
+	c.StringVar(&c.levels, "levels", "", "CONFIG", "Set per-component log levels (e.g. 'frwl=trace,prefs=warn,*=info'; '-' - reset)")
+	c.StringVar(&c.rotation, "rotation", "", "CONFIG", "Set log rotation policy (e.g. 'size=16,age=168,archives=5,days=30'):\n  size - max log size (MB); age - max log age (hours);\n  archives - number of compressed archives to keep; days - max age of archives")
 }
 func (c *CmdLogs) Run() error {
 	if c.enable && c.disable {
@@ -57,13 +72,102 @@ func (c *CmdLogs) Run() error {
 	} else if c.disable {
 		err = c.setSetLogging(false)
 	}
-
-	if err != nil || c.enable || c.disable {
+	if err != nil {
 		return err
 	}
+
+	isConfigChanged := c.enable || c.disable
+	if len(c.format) > 0 {
+		isConfigChanged = true
+		if err := c.setFormat(c.format); err != nil {
+			return err
+		}
+	}
+	if len(c.levels) > 0 {
+		isConfigChanged = true
+		levels := c.levels
+		if levels == "-" {
+			levels = ""
+		}
+		if _, _, err := logger.ParseLevels(levels); err != nil {
+			return flags.BadParameter{Message: err.Error()}
+		}
+		if err := _proto.SetPreferences(string(service_types.Prefs_LogLevels), levels); err != nil {
+			return err
+		}
+	}
+
+	if len(c.rotation) > 0 {
+		isConfigChanged = true
+		if _, err := logger.ParseRotationPolicy(c.rotation, logger.RotationPolicy{}); err != nil {
+			return flags.BadParameter{Message: err.Error()}
+		}
+		if err := _proto.SetPreferences(string(service_types.Prefs_LogRotation), c.rotation); err != nil {
+			return err
+		}
+	}
+
+	if isConfigChanged {
+		return nil
+	}
+	if len(c.since) > 0 {
+		return c.doShowTimeWindow()
+	}
 	return c.doShow()
 }
 
+// doShowTimeWindow prints log records for the time period (the records are requested from the daemon)
+func (c *CmdLogs) doShowTimeWindow() error {
+	period, err := time.ParseDuration(c.since)
+	if err != nil || period <= 0 {
+		return flags.BadParameter{Message: fmt.Sprintf("bad time period '%s' (expected e.g. '30m', '12h')", c.since)}
+	}
+
+	filter, err := c.getFilter()
+	if err != nil {
+		return err
+	}
+
+	req := service_types.GenerateDiagnostics{
+		LogComponents: filter.Components,
+		LogMinLevel:   c.level,
+		LogFrom:       time.Now().Add(-period).Unix(),
+	}
+	resp, err := _proto.GetDiagnosticLogs(req)
+	if err != nil {
+		return err
+	}
+	setJSONResult(struct{ Log string }{resp.Log1_Active})
+	fmt.Print(resp.Log1_Active)
+	return nil
+}
+
+func (c *CmdLogs) setFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "text":
+		return _proto.SetPreferences(string(service_types.Prefs_IsLogStructured), "false")
+	case "json":
+		return _proto.SetPreferences(string(service_types.Prefs_IsLogStructured), "true")
+	}
+	return flags.BadParameter{Message: fmt.Sprintf("unknown log format '%s' (expected 'text' or 'json')", format)}
+}
+
+func (c *CmdLogs) getFilter() (filter logger.Filter, err error) {
+	if len(c.component) > 0 {
+		for _, comp := range strings.Split(c.component, ",") {
+			if comp = strings.TrimSpace(comp); len(comp) > 0 {
+				filter.Components = append(filter.Components, comp)
+			}
+		}
+	}
+	if len(c.level) > 0 {
+		if filter.MinLevel, err = logger.ParseLevel(c.level); err != nil {
+			return filter, flags.BadParameter{Message: err.Error()}
+		}
+	}
+	return filter, nil
+}
+
 func (c *CmdLogs) setSetLogging(enable bool) error {
 	if enable {
 		return _proto.SetPreferences(string(service_types.Prefs_IsEnableLogging), "true")
@@ -72,6 +176,10 @@ func (c *CmdLogs) setSetLogging(enable bool) error {
 }
 
 func (c *CmdLogs) doShow() error {
+	filter, err := c.getFilter()
+	if err != nil {
+		return err
+	}
 
 	isPartOfFile := false
 	isSomethingPrinted := false
@@ -99,6 +207,14 @@ func (c *CmdLogs) doShow() error {
 	size := stat.Size()
 
 	maxBytesToRead := int64(60 * 50)
+	maxBytesToPrint := maxBytesToRead
+	if !filter.IsEmpty() {
+		// read bigger part of the log: only the matching records will be printed
+		maxBytesToRead = 1024 * 512
+	}
+	if size < maxBytesToRead {
+		maxBytesToRead = size
+	}
 	if size > maxBytesToRead {
 		isPartOfFile = true
 		if _, err := file.Seek(-maxBytesToRead, io.SeekEnd); err != nil {
@@ -111,7 +227,21 @@ func (c *CmdLogs) doShow() error {
 		return err
 	}
 
-	fmt.Println(string(buff))
+	text := logger.FilterLogText(string(buff), filter)
+	if int64(len(text)) > maxBytesToPrint {
+		isPartOfFile = true
+		text = text[int64(len(text))-maxBytesToPrint:]
+		if idx := strings.Index(text, "\n"); idx >= 0 {
+			text = text[idx+1:] // start from the beginning of a line
+		}
+	}
+
+	setJSONResult(struct {
+		LogFile      string
+		IsPartOfFile bool
+		Log          string
+	}{fname, isPartOfFile, text})
+	fmt.Println(text)
 	isSomethingPrinted = true
 
 	if isPartOfFile {
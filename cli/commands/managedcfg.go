@@ -0,0 +1,82 @@
+//  privateLINE Connect command line interface (CLI)
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the privateLINE Connect command line interface.
+//
+//  The privateLINE Connect command line interface is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The privateLINE Connect command line interface is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the privateLINE Connect command line interface. If not, see <https://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/swapnilsparsh/devsVPN/cli/flags"
+)
+
+type CmdManagedConfig struct {
+	flags.CmdInfo
+	reload bool
+	dryRun bool
+}
+
+func (c *CmdManagedConfig) Init() {
+	c.Initialize("config", "Headless daemon configuration file (declarative provisioning)\nThe daemon reads the configuration file at startup and on SIGHUP.\nWhen the configuration is locked, clients are not allowed to change the managed settings.")
+	c.BoolVar(&c.dryRun, "dry-run", false, "(default) Validate the configuration file and show the changes which would be applied")
+	c.BoolVar(&c.reload, "reload", false, "Re-read the configuration file and apply it")
+}
+
+func (c *CmdManagedConfig) Run() error {
+	if c.reload && c.dryRun {
+		return flags.BadParameter{}
+	}
+
+	status, err := _proto.ManagedConfigApply(!c.reload)
+	if err != nil {
+		return err
+	}
+	setJSONResult(status)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "Configuration file\t:\t%s\n", status.File)
+	fmt.Fprintf(w, "Active\t:\t%t\n", status.IsLoaded)
+	if len(status.ManagedKeys) > 0 {
+		fmt.Fprintf(w, "Locked\t:\t%t\n", status.Locked)
+		fmt.Fprintf(w, "Managed settings\t:\t%s\n", strings.Join(status.ManagedKeys, ", "))
+	}
+	w.Flush()
+
+	if len(status.ManagedKeys) == 0 {
+		fmt.Println("No managed settings (the configuration file not found or empty)")
+		return nil
+	}
+
+	if len(status.Changes) == 0 {
+		fmt.Println("No changes")
+		return nil
+	}
+
+	if c.reload {
+		fmt.Println("Applied changes:")
+	} else {
+		fmt.Println("Changes to be applied:")
+	}
+	for _, ch := range status.Changes {
+		fmt.Println("  " + ch.String())
+	}
+	return nil
+}
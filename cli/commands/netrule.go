@@ -0,0 +1,274 @@
+//  privateLINE Connect command line interface (CLI)
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the privateLINE Connect command line interface.
+//
+//  The privateLINE Connect command line interface is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The privateLINE Connect command line interface is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the privateLINE Connect command line interface. If not, see <https://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/swapnilsparsh/devsVPN/cli/cliplatform"
+	"github.com/swapnilsparsh/devsVPN/cli/flags"
+	"github.com/swapnilsparsh/devsVPN/cli/helpers"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+)
+
+type CmdNetworkRule struct {
+	flags.CmdInfo
+	list    bool
+	add     string
+	remove  string
+	enable  string
+	disable string
+
+	// conditions
+	ifaceName     string
+	ifaceType     string
+	gatewayIP     string
+	gatewayMAC    string
+	dnsSuffix     string
+	reachableHost string
+	current       bool
+
+	// actions
+	profile        string
+	connectVpn     string // [on/off]
+	enableFirewall string // [on/off]
+	blockLan       string // [on/off]
+	totalShield    string // [on/off]
+	location       string
+	protocol       string
+	dns            string
+}
+
+func (c *CmdNetworkRule) Init() {
+	c.KeepArgsOrderInHelp = true
+	c.Initialize("netrule", "Manage network-condition rules\nA rule defines automatic actions applied when the current network (wired or wireless) matches the rule conditions.\nThe rules are evaluated on network changes; the first matching enabled rule is applied (only when there are no actions for the current WiFi network).")
+	c.BoolVar(&c.list, "list", false, "(default) Show the current network and the rules")
+	c.StringVar(&c.add, "add", "", "NAME", "Add (or replace) a rule. Use with condition and action parameters.\nExample:\n    "+cliplatform.CliExeName+" netrule -add office -interface_type ethernet -dns_suffix corp.example.com -connect_vpn off\n    "+cliplatform.CliExeName+" netrule -add docked -current -connect_vpn on -profile work")
+	c.StringVar(&c.remove, "remove", "", "NAME", "Remove the rule")
+	c.StringVar(&c.enable, "enable", "", "NAME", "Enable the rule")
+	c.StringVar(&c.disable, "disable", "", "NAME", "Disable the rule")
+
+	c.StringVar(&c.ifaceName, "interface", "", "NAME", "Condition: name of the default network interface (wildcards allowed, e.g. 'enx*')")
+	c.StringVar(&c.ifaceType, "interface_type", "", "TYPE", "Condition: type of the default network interface [ethernet/wifi/other]")
+	c.StringVar(&c.gatewayIP, "gateway_ip", "", "IP", "Condition: IP address of the default gateway")
+	c.StringVar(&c.gatewayMAC, "gateway_mac", "", "MAC", "Condition: MAC address of the default gateway")
+	c.StringVar(&c.dnsSuffix, "dns_suffix", "", "DOMAIN", "Condition: DNS search domain (e.g. provided by DHCP)")
+	c.StringVar(&c.reachableHost, "reachable", "", "HOST:PORT", "Condition: the internal host is reachable over TCP (e.g. 'intranet.corp.example.com:443')")
+	c.BoolVar(&c.current, "current", false, "Condition: the default gateway (IP and MAC) of the current network")
+
+	c.StringVar(&c.profile, "profile", "", "NAME", "Connection profile used to connect VPN (see 'profile' command)")
+	c.StringVar(&c.connectVpn, "connect_vpn", "", "[on/off]", "Action: connect (on) or disconnect (off) VPN")
+	c.StringVar(&c.enableFirewall, "firewall", "", "[on]", "Action: enable firewall (disabling is not supported)")
+	c.StringVar(&c.blockLan, "block_lan", "", "[on/off]", "Action: block LAN traffic")
+	c.StringVar(&c.totalShield, "total_shield", "", "[on/off]", "Action: enable Total Shield")
+	c.StringVar(&c.location, "location", "", "LOCATION", "Action: gateway ID or country code of the server to connect")
+	c.StringVar(&c.protocol, "protocol", "", "PROTOCOL", "Action: protocol to connect [wireguard/wireguard_v2ray_quic/wireguard_v2ray_tcp/openvpn]")
+	c.StringVar(&c.dns, "dns", "", "DNS_LIST", "Action: comma-separated list of custom DNS servers")
+}
+
+func (c *CmdNetworkRule) Run() (err error) {
+	if countNonEmpty(c.add, c.remove, c.enable, c.disable) > 1 || (c.list && countNonEmpty(c.add, c.remove, c.enable, c.disable) > 0) {
+		return flags.ConflictingParameters{}
+	}
+	isRuleParamsDefined := countNonEmpty(c.ifaceName, c.ifaceType, c.gatewayIP, c.gatewayMAC, c.dnsSuffix, c.reachableHost,
+		c.profile, c.connectVpn, c.enableFirewall, c.blockLan, c.totalShield, c.location, c.protocol, c.dns) > 0 || c.current
+	if isRuleParamsDefined && len(c.add) == 0 {
+		return flags.BadParameter{Message: "condition and action parameters are applicable only with '-add'"}
+	}
+
+	resp, err := _proto.NetworkRulesGet()
+	if err != nil {
+		return err
+	}
+
+	if len(c.add) > 0 || len(c.remove) > 0 || len(c.enable) > 0 || len(c.disable) > 0 {
+		rules := append([]preferences.NetworkRule{}, resp.Rules...)
+		switch {
+		case len(c.add) > 0:
+			rule, err := c.newRule(resp.CurrentNetwork)
+			if err != nil {
+				return err
+			}
+			if idx := networkRuleIndex(rules, rule.Name); idx >= 0 {
+				rules[idx] = rule
+			} else {
+				rules = append(rules, rule)
+			}
+		case len(c.remove) > 0:
+			idx := networkRuleIndex(rules, c.remove)
+			if idx < 0 {
+				return fmt.Errorf("network rule '%s' not found", strings.TrimSpace(c.remove))
+			}
+			rules = append(rules[:idx], rules[idx+1:]...)
+		default:
+			name := c.enable + c.disable
+			idx := networkRuleIndex(rules, name)
+			if idx < 0 {
+				return fmt.Errorf("network rule '%s' not found", strings.TrimSpace(name))
+			}
+			rules[idx].Disabled = len(c.disable) > 0
+		}
+
+		if resp, err = _proto.NetworkRulesSet(rules); err != nil {
+			return err
+		}
+	}
+
+	setJSONResult(resp)
+	printNetworkRules(nil, resp).Flush()
+	return nil
+}
+
+// newRule creates the rule according to the command parameters
+func (c *CmdNetworkRule) newRule(currentNetwork types.DefaultNetworkInfo) (rule preferences.NetworkRule, err error) {
+	rule.Name = strings.TrimSpace(c.add)
+	rule.Profile = c.profile
+
+	cond := &rule.Conditions
+	cond.InterfaceName = c.ifaceName
+	cond.InterfaceType = c.ifaceType
+	cond.GatewayIP = c.gatewayIP
+	cond.GatewayMAC = c.gatewayMAC
+	cond.DnsSuffix = c.dnsSuffix
+	cond.ReachableHost = c.reachableHost
+	if c.current {
+		if len(c.gatewayIP) > 0 || len(c.gatewayMAC) > 0 {
+			return rule, flags.ConflictingParameters{}
+		}
+		if len(currentNetwork.GatewayIP) == 0 {
+			return rule, fmt.Errorf("unable to obtain the default gateway of the current network")
+		}
+		cond.GatewayIP = currentNetwork.GatewayIP
+		cond.GatewayMAC = currentNetwork.GatewayMAC
+	}
+
+	onOff := func(val string) (*bool, error) {
+		if len(val) == 0 {
+			return nil, nil
+		}
+		v, err := helpers.BoolParameterParse(val)
+		if err != nil {
+			return nil, err
+		}
+		return &v, nil
+	}
+
+	act := &rule.Actions
+	if act.ConnectVpn, err = onOff(c.connectVpn); err != nil {
+		return rule, err
+	}
+	if act.EnableFirewall, err = onOff(c.enableFirewall); err != nil {
+		return rule, err
+	}
+	if act.BlockLan, err = onOff(c.blockLan); err != nil {
+		return rule, err
+	}
+	if act.TotalShield, err = onOff(c.totalShield); err != nil {
+		return rule, err
+	}
+	act.Location = c.location
+	act.Protocol = strings.ToLower(c.protocol)
+	for _, d := range strings.Split(c.dns, ",") {
+		if d = strings.TrimSpace(d); len(d) > 0 {
+			act.DnsServers = append(act.DnsServers, d)
+		}
+	}
+
+	if act.IsEmpty() && len(rule.Profile) == 0 {
+		return rule, flags.BadParameter{Message: "no actions defined for the rule"}
+	}
+	return rule, nil
+}
+
+func networkRuleIndex(rules []preferences.NetworkRule, name string) int {
+	for i, r := range rules {
+		if strings.EqualFold(r.Name, strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// printNetworkRules prints the state of the current network and the list of network rules
+func printNetworkRules(w *tabwriter.Writer, resp types.NetworkRulesResp) *tabwriter.Writer {
+	if w == nil {
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	}
+
+	valOrNone := func(v string) string {
+		if len(v) == 0 {
+			return "-"
+		}
+		return v
+	}
+
+	fmt.Fprintf(w, "Current network:\t\n")
+	if len(resp.CurrentNetworkError) > 0 {
+		fmt.Fprintf(w, "    Error\t:\t%s\n", resp.CurrentNetworkError)
+	} else {
+		n := resp.CurrentNetwork
+		fmt.Fprintf(w, "    Interface\t:\t%s (%s)\n", valOrNone(n.InterfaceName), valOrNone(n.InterfaceType))
+		fmt.Fprintf(w, "    Gateway\t:\t%s (MAC %s)\n", valOrNone(n.GatewayIP), valOrNone(n.GatewayMAC))
+		fmt.Fprintf(w, "    DNS search domains\t:\t%s\n", valOrNone(strings.Join(n.DnsSearchDomains, ", ")))
+	}
+	fmt.Fprintf(w, "    Matching rule\t:\t%s\n", valOrNone(resp.MatchedRule))
+
+	if len(resp.Rules) == 0 {
+		fmt.Fprintf(w, "Rules\t:\tnot defined\n")
+		return w
+	}
+
+	fmt.Fprintf(w, "Rules:\t\n")
+	for _, r := range resp.Rules {
+		state := ""
+		if r.Disabled {
+			state = " (disabled)"
+		}
+		fmt.Fprintf(w, "    %s%s\t:\t%s\n", r.Name, state, networkRuleConditionsStr(r.Conditions))
+		actions := wifiNetworkActionsStrings(r.Actions)
+		if len(r.Profile) > 0 {
+			actions = append([]string{"Profile: " + r.Profile}, actions...)
+		}
+		for _, a := range actions {
+			fmt.Fprintf(w, "    \t \t    %s\n", a)
+		}
+	}
+	return w
+}
+
+func networkRuleConditionsStr(c preferences.NetworkRuleConditions) string {
+	var conds []string
+	add := func(name, val string) {
+		if len(val) > 0 {
+			conds = append(conds, fmt.Sprintf("%s=%s", name, val))
+		}
+	}
+	add("interface", c.InterfaceName)
+	add("interface_type", c.InterfaceType)
+	add("gateway_ip", c.GatewayIP)
+	add("gateway_mac", c.GatewayMAC)
+	add("dns_suffix", c.DnsSuffix)
+	add("reachable", c.ReachableHost)
+	return strings.Join(conds, " AND ")
+}
@@ -0,0 +1,156 @@
+//  privateLINE Connect command line interface (CLI)
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the privateLINE Connect command line interface.
+//
+//  The privateLINE Connect command line interface is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The privateLINE Connect command line interface is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the privateLINE Connect command line interface. If not, see <https://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/cli/cliplatform"
+	"github.com/swapnilsparsh/devsVPN/cli/flags"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+)
+
+type CmdOpenVPN struct {
+	flags.CmdInfo
+	verbosity int
+}
+
+func (c *CmdOpenVPN) Init() {
+	c.Initialize("openvpn", "Show the details of the current OpenVPN connection: state, options pushed by the server and traffic statistics")
+	c.IntVar(&c.verbosity, "verb", -1, "LEVEL", "Set OpenVPN log verbosity [0-11] (0 - default)\nThe new value is applied to the active connection immediately\nExample:\n    "+cliplatform.CliExeName+" openvpn -verb 6")
+}
+
+func (c *CmdOpenVPN) Run() (err error) {
+	var resp types.OpenVpnDetailsResp
+	if c.verbosity >= 0 {
+		if c.verbosity > 11 {
+			return flags.BadParameter{Message: "verbosity must be in range [0-11]"}
+		}
+		resp, err = _proto.OpenVpnLogVerbositySet(c.verbosity)
+	} else {
+		resp, err = _proto.OpenVpnDetailsGet()
+	}
+	if err != nil {
+		return err
+	}
+
+	setJSONResult(resp)
+	printOpenVpnDetails(nil, resp).Flush()
+	return nil
+}
+
+// printOpenVpnDetails prints the details of the OpenVPN connection
+func printOpenVpnDetails(w *tabwriter.Writer, resp types.OpenVpnDetailsResp) *tabwriter.Writer {
+	if w == nil {
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	}
+
+	verbosity := "default"
+	if resp.LogVerbosity > 0 {
+		verbosity = fmt.Sprint(resp.LogVerbosity)
+	}
+	fmt.Fprintf(w, "Log verbosity\t:\t%s\n", verbosity)
+
+	d := resp.Details
+	if d == nil {
+		fmt.Fprintf(w, "Status\t:\tOpenVPN is not connected\n")
+		return w
+	}
+
+	st := d.State
+	state := st.State
+	if len(st.Description) > 0 {
+		state += " (" + st.Description + ")"
+	}
+	if st.Time > 0 {
+		state += ", since " + time.Unix(st.Time, 0).Local().Format(time.DateTime)
+	}
+	fmt.Fprintf(w, "State\t:\t%s\n", state)
+	if len(st.RemoteIP) > 0 {
+		fmt.Fprintf(w, "    Remote\t:\t%s:%d\n", st.RemoteIP, st.RemotePort)
+	}
+	if len(st.LocalIP) > 0 {
+		fmt.Fprintf(w, "    Local\t:\t%s:%d\n", st.LocalIP, st.LocalPort)
+	}
+	if len(st.LocalTunIP) > 0 {
+		fmt.Fprintf(w, "    Tunnel IP\t:\t%s\n", st.LocalTunIP)
+	}
+	if len(st.LocalTunIPv6) > 0 {
+		fmt.Fprintf(w, "    Tunnel IPv6\t:\t%s\n", st.LocalTunIPv6)
+	}
+
+	o := d.PushedOptions
+	fmt.Fprintf(w, "Pushed options:\t\n")
+	if len(o.Cipher) > 0 {
+		fmt.Fprintf(w, "    Cipher\t:\t%s\n", o.Cipher)
+	}
+	if len(o.IfconfigLocal) > 0 {
+		fmt.Fprintf(w, "    Ifconfig\t:\t%s %s (topology: %s)\n", o.IfconfigLocal, o.IfconfigNetmask, o.Topology)
+	}
+	if len(o.IfconfigIPv6) > 0 {
+		fmt.Fprintf(w, "    Ifconfig IPv6\t:\t%s\n", o.IfconfigIPv6)
+	}
+	if len(o.DnsServers) > 0 {
+		fmt.Fprintf(w, "    DNS\t:\t%s\n", strings.Join(o.DnsServers, ", "))
+	}
+	if len(o.DnsDomains) > 0 {
+		fmt.Fprintf(w, "    DNS domains\t:\t%s\n", strings.Join(o.DnsDomains, ", "))
+	}
+	if o.RedirectGateway {
+		fmt.Fprintf(w, "    Redirect gateway\t:\t%s\n", strings.TrimSpace("yes "+o.RedirectFlags))
+	}
+	if len(o.RouteGateway) > 0 {
+		fmt.Fprintf(w, "    Route gateway\t:\t%s\n", o.RouteGateway)
+	}
+	for _, r := range o.Routes {
+		route := strings.TrimSpace(strings.Join([]string{r.Network, r.Netmask, r.Gateway}, " "))
+		if r.Metric > 0 {
+			route += fmt.Sprintf(" (metric %d)", r.Metric)
+		}
+		fmt.Fprintf(w, "    Route\t:\t%s\n", route)
+	}
+	for _, r := range o.RoutesIPv6 {
+		fmt.Fprintf(w, "    Route IPv6\t:\t%s\n", r)
+	}
+	if o.PingSec > 0 || o.PingRestartSec > 0 {
+		fmt.Fprintf(w, "    Ping\t:\t%d sec (restart after %d sec)\n", o.PingSec, o.PingRestartSec)
+	}
+	if len(o.Other) > 0 {
+		fmt.Fprintf(w, "    Other\t:\t%s\n", strings.Join(o.Other, "; "))
+	}
+
+	s := d.Statistics
+	fmt.Fprintf(w, "Traffic (in/out)\t:\t%d / %d bytes\n", s.BytesIn, s.BytesOut)
+	if s.Updated > 0 {
+		fmt.Fprintf(w, "    TUN/TAP (read/write)\t:\t%d / %d bytes\n", s.TunReadBytes, s.TunWriteBytes)
+		fmt.Fprintf(w, "    TCP/UDP (read/write)\t:\t%d / %d bytes\n", s.LinkReadBytes, s.LinkWriteBytes)
+		fmt.Fprintf(w, "    Auth read\t:\t%d bytes\n", s.AuthReadBytes)
+		if s.PreCompressBytes > 0 || s.PreDecompressBytes > 0 {
+			fmt.Fprintf(w, "    Compress (pre/post)\t:\t%d / %d bytes\n", s.PreCompressBytes, s.PostCompressBytes)
+			fmt.Fprintf(w, "    Decompress (pre/post)\t:\t%d / %d bytes\n", s.PreDecompressBytes, s.PostDecompressBytes)
+		}
+		fmt.Fprintf(w, "    Updated\t:\t%s\n", time.Unix(s.Updated, 0).Local().Format(time.TimeOnly))
+	}
+	return w
+}
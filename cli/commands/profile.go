@@ -0,0 +1,201 @@
+//  privateLINE Connect command line interface (CLI)
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the privateLINE Connect command line interface.
+//
+//  The privateLINE Connect command line interface is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The privateLINE Connect command line interface is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the privateLINE Connect command line interface. If not, see <https://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/swapnilsparsh/devsVPN/cli/cliplatform"
+	"github.com/swapnilsparsh/devsVPN/cli/flags"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	service_types "github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+type CmdProfile struct {
+	flags.CmdInfo
+	list      bool
+	show      string
+	save      string
+	overwrite bool
+	rename    string
+	newName   string
+	delete    string
+}
+
+func (c *CmdProfile) Init() {
+	c.KeepArgsOrderInHelp = true
+	c.Initialize("profile", "Manage named connection profiles\nA profile can be used to connect ('connect -profile NAME'), for auto-connection ('autoconnect -profile NAME')\nor when joining a WiFi network ('wifi -set_network_profile')")
+	c.BoolVar(&c.list, "list", false, "(default) Show connection profiles")
+	c.StringVar(&c.show, "show", "", "NAME", "Show connection parameters of the profile")
+	c.StringVar(&c.save, "save", "", "NAME", "Save the last used connection parameters as a profile\nExample:\n    "+cliplatform.CliExeName+" profile -save 'home fastest WireGuard'")
+	c.BoolVar(&c.overwrite, "overwrite", false, "Replace the profile if it already exists (use with '-save')")
+	c.StringVar(&c.rename, "rename", "", "NAME", "Rename the profile (use with '-new_name')")
+	c.StringVar(&c.newName, "new_name", "", "NAME", "New name of the profile (use with '-rename')")
+	c.StringVar(&c.delete, "delete", "", "NAME", "Delete the profile\n(auto-connection and WiFi networks which use the profile will use the last connection parameters)")
+}
+
+func (c *CmdProfile) Run() (err error) {
+	if countNonEmpty(c.show, c.save, c.rename, c.delete) > 1 || (c.list && countNonEmpty(c.show, c.save, c.rename, c.delete) > 0) {
+		return flags.ConflictingParameters{}
+	}
+	if (len(c.rename) > 0) != (len(c.newName) > 0) {
+		return flags.BadParameter{Message: "'-rename' and '-new_name' must be used together"}
+	}
+	if c.overwrite && len(c.save) == 0 {
+		return flags.BadParameter{Message: "'-overwrite' is applicable only with '-save'"}
+	}
+
+	var resp types.ConnectionProfilesResp
+	switch {
+	case len(c.save) > 0:
+		resp, err = _proto.ConnectionProfileSaveLast(c.save, c.overwrite)
+	case len(c.rename) > 0:
+		resp, err = _proto.ConnectionProfileRename(c.rename, c.newName)
+	case len(c.delete) > 0:
+		resp, err = _proto.ConnectionProfileDelete(c.delete)
+	default:
+		resp, err = _proto.ConnectionProfilesGet()
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(c.show) > 0 {
+		for _, p := range resp.Profiles {
+			if strings.EqualFold(p.Name, strings.TrimSpace(c.show)) {
+				setJSONResult(p)
+				printConnectionProfile(nil, p).Flush()
+				return nil
+			}
+		}
+		return fmt.Errorf("connection profile '%s' not found", strings.TrimSpace(c.show))
+	}
+
+	setJSONResult(resp)
+	printConnectionProfiles(nil, resp.Profiles, resp.AutoconnectProfile).Flush()
+	return nil
+}
+
+// printConnectionProfiles prints the list of connection profiles
+func printConnectionProfiles(w *tabwriter.Writer, profiles []preferences.ConnectionProfile, autoconnectProfile string) *tabwriter.Writer {
+	if w == nil {
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	}
+
+	if len(profiles) == 0 {
+		fmt.Fprintln(w, "No connection profiles defined")
+		return w
+	}
+
+	fmt.Fprintln(w, "NAME\tPROTOCOL\tOBFUSCATION\tMODIFIED\t")
+	for _, p := range profiles {
+		autoconnect := ""
+		if strings.EqualFold(p.Name, autoconnectProfile) {
+			autoconnect = "(auto-connect)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.Name, profileProtocolStr(p.Params), profileObfuscationStr(p.Params), p.Modified.Local().Format("2006-01-02 15:04"), autoconnect)
+	}
+	return w
+}
+
+// printConnectionProfile prints connection parameters of the profile
+func printConnectionProfile(w *tabwriter.Writer, p preferences.ConnectionProfile) *tabwriter.Writer {
+	if w == nil {
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	}
+
+	boolToStr := func(v bool) string {
+		if v {
+			return "Enabled"
+		}
+		return "Disabled"
+	}
+
+	params := p.Params
+	fmt.Fprintf(w, "Name\t:\t%s\n", p.Name)
+	fmt.Fprintf(w, "Protocol\t:\t%s\n", profileProtocolStr(params))
+	fmt.Fprintf(w, "Obfuscation\t:\t%s\n", profileObfuscationStr(params))
+	if params.VpnType == vpn.WireGuard {
+		if params.WireGuardParameters.Port.Port > 0 {
+			fmt.Fprintf(w, "Port\t:\t%d\n", params.WireGuardParameters.Port.Port)
+		}
+		if params.WireGuardParameters.Mtu > 0 {
+			fmt.Fprintf(w, "MTU\t:\t%d\n", params.WireGuardParameters.Mtu)
+		}
+	} else if params.OpenVpnParameters.Port.Port > 0 {
+		fmt.Fprintf(w, "Port\t:\t%d\n", params.OpenVpnParameters.Port.Port)
+	}
+	fmt.Fprintf(w, "Multi-Hop\t:\t%s\n", boolToStr(params.IsMultiHop()))
+	fmt.Fprintf(w, "Server selection\t:\t%s\n", profileServerSelectionStr(params.Metadata.ServerSelectionEntry))
+	fmt.Fprintf(w, "AntiTracker\t:\t%s\n", boolToStr(params.Metadata.AntiTracker.IsEnabled()))
+	fmt.Fprintf(w, "IPv6 in tunnel\t:\t%s\n", boolToStr(params.IPv6))
+	fmt.Fprintf(w, "Firewall during connection\t:\t%s\n", boolToStr(params.FirewallOn || params.FirewallOnDuringConnection))
+	fmt.Fprintf(w, "Created\t:\t%s\n", p.Created.Local().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "Modified\t:\t%s\n", p.Modified.Local().Format("2006-01-02 15:04:05"))
+	return w
+}
+
+func profileProtocolStr(params service_types.ConnectionParams) string {
+	if params.IsCustomServer() {
+		return "custom server " + params.CustomServerID
+	}
+	if params.IsMultiHop() {
+		return params.VpnType.String() + " (Multi-Hop)"
+	}
+	return params.VpnType.String()
+}
+
+func profileObfuscationStr(params service_types.ConnectionParams) string {
+	if params.AutoTransport && !params.IsMultiHop() {
+		return "automatic"
+	}
+	if params.VpnType == vpn.WireGuard {
+		if v2ray := params.WireGuardParameters.V2RayProxy.ToString(); len(v2ray) > 0 {
+			return "V2Ray " + v2ray
+		}
+		if params.WireGuardParameters.Obfsproxy.IsObfsproxy() {
+			return params.WireGuardParameters.Obfsproxy.ToString()
+		}
+		return "-"
+	}
+	if v2ray := params.OpenVpnParameters.V2RayProxy.ToString(); len(v2ray) > 0 {
+		return "V2Ray " + v2ray
+	}
+	if params.OpenVpnParameters.Obfs4proxy.IsObfsproxy() {
+		return params.OpenVpnParameters.Obfs4proxy.ToString()
+	}
+	return "-"
+}
+
+func profileServerSelectionStr(s service_types.ServerSelectionEnum) string {
+	switch s {
+	case service_types.Fastest:
+		return "fastest"
+	case service_types.Random:
+		return "random"
+	default:
+		return "default"
+	}
+}
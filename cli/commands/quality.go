@@ -0,0 +1,153 @@
+//  privateLINE Connect command line interface (CLI)
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the privateLINE Connect command line interface.
+//
+//  The privateLINE Connect command line interface is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The privateLINE Connect command line interface is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the privateLINE Connect command line interface. If not, see <https://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/cli/cliplatform"
+	"github.com/swapnilsparsh/devsVPN/cli/flags"
+	"github.com/swapnilsparsh/devsVPN/cli/helpers"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+)
+
+type CmdQuality struct {
+	flags.CmdInfo
+	failover        string
+	checkInterval   int
+	maxHandshakeAge int
+	maxRtt          int
+	maxLoss         int
+	stallTimeout    int
+	badChecks       int
+}
+
+func (c *CmdQuality) Init() {
+	c.KeepArgsOrderInHelp = true
+	c.Initialize("quality", "Show the WireGuard connection quality and manage the thresholds of the connection-quality monitor\nWhen the failover is enabled and thresholds are crossed on consecutive checks, the VPN reconnects to the next-best host of the registered server.\nThe threshold value 0 disables the corresponding check.")
+	c.StringVar(&c.failover, "failover", "", "<on/off>", "Enable/disable automatic failover to the next-best host of the registered server\nExample:\n    "+cliplatform.CliExeName+" quality -failover on -max_rtt 800 -bad_checks 3")
+	c.IntVar(&c.checkInterval, "check_interval", -1, "SECONDS", "Interval between quality checks [1-600]")
+	c.IntVar(&c.maxHandshakeAge, "max_handshake_age", -1, "SECONDS", "Maximum age of the latest WireGuard handshake (min. 150)")
+	c.IntVar(&c.maxRtt, "max_rtt", -1, "MS", "Maximum round-trip time to the VPN gateway")
+	c.IntVar(&c.maxLoss, "max_loss", -1, "PERCENT", "Maximum packet loss to the VPN gateway [0-100]")
+	c.IntVar(&c.stallTimeout, "stall_timeout", -1, "SECONDS", "Maximum time when data is sent but nothing is received (min. 150)")
+	c.IntVar(&c.badChecks, "bad_checks", -1, "COUNT", "Number of consecutive failed checks required to fail over [1-100]")
+}
+
+func (c *CmdQuality) Run() (err error) {
+	resp, err := _proto.ConnectionQualityGet()
+	if err != nil {
+		return err
+	}
+
+	params := resp.Params
+	isChanged := false
+	if len(c.failover) > 0 {
+		if params.FailoverEnabled, err = helpers.BoolParameterParse(c.failover); err != nil {
+			return err
+		}
+		isChanged = true
+	}
+	for _, v := range []struct {
+		val    int
+		target *int
+	}{
+		{c.checkInterval, &params.CheckIntervalSec},
+		{c.maxHandshakeAge, &params.MaxHandshakeAgeSec},
+		{c.maxRtt, &params.MaxRttMs},
+		{c.maxLoss, &params.MaxPacketLossPercent},
+		{c.stallTimeout, &params.StallTimeoutSec},
+		{c.badChecks, &params.BadChecksToFailover},
+	} {
+		if v.val >= 0 {
+			*v.target = v.val
+			isChanged = true
+		}
+	}
+
+	if isChanged {
+		if err := params.Validate(); err != nil {
+			return flags.BadParameter{Message: err.Error()}
+		}
+		if resp, err = _proto.ConnectionQualitySet(params); err != nil {
+			return err
+		}
+	}
+
+	setJSONResult(resp)
+	printConnectionQuality(nil, resp).Flush()
+	return nil
+}
+
+// printConnectionQuality prints the thresholds and the latest results of the connection-quality monitor
+func printConnectionQuality(w *tabwriter.Writer, resp types.ConnectionQualityResp) *tabwriter.Writer {
+	if w == nil {
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	}
+
+	threshold := func(v int, units string) string {
+		if v <= 0 {
+			return "disabled"
+		}
+		return fmt.Sprintf("%d %s", v, units)
+	}
+
+	p := resp.Params
+	failover := "Disabled"
+	if p.FailoverEnabled {
+		failover = fmt.Sprintf("Enabled (after %d failed checks)", p.BadChecksToFailover)
+	}
+	fmt.Fprintf(w, "Failover\t:\t%s\n", failover)
+	fmt.Fprintf(w, "Thresholds:\t\n")
+	fmt.Fprintf(w, "    Check interval\t:\t%d sec\n", p.CheckIntervalSec)
+	fmt.Fprintf(w, "    Handshake age\t:\t%s\n", threshold(p.MaxHandshakeAgeSec, "sec"))
+	fmt.Fprintf(w, "    Round-trip time\t:\t%s\n", threshold(p.MaxRttMs, "ms"))
+	fmt.Fprintf(w, "    Packet loss\t:\t%s\n", threshold(p.MaxPacketLossPercent, "%"))
+	fmt.Fprintf(w, "    Stall timeout\t:\t%s\n", threshold(p.StallTimeoutSec, "sec"))
+
+	s := resp.Status
+	if !s.IsActive || s.LastCheck.IsZero() {
+		fmt.Fprintf(w, "Status\t:\tnot monitored (WireGuard is not connected)\n")
+	} else {
+		status := "OK"
+		if len(s.Problems) > 0 {
+			status = fmt.Sprintf("%s (failed checks: %d)", strings.Join(s.Problems, "; "), s.BadChecks)
+		}
+		fmt.Fprintf(w, "Status\t:\t%s\n", status)
+		fmt.Fprintf(w, "    Server host\t:\t%s\n", s.Host)
+		fmt.Fprintf(w, "    Last check\t:\t%s\n", s.LastCheck.Local().Format(time.TimeOnly))
+		fmt.Fprintf(w, "    Handshake age\t:\t%d sec\n", s.HandshakeAgeSec)
+		fmt.Fprintf(w, "    Round-trip time\t:\t%d ms\n", s.RttMs)
+		fmt.Fprintf(w, "    Packet loss\t:\t%d %%\n", s.PacketLossPercent)
+		if s.StalledSec > 0 {
+			fmt.Fprintf(w, "    Stalled\t:\t%d sec\n", s.StalledSec)
+		}
+		fmt.Fprintf(w, "    Traffic (rx/tx)\t:\t%d / %d bytes\n", s.ReceivedBytes, s.SentBytes)
+	}
+
+	if f := s.LastFailover; f != nil {
+		fmt.Fprintf(w, "Last failover\t:\t%s: %s -> %s (%s)\n", f.Time.Local().Format(time.DateTime), f.FromHost, f.ToHost, f.Reason)
+	}
+	return w
+}
@@ -0,0 +1,195 @@
+//  privateLINE Connect command line interface (CLI)
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the privateLINE Connect command line interface.
+//
+//  The privateLINE Connect command line interface is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The privateLINE Connect command line interface is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the privateLINE Connect command line interface. If not, see <https://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/swapnilsparsh/devsVPN/cli/cliplatform"
+	"github.com/swapnilsparsh/devsVPN/cli/flags"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+)
+
+type CmdSchedule struct {
+	flags.CmdInfo
+	list    bool
+	add     string
+	remove  string
+	enable  string
+	disable string
+
+	// rule parameters
+	action  string
+	days    string
+	start   string
+	end     string
+	profile string
+}
+
+func (c *CmdSchedule) Init() {
+	c.KeepArgsOrderInHelp = true
+	c.Initialize("schedule", "Manage time-of-day rules\nA rule with the end time defines a time window: the VPN state is applied when the window starts (or when the daemon starts within the window)\nand the original state is restored when the window ends. A rule without the end time is a one-shot daily action.")
+	c.BoolVar(&c.list, "list", false, "(default) Show the rules and the next scheduled transition")
+	c.StringVar(&c.add, "add", "", "NAME", "Add (or replace) a rule. Use with rule parameters.\nExample:\n    "+cliplatform.CliExeName+" schedule -add work -action connect -days weekdays -start 08:00 -end 18:00 -profile work\n    "+cliplatform.CliExeName+" schedule -add night -action disconnect -start 23:00 -end 07:00\n    "+cliplatform.CliExeName+" schedule -add lunch-end -action resume -start 13:00")
+	c.StringVar(&c.remove, "remove", "", "NAME", "Remove the rule")
+	c.StringVar(&c.enable, "enable", "", "NAME", "Enable the rule")
+	c.StringVar(&c.disable, "disable", "", "NAME", "Disable the rule")
+
+	c.StringVar(&c.action, "action", "", "ACTION", "Rule action [connect/disconnect/resume]")
+	c.StringVar(&c.days, "days", "", "DAYS", "Comma-separated list of week days (e.g. 'mon,wed,fri'), 'weekdays' or 'weekend' (default: every day)")
+	c.StringVar(&c.start, "start", "", "HH:MM", "Start time (local)")
+	c.StringVar(&c.end, "end", "", "HH:MM", "End time (local) of the rule time window; it can be on the next day (e.g. '-start 23:00 -end 07:00')")
+	c.StringVar(&c.profile, "profile", "", "NAME", "Connection profile used to connect VPN (see 'profile' command)")
+}
+
+func (c *CmdSchedule) Run() (err error) {
+	if countNonEmpty(c.add, c.remove, c.enable, c.disable) > 1 || (c.list && countNonEmpty(c.add, c.remove, c.enable, c.disable) > 0) {
+		return flags.ConflictingParameters{}
+	}
+	if countNonEmpty(c.action, c.days, c.start, c.end, c.profile) > 0 && len(c.add) == 0 {
+		return flags.BadParameter{Message: "rule parameters are applicable only with '-add'"}
+	}
+
+	resp, err := _proto.ScheduleRulesGet()
+	if err != nil {
+		return err
+	}
+
+	if len(c.add) > 0 || len(c.remove) > 0 || len(c.enable) > 0 || len(c.disable) > 0 {
+		rules := append([]preferences.ScheduleRule{}, resp.Rules...)
+		switch {
+		case len(c.add) > 0:
+			if len(c.action) == 0 || len(c.start) == 0 {
+				return flags.BadParameter{Message: "'-action' and '-start' parameters are required"}
+			}
+			rule := preferences.ScheduleRule{
+				Name:    strings.TrimSpace(c.add),
+				Days:    parseScheduleDays(c.days),
+				Start:   c.start,
+				End:     c.end,
+				Action:  preferences.ScheduleAction(strings.ToLower(c.action)),
+				Profile: c.profile,
+			}
+			if idx := scheduleRuleIndex(rules, rule.Name); idx >= 0 {
+				rules[idx] = rule
+			} else {
+				rules = append(rules, rule)
+			}
+		case len(c.remove) > 0:
+			idx := scheduleRuleIndex(rules, c.remove)
+			if idx < 0 {
+				return fmt.Errorf("schedule rule '%s' not found", strings.TrimSpace(c.remove))
+			}
+			rules = append(rules[:idx], rules[idx+1:]...)
+		default:
+			name := c.enable + c.disable
+			idx := scheduleRuleIndex(rules, name)
+			if idx < 0 {
+				return fmt.Errorf("schedule rule '%s' not found", strings.TrimSpace(name))
+			}
+			rules[idx].Disabled = len(c.disable) > 0
+		}
+
+		if resp, err = _proto.ScheduleRulesSet(rules); err != nil {
+			return err
+		}
+	}
+
+	setJSONResult(resp)
+	printScheduleRules(nil, resp).Flush()
+	return nil
+}
+
+// parseScheduleDays converts the comma-separated list of week days into the list of day names
+// ('weekdays' and 'weekend' are expanded)
+func parseScheduleDays(days string) (ret []string) {
+	for _, d := range strings.Split(days, ",") {
+		switch d = strings.ToLower(strings.TrimSpace(d)); d {
+		case "":
+		case "weekdays":
+			ret = append(ret, "mon", "tue", "wed", "thu", "fri")
+		case "weekend":
+			ret = append(ret, "sat", "sun")
+		default:
+			ret = append(ret, d)
+		}
+	}
+	return ret
+}
+
+func scheduleRuleIndex(rules []preferences.ScheduleRule, name string) int {
+	for i, r := range rules {
+		if strings.EqualFold(r.Name, strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// printScheduleRules prints the list of time-of-day rules and the next scheduled transition
+func printScheduleRules(w *tabwriter.Writer, resp types.ScheduleRulesResp) *tabwriter.Writer {
+	if w == nil {
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	}
+
+	if len(resp.Rules) == 0 {
+		fmt.Fprintf(w, "Rules\t:\tnot defined\n")
+		return w
+	}
+
+	fmt.Fprintf(w, "Rules:\t\n")
+	for _, r := range resp.Rules {
+		state := ""
+		if r.Disabled {
+			state = " (disabled)"
+		}
+		days := "every day"
+		if len(r.Days) > 0 {
+			days = strings.Join(r.Days, ",")
+		}
+		period := r.Start
+		if r.IsWindow() {
+			period = r.Start + "-" + r.End
+		}
+		action := string(r.Action)
+		if len(r.Profile) > 0 {
+			action += " (profile: " + r.Profile + ")"
+		}
+		fmt.Fprintf(w, "    %s%s\t:\t%s %s: %s\n", r.Name, state, days, period, action)
+	}
+
+	if len(resp.ActiveRules) > 0 {
+		fmt.Fprintf(w, "Active now\t:\t%s\n", strings.Join(resp.ActiveRules, ", "))
+	}
+	if t := resp.NextTransition; t != nil {
+		event := string(t.Action)
+		if t.WindowEnd {
+			event = "end of '" + string(t.Action) + "' window"
+		}
+		fmt.Fprintf(w, "Next transition\t:\t%s (rule '%s': %s)\n", t.Time.Local().Format("Mon 2006-01-02 15:04"), t.Rule, event)
+	} else {
+		fmt.Fprintf(w, "Next transition\t:\tnone (no enabled rules)\n")
+	}
+	return w
+}
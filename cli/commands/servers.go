@@ -23,6 +23,7 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -139,6 +140,8 @@ func (c *CmdServers) Run() error {
 
 	svrs := serversFilter(isWgDisabled, isOpenVPNDisabled,
 		slist, c.filter, c.proto, c.location, c.city, c.countryCode, c.country, c.filterInvert)
+	setJSONResult(svrs)
+
 	for _, s := range svrs {
 		str := ""
 		IPvInfo := "IPv4"
@@ -398,3 +401,28 @@ type serverDesc struct {
 func (s *serverDesc) String() string {
 	return fmt.Sprintf("%s, %s (%s), %s", s.gateway, s.city, s.countryCode, s.country)
 }
+
+// MarshalJSON - server info in JSON output mode
+func (s serverDesc) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Protocol     string
+		Gateway      string
+		City         string
+		CountryCode  string
+		Country      string
+		ISP          string
+		IsIPv6Tunnel bool
+		PingMs       int `json:",omitempty"`
+		Hosts        []hostDesc
+	}{s.protocol, s.gateway, s.city, s.countryCode, s.country, s.isp, s.isIPv6Tunnel, s.pingMs, s.hosts})
+}
+
+// MarshalJSON - host info in JSON output mode
+func (h hostDesc) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Hostname string
+		Host     string
+		PingMs   int     `json:",omitempty"`
+		Load     float32 `json:",omitempty"`
+	}{h.hostname, h.host, h.pingMs, h.load})
+}
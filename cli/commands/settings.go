@@ -0,0 +1,142 @@
+//  privateLINE Connect command line interface (CLI)
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the privateLINE Connect command line interface.
+//
+//  The privateLINE Connect command line interface is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The privateLINE Connect command line interface is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the privateLINE Connect command line interface. If not, see <https://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/swapnilsparsh/devsVPN/cli/flags"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+)
+
+type CmdSettings struct {
+	flags.CmdInfo
+	action string
+	file   string
+	dryRun bool
+}
+
+func (c *CmdSettings) Init() {
+	c.SetPreParseFunc(c.preParse)
+	c.Initialize("settings", "Export or import all user settings as a portable bundle (JSON)\nThe bundle contains: Total Shield applications, trusted WiFi networks, firewall exceptions, DNS and connection settings.\nSecrets (session tokens, WireGuard keys, proxy credentials) are never exported.\nExamples:\n  settings export -file settings.json\n  settings import -file settings.json -dry-run")
+	c.DefaultStringVar(&c.action, "export|import")
+	c.StringVar(&c.file, "file", "", "FILE", "Path to the settings file (default: standard output for 'export', standard input for 'import')")
+	c.BoolVar(&c.dryRun, "dry-run", false, "(import) Validate the settings and show the changes without applying them")
+}
+
+// preParse allows to define the action before the flags (e.g. 'settings export -file FILE')
+func (c *CmdSettings) preParse(arguments []string) ([]string, error) {
+	if len(arguments) > 0 && !strings.HasPrefix(arguments[0], "-") {
+		return append(arguments[1:], arguments[0]), nil
+	}
+	return arguments, nil
+}
+
+func (c *CmdSettings) Run() error {
+	switch strings.ToLower(c.action) {
+	case "export":
+		if c.dryRun {
+			return flags.BadParameter{Message: "'-dry-run' is applicable only for 'import'"}
+		}
+		return c.doExport()
+	case "import":
+		return c.doImport()
+	default:
+		return flags.BadParameter{Message: "expected action: 'export' or 'import'"}
+	}
+}
+
+func (c *CmdSettings) doExport() error {
+	bundle, err := _proto.SettingsExport()
+	if err != nil {
+		return err
+	}
+
+	if len(c.file) == 0 && IsJSONOutput() {
+		// the bundle is the result of the command
+		setJSONResult(bundle)
+		return nil
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if len(c.file) == 0 {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(c.file, data, 0600); err != nil {
+		return err
+	}
+	fmt.Printf("Settings exported to '%s'\n", c.file)
+	return nil
+}
+
+func (c *CmdSettings) doImport() error {
+	var (
+		data []byte
+		err  error
+	)
+	if len(c.file) == 0 || c.file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(c.file)
+	}
+	if err != nil {
+		return err
+	}
+
+	var bundle preferences.SettingsBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return fmt.Errorf("failed to parse settings: %w", err)
+	}
+
+	changes, err := _proto.SettingsImport(bundle, c.dryRun)
+	if err != nil {
+		return err
+	}
+	setJSONResult(struct {
+		IsDryRun bool
+		Changes  []string
+	}{c.dryRun, changes})
+
+	if len(changes) == 0 {
+		fmt.Println("No changes")
+		return nil
+	}
+
+	if c.dryRun {
+		fmt.Println("Changes to be applied:")
+	} else {
+		fmt.Println("Settings imported. Applied changes:")
+	}
+	for _, ch := range changes {
+		fmt.Println("  " + ch)
+	}
+	return nil
+}
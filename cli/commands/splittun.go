@@ -29,11 +29,14 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/swapnilsparsh/devsVPN/cli/cliplatform"
 	"github.com/swapnilsparsh/devsVPN/cli/flags"
 	"github.com/swapnilsparsh/devsVPN/cli/helpers"
 	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/splittun"
 )
 
 type Exclude struct {
@@ -173,6 +176,16 @@ type SplitTun struct {
 	appremove  string
 	appadd     string // this parameter is not in use. We need it just for help info (using 'appaddArgs' parsed with specific logic)
 	appaddArgs []string
+
+	domains      bool
+	domainAdd    string
+	domainBypass string
+	domainRemove string
+
+	subnets       bool
+	subnetInclude string
+	subnetExclude string
+	subnetRemove  string
 }
 
 const (
@@ -225,6 +238,16 @@ func (c *SplitTun) Init() {
 	// 	Note! The privateLINE AntiTracker and custom DNS are not functional when this feature is disabled.
 	// 	Note! This functionality only applies in Inverse Total Shield mode when the VPN is connected.`)
 
+	c.BoolVar(&c.domains, "domains", false, "Show per-domain rules and the resolved addresses")
+	c.StringVar(&c.domainAdd, "domain_add", "", "DOMAIN", "Always route the traffic to the domain through the VPN tunnel\n(wildcard '*.example.com' matches any subdomain: its addresses are taken from the DNS responses)\nExample:\n    "+cliplatform.CliExeName+" totshld -domain_add *.corp.example.com")
+	c.StringVar(&c.domainBypass, "domain_bypass", "", "DOMAIN", "Send the traffic to the domain directly (bypass the VPN tunnel)\nExample:\n    "+cliplatform.CliExeName+" totshld -domain_bypass streaming.example.net")
+	c.StringVar(&c.domainRemove, "domain_remove", "", "DOMAIN", "Remove per-domain rule")
+
+	c.BoolVar(&c.subnets, "subnets", false, "Show per-CIDR rules")
+	c.StringVar(&c.subnetInclude, "subnet_include", "", "CIDR", "Route the traffic to the IPv4 network through the VPN tunnel\nExample:\n    "+cliplatform.CliExeName+" totshld -subnet_include 192.168.100.0/24")
+	c.StringVar(&c.subnetExclude, "subnet_exclude", "", "CIDR", "Send the traffic to the IPv4 network directly (bypass the VPN tunnel)\nExample:\n    "+cliplatform.CliExeName+" totshld -subnet_exclude 203.0.113.0/24")
+	c.StringVar(&c.subnetRemove, "subnet_remove", "", "CIDR", "Remove per-CIDR rule")
+
 	c.BoolVar(&c.on, "off", false, "Disable Total Shield mode")
 
 	c.BoolVar(&c.off, "on", false, "Enable Total Shield mode: allow only traffic to privateLINE services, block traffic to the internet")
@@ -242,6 +265,18 @@ func (c *SplitTun) Run() error {
 		return flags.ConflictingParameters{}
 	}
 
+	if domainOps := countNonEmpty(c.domainAdd, c.domainBypass, c.domainRemove); domainOps > 1 || (domainOps > 0 && c.domains) {
+		return flags.ConflictingParameters{}
+	} else if domainOps > 0 || c.domains {
+		return c.doDomains()
+	}
+
+	if subnetOps := countNonEmpty(c.subnetInclude, c.subnetExclude, c.subnetRemove); subnetOps > 1 || (subnetOps > 0 && c.subnets) {
+		return flags.ConflictingParameters{}
+	} else if subnetOps > 0 || c.subnets {
+		return c.doSubnets()
+	}
+
 	cfg, err := _proto.GetSplitTunnelStatus()
 	if err != nil {
 		return err
@@ -330,17 +365,171 @@ func (c *SplitTun) Run() error {
 }
 
 func (c *SplitTun) doShowStatus(cfg types.SplitTunnelStatus, isFull bool) error {
+	setJSONResult(cfg)
 	w := printSplitTunState(nil, false, isFull, cfg.IsEnabled, cfg.EnableAppWhitelist, cfg.IsInversed, cfg.IsAnyDns, cfg.IsAllowWhenNoVpn, cfg.SplitTunnelApps, cfg.RunningApps)
 	w.Flush()
+	if len(cfg.Domains) > 0 {
+		fmt.Println()
+		printSplitTunDomains(nil, cfg.Domains).Flush()
+	}
+	if len(cfg.IncludeSubnets) > 0 || len(cfg.ExcludeSubnets) > 0 {
+		fmt.Println()
+		printSplitTunSubnets(nil, cfg.IncludeSubnets, cfg.ExcludeSubnets).Flush()
+	}
 	return nil
 }
 
 func (c *SplitTun) doShowStatusShort(cfg types.SplitTunnelStatus) error {
+	setJSONResult(cfg)
 	w := printSplitTunState(nil, true, false, cfg.IsEnabled, cfg.EnableAppWhitelist, cfg.IsInversed, cfg.IsAnyDns, cfg.IsAllowWhenNoVpn, cfg.SplitTunnelApps, cfg.RunningApps)
 	w.Flush()
 	return nil
 }
 
+func (c *SplitTun) doDomains() (err error) {
+	var resp types.SplitTunnelDomainsResp
+	switch {
+	case len(c.domainAdd) > 0:
+		resp, err = _proto.SplitTunnelDomainAdd(c.domainAdd, false)
+	case len(c.domainBypass) > 0:
+		resp, err = _proto.SplitTunnelDomainAdd(c.domainBypass, true)
+	case len(c.domainRemove) > 0:
+		resp, err = _proto.SplitTunnelDomainRemove(c.domainRemove)
+	default:
+		resp, err = _proto.SplitTunnelDomainsGet()
+	}
+	if err != nil {
+		return err
+	}
+
+	setJSONResult(resp.Domains)
+	if len(resp.FuncNotAvailableMessage) > 0 {
+		fmt.Printf("Warning: %s\n", resp.FuncNotAvailableMessage)
+	}
+	w := printSplitTunDomains(nil, resp.Domains)
+	w.Flush()
+	return nil
+}
+
+// printSplitTunDomains prints per-domain split tunnelling rules with the resolved addresses
+func printSplitTunDomains(w *tabwriter.Writer, domains []splittun.DomainStatus) *tabwriter.Writer {
+	if w == nil {
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	}
+
+	if len(domains) == 0 {
+		fmt.Fprintln(w, "No per-domain rules defined")
+		return w
+	}
+
+	fmt.Fprintln(w, "DOMAIN\tROUTE\tRESOLVED ADDRESSES\tEXPIRES")
+	for _, d := range domains {
+		route := "VPN tunnel"
+		if d.Bypass {
+			route = "direct"
+		}
+
+		addrs := "-"
+		if len(d.IPs) > 0 {
+			ips := make([]string, 0, len(d.IPs))
+			for _, ip := range d.IPs {
+				ips = append(ips, ip.String())
+			}
+			addrs = strings.Join(ips, ", ")
+		}
+
+		expires := "-"
+		if len(d.Error) > 0 {
+			expires = "error: " + d.Error
+		} else if !d.Expires.IsZero() {
+			expires = "in " + time.Until(d.Expires).Truncate(time.Second).String()
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.Domain, route, addrs, expires)
+	}
+	return w
+}
+
+func (c *SplitTun) doSubnets() error {
+	resp, err := _proto.SplitTunnelSubnetsGet()
+	if err != nil {
+		return err
+	}
+
+	if arg := c.subnetInclude + c.subnetExclude + c.subnetRemove; len(arg) > 0 {
+		subnet, err := splittun.NormalizeSubnet(arg)
+		if err != nil {
+			return flags.BadParameter{Message: err.Error()}
+		}
+		cidr := subnet.String()
+
+		include, exclude := removeString(resp.Include, cidr), removeString(resp.Exclude, cidr)
+		switch {
+		case len(c.subnetInclude) > 0:
+			include = append(include, cidr)
+		case len(c.subnetExclude) > 0:
+			exclude = append(exclude, cidr)
+		default:
+			if len(include) == len(resp.Include) && len(exclude) == len(resp.Exclude) {
+				return fmt.Errorf("per-CIDR rule '%s' not found", cidr)
+			}
+		}
+
+		if resp, err = _proto.SplitTunnelSubnetsSet(include, exclude); err != nil {
+			return err
+		}
+	}
+
+	setJSONResult(resp)
+	if len(resp.FuncNotAvailableMessage) > 0 {
+		fmt.Printf("Warning: %s\n", resp.FuncNotAvailableMessage)
+	}
+	w := printSplitTunSubnets(nil, resp.Include, resp.Exclude)
+	w.Flush()
+	return nil
+}
+
+// printSplitTunSubnets prints per-CIDR split tunnelling rules
+func printSplitTunSubnets(w *tabwriter.Writer, include, exclude []string) *tabwriter.Writer {
+	if w == nil {
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	}
+
+	if len(include) == 0 && len(exclude) == 0 {
+		fmt.Fprintln(w, "No per-CIDR rules defined")
+		return w
+	}
+
+	fmt.Fprintln(w, "NETWORK\tROUTE")
+	for _, n := range include {
+		fmt.Fprintf(w, "%s\t%s\n", n, "VPN tunnel")
+	}
+	for _, n := range exclude {
+		fmt.Fprintf(w, "%s\t%s\n", n, "direct")
+	}
+	return w
+}
+
+// removeString returns a copy of the list without the value
+func removeString(list []string, value string) []string {
+	ret := make([]string, 0, len(list))
+	for _, v := range list {
+		if v != value {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+func countNonEmpty(values ...string) (cnt int) {
+	for _, v := range values {
+		if len(v) > 0 {
+			cnt++
+		}
+	}
+	return cnt
+}
+
 func (c *SplitTun) specialParse(arguments []string) bool {
 	if len(arguments) > 1 && strings.ToLower(arguments[0]) == "-appadd" {
 		c.appaddArgs = arguments[1:]
@@ -28,7 +28,9 @@ import (
 
 	"github.com/swapnilsparsh/devsVPN/cli/flags"
 	apitypes "github.com/swapnilsparsh/devsVPN/daemon/api/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/srverrors"
+	service_types "github.com/swapnilsparsh/devsVPN/daemon/service/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
 )
 
@@ -43,6 +45,18 @@ func (c *CmdState) Run() error {
 	return showState()
 }
 
+// stateJSON - the result of 'status' command in JSON output mode
+// (also it is the result of commands which print the state after finished: 'connect', 'disconnect', 'connection'...)
+type stateJSON struct {
+	VpnState    string
+	IsPaused    bool
+	IsLoggedIn  bool
+	AccountID   string               `json:",omitempty"`
+	Connected   *types.ConnectedResp `json:",omitempty"` // only when VPN is connected
+	Firewall    service_types.KillSwitchStatus
+	SplitTunnel *types.SplitTunnelStatus `json:",omitempty"` // only when the functionality is available
+}
+
 func showState() error {
 	fwstate, err := _proto.FirewallStatus()
 	if err != nil {
@@ -73,6 +87,24 @@ func showState() error {
 		}
 	}
 
+	if IsJSONOutput() {
+		helloResp := _proto.GetHelloResponse()
+		result := stateJSON{
+			VpnState:   state.String(),
+			IsLoggedIn: len(helloResp.Session.Session) > 0,
+			AccountID:  helloResp.Session.AccountID,
+			Firewall:   fwstate.KillSwitchStatus,
+		}
+		if state == vpn.CONNECTED {
+			result.IsPaused = connected.IsPaused
+			result.Connected = &connected
+		}
+		if !stStatus.IsFunctionalityNotAvailable {
+			result.SplitTunnel = &stStatus
+		}
+		setJSONResult(result)
+	}
+
 	w := printAccountInfo(nil, _proto.GetHelloResponse().Session.AccountID)
 	printState(w, state, connected, serverInfo, exitServerInfo, _proto.GetHelloResponse())
 	printRestApiState(w, _proto.GetHelloResponse().DevRestApiBackend)
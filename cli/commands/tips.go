@@ -52,7 +52,7 @@ const (
 )
 
 func PrintTips(tips []TipType) {
-	if len(tips) == 0 {
+	if len(tips) == 0 || IsJSONOutput() {
 		return
 	}
 
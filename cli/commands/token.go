@@ -0,0 +1,96 @@
+//  privateLINE Connect command line interface (CLI)
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the privateLINE Connect command line interface.
+//
+//  The privateLINE Connect command line interface is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The privateLINE Connect command line interface is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the privateLINE Connect command line interface. If not, see <https://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/cli/flags"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/roles"
+)
+
+type CmdAccessToken struct {
+	flags.CmdInfo
+	list   bool
+	create string
+	role   string
+	delete string
+}
+
+func (c *CmdAccessToken) Init() {
+	c.Initialize("token", "Manage access tokens (role-based permissions)\nAn access token allows a client to connect to the daemon with limited rights.\nThe token is in use by CLI when defined in environment variable PRIVATELINE_ACCESS_TOKEN.\nRoles:\n  observer - read-only access (status, servers, settings info)\n  operator - observer rights + connection control (connect/disconnect, pause, firewall on/off)\n  admin    - full access")
+	c.BoolVar(&c.list, "list", false, "(default) Show access tokens")
+	c.StringVar(&c.create, "create", "", "NAME", "Create new access token (the token value is shown only once)")
+	c.StringVar(&c.role, "role", string(roles.RoleObserver), "ROLE", "Role of the new access token: observer, operator or admin")
+	c.StringVar(&c.delete, "delete", "", "NAME", "Remove access token")
+}
+
+func (c *CmdAccessToken) Run() error {
+	if len(c.create) > 0 && len(c.delete) > 0 {
+		return flags.BadParameter{}
+	}
+
+	if len(c.create) > 0 {
+		role, err := roles.Parse(c.role)
+		if err != nil {
+			return flags.BadParameter{Message: err.Error()}
+		}
+
+		resp, err := _proto.AccessTokenCreate(c.create, role)
+		if err != nil {
+			return err
+		}
+		setJSONResult(resp)
+
+		fmt.Printf("Access token '%s' created (role: %s)\n", resp.Info.Name, resp.Info.Role)
+		fmt.Printf("Token: %s\n", resp.Token)
+		fmt.Println("Please, save the token: it is not possible to retrieve it later.")
+		return nil
+	}
+
+	if len(c.delete) > 0 {
+		if err := _proto.AccessTokenDelete(c.delete); err != nil {
+			return err
+		}
+		fmt.Printf("Access token '%s' removed\n", c.delete)
+		return nil
+	}
+
+	tokens, err := _proto.AccessTokenList()
+	if err != nil {
+		return err
+	}
+	setJSONResult(tokens)
+	if len(tokens) == 0 {
+		fmt.Println("No access tokens defined")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tROLE\tCREATED")
+	for _, t := range tokens {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", t.Name, t.Role, time.Unix(t.Created, 0).Format(time.RFC3339))
+	}
+	w.Flush()
+	return nil
+}
@@ -25,12 +25,14 @@ package commands
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"runtime"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/swapnilsparsh/devsVPN/cli/flags"
 	"github.com/swapnilsparsh/devsVPN/cli/helpers"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
 )
 
@@ -60,6 +62,8 @@ type CmdWiFi struct {
 	default_trust_status string //[none/trusted/untrusted]
 	set_trusted_action   string // [action:value] // actions: 'trusted_vpn_off:[true/false]', 'trusted_firewall_off', 'untrusted_vpn_on', 'untrusted_firewall_on', untrusted_block_lan
 	set_trusted_network  string // [network:status] (status: none/trusted/untrusted; e.g. 'my_home_wifi':trusted)
+	set_network_profile  string // [network:profile] (e.g. 'my_home_wifi':home)
+	set_network_action   string // [network:action=value] (e.g. 'my_home_wifi':total_shield=on)
 	reset_settings       bool
 }
 
@@ -106,6 +110,36 @@ func (c *CmdWiFi) Init() {
 					ivpn wifi -set_trusted_network 'my home network':trusted
 					Define current WiFi network as 'untrusted':
 						ivpn wifi -set_trusted_network untrusted`)
+	c.StringVar(&c.set_network_profile, "set_network_profile", "", "CONFIG",
+		`Set connection profile for WiFi network
+			The profile is used when the VPN gets connected on joining the untrusted network.
+			The network must be already defined (see '-set_trusted_network').
+			CONFIG parameter format: '<NETWORK_NAME>':<PROFILE>
+				PROFILE: name of the connection profile (see 'profile' command)
+					(Set an empty value to use the default auto-connection parameters)
+			Example:
+					ivpn wifi -set_network_profile 'my cafe':work
+					ivpn wifi -set_network_profile 'my cafe':`)
+	c.StringVar(&c.set_network_action, "set_network_action", "", "CONFIG",
+		`Set action for WiFi network (overrides the common action for the network)
+			The network must be already defined (see '-set_trusted_network').
+			CONFIG parameter format: '<NETWORK_NAME>':<ACTION>=<VALUE>
+				ACTION:
+					* connect_vpn     - [on/off] Connect (on) or disconnect (off) VPN
+					* enable_firewall - [on] Enable firewall (disabling is not supported)
+					* block_lan       - [on/off] Block LAN traffic
+					* total_shield    - [on/off] Enable Total Shield
+					* location        - Gateway ID or country code of the server to connect
+					                    (WireGuard: selects hosts of the server this device is registered with)
+					* protocol        - [wireguard/wireguard_v2ray_quic/wireguard_v2ray_tcp/openvpn]
+					* dns             - Comma-separated list of custom DNS servers
+				Set the value to "default" to use the common action.
+				Use action "all" with value "default" to remove all actions of the network.
+			Example:
+					ivpn wifi -set_network_action 'my cafe':total_shield=on
+					ivpn wifi -set_network_action 'my cafe':location=us-tx
+					ivpn wifi -set_network_action 'my cafe':dns=1.1.1.1,9.9.9.9
+					ivpn wifi -set_network_action 'my cafe':all=default`)
 
 	c.BoolVar(&c.reset_settings, "reset_settings", false, "Reset WiFi settings to defaults")
 }
@@ -265,6 +299,38 @@ func (c *CmdWiFi) Run() error {
 		isSettingsChanged = true
 	}
 
+	if len(c.set_network_profile) > 0 {
+		dividerIdx := strings.LastIndex(c.set_network_profile, ":")
+		if dividerIdx < 0 {
+			return flags.BadParameter{Message: "set_network_profile"}
+		}
+		netName := helpers.TrimSpacesAndRemoveQuotes(c.set_network_profile[:dividerIdx])
+		profile := helpers.TrimSpacesAndRemoveQuotes(c.set_network_profile[dividerIdx+1:])
+		if len(netName) == 0 {
+			return flags.BadParameter{Message: "WiFi network name not defined"}
+		}
+
+		found := false
+		for i, n := range wifiSettings.Networks {
+			if n.SSID == netName {
+				wifiSettings.Networks[i].Profile = profile
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("WiFi network '%s' not defined (use '-set_trusted_network' to define it)", netName)
+		}
+		isSettingsChanged = true
+	}
+
+	if len(c.set_network_action) > 0 {
+		if err := setWiFiNetworkAction(&wifiSettings, c.set_network_action); err != nil {
+			return err
+		}
+		isSettingsChanged = true
+	}
+
 	// reset all settings
 	if c.reset_settings {
 		fmt.Println("Resetting settings...")
@@ -319,7 +385,11 @@ func (c *CmdWiFi) printStatus(w *tabwriter.Writer) *tabwriter.Writer {
 		return boolToStrEx(&v, enabledStrVal, disabledStrVal, "", "")
 	}
 
+	var curNetJSON *types.WiFiCurrentNetworkResp
 	curNet, err := _proto.GetWiFiCurrentNetwork()
+	if err == nil {
+		curNetJSON = &curNet
+	}
 	if err != nil {
 		fmt.Println(err)
 	} else if len(curNet.Error) > 0 {
@@ -334,6 +404,10 @@ func (c *CmdWiFi) printStatus(w *tabwriter.Writer) *tabwriter.Writer {
 	}
 
 	wifiSettings := _proto.GetHelloResponse().DaemonSettings.WiFi
+	setJSONResult(struct {
+		CurrentNetwork *types.WiFiCurrentNetworkResp `json:",omitempty"`
+		Settings       preferences.WiFiParams
+	}{curNetJSON, wifiSettings})
 
 	canApplyInBackgroundWarning := ""
 	if !wifiSettings.CanApplyInBackground {
@@ -359,8 +433,142 @@ func (c *CmdWiFi) printStatus(w *tabwriter.Writer) *tabwriter.Writer {
 	} else {
 		fmt.Fprintf(w, "Networks:\t\n")
 		for _, n := range wifiSettings.Networks {
-			fmt.Fprintf(w, "        %s\t:\t%v\n", n.SSID, boolToStrEx(&n.IsTrusted, "Trusted", "Untrusted", "No status", ""))
+			trustStr := boolToStrEx(&n.IsTrusted, "Trusted", "Untrusted", "No status", "")
+			if len(n.Profile) > 0 {
+				trustStr += fmt.Sprintf(" (profile: %s)", n.Profile)
+			}
+			fmt.Fprintf(w, "        %s\t:\t%v\n", n.SSID, trustStr)
+			if n.Actions != nil {
+				for _, a := range wifiNetworkActionsStrings(*n.Actions) {
+					fmt.Fprintf(w, "            \t \t%s\n", a)
+				}
+			}
 		}
 	}
 	return w
 }
+
+var wifiNetworkActionRegexp = regexp.MustCompile(`^(.*):([a-z_]+)=(.*)$`)
+
+// setWiFiNetworkAction applies action configuration ('<NETWORK_NAME>':<ACTION>=<VALUE>) to the WiFi network
+func setWiFiNetworkAction(wifiSettings *preferences.WiFiParams, config string) error {
+	m := wifiNetworkActionRegexp.FindStringSubmatch(strings.TrimSpace(config))
+	if m == nil {
+		return flags.BadParameter{Message: "set_network_action (expected format: '<NETWORK_NAME>':<ACTION>=<VALUE>)"}
+	}
+	netName := helpers.TrimSpacesAndRemoveQuotes(m[1])
+	action := m[2]
+	valueStr := helpers.TrimSpacesAndRemoveQuotes(m[3])
+	isDefault := strings.ToLower(valueStr) == "default"
+
+	var network *preferences.WiFiNetwork
+	for i := range wifiSettings.Networks {
+		if wifiSettings.Networks[i].SSID == netName {
+			network = &wifiSettings.Networks[i]
+			break
+		}
+	}
+	if network == nil {
+		return fmt.Errorf("WiFi network '%s' not defined (use '-set_trusted_network' to define it)", netName)
+	}
+
+	actions := preferences.WiFiNetworkActions{}
+	if network.Actions != nil {
+		actions = *network.Actions
+	}
+
+	boolVal := func() (*bool, error) {
+		if isDefault {
+			return nil, nil
+		}
+		v, err := helpers.BoolParameterParse(valueStr)
+		if err != nil {
+			return nil, err
+		}
+		return &v, nil
+	}
+
+	var err error
+	switch action {
+	case "all":
+		if !isDefault {
+			return flags.BadParameter{Message: "action 'all' accepts only value 'default'"}
+		}
+		actions = preferences.WiFiNetworkActions{}
+	case "connect_vpn":
+		actions.ConnectVpn, err = boolVal()
+	case "enable_firewall":
+		actions.EnableFirewall, err = boolVal()
+	case "block_lan":
+		actions.BlockLan, err = boolVal()
+	case "total_shield":
+		actions.TotalShield, err = boolVal()
+	case "location":
+		actions.Location = ""
+		if !isDefault {
+			actions.Location = valueStr
+		}
+	case "protocol":
+		actions.Protocol = ""
+		if !isDefault {
+			actions.Protocol = strings.ToLower(valueStr)
+		}
+	case "dns":
+		actions.DnsServers = nil
+		if !isDefault {
+			for _, d := range strings.Split(valueStr, ",") {
+				if d = strings.TrimSpace(d); len(d) > 0 {
+					actions.DnsServers = append(actions.DnsServers, d)
+				}
+			}
+		}
+	default:
+		return flags.BadParameter{Message: fmt.Sprintf("not supported network action '%s'", action)}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := actions.Validate(); err != nil {
+		return flags.BadParameter{Message: err.Error()}
+	}
+
+	if actions.IsEmpty() {
+		network.Actions = nil
+	} else {
+		network.Actions = &actions
+	}
+	return nil
+}
+
+// wifiNetworkActionsStrings returns human-readable description of the WiFi network actions
+func wifiNetworkActionsStrings(a preferences.WiFiNetworkActions) (ret []string) {
+	onOff := func(v bool) string {
+		if v {
+			return "on"
+		}
+		return "off"
+	}
+	if a.ConnectVpn != nil {
+		ret = append(ret, "Connect VPN: "+onOff(*a.ConnectVpn))
+	}
+	if a.EnableFirewall != nil {
+		ret = append(ret, "Enable firewall: "+onOff(*a.EnableFirewall))
+	}
+	if a.BlockLan != nil {
+		ret = append(ret, "Block LAN traffic: "+onOff(*a.BlockLan))
+	}
+	if a.TotalShield != nil {
+		ret = append(ret, "Total Shield: "+onOff(*a.TotalShield))
+	}
+	if len(a.Location) > 0 {
+		ret = append(ret, "Location: "+a.Location)
+	}
+	if len(a.Protocol) > 0 {
+		ret = append(ret, "Protocol: "+a.Protocol)
+	}
+	if len(a.DnsServers) > 0 {
+		ret = append(ret, "DNS: "+strings.Join(a.DnsServers, ", "))
+	}
+	return ret
+}
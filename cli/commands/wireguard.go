@@ -65,7 +65,7 @@ func (c *CmdWireGuard) Run() error {
 		return err
 	}
 	if len(resp.DisabledFunctions.WireGuardError) > 0 {
-		return fmt.Errorf("WireGuard functionality disabled:\n\t" + resp.DisabledFunctions.WireGuardError)
+		return fmt.Errorf("WireGuard functionality disabled:\n\t%s", resp.DisabledFunctions.WireGuardError)
 	}
 
 	if c.regenerate {
@@ -113,6 +113,14 @@ func (c *CmdWireGuard) getState() error {
 		quantumResistanceStatus = "Enabled"
 	}
 
+	setJSONResult(struct {
+		LocalIP                 string
+		PublicKey               string
+		QuantumResistance       bool
+		GeneratedUnix           int64
+		RotationIntervalSeconds int64
+	}{resp.Session.WgLocalIP, resp.Session.WgPublicKey, resp.Session.WgUsePresharedKey, resp.Session.WgKeyGenerated, resp.Session.WgKeysRegenInerval})
+
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
 	fmt.Fprintf(w, "Local IP:\t%v\n", resp.Session.WgLocalIP)
 	fmt.Fprintf(w, "Public KEY:\t%v\n", resp.Session.WgPublicKey)
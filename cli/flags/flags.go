@@ -97,6 +97,11 @@ func (c *CmdInfo) SetParseSpecialFunc(f func(arguments []string) bool) {
 	c.parseSpecial = f
 }
 
+// HasParseSpecial returns true when the command processes arguments by itself (special parse function registered)
+func (c *CmdInfo) HasParseSpecial() bool {
+	return c.parseSpecial != nil
+}
+
 func (c *CmdInfo) PreParse(arguments []string) (argumentsUpdated []string, err error) {
 	if c.preParse != nil {
 		return c.preParse(arguments)
@@ -47,6 +47,7 @@ type ICommand interface {
 	Init()
 	Parse(arguments []string) error
 	ParseSpecial(arguments []string) (parsedSpecial bool)
+	HasParseSpecial() bool
 	PreParse(arguments []string) (argumentsUpdated []string, err error)
 	Run() error
 
@@ -72,7 +73,9 @@ func printHeader() {
 
 func printUsageAll(short bool) {
 	printHeader()
-	fmt.Printf("Usage: %s COMMAND [OPTIONS...] [COMMAND_PARAMETER] [-h|-help]\n\n", filepath.Base(os.Args[0]))
+	fmt.Printf("Usage: %s [-json] COMMAND [OPTIONS...] [COMMAND_PARAMETER] [-h|-help]\n\n", filepath.Base(os.Args[0]))
+	fmt.Println("  -json  Machine-readable output: print the result (or the error) of the command as JSON document")
+	fmt.Println()
 
 	fmt.Println("COMMANDS:")
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
@@ -99,7 +102,7 @@ func main() {
 	addCommand(&commands.CmdDisconnect{})
 	addCommand(&commands.CmdConnectionControl{})
 	addCommand(&commands.CmdServers{})
-	// addCommand(&commands.CmdFirewall{}) // TODO FIXME: Vlad - disabled firewall commands for now
+	addCommand(&commands.CmdFirewall{})
 	if cliplatform.IsSplitTunSupported() {
 		// Split tunnel functionality is currently only available on Windows
 		addCommand(&commands.SplitTun{})
@@ -115,8 +118,21 @@ func main() {
 	addCommand(&commands.CmdLogout{})
 	addCommand(&commands.CmdAccount{})
 	addCommand(&commands.CmdParanoidMode{})
+	addCommand(&commands.CmdAccessToken{})
+	addCommand(&commands.CmdHistory{})
+	addCommand(&commands.CmdManagedConfig{})
+	addCommand(&commands.CmdSettings{})
+	addCommand(&commands.CmdProfile{})
 	addCommand(&commands.CmdAutoConnect{})
 	addCommand(&commands.CmdWiFi{})
+	addCommand(&commands.CmdNetworkRule{})
+	addCommand(&commands.CmdSchedule{})
+	addCommand(&commands.CmdQuality{})
+	addCommand(&commands.CmdOpenVPN{})
+	addCommand(&commands.CmdCustom{})
+
+	// global '-json' option
+	os.Args = processJSONOutputArg(os.Args)
 
 	if len(os.Args) >= 2 {
 		arg1 := strings.TrimLeft(strings.ToLower(os.Args[1]), "-")
@@ -153,30 +169,19 @@ func main() {
 	}
 
 	// initialize command handler
-	port, secret, err := readDaemonPort()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: Unable to connect to service: %s\n", err)
-		printServStartInstructions()
-		os.Exit(1)
-	}
-
-	proto := protocol.CreateClient(port, secret)
-
-	proto.SetParanoidModeSecretRequestFunc(RequestParanoidModePassword)
-	proto.SetPrintFunc(PrintToConsoleFunc)
-
-	if err := proto.Connect(); err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: Failed to connect to service : %s\n", err)
-		printServStartInstructions()
-		os.Exit(1)
-	}
+	proto := connectToDaemon()
 
 	commands.Initialize(proto)
 
 	if len(os.Args) < 2 {
-		if err := stateCmd.Run(); err != nil {
+		err := stateCmd.Run()
+		if commands.IsJSONOutput() {
+			os.Exit(commands.PrintJSONOutput(stateCmd.Name(), err))
+		}
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "\n%v\n", err)
-			os.Exit(1)
+			_, exitCode := commands.ErrorInfo(err)
+			os.Exit(exitCode)
 		}
 		return
 	}
@@ -193,10 +198,108 @@ func main() {
 
 	// unknown command
 	if !isProcessed {
+		err := flags.BadParameter{Message: fmt.Sprintf("unexpected command '%s'", os.Args[1])}
+		if commands.IsJSONOutput() {
+			os.Exit(commands.PrintJSONOutput(os.Args[1], err))
+		}
 		fmt.Fprintf(os.Stderr, "Error. Unexpected command %s\n", os.Args[1])
 		printUsageAll(true)
-		os.Exit(1)
+		os.Exit(commands.ExitCodeBadParameter)
+	}
+}
+
+// processJSONOutputArg enables JSON output mode when the '-json' option is defined.
+// The option is accepted before the command name or among the command arguments.
+// For commands which process the arguments by themselves (e.g. 'exclude <command>'),
+// the option is accepted only before the first non-option argument.
+// Returns the arguments without '-json' option.
+func processJSONOutputArg(args []string) []string {
+	isJSONArg := func(a string) bool { return a == "-json" || a == "--json" }
+
+	ret := make([]string, 0, len(args))
+	var cmd ICommand
+	isCommandFound := false
+	isRawArgs := false
+	for i, a := range args {
+		if i > 0 && !isRawArgs && isJSONArg(a) {
+			commands.EnableJSONOutput()
+			continue
+		}
+		ret = append(ret, a)
+
+		if i == 0 {
+			continue
+		}
+		if !isCommandFound {
+			isCommandFound = true
+			for _, c := range _commands {
+				if c.Name() == a {
+					cmd = c
+					break
+				}
+			}
+		} else if cmd != nil && cmd.HasParseSpecial() && !strings.HasPrefix(a, "-") {
+			isRawArgs = true
+		}
+	}
+	return ret
+}
+
+// connectToDaemon connects to the daemon.
+// The Unix-domain socket is preferred (if exists); the TCP port (from connection-info file) is in use otherwise.
+// If the access token is defined in environment (PRIVATELINE_ACCESS_TOKEN) - it is in use for authentication
+// (the daemon limits the client by the token role).
+func connectToDaemon() *protocol.Client {
+	accessToken := strings.TrimSpace(os.Getenv("PRIVATELINE_ACCESS_TOKEN"))
+
+	if socketFile := platform.ServiceSocketFile(); len(socketFile) > 0 {
+		if _, err := os.Stat(socketFile); err == nil {
+			proto := protocol.CreateClientUnixSocket(socketFile)
+			proto.SetAccessToken(accessToken)
+			proto.SetParanoidModeSecretRequestFunc(RequestParanoidModePassword)
+			proto.SetPrintFunc(PrintToConsoleFunc)
+
+			if err := proto.Connect(); err == nil {
+				return proto
+			}
+			// the current user may be not allowed to use the socket; fall back to TCP connection
+			proto.Close()
+		}
+	}
+
+	port, secret, err := readDaemonPort()
+	if err != nil {
+		if commands.IsJSONOutput() {
+			os.Exit(commands.PrintJSONOutput(commandName(), commands.DaemonNotAvailable{Err: err}))
+		}
+		fmt.Fprintf(os.Stderr, "ERROR: Unable to connect to service: %s\n", err)
+		printServStartInstructions()
+		os.Exit(commands.ExitCodeDaemonUnavailable)
+	}
+
+	proto := protocol.CreateClient(port, secret)
+
+	proto.SetAccessToken(accessToken)
+	proto.SetParanoidModeSecretRequestFunc(RequestParanoidModePassword)
+	proto.SetPrintFunc(PrintToConsoleFunc)
+
+	if err := proto.Connect(); err != nil {
+		if commands.IsJSONOutput() {
+			os.Exit(commands.PrintJSONOutput(commandName(), commands.DaemonNotAvailable{Err: err}))
+		}
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to connect to service : %s\n", err)
+		printServStartInstructions()
+		os.Exit(commands.ExitCodeDaemonUnavailable)
 	}
+	return proto
+}
+
+// commandName returns the name of the command to execute (from command line arguments)
+func commandName() string {
+	if len(os.Args) < 2 {
+		return "status"
+	}
+	return os.Args[1]
 }
 
 func RequestParanoidModePassword(c *protocol.Client) (string, error) {
@@ -223,18 +326,16 @@ func PrintToConsoleFunc(text string) {
 func runCommand(c ICommand, args []string) {
 
 	funcExitErrBadParam := func(err error) {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		isParamError := false
-		if _, ok := err.(flags.BadParameter); ok {
-			isParamError = true
-		} else if _, ok := err.(flags.ConflictingParameters); ok {
-			isParamError = true
+		if commands.IsJSONOutput() {
+			os.Exit(commands.PrintJSONOutput(c.Name(), err))
 		}
-		if isParamError {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		_, exitCode := commands.ErrorInfo(err)
+		if exitCode == commands.ExitCodeBadParameter {
 			//c.Usage(false)
 			fmt.Printf("\nFor detailed argument descriptions, use the command:\n    %s %s -h\t\n", filepath.Base(os.Args[0]), c.Name())
 		}
-		os.Exit(1)
+		os.Exit(exitCode)
 	}
 
 	parsedSpecial := c.ParseSpecial(args)
@@ -253,6 +354,10 @@ func runCommand(c ICommand, args []string) {
 	if err := c.Run(); err != nil {
 		funcExitErrBadParam(err)
 	}
+
+	if commands.IsJSONOutput() {
+		os.Exit(commands.PrintJSONOutput(c.Name(), nil))
+	}
 }
 
 // read port+secret to be able to connect to a daemon
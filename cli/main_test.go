@@ -0,0 +1,67 @@
+//
+//  privateLINE Connect CLI (command line interface)
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Created by Stelnykovych Alexandr.
+//  Copyright (c) 2023 IVPN Limited.
+//
+//  This file is part of the privateLINE Connect CLI (command line interface).
+//
+//  The IVPN command line interface is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The IVPN command line interface is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the IVPN command line interface. If not, see <https://www.gnu.org/licenses/>.
+//
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/swapnilsparsh/devsVPN/cli/commands"
+)
+
+func TestProcessJSONOutputArg(t *testing.T) {
+	_commands = nil
+	addCommand(&commands.CmdState{})
+	addCommand(&commands.CmdConnect{})
+	addCommand(&commands.Exclude{})
+	defer func() { _commands = nil }()
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"no arguments", []string{"cli"}, []string{"cli"}},
+		{"no json option", []string{"cli", "connect", "-any"}, []string{"cli", "connect", "-any"}},
+		{"before command", []string{"cli", "-json", "connect", "-any"}, []string{"cli", "connect", "-any"}},
+		{"after command", []string{"cli", "connect", "-any", "-json"}, []string{"cli", "connect", "-any"}},
+		{"double dash", []string{"cli", "status", "--json"}, []string{"cli", "status"}},
+		{"only option", []string{"cli", "-json"}, []string{"cli"}},
+		{"unknown command", []string{"cli", "unknown", "-json"}, []string{"cli", "unknown"}},
+		{"special parse command option", []string{"cli", "exclude", "-json", "ping", "1.1.1.1"}, []string{"cli", "exclude", "ping", "1.1.1.1"}},
+		{"special parse command argument", []string{"cli", "exclude", "ping", "-json"}, []string{"cli", "exclude", "ping", "-json"}},
+		{"special parse command before", []string{"cli", "-json", "exclude", "cmd", "-json", "x"}, []string{"cli", "exclude", "cmd", "-json", "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := processJSONOutputArg(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("processJSONOutputArg(%v) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+
+	if !commands.IsJSONOutput() {
+		t.Error("JSON output mode is not enabled")
+	}
+}
@@ -37,8 +37,11 @@ import (
 
 	apitypes "github.com/swapnilsparsh/devsVPN/daemon/api/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/logger"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/roles"
 	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/history"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/managedcfg"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
 	service_types "github.com/swapnilsparsh/devsVPN/daemon/service/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/version"
@@ -52,6 +55,12 @@ type Client struct {
 	_secret uint64
 	_conn   net.Conn
 
+	// path to the daemon Unix-domain socket (if defined - it is in use instead of TCP port)
+	_socketFile string
+
+	// access token (if defined - it is in use instead of secret; the client is limited by the token role)
+	_accessToken string
+
 	_requestIdx int
 
 	_defaultTimeout  time.Duration
@@ -83,6 +92,15 @@ func CreateClient(port int, secret uint64) *Client {
 		_receivers:      make(map[*receiverChannel]struct{})}
 }
 
+// CreateClientUnixSocket initialising new client for daemon which communicates over Unix-domain socket.
+// The secret is not required in this case: the daemon authorizes the client by its peer credentials.
+func CreateClientUnixSocket(socketFile string) *Client {
+	return &Client{
+		_socketFile:     socketFile,
+		_defaultTimeout: time.Second * 60 * 3,
+		_receivers:      make(map[*receiverChannel]struct{})}
+}
+
 // Connect is connecting to daemon
 func (c *Client) Connect() (err error) {
 	if c._conn != nil {
@@ -91,8 +109,13 @@ func (c *Client) Connect() (err error) {
 
 	logger.Info("Connecting...")
 
-	c._conn, err = net.Dial("tcp", fmt.Sprintf(":%d", c._port))
+	if len(c._socketFile) > 0 {
+		c._conn, err = net.Dial("unix", c._socketFile)
+	} else {
+		c._conn, err = net.Dial("tcp", fmt.Sprintf(":%d", c._port))
+	}
 	if err != nil {
+		c._conn = nil
 		return fmt.Errorf("failed to connect to IVPN daemon (does IVPN daemon/service running?): %w", err)
 	}
 
@@ -108,6 +131,13 @@ func (c *Client) Connect() (err error) {
 	return nil
 }
 
+// Close closes connection to the daemon
+func (c *Client) Close() {
+	if c._conn != nil {
+		c._conn.Close()
+	}
+}
+
 func paranoidModeSecretHash(secret string) string {
 	if len(secret) <= 0 {
 		return ""
@@ -127,6 +157,11 @@ func (c *Client) SetParanoidModeSecretRequestFunc(f func(*Client) (string, error
 	c._paranoidModeSecretRequestFunc = f
 }
 
+// SetAccessToken defines the access token to be used for authentication (instead of the secret)
+func (c *Client) SetAccessToken(token string) {
+	c._accessToken = token
+}
+
 func (c *Client) SetPrintFunc(f func(string)) {
 	c._printFunc = f
 }
@@ -147,6 +182,7 @@ func (c *Client) SendHelloEx(isSendResponseToAllClients bool) (helloResponse typ
 	}
 	helloReq := types.Hello{
 		Secret:                   c._secret,
+		AccessToken:              c._accessToken,
 		ClientType:               types.ClientCli,
 		GetStatus:                true,
 		Version:                  ver + ": CLI",
@@ -239,6 +275,18 @@ func (c *Client) SetPreferences(key, value string) error {
 	return nil
 }
 
+// GetDiagnosticLogs requests daemon logs (the request can contain the records filter and time window)
+func (c *Client) GetDiagnosticLogs(req types.GenerateDiagnostics) (resp types.DiagnosticsGeneratedResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
 // FirewallSet change firewall state
 func (c *Client) FirewallSet(isOn bool) error {
 	if err := c.ensureConnected(); err != nil {
@@ -515,6 +563,77 @@ func (c *Client) SplitTunnelRemoveApp(cmdOrPid string) error {
 	return nil
 }
 
+// SplitTunnelDomainsGet requests the per-domain split tunnelling rules
+func (c *Client) SplitTunnelDomainsGet() (resp types.SplitTunnelDomainsResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.SplitTunnelDomainsGet{}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// SplitTunnelDomainAdd adds (or updates) per-domain split tunnelling rule
+// (bypass: false - route the traffic to the domain through the VPN tunnel; true - send it directly)
+func (c *Client) SplitTunnelDomainAdd(domain string, bypass bool) (resp types.SplitTunnelDomainsResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.SplitTunnelDomainAdd{Domain: domain, Bypass: bypass}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// SplitTunnelDomainRemove removes per-domain split tunnelling rule
+func (c *Client) SplitTunnelDomainRemove(domain string) (resp types.SplitTunnelDomainsResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.SplitTunnelDomainRemove{Domain: domain}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// SplitTunnelSubnetsGet requests per-CIDR split tunnelling rules
+func (c *Client) SplitTunnelSubnetsGet() (resp types.SplitTunnelSubnetsResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.SplitTunnelSubnetsGet{}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// SplitTunnelSubnetsSet sets per-CIDR split tunnelling rules (both lists are replaced)
+func (c *Client) SplitTunnelSubnetsSet(include, exclude []string) (resp types.SplitTunnelSubnetsResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.SplitTunnelSubnetsSet{Include: include, Exclude: exclude}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
 // GetServers gets servers list
 func (c *Client) GetServers() (apitypes.ServersInfoResponse, error) {
 	if err := c.ensureConnected(); err != nil {
@@ -737,6 +856,109 @@ func (c *Client) SetParanoidModePassword(secret string) error {
 	return nil
 }
 
+// AccessTokenCreate creates new access token for the role
+func (c *Client) AccessTokenCreate(name string, role roles.Role) (resp types.AccessTokenCreatedResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.AccessTokenCreate{TokenName: name, Role: role}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// AccessTokenDelete removes access token
+func (c *Client) AccessTokenDelete(name string) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+
+	req := types.AccessTokenDelete{TokenName: name}
+	var resp types.EmptyResp
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AccessTokenList returns info about all access tokens
+func (c *Client) AccessTokenList() (tokens []roles.TokenInfo, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	req := types.AccessTokenList{}
+	var resp types.AccessTokenListResp
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tokens, nil
+}
+
+// SettingsExport returns the portable settings bundle
+func (c *Client) SettingsExport() (bundle preferences.SettingsBundle, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return bundle, err
+	}
+
+	req := types.SettingsExport{}
+	var resp types.SettingsExportResp
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return bundle, err
+	}
+	return resp.Bundle, nil
+}
+
+// SettingsImport applies the settings bundle (dryRun - only validate and report the changes)
+func (c *Client) SettingsImport(bundle preferences.SettingsBundle, dryRun bool) (changes []string, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	req := types.SettingsImport{Bundle: bundle, DryRun: dryRun}
+	var resp types.SettingsImportResp
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Changes, nil
+}
+
+// ManagedConfigApply (re)applies the headless daemon configuration file (dryRun - only validate and report the changes)
+func (c *Client) ManagedConfigApply(dryRun bool) (status managedcfg.Status, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return status, err
+	}
+
+	req := types.ManagedConfigApply{DryRun: dryRun}
+	var resp types.ManagedConfigResp
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return status, err
+	}
+	return resp.Status, nil
+}
+
+// HistoryGet returns connection events history
+func (c *Client) HistoryGet(from, to time.Time, eventTypes []string, maxCount int) (events []history.Event, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	req := types.HistoryGet{Types: eventTypes, MaxCount: maxCount}
+	if !from.IsZero() {
+		req.From = from.Unix()
+	}
+	if !to.IsZero() {
+		req.To = to.Unix()
+	}
+	var resp types.HistoryResp
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Events, nil
+}
+
 func (c *Client) SetUserPreferences(upref preferences.UserPreferences) error {
 	if err := c.ensureConnected(); err != nil {
 		return err
@@ -776,6 +998,62 @@ func (c *Client) SetWiFiSettings(params preferences.WiFiParams) error {
 	return nil
 }
 
+// ConnectionProfilesGet requests the list of named connection profiles
+func (c *Client) ConnectionProfilesGet() (resp types.ConnectionProfilesResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.ConnectionProfilesGet{}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// ConnectionProfileSaveLast stores the last connection parameters as named connection profile
+func (c *Client) ConnectionProfileSaveLast(name string, overwrite bool) (resp types.ConnectionProfilesResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.ConnectionProfileSave{ProfileName: name, FromLastConnection: true, Overwrite: overwrite}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// ConnectionProfileRename renames named connection profile
+func (c *Client) ConnectionProfileRename(name, newName string) (resp types.ConnectionProfilesResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.ConnectionProfileRename{ProfileName: name, NewProfileName: newName}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// ConnectionProfileDelete removes named connection profile
+func (c *Client) ConnectionProfileDelete(name string) (resp types.ConnectionProfilesResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.ConnectionProfileDelete{ProfileName: name}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
 func (c *Client) SetDefConnectionParams(params types.ConnectSettings) error {
 	if err := c.ensureConnected(); err != nil {
 		return err
@@ -797,3 +1075,157 @@ func (c *Client) GetDefConnectionParams() (types.ConnectSettings, error) {
 	_, _, err := c.sendRecvAny(&types.ConnectSettingsGet{}, &resp)
 	return resp, err
 }
+
+// NetworkRulesGet requests the network-condition rules and the state of the current network
+func (c *Client) NetworkRulesGet() (resp types.NetworkRulesResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.NetworkRulesGet{}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// NetworkRulesSet sets the network-condition rules (the list is replaced)
+func (c *Client) NetworkRulesSet(rules []preferences.NetworkRule) (resp types.NetworkRulesResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.NetworkRulesSet{Rules: rules}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// ScheduleRulesGet requests the time-of-day rules and the next scheduled transition
+func (c *Client) ScheduleRulesGet() (resp types.ScheduleRulesResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.ScheduleRulesGet{}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// ScheduleRulesSet sets the time-of-day rules (the list is replaced)
+func (c *Client) ScheduleRulesSet(rules []preferences.ScheduleRule) (resp types.ScheduleRulesResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.ScheduleRulesSet{Rules: rules}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// ConnectionQualityGet requests the thresholds and the latest results of the connection-quality monitor
+func (c *Client) ConnectionQualityGet() (resp types.ConnectionQualityResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.ConnectionQualityGet{}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// ConnectionQualitySet sets the thresholds of the connection-quality monitor
+func (c *Client) ConnectionQualitySet(params preferences.ConnectionQualityParams) (resp types.ConnectionQualityResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.ConnectionQualitySet{Params: params}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// OpenVpnDetailsGet requests the details of the current OpenVPN connection
+func (c *Client) OpenVpnDetailsGet() (resp types.OpenVpnDetailsResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.OpenVpnDetailsGet{}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// OpenVpnLogVerbositySet sets OpenVPN log verbosity (0 - default)
+func (c *Client) OpenVpnLogVerbositySet(verbosity int) (resp types.OpenVpnDetailsResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.OpenVpnLogVerbositySet{Verbosity: verbosity}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// CustomServersGet requests the list of user-imported servers
+func (c *Client) CustomServersGet() (resp types.CustomServersResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.CustomServersGet{}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// CustomServerImport imports WireGuard or OpenVPN configuration as a custom server
+func (c *Client) CustomServerImport(name, config, username, password string) (resp types.CustomServersResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.CustomServerImport{ServerName: name, Config: config, Username: username, Password: password}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// CustomServerDelete removes the user-imported server
+func (c *Client) CustomServerDelete(id string) (resp types.CustomServersResp, err error) {
+	if err := c.ensureConnected(); err != nil {
+		return resp, err
+	}
+
+	req := types.CustomServerDelete{ID: id}
+	if err := c.sendRecv(&req, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
@@ -77,6 +77,7 @@ type WireGuardServerHostInfo struct {
 	IPv6       WireGuardServerHostInfoIPv6 `json:"ipv6"`
 	DnsServers string                      `json:"dns_servers"`
 	AllowedIPs string                      `json:"allowed_ips"`
+	Obfs       ObfsParams                  `json:"obfs"` // pluggable transports bridge which forwards to WireGuard (optional)
 }
 
 // WireGuardServerInfo contains all info about WG server
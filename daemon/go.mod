@@ -18,6 +18,7 @@ require (
 	golang.org/x/sys v0.45.0
 	golang.zx2c4.com/wireguard/wgctrl v0.0.0-20230429144221-925a1e7659e6
 	golang.zx2c4.com/wireguard/windows v0.5.3
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require github.com/golang-collections/collections v0.0.0-20130729185459-604e922904d3 // indirect
@@ -36,6 +37,5 @@ require (
 	github.com/rivo/uniseg v0.4.4 // indirect
 	github.com/vishvananda/netns v0.0.4 // indirect
 	golang.org/x/crypto v0.52.0 // indirect
-	golang.zx2c4.com/wireguard v0.0.0-20231211153847-12269c276173 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	golang.zx2c4.com/wireguard v0.0.0-20231211153847-12269c276173
 )
@@ -25,9 +25,25 @@ package helpers
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
+	"math"
 	"strings"
 )
 
+var byteUnits = []string{"Bytes", "KB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"}
+
+// FormatBytes converts bytes count to a readable format (e.g. "1.50 MB")
+func FormatBytes(bytes int64) string {
+	if bytes <= 0 {
+		return "0 Bytes"
+	}
+
+	magnitude := int(math.Floor(math.Log(float64(bytes)) / math.Log(1024)))
+	value := float64(bytes) / math.Pow(1024, float64(magnitude))
+
+	return fmt.Sprintf("%.2f %s", value, byteUnits[magnitude])
+}
+
 func CapitalizeFirstLetter(s string) string {
 	if len(s) == 0 {
 		return s // Return the original string if it's empty
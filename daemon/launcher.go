@@ -95,16 +95,21 @@ func Launch() {
 		}
 	}
 
+	// The log rotation configuration must be applied before the first record is written
+	// (the log of the previous-previous session is archived when the log file is created)
+	prefs := preferences.Create()
+	isPrefsLoaded := prefs.LoadPreferences() == nil
+	if isPrefsLoaded {
+		logger.SetRotationPolicy(prefs.LogRotation)
+	}
+
 	if isLoggingEnabledArgument {
 		logger.Enable(true)
 		logger.Info("Logging enabled at build time")
 		// logger.Info("Logging enabled (forced by command line argument)")
-	} else {
+	} else if isPrefsLoaded {
 		// initialize logging according to service preferences
-		var prefs preferences.Preferences
-		if err := prefs.LoadPreferences(); err == nil {
-			logger.Enable(prefs.IsLogging)
-		}
+		logger.Enable(prefs.IsLogging)
 	}
 
 	// Log full version
@@ -310,6 +315,8 @@ func launchService(secret uint64, startedOnPort chan<- int) {
 
 	// network change detector
 	netDetector := netchange.Create()
+	// network change detector for network-condition rules
+	netRulesDetector := netchange.Create()
 
 	// WireGuard keys manager
 	wgKeysMgr := wgkeys.CreateKeysManager(apiObj, platform.WgToolBinaryPath())
@@ -328,6 +335,7 @@ func launchService(secret uint64, startedOnPort chan<- int) {
 		apiObj,
 		updater,
 		netDetector,
+		netRulesDetector,
 		wgKeysMgr,
 		serviceEventsChan,
 		systemLog)
@@ -349,6 +357,18 @@ func launchService(secret uint64, startedOnPort chan<- int) {
 		protocol.Stop()
 	}()
 
+	// reload headless configuration file on SIGHUP
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		for range sighup {
+			log.Info("SIGNAL received: 'SIGHUP'. Reloading configuration file...")
+			if _, err := serv.ManagedConfigApply(false); err != nil {
+				log.Error("Failed to apply configuration file (previous configuration is kept): ", err)
+			}
+		}
+	}()
+
 	// start receiving requests from client (synchronous)
 	if err := protocol.Start(secret, startedOnPort, serv); err != nil {
 		log.Error("Protocol stopped with error:", err)
@@ -134,30 +134,31 @@ func Enable(isEnabled bool) {
 }
 
 // Info - Log info message
-func Info(v ...interface{}) { _info("", v...) }
+func Info(v ...interface{}) { _info("", nil, v...) }
 
 // Debug - Log Debug message
-func Debug(v ...interface{}) { _debug("", v...) }
+func Debug(v ...interface{}) { _debug("", nil, v...) }
 
 // Warning - Log Warning message
-func Warning(v ...interface{}) { _warning("", v...) }
+func Warning(v ...interface{}) { _warning("", nil, v...) }
 
 // Trace - Log Trace message
-func Trace(v ...interface{}) { _trace("", v...) }
+func Trace(v ...interface{}) { _trace("", nil, v...) }
 
 // Error - Log Error message
-func Error(v ...interface{}) { _error("", 0, v...) }
+func Error(v ...interface{}) { _error("", nil, 0, v...) }
 
 // ErrorTrace - Log error with trace
-func ErrorTrace(e error) { _errorTrace("", e) }
+func ErrorTrace(e error) { _errorTrace("", nil, e) }
 
 // Panic - Log Error message and call panic()
-func Panic(v ...interface{}) { _panic("", v...) }
+func Panic(v ...interface{}) { _panic("", nil, v...) }
 
 // Logger - standalone logger object
 type Logger struct {
 	pref       string
 	isDisabled bool
+	fields     []any // key/value pairs added to each record (see With())
 }
 
 // NewLogger - create named logger object
@@ -187,7 +188,7 @@ func (l *Logger) Info(v ...interface{}) {
 	if l.isDisabled {
 		return
 	}
-	_info(l.pref, v...)
+	_info(l.pref, l.fields, v...)
 }
 
 func (l *Logger) Infof(format string, v ...interface{}) {
@@ -199,7 +200,7 @@ func (l *Logger) Debug(v ...interface{}) {
 	if l.isDisabled {
 		return
 	}
-	_debug(l.pref, v...)
+	_debug(l.pref, l.fields, v...)
 }
 
 func (l *Logger) Debugf(format string, v ...interface{}) {
@@ -211,7 +212,7 @@ func (l *Logger) Warning(v ...interface{}) {
 	if l.isDisabled {
 		return
 	}
-	_warning(l.pref, v...)
+	_warning(l.pref, l.fields, v...)
 }
 
 func (l *Logger) Warn(v ...interface{}) {
@@ -226,7 +227,7 @@ func (l *Logger) Trace(v ...interface{}) {
 	if l.isDisabled {
 		return
 	}
-	_trace(l.pref, v...)
+	_trace(l.pref, l.fields, v...)
 }
 
 func (l *Logger) Tracef(format string, v ...interface{}) {
@@ -234,7 +235,7 @@ func (l *Logger) Tracef(format string, v ...interface{}) {
 		return
 	}
 
-	_traceWithOffset(l.pref, 0, fmt.Sprintf(format, v...))
+	_traceWithOffset(l.pref, l.fields, 0, fmt.Sprintf(format, v...))
 }
 
 // Error - Log Error message
@@ -242,14 +243,14 @@ func (l *Logger) Error(v ...interface{}) {
 	if l.isDisabled {
 		return
 	}
-	_error(l.pref, 0, v...)
+	_error(l.pref, l.fields, 0, v...)
 }
 
 func (l *Logger) Errorf(format string, v ...interface{}) {
 	if l.isDisabled {
 		return
 	}
-	_error(l.pref, 0, fmt.Errorf(format, v...))
+	_error(l.pref, l.fields, 0, fmt.Errorf(format, v...))
 }
 
 // ErrorE - Log Error and return same error object
@@ -258,7 +259,7 @@ func (l *Logger) ErrorE(err error, callerStackOffset int) error {
 	if l.isDisabled {
 		return err
 	}
-	_error(l.pref, callerStackOffset, err)
+	_error(l.pref, l.fields, callerStackOffset, err)
 	return err
 }
 
@@ -272,7 +273,7 @@ func (l *Logger) ErrorTrace(e error) {
 	if l.isDisabled {
 		return
 	}
-	_errorTrace(l.pref, e)
+	_errorTrace(l.pref, l.fields, e)
 }
 
 // Panic - Log Error message and call panic()
@@ -280,7 +281,7 @@ func (l *Logger) Panic(v ...interface{}) {
 	if l.isDisabled {
 		return
 	}
-	_panic(l.pref, v...)
+	_panic(l.pref, l.fields, v...)
 }
 
 func (l *Logger) LogCallStack() {
@@ -294,45 +295,72 @@ func PrintCallStackToStderr() {
 // Enable - enable\disable logger
 func (l *Logger) Enable(enable bool) { l.isDisabled = !enable }
 
-func _info(name string, v ...interface{}) {
-	mes, timeStr, _, _ := getLogPrefixes(fmt.Sprint(v...), 0)
-	write(timeStr, name, mes)
+// With returns a copy of the logger which adds the key/value pairs to each record
+// (e.g. log.With("server", host, "port", port).Info("connecting"))
+func (l *Logger) With(keyvals ...any) *Logger {
+	fields := make([]any, 0, len(l.fields)+len(keyvals))
+	fields = append(fields, l.fields...)
+	fields = append(fields, keyvals...)
+	return &Logger{pref: l.pref, isDisabled: l.isDisabled, fields: fields}
 }
 
-func _debug(name string, v ...interface{}) {
-	mes, timeStr, runtimeInfo, _ := getLogPrefixes(fmt.Sprint(v...), 0)
-	write(timeStr, name, "DEBUG", runtimeInfo, mes)
+func _info(name string, fields []any, v ...interface{}) {
+	if !isLevelEnabled(name, LevelInfo) {
+		return
+	}
+	t, mes, timeStr, runtimeInfo, methodInfo := getLogPrefixes(fmt.Sprint(v...), 0)
+	write(record{t, LevelInfo, name, runtimeInfo, methodInfo, mes, fields, []any{timeStr, name, mes}})
+}
+
+func _debug(name string, fields []any, v ...interface{}) {
+	if !isLevelEnabled(name, LevelDebug) {
+		return
+	}
+	t, mes, timeStr, runtimeInfo, methodInfo := getLogPrefixes(fmt.Sprint(v...), 0)
+	write(record{t, LevelDebug, name, runtimeInfo, methodInfo, mes, fields, []any{timeStr, name, "DEBUG", runtimeInfo, mes}})
 }
 
-func _warning(name string, v ...interface{}) {
-	mes, timeStr, runtimeInfo, _ := getLogPrefixes(fmt.Sprint(v...), 0)
-	write(timeStr, name, "WARNING", runtimeInfo, mes)
+func _warning(name string, fields []any, v ...interface{}) {
+	if !isLevelEnabled(name, LevelWarning) {
+		return
+	}
+	t, mes, timeStr, runtimeInfo, methodInfo := getLogPrefixes(fmt.Sprint(v...), 0)
+	write(record{t, LevelWarning, name, runtimeInfo, methodInfo, mes, fields, []any{timeStr, name, "WARNING", runtimeInfo, mes}})
 }
 
-func _traceWithOffset(name string, callerStackOffset int, v ...interface{}) {
-	mes, timeStr, runtimeInfo, methodInfo := getLogPrefixes(fmt.Sprint(v...), callerStackOffset)
-	write(timeStr, name, "TRACE", runtimeInfo+methodInfo, mes)
+func _traceWithOffset(name string, fields []any, callerStackOffset int, v ...interface{}) {
+	if !isLevelEnabled(name, LevelTrace) {
+		return
+	}
+	t, mes, timeStr, runtimeInfo, methodInfo := getLogPrefixes(fmt.Sprint(v...), callerStackOffset)
+	write(record{t, LevelTrace, name, runtimeInfo, methodInfo, mes, fields, []any{timeStr, name, "TRACE", runtimeInfo + methodInfo, mes}})
 }
 
-func _trace(name string, v ...interface{}) {
-	_traceWithOffset(name, 0, v...)
+func _trace(name string, fields []any, v ...interface{}) {
+	_traceWithOffset(name, fields, 0, v...)
 }
 
-func _error(name string, callerStackOffset int, v ...interface{}) {
-	mes, timeStr, runtimeInfo, methodInfo := getLogPrefixes(fmt.Sprint(v...), callerStackOffset)
-	write(timeStr, name, "ERROR", runtimeInfo+methodInfo, mes)
+func _error(name string, fields []any, callerStackOffset int, v ...interface{}) {
+	if !isLevelEnabled(name, LevelError) {
+		return
+	}
+	t, mes, timeStr, runtimeInfo, methodInfo := getLogPrefixes(fmt.Sprint(v...), callerStackOffset)
+	write(record{t, LevelError, name, runtimeInfo, methodInfo, mes, fields, []any{timeStr, name, "ERROR", runtimeInfo + methodInfo, mes}})
 }
 
-func _errorTrace(name string, err error) {
-	mes, timeStr, runtimeInfo, methodInfo := getLogPrefixes(getErrorDetails(err), 0)
-	write(timeStr, name, "ERROR", runtimeInfo+methodInfo, mes)
+func _errorTrace(name string, fields []any, err error) {
+	if !isLevelEnabled(name, LevelError) {
+		return
+	}
+	t, mes, timeStr, runtimeInfo, methodInfo := getLogPrefixes(getErrorDetails(err), 0)
+	write(record{t, LevelError, name, runtimeInfo, methodInfo, mes, fields, []any{timeStr, name, "ERROR", runtimeInfo + methodInfo, mes}})
 }
 
-func _panic(name string, v ...interface{}) {
-	mes, timeStr, runtimeInfo, methodInfo := getLogPrefixes(fmt.Sprint(v...), 0)
+func _panic(name string, fields []any, v ...interface{}) {
+	t, mes, timeStr, runtimeInfo, methodInfo := getLogPrefixes(fmt.Sprint(v...), 0)
 
 	//fmt.Println(timeStr, "PANIC", runtimeInfo+methodInfo, mes)
-	write(timeStr, name, "PANIC", runtimeInfo+methodInfo, mes)
+	write(record{t, LevelPanic, name, runtimeInfo, methodInfo, mes, fields, []any{timeStr, name, "PANIC", runtimeInfo + methodInfo, mes}})
 
 	panic(runtimeInfo + methodInfo + ": " + mes)
 }
@@ -357,8 +385,8 @@ func getCallerMethodName(callerStackOffset int) (string, error) {
 	return caller.Name(), nil
 }
 
-func getLogPrefixes(message string, callerStackOffset int) (retMes string, timeStr string, runtimeInfo string, methodInfo string) {
-	t := time.Now()
+func getLogPrefixes(message string, callerStackOffset int) (t time.Time, retMes string, timeStr string, runtimeInfo string, methodInfo string) {
+	t = time.Now()
 
 	if _, filename, line, isRuntimeInfoOk := runtime.Caller(3 + callerStackOffset); isRuntimeInfoOk {
 		runtimeInfo = filepath.Base(filename) + ":" + strconv.Itoa(line) + ":"
@@ -371,17 +399,19 @@ func getLogPrefixes(message string, callerStackOffset int) (retMes string, timeS
 	timeStr = t.Format(time.StampMilli)
 	retMes = strings.TrimRight(message, "\n")
 
-	return retMes, timeStr, runtimeInfo, methodInfo
+	return t, retMes, timeStr, runtimeInfo, methodInfo
 }
 
-func write(fields ...interface{}) {
+func write(rec record) {
 	writeMutex.Lock()
 	defer writeMutex.Unlock()
 
 	if isLoggingEnabled {
+		line := rec.format()
+
 		if isCanPrintToConsole {
 			// printing into console
-			fmt.Println(fields...)
+			fmt.Println(line)
 		}
 
 		if globalLogFile == nil {
@@ -390,7 +420,12 @@ func write(fields ...interface{}) {
 
 		if globalLogFile != nil {
 			// writting into log-file
-			globalLogFile.WriteString(fmt.Sprintln(fields...))
+			n, _ := globalLogFile.WriteString(line + "\n")
+			activeLogSize += int64(n)
+
+			if isRotationRequired() {
+				rotateLogFile()
+			}
 		}
 	}
 }
@@ -407,6 +442,7 @@ func deleteLogFile() {
 	if len(filePath) > 0 {
 		os.Remove(filePath)
 		os.Remove(filePath + ".0")
+		deleteArchives()
 	}
 }
 
@@ -418,21 +454,35 @@ func createLogFile() error {
 
 	if len(filePath) > 0 {
 		if _, err := os.Stat(filePath); err == nil {
+			// keep the log of the previous-previous session in archive (if rotation enabled)
+			if rotationPolicy.MaxArchives > 0 {
+				if _, err := os.Stat(filePath + ".0"); err == nil {
+					if pendingFile, err := moveToPendingArchive(filePath + ".0"); err == nil {
+						archiveAsync(pendingFile, rotationPolicy)
+					}
+				}
+			}
 			os.Rename(filePath, filePath+".0")
 		}
+		return openLogFile()
+	}
 
-		var err error
-		globalLogFile, err = os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) // read\write only for privileged user
-		if err != nil {
-			return fmt.Errorf("failed to create log-file: %w", err)
-		}
-		// only for Windows: Golang is not able to change file permissins in Windows style
-		if err := filerights.WindowsChmod(filePath, 0600); err != nil { // read\write only for privileged user
-			return fmt.Errorf("failed to change log-file permissions: %w", err)
-		}
-	} else {
-		return fmt.Errorf("logfile name not initialized")
+	return fmt.Errorf("logfile name not initialized")
+}
+
+// openLogFile creates new (empty) active log file
+func openLogFile() error {
+	var err error
+	globalLogFile, err = os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) // read\write only for privileged user
+	if err != nil {
+		return fmt.Errorf("failed to create log-file: %w", err)
 	}
+	activeLogSize = 0
+	activeLogCreated = time.Now()
 
+	// only for Windows: Golang is not able to change file permissins in Windows style
+	if err := filerights.WindowsChmod(filePath, 0600); err != nil { // read\write only for privileged user
+		return fmt.Errorf("failed to change log-file permissions: %w", err)
+	}
 	return nil
 }
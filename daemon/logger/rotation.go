@@ -0,0 +1,437 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/platform/filerights"
+)
+
+// RotationPolicy - log rotation and retention configuration
+type RotationPolicy struct {
+	MaxSizeMB      int // rotate the active log when its size exceeds the limit (0 - no size limit)
+	MaxAgeHours    int // rotate the active log when it is older than the limit (0 - no age limit)
+	MaxArchives    int // max number of compressed archives to keep (0 - rotation disabled)
+	MaxArchiveDays int // remove archives older than N days (0 - no age limit)
+}
+
+// DefaultRotationPolicy returns the default log rotation configuration
+func DefaultRotationPolicy() RotationPolicy {
+	return RotationPolicy{MaxSizeMB: 16, MaxAgeHours: 24 * 7, MaxArchives: 5, MaxArchiveDays: 30}
+}
+
+// IsEnabled returns true if the active log is rotating
+func (p RotationPolicy) IsEnabled() bool {
+	return p.MaxArchives > 0 && (p.MaxSizeMB > 0 || p.MaxAgeHours > 0)
+}
+
+// String returns the policy in format accepted by ParseRotationPolicy()
+func (p RotationPolicy) String() string {
+	return fmt.Sprintf("size=%d,age=%d,archives=%d,days=%d", p.MaxSizeMB, p.MaxAgeHours, p.MaxArchives, p.MaxArchiveDays)
+}
+
+// ParseRotationPolicy parses the policy in format: "size=16,age=168,archives=5,days=30"
+// (size - MB; age - hours; archives - number of archives to keep; days - max age of archives).
+// The values which are not defined are taken from 'base'.
+func ParseRotationPolicy(s string, base RotationPolicy) (RotationPolicy, error) {
+	p := base
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return base, fmt.Errorf("bad log rotation configuration '%s' (expected 'name=value')", item)
+		}
+		val, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil || val < 0 {
+			return base, fmt.Errorf("bad log rotation value '%s' (expected non-negative integer)", item)
+		}
+		switch strings.ToLower(strings.TrimSpace(kv[0])) {
+		case "size":
+			p.MaxSizeMB = val
+		case "age":
+			p.MaxAgeHours = val
+		case "archives":
+			p.MaxArchives = val
+		case "days":
+			p.MaxArchiveDays = val
+		default:
+			return base, fmt.Errorf("unknown log rotation parameter '%s' (expected: size, age, archives, days)", kv[0])
+		}
+	}
+	return p, nil
+}
+
+const archiveTimeFormat = "20060102T150405.000"
+
+var (
+	rotationPolicy       = DefaultRotationPolicy() // in use until SetRotationPolicy() is called
+	activeLogSize        int64
+	activeLogCreated     time.Time
+	archivingWaitGroup   sync.WaitGroup
+	archivingSerialMutex sync.Mutex // only one archiving operation at a time
+)
+
+// SetRotationPolicy applies log rotation configuration
+func SetRotationPolicy(p RotationPolicy) {
+	writeMutex.Lock()
+	defer writeMutex.Unlock()
+	rotationPolicy = p
+
+	if p.MaxArchives > 0 {
+		go applyRetention(p)
+	}
+}
+
+// isRotationRequired must be called under writeMutex
+func isRotationRequired() bool {
+	p := rotationPolicy
+	if !p.IsEnabled() || globalLogFile == nil {
+		return false
+	}
+	if p.MaxSizeMB > 0 && activeLogSize >= int64(p.MaxSizeMB)*1024*1024 {
+		return true
+	}
+	if p.MaxAgeHours > 0 && !activeLogCreated.IsZero() && time.Since(activeLogCreated) >= time.Duration(p.MaxAgeHours)*time.Hour {
+		return true
+	}
+	return false
+}
+
+// rotateLogFile moves the active log to a compressed archive and opens a new log file.
+// Must be called under writeMutex.
+func rotateLogFile() {
+	globalLogFile.Close()
+	globalLogFile = nil
+
+	pendingFile, err := moveToPendingArchive(filePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "log rotation error:", err)
+	}
+
+	if err := openLogFile(); err != nil {
+		fmt.Fprintln(os.Stderr, "log rotation error:", err)
+	}
+
+	if len(pendingFile) > 0 {
+		archiveAsync(pendingFile, rotationPolicy)
+	}
+}
+
+// moveToPendingArchive renames the file to a temporary name; the file will be compressed later (see archiveAsync())
+func moveToPendingArchive(file string) (pendingFile string, err error) {
+	pendingFile = fmt.Sprintf("%s.%s.pending", filePath, time.Now().Format(archiveTimeFormat))
+	if err := os.Rename(file, pendingFile); err != nil {
+		return "", fmt.Errorf("failed to rename '%s': %w", file, err)
+	}
+	return pendingFile, nil
+}
+
+// archiveAsync compresses the file in background and applies the retention policy
+func archiveAsync(pendingFile string, policy RotationPolicy) {
+	archivingWaitGroup.Add(1)
+	go func() {
+		defer archivingWaitGroup.Done()
+		archivingSerialMutex.Lock()
+		defer archivingSerialMutex.Unlock()
+
+		if err := compressFile(pendingFile, strings.TrimSuffix(pendingFile, ".pending")+".gz"); err != nil {
+			fmt.Fprintln(os.Stderr, "log archiving error:", err)
+			return
+		}
+		doApplyRetention(policy)
+	}()
+}
+
+func compressFile(src, dst string) (retErr error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) // read\write only for privileged user
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			os.Remove(dst)
+		}
+	}()
+
+	zw := gzip.NewWriter(out)
+	zw.Name = filepath.Base(filePath)
+	if _, err := io.Copy(zw, in); err != nil {
+		zw.Close()
+		out.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	// only for Windows: Golang is not able to change file permissins in Windows style
+	if err := filerights.WindowsChmod(dst, 0600); err != nil {
+		return err
+	}
+
+	in.Close()
+	return os.Remove(src)
+}
+
+type archiveInfo struct {
+	path    string
+	created time.Time // time of rotation (the archive contains records older than this time)
+}
+
+// listArchives returns compressed log archives (sorted: oldest first)
+func listArchives() []archiveInfo {
+	if len(filePath) == 0 {
+		return nil
+	}
+	files, _ := filepath.Glob(filePath + ".*.gz")
+
+	ret := make([]archiveInfo, 0, len(files))
+	for _, f := range files {
+		timeStr := strings.TrimSuffix(strings.TrimPrefix(f, filePath+"."), ".gz")
+		t, err := time.ParseInLocation(archiveTimeFormat, timeStr, time.Local)
+		if err != nil {
+			continue
+		}
+		ret = append(ret, archiveInfo{path: f, created: t})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].created.Before(ret[j].created) })
+	return ret
+}
+
+func applyRetention(policy RotationPolicy) {
+	archivingSerialMutex.Lock()
+	defer archivingSerialMutex.Unlock()
+	doApplyRetention(policy)
+}
+
+func doApplyRetention(policy RotationPolicy) {
+	archives := listArchives()
+
+	if policy.MaxArchiveDays > 0 {
+		oldest := time.Now().Add(-time.Duration(policy.MaxArchiveDays) * 24 * time.Hour)
+		for len(archives) > 0 && archives[0].created.Before(oldest) {
+			os.Remove(archives[0].path)
+			archives = archives[1:]
+		}
+	}
+
+	if policy.MaxArchives > 0 {
+		for len(archives) > policy.MaxArchives {
+			os.Remove(archives[0].path)
+			archives = archives[1:]
+		}
+	}
+}
+
+// deleteArchives removes all archives. Must be called under writeMutex.
+func deleteArchives() {
+	archivingWaitGroup.Wait()
+	for _, a := range listArchives() {
+		os.Remove(a.path)
+	}
+}
+
+// GetLogTextTimeWindow returns log records in the time window from all available logs (archives, previous session log and active log).
+// Zero 'to' means 'till now'. If the result is bigger than 'maxBytesSize' - only the last part is returned.
+func GetLogTextTimeWindow(from, to time.Time, maxBytesSize int64) (string, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if to.Before(from) {
+		return "", fmt.Errorf("bad time window: 'from' is after 'to'")
+	}
+
+	writeMutex.Lock()
+	if globalLogFile != nil {
+		globalLogFile.Sync()
+	}
+	archives := listArchives()
+	writeMutex.Unlock()
+
+	// archive contains records older than its creation time
+	sources := make([]logSource, 0, len(archives)+2)
+	for _, a := range archives {
+		if !a.created.Before(from) {
+			sources = append(sources, logSource{path: a.path, isCompressed: true})
+		}
+	}
+	sources = append(sources, logSource{path: filePath + ".0"}, logSource{path: filePath})
+
+	// The archives are created by rotation of the active log and from the log of the previous-previous session
+	// (when the daemon starts), so the order of archive names does not reflect the order of records:
+	// the sources are sorted by the time of the first record.
+	for i := range sources {
+		sources[i].firstRecord, _ = sources[i].firstRecordTime()
+	}
+	sort.SliceStable(sources, func(i, j int) bool { return sources[i].firstRecord.Before(sources[j].firstRecord) })
+
+	tail := logTail{maxSize: maxBytesSize}
+	for _, src := range sources {
+		if src.firstRecord.IsZero() || src.firstRecord.After(to) {
+			continue
+		}
+		src.read(from, to, tail.add)
+	}
+	return tail.String(), nil
+}
+
+// logSource - log file (active log, previous session log or compressed archive)
+type logSource struct {
+	path         string
+	isCompressed bool
+	firstRecord  time.Time // time of the first record (zero - no records)
+}
+
+func (src logSource) open() (io.ReadCloser, error) {
+	f, err := os.Open(src.path)
+	if err != nil {
+		return nil, err
+	}
+	if !src.isCompressed {
+		return f, nil
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{zr, f}, nil
+}
+
+func (src logSource) scan(onLine func(line string) bool) {
+	r, err := src.open()
+	if err != nil {
+		return
+	}
+	defer r.Close()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if !onLine(scanner.Text()) {
+			return
+		}
+	}
+}
+
+func (src logSource) firstRecordTime() (ret time.Time, ok bool) {
+	src.scan(func(line string) bool {
+		ret, ok = parseRecordTime(line)
+		return !ok
+	})
+	return ret, ok
+}
+
+// read passes to 'onLine' the lines of the records in the time window [from - to] (a record can be multi-line)
+func (src logSource) read(from, to time.Time, onLine func(line string)) {
+	isRecordMatching := false
+	src.scan(func(line string) bool {
+		if t, ok := parseRecordTime(line); ok {
+			isRecordMatching = !t.Before(from) && !t.After(to)
+		}
+		if isRecordMatching {
+			onLine(line)
+		}
+		return true
+	})
+}
+
+// logTail keeps the last lines which total size does not exceed 'maxSize' bytes (0 - no limit)
+type logTail struct {
+	maxSize int64
+	lines   []string
+	size    int64
+}
+
+func (lt *logTail) add(line string) {
+	lt.lines = append(lt.lines, line)
+	lt.size += int64(len(line)) + 1
+	if lt.maxSize <= 0 {
+		return
+	}
+	drop := 0
+	for drop < len(lt.lines) && lt.size > lt.maxSize {
+		lt.size -= int64(len(lt.lines[drop])) + 1
+		lt.lines[drop] = "" // release the memory
+		drop++
+	}
+	lt.lines = lt.lines[drop:]
+}
+
+func (lt *logTail) String() string {
+	if len(lt.lines) == 0 {
+		return ""
+	}
+	return strings.Join(lt.lines, "\n") + "\n"
+}
+
+// parseRecordTime returns the time of the record (ok==false if the line is not a beginning of a record)
+func parseRecordTime(line string) (t time.Time, ok bool) {
+	if strings.HasPrefix(line, "{") {
+		var jr jsonRecord
+		if err := json.Unmarshal([]byte(line), &jr); err == nil && len(jr.Time) > 0 {
+			if t, err := time.Parse(time.RFC3339Nano, jr.Time); err == nil {
+				return t, true
+			}
+		}
+	}
+
+	// text record: the time has no year information
+	if len(line) < len(time.StampMilli) {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(time.StampMilli, line[:len(time.StampMilli)], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	now := time.Now()
+	t = t.AddDate(now.Year(), 0, 0)
+	if t.After(now.Add(24 * time.Hour)) {
+		t = t.AddDate(-1, 0, 0) // the record from the previous year
+	}
+	return t, true
+}
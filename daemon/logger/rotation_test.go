@@ -0,0 +1,338 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRotationPolicy(t *testing.T) {
+	base := RotationPolicy{MaxSizeMB: 16, MaxAgeHours: 168, MaxArchives: 5, MaxArchiveDays: 30}
+
+	tests := []struct {
+		name    string
+		in      string
+		want    RotationPolicy
+		wantErr bool
+	}{
+		{"empty keeps base", "", base, false},
+		{"all values", "size=1,age=2,archives=3,days=4", RotationPolicy{1, 2, 3, 4}, false},
+		{"partial", "archives=0", RotationPolicy{16, 168, 0, 30}, false},
+		{"spaces and case", " SIZE = 8 , days=0 ", RotationPolicy{8, 168, 5, 0}, false},
+		{"trailing comma", "size=2,", RotationPolicy{2, 168, 5, 30}, false},
+		{"no value", "size", base, true},
+		{"negative", "size=-1", base, true},
+		{"not a number", "age=week", base, true},
+		{"unknown name", "count=1", base, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRotationPolicy(tt.in, base)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRotationPolicy(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRotationPolicy(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			if !tt.wantErr {
+				// String() output must be accepted by the parser
+				if again, err := ParseRotationPolicy(got.String(), RotationPolicy{}); err != nil || again != got {
+					t.Errorf("round trip of %q: got %+v (err: %v)", got.String(), again, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRotationPolicyIsEnabled(t *testing.T) {
+	tests := []struct {
+		p    RotationPolicy
+		want bool
+	}{
+		{RotationPolicy{}, false},
+		{RotationPolicy{MaxSizeMB: 1}, false},
+		{RotationPolicy{MaxArchives: 1}, false},
+		{RotationPolicy{MaxSizeMB: 1, MaxArchives: 1}, true},
+		{RotationPolicy{MaxAgeHours: 1, MaxArchives: 1}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.p.IsEnabled(); got != tt.want {
+			t.Errorf("%+v.IsEnabled() = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestParseRecordTime(t *testing.T) {
+	now := time.Now()
+	textRecord := now.Add(-time.Hour).Format(time.StampMilli) + " [INF] test"
+
+	tests := []struct {
+		name   string
+		line   string
+		want   time.Time
+		wantOk bool
+	}{
+		{"json record", `{"time":"2024-05-06T07:08:09.123Z","level":"info"}`, time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.UTC), true},
+		{"text record", textRecord, now.Add(-time.Hour).Truncate(time.Millisecond), true},
+		{"continuation line", "    at some.function()", time.Time{}, false},
+		{"short line", "abc", time.Time{}, false},
+		{"json without time", `{"level":"info"}`, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRecordTime(tt.line)
+			if ok != tt.wantOk {
+				t.Fatalf("parseRecordTime(%q) ok = %v, want %v", tt.line, ok, tt.wantOk)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("parseRecordTime(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyRetention(t *testing.T) {
+	dir := t.TempDir()
+	savedFilePath := filePath
+	filePath = filepath.Join(dir, "daemon.log")
+	defer func() { filePath = savedFilePath }()
+
+	now := time.Now()
+	ages := []time.Duration{50 * 24 * time.Hour, 20 * 24 * time.Hour, 3 * time.Hour, 2 * time.Hour, time.Hour}
+	var archives []string
+	for _, age := range ages {
+		f := filePath + "." + now.Add(-age).Format(archiveTimeFormat) + ".gz"
+		if err := os.WriteFile(f, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+		archives = append(archives, f)
+	}
+	// not an archive: must be kept
+	unrelated := filePath + ".0"
+	if err := os.WriteFile(unrelated, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := listArchives(); len(got) != len(archives) || got[0].path != archives[0] {
+		t.Fatalf("listArchives() = %v, want %d archives sorted from oldest", got, len(archives))
+	}
+
+	// the archive older than 30 days is removed
+	doApplyRetention(RotationPolicy{MaxArchives: 10, MaxArchiveDays: 30})
+	checkExists(t, archives[0], false)
+	checkExists(t, archives[1], true)
+
+	// only the latest 2 archives are kept
+	doApplyRetention(RotationPolicy{MaxArchives: 2})
+	for i, f := range archives[1:] {
+		checkExists(t, f, i >= 2)
+	}
+	checkExists(t, unrelated, true)
+}
+
+func checkExists(t *testing.T, file string, want bool) {
+	t.Helper()
+	_, err := os.Stat(file)
+	if exists := err == nil; exists != want {
+		t.Errorf("file '%s' exists = %v, want %v", filepath.Base(file), exists, want)
+	}
+}
+
+func TestDefaultRotationPolicy(t *testing.T) {
+	// the policy is in use before the preferences are loaded: the log of the previous-previous session must be archived
+	if !DefaultRotationPolicy().IsEnabled() {
+		t.Errorf("DefaultRotationPolicy() = %+v: rotation disabled", DefaultRotationPolicy())
+	}
+}
+
+func TestCreateLogFileArchivesPreviousSession(t *testing.T) {
+	dir := t.TempDir()
+	savedFilePath, savedPolicy := filePath, rotationPolicy
+	filePath = filepath.Join(dir, "daemon.log")
+	rotationPolicy = DefaultRotationPolicy() // SetRotationPolicy() not called yet
+	defer func() {
+		if globalLogFile != nil {
+			globalLogFile.Close()
+			globalLogFile = nil
+		}
+		filePath, rotationPolicy = savedFilePath, savedPolicy
+	}()
+
+	if err := os.WriteFile(filePath+".0", []byte("previous-previous session\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filePath, []byte("previous session\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := createLogFile(); err != nil {
+		t.Fatalf("createLogFile() unexpected error: %v", err)
+	}
+	archivingWaitGroup.Wait()
+
+	if data, _ := os.ReadFile(filePath + ".0"); string(data) != "previous session\n" {
+		t.Errorf("previous session log = %q", string(data))
+	}
+	archives := listArchives()
+	if len(archives) != 1 {
+		t.Fatalf("archives: %v, want the log of the previous-previous session", archives)
+	}
+	text, err := readArchive(archives[0].path)
+	if err != nil || text != "previous-previous session\n" {
+		t.Errorf("archive content = %q (err: %v)", text, err)
+	}
+}
+
+func readArchive(path string) (string, error) {
+	r, err := logSource{path: path, isCompressed: true}.open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	var sb strings.Builder
+	_, err = io.Copy(&sb, r)
+	return sb.String(), err
+}
+
+func testRecord(t time.Time, msg string) string {
+	return fmt.Sprintf(`{"time":"%s","level":"info","msg":"%s"}`, t.Format(time.RFC3339Nano), msg)
+}
+
+// writeTestLog writes the records (one per minute, starting at 'start') to the file
+func writeTestLog(t *testing.T, file string, start time.Time, msgs ...string) {
+	t.Helper()
+	var sb strings.Builder
+	for i, m := range msgs {
+		sb.WriteString(testRecord(start.Add(time.Duration(i)*time.Minute), m) + "\n")
+	}
+	if err := os.WriteFile(file, []byte(sb.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTestArchive(t *testing.T, archived, start time.Time, msgs ...string) {
+	t.Helper()
+	pending := filePath + "." + archived.Format(archiveTimeFormat) + ".pending"
+	writeTestLog(t, pending, start, msgs...)
+	if err := compressFile(pending, strings.TrimSuffix(pending, ".pending")+".gz"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetLogTextTimeWindow(t *testing.T) {
+	dir := t.TempDir()
+	savedFilePath := filePath
+	filePath = filepath.Join(dir, "daemon.log")
+	defer func() { filePath = savedFilePath }()
+
+	now := time.Now().Truncate(time.Second)
+	sessionStart := now.Add(-time.Hour)
+	// rotated during the previous-previous session
+	writeTestArchive(t, now.Add(-5*time.Hour), now.Add(-6*time.Hour), "s1-a", "s1-b")
+	// the log of the previous-previous session: archived when the current session started
+	writeTestArchive(t, sessionStart, now.Add(-4*time.Hour), "s1-c", "s1-d")
+	// the log of the previous session
+	writeTestLog(t, filePath+".0", now.Add(-3*time.Hour), "s2-a", "s2-b")
+	// rotated twice during the current session (the archives are created after the previous session log)
+	writeTestArchive(t, now.Add(-40*time.Minute), sessionStart, "s3-a", "s3-b")
+	writeTestArchive(t, now.Add(-20*time.Minute), now.Add(-30*time.Minute), "s3-c", "s3-d")
+	// active log
+	writeTestLog(t, filePath, now.Add(-10*time.Minute), "s3-e", "s3-f")
+
+	msgs := func(text string) []string {
+		var ret []string
+		for _, l := range strings.Split(strings.TrimSpace(text), "\n") {
+			if i := strings.Index(l, `"msg":"`); i >= 0 {
+				ret = append(ret, strings.TrimSuffix(l[i+len(`"msg":"`):], `"}`))
+			}
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name    string
+		from    time.Time
+		to      time.Time
+		maxSize int64
+		want    string
+	}{
+		{"all records in time order", now.Add(-7 * time.Hour), time.Time{}, 0, "s1-a s1-b s1-c s1-d s2-a s2-b s3-a s3-b s3-c s3-d s3-e s3-f"},
+		{"previous session and current session", now.Add(-3 * time.Hour), time.Time{}, 0, "s2-a s2-b s3-a s3-b s3-c s3-d s3-e s3-f"},
+		{"window inside the sessions", now.Add(-4 * time.Hour).Add(time.Minute), sessionStart, 0, "s1-d s2-a s2-b s3-a"},
+		{"size limit keeps the latest records", now.Add(-7 * time.Hour), time.Time{}, int64(3 * (len(testRecord(now, "s3-f")) + 1)), "s3-d s3-e s3-f"},
+		{"too small size limit", now.Add(-7 * time.Hour), time.Time{}, 10, ""},
+		{"nothing in the window", now.Add(-9 * time.Hour), now.Add(-8 * time.Hour), 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := GetLogTextTimeWindow(tt.from, tt.to, tt.maxSize)
+			if err != nil {
+				t.Fatalf("GetLogTextTimeWindow() unexpected error: %v", err)
+			}
+			if got := strings.Join(msgs(text), " "); got != tt.want {
+				t.Errorf("GetLogTextTimeWindow() records = %q, want %q", got, tt.want)
+			}
+			if tt.maxSize > 0 && int64(len(text)) > tt.maxSize {
+				t.Errorf("GetLogTextTimeWindow() size = %d, limit %d", len(text), tt.maxSize)
+			}
+		})
+	}
+
+	if _, err := GetLogTextTimeWindow(now, now.Add(-time.Hour), 0); err == nil {
+		t.Errorf("GetLogTextTimeWindow() with bad window: no error")
+	}
+}
+
+func TestLogTail(t *testing.T) {
+	tail := logTail{maxSize: 12}
+	for _, l := range []string{"aaaa", "bbbb", "cccc", "dd"} {
+		tail.add(l)
+	}
+	if got := tail.String(); got != "cccc\ndd\n" {
+		t.Errorf("logTail = %q, want %q", got, "cccc\ndd\n")
+	}
+	if tail.size > tail.maxSize {
+		t.Errorf("logTail size %d exceeds the limit %d", tail.size, tail.maxSize)
+	}
+
+	// the line bigger than the limit is not kept
+	tail.add(strings.Repeat("x", 20))
+	if got := tail.String(); got != "" {
+		t.Errorf("logTail = %q, want empty", got)
+	}
+
+	// no limit
+	unlimited := logTail{}
+	for _, l := range []string{"aaaa", "bbbb", "cccc"} {
+		unlimited.add(l)
+	}
+	if got := unlimited.String(); got != "aaaa\nbbbb\ncccc\n" {
+		t.Errorf("logTail without limit = %q", got)
+	}
+}
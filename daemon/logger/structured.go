@@ -0,0 +1,347 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level - log records severity
+type Level int
+
+const (
+	LevelTrace   Level = iota
+	LevelDebug   Level = iota
+	LevelInfo    Level = iota
+	LevelWarning Level = iota
+	LevelError   Level = iota
+	LevelPanic   Level = iota
+)
+
+var levelNames = map[Level]string{
+	LevelTrace:   "TRACE",
+	LevelDebug:   "DEBUG",
+	LevelInfo:    "INFO",
+	LevelWarning: "WARNING",
+	LevelError:   "ERROR",
+	LevelPanic:   "PANIC",
+}
+
+func (l Level) String() string {
+	if n, ok := levelNames[l]; ok {
+		return n
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// ParseLevel converts level name to Level (case insensitive; "warn" is accepted as alias to "warning")
+func ParseLevel(s string) (Level, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "WARN" {
+		return LevelWarning, nil
+	}
+	for l, n := range levelNames {
+		if n == s {
+			return l, nil
+		}
+	}
+	return LevelTrace, fmt.Errorf("unknown log level '%s'", s)
+}
+
+// DefaultComponentKey - key of the default level in the per-component levels configuration
+const DefaultComponentKey = "*"
+
+var (
+	configMutex      sync.RWMutex
+	isStructured     bool
+	defaultLevel     = LevelTrace
+	componentsLevels = map[string]Level{}
+)
+
+// SetStructured enables\disables structured logging (each record is written as a JSON line)
+func SetStructured(structured bool) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	isStructured = structured
+}
+
+// IsStructured returns true if structured (JSON lines) logging is enabled
+func IsStructured() bool {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+	return isStructured
+}
+
+// SetLevels applies per-component levels configuration in format: "frwl=trace,prefs=warn,*=info"
+// (component is the name of the logger; '*' - the default level for all other components).
+// Empty string resets the configuration (all records are logged).
+func SetLevels(levelsConfig string) error {
+	levels, defLevel, err := ParseLevels(levelsConfig)
+	if err != nil {
+		return err
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	componentsLevels = levels
+	defaultLevel = defLevel
+	return nil
+}
+
+// ParseLevels parses per-component levels configuration (see SetLevels() for details)
+func ParseLevels(levelsConfig string) (levels map[string]Level, defLevel Level, err error) {
+	levels = map[string]Level{}
+	defLevel = LevelTrace
+
+	for _, item := range strings.Split(levelsConfig, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return nil, LevelTrace, fmt.Errorf("bad log level configuration '%s' (expected 'component=level')", item)
+		}
+		component := componentName(kv[0])
+		level, err := ParseLevel(kv[1])
+		if err != nil {
+			return nil, LevelTrace, err
+		}
+		if component == DefaultComponentKey {
+			defLevel = level
+		} else {
+			levels[component] = level
+		}
+	}
+	return levels, defLevel, nil
+}
+
+// LevelsConfig returns current per-component levels configuration (in the format accepted by SetLevels())
+func LevelsConfig() string {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+
+	items := make([]string, 0, len(componentsLevels)+1)
+	for c, l := range componentsLevels {
+		items = append(items, c+"="+strings.ToLower(l.String()))
+	}
+	sort.Strings(items)
+	if defaultLevel != LevelTrace {
+		items = append(items, DefaultComponentKey+"="+strings.ToLower(defaultLevel.String()))
+	}
+	return strings.Join(items, ",")
+}
+
+// componentName returns component name by the logger prefix (e.g. "[frwl  ]" -> "frwl")
+func componentName(prefix string) string {
+	return strings.ToLower(strings.Trim(prefix, " [],./:\\"))
+}
+
+func isLevelEnabled(prefix string, level Level) bool {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+
+	minLevel := defaultLevel
+	if len(componentsLevels) > 0 {
+		if l, ok := componentsLevels[componentName(prefix)]; ok {
+			minLevel = l
+		}
+	}
+	return level >= minLevel
+}
+
+// record - one log record
+type record struct {
+	time      time.Time
+	level     Level
+	prefix    string
+	caller    string // "file.go:123"
+	function  string
+	message   string
+	fields    []any // key/value pairs
+	textParts []any // the record data in text (not structured) format
+}
+
+type jsonRecord struct {
+	Time      string         `json:"time"`
+	Level     string         `json:"level"`
+	Component string         `json:"component,omitempty"`
+	Caller    string         `json:"caller,omitempty"`
+	Function  string         `json:"func,omitempty"`
+	Message   string         `json:"msg"`
+	Fields    map[string]any `json:"fields,omitempty"`
+}
+
+// format returns the record line (without line-end)
+func (r record) format() string {
+	if !IsStructured() {
+		line := strings.TrimRight(fmt.Sprintln(r.textParts...), "\n")
+		if len(r.fields) > 0 {
+			line += " " + fieldsText(r.fields)
+		}
+		return line
+	}
+
+	jr := jsonRecord{
+		Time:      r.time.Format(time.RFC3339Nano),
+		Level:     r.level.String(),
+		Component: componentName(r.prefix),
+		Caller:    strings.TrimSuffix(r.caller, ":"),
+		Function:  strings.TrimSuffix(strings.TrimPrefix(r.function, "(in "), "):"),
+		Message:   r.message,
+		Fields:    fieldsMap(r.fields),
+	}
+	data, err := json.Marshal(jr)
+	if err != nil {
+		// some field values can not be serialized: use their text representation
+		for k, v := range jr.Fields {
+			jr.Fields[k] = fmt.Sprint(v)
+		}
+		if data, err = json.Marshal(jr); err != nil {
+			return fmt.Sprintf(`{"level":"ERROR","msg":%q}`, "failed to serialize log record: "+err.Error())
+		}
+	}
+	return string(data)
+}
+
+func fieldsMap(kv []any) map[string]any {
+	if len(kv) == 0 {
+		return nil
+	}
+	ret := make(map[string]any, (len(kv)+1)/2)
+	for i := 0; i < len(kv); i += 2 {
+		key := fmt.Sprint(kv[i])
+		if i+1 >= len(kv) {
+			ret[key] = nil
+			break
+		}
+		switch v := kv[i+1].(type) {
+		case error:
+			ret[key] = v.Error()
+		case fmt.Stringer:
+			ret[key] = v.String()
+		default:
+			ret[key] = v
+		}
+	}
+	return ret
+}
+
+func fieldsText(kv []any) string {
+	m := fieldsMap(kv)
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, fmt.Sprintf("%s=%v", k, m[k]))
+	}
+	return strings.Join(items, " ")
+}
+
+// Filter - parameters to filter log text
+type Filter struct {
+	Components []string // empty - all components
+	MinLevel   Level
+}
+
+// IsEmpty returns true if filter does not skip any records
+func (f Filter) IsEmpty() bool {
+	return len(f.Components) == 0 && f.MinLevel == LevelTrace
+}
+
+// FilterLogText returns only records which are matching the filter.
+// Both text and structured (JSON lines) records are supported.
+// Lines which are not a beginning of a record (e.g. call stack) are belonging to the previous record.
+func FilterLogText(text string, filter Filter) string {
+	if filter.IsEmpty() {
+		return text
+	}
+
+	components := make(map[string]struct{}, len(filter.Components))
+	for _, c := range filter.Components {
+		components[componentName(c)] = struct{}{}
+	}
+
+	var sb strings.Builder
+	isRecordMatching := false
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if component, level, isRecordStart := parseRecordLine(line); isRecordStart {
+			_, isComponentOk := components[component]
+			isRecordMatching = (len(components) == 0 || isComponentOk) && level >= filter.MinLevel
+		}
+		if isRecordMatching {
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+var textLevelRegexp = regexp.MustCompile(`^ ?(TRACE|DEBUG|WARNING|ERROR|PANIC) \S+\.go:\d+:`)
+
+// parseRecordLine returns component and level of the record (isRecordStart==false if the line is not a beginning of a record)
+func parseRecordLine(line string) (component string, level Level, isRecordStart bool) {
+	if strings.HasPrefix(line, "{") {
+		var jr jsonRecord
+		if err := json.Unmarshal([]byte(line), &jr); err == nil && len(jr.Level) > 0 {
+			level, _ := ParseLevel(jr.Level)
+			return jr.Component, level, true
+		}
+	}
+
+	// text record: "<time> [prefix] [LEVEL ...] message" (prefix is empty for package-level logging functions)
+	if len(line) < len(time.StampMilli) {
+		return "", LevelTrace, false
+	}
+	if _, err := time.Parse(time.StampMilli, line[:len(time.StampMilli)]); err != nil {
+		return "", LevelTrace, false
+	}
+	rest := strings.TrimLeft(line[len(time.StampMilli):], " ")
+	if strings.HasPrefix(rest, "[") {
+		if prefixEnd := strings.Index(rest, "]"); prefixEnd > 0 {
+			component = componentName(rest[:prefixEnd+1])
+			rest = rest[prefixEnd+1:]
+		}
+	}
+
+	// the level token (absent for INFO records) directly follows the prefix and is followed by the caller info:
+	// "DEBUG file.go:123: message"; a level name in the message text of INFO records is not a level token
+	level = LevelInfo
+	if m := textLevelRegexp.FindStringSubmatch(rest); m != nil {
+		if l, err := ParseLevel(m[1]); err == nil {
+			level = l
+		}
+	}
+	return component, level, true
+}
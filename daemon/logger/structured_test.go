@@ -0,0 +1,199 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLevels(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		wantLevels map[string]Level
+		wantDef    Level
+		wantErr    string
+	}{
+		{"empty", "", map[string]Level{}, LevelTrace, ""},
+		{"default only", "*=info", map[string]Level{}, LevelInfo, ""},
+		{"components", "frwl=trace, prefs=WARN ,*=error,", map[string]Level{"frwl": LevelTrace, "prefs": LevelWarning}, LevelError, ""},
+		{"component as prefix", "[frwl  ]=debug", map[string]Level{"frwl": LevelDebug}, LevelTrace, ""},
+		{"last value wins", "frwl=debug,frwl=panic", map[string]Level{"frwl": LevelPanic}, LevelTrace, ""},
+		{"no value", "frwl", nil, LevelTrace, "expected 'component=level'"},
+		{"unknown level", "frwl=verbose", nil, LevelTrace, "unknown log level 'VERBOSE'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels, def, err := ParseLevels(tt.in)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseLevels(%q) error = %v, want error containing %q", tt.in, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLevels(%q) unexpected error: %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(levels, tt.wantLevels) || def != tt.wantDef {
+				t.Errorf("ParseLevels(%q) = %v, %v; want %v, %v", tt.in, levels, def, tt.wantLevels, tt.wantDef)
+			}
+		})
+	}
+}
+
+// textLine returns the log line in the same text format as the logger writes it
+func textLine(level Level, prefix, message string) string {
+	timeStr := time.Date(2025, 3, 4, 10, 11, 12, 0, time.Local).Format(time.StampMilli)
+	parts := []any{timeStr, prefix, message}
+	if level != LevelInfo {
+		parts = []any{timeStr, prefix, level.String(), "file.go:12:(in pkg.Func):", message}
+	}
+	return record{level: level, prefix: prefix, message: message, textParts: parts}.format()
+}
+
+func TestFilterLogText(t *testing.T) {
+	SetStructured(false)
+
+	jsonLine := func(level Level, component, message string) string {
+		data, _ := json.Marshal(jsonRecord{Time: "2025-03-04T10:11:12Z", Level: level.String(), Component: component, Message: message})
+		return string(data)
+	}
+
+	lines := []string{
+		textLine(LevelInfo, "[frwl  ]", "firewall enabled"),
+		textLine(LevelError, "[frwl  ]", "failed to apply rule"),
+		"goroutine 1 [running]:", // continuation of the previous record
+		textLine(LevelDebug, "[prefs ]", "saving preferences"),
+		textLine(LevelInfo, "[prefs ]", "ERROR is only a word in the message"),
+		textLine(LevelWarning, "", "package-level warning"),
+		jsonLine(LevelInfo, "frwl", "json info"),
+		jsonLine(LevelError, "prefs", "json error"),
+	}
+	text := strings.Join(lines, "\n") + "\n"
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []int // indexes of the expected lines
+	}{
+		{"empty filter", Filter{}, []int{0, 1, 2, 3, 4, 5, 6, 7}},
+		{"component", Filter{Components: []string{"frwl"}}, []int{0, 1, 2, 6}},
+		{"component as prefix", Filter{Components: []string{"[prefs ]"}}, []int{3, 4, 7}},
+		{"level", Filter{MinLevel: LevelWarning}, []int{1, 2, 5, 7}},
+		{"level info", Filter{MinLevel: LevelInfo}, []int{0, 1, 2, 4, 5, 6, 7}},
+		{"component and level", Filter{Components: []string{"prefs"}, MinLevel: LevelError}, []int{7}},
+		{"no match", Filter{Components: []string{"wg"}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want strings.Builder
+			for _, i := range tt.want {
+				want.WriteString(lines[i] + "\n")
+			}
+			if got := FilterLogText(text, tt.filter); got != want.String() {
+				t.Errorf("FilterLogText() =\n%s\nwant:\n%s", got, want.String())
+			}
+		})
+	}
+}
+
+func TestParseRecordLine(t *testing.T) {
+	SetStructured(false)
+
+	tests := []struct {
+		name          string
+		line          string
+		wantComponent string
+		wantLevel     Level
+		wantStart     bool
+	}{
+		{"info", textLine(LevelInfo, "[frwl  ]", "started"), "frwl", LevelInfo, true},
+		{"trace", textLine(LevelTrace, "[frwl  ]", "started"), "frwl", LevelTrace, true},
+		{"panic without prefix", textLine(LevelPanic, "", "crashed"), "", LevelPanic, true},
+		{"level word in info message", textLine(LevelInfo, "[wg    ]", "DEBUG mode enabled"), "wg", LevelInfo, true},
+		{"level word in info message without prefix", textLine(LevelInfo, "", "WARNING: low disk space"), "", LevelInfo, true},
+		{"level word later in message", textLine(LevelInfo, "[wg    ]", "reported ERROR file.go:1:"), "wg", LevelInfo, true},
+		{"continuation", "\tmain.go:10 +0x1d", "", LevelTrace, false},
+		{"json", `{"time":"2025-03-04T10:11:12Z","level":"WARNING","component":"frwl","msg":"x"}`, "frwl", LevelWarning, true},
+		{"not json record", `{"msg":"x"}`, "", LevelTrace, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component, level, isStart := parseRecordLine(tt.line)
+			if component != tt.wantComponent || level != tt.wantLevel || isStart != tt.wantStart {
+				t.Errorf("parseRecordLine(%q) = %q, %v, %v; want %q, %v, %v", tt.line, component, level, isStart, tt.wantComponent, tt.wantLevel, tt.wantStart)
+			}
+		})
+	}
+}
+
+func TestRecordFormatJSON(t *testing.T) {
+	SetStructured(true)
+	defer SetStructured(false)
+
+	recTime := time.Date(2024, 12, 31, 23, 59, 58, 123000000, time.UTC)
+	r := record{
+		time:     recTime,
+		level:    LevelWarning,
+		prefix:   "[frwl  ]",
+		caller:   "file.go:12:",
+		function: "(in pkg.Func):",
+		message:  "message",
+		fields:   []any{"port", 443, "server", "host"},
+	}
+
+	var jr jsonRecord
+	if err := json.Unmarshal([]byte(r.format()), &jr); err != nil {
+		t.Fatal(err)
+	}
+	want := jsonRecord{
+		Time:      recTime.Format(time.RFC3339Nano),
+		Level:     "WARNING",
+		Component: "frwl",
+		Caller:    "file.go:12",
+		Function:  "pkg.Func",
+		Message:   "message",
+		Fields:    map[string]any{"port": float64(443), "server": "host"},
+	}
+	if !reflect.DeepEqual(jr, want) {
+		t.Errorf("format() = %+v, want %+v", jr, want)
+	}
+}
+
+func TestLoggerWith(t *testing.T) {
+	l := NewLogger("test")
+	l2 := l.With("a", 1).With("b", 2)
+	if len(l.fields) != 0 {
+		t.Errorf("With() must not change the original logger fields: %v", l.fields)
+	}
+	if want := []any{"a", 1, "b", 2}; !reflect.DeepEqual(l2.fields, want) || l2.pref != l.pref {
+		t.Errorf("With() fields = %v, want %v", l2.fields, want)
+	}
+	if got := fieldsText(l2.fields); got != "a=1 b=2" {
+		t.Errorf("fieldsText() = %q", got)
+	}
+}
@@ -0,0 +1,102 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package netinfo
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// InterfaceType - type of network interface
+type InterfaceType string
+
+const (
+	InterfaceTypeUnknown  InterfaceType = ""
+	InterfaceTypeEthernet InterfaceType = "ethernet"
+	InterfaceTypeWiFi     InterfaceType = "wifi"
+	InterfaceTypeOther    InterfaceType = "other"
+)
+
+// DefaultNetworkInfo - information about the network which is in use by the default route
+type DefaultNetworkInfo struct {
+	InterfaceName    string
+	InterfaceType    InterfaceType
+	GatewayIP        net.IP
+	GatewayMAC       net.HardwareAddr // nil - unknown
+	DnsSearchDomains []string         // DNS search domains (DNS suffixes) provided by DHCP or configured manually
+}
+
+// DefaultNetwork returns information about the network which is in use by the default route
+// (the routes of VPN interfaces are ignored)
+func DefaultNetwork() (DefaultNetworkInfo, error) {
+	// method should be implemented in platform-specific file
+	return doDefaultNetwork()
+}
+
+// parseArpMAC returns the first MAC address found in the output of 'arp' utility
+//
+//	macOS:   "? (192.168.1.1) at 0:11:22:33:44:55 on en0 ifscope [ethernet]"
+//	Windows: "  192.168.1.1           00-11-22-33-44-55     dynamic"
+var arpMacRegexp = regexp.MustCompile(`(?i)(?:^|\s)([0-9a-f]{1,2}(?:[:-][0-9a-f]{1,2}){5})(?:\s|$)`)
+
+func parseArpMAC(out string) net.HardwareAddr {
+	m := arpMacRegexp.FindStringSubmatch(out)
+	if len(m) < 2 {
+		return nil
+	}
+	// normalize: "0:11:22:33:44:55" => "00:11:22:33:44:55"
+	octets := strings.FieldsFunc(m[1], func(r rune) bool { return r == ':' || r == '-' })
+	for i, o := range octets {
+		if len(o) == 1 {
+			octets[i] = "0" + o
+		}
+	}
+	mac, err := net.ParseMAC(strings.Join(octets, ":"))
+	if err != nil {
+		return nil
+	}
+	return mac
+}
+
+// parseResolvConfSearchDomains returns DNS search domains defined in resolv.conf file content
+func parseResolvConfSearchDomains(content string) (domains []string) {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || (fields[0] != "search" && fields[0] != "domain") {
+			continue
+		}
+		for _, d := range fields[1:] {
+			if d = strings.TrimSuffix(strings.ToLower(d), "."); len(d) > 0 && d != "." {
+				domains = appendUnique(domains, d)
+			}
+		}
+	}
+	return domains
+}
+
+func appendUnique(list []string, v string) []string {
+	for _, e := range list {
+		if e == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
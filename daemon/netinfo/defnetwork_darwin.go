@@ -0,0 +1,73 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package netinfo
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+var (
+	// "Hardware Port: Wi-Fi\nDevice: en0"
+	hardwarePortRegexp = regexp.MustCompile(`(?m)^Hardware Port:\s*(.+)\s*\nDevice:\s*(\S+)`)
+	// "  search domain[0] : corp.example.com"
+	scutilSearchDomainRegexp = regexp.MustCompile(`(?m)^\s*search domain\[\d+\]\s*:\s*(\S+)`)
+)
+
+func doDefaultNetwork() (ret DefaultNetworkInfo, err error) {
+	routes, err := doGetDefaultRoutes(false)
+	if err != nil {
+		return ret, err
+	}
+	ret.InterfaceName = routes[0].InterfaceName
+	ret.GatewayIP = routes[0].GatewayIP
+	ret.InterfaceType = InterfaceTypeOther
+
+	if out, err := exec.Command("/usr/sbin/networksetup", "-listallhardwareports").CombinedOutput(); err == nil {
+		for _, m := range hardwarePortRegexp.FindAllStringSubmatch(string(out), -1) {
+			if m[2] != ret.InterfaceName {
+				continue
+			}
+			port := strings.ToLower(m[1])
+			if strings.Contains(port, "wi-fi") || strings.Contains(port, "airport") {
+				ret.InterfaceType = InterfaceTypeWiFi
+			} else if strings.Contains(port, "ethernet") || strings.Contains(port, "lan") {
+				ret.InterfaceType = InterfaceTypeEthernet
+			}
+			break
+		}
+	}
+
+	if out, err := exec.Command("/usr/sbin/arp", "-n", ret.GatewayIP.String()).CombinedOutput(); err == nil {
+		ret.GatewayMAC = parseArpMAC(string(out))
+	}
+
+	if out, err := exec.Command("/usr/sbin/scutil", "--dns").CombinedOutput(); err == nil {
+		for _, m := range scutilSearchDomainRegexp.FindAllStringSubmatch(string(out), -1) {
+			ret.DnsSearchDomains = appendUnique(ret.DnsSearchDomains, strings.TrimSuffix(strings.ToLower(m[1]), "."))
+		}
+	} else {
+		log.Warning(fmt.Sprintf("failed to obtain DNS search domains: %v", err))
+	}
+
+	return ret, nil
+}
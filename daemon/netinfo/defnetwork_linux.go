@@ -0,0 +1,94 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package netinfo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+)
+
+func doDefaultNetwork() (ret DefaultNetworkInfo, err error) {
+	routes, err := netlink.RouteList(nil, netlink.FAMILY_V4)
+	if err != nil {
+		return ret, fmt.Errorf("failed to get routes: %w", err)
+	}
+
+	// default route with the lowest metric (the routes without gateway, e.g. over WireGuard interface, are ignored)
+	var defRoute *netlink.Route
+	for i, r := range routes {
+		if r.Gw == nil || (r.Dst != nil && !r.Dst.IP.IsUnspecified()) {
+			continue
+		}
+		if r.Dst != nil {
+			if ones, _ := r.Dst.Mask.Size(); ones != 0 {
+				continue
+			}
+		}
+		if defRoute == nil || r.Priority < defRoute.Priority {
+			defRoute = &routes[i]
+		}
+	}
+	if defRoute == nil {
+		return ret, fmt.Errorf("no default route found")
+	}
+
+	link, err := netlink.LinkByIndex(defRoute.LinkIndex)
+	if err != nil {
+		return ret, fmt.Errorf("failed to get interface of the default route: %w", err)
+	}
+
+	ret.InterfaceName = link.Attrs().Name
+	ret.InterfaceType = linuxInterfaceType(link)
+	ret.GatewayIP = defRoute.Gw
+
+	if neighs, err := netlink.NeighList(defRoute.LinkIndex, netlink.FAMILY_V4); err == nil {
+		for _, n := range neighs {
+			if n.IP.Equal(defRoute.Gw) && len(n.HardwareAddr) > 0 {
+				ret.GatewayMAC = n.HardwareAddr
+				break
+			}
+		}
+	}
+
+	if content, err := os.ReadFile("/etc/resolv.conf"); err == nil {
+		ret.DnsSearchDomains = parseResolvConfSearchDomains(string(content))
+	}
+
+	return ret, nil
+}
+
+func linuxInterfaceType(link netlink.Link) InterfaceType {
+	sysPath := filepath.Join("/sys/class/net", link.Attrs().Name)
+	if _, err := os.Stat(filepath.Join(sysPath, "wireless")); err == nil {
+		return InterfaceTypeWiFi
+	}
+	if _, err := os.Stat(filepath.Join(sysPath, "phy80211")); err == nil {
+		return InterfaceTypeWiFi
+	}
+	// ARPHRD_ETHER == 1
+	if t, err := os.ReadFile(filepath.Join(sysPath, "type")); err == nil && strings.TrimSpace(string(t)) == "1" && link.Type() == "device" {
+		return InterfaceTypeEthernet
+	}
+	return InterfaceTypeOther
+}
@@ -0,0 +1,81 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package netinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseArpMAC(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want string
+	}{
+		{"macos", "? (192.168.1.1) at 0:11:22:33:44:55 on en0 ifscope [ethernet]", "00:11:22:33:44:55"},
+		{"macos full octets", "? (10.0.0.1) at a4:2b:b0:c1:d2:e3 on en1 ifscope [ethernet]", "a4:2b:b0:c1:d2:e3"},
+		{"windows", "\nInterface: 192.168.1.10 --- 0xb\n  Internet Address      Physical Address      Type\n  192.168.1.1           00-11-22-AA-BB-CC     dynamic\n", "00:11:22:aa:bb:cc"},
+		{"no entry", "? (192.168.1.1) at (incomplete) on en0 ifscope [ethernet]", ""},
+		{"empty", "", ""},
+		{"ipv6 address is not a mac", "fe80::1:2:3:4:5 dynamic", ""},
+		{"too many octets", "00:11:22:33:44:55:66", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseArpMAC(tt.out)
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("parseArpMAC() = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.String() != tt.want {
+				t.Errorf("parseArpMAC() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResolvConfSearchDomains(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"empty", "", nil},
+		{"nameservers only", "nameserver 127.0.0.53\noptions edns0 trust-ad\n", nil},
+		{"search", "nameserver 127.0.0.53\nsearch corp.example.com lan\n", []string{"corp.example.com", "lan"}},
+		{"domain and search", "domain Corp.Example.COM.\nsearch corp.example.com home.arpa\n", []string{"corp.example.com", "home.arpa"}},
+		{"root domain is ignored", "search .\n", nil},
+		{"keyword without value", "search\n", nil},
+		{"commented out", "# search corp.example.com\n", nil},
+		{"tabs and crlf", "search\tcorp.example.com\r\n", []string{"corp.example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseResolvConfSearchDomains(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseResolvConfSearchDomains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package netinfo
+
+import (
+	"os/exec"
+	"strings"
+
+	"golang.org/x/sys/windows"
+	"golang.zx2c4.com/wireguard/windows/tunnel/winipcfg"
+)
+
+func doDefaultNetwork() (ret DefaultNetworkInfo, err error) {
+	gwIP, iface, err := DefaultGatewayEx(false)
+	if err != nil {
+		return ret, err
+	}
+	ret.InterfaceName = iface.Name
+	ret.GatewayIP = gwIP
+	ret.InterfaceType = InterfaceTypeOther
+
+	if adapters, err := winipcfg.GetAdaptersAddresses(windows.AF_INET, winipcfg.GAAFlagDefault); err == nil {
+		for _, a := range adapters {
+			if int(a.IfIndex) != iface.Index {
+				continue
+			}
+			switch a.IfType {
+			case winipcfg.IfTypeEthernetCSMACD:
+				ret.InterfaceType = InterfaceTypeEthernet
+			case winipcfg.IfTypeIEEE80211:
+				ret.InterfaceType = InterfaceTypeWiFi
+			}
+			if s := strings.ToLower(a.DNSSuffix()); len(s) > 0 {
+				ret.DnsSearchDomains = appendUnique(ret.DnsSearchDomains, s)
+			}
+			for sfx := a.FirstDNSSuffix; sfx != nil; sfx = sfx.Next {
+				if s := strings.ToLower(sfx.String()); len(s) > 0 {
+					ret.DnsSearchDomains = appendUnique(ret.DnsSearchDomains, s)
+				}
+			}
+			break
+		}
+	}
+
+	if out, err := exec.Command("arp", "-a", gwIP.String()).CombinedOutput(); err == nil {
+		ret.GatewayMAC = parseArpMAC(string(out))
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,117 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package netinfo
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	// size of IP + ICMP headers of the ICMP echo request
+	icmpHeadersSizeIPv4 = 20 + 8
+	icmpHeadersSizeIPv6 = 40 + 8
+
+	pmtuProbeTimeout  = time.Second
+	pmtuProbeAttempts = 2 // a lost reply must not be treated as "packet too big"
+)
+
+// IsPacketFitsPath sends ICMP echo requests with the 'Don't Fragment' bit set.
+// Returns 'true' when the IP packet of 'packetSize' bytes (including headers) reached the host and the reply was received.
+// 'fwMark' - firewall mark of the probe packets (Linux only; 0 - packets are not marked)
+func IsPacketFitsPath(ip net.IP, packetSize int, fwMark int) (bool, error) {
+	if ip == nil {
+		return false, fmt.Errorf("IP address not defined")
+	}
+	payloadSize := packetSize - icmpHeadersSizeIPv4
+	if ip.To4() == nil {
+		payloadSize = packetSize - icmpHeadersSizeIPv6
+	}
+	if payloadSize < 0 {
+		return false, fmt.Errorf("packet size %d is too small", packetSize)
+	}
+
+	for i := 0; i < pmtuProbeAttempts; i++ {
+		ok, err := doPingDontFragment(ip, payloadSize, pmtuProbeTimeout, fwMark)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DiscoverPathMtu detects the largest IP packet (in range [minSize - maxSize]) which reaches the host without fragmentation.
+// Returns an error when even the packet of 'minSize' does not reach the host (e.g. ICMP is blocked).
+// 'fwMark' - firewall mark of the probe packets (Linux only; 0 - packets are not marked)
+func DiscoverPathMtu(ip net.IP, minSize, maxSize int, fwMark int) (int, error) {
+	mtu, err := discoverPathMtu(minSize, maxSize, func(packetSize int) (bool, error) {
+		return IsPacketFitsPath(ip, packetSize, fwMark)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", ip, err)
+	}
+	return mtu, nil
+}
+
+// discoverPathMtu searches the largest packet size (in range [minSize - maxSize]) for which 'isFits' returns 'true'.
+// 'isFits' is expected to be monotonic: if a packet fits, any smaller packet fits too.
+func discoverPathMtu(minSize, maxSize int, isFits func(packetSize int) (bool, error)) (int, error) {
+	if minSize > maxSize {
+		return 0, fmt.Errorf("bad packet size range [%d - %d]", minSize, maxSize)
+	}
+
+	ok, err := isFits(minSize)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, fmt.Errorf("no reply (packet size %d)", minSize)
+	}
+	if minSize == maxSize {
+		return minSize, nil
+	}
+
+	// in most cases the largest packet fits
+	if ok, err = isFits(maxSize); err != nil {
+		return 0, err
+	}
+	if ok {
+		return maxSize, nil
+	}
+
+	// binary search: 'low' always fits, 'high' is the upper limit
+	low, high := minSize, maxSize-1
+	for low < high {
+		mid := (low + high + 1) / 2
+		if ok, err = isFits(mid); err != nil {
+			return 0, err
+		}
+		if ok {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low, nil
+}
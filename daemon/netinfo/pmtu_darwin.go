@@ -0,0 +1,50 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package netinfo
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/shell"
+)
+
+// doPingDontFragment sends one ICMP echo request with the 'Don't Fragment' bit set. Returns 'true' when the reply received.
+// 'fwMark' is not supported on this platform.
+func doPingDontFragment(ip net.IP, payloadSize int, timeout time.Duration, fwMark int) (bool, error) {
+	if fwMark > 0 {
+		return false, fmt.Errorf("marking packets is not supported on this platform")
+	}
+
+	// command: /sbin/ping -n -c 1 -t 1 -D -s 1372 10.0.0.1
+	// (exit code 2 - no reply, including "Message too long" when the packet exceeds the local interface MTU;
+	// other non-zero codes - errors: they must not be treated as "packet too big")
+	timeoutSec := strconv.Itoa(max(1, int(timeout/time.Second)))
+	_, err := shell.ExecGetExitCode(nil, "/sbin/ping", "-n", "-c", "1", "-t", timeoutSec, "-D", "-s", strconv.Itoa(payloadSize), ip.String())
+	if err == nil {
+		return true, nil
+	}
+	if exitCode, e := shell.GetCmdExitCode(err); e == nil && exitCode == 2 {
+		return false, nil
+	}
+	return false, fmt.Errorf("ping failed: %w", err)
+}
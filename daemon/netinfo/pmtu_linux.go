@@ -0,0 +1,53 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package netinfo
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/shell"
+)
+
+// doPingDontFragment sends one ICMP echo request with the 'Don't Fragment' bit set. Returns 'true' when the reply received.
+// 'fwMark' - firewall mark of the packet (0 - not marked): it allows to route the packet outside the VPN tunnel (policy routing)
+func doPingDontFragment(ip net.IP, payloadSize int, timeout time.Duration, fwMark int) (bool, error) {
+	// (exit code 1 - no reply, including "local error: message too long" when the packet exceeds the local interface MTU;
+	// exit code 2 - other errors, e.g. unknown host or no permissions: they must not be treated as "packet too big")
+	_, err := shell.ExecGetExitCode(nil, "ping", pingDontFragmentArgs(ip, payloadSize, timeout, fwMark)...)
+	if err == nil {
+		return true, nil
+	}
+	if exitCode, e := shell.GetCmdExitCode(err); e == nil && exitCode == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("ping failed: %w", err)
+}
+
+// pingDontFragmentArgs returns the 'ping' arguments, e.g.: ping -n -c 1 -W 1 -M do -m 51820 -s 1372 10.0.0.1
+func pingDontFragmentArgs(ip net.IP, payloadSize int, timeout time.Duration, fwMark int) []string {
+	args := []string{"-n", "-c", "1", "-W", strconv.Itoa(max(1, int(timeout/time.Second))), "-M", "do"}
+	if fwMark > 0 {
+		args = append(args, "-m", strconv.Itoa(fwMark))
+	}
+	return append(args, "-s", strconv.Itoa(payloadSize), ip.String())
+}
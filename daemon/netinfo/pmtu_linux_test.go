@@ -0,0 +1,52 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package netinfo
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPingDontFragmentArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		ip      string
+		size    int
+		timeout time.Duration
+		fwMark  int
+		want    []string
+	}{
+		{"not marked", "10.0.0.1", 1372, time.Second, 0, []string{"-n", "-c", "1", "-W", "1", "-M", "do", "-s", "1372", "10.0.0.1"}},
+		{"marked", "203.0.113.1", 1452, time.Second, 51820, []string{"-n", "-c", "1", "-W", "1", "-M", "do", "-m", "51820", "-s", "1452", "203.0.113.1"}},
+		{"sub-second timeout", "10.0.0.1", 1252, time.Millisecond * 500, 0, []string{"-n", "-c", "1", "-W", "1", "-M", "do", "-s", "1252", "10.0.0.1"}},
+		{"ipv6", "2001:db8::1", 1372, time.Second * 2, 51820, []string{"-n", "-c", "1", "-W", "2", "-M", "do", "-m", "51820", "-s", "1372", "2001:db8::1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pingDontFragmentArgs(net.ParseIP(tt.ip), tt.size, tt.timeout, tt.fwMark)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pingDontFragmentArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
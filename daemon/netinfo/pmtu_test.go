@@ -0,0 +1,90 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package netinfo
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiscoverPathMtu(t *testing.T) {
+	probeErr := fmt.Errorf("ping failed")
+	tests := []struct {
+		name     string
+		minSize  int
+		maxSize  int
+		pathMtu  int   // the largest packet which fits (0 - nothing fits)
+		probeErr error // returned by the probe of any size
+		want     int
+		wantErr  string
+	}{
+		{name: "max fits", minSize: 1280, maxSize: 1500, pathMtu: 1500, want: 1500},
+		{name: "path larger than max", minSize: 1280, maxSize: 1420, pathMtu: 9000, want: 1420},
+		{name: "only min fits", minSize: 1280, maxSize: 1500, pathMtu: 1280, want: 1280},
+		{name: "max minus one", minSize: 1280, maxSize: 1500, pathMtu: 1499, want: 1499},
+		{name: "min plus one", minSize: 1280, maxSize: 1500, pathMtu: 1281, want: 1281},
+		{name: "in the middle", minSize: 1280, maxSize: 1500, pathMtu: 1392, want: 1392},
+		{name: "pppoe", minSize: 1340, maxSize: 1480, pathMtu: 1432, want: 1432},
+		{name: "min equals max", minSize: 1280, maxSize: 1280, pathMtu: 1500, want: 1280},
+		{name: "adjacent sizes", minSize: 1280, maxSize: 1281, pathMtu: 1280, want: 1280},
+		{name: "min does not fit", minSize: 1280, maxSize: 1500, pathMtu: 1000, wantErr: "no reply (packet size 1280)"},
+		{name: "nothing fits", minSize: 1280, maxSize: 1500, pathMtu: 0, wantErr: "no reply"},
+		{name: "bad range", minSize: 1500, maxSize: 1280, pathMtu: 1500, wantErr: "bad packet size range [1500 - 1280]"},
+		{name: "probe error", minSize: 1280, maxSize: 1500, pathMtu: 1400, probeErr: probeErr, wantErr: "ping failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probed := map[int]bool{}
+			isFits := func(packetSize int) (bool, error) {
+				if packetSize < tt.minSize || packetSize > tt.maxSize {
+					t.Fatalf("probed size %d is out of range [%d - %d]", packetSize, tt.minSize, tt.maxSize)
+				}
+				if probed[packetSize] {
+					t.Fatalf("size %d probed twice", packetSize)
+				}
+				probed[packetSize] = true
+				if tt.probeErr != nil {
+					return false, tt.probeErr
+				}
+				return packetSize <= tt.pathMtu, nil
+			}
+
+			got, err := discoverPathMtu(tt.minSize, tt.maxSize, isFits)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("discoverPathMtu() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("discoverPathMtu() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("discoverPathMtu() = %d, want %d", got, tt.want)
+			}
+			// min + max + binary search over 220 sizes: ~10 probes
+			if len(probed) > 12 {
+				t.Errorf("discoverPathMtu() made %d probes", len(probed))
+			}
+		})
+	}
+}
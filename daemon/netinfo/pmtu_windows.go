@@ -0,0 +1,58 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package netinfo
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/shell"
+)
+
+// doPingDontFragment sends one ICMP echo request with the 'Don't Fragment' bit set. Returns 'true' when the reply received.
+// 'fwMark' is not supported on this platform.
+func doPingDontFragment(ip net.IP, payloadSize int, timeout time.Duration, fwMark int) (bool, error) {
+	if fwMark > 0 {
+		return false, fmt.Errorf("marking packets is not supported on this platform")
+	}
+
+	pingBinaryPath := "ping.exe"
+	if envVarSystemroot := os.Getenv("SYSTEMROOT"); len(envVarSystemroot) > 0 {
+		pingBinaryPath = strings.ReplaceAll(path.Join(envVarSystemroot, "system32", "ping.exe"), "/", "\\")
+	}
+
+	// command: ping.exe -n 1 -w 1000 -f -l 1372 10.0.0.1
+	// Note: exit code is 0 even for "Destination host unreachable" and "Packet needs to be fragmented but DF set."
+	// replies, so the output is checked for the echo reply ("Reply from 10.0.0.1: bytes=1372 time=21ms TTL=64")
+	outText, _, _, _, err := shell.ExecAndGetOutput(nil, 4096, "", pingBinaryPath,
+		"-n", "1", "-w", strconv.Itoa(int(timeout/time.Millisecond)), "-f", "-l", strconv.Itoa(payloadSize), ip.String())
+	if err != nil {
+		if _, e := shell.GetCmdExitCode(err); e == nil {
+			return false, nil // non-zero exit code: no reply
+		}
+		return false, fmt.Errorf("ping failed: %w", err)
+	}
+	return strings.Contains(strings.ToUpper(outText), "TTL="), nil
+}
@@ -25,11 +25,13 @@ package obfsproxy
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/swapnilsparsh/devsVPN/daemon/logger"
@@ -49,6 +51,9 @@ const (
 	None  ObfsProxyVersion = 0
 	OBFS3 ObfsProxyVersion = 3
 	OBFS4 ObfsProxyVersion = 4
+	// HTTP transports (require Config.Url)
+	MEEK      ObfsProxyVersion = 5 // meek_lite: HTTPS requests to the front domain (domain fronting)
+	WEBTUNNEL ObfsProxyVersion = 6 // WebTunnel: HTTP upgrade on the web server which looks like a normal web site
 )
 
 // Obfs4IatMode - Inter-Arrival Time (IAT)
@@ -70,18 +75,60 @@ const (
 type Config struct {
 	Version  ObfsProxyVersion
 	Obfs4Iat Obfs4IatMode
+
+	// Parameters of HTTP transports (MEEK, WEBTUNNEL)
+	Url   string `json:",omitempty"` // URL of the transport endpoint (e.g. "https://example.com/path")
+	Front string `json:",omitempty"` // MEEK only: domain used for DNS and TLS SNI instead of the URL host
 }
 
 // IsObfsproxy returns 'true' when enabled
 func (c Config) IsObfsproxy() bool {
 	switch c.Version {
-	case OBFS3, OBFS4:
+	case OBFS3, OBFS4, MEEK, WEBTUNNEL:
 	default:
 		return false
 	}
 	return true
 }
 
+// IsHttpTransport returns 'true' for the transports which are tunneled over HTTP(S) to Config.Url
+func (c Config) IsHttpTransport() bool {
+	return c.Version == MEEK || c.Version == WEBTUNNEL
+}
+
+// TransportName returns the name of transport as it is known by the pluggable transport binary
+func (c Config) TransportName() string {
+	switch c.Version {
+	case OBFS3:
+		return "obfs3"
+	case OBFS4:
+		return "obfs4"
+	case MEEK:
+		return "meek_lite"
+	case WEBTUNNEL:
+		return "webtunnel"
+	}
+	return ""
+}
+
+// Validate checks that the configuration contains all the parameters required by the transport
+func (c Config) Validate() error {
+	if !c.IsObfsproxy() {
+		return fmt.Errorf("unsupported obfsproxy version: %d", c.Version)
+	}
+	if !c.IsHttpTransport() {
+		return nil
+	}
+	u, err := url.Parse(c.Url)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return fmt.Errorf("%s transport requires 'https://' URL", c.TransportName())
+	}
+	if c.Version == WEBTUNNEL && len(c.Front) > 0 {
+		return fmt.Errorf("front domain is not applicable for %s transport", c.TransportName())
+	}
+	return nil
+}
+
 func (c Config) Equals(b Config) bool {
 	if c.IsObfsproxy() != b.IsObfsproxy() {
 		return false
@@ -89,6 +136,9 @@ func (c Config) Equals(b Config) bool {
 	if c.Version == b.Version && c.Version == OBFS3 {
 		return true
 	}
+	if c.IsHttpTransport() {
+		return c.Version == b.Version && c.Url == b.Url && c.Front == b.Front
+	}
 	return c.Version == b.Version && c.Obfs4Iat == b.Obfs4Iat
 }
 
@@ -99,6 +149,12 @@ func (c Config) ToString() string {
 	if c.Version == OBFS4 {
 		return fmt.Sprintf("obfs%d, IAT%d", c.Version, c.Obfs4Iat)
 	}
+	if c.IsHttpTransport() {
+		if len(c.Front) > 0 {
+			return fmt.Sprintf("%s, %s (front %s)", c.TransportName(), c.Url, c.Front)
+		}
+		return fmt.Sprintf("%s, %s", c.TransportName(), c.Url)
+	}
 	return fmt.Sprintf("obfs%d", c.Version)
 }
 
@@ -130,6 +186,29 @@ func (p *Obfsproxy) MakeObfs4AuthFileContent(cert string) string {
 	return fmt.Sprintf("cert=%s;\niat-mode=%d", cert, p.config.Obfs4Iat)
 }
 
+// MakeClientArgs returns per-connection arguments of the transport in the PT format: "key=value;key=value".
+// The arguments are passed to the PT client in the SOCKS5 authentication request (see NewSocksAuth())
+func (p *Obfsproxy) MakeClientArgs(cert string) string {
+	var args []string
+	switch p.config.Version {
+	case OBFS4:
+		args = append(args, "cert="+escapeArgValue(cert), fmt.Sprintf("iat-mode=%d", p.config.Obfs4Iat))
+	case MEEK:
+		args = append(args, "url="+escapeArgValue(p.config.Url))
+		if len(p.config.Front) > 0 {
+			args = append(args, "front="+escapeArgValue(p.config.Front))
+		}
+	case WEBTUNNEL:
+		args = append(args, "url="+escapeArgValue(p.config.Url), "ver=0.0.1")
+	}
+	return strings.Join(args, ";")
+}
+
+// escapeArgValue escapes the special characters of PT argument value (PT spec: backslash, '=' and ';' must be escaped by backslash)
+func escapeArgValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`).Replace(v)
+}
+
 func (p *Obfsproxy) Config() Config {
 	return p.config
 }
@@ -192,9 +271,9 @@ func (p *Obfsproxy) start() (port int, command *startedCmd, err error) {
 	// https://gitweb.torproject.org/torspec.git/tree/pt-spec.txt
 	// https://www.fortinet.com/blog/threat-research/dissecting-tor-bridges-pluggable-transport-part-2
 
-	obfsProxyVer := "obfs4"
-	if p.config.Version == OBFS3 {
-		obfsProxyVer = "obfs3"
+	obfsProxyVer := p.config.TransportName()
+	if obfsProxyVer == "" {
+		return 0, nil, fmt.Errorf("unsupported obfsproxy version: %d", p.config.Version)
 	}
 	cmd.Env = os.Environ()
 	cmd.Env = append(cmd.Env, "TOR_PT_CLIENT_TRANSPORTS="+obfsProxyVer)
@@ -24,6 +24,8 @@ package obfsproxy_test
 
 import (
 	"fmt"
+	"net"
+	"strings"
 	"testing"
 	"time"
 
@@ -54,3 +56,121 @@ func TestStart(t *testing.T) {
 	}
 	fmt.Println("STOPED")
 }
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    obfsproxy.Config
+		wantErr string
+	}{
+		{"disabled", obfsproxy.Config{}, "unsupported obfsproxy version: 0"},
+		{"unknown version", obfsproxy.Config{Version: 7}, "unsupported obfsproxy version: 7"},
+		{"obfs3", obfsproxy.Config{Version: obfsproxy.OBFS3}, ""},
+		{"obfs4", obfsproxy.Config{Version: obfsproxy.OBFS4, Obfs4Iat: obfsproxy.Obfs4IatOnParanoid}, ""},
+		{"meek", obfsproxy.Config{Version: obfsproxy.MEEK, Url: "https://meek.example.com/", Front: "cdn.example.com"}, ""},
+		{"meek no url", obfsproxy.Config{Version: obfsproxy.MEEK}, "meek_lite transport requires 'https://' URL"},
+		{"meek http url", obfsproxy.Config{Version: obfsproxy.MEEK, Url: "http://meek.example.com/"}, "requires 'https://' URL"},
+		{"meek no host", obfsproxy.Config{Version: obfsproxy.MEEK, Url: "https:///path"}, "requires 'https://' URL"},
+		{"webtunnel", obfsproxy.Config{Version: obfsproxy.WEBTUNNEL, Url: "https://example.com:8443/secret"}, ""},
+		{"webtunnel front", obfsproxy.Config{Version: obfsproxy.WEBTUNNEL, Url: "https://example.com/", Front: "cdn.example.com"}, "front domain is not applicable for webtunnel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.conf.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMakeClientArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		conf obfsproxy.Config
+		cert string
+		want string
+	}{
+		{"obfs3", obfsproxy.Config{Version: obfsproxy.OBFS3}, "ignored", ""},
+		{"obfs4", obfsproxy.Config{Version: obfsproxy.OBFS4, Obfs4Iat: obfsproxy.Obfs4IatOn}, "AbC+/d", "cert=AbC+/d;iat-mode=1"},
+		{"obfs4 escaped cert", obfsproxy.Config{Version: obfsproxy.OBFS4}, `a=b;c\d`, `cert=a\=b\;c\\d;iat-mode=0`},
+		{"meek", obfsproxy.Config{Version: obfsproxy.MEEK, Url: "https://meek.example.com/"}, "", "url=https://meek.example.com/"},
+		{"meek front", obfsproxy.Config{Version: obfsproxy.MEEK, Url: "https://meek.example.com/?a=1;b=2", Front: "cdn.example.com"}, "",
+			`url=https://meek.example.com/?a\=1\;b\=2;front=cdn.example.com`},
+		{"webtunnel", obfsproxy.Config{Version: obfsproxy.WEBTUNNEL, Url: "https://example.com/secret"}, "", "url=https://example.com/secret;ver=0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := obfsproxy.CreateObfsproxy("", tt.conf).MakeClientArgs(tt.cert); got != tt.want {
+				t.Errorf("MakeClientArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSocksAuth(t *testing.T) {
+	long := strings.Repeat("a", 255)
+
+	tests := []struct {
+		name         string
+		args         string
+		wantNil      bool
+		wantUser     string
+		wantPassword string
+		wantErr      bool
+	}{
+		{"empty", "", true, "", "", false},
+		{"short", "cert=abc;iat-mode=0", false, "cert=abc;iat-mode=0", "\x00", false},
+		{"max username", long, false, long, "\x00", false},
+		{"split", long + "bcd", false, long, "bcd", false},
+		{"max length", long + long, false, long, long, false},
+		{"too long", long + long + "x", false, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, err := obfsproxy.NewSocksAuth(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSocksAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantNil {
+				if auth != nil {
+					t.Fatalf("NewSocksAuth() = %+v, want nil", auth)
+				}
+				return
+			}
+			if auth == nil || auth.User != tt.wantUser || auth.Password != tt.wantPassword {
+				t.Fatalf("NewSocksAuth() = %+v, want user %q password %q", auth, tt.wantUser, tt.wantPassword)
+			}
+			if auth.User+strings.TrimSuffix(auth.Password, "\x00") != tt.args {
+				t.Errorf("username and password must carry the arguments")
+			}
+		})
+	}
+}
+
+func TestCreateUdpTunnelIPv6(t *testing.T) {
+	_, err := obfsproxy.CreateUdpTunnel("", obfsproxy.Config{Version: obfsproxy.OBFS3}, net.ParseIP("2001:db8::1"), 443, "")
+	if err == nil || !strings.Contains(err.Error(), "IPv6 is not supported") {
+		t.Fatalf("CreateUdpTunnel() error = %v, want IPv6 error", err)
+	}
+
+	tun, err := obfsproxy.CreateUdpTunnel("", obfsproxy.Config{Version: obfsproxy.OBFS3}, net.ParseIP("192.0.2.1"), 443, "")
+	if err != nil {
+		t.Fatalf("CreateUdpTunnel() unexpected error: %v", err)
+	}
+	if hosts, _ := tun.GetRemoteHosts(); len(hosts) != 1 || !hosts[0].Equal(net.ParseIP("192.0.2.1")) {
+		t.Errorf("GetRemoteHosts() = %v", hosts)
+	}
+}
@@ -0,0 +1,38 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package obfsproxy
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/shell"
+)
+
+func implAddHostRoute(host net.IP, defaultGateway net.IP) error {
+	if err := shell.Exec(log, "/sbin/route", "-n", "add", "-inet", "-net", host.String(), defaultGateway.String(), "255.255.255.255"); err != nil {
+		return fmt.Errorf("adding route shell comand error : %w", err)
+	}
+	return nil
+}
+
+func implDeleteHostRoute(host net.IP) {
+	shell.Exec(log, "/sbin/route", "-n", "delete", "-inet", "-net", host.String())
+}
@@ -0,0 +1,432 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package obfsproxy
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/netinfo"
+	"golang.org/x/net/proxy"
+)
+
+const (
+	// max size of SOCKS5 username and password (RFC1929)
+	socksAuthFieldMaxLen = 255
+	// timeout to establish the stream through the pluggable transport
+	streamConnectTimeout = time.Second * 30
+	// max UDP datagram size which can be framed into the stream (2-bytes length prefix)
+	maxDatagramSize = 0xFFFF
+)
+
+// errIPv6NotSupported - the routes to the PT hosts (outside of the VPN tunnel) and the firewall exceptions are applied for IPv4 hosts only
+var errIPv6NotSupported = errors.New("IPv6 is not supported for obfsproxy tunnel endpoints (routes and firewall exceptions are applied for IPv4 hosts only)")
+
+// UdpTunnel carries UDP datagrams (e.g. WireGuard) through the pluggable transport.
+//
+// Data flow:
+//
+//	[WireGuard] -UDP-> [UdpTunnel local port] -SOCKS5-> [PT client (obfs4proxy)] -(obfs4/meek/webtunnel)-> [PT server] -> [VPN server]
+//
+// Inside the PT stream every datagram is prefixed by its length (2 bytes, big-endian);
+// the PT server forwards the datagrams to the VPN server and sends the responses back the same way.
+type UdpTunnel struct {
+	proxy      *Obfsproxy
+	remoteAddr string   // address of the PT server (the target of SOCKS5 CONNECT request)
+	clientArgs string   // PT per-connection arguments
+	hosts      []net.IP // hosts the PT client connects to (they must be accessible outside of VPN tunnel)
+
+	mutex       sync.Mutex
+	udpConn     *net.UDPConn
+	stream      net.Conn
+	peerAddr    *net.UDPAddr // local address of the last datagram sender (WireGuard)
+	stoppedChan chan struct{}
+	stopErr     error
+	isStopping  bool
+
+	routeMutex     sync.Mutex
+	defaultGateway net.IP // default gateway used for routes to 'hosts'
+}
+
+// CreateUdpTunnel creates new tunnel object
+//
+//	binaryPath - path to pluggable transport binary (obfs4proxy)
+//	conf - transport configuration
+//	remoteIp, remotePort - the PT server (for HTTP transports the value is ignored: the server is defined by conf.Url)
+//	cert - obfs4 server certificate (obfs4 only)
+func CreateUdpTunnel(binaryPath string, conf Config, remoteIp net.IP, remotePort int, cert string) (*UdpTunnel, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	if conf.Version == OBFS4 && len(cert) == 0 {
+		return nil, fmt.Errorf("bad configuration (empty Key for obfs4)")
+	}
+
+	t := &UdpTunnel{proxy: CreateObfsproxy(binaryPath, conf)}
+	t.clientArgs = t.proxy.MakeClientArgs(cert)
+
+	if !conf.IsHttpTransport() {
+		if remoteIp == nil || remotePort <= 0 {
+			return nil, fmt.Errorf("obfsproxy remote endpoint not defined")
+		}
+		if remoteIp.To4() == nil {
+			return nil, fmt.Errorf("obfsproxy remote endpoint %s: %w", remoteIp, errIPv6NotSupported)
+		}
+		t.hosts = []net.IP{remoteIp}
+		t.remoteAddr = net.JoinHostPort(remoteIp.String(), strconv.Itoa(remotePort))
+		return t, nil
+	}
+
+	// HTTP transports: the PT client connects to the URL host (or to the front domain for meek)
+	u, _ := url.Parse(conf.Url)
+	hostName := u.Hostname()
+	if len(conf.Front) > 0 {
+		hostName = conf.Front
+	}
+	ips, err := net.LookupIP(hostName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve '%s': %w", hostName, err)
+	}
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			t.hosts = append(t.hosts, ip)
+		}
+	}
+	if len(t.hosts) == 0 {
+		return nil, fmt.Errorf("no IPv4 address for '%s': %w", hostName, errIPv6NotSupported)
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	t.remoteAddr = net.JoinHostPort(t.hosts[0].String(), port)
+	return t, nil
+}
+
+// Config returns the transport configuration
+func (t *UdpTunnel) Config() Config {
+	return t.proxy.Config()
+}
+
+// GetRemoteHosts returns IP addresses of the hosts which the transport connects to
+func (t *UdpTunnel) GetRemoteHosts() ([]net.IP, error) {
+	return t.hosts, nil
+}
+
+// Start starts the pluggable transport and the local UDP listener.
+// Returns the local UDP port which must be used as the VPN server endpoint.
+func (t *UdpTunnel) Start() (localPort int, err error) {
+	t.mutex.Lock()
+	isUsed := t.stoppedChan != nil || t.isStopping
+	t.mutex.Unlock()
+	if isUsed {
+		return 0, fmt.Errorf("obfsproxy tunnel already started")
+	}
+
+	defer func() {
+		if err != nil {
+			t.close(err)
+		}
+	}()
+
+	if err := t.setMainRoute(nil); err != nil {
+		return 0, fmt.Errorf("error applying route to remote obfsproxy endpoint: %w", err)
+	}
+
+	socksPort, err := t.proxy.Start()
+	if err != nil {
+		return 0, err
+	}
+
+	stream, err := t.dialStream(socksPort)
+	if err != nil {
+		return 0, err
+	}
+	t.mutex.Lock()
+	t.stream = stream
+	t.mutex.Unlock()
+
+	udpConn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to start local UDP listener: %w", err)
+	}
+
+	t.mutex.Lock()
+	t.udpConn = udpConn
+	t.stoppedChan = make(chan struct{})
+	t.mutex.Unlock()
+
+	go t.forwardUdpToStream(udpConn, socksPort)
+	go t.forwardStreamToUdp(stream)
+	go func() {
+		// PT process stopped: the tunnel can not work anymore
+		t.close(t.proxy.Wait())
+	}()
+
+	localPort = udpConn.LocalAddr().(*net.UDPAddr).Port
+	log.Info(fmt.Sprintf("Obfsproxy tunnel started (local UDP port %d -> %s)", localPort, t.proxy.Config().ToString()))
+	return localPort, nil
+}
+
+// Wait blocks until the tunnel stopped
+func (t *UdpTunnel) Wait() error {
+	t.mutex.Lock()
+	stoppedChan := t.stoppedChan
+	t.mutex.Unlock()
+
+	if stoppedChan == nil {
+		return nil
+	}
+	<-stoppedChan
+	return t.stopErr
+}
+
+// Stop stops the tunnel and the pluggable transport
+func (t *UdpTunnel) Stop() error {
+	t.mutex.Lock()
+	stoppedChan := t.stoppedChan
+	t.mutex.Unlock()
+
+	t.close(nil)
+	if stoppedChan != nil {
+		<-stoppedChan
+	}
+	return nil
+}
+
+func (t *UdpTunnel) close(reason error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.isStopping {
+		return
+	}
+	t.isStopping = true
+	t.stopErr = reason
+
+	if reason != nil {
+		log.Error(fmt.Sprintf("Obfsproxy tunnel stopped: %v", reason))
+	}
+	if t.udpConn != nil {
+		t.udpConn.Close()
+	}
+	if t.stream != nil {
+		t.stream.Close()
+	}
+	t.proxy.Stop()
+
+	if err := t.deleteMainRoute(); err != nil {
+		log.Error(err)
+	}
+	if t.stoppedChan != nil {
+		close(t.stoppedChan)
+	}
+}
+
+// NewSocksAuth returns SOCKS5 authentication data which carries PT per-connection arguments.
+// PT spec: the arguments are split between username and password fields;
+// when they fit into the username - the password is a single NUL byte.
+func NewSocksAuth(clientArgs string) (*proxy.Auth, error) {
+	if len(clientArgs) == 0 {
+		return nil, nil
+	}
+	if len(clientArgs) > socksAuthFieldMaxLen*2 {
+		return nil, fmt.Errorf("obfsproxy arguments are too long")
+	}
+	if len(clientArgs) <= socksAuthFieldMaxLen {
+		return &proxy.Auth{User: clientArgs, Password: "\x00"}, nil
+	}
+	return &proxy.Auth{User: clientArgs[:socksAuthFieldMaxLen], Password: clientArgs[socksAuthFieldMaxLen:]}, nil
+}
+
+func (t *UdpTunnel) dialStream(socksPort int) (net.Conn, error) {
+	auth, err := NewSocksAuth(t.clientArgs)
+	if err != nil {
+		return nil, err
+	}
+	dialer, err := proxy.SOCKS5("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(socksPort)), auth, proxy.Direct)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamConnectTimeout)
+	defer cancel()
+	conn, err := dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", t.remoteAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect through obfsproxy: %w", err)
+	}
+	return conn, nil
+}
+
+// forwardUdpToStream reads datagrams from local UDP port and writes them into the stream.
+// If the stream was broken - it is re-established on the next datagram.
+func (t *UdpTunnel) forwardUdpToStream(udpConn *net.UDPConn, socksPort int) {
+	buf := make([]byte, maxDatagramSize+2)
+	for {
+		n, addr, err := udpConn.ReadFromUDP(buf[2:])
+		if err != nil {
+			t.close(nil) // local UDP connection closed
+			return
+		}
+
+		t.mutex.Lock()
+		t.peerAddr = addr
+		stream := t.stream
+		isStopping := t.isStopping
+		t.mutex.Unlock()
+
+		if isStopping {
+			return
+		}
+
+		if stream == nil {
+			log.Info("Re-establishing obfsproxy stream...")
+			if stream, err = t.dialStream(socksPort); err != nil {
+				log.Error(err)
+				continue // drop the datagram; VPN will retry
+			}
+			t.mutex.Lock()
+			if t.isStopping {
+				t.mutex.Unlock()
+				stream.Close()
+				return
+			}
+			t.stream = stream
+			t.mutex.Unlock()
+			go t.forwardStreamToUdp(stream)
+		}
+
+		binary.BigEndian.PutUint16(buf, uint16(n))
+		if _, err := stream.Write(buf[:n+2]); err != nil {
+			log.Error(fmt.Sprintf("Obfsproxy stream write error: %v", err))
+			t.dropStream(stream)
+		}
+	}
+}
+
+// forwardStreamToUdp reads framed datagrams from the stream and sends them to the local peer (WireGuard)
+func (t *UdpTunnel) forwardStreamToUdp(stream net.Conn) {
+	defer t.dropStream(stream)
+
+	hdr := make([]byte, 2)
+	buf := make([]byte, maxDatagramSize)
+	for {
+		if _, err := io.ReadFull(stream, hdr); err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Info(fmt.Sprintf("Obfsproxy stream closed: %v", err))
+			}
+			return
+		}
+		n := int(binary.BigEndian.Uint16(hdr))
+		if _, err := io.ReadFull(stream, buf[:n]); err != nil {
+			return
+		}
+
+		t.mutex.Lock()
+		peer := t.peerAddr
+		udpConn := t.udpConn
+		t.mutex.Unlock()
+
+		if peer == nil || udpConn == nil {
+			continue
+		}
+		udpConn.WriteToUDP(buf[:n], peer)
+	}
+}
+
+// dropStream closes the stream; the new one will be established on the next outgoing datagram
+func (t *UdpTunnel) dropStream(stream net.Conn) {
+	stream.Close()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.stream == stream {
+		t.stream = nil
+	}
+}
+
+// UpdateMainRoute updates routes to the remote hosts.
+// This method must be called when the default route was changed (e.g. changed WiFi network)
+func (t *UdpTunnel) UpdateMainRoute() error {
+	t.routeMutex.Lock()
+	curDefaultGateway := t.defaultGateway
+	t.routeMutex.Unlock()
+
+	if curDefaultGateway == nil {
+		return nil
+	}
+	gwIp, err := netinfo.DefaultGatewayIP()
+	if err != nil {
+		return fmt.Errorf("failed to check obfsproxy route consistency: %w", err)
+	}
+	if curDefaultGateway.Equal(gwIp) {
+		return nil
+	}
+
+	log.Info("Updating route to obfsproxy server...")
+	if err := t.deleteMainRoute(); err != nil {
+		log.Error(err)
+	}
+	return t.setMainRoute(gwIp)
+}
+
+// setMainRoute routes the remote hosts via default gateway (outside of the VPN tunnel)
+func (t *UdpTunnel) setMainRoute(defaultGateway net.IP) error {
+	var err error
+	if defaultGateway == nil {
+		if defaultGateway, err = netinfo.DefaultGatewayIP(); err != nil {
+			return fmt.Errorf("getting default gateway ip error : %w", err)
+		}
+	}
+
+	for _, h := range t.hosts {
+		if err := implAddHostRoute(h, defaultGateway); err != nil {
+			return err
+		}
+	}
+
+	t.routeMutex.Lock()
+	defer t.routeMutex.Unlock()
+	t.defaultGateway = defaultGateway
+	return nil
+}
+
+func (t *UdpTunnel) deleteMainRoute() error {
+	t.routeMutex.Lock()
+	isApplied := t.defaultGateway != nil
+	t.defaultGateway = nil
+	t.routeMutex.Unlock()
+
+	if !isApplied {
+		return nil
+	}
+	for _, h := range t.hosts {
+		implDeleteHostRoute(h)
+	}
+	return nil
+}
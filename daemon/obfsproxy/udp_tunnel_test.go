@@ -0,0 +1,94 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package obfsproxy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestUdpTunnelFraming checks the length-prefix framing of the datagrams in the PT stream (both directions)
+func TestUdpTunnelFraming(t *testing.T) {
+	stream, server := net.Pipe() // 'server' is the PT server side of the stream
+	defer server.Close()
+
+	udpConn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer, err := net.DialUDP("udp4", nil, udpConn.LocalAddr().(*net.UDPAddr)) // WireGuard side
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	tun := &UdpTunnel{proxy: CreateObfsproxy("", Config{Version: OBFS3}), stream: stream, udpConn: udpConn, stoppedChan: make(chan struct{})}
+	go tun.forwardUdpToStream(udpConn, 0)
+	go tun.forwardStreamToUdp(stream)
+	defer tun.Stop()
+
+	server.SetDeadline(time.Now().Add(5 * time.Second))
+	peer.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// local datagrams -> stream
+	for _, datagram := range [][]byte{[]byte("handshake"), bytes.Repeat([]byte{0xAB}, 1420)} {
+		if _, err := peer.Write(datagram); err != nil {
+			t.Fatal(err)
+		}
+		hdr := make([]byte, 2)
+		if _, err := io.ReadFull(server, hdr); err != nil {
+			t.Fatal(err)
+		}
+		if n := int(binary.BigEndian.Uint16(hdr)); n != len(datagram) {
+			t.Fatalf("length prefix = %d, want %d", n, len(datagram))
+		}
+		data := make([]byte, len(datagram))
+		if _, err := io.ReadFull(server, data); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, datagram) {
+			t.Fatalf("stream data differs from the datagram")
+		}
+	}
+
+	// stream -> local peer: several frames in one write are split into separate datagrams
+	responses := [][]byte{[]byte("response-1"), {}, bytes.Repeat([]byte{0xCD}, 1200)}
+	var frames []byte
+	for _, r := range responses {
+		frames = binary.BigEndian.AppendUint16(frames, uint16(len(r)))
+		frames = append(frames, r...)
+	}
+	go server.Write(frames)
+
+	buf := make([]byte, maxDatagramSize)
+	for _, want := range responses {
+		n, err := peer.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf[:n], want) {
+			t.Fatalf("datagram = %d bytes, want %d bytes", n, len(want))
+		}
+	}
+}
@@ -39,6 +39,7 @@ func (p *Protocol) createSettingsResponse() *types.SettingsResp {
 	return &types.SettingsResp{
 		IsAutoconnectOnLaunch:          prefs.IsAutoconnectOnLaunch,
 		IsAutoconnectOnLaunchDaemon:    prefs.IsAutoconnectOnLaunchDaemon,
+		AutoconnectProfile:             prefs.AutoconnectProfile,
 		UserDefinedOvpnFile:            platform.OpenvpnUserParamsFile(),
 		UserPrefs:                      prefs.UserPrefs,
 		WiFi:                           prefs.WiFiControl,
@@ -105,14 +106,25 @@ func (p *Protocol) createConnectedResponse(state vpn.StateInfo) *types.Connected
 		VpnType:         state.VpnType,
 		ExitHostname:    state.ExitHostname,
 		// Dns:             types.DnsStatus{Dns: manualDns, AntiTrackerStatus: p._service.GetAntiTrackerStatus()}, // TODO: Vlad - disabled AntiTracker functionality for now
-		Dns:        types.DnsStatus{Dns: manualDns, DnsMgmtStyleInUse: dns.DnsMgmtStyleInUse()},
-		IsTCP:      state.IsTCP,
-		Mtu:        state.Mtu,
-		V2RayProxy: state.V2RayProxy,
-		Obfsproxy:  state.Obfsproxy,
-		IsPaused:   p._service.IsPaused(),
-		PausedTill: pausedTillStr,
+		Dns:          types.DnsStatus{Dns: manualDns, DnsMgmtStyleInUse: dns.DnsMgmtStyleInUse()},
+		IsTCP:        state.IsTCP,
+		Mtu:          state.Mtu,
+		PathMtu:      p._service.DiscoveredMtu(),
+		V2RayProxy:   state.V2RayProxy,
+		Obfsproxy:    state.Obfsproxy,
+		OpenVpn:      p._service.OpenVpnDetails(),
+		CustomServer: p._service.ConnectedCustomServer(),
+		IsPaused:     p._service.IsPaused(),
+		PausedTill:   pausedTillStr,
 	}
 
 	return ret
 }
+
+// unixTimeOrZero converts Unix time to time.Time (0 -> zero time.Time)
+func unixTimeOrZero(unixTime int64) time.Time {
+	if unixTime <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(unixTime, 0)
+}
@@ -0,0 +1,120 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/history"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+// historyConnectionState keeps info about the current connection, required for the connection events history
+type historyConnectionState struct {
+	mutex          sync.Mutex
+	lastState      vpn.State
+	isStateSaved   bool
+	connectedSince time.Time
+	server         string
+}
+
+func stateServerName(state vpn.StateInfo) string {
+	if state.ExitHostname != "" {
+		return state.ExitHostname
+	}
+	return ipToString(state.ServerIP)
+}
+
+// historySaveVpnState saves VPN state transition into the connection events history
+func (p *Protocol) historySaveVpnState(state vpn.StateInfo) {
+	h := &p._historyState
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.isStateSaved && h.lastState == state.State {
+		return // state not changed
+	}
+	h.lastState = state.State
+	h.isStateSaved = true
+
+	if state.State == vpn.DISCONNECTED {
+		return // saved by historySaveDisconnected() (it contains the disconnection reason)
+	}
+
+	serverPort := ""
+	if state.ServerPort > 0 {
+		serverPort = strconv.Itoa(state.ServerPort)
+	}
+
+	if state.State == vpn.CONNECTED {
+		h.connectedSince = time.Now()
+		h.server = stateServerName(state)
+	}
+
+	history.Add(history.EventVpnState, state.StateAdditionalInfo,
+		"state", state.State.String(),
+		"vpn_type", state.VpnType.String(),
+		"server", stateServerName(state),
+		"server_port", serverPort,
+		"client_ip", ipToString(state.ClientIP))
+}
+
+// historySaveDisconnected saves 'disconnected' event (with connection duration and disconnection reason) into the connection events history
+func (p *Protocol) historySaveDisconnected(reason types.DisconnectionReason, errMsg string) {
+	h := &p._historyState
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	reasonStr := "unknown"
+	switch {
+	case reason == types.DisconnectRequested:
+		reasonStr = "requested"
+	case reason == types.AuthenticationError:
+		reasonStr = "authentication_error"
+	case errMsg != "":
+		reasonStr = "error"
+	}
+
+	duration := ""
+	if !h.connectedSince.IsZero() {
+		duration = time.Since(h.connectedSince).Round(time.Second).String()
+	}
+
+	history.Add(history.EventDisconnected, errMsg,
+		"reason", reasonStr,
+		"server", h.server,
+		"duration", duration)
+
+	h.lastState = vpn.DISCONNECTED
+	h.isStateSaved = true
+	h.connectedSince = time.Time{}
+	h.server = ""
+}
+
+func ipToString(ip net.IP) string {
+	if len(ip) == 0 {
+		return ""
+	}
+	return ip.String()
+}
@@ -0,0 +1,101 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"runtime"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+)
+
+// keys of the headless configuration file which are changed by the requests
+// (the connection parameters are not listed here: the service overrides them by the managed values)
+var managedConfigKeysByCommand = map[string][]string{
+	"KillSwitchSetIsPersistent":      {"firewall.persistent"},
+	"KillSwitchSetAllowLAN":          {"firewall.allow_lan"},
+	"KillSwitchSetAllowLANMulticast": {"firewall.allow_lan_multicast"},
+	"KillSwitchSetAllowApiServers":   {"firewall.allow_api_servers"},
+	"KillSwitchSetUserExceptions":    {"firewall.user_exceptions"},
+	"SplitTunnelSetConfig":           {"total_shield.enabled", "total_shield.app_whitelist"},
+	"SplitTunnelDomainAdd":           {"total_shield.domains"},
+	"SplitTunnelDomainRemove":        {"total_shield.domains"},
+	"SplitTunnelSubnetsSet":          {"total_shield.include_subnets", "total_shield.exclude_subnets"},
+	"WiFiSettings":                   {"wifi"},
+	"SetAlternateDns":                {"dns"},
+	"NetworkRulesSet":                {"network_rules"},
+	"ScheduleRulesSet":               {"schedule"},
+	"ConnectionQualitySet":           {"connection_quality"},
+}
+
+var managedConfigKeysByPreference = map[types.ServicePreference]string{
+	types.Prefs_IsAutoconnectOnLaunch:        "autoconnect.on_launch",
+	types.Prefs_IsAutoconnectOnLaunch_Daemon: "autoconnect.on_launch_daemon",
+	types.Prefs_AutoconnectProfile:           "autoconnect.profile",
+	types.Prefs_IsUnixSocketDisabled:         "unix_socket.disabled",
+	types.Prefs_UnixSocketAllowedUIDs:        "unix_socket.allowed_uids",
+	types.Prefs_UnixSocketAllowedGIDs:        "unix_socket.allowed_gids",
+}
+
+// managedConfigKeys returns the keys of the headless configuration file which are changed by the request
+func managedConfigKeys(reqCmd types.RequestBase, messageData []byte) []string {
+	switch reqCmd.Command {
+	case "SetPreference":
+		var req types.SetPreference
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			return nil
+		}
+		if key, ok := managedConfigKeysByPreference[types.ServicePreference(req.Key)]; ok {
+			return []string{key}
+		}
+		return nil
+	case "SplitTunnelAddApp", "SplitTunnelRemoveApp":
+		if runtime.GOOS == "windows" {
+			return []string{"total_shield.apps"} // only on Windows the applications list is kept in preferences
+		}
+		return nil
+	}
+	return managedConfigKeysByCommand[reqCmd.Command]
+}
+
+// checkManagedConfigLock ensures the request does not change the settings managed by the locked headless configuration file.
+// If the request is not allowed - sends an error response to the client and returns 'false'.
+func (p *Protocol) checkManagedConfigLock(conn net.Conn, reqCmd types.RequestBase, messageData []byte) bool {
+	keys := managedConfigKeys(reqCmd, messageData)
+	if len(keys) == 0 {
+		return true
+	}
+
+	lockedKey := p._service.ManagedConfigLockedKey(keys...)
+	if lockedKey == "" {
+		return true
+	}
+
+	errorResp := types.ErrorResp{
+		ErrorType:    types.ErrorManagedSettingLocked,
+		ErrorTitle:   "Setting is managed",
+		ErrorMessage: fmt.Sprintf("The setting '%s' is managed by the configuration file and can not be changed", lockedKey)}
+
+	log.Warning(fmt.Sprintf("      [%d] %sRequest '%s' rejected: setting '%s' is managed by the configuration file", reqCmd.Idx, p.connLogID(conn), reqCmd.Command, lockedKey))
+	p.sendResponse(conn, &errorResp, reqCmd.Idx)
+	return false
+}
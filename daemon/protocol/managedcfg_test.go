@@ -0,0 +1,63 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+)
+
+func TestManagedConfigKeys(t *testing.T) {
+	setPreference := func(key types.ServicePreference, value string) []byte {
+		data, err := json.Marshal(types.SetPreference{Key: string(key), Value: value})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name string
+		cmd  string
+		data []byte
+		want []string
+	}{
+		{"unix socket disabled", "SetPreference", setPreference(types.Prefs_IsUnixSocketDisabled, "true"), []string{"unix_socket.disabled"}},
+		{"unix socket uids", "SetPreference", setPreference(types.Prefs_UnixSocketAllowedUIDs, "1000,1001"), []string{"unix_socket.allowed_uids"}},
+		{"unix socket gids", "SetPreference", setPreference(types.Prefs_UnixSocketAllowedGIDs, "100"), []string{"unix_socket.allowed_gids"}},
+		{"autoconnect on launch", "SetPreference", setPreference(types.Prefs_IsAutoconnectOnLaunch, "true"), []string{"autoconnect.on_launch"}},
+		{"not managed preference", "SetPreference", setPreference(types.Prefs_IsEnableLogging, "true"), nil},
+		{"malformed preference request", "SetPreference", []byte("{"), nil},
+		{"firewall request", "KillSwitchSetAllowLAN", []byte("{}"), []string{"firewall.allow_lan"}},
+		{"not managed request", "Hello", []byte("{}"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := managedConfigKeys(types.RequestBase{CommandBase: types.CommandBase{Command: tt.cmd}}, tt.data)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("managedConfigKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/roles"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+)
+
+// authenticateHello checks the authentication data of the 'Hello' request and returns the role of the client.
+//   - client with a valid access token: the role of the token
+//   - client with a correct daemon secret (or authorized by Unix socket peer credentials): RoleAdmin
+func (p *Protocol) authenticateHello(conn net.Conn, hello types.Hello) (roles.Role, error) {
+	if len(hello.AccessToken) > 0 {
+		tokenInfo, ok := p._accessTokens.CheckToken(hello.AccessToken)
+		if !ok {
+			return "", fmt.Errorf("access token verification error")
+		}
+		log.Info(fmt.Sprintf("%sAuthenticated by access token '%s' (role: %s)", p.connLogID(conn), tokenInfo.Name, tokenInfo.Role))
+		return tokenInfo.Role, nil
+	}
+
+	if err := checkPeerAccessDenied(conn); err != nil {
+		return "", err
+	}
+	if hello.Secret != p._secret && !isPeerAuthorizedConn(conn) {
+		return "", fmt.Errorf("secret verification error")
+	}
+	return roles.RoleAdmin, nil
+}
+
+// clientRole returns the role of the connected client
+func (p *Protocol) clientRole(c net.Conn) roles.Role {
+	p._connectionsMutex.RLock()
+	defer p._connectionsMutex.RUnlock()
+
+	if ci, ok := p._connections[c]; ok {
+		return ci.Role
+	}
+	return ""
+}
+
+// redactForRole returns the response to be sent to a client with the role.
+// The account session token and the device information are visible only for 'admin' clients
+// (otherwise a restricted client is able to use the token to act on behalf of the account).
+func redactForRole(role roles.Role, cmd ICommandBase) ICommandBase {
+	if role == roles.RoleAdmin {
+		return cmd
+	}
+
+	switch r := cmd.(type) {
+	case *types.HelloResp:
+		ret := *r
+		ret.Session = r.Session.Redacted()
+		return &ret
+	case *types.SessionStatusResp:
+		ret := *r
+		if len(ret.SessionToken) > 0 {
+			ret.SessionToken = types.RedactedSecret
+		}
+		ret.DeviceName = ""
+		return &ret
+	}
+	return cmd
+}
+
+// checkCommandPermission sends an error response (and returns 'false') when the command is not allowed for the client role
+func (p *Protocol) checkCommandPermission(conn net.Conn, reqCmd types.RequestBase) bool {
+	role := p.clientRole(conn)
+	if role.IsCommandAllowed(reqCmd.Command) {
+		return true
+	}
+
+	errorResp := types.ErrorResp{
+		ErrorType:    types.ErrorPermissionDenied,
+		ErrorTitle:   "Permission denied",
+		ErrorMessage: fmt.Sprintf("The command '%s' is not allowed for the client role '%s'", reqCmd.Command, role)}
+
+	log.Warning(fmt.Sprintf("      [%d] %sPermission denied for '%s' (role: '%s')", reqCmd.Idx, p.connLogID(conn), reqCmd.Command, role))
+	p.sendResponse(conn, &errorResp, reqCmd.Idx)
+	return false
+}
@@ -0,0 +1,82 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/roles"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+)
+
+func TestRedactForRole(t *testing.T) {
+	session := types.SessionResp{
+		AccountID:          "a-1234",
+		Session:            "session-token",
+		DeviceName:         "my-device",
+		WgPublicKey:        "public-key",
+		WgLocalIP:          "10.0.0.2",
+		WgKeyGenerated:     1700000000,
+		WgKeysRegenInerval: 86400,
+		WgUsePresharedKey:  true,
+	}
+	hello := &types.HelloResp{Version: "1.0", Session: session, ClientRole: roles.RoleObserver}
+	status := &types.SessionStatusResp{APIStatus: 200, SessionToken: "session-token", DeviceName: "my-device"}
+	other := &types.EmptyResp{}
+
+	redactedHello := &types.HelloResp{Version: "1.0", Session: types.SessionResp{AccountID: "a-1234", Session: types.RedactedSecret}, ClientRole: roles.RoleObserver}
+	redactedStatus := &types.SessionStatusResp{APIStatus: 200, SessionToken: types.RedactedSecret}
+
+	tests := []struct {
+		name string
+		role roles.Role
+		cmd  ICommandBase
+		want ICommandBase
+	}{
+		{"admin hello", roles.RoleAdmin, hello, hello},
+		{"admin session status", roles.RoleAdmin, status, status},
+		{"observer hello", roles.RoleObserver, hello, redactedHello},
+		{"operator hello", roles.RoleOperator, hello, redactedHello},
+		{"observer session status", roles.RoleObserver, status, redactedStatus},
+		{"operator session status", roles.RoleOperator, status, redactedStatus},
+		{"unknown role hello", "", hello, redactedHello},
+		{"logged out hello", roles.RoleObserver, &types.HelloResp{}, &types.HelloResp{}},
+		{"logged out session status", roles.RoleObserver, &types.SessionStatusResp{APIStatus: 401}, &types.SessionStatusResp{APIStatus: 401}},
+		{"other response", roles.RoleObserver, other, other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactForRole(tt.role, tt.cmd); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redactForRole() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// the original responses must not be modified
+	if hello.Session != session || status.SessionToken != "session-token" || status.DeviceName != "my-device" {
+		t.Errorf("redactForRole() modified the original response")
+	}
+	// admin clients receive the original object
+	if redactForRole(roles.RoleAdmin, hello) != ICommandBase(hello) {
+		t.Errorf("redactForRole() must not copy the response for admin clients")
+	}
+}
@@ -37,17 +37,23 @@ import (
 	api_types "github.com/swapnilsparsh/devsVPN/daemon/api/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/helpers"
 	"github.com/swapnilsparsh/devsVPN/daemon/logger"
+	"github.com/swapnilsparsh/devsVPN/daemon/netinfo"
 	"github.com/swapnilsparsh/devsVPN/daemon/oshelpers"
 	"github.com/swapnilsparsh/devsVPN/daemon/protocol/eaa"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/roles"
 	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/rageshake"
 
 	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
 	firewall_types "github.com/swapnilsparsh/devsVPN/daemon/service/firewall/types"
 
+	"github.com/swapnilsparsh/devsVPN/daemon/service/history"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/managedcfg"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/metrics"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/platform"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
 	service_types "github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/splittun"
 	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
 	"github.com/swapnilsparsh/devsVPN/daemon/wifiNotifier"
 )
@@ -150,6 +156,11 @@ type Service interface {
 	SplitTunnelling_AddApp(exec string) (cmdToExecute string, isAlreadyRunning bool, err error)
 	SplitTunnelling_RemoveApp(pid int, exec string) (err error)
 	SplitTunnelling_AddedPidInfo(pid int, exec string, cmdToExecute string) error
+	SplitTunnelling_GetDomains() (rules []splittun.DomainStatus, funcNotAvailableErr error)
+	SplitTunnelling_AddDomain(domain string, bypass bool) error
+	SplitTunnelling_RemoveDomain(domain string) error
+	SplitTunnelling_GetSubnets() (include, exclude []string, funcNotAvailableErr error)
+	SplitTunnelling_SetSubnets(include, exclude []string) error
 
 	GetInstalledApps(extraArgsJSON string) ([]oshelpers.AppInfo, error)
 	GetBinaryIcon(binaryPath string) (string, error)
@@ -159,6 +170,42 @@ type Service interface {
 	SetUserPreferences(userPrefs preferences.UserPreferences) (err error)
 	ResetPreferences() error
 
+	// settings export/import (portable settings bundle)
+	SettingsExport() preferences.SettingsBundle
+	SettingsImport(bundle preferences.SettingsBundle, dryRun bool) (changes []string, err error)
+
+	ConnectionProfiles() []preferences.ConnectionProfile
+	ConnectionProfileSave(name string, params service_types.ConnectionParams, fromLastConnection, overwrite bool) error
+	ConnectionProfileRename(name, newName string) error
+	ConnectionProfileDelete(name string) error
+	ConnectionProfileParams(name string) (service_types.ConnectionParams, error)
+
+	NetworkRules() []preferences.NetworkRule
+	SetNetworkRules(rules []preferences.NetworkRule) error
+	NetworkRulesStatus() (network netinfo.DefaultNetworkInfo, matchedRule string, err error)
+
+	ScheduleRules() []preferences.ScheduleRule
+	SetScheduleRules(rules []preferences.ScheduleRule) error
+	ScheduleStatus() (next *preferences.ScheduleTransition, activeRules []string)
+
+	ConnectionQualityParams() preferences.ConnectionQualityParams
+	SetConnectionQualityParams(params preferences.ConnectionQualityParams) error
+	ConnectionQualityStatus() service_types.ConnectionQualityStatus
+	DiscoveredMtu() int
+
+	OpenVpnDetails() *vpn.OpenVpnDetails
+	OpenVpnLogVerbosity() int
+	SetOpenVpnLogVerbosity(verbosity int) error
+
+	CustomServers() []service_types.CustomServerInfo
+	CustomServerImport(name, config, username, password string) (service_types.CustomServerInfo, error)
+	CustomServerDelete(idOrName string) error
+	ConnectedCustomServer() string
+
+	// headless daemon configuration file
+	ManagedConfigApply(dryRun bool) (status managedcfg.Status, err error)
+	ManagedConfigLockedKey(keys ...string) string
+
 	// SetManualDNS update default DNS parameters AND apply new DNS value for current VPN connection
 	// If 'antiTracker' is enabled - the 'dnsCfg' will be ignored
 	SetManualDNS(dns dns.DnsSettings, antiTracker service_types.AntiTrackerMetadata) (changedDns dns.DnsSettings, retErr error)
@@ -235,6 +282,7 @@ type Service interface {
 	GetWiFiAvailableNetworks() ([]string, error)
 
 	GetDiagnosticLogs() (logActive string, logPrevSession string, extraInfo *rageshake.SystemInfo, err error)
+	GetDiagnosticLogsTimeWindow(from, to time.Time) (string, error)
 	//GenerateCrashReport(crashType string, additionalData map[string]interface{}) (*rageshake.CrashReport, error)
 	SubmitRageshakeReport(crashType, app, version, text string, filesToAttach []string, jsonFilesToAttach []helpers.JsonFileToAttach, additionalData map[string]string) (resp *api_types.RageshakeServerResponse, httpStatusCode int, err error)
 	SubmitRageshakeReportInternal(errMsg string) (resp *api_types.RageshakeServerResponse, httpStatusCode int, err error)
@@ -246,9 +294,17 @@ type Service interface {
 // Callback functions for Wireguard to report rx/tx statistics and handshake timestamps to UI client
 type OnTransferDataCallback func(string, string)
 type OnHandshakeCallback func(string)
+
+// Callback functions to report raw statistics values (e.g. to the metrics endpoint); optional
+type OnTransferBytesCallback func(sent, received uint64)
+type OnHandshakeTimeCallback func(time.Time)
+
 type StatsCallbacks struct {
 	OnTransferDataCallback OnTransferDataCallback
 	OnHandshakeCallback    OnHandshakeCallback
+
+	OnTransferBytesCallback OnTransferBytesCallback
+	OnHandshakeTimeCallback OnHandshakeTimeCallback
 }
 
 // CreateProtocol - Create new protocol object
@@ -256,6 +312,7 @@ func CreateProtocol() (*Protocol, error) {
 	return &Protocol{
 		_connections:     make(map[net.Conn]connectionInfo),
 		_eaa:             eaa.Init(platform.ParanoidModeSecretFile()),
+		_accessTokens:    roles.Init(platform.AccessTokensFile()),
 		_connRequestChan: make(chan service_types.ConnectionParams, 1),
 	}, nil
 }
@@ -263,6 +320,7 @@ func CreateProtocol() (*Protocol, error) {
 type connectionInfo struct {
 	Type            types.ClientTypeEnum // UI or CLI
 	IsAuthenticated bool                 // true when connection fully authenticated (secret is OK and EAA check is passed)
+	Role            roles.Role           // defines the commands allowed for the client
 }
 
 // Protocol - TCP interface to communicate with PL Connect application
@@ -271,6 +329,8 @@ type Protocol struct {
 
 	// connections listener
 	_connListener *net.TCPListener
+	// Unix-domain socket listener (nil if not supported or disabled); protected by '_connectionsMutex'
+	_unixListener *net.UnixListener
 
 	_connectionsMutex sync.RWMutex
 	_connections      map[net.Conn]connectionInfo
@@ -287,8 +347,14 @@ type Protocol struct {
 	// keep info about last VPN state
 	_lastVPNState vpn.StateInfo
 
+	// info about current connection (for the connection events history)
+	_historyState historyConnectionState
+
 	_eaa *eaa.Eaa
 
+	// per-client access tokens (role-based permissions)
+	_accessTokens *roles.AccessTokens
+
 	_isRunning bool // 'false' when not running OR after Stop() command call
 
 	// Send this error info to a first connected client
@@ -312,6 +378,12 @@ func (p *Protocol) Stop() {
 		p._isRunning = false
 		// do not accept new incoming connections
 		listener.Close()
+		p._connectionsMutex.RLock()
+		unixListener := p._unixListener
+		p._connectionsMutex.RUnlock()
+		if unixListener != nil {
+			unixListener.Close()
+		}
 
 		// Do not use any send\receive communications with connected clients after listener stopped
 	}
@@ -376,6 +448,12 @@ func (p *Protocol) Start(secret uint64, startedOnPort chan<- int, service Servic
 	//  See also "RegisterConnectionRequest()" for details)
 	go p.processConnectionRequests()
 
+	// Start listening on Unix-domain socket (if applicable).
+	// Clients are able to use TCP connection anyway, so it is not a fatal error.
+	if err := p.startUnixSocketListener(); err != nil {
+		log.Error(err)
+	}
+
 	// infinite loop of processing privateLINE client connection
 	for {
 		conn, err := listener.Accept()
@@ -448,6 +526,7 @@ func (p *Protocol) processClient(conn net.Conn) {
 		}
 
 		// CONNECTION AUTHENTICATION: First request should be 'Hello' with correct authentication secret
+		// (the secret is not required for Unix socket connections: they are authorized by peer credentials)
 		if !isAuthenticated {
 			messageData := []byte(message)
 
@@ -467,15 +546,16 @@ func (p *Protocol) processClient(conn net.Conn) {
 				p.sendErrorResponse(conn, cmd, fmt.Errorf("connection authentication error: %w", err))
 				return
 			}
-			if hello.Secret != p._secret {
-				log.Warning(fmt.Errorf("refusing connection: secret verification error"))
-				p.sendErrorResponse(conn, cmd, fmt.Errorf("secret verification error"))
+			role, err := p.authenticateHello(conn, hello)
+			if err != nil {
+				log.Warning(fmt.Errorf("refusing connection: %w", err))
+				p.sendErrorResponse(conn, cmd, err)
 				return
 			}
 
 			// AUTHENTICATED
 			isAuthenticated = true
-			p.clientConnected(conn, hello.ClientType) //0-ui 1-cli
+			p.clientConnected(conn, hello.ClientType, role) //0-ui 1-cli
 		}
 
 		// Processing requests from client (in separate routine)
@@ -559,6 +639,15 @@ func (p *Protocol) processRequest(conn net.Conn, message string) {
 		}
 	}
 
+	// Role-based permissions: ensure the command is allowed for the client
+	if !p.checkCommandPermission(conn, reqCmd) {
+		return
+	}
+	// Settings managed by the locked configuration file can not be changed by clients
+	if !p.checkManagedConfigLock(conn, reqCmd, messageData) {
+		return
+	}
+
 	if !p._eaa.IsEnabled() {
 		// EAA is disabled. So, mark connection as authenticated
 		p.clientSetAuthenticated(conn)
@@ -612,7 +701,9 @@ func (p *Protocol) processRequest(conn net.Conn, message string) {
 
 		// send back Hello message with account session info
 		helloResponse := p.createHelloResponse()
-		p.sendResponse(conn, helloResponse, req.Idx)
+		helloResponseToRequestor := *helloResponse
+		helloResponseToRequestor.ClientRole = p.clientRole(conn)
+		p.sendResponse(conn, &helloResponseToRequestor, req.Idx)
 		if req.SendResponseToAllClients {
 			p.notifyClients(helloResponse)
 		}
@@ -660,6 +751,220 @@ func (p *Protocol) processRequest(conn net.Conn, message string) {
 			p.sendResponse(conn, &types.EmptyResp{}, req.Idx)
 		}
 
+	case "AccessTokenCreate":
+		var req types.AccessTokenCreate
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+
+		tokenInfo, token, err := p._accessTokens.Create(req.TokenName, req.Role)
+		if err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		log.Info(fmt.Sprintf("%sAccess token '%s' created (role: %s)", p.connLogID(conn), tokenInfo.Name, tokenInfo.Role))
+		p.sendResponse(conn, &types.AccessTokenCreatedResp{Info: tokenInfo, Token: token}, req.Idx)
+
+	case "AccessTokenDelete":
+		var req types.AccessTokenDelete
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+
+		if err := p._accessTokens.Delete(req.TokenName); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		log.Info(fmt.Sprintf("%sAccess token '%s' removed", p.connLogID(conn), req.TokenName))
+		p.sendResponse(conn, &types.EmptyResp{}, req.Idx)
+
+	case "AccessTokenList":
+		tokens, err := p._accessTokens.List()
+		if err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendResponse(conn, &types.AccessTokenListResp{Tokens: tokens}, reqCmd.Idx)
+
+	case "SettingsExport":
+		p.sendResponse(conn, &types.SettingsExportResp{Bundle: p._service.SettingsExport()}, reqCmd.Idx)
+
+	case "SettingsImport":
+		var req types.SettingsImport
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+
+		changes, err := p._service.SettingsImport(req.Bundle, req.DryRun)
+		if err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendResponse(conn, &types.SettingsImportResp{Changes: changes}, req.Idx)
+
+	case "ConnectionProfilesGet":
+		p.sendConnectionProfiles(conn, reqCmd.Idx)
+
+	case "ConnectionProfileSave":
+		var req types.ConnectionProfileSave
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		if err := p._service.ConnectionProfileSave(req.ProfileName, req.Params, req.FromLastConnection, req.Overwrite); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendConnectionProfiles(conn, reqCmd.Idx) // other clients are notified by the service (OnPreferencesChanged)
+
+	case "ConnectionProfileRename":
+		var req types.ConnectionProfileRename
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		if err := p._service.ConnectionProfileRename(req.ProfileName, req.NewProfileName); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendConnectionProfiles(conn, reqCmd.Idx) // other clients are notified by the service (OnPreferencesChanged)
+
+	case "ConnectionProfileDelete":
+		var req types.ConnectionProfileDelete
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		if err := p._service.ConnectionProfileDelete(req.ProfileName); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendConnectionProfiles(conn, reqCmd.Idx) // other clients are notified by the service (OnPreferencesChanged)
+
+	case "NetworkRulesGet":
+		p.sendNetworkRules(conn, reqCmd.Idx)
+
+	case "NetworkRulesSet":
+		var req types.NetworkRulesSet
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		if err := p._service.SetNetworkRules(req.Rules); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendNetworkRules(conn, reqCmd.Idx)
+
+	case "ScheduleRulesGet":
+		p.sendScheduleRules(conn, reqCmd.Idx)
+
+	case "ScheduleRulesSet":
+		var req types.ScheduleRulesSet
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		if err := p._service.SetScheduleRules(req.Rules); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendScheduleRules(conn, reqCmd.Idx)
+
+	case "ConnectionQualityGet":
+		p.sendConnectionQuality(conn, reqCmd.Idx)
+
+	case "ConnectionQualitySet":
+		var req types.ConnectionQualitySet
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		if err := p._service.SetConnectionQualityParams(req.Params); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendConnectionQuality(conn, reqCmd.Idx)
+
+	case "OpenVpnDetailsGet":
+		p.sendOpenVpnDetails(conn, reqCmd.Idx)
+
+	case "OpenVpnLogVerbositySet":
+		var req types.OpenVpnLogVerbositySet
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		if err := p._service.SetOpenVpnLogVerbosity(req.Verbosity); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendOpenVpnDetails(conn, reqCmd.Idx)
+
+	case "CustomServersGet":
+		p.sendCustomServers(conn, reqCmd.Idx, nil)
+
+	case "CustomServerImport":
+		var req types.CustomServerImport
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		info, err := p._service.CustomServerImport(req.ServerName, req.Config, req.Username, req.Password)
+		if err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendCustomServers(conn, reqCmd.Idx, &info)
+
+	case "CustomServerDelete":
+		var req types.CustomServerDelete
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		if err := p._service.CustomServerDelete(req.ID); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendCustomServers(conn, reqCmd.Idx, nil)
+
+	case "ManagedConfigApply":
+		var req types.ManagedConfigApply
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+
+		status, err := p._service.ManagedConfigApply(req.DryRun)
+		if err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendResponse(conn, &types.ManagedConfigResp{Status: status}, req.Idx)
+
+	case "HistoryGet":
+		var req types.HistoryGet
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+
+		eventTypes, err := history.ParseEventTypes(strings.Join(req.Types, ","))
+		if err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		events, err := history.Query(unixTimeOrZero(req.From), unixTimeOrZero(req.To), eventTypes, req.MaxCount)
+		if err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendResponse(conn, &types.HistoryResp{Events: events}, req.Idx)
+
 	case "GetVPNState":
 		// send VPN connection  state
 		sendState(reqCmd.Idx, false)
@@ -1023,10 +1328,78 @@ func (p *Protocol) processRequest(conn net.Conn, message string) {
 		}
 		p.sendResponse(conn, &types.EmptyResp{}, reqCmd.Idx)
 
+	case "SplitTunnelDomainsGet":
+		p.sendSplitTunnelDomains(conn, reqCmd.Idx)
+
+	case "SplitTunnelDomainAdd":
+		var req types.SplitTunnelDomainAdd
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		if err := p._service.SplitTunnelling_AddDomain(req.Domain, req.Bypass); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendSplitTunnelDomains(conn, reqCmd.Idx)
+		// all clients will be notified about configuration change by service in OnSplitTunnelStatusChanged() handler
+
+	case "SplitTunnelDomainRemove":
+		var req types.SplitTunnelDomainRemove
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		if err := p._service.SplitTunnelling_RemoveDomain(req.Domain); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendSplitTunnelDomains(conn, reqCmd.Idx)
+		// all clients will be notified about configuration change by service in OnSplitTunnelStatusChanged() handler
+
+	case "SplitTunnelSubnetsGet":
+		p.sendSplitTunnelSubnets(conn, reqCmd.Idx)
+
+	case "SplitTunnelSubnetsSet":
+		var req types.SplitTunnelSubnetsSet
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		if err := p._service.SplitTunnelling_SetSubnets(req.Include, req.Exclude); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+		p.sendSplitTunnelSubnets(conn, reqCmd.Idx)
+		// all clients will be notified about configuration change by service in OnSplitTunnelStatusChanged() handler
+
 	case "GenerateDiagnostics":
+		var req types.GenerateDiagnostics
+		if err := json.Unmarshal(messageData, &req); err != nil {
+			p.sendErrorResponse(conn, reqCmd, err)
+			break
+		}
+
+		logFilter := logger.Filter{Components: req.LogComponents}
+		if len(req.LogMinLevel) > 0 {
+			if logFilter.MinLevel, err = logger.ParseLevel(req.LogMinLevel); err != nil {
+				p.sendErrorResponse(conn, reqCmd, err)
+				break
+			}
+		}
+
 		if log, log0, extraInfo, err := p._service.GetDiagnosticLogs(); err != nil {
 			p.sendErrorResponse(conn, reqCmd, err)
 		} else {
+			if req.LogFrom > 0 || req.LogTo > 0 {
+				if log, err = p._service.GetDiagnosticLogsTimeWindow(unixTimeOrZero(req.LogFrom), unixTimeOrZero(req.LogTo)); err != nil {
+					p.sendErrorResponse(conn, reqCmd, err)
+					break
+				}
+				log0 = ""
+			}
+			log = logger.FilterLogText(log, logFilter)
+			log0 = logger.FilterLogText(log0, logFilter)
 			p.sendResponse(conn, &types.DiagnosticsGeneratedResp{Log1_Active: log, Log0_Old: log0, ExtraInfo: *extraInfo}, reqCmd.Idx)
 		}
 
@@ -1043,6 +1416,13 @@ func (p *Protocol) processRequest(conn net.Conn, message string) {
 		if req.ClientSystemInfoJson != "" {
 			jsonFilesToAttach = append(jsonFilesToAttach, helpers.JsonFileToAttach{JsonFileName: req.App + ".sysinfo.json", JsonFileContents: []byte(req.ClientSystemInfoJson)})
 		}
+		if req.LogFrom > 0 || req.LogTo > 0 {
+			if logWindow, err := p._service.GetDiagnosticLogsTimeWindow(unixTimeOrZero(req.LogFrom), unixTimeOrZero(req.LogTo)); err != nil {
+				log.Warning(fmt.Errorf("failed to get logs for the time window: %w", err))
+			} else {
+				jsonFilesToAttach = append(jsonFilesToAttach, helpers.JsonFileToAttach{JsonFileName: helpers.ServiceName + ".window.log", JsonFileContents: []byte(logWindow)})
+			}
+		}
 
 		resp, statusCode, err := p._service.SubmitRageshakeReport(
 			string(req.CrashType),
@@ -1605,15 +1985,25 @@ func (p *Protocol) processRequest(conn net.Conn, message string) {
 			return
 		}
 
-		// TODO: FIXME: Vlad - unconditionally using the Wireguard entry server info saved in preferences (if we have one), not the passed parameter
-		prefs := p._service.Preferences()
-		if len(prefs.LastConnectionParams.WireGuardParameters.EntryVpnServer.Hosts) <= 0 {
-			p.sendErrorResponse(conn, reqCmd, fmt.Errorf("error - this device was not yet registered with the privateLINE server, please login first"))
-			return
+		if len(connectRequest.Profile) > 0 {
+			// the profile parameters are bound to the registered server; the port and DNS of the profile are kept
+			params, err := p._service.ConnectionProfileParams(connectRequest.Profile)
+			if err != nil {
+				p.sendErrorResponse(conn, reqCmd, err)
+				return
+			}
+			connectRequest.Params = params
+		} else {
+			// TODO: FIXME: Vlad - unconditionally using the Wireguard entry server info saved in preferences (if we have one), not the passed parameter
+			prefs := p._service.Preferences()
+			if len(prefs.LastConnectionParams.WireGuardParameters.EntryVpnServer.Hosts) <= 0 && !connectRequest.Params.IsCustomServer() {
+				p.sendErrorResponse(conn, reqCmd, fmt.Errorf("error - this device was not yet registered with the privateLINE server, please login first"))
+				return
+			}
+			connectRequest.Params.WireGuardParameters.EntryVpnServer = prefs.LastConnectionParams.WireGuardParameters.EntryVpnServer
+			connectRequest.Params.WireGuardParameters.Port.Port = prefs.LastConnectionParams.WireGuardParameters.Port.Port
+			connectRequest.Params.ManualDNS = prefs.LastConnectionParams.ManualDNS
 		}
-		connectRequest.Params.WireGuardParameters.EntryVpnServer = prefs.LastConnectionParams.WireGuardParameters.EntryVpnServer
-		connectRequest.Params.WireGuardParameters.Port.Port = prefs.LastConnectionParams.WireGuardParameters.Port.Port
-		connectRequest.Params.ManualDNS = prefs.LastConnectionParams.ManualDNS
 
 		// Save last received connection request. It will be processed in separate routine 'processConnectionRequests()' which is already running
 		p.RegisterConnectionRequest(connectRequest.Params)
@@ -1709,6 +2099,7 @@ func (p *Protocol) processConnectionRequests() {
 				if len(p._connRequestChan) == 0 || p._disconnectRequested {
 					lastState := p._lastVPNState
 					p._lastVPNState = vpn.NewStateInfo(vpn.DISCONNECTED, "")
+					metrics.SetVpnState(vpn.DISCONNECTED)
 
 					// Sending "Disconnected" only in one place (after VPN process stopped)
 					disconnectionReason := types.Unknown
@@ -1728,6 +2119,7 @@ func (p *Protocol) processConnectionRequests() {
 					if connectionError != nil {
 						errMsg = connectionError.Error()
 					}
+					p.historySaveDisconnected(disconnectionReason, errMsg)
 					saveLastError(connectionError)
 					p.notifyClients(&types.DisconnectedResp{Failure: connectionError != nil, Reason: disconnectionReason, ReasonDescription: errMsg})
 				}
@@ -1824,6 +2216,8 @@ func (p *Protocol) processConnectRequest(r service_types.ConnectionParams) (err
 // OnVpnStateChanged_SaveStateEarly - save the VPN state. If saveAndProcess==true, also call OnVpnStateChanged_ProcessSavedState().
 func (p *Protocol) OnVpnStateChanged_SaveStateEarly(state vpn.StateInfo, saveAndProcess bool) {
 	p._lastVPNState = state
+	metrics.SetVpnState(state.State)
+	p.historySaveVpnState(state)
 
 	if saveAndProcess {
 		p.OnVpnStateChanged_ProcessSavedState()
@@ -1873,3 +2267,85 @@ func (p *Protocol) NotifyClientsVpnConnecting() {
 func (p *Protocol) OnVpnPauseChanged() {
 	p.OnVpnStateChanged_ProcessSavedState()
 }
+
+// OnPreferencesChanged - notify clients about changed preferences
+func (p *Protocol) OnPreferencesChanged() {
+	p.notifyClients(p.createSettingsResponse())
+	p.notifyClients(p.createHelloResponse())
+}
+
+// sendSplitTunnelDomains sends per-domain split tunnelling rules with the resolving status
+func (p *Protocol) sendSplitTunnelDomains(conn net.Conn, idx int) {
+	domains, funcNotAvailableErr := p._service.SplitTunnelling_GetDomains()
+	resp := types.SplitTunnelDomainsResp{Domains: domains}
+	if funcNotAvailableErr != nil {
+		resp.FuncNotAvailableMessage = funcNotAvailableErr.Error()
+	}
+	p.sendResponse(conn, &resp, idx)
+}
+
+// sendSplitTunnelSubnets sends per-CIDR split tunnelling rules
+func (p *Protocol) sendSplitTunnelSubnets(conn net.Conn, idx int) {
+	include, exclude, funcNotAvailableErr := p._service.SplitTunnelling_GetSubnets()
+	resp := types.SplitTunnelSubnetsResp{Include: include, Exclude: exclude}
+	if funcNotAvailableErr != nil {
+		resp.FuncNotAvailableMessage = funcNotAvailableErr.Error()
+	}
+	p.sendResponse(conn, &resp, idx)
+}
+
+// sendConnectionProfiles sends the list of named connection profiles
+func (p *Protocol) sendConnectionProfiles(conn net.Conn, idx int) {
+	resp := types.ConnectionProfilesResp{Profiles: p._service.ConnectionProfiles(), AutoconnectProfile: p._service.Preferences().AutoconnectProfile}
+	p.sendResponse(conn, &resp, idx)
+}
+
+// sendNetworkRules sends the network-condition rules and the state of the current network
+func (p *Protocol) sendNetworkRules(conn net.Conn, idx int) {
+	resp := types.NetworkRulesResp{Rules: p._service.NetworkRules()}
+
+	network, matchedRule, err := p._service.NetworkRulesStatus()
+	if err != nil {
+		resp.CurrentNetworkError = err.Error()
+	} else {
+		resp.CurrentNetwork = types.DefaultNetworkInfo{
+			InterfaceName:    network.InterfaceName,
+			InterfaceType:    string(network.InterfaceType),
+			DnsSearchDomains: network.DnsSearchDomains,
+		}
+		if network.GatewayIP != nil {
+			resp.CurrentNetwork.GatewayIP = network.GatewayIP.String()
+		}
+		if network.GatewayMAC != nil {
+			resp.CurrentNetwork.GatewayMAC = network.GatewayMAC.String()
+		}
+	}
+	resp.MatchedRule = matchedRule
+
+	p.sendResponse(conn, &resp, idx)
+}
+
+// sendScheduleRules sends the time-of-day rules and the next scheduled transition
+func (p *Protocol) sendScheduleRules(conn net.Conn, idx int) {
+	resp := types.ScheduleRulesResp{Rules: p._service.ScheduleRules()}
+	resp.NextTransition, resp.ActiveRules = p._service.ScheduleStatus()
+	p.sendResponse(conn, &resp, idx)
+}
+
+// sendOpenVpnDetails sends the details of the current OpenVPN connection and OpenVPN log verbosity
+func (p *Protocol) sendOpenVpnDetails(conn net.Conn, idx int) {
+	resp := types.OpenVpnDetailsResp{Details: p._service.OpenVpnDetails(), LogVerbosity: p._service.OpenVpnLogVerbosity()}
+	p.sendResponse(conn, &resp, idx)
+}
+
+// sendCustomServers sends the list of user-imported servers
+func (p *Protocol) sendCustomServers(conn net.Conn, idx int, imported *service_types.CustomServerInfo) {
+	resp := types.CustomServersResp{Servers: p._service.CustomServers(), Imported: imported}
+	p.sendResponse(conn, &resp, idx)
+}
+
+// sendConnectionQuality sends the thresholds and the latest results of the connection-quality monitor
+func (p *Protocol) sendConnectionQuality(conn net.Conn, idx int) {
+	resp := types.ConnectionQualityResp{Params: p._service.ConnectionQualityParams(), Status: p._service.ConnectionQualityStatus()}
+	p.sendResponse(conn, &resp, idx)
+}
@@ -28,6 +28,7 @@ import (
 	api_types "github.com/swapnilsparsh/devsVPN/daemon/api/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	service_types "github.com/swapnilsparsh/devsVPN/daemon/service/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
 	"github.com/swapnilsparsh/devsVPN/daemon/wifiNotifier"
 )
@@ -127,6 +128,23 @@ func (p *Protocol) OnSplitTunnelStatusChanged() {
 	p.notifyClients(&status)
 }
 
+// OnConnectionFailover - the connection-quality monitor is reconnecting to another server. Notifying clients.
+func (p *Protocol) OnConnectionFailover(info service_types.ConnectionFailoverInfo) {
+	p.notifyClients(&types.ConnectionFailoverResp{Failover: info})
+}
+
+// OnWireGuardMtuChanged - MTU of the WireGuard interface changed by path MTU discovery. Notifying clients with the updated connection info.
+func (p *Protocol) OnWireGuardMtuChanged() {
+	if state := p._lastVPNState; state.State == vpn.CONNECTED {
+		p.notifyClients(p.createConnectedResponse(state))
+	}
+}
+
+// OnAutoTransportAttempt - transport attempt in automatic transport mode. Notifying clients.
+func (p *Protocol) OnAutoTransportAttempt(info service_types.AutoTransportAttemptInfo) {
+	p.notifyClients(&types.AutoTransportAttemptResp{Attempt: info})
+}
+
 func (p *Protocol) LastVpnStateIsConnected() bool {
 	return p._lastVPNState.State == vpn.CONNECTED
 }
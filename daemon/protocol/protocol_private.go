@@ -27,6 +27,7 @@ import (
 	"net"
 	"strings"
 
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/roles"
 	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
 )
 
@@ -70,10 +71,10 @@ func (p *Protocol) IsCanDoBackgroundAction() bool {
 	return true
 }
 
-func (p *Protocol) clientConnected(c net.Conn, cType types.ClientTypeEnum) {
+func (p *Protocol) clientConnected(c net.Conn, cType types.ClientTypeEnum, role roles.Role) {
 	p._connectionsMutex.Lock()
 	defer p._connectionsMutex.Unlock()
-	p._connections[c] = connectionInfo{Type: cType}
+	p._connections[c] = connectionInfo{Type: cType, Role: role}
 }
 
 func (p *Protocol) clientDisconnected(c net.Conn) (disconnectedClientInfo *connectionInfo) {
@@ -107,9 +108,9 @@ func (p *Protocol) notifyClientsDaemonExiting() {
 			log.Info("Notifying clients: 'daemon is stopping'...")
 		}
 
-		for conn := range p._connections {
+		for conn, connInfo := range p._connections {
 			// notifying client "service is going to stop" (client application (UI) will close)
-			p.sendResponse(conn, &types.ServiceExitingResp{}, 0)
+			p.sendResponseToRole(conn, connInfo.Role, &types.ServiceExitingResp{}, 0)
 			// closing current connection with a client
 			conn.Close()
 		}
@@ -0,0 +1,302 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package roles
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/helpers"
+)
+
+// Role defines the set of protocol commands a connected client is allowed to execute
+type Role string
+
+const (
+	// RoleObserver - read-only access (status, servers, settings info)
+	RoleObserver Role = "observer"
+	// RoleOperator - observer rights + connection control (connect/disconnect, pause, firewall on/off ...)
+	RoleOperator Role = "operator"
+	// RoleAdmin - full access (all commands)
+	RoleAdmin Role = "admin"
+)
+
+// commands which are allowed for RoleObserver
+var observerCommands = map[string]struct{}{
+	"Hello":                   {},
+	"EmptyReq":                {},
+	"GetVPNState":             {},
+	"GetServers":              {},
+	"PingServers":             {},
+	"CheckAccessiblePorts":    {},
+	"KillSwitchGetStatus":     {},
+	"SplitTunnelGetStatus":    {},
+	"GetDnsPredefinedConfigs": {},
+	"SessionStatus":           {},
+	"WiFiCurrentNetwork":      {},
+	"WiFiAvailableNetworks":   {},
+	"ConnectSettingsGet":      {},
+	"GetAppIcon":              {},
+	"GetInstalledApps":        {},
+	"HistoryGet":              {},
+	"SplitTunnelDomainsGet":   {},
+	"SplitTunnelSubnetsGet":   {},
+	"ConnectionProfilesGet":   {},
+	"NetworkRulesGet":         {},
+	"ScheduleRulesGet":        {},
+	"ConnectionQualityGet":    {},
+	"OpenVpnDetailsGet":       {},
+	"CustomServersGet":        {},
+}
+
+// commands which are allowed for RoleOperator (in addition to observerCommands)
+var operatorCommands = map[string]struct{}{
+	"Connect":                 {},
+	"Disconnect":              {},
+	"PauseConnection":         {},
+	"ResumeConnection":        {},
+	"ConnectSettings":         {},
+	"KillSwitchSetEnabled":    {},
+	"SetAlternateDns":         {},
+	"SplitTunnelAddApp":       {},
+	"SplitTunnelRemoveApp":    {},
+	"SplitTunnelAddedPidInfo": {},
+	"GenerateDiagnostics":     {},
+	"SubmitRageshakeReport":   {},
+}
+
+// Parse converts string to Role
+func Parse(s string) (Role, error) {
+	r := Role(strings.ToLower(strings.TrimSpace(s)))
+	if !r.IsValid() {
+		return "", fmt.Errorf("unknown role '%s' (expected: %s, %s or %s)", s, RoleObserver, RoleOperator, RoleAdmin)
+	}
+	return r, nil
+}
+
+// IsValid returns true if role is known
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleObserver, RoleOperator, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// IsCommandAllowed returns true if the protocol command is allowed for the role
+func (r Role) IsCommandAllowed(commandName string) bool {
+	switch r {
+	case RoleAdmin:
+		return true
+	case RoleOperator:
+		if _, ok := operatorCommands[commandName]; ok {
+			return true
+		}
+		_, ok := observerCommands[commandName]
+		return ok
+	case RoleObserver:
+		_, ok := observerCommands[commandName]
+		return ok
+	}
+	return false
+}
+
+// TokenInfo - public information about access token (the token value itself is never stored)
+type TokenInfo struct {
+	Name    string
+	Role    Role
+	Created int64 // Unix time
+}
+
+type tokenEntry struct {
+	TokenInfo
+	TokenHash string // hex-encoded SHA-256 of the token
+}
+
+// AccessTokens - per-client access tokens storage.
+// Each token is bound to a Role. Clients which are using a token (instead of the daemon secret)
+// are limited to commands allowed for the token role.
+type AccessTokens struct {
+	mutex      sync.Mutex
+	tokensFile string
+}
+
+func Init(tokensFile string) *AccessTokens {
+	return &AccessTokens{tokensFile: tokensFile}
+}
+
+// CheckToken returns information about the token if it is known
+func (t *AccessTokens) CheckToken(token string) (info TokenInfo, ok bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	token = strings.TrimSpace(token)
+	if len(token) == 0 {
+		return TokenInfo{}, false
+	}
+
+	entries, err := t.doRead()
+	if err != nil {
+		return TokenInfo{}, false
+	}
+
+	hash := hashToken(token)
+	for _, e := range entries {
+		if subtle.ConstantTimeCompare([]byte(e.TokenHash), []byte(hash)) == 1 {
+			return e.TokenInfo, true
+		}
+	}
+	return TokenInfo{}, false
+}
+
+// Create generates a new token for the role. The token value is returned only once.
+func (t *AccessTokens) Create(name string, role Role) (info TokenInfo, token string, err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.tokensFile) <= 0 {
+		return TokenInfo{}, "", fmt.Errorf("access tokens are not supported on this platform")
+	}
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		return TokenInfo{}, "", fmt.Errorf("access token name not defined")
+	}
+	if !role.IsValid() {
+		return TokenInfo{}, "", fmt.Errorf("unknown role '%s'", role)
+	}
+
+	entries, err := t.doRead()
+	if err != nil {
+		return TokenInfo{}, "", err
+	}
+	for _, e := range entries {
+		if e.Name == name {
+			return TokenInfo{}, "", fmt.Errorf("access token '%s' already exists", name)
+		}
+	}
+
+	buff := make([]byte, 32)
+	if _, err := rand.Read(buff); err != nil {
+		return TokenInfo{}, "", fmt.Errorf("failed to generate access token: %w", err)
+	}
+	token = hex.EncodeToString(buff)
+
+	info = TokenInfo{Name: name, Role: role, Created: time.Now().Unix()}
+	entries = append(entries, tokenEntry{TokenInfo: info, TokenHash: hashToken(token)})
+
+	if err := t.doWrite(entries); err != nil {
+		return TokenInfo{}, "", err
+	}
+	return info, token, nil
+}
+
+// Delete removes the token by name
+func (t *AccessTokens) Delete(name string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	entries, err := t.doRead()
+	if err != nil {
+		return err
+	}
+
+	newEntries := make([]tokenEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.Name != name {
+			newEntries = append(newEntries, e)
+		}
+	}
+	if len(newEntries) == len(entries) {
+		return fmt.Errorf("access token '%s' not found", name)
+	}
+	return t.doWrite(newEntries)
+}
+
+// List returns information about all tokens
+func (t *AccessTokens) List() ([]TokenInfo, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	entries, err := t.doRead()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]TokenInfo, 0, len(entries))
+	for _, e := range entries {
+		ret = append(ret, e.TokenInfo)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret, nil
+}
+
+// --------- private functions ---------
+
+func hashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+func (t *AccessTokens) doRead() ([]tokenEntry, error) {
+	if len(t.tokensFile) <= 0 {
+		return nil, nil // not implemented for this platform
+	}
+
+	data, err := os.ReadFile(t.tokensFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // no tokens defined
+		}
+		return nil, fmt.Errorf("failed to read access tokens file: %w", err)
+	}
+
+	var entries []tokenEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse access tokens file: %w", err)
+	}
+	return entries, nil
+}
+
+func (t *AccessTokens) doWrite(entries []tokenEntry) error {
+	if len(entries) == 0 {
+		if err := os.Remove(t.tokensFile); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove access tokens file: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize access tokens: %w", err)
+	}
+	if err := helpers.WriteFile(t.tokensFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to save access tokens file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package roles
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func keys(m map[string]struct{}) []string {
+	ret := make([]string, 0, len(m))
+	for k := range m {
+		ret = append(ret, k)
+	}
+	return ret
+}
+
+func TestIsCommandAllowed(t *testing.T) {
+	adminCommands := []string{"SetPreference", "SessionNew", "AccessTokenCreate", "AccessTokenDelete", "AccessTokenList", "SettingsImport", "ManagedConfigApply", "UnknownCommand"}
+
+	groups := []struct {
+		name     string
+		commands []string
+		allowed  map[Role]bool
+	}{
+		{"observer commands", keys(observerCommands), map[Role]bool{RoleObserver: true, RoleOperator: true, RoleAdmin: true}},
+		{"operator commands", keys(operatorCommands), map[Role]bool{RoleObserver: false, RoleOperator: true, RoleAdmin: true}},
+		{"admin commands", adminCommands, map[Role]bool{RoleObserver: false, RoleOperator: false, RoleAdmin: true}},
+	}
+
+	for _, g := range groups {
+		for _, role := range []Role{RoleObserver, RoleOperator, RoleAdmin, "", "root"} {
+			t.Run(g.name+"/"+string(role), func(t *testing.T) {
+				want := g.allowed[role] // unknown roles are not allowed to execute anything
+				for _, cmd := range g.commands {
+					if got := role.IsCommandAllowed(cmd); got != want {
+						t.Errorf("Role(%q).IsCommandAllowed(%q) = %v, want %v", role, cmd, got, want)
+					}
+				}
+			})
+		}
+	}
+
+	// the command lists must not overlap
+	for cmd := range operatorCommands {
+		if _, ok := observerCommands[cmd]; ok {
+			t.Errorf("command '%s' is defined for both observer and operator roles", cmd)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Role
+		wantErr bool
+	}{
+		{"observer", RoleObserver, false},
+		{" Operator ", RoleOperator, false},
+		{"ADMIN", RoleAdmin, false},
+		{"", "", true},
+		{"root", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Parse(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	// SHA-256("test")
+	if got, want := hashToken("test"), "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"; got != want {
+		t.Errorf("hashToken() = %s, want %s", got, want)
+	}
+	if hashToken("a") == hashToken("b") {
+		t.Errorf("different tokens must have different hashes")
+	}
+}
+
+func TestAccessTokens(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tokens.json")
+	tokens := Init(file)
+
+	// no tokens defined
+	if _, ok := tokens.CheckToken("anything"); ok {
+		t.Fatal("CheckToken() must fail when no tokens defined")
+	}
+
+	obsInfo, obsToken, err := tokens.Create(" monitoring ", RoleObserver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obsInfo.Name != "monitoring" || obsInfo.Role != RoleObserver || obsInfo.Created == 0 || len(obsToken) != 64 {
+		t.Fatalf("Create() = %+v, token %q", obsInfo, obsToken)
+	}
+	_, opToken, err := tokens.Create("automation", RoleOperator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opToken == obsToken {
+		t.Fatal("tokens must be unique")
+	}
+
+	// only the hash of the token is stored
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), obsToken) || !strings.Contains(string(data), hashToken(obsToken)) {
+		t.Errorf("tokens file must contain the token hash only:\n%s", data)
+	}
+	if fi, err := os.Stat(file); err == nil && fi.Mode().Perm() != 0600 {
+		t.Errorf("tokens file permissions = %v, want 0600", fi.Mode().Perm())
+	}
+
+	// lookup
+	if info, ok := tokens.CheckToken(" " + obsToken + "\n"); !ok || info != obsInfo {
+		t.Errorf("CheckToken(observer token) = %+v, %v", info, ok)
+	}
+	if info, ok := tokens.CheckToken(opToken); !ok || info.Role != RoleOperator || info.Name != "automation" {
+		t.Errorf("CheckToken(operator token) = %+v, %v", info, ok)
+	}
+	for _, bad := range []string{"", "  ", hashToken(obsToken), obsToken[:63], strings.ToUpper(obsToken), obsToken + "0"} {
+		if _, ok := tokens.CheckToken(bad); ok {
+			t.Errorf("CheckToken(%q) must fail", bad)
+		}
+	}
+
+	// errors
+	for _, tc := range []struct {
+		name    string
+		role    Role
+		wantErr string
+	}{
+		{"monitoring", RoleAdmin, "already exists"},
+		{" ", RoleAdmin, "name not defined"},
+		{"other", "root", "unknown role"},
+	} {
+		if _, _, err := tokens.Create(tc.name, tc.role); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("Create(%q, %q) error = %v, want %q", tc.name, tc.role, err, tc.wantErr)
+		}
+	}
+
+	list, err := tokens.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := []string{list[0].Name, list[1].Name}; len(list) != 2 || !reflect.DeepEqual(names, []string{"automation", "monitoring"}) {
+		t.Errorf("List() = %+v, want sorted by name", list)
+	}
+
+	// delete
+	if err := tokens.Delete("unknown"); err == nil {
+		t.Error("Delete() of unknown token must fail")
+	}
+	if err := tokens.Delete("monitoring"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tokens.CheckToken(obsToken); ok {
+		t.Error("deleted token must not be accepted")
+	}
+	if _, ok := tokens.CheckToken(opToken); !ok {
+		t.Error("other tokens must be kept")
+	}
+	if err := tokens.Delete("automation"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("tokens file must be removed with the last token (err: %v)", err)
+	}
+}
+
+func TestAccessTokensNotSupported(t *testing.T) {
+	tokens := Init("")
+	if _, _, err := tokens.Create("name", RoleAdmin); err == nil {
+		t.Error("Create() must fail when tokens file is not defined")
+	}
+	if _, ok := tokens.CheckToken("token"); ok {
+		t.Error("CheckToken() must fail when tokens file is not defined")
+	}
+}
+
+func TestAccessTokensBrokenFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tokens.json")
+	if err := os.WriteFile(file, []byte("{broken"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tokens := Init(file)
+	if _, ok := tokens.CheckToken("token"); ok {
+		t.Error("CheckToken() must fail when tokens file is broken")
+	}
+	if _, err := tokens.List(); err == nil {
+		t.Error("List() must fail when tokens file is broken")
+	}
+}
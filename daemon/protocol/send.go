@@ -29,6 +29,7 @@ import (
 	"strconv"
 
 	"github.com/swapnilsparsh/devsVPN/daemon/helpers"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/roles"
 	"github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
 )
 
@@ -88,8 +89,8 @@ func (p *Protocol) notifyClients(cmd ICommandBase) {
 	p._connectionsMutex.RLock()
 	defer p._connectionsMutex.RUnlock()
 
-	for conn := range p._connections {
-		p.sendResponse(conn, cmd, 0)
+	for conn, connInfo := range p._connections {
+		p.sendResponseToRole(conn, connInfo.Role, cmd, 0)
 	}
 }
 
@@ -122,6 +123,13 @@ func (p *Protocol) sendErrorResponse(conn net.Conn, request types.RequestBase, e
 }
 
 func (p *Protocol) sendResponse(conn net.Conn, cmd ICommandBase, idx int) (retErr error) {
+	return p.sendResponseToRole(conn, p.clientRole(conn), cmd, idx)
+}
+
+// sendResponseToRole sends the response with the secrets redacted according to the client role.
+// (used when the caller already holds '_connectionsMutex')
+func (p *Protocol) sendResponseToRole(conn net.Conn, role roles.Role, cmd ICommandBase, idx int) (retErr error) {
+	cmd = redactForRole(role, cmd)
 	if err := Send(conn, cmd, idx); err != nil {
 		return fmt.Errorf("%sfailed to send command: %w", p.connLogID(conn), err)
 	}
@@ -24,6 +24,7 @@ package types
 
 import (
 	api_types "github.com/swapnilsparsh/devsVPN/daemon/api/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/roles"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
 	service_types "github.com/swapnilsparsh/devsVPN/daemon/service/types"
@@ -51,6 +52,9 @@ type Hello struct {
 	Version string
 
 	Secret uint64
+	// AccessToken - alternative to Secret: per-client token bound to a role (see 'roles' package)
+	// Clients authenticated by the token are limited to the commands allowed for the token role
+	AccessToken string
 
 	// when 'true' - send HelloResp to all connected clients
 	SendResponseToAllClients bool
@@ -215,6 +219,117 @@ type ConnectSettingsGet struct {
 type Connect struct {
 	RequestBase
 	Params service_types.ConnectionParams
+	// Profile: name of the connection profile to connect with (when defined - 'Params' are ignored)
+	Profile string
+}
+
+// ConnectionProfilesGet request the list of named connection profiles
+// (response: ConnectionProfilesResp)
+type ConnectionProfilesGet struct {
+	RequestBase
+}
+
+// ConnectionProfileSave request to add (or update) named connection profile
+// (response: ConnectionProfilesResp)
+type ConnectionProfileSave struct {
+	RequestBase
+	ProfileName        string
+	Params             service_types.ConnectionParams
+	FromLastConnection bool // store the last connection parameters (daemon side) instead of 'Params'
+	Overwrite          bool // update the profile if it already exists
+}
+
+// ConnectionProfileRename request to rename named connection profile
+// (response: ConnectionProfilesResp)
+type ConnectionProfileRename struct {
+	RequestBase
+	ProfileName    string
+	NewProfileName string
+}
+
+// ConnectionProfileDelete request to remove named connection profile
+// (response: ConnectionProfilesResp)
+type ConnectionProfileDelete struct {
+	RequestBase
+	ProfileName string
+}
+
+// NetworkRulesGet request the network-condition rules and the state of the current network
+// (response: NetworkRulesResp)
+type NetworkRulesGet struct {
+	RequestBase
+}
+
+// NetworkRulesSet request to set the network-condition rules (the list is replaced)
+// (response: NetworkRulesResp)
+type NetworkRulesSet struct {
+	RequestBase
+	Rules []preferences.NetworkRule
+}
+
+// ScheduleRulesGet request the time-of-day rules and the next scheduled transition
+// (response: ScheduleRulesResp)
+type ScheduleRulesGet struct {
+	RequestBase
+}
+
+// ScheduleRulesSet request to set the time-of-day rules (the list is replaced)
+// (response: ScheduleRulesResp)
+type ScheduleRulesSet struct {
+	RequestBase
+	Rules []preferences.ScheduleRule
+}
+
+// ConnectionQualityGet request the thresholds and the latest results of the connection-quality monitor
+// (response: ConnectionQualityResp)
+type ConnectionQualityGet struct {
+	RequestBase
+}
+
+// ConnectionQualitySet request to set the thresholds of the connection-quality monitor
+// (response: ConnectionQualityResp)
+type ConnectionQualitySet struct {
+	RequestBase
+	Params preferences.ConnectionQualityParams
+}
+
+// OpenVpnDetailsGet request the details of the current OpenVPN connection (state, options pushed by the server, traffic statistics)
+// (response: OpenVpnDetailsResp)
+type OpenVpnDetailsGet struct {
+	RequestBase
+}
+
+// OpenVpnLogVerbositySet request to set OpenVPN log verbosity ('verb' option, 0-11; 0 - default).
+// It is applied to the current OpenVPN connection immediately.
+// (response: OpenVpnDetailsResp)
+type OpenVpnLogVerbositySet struct {
+	RequestBase
+	Verbosity int
+}
+
+// CustomServersGet request the list of user-imported servers ("bring your own server")
+// (response: CustomServersResp)
+type CustomServersGet struct {
+	RequestBase
+}
+
+// CustomServerImport request to import WireGuard ('.conf') or OpenVPN ('.ovpn') configuration file as a custom server.
+// To connect to the server: 'Connect' request with 'Params.CustomServerID' defined.
+// (response: CustomServersResp)
+type CustomServerImport struct {
+	RequestBase
+	ServerName string
+	Config     string // content of the configuration file
+	// (OpenVPN) credentials, required when the configuration contains 'auth-user-pass' option
+	Username string
+	Password string
+}
+
+// CustomServerDelete request to remove the user-imported server
+// (response: CustomServersResp)
+type CustomServerDelete struct {
+	RequestBase
+	ID string // ID or name of the server
 }
 
 // Disconnect disconnect active VPN connection
@@ -330,11 +445,77 @@ type ParanoidModeSetPasswordReq struct {
 	NewSecret string
 }
 
+// access tokens (role-based permissions)
+
+// AccessTokenCreate request to create new access token for a client
+// (response: AccessTokenCreatedResp)
+type AccessTokenCreate struct {
+	RequestBase
+	TokenName string
+	Role      roles.Role
+}
+
+// AccessTokenDelete request to remove access token
+type AccessTokenDelete struct {
+	RequestBase
+	TokenName string
+}
+
+// AccessTokenList request to get info about all access tokens
+// (response: AccessTokenListResp)
+type AccessTokenList struct {
+	RequestBase
+}
+
+// SettingsExport request to get the portable settings bundle
+// (response: SettingsExportResp)
+type SettingsExport struct {
+	RequestBase
+}
+
+// SettingsImport request to apply the settings bundle
+// (response: SettingsImportResp)
+type SettingsImport struct {
+	RequestBase
+	Bundle preferences.SettingsBundle
+	DryRun bool // only validate the bundle and report the changes which would be applied
+}
+
+// ManagedConfigApply request to (re)apply the headless daemon configuration file
+// (response: ManagedConfigResp)
+type ManagedConfigApply struct {
+	RequestBase
+	DryRun bool // only validate the configuration and report the changes which would be applied
+}
+
+// HistoryGet request to get connection events history
+// (response: HistoryResp)
+type HistoryGet struct {
+	RequestBase
+	From     int64    // Unix time; 0 - no limit
+	To       int64    // Unix time; 0 - no limit
+	Types    []string // event types (see 'history' package); empty - all types
+	MaxCount int      // maximum number of (latest) events to return; 0 - no limit
+}
+
 type CheckAccessiblePorts struct {
 	RequestBase
 	PortsToTest []api_types.PortInfo // in case of empty - will be tested all known ports
 }
 
+// GenerateDiagnostics request to get diagnostic logs
+// (response: DiagnosticsGeneratedResp)
+type GenerateDiagnostics struct {
+	RequestBase
+	// Optional filter of log records
+	LogComponents []string // names of loggers (e.g. "frwl", "prefs"); empty - all components
+	LogMinLevel   string   // trace, debug, info, warning, error; empty - all levels
+	// Optional time window (Unix time). When defined - the records from all available logs (including archives)
+	// in the time window are returned in 'Log1_Active' ('Log0_Old' is empty). Zero 'LogTo' means 'till now'.
+	LogFrom int64
+	LogTo   int64
+}
+
 // CrashReportType defines the type of crash report
 type CrashReportType string
 
@@ -360,4 +541,7 @@ type SubmitRageshakeReport struct {
 	ClientAttachedFilesPaths []string          `json:"client_attached_files_paths"`
 	ClientSystemInfoJson     string            `json:"client_system_info_json"`
 	AdditionalData           map[string]string `json:"additional_data,omitempty"`
+	// Optional time window (Unix time): attach the daemon log records in the window (from all available logs, including archives)
+	LogFrom int64 `json:"log_from,omitempty"`
+	LogTo   int64 `json:"log_to,omitempty"`
 }
@@ -29,8 +29,11 @@ import (
 	api_types "github.com/swapnilsparsh/devsVPN/daemon/api/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/logger"
 	"github.com/swapnilsparsh/devsVPN/daemon/obfsproxy"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol/roles"
 	"github.com/swapnilsparsh/devsVPN/daemon/rageshake"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/history"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/managedcfg"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
 	service_types "github.com/swapnilsparsh/devsVPN/daemon/service/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/v2r"
@@ -48,6 +51,8 @@ type ErrorType int
 const (
 	ErrorUnknown                   ErrorType = iota
 	ErrorParanoidModePasswordError ErrorType = iota
+	ErrorPermissionDenied          ErrorType = iota
+	ErrorManagedSettingLocked      ErrorType = iota // the setting is managed by the locked headless configuration file
 )
 
 // ErrorResp response of error
@@ -129,6 +134,7 @@ type SettingsResp struct {
 
 	IsAutoconnectOnLaunch          bool
 	IsAutoconnectOnLaunchDaemon    bool
+	AutoconnectProfile             string
 	UserDefinedOvpnFile            string
 	UserPrefs                      preferences.UserPreferences
 	WiFi                           preferences.WiFiParams
@@ -165,6 +171,10 @@ type HelloResp struct {
 
 	ParanoidMode ParanoidModeStatus
 
+	// ClientRole - role of the client which sent the 'Hello' request
+	// (empty in HelloResp notifications sent to all clients)
+	ClientRole roles.Role
+
 	DaemonSettings SettingsResp
 }
 
@@ -180,6 +190,25 @@ type SessionResp struct {
 	WgUsePresharedKey  bool
 }
 
+// RedactedSecret - placeholder which replaces a secret value in responses to the clients which are not allowed to see it
+const RedactedSecret = "***"
+
+// Redacted returns session info without the secrets.
+// The session token is replaced by a placeholder (so a client is still able to detect that the account is logged in),
+// the device information is removed.
+func (s SessionResp) Redacted() SessionResp {
+	if len(s.Session) > 0 {
+		s.Session = RedactedSecret
+	}
+	s.DeviceName = ""
+	s.WgPublicKey = ""
+	s.WgLocalIP = ""
+	s.WgKeyGenerated = 0
+	s.WgKeysRegenInerval = 0
+	s.WgUsePresharedKey = false
+	return s
+}
+
 type TransferredDataResp struct {
 	SentData     string
 	ReceivedData string
@@ -344,8 +373,11 @@ type ConnectedResp struct {
 	Dns             DnsStatus
 	IsTCP           bool
 	Mtu             int                    // (for WireGuard connections)
+	PathMtu         int                    // MTU of the WireGuard interface detected by path MTU discovery (0 - not detected yet)
 	V2RayProxy      v2r.V2RayTransportType // applicable only for 'CONNECTED' state
-	Obfsproxy       obfsproxy.Config       // applicable only for 'CONNECTED' state (OpenVPN)
+	Obfsproxy       obfsproxy.Config       // applicable only for 'CONNECTED' state (OpenVPN; WireGuard with pluggable transport)
+	OpenVpn         *vpn.OpenVpnDetails    `json:",omitempty"` // (for OpenVPN connections) state, options pushed by the server, traffic statistics
+	CustomServer    string                 `json:",omitempty"` // name of the user-imported server (empty - privateLINE server)
 	IsPaused        bool                   // When "true" - the actual connection may be "disconnected" (depending on the platform and VPN protocol), but the daemon responds "connected"
 	PausedTill      string                 // pausedTill.Format(time.RFC3339)
 }
@@ -457,3 +489,106 @@ type CheckAccessiblePortsResponse struct {
 	RequestBase
 	Ports []api_types.PortInfo
 }
+
+// AccessTokenCreatedResp contains new access token (the token value is available only in this response)
+type AccessTokenCreatedResp struct {
+	CommandBase
+	Info  roles.TokenInfo
+	Token string
+}
+
+// AccessTokenListResp contains info about all access tokens
+type AccessTokenListResp struct {
+	CommandBase
+	Tokens []roles.TokenInfo
+}
+
+// SettingsExportResp contains the portable settings bundle
+type SettingsExportResp struct {
+	CommandBase
+	Bundle preferences.SettingsBundle
+}
+
+// SettingsImportResp contains the list of changes applied by SettingsImport (or to be applied, in dry-run mode)
+type SettingsImportResp struct {
+	CommandBase
+	Changes []string
+}
+
+// ManagedConfigResp contains info about the headless daemon configuration file
+type ManagedConfigResp struct {
+	CommandBase
+	Status managedcfg.Status
+}
+
+// HistoryResp contains connection events history
+type HistoryResp struct {
+	CommandBase
+	Events []history.Event
+}
+
+// DefaultNetworkInfo - information about the network which is in use by the default route
+type DefaultNetworkInfo struct {
+	InterfaceName    string
+	InterfaceType    string // "ethernet", "wifi" or "other"
+	GatewayIP        string
+	GatewayMAC       string
+	DnsSearchDomains []string
+}
+
+// NetworkRulesResp contains the network-condition rules and the state of the current network
+type NetworkRulesResp struct {
+	CommandBase
+	Rules               []preferences.NetworkRule
+	CurrentNetwork      DefaultNetworkInfo
+	CurrentNetworkError string // error obtaining info about the current network
+	MatchedRule         string // name of the rule which matches the current network (empty - no matching rules)
+}
+
+// ScheduleRulesResp contains the time-of-day rules and the next scheduled transition
+type ScheduleRulesResp struct {
+	CommandBase
+	Rules          []preferences.ScheduleRule
+	NextTransition *preferences.ScheduleTransition // nil - no enabled rules
+	ActiveRules    []string                        // names of the rules which time windows are active now
+}
+
+// ConnectionQualityResp contains the thresholds and the latest results of the connection-quality monitor
+type ConnectionQualityResp struct {
+	CommandBase
+	Params preferences.ConnectionQualityParams
+	Status service_types.ConnectionQualityStatus
+}
+
+// ConnectionFailoverResp - notification: the connection-quality monitor is reconnecting to another server
+type ConnectionFailoverResp struct {
+	CommandBase
+	Failover service_types.ConnectionFailoverInfo
+}
+
+// OpenVpnDetailsResp contains the details of the current OpenVPN connection
+type OpenVpnDetailsResp struct {
+	CommandBase
+	Details      *vpn.OpenVpnDetails // nil - no OpenVPN connection
+	LogVerbosity int                 // OpenVPN log verbosity used for connections (0 - default)
+}
+
+// CustomServersResp contains the list of user-imported servers ("bring your own server")
+type CustomServersResp struct {
+	CommandBase
+	Servers  []service_types.CustomServerInfo
+	Imported *service_types.CustomServerInfo `json:",omitempty"` // (response to CustomServerImport) the imported server
+}
+
+// AutoTransportAttemptResp - notification: transport attempt in automatic transport mode
+type AutoTransportAttemptResp struct {
+	CommandBase
+	Attempt service_types.AutoTransportAttemptInfo
+}
+
+// ConnectionProfilesResp contains the list of named connection profiles
+type ConnectionProfilesResp struct {
+	CommandBase
+	Profiles           []preferences.ConnectionProfile
+	AutoconnectProfile string // profile used for auto-connection (empty - the last connection parameters)
+}
@@ -100,6 +100,11 @@ type SplitTunnelStatus struct {
 	// Information about active applications running in Split-Tunnel environment
 	// (applicable for Linux)
 	RunningApps []splittun.RunningApp
+	// Per-domain rules with the resolving status
+	Domains []splittun.DomainStatus
+	// Per-CIDR rules: networks routed through the VPN tunnel ('include') and bypassing it ('exclude')
+	IncludeSubnets []string
+	ExcludeSubnets []string
 }
 
 // SplitTunnelAddApp (request) add application to SplitTunneling
@@ -158,3 +163,53 @@ type SplitTunnelRemoveApp struct {
 	// (applicable for Windows) full path to the app binary to be excluded from ST
 	Exec string
 }
+
+// SplitTunnelDomainsGet (request) requests the per-domain split tunnelling rules
+// Expected response: SplitTunnelDomainsResp
+type SplitTunnelDomainsGet struct {
+	RequestBase
+}
+
+// SplitTunnelDomainsResp (response) contains the per-domain split tunnelling rules with the resolving status
+type SplitTunnelDomainsResp struct {
+	CommandBase
+	Domains                 []splittun.DomainStatus
+	FuncNotAvailableMessage string // non-empty when per-domain split tunnelling is not available on this platform
+}
+
+// SplitTunnelDomainAdd (request) adds (or updates) per-domain split tunnelling rule
+// Expected response: SplitTunnelDomainsResp
+type SplitTunnelDomainAdd struct {
+	RequestBase
+	Domain string // domain name; '*.example.com' - any subdomain of 'example.com' (detected from DNS responses)
+	Bypass bool   // false - route the traffic to the domain through the VPN tunnel; true - send it directly (bypass the VPN tunnel)
+}
+
+// SplitTunnelDomainRemove (request) removes per-domain split tunnelling rule
+// Expected response: SplitTunnelDomainsResp
+type SplitTunnelDomainRemove struct {
+	RequestBase
+	Domain string
+}
+
+// SplitTunnelSubnetsGet (request) requests the per-CIDR split tunnelling rules
+// Expected response: SplitTunnelSubnetsResp
+type SplitTunnelSubnetsGet struct {
+	RequestBase
+}
+
+// SplitTunnelSubnetsResp (response) contains the per-CIDR split tunnelling rules
+type SplitTunnelSubnetsResp struct {
+	CommandBase
+	Include                 []string // networks routed through the VPN tunnel
+	Exclude                 []string // networks routed bypassing the VPN tunnel
+	FuncNotAvailableMessage string   // non-empty when per-CIDR split tunnelling is not available on this platform
+}
+
+// SplitTunnelSubnetsSet (request) sets the per-CIDR split tunnelling rules (both lists are replaced)
+// Expected response: SplitTunnelSubnetsResp
+type SplitTunnelSubnetsSet struct {
+	RequestBase
+	Include []string // IPv4 networks in CIDR notation (or single IPv4 addresses) to route through the VPN tunnel
+	Exclude []string // IPv4 networks in CIDR notation (or single IPv4 addresses) to route bypassing the VPN tunnel
+}
@@ -72,10 +72,19 @@ type ServicePreference string
 
 const (
 	Prefs_IsEnableLogging                ServicePreference = "enable_logging"
+	Prefs_IsLogStructured                ServicePreference = "log_structured"
+	Prefs_LogLevels                      ServicePreference = "log_levels"
+	Prefs_LogRotation                    ServicePreference = "log_rotation"
 	Prefs_IsAutoconnectOnLaunch          ServicePreference = "autoconnect_on_launch"
 	Prefs_IsAutoconnectOnLaunch_Daemon   ServicePreference = "autoconnect_on_launch_daemon"
 	Prefs_HealthchecksType               ServicePreference = "healthchecks_type"
 	Prefs_PermissionReconfigureOtherVPNs ServicePreference = "permission_reconfigure_other_vpns"
+	Prefs_IsUnixSocketDisabled           ServicePreference = "unix_socket_disabled"
+	Prefs_UnixSocketAllowedUIDs          ServicePreference = "unix_socket_allowed_uids" // comma-separated list of UIDs
+	Prefs_UnixSocketAllowedGIDs          ServicePreference = "unix_socket_allowed_gids" // comma-separated list of GIDs
+	Prefs_IsMetricsEnabled               ServicePreference = "metrics_enabled"
+	Prefs_MetricsListenAddress           ServicePreference = "metrics_listen_address"
+	Prefs_AutoconnectProfile             ServicePreference = "autoconnect_profile"
 )
 
 func (sp ServicePreference) Equals(key string) bool {
@@ -0,0 +1,170 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/platform"
+)
+
+// peerCredentials - credentials of a client process connected over the Unix-domain socket
+type peerCredentials struct {
+	Uid uint32
+	Gid uint32
+	Pid int32
+}
+
+// unixPeerAddr is a net.Addr which identifies a Unix socket client by its peer credentials (in use for logging)
+type unixPeerAddr struct {
+	cred peerCredentials
+}
+
+func (a unixPeerAddr) Network() string { return "unix" }
+func (a unixPeerAddr) String() string {
+	return fmt.Sprintf("uds(uid:%d pid:%d)", a.cred.Uid, a.cred.Pid)
+}
+
+// unixPeerConn - client connection accepted on the Unix-domain socket
+type unixPeerConn struct {
+	*net.UnixConn
+	cred      peerCredentials
+	isAllowed bool // peer credentials are in the allow-list (checked when connection accepted)
+}
+
+func (c *unixPeerConn) RemoteAddr() net.Addr {
+	return unixPeerAddr{cred: c.cred}
+}
+
+// isPeerAuthorizedConn returns 'true' for connections which were authorized by their peer credentials
+func isPeerAuthorizedConn(c net.Conn) bool {
+	upc, ok := c.(*unixPeerConn)
+	return ok && upc.isAllowed
+}
+
+// checkPeerAccessDenied returns error for Unix socket connections of a peer which is not in the allow-list
+func checkPeerAccessDenied(c net.Conn) error {
+	if upc, ok := c.(*unixPeerConn); ok && !upc.isAllowed {
+		return fmt.Errorf("access denied for the user (uid:%d gid:%d)", upc.cred.Uid, upc.cred.Gid)
+	}
+	return nil
+}
+
+// peerGroupIDs returns the primary and the supplementary group IDs of the peer user.
+// SO_PEERCRED provides only the primary group, so the supplementary groups are resolved from the user database;
+// if it fails - only the primary group is returned.
+func peerGroupIDs(cred peerCredentials) []uint32 {
+	gids := []uint32{cred.Gid}
+
+	u, err := user.LookupId(strconv.FormatUint(uint64(cred.Uid), 10))
+	if err != nil {
+		log.Warning(fmt.Sprintf("Unix socket peer (uid:%d): failed to resolve supplementary groups: %v", cred.Uid, err))
+		return gids
+	}
+	groupIds, err := u.GroupIds()
+	if err != nil {
+		log.Warning(fmt.Sprintf("Unix socket peer (uid:%d): failed to resolve supplementary groups: %v", cred.Uid, err))
+		return gids
+	}
+	for _, g := range groupIds {
+		gid, err := strconv.ParseUint(g, 10, 32)
+		if err != nil || uint32(gid) == cred.Gid {
+			continue
+		}
+		gids = append(gids, uint32(gid))
+	}
+	return gids
+}
+
+// startUnixSocketListener starts listening on the Unix-domain socket (if supported on this platform and not disabled in preferences).
+// The accepted connections are processed the same way as TCP connections,
+// but the 'Hello' secret is not required: a client is authorized by its peer credentials (SO_PEERCRED).
+func (p *Protocol) startUnixSocketListener() error {
+	socketFile := platform.ServiceSocketFile()
+	if len(socketFile) <= 0 {
+		return nil // not supported on this platform
+	}
+
+	prefs := p._service.Preferences()
+	if prefs.IsUnixSocketDisabled {
+		log.Info("Unix socket listener disabled")
+		return nil
+	}
+
+	// remove socket file which can remain after previous daemon session
+	if err := os.Remove(socketFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove old socket file: %w", err)
+	}
+
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: socketFile, Net: "unix"})
+	if err != nil {
+		return fmt.Errorf("failed to start Unix socket listener: %w", err)
+	}
+	listener.SetUnlinkOnClose(true)
+
+	// Any local user is able to connect to the socket; the access is controlled by peer credentials check
+	if err := os.Chmod(socketFile, 0666); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to change Unix socket access rights: %w", err)
+	}
+
+	p._connectionsMutex.Lock()
+	p._unixListener = listener
+	p._connectionsMutex.Unlock()
+	log.Info(fmt.Sprintf("Unix socket listener started: %s", socketFile))
+
+	go func() {
+		defer func() {
+			listener.Close()
+			log.Info("Unix socket listener closed")
+		}()
+
+		for {
+			conn, err := listener.AcceptUnix()
+			if err != nil {
+				if p._isRunning && !errors.Is(err, net.ErrClosed) {
+					log.Error("Server: failed to accept incoming Unix socket connection:", err)
+				}
+				return
+			}
+
+			cred, err := getPeerCredentials(conn)
+			if err != nil {
+				log.Error(fmt.Errorf("refusing Unix socket connection: failed to get peer credentials: %w", err))
+				conn.Close()
+				continue
+			}
+
+			// Preferences can be changed at runtime, so always check the latest allow-lists.
+			// Not allowed peer will be refused when processing its 'Hello' request (so the client receives an error response).
+			prefs := p._service.Preferences()
+			isAllowed := prefs.IsUnixSocketPeerAllowed(cred.Uid, peerGroupIDs(cred))
+
+			go p.processClient(&unixPeerConn{UnixConn: conn, cred: cred, isAllowed: isAllowed})
+		}
+	}()
+
+	return nil
+}
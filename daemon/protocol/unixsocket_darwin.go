@@ -0,0 +1,58 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// getPeerCredentials returns credentials of the process on the other end of the Unix socket (LOCAL_PEERCRED)
+func getPeerCredentials(conn *net.UnixConn) (cred peerCredentials, err error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return cred, err
+	}
+
+	var (
+		xucred  *unix.Xucred
+		pid     int
+		credErr error
+	)
+	if err := rawConn.Control(func(fd uintptr) {
+		if xucred, credErr = unix.GetsockoptXucred(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERCRED); credErr != nil {
+			return
+		}
+		pid, _ = unix.GetsockoptInt(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERPID)
+	}); err != nil {
+		return cred, err
+	}
+	if credErr != nil {
+		return cred, fmt.Errorf("LOCAL_PEERCRED: %w", credErr)
+	}
+
+	cred = peerCredentials{Uid: xucred.Uid, Pid: int32(pid)}
+	if xucred.Ngroups > 0 {
+		cred.Gid = xucred.Groups[0]
+	}
+	return cred, nil
+}
@@ -27,6 +27,7 @@ import (
 	"net"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 
 	"github.com/swapnilsparsh/devsVPN/daemon/helpers"
@@ -70,6 +71,11 @@ var (
 	stateAllowLan          bool
 	stateAllowLanMulticast bool
 
+	// addresses resolved for per-domain split tunnelling rules (map[<IP>]<expiration time>)
+	splitTunnelDomainsTunnelAddrs map[string]time.Time
+	splitTunnelDomainsBypassAddrs map[string]time.Time
+	splitTunnelSubnets            []net.IPNet
+
 	getPrefsCallback                 preferences.GetPrefsCallback
 	setHealthchecksTypeCallback      service_types.SetHealthchecksTypeCallback
 	disableTotalShieldAsyncCallback  DisableTotalShieldAsyncCallback
@@ -482,6 +488,29 @@ func OnChangeDNS(newDnsCfg *dns.DnsSettings) error {
 	return err
 }
 
+// OnChangeSplitTunnelDomains - must be called when the addresses resolved for per-domain split tunnelling rules are changed.
+// Traffic to these addresses is allowed by the firewall till the expiration time (even when Total Shield is on).
+func OnChangeSplitTunnelDomains(tunnelAddrs, bypassAddrs map[string]time.Time) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	splitTunnelDomainsTunnelAddrs = tunnelAddrs
+	splitTunnelDomainsBypassAddrs = bypassAddrs
+
+	return implOnChangeSplitTunnelDomains()
+}
+
+// OnChangeSplitTunnelSubnets - must be called when the networks of per-CIDR split tunnelling rules are changed.
+// Traffic to these networks is allowed by the firewall (even when Total Shield is on).
+func OnChangeSplitTunnelSubnets(subnets []net.IPNet) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	splitTunnelSubnets = subnets
+
+	return implOnChangeSplitTunnelSubnets()
+}
+
 // SetUserExceptions set ip/mask to be excluded from FW block
 // Parameters:
 //   - exceptions - comma separated list of IP addresses in format: x.x.x.x[/xx]
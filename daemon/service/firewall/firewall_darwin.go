@@ -353,3 +353,11 @@ func implSingleDnsRuleOn(dnsAddr net.IP) (retErr error) {
 }
 
 func implTopFirewallPriority() bool { return true } // nothing to do on OSX
+
+func implOnChangeSplitTunnelDomains() error {
+	return nil // per-domain split tunnelling is not implemented for this platform
+}
+
+func implOnChangeSplitTunnelSubnets() error {
+	return nil // per-CIDR split tunnelling is not implemented for this platform
+}
@@ -398,6 +398,30 @@ func implOnChangeDNS(dnsServers *[]net.IP) (err error) {
 	return nil
 }
 
+// implOnChangeSplitTunnelDomains updates the nft sets of the addresses resolved for per-domain split tunnelling rules.
+// Not supported by the legacy (iptables) firewall implementation.
+func implOnChangeSplitTunnelDomains() error {
+	if enabled, err := implGetEnabled(false); err != nil {
+		return log.ErrorFE("failed to get info if firewall is on: %w", err)
+	} else if !enabled {
+		return nil
+	}
+
+	return implOnChangeSplitTunnelDomainsNft()
+}
+
+// implOnChangeSplitTunnelSubnets updates the nft set of the networks of per-CIDR split tunnelling rules.
+// Not supported by the legacy (iptables) firewall implementation.
+func implOnChangeSplitTunnelSubnets() error {
+	if enabled, err := implGetEnabled(false); err != nil {
+		return log.ErrorFE("failed to get info if firewall is on: %w", err)
+	} else if !enabled {
+		return nil
+	}
+
+	return implOnChangeSplitTunnelSubnetsNft()
+}
+
 func implTotalShieldApply(wfpTransactionAlreadyInProgress, totalShieldNewState bool) (retErr error) {
 	var (
 		implTotalShieldApplyWaiter sync.WaitGroup
@@ -28,11 +28,14 @@
 package firewall
 
 import (
+	"encoding/binary"
 	"fmt"
+	"math"
 	"net"
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/nftables"
 	"github.com/google/nftables/binaryutil"
@@ -50,6 +53,11 @@ const (
 	PL_DNS_SET                   = "privateLINE_DNS"
 	PL_INTERNAL_HOSTS_SET_PREFIX = "privateLINE_allow_incoming_IPv4_UDP_for_"
 
+	// addresses resolved for per-domain split tunnelling rules (elements expire by DNS TTL)
+	PL_SPLIT_TUNNEL_DOMAINS_SET = "privateLINE_split_tunnel_domains_IPv4"
+	PL_SPLIT_BYPASS_DOMAINS_SET = "privateLINE_split_bypass_domains_IPv4"
+	PL_SPLIT_SUBNETS_SET        = "privateLINE_split_tunnel_subnets_IPv4"
+
 	VPN_COEXISTENCE_CHAIN_NFT_IN  = VPN_COEXISTENCE_CHAIN_PREFIX + "-nft-in"
 	VPN_COEXISTENCE_CHAIN_NFT_OUT = VPN_COEXISTENCE_CHAIN_PREFIX + "-nft-out"
 )
@@ -627,6 +635,44 @@ func doEnableNft(fwLinuxNftablesMutexGrabbed, enableNftVpnCoexistence bool) (err
 	}
 	ourSets = append(ourSets, privatelineDnsAddrsIPv4)
 
+	// sets of addresses resolved for per-domain split tunnelling rules
+	splitTunnelDomainsAddrsIPv4 := &nftables.Set{
+		Name:       PL_SPLIT_TUNNEL_DOMAINS_SET,
+		Table:      filter,
+		KeyType:    nftables.TypeIPAddr,
+		Dynamic:    true,
+		HasTimeout: true,
+	}
+	if err := nftConn.AddSet(splitTunnelDomainsAddrsIPv4, splitTunnelDomainsSetElements(splitTunnelDomainsTunnelAddrs)); err != nil {
+		return log.ErrorFE("enable - error creating nft set: %w", err)
+	}
+	ourSets = append(ourSets, splitTunnelDomainsAddrsIPv4)
+
+	splitBypassDomainsAddrsIPv4 := &nftables.Set{
+		Name:       PL_SPLIT_BYPASS_DOMAINS_SET,
+		Table:      filter,
+		KeyType:    nftables.TypeIPAddr,
+		Dynamic:    true,
+		HasTimeout: true,
+	}
+	if err := nftConn.AddSet(splitBypassDomainsAddrsIPv4, splitTunnelDomainsSetElements(splitTunnelDomainsBypassAddrs)); err != nil {
+		return log.ErrorFE("enable - error creating nft set: %w", err)
+	}
+	ourSets = append(ourSets, splitBypassDomainsAddrsIPv4)
+
+	// set of networks of per-CIDR split tunnelling rules (both 'include' and 'exclude')
+	splitSubnetsIPv4 := &nftables.Set{
+		Name:     PL_SPLIT_SUBNETS_SET,
+		Table:    filter,
+		KeyType:  nftables.TypeIPAddr,
+		Interval: true,
+		Dynamic:  true,
+	}
+	if err := nftConn.AddSet(splitSubnetsIPv4, splitTunnelSubnetsSetElements(splitTunnelSubnets)); err != nil {
+		return log.ErrorFE("enable - error creating nft set: %w", err)
+	}
+	ourSets = append(ourSets, splitSubnetsIPv4)
+
 	for _, vpnEntryHostParsed := range prefs.VpnEntryHostsParsed {
 		if err = nftConn.SetAddElements(wgEndpointAddrsIPv4, []nftables.SetElement{{Key: vpnEntryHostParsed.VpnEntryHostIP}}); err != nil {
 			return log.ErrorFE("enable - error adding vpnEntryHostParsed.VpnEntryHostIP to set: %w", err)
@@ -753,6 +799,46 @@ func doEnableNft(fwLinuxNftablesMutexGrabbed, enableNftVpnCoexistence bool) (err
 		},
 	})
 
+	// Allow per-domain split tunnelling addresses (both 'tunnel' and 'bypass' rules) and networks of per-CIDR rules:
+	// out any proto, in established+related
+	for _, splitDomainsSet := range []*nftables.Set{splitTunnelDomainsAddrsIPv4, splitBypassDomainsAddrsIPv4, splitSubnetsIPv4} {
+		nftConn.AddRule(&nftables.Rule{ // in established+related
+			Table: filter,
+			Chain: vpnCoexistenceChainIn,
+			Exprs: []expr.Any{
+				// [ src IP: payload load 4b @ network header + 12 => reg 1 ]
+				&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: 12, Len: 4},
+				// [ lookup reg 1, set of split tunnelling addresses ]
+				&expr.Lookup{SourceRegister: 1, SetName: splitDomainsSet.Name, SetID: splitDomainsSet.ID},
+				&expr.Ct{Register: 2, SourceRegister: false, Key: expr.CtKeySTATE},
+				&expr.Bitwise{
+					SourceRegister: 2,
+					DestRegister:   2,
+					Len:            4,
+					Mask:           binaryutil.NativeEndian.PutUint32(expr.CtStateBitESTABLISHED | expr.CtStateBitRELATED),
+					Xor:            binaryutil.NativeEndian.PutUint32(0),
+				},
+				&expr.Cmp{Op: expr.CmpOpNeq, Register: 2, Data: []byte{0, 0, 0, 0}},
+				&expr.Counter{},
+				//[ immediate reg 0 accept ]
+				&expr.Verdict{Kind: expr.VerdictAccept},
+			},
+		})
+		nftConn.AddRule(&nftables.Rule{ // out any proto
+			Table: filter,
+			Chain: vpnCoexistenceChainOut,
+			Exprs: []expr.Any{
+				// [ dest IP: payload load 4b @ network header + 16 => reg 1 ]
+				&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: 16, Len: 4},
+				// [ lookup reg 1, set of split tunnelling addresses ]
+				&expr.Lookup{SourceRegister: 1, SetName: splitDomainsSet.Name, SetID: splitDomainsSet.ID},
+				&expr.Counter{},
+				//[ immediate reg 0 accept ]
+				&expr.Verdict{Kind: expr.VerdictAccept},
+			},
+		})
+	}
+
 	/*
 		// Allow UDP src port 53 from our DNS servers, incl. custom DNS
 		nftConn.AddRule(&nftables.Rule{ // in UDP, src port 53
@@ -936,6 +1022,32 @@ func doEnableNft(fwLinuxNftablesMutexGrabbed, enableNftVpnCoexistence bool) (err
 			&expr.Verdict{Kind: expr.VerdictAccept}},
 	})
 
+	// On systems with cgroup v2 only (no net_cls controller) the packets of allowed apps are marked by splittun package
+	// ('socket cgroupv2' match), the mark value is the same as cgroup classid. Inbound packets are matched by conntrack mark.
+	allowedAppsMark := binaryutil.NativeEndian.PutUint32(PL_CGROUP_ID)
+	nftConn.AddRule(&nftables.Rule{
+		Table: filter,
+		Chain: vpnCoexistenceChainIn,
+		Exprs: []expr.Any{
+			// [ ct load mark => reg 1 ]
+			&expr.Ct{Key: expr.CtKeyMARK, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: allowedAppsMark},
+			&expr.Counter{},
+			//[ immediate reg 0 accept ]
+			&expr.Verdict{Kind: expr.VerdictAccept}},
+	})
+	nftConn.AddRule(&nftables.Rule{
+		Table: filter,
+		Chain: vpnCoexistenceChainOut,
+		Exprs: []expr.Any{
+			// [ meta load mark => reg 1 ]
+			&expr.Meta{Key: expr.MetaKeyMARK, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: allowedAppsMark},
+			&expr.Counter{},
+			//[ immediate reg 0 accept ]
+			&expr.Verdict{Kind: expr.VerdictAccept}},
+	})
+
 	// Eh, allow our REST API servers explicitly also - just in case
 	nftConn.AddRule(&nftables.Rule{ // outbound TCP ports 80,443
 		Table: filter,
@@ -1385,6 +1497,104 @@ func implOnChangeDnsNft(newDnsServers *[]net.IP) (err error) { // by now we know
 	return nil
 }
 
+// splitTunnelDomainsSetElements converts map[<IP>]<expiration time> to nft set elements with timeouts (expired addresses are skipped)
+func splitTunnelDomainsSetElements(addrs map[string]time.Time) []nftables.SetElement {
+	elements := make([]nftables.SetElement, 0, len(addrs))
+	for addr, expires := range addrs {
+		ip := net.ParseIP(addr).To4()
+		timeout := time.Until(expires).Truncate(time.Second)
+		if ip == nil || timeout <= 0 {
+			continue
+		}
+		elements = append(elements, nftables.SetElement{Key: ip, Timeout: timeout})
+	}
+	return elements
+}
+
+func implOnChangeSplitTunnelDomainsNft() (err error) {
+	fwLinuxNftablesMutex.Lock()
+	defer fwLinuxNftablesMutex.Unlock()
+
+	defer func() {
+		if err != nil {
+			printNftToLog()
+		}
+	}()
+
+	filter := &nftables.Table{Family: TABLE_TYPE, Name: TABLE}
+
+	for setName, addrs := range map[string]map[string]time.Time{
+		PL_SPLIT_TUNNEL_DOMAINS_SET: splitTunnelDomainsTunnelAddrs,
+		PL_SPLIT_BYPASS_DOMAINS_SET: splitTunnelDomainsBypassAddrs,
+	} {
+		set, err := nftConn.GetSetByName(filter, setName)
+		if err != nil || set == nil {
+			return log.ErrorFE("error GetSetByName(filter, %s): %w", setName, err)
+		}
+		nftConn.FlushSet(set)
+		if elements := splitTunnelDomainsSetElements(addrs); len(elements) > 0 {
+			if err = nftConn.SetAddElements(set, elements); err != nil {
+				return log.ErrorFE("error adding elements to set %s: %w", setName, err)
+			}
+		}
+	}
+
+	if err := nftConn.Flush(); err != nil {
+		return log.ErrorFE("implOnChangeSplitTunnelDomainsNft - error nft flush: %w", err)
+	}
+
+	return nil
+}
+
+// splitTunnelSubnetsSetElements converts IPv4 networks to elements of nft interval set
+func splitTunnelSubnetsSetElements(subnets []net.IPNet) []nftables.SetElement {
+	elements := make([]nftables.SetElement, 0, len(subnets)*2)
+	for _, n := range subnets {
+		start := n.IP.Mask(n.Mask).To4()
+		if start == nil {
+			continue
+		}
+		elements = append(elements, nftables.SetElement{Key: start})
+
+		// interval end is exclusive: the first address after the network (absent for the networks at the end of address space)
+		end := binary.BigEndian.Uint32(start) | ^binary.BigEndian.Uint32(net.IP(n.Mask).To4())
+		if end != math.MaxUint32 {
+			elements = append(elements, nftables.SetElement{Key: binary.BigEndian.AppendUint32(nil, end+1), IntervalEnd: true})
+		}
+	}
+	return elements
+}
+
+func implOnChangeSplitTunnelSubnetsNft() (err error) {
+	fwLinuxNftablesMutex.Lock()
+	defer fwLinuxNftablesMutex.Unlock()
+
+	defer func() {
+		if err != nil {
+			printNftToLog()
+		}
+	}()
+
+	filter := &nftables.Table{Family: TABLE_TYPE, Name: TABLE}
+
+	set, err := nftConn.GetSetByName(filter, PL_SPLIT_SUBNETS_SET)
+	if err != nil || set == nil {
+		return log.ErrorFE("error GetSetByName(filter, %s): %w", PL_SPLIT_SUBNETS_SET, err)
+	}
+	nftConn.FlushSet(set)
+	if elements := splitTunnelSubnetsSetElements(splitTunnelSubnets); len(elements) > 0 {
+		if err = nftConn.SetAddElements(set, elements); err != nil {
+			return log.ErrorFE("error adding elements to set %s: %w", PL_SPLIT_SUBNETS_SET, err)
+		}
+	}
+
+	if err := nftConn.Flush(); err != nil {
+		return log.ErrorFE("implOnChangeSplitTunnelSubnetsNft - error nft flush: %w", err)
+	}
+
+	return nil
+}
+
 func implTotalShieldApplyNft(totalShieldNewState bool) (err error) {
 	fwLinuxNftablesMutex.Lock()
 	defer fwLinuxNftablesMutex.Unlock()
@@ -1567,3 +1567,11 @@ func implGetFirewallBackgroundMonitors() (monitors []*srvhelpers.ServiceBackgrou
 func DisableCoexistenceWithOtherVpns() error {
 	return nil
 }
+
+func implOnChangeSplitTunnelDomains() error {
+	return nil // per-domain split tunnelling is not implemented for this platform
+}
+
+func implOnChangeSplitTunnelSubnets() error {
+	return nil // per-CIDR split tunnelling is not implemented for this platform
+}
@@ -0,0 +1,223 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+// Package history implements a bounded on-disk journal of connection-related events
+// (VPN state transitions, auto-connect decisions, firewall changes, pause/resume, healthchecks...).
+// Events are stored as JSON lines; when the journal file exceeds the size limit it is moved to a single backup file.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/logger"
+)
+
+var log *logger.Logger
+
+func init() {
+	log = logger.NewLogger("histry")
+}
+
+// EventType - type of the history event
+type EventType string
+
+const (
+	EventVpnState     EventType = "vpn_state"    // VPN state transition
+	EventDisconnected EventType = "disconnected" // VPN disconnected (contains connection duration and disconnection reason)
+	EventAutoConnect  EventType = "autoconnect"  // decision of the automatic connection manager
+	EventFirewall     EventType = "firewall"     // firewall (kill-switch) enabled/disabled
+	EventPause        EventType = "pause"        // connection paused
+	EventResume       EventType = "resume"       // connection resumed
+	EventHealthcheck  EventType = "healthcheck"  // healthcheck failed (and the action taken)
+	EventSchedule     EventType = "schedule"     // action of the schedule rule
+	EventFailover     EventType = "failover"     // connection quality thresholds crossed: reconnected to another server
+	EventMtu          EventType = "mtu"          // MTU of the WireGuard interface changed by path MTU discovery
+	EventTransport    EventType = "transport"    // transport attempt in automatic transport mode
+)
+
+// Event - single history record
+type Event struct {
+	Time    time.Time         `json:"time"`
+	Type    EventType         `json:"type"`
+	Message string            `json:"msg,omitempty"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// MaxFileSize - when the journal file exceeds this size, it is moved to the backup file ('<file>.1')
+const MaxFileSize int64 = 1024 * 1024
+
+var (
+	mutex       sync.Mutex
+	historyFile string
+)
+
+// Init initializes the journal. Empty file path disables the journal.
+func Init(file string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	historyFile = file
+}
+
+func backupFile(file string) string {
+	return file + ".1"
+}
+
+// Add saves new event into the journal.
+// 'keyvals' are the additional event fields: key1, value1, key2, value2 ... (empty values are ignored)
+func Add(eventType EventType, message string, keyvals ...string) {
+	evt := Event{Time: time.Now(), Type: eventType, Message: message}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i+1] == "" {
+			continue
+		}
+		if evt.Fields == nil {
+			evt.Fields = make(map[string]string)
+		}
+		evt.Fields[keyvals[i]] = keyvals[i+1]
+	}
+
+	if err := write(evt); err != nil {
+		log.Warning(fmt.Sprintf("failed to save history event: %v", err))
+	}
+}
+
+func write(evt Event) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if historyFile == "" {
+		return nil
+	}
+
+	if fi, err := os.Stat(historyFile); err == nil && fi.Size()+int64(len(data)) >= MaxFileSize {
+		if err := os.Rename(historyFile, backupFile(historyFile)); err != nil {
+			return fmt.Errorf("failed to rotate history file: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(historyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Query returns events in the time range [from, to] (zero time - no limit) ordered by time.
+// If 'types' is not empty - only events of specified types are returned.
+// If maxCount > 0 - only the latest 'maxCount' events are returned.
+func Query(from, to time.Time, types []EventType, maxCount int) ([]Event, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if historyFile == "" {
+		return nil, fmt.Errorf("connection history is not available")
+	}
+
+	typesMap := make(map[EventType]struct{}, len(types))
+	for _, t := range types {
+		typesMap[t] = struct{}{}
+	}
+
+	ret := make([]Event, 0)
+	for _, file := range []string{backupFile(historyFile), historyFile} {
+		events, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			if !from.IsZero() && e.Time.Before(from) {
+				continue
+			}
+			if !to.IsZero() && e.Time.After(to) {
+				continue
+			}
+			if len(typesMap) > 0 {
+				if _, ok := typesMap[e.Type]; !ok {
+					continue
+				}
+			}
+			ret = append(ret, e)
+		}
+	}
+
+	if maxCount > 0 && len(ret) > maxCount {
+		ret = ret[len(ret)-maxCount:]
+	}
+	return ret, nil
+}
+
+func readFile(file string) ([]Event, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var ret []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), int(MaxFileSize))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			continue // skip broken records (e.g. partially written line)
+		}
+		ret = append(ret, e)
+	}
+	return ret, scanner.Err()
+}
+
+// ParseEventTypes parses comma-separated list of event types
+func ParseEventTypes(s string) ([]EventType, error) {
+	var ret []EventType
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		et := EventType(t)
+		switch et {
+		case EventVpnState, EventDisconnected, EventAutoConnect, EventFirewall, EventPause, EventResume, EventHealthcheck, EventSchedule, EventFailover, EventMtu, EventTransport:
+			ret = append(ret, et)
+		default:
+			return nil, fmt.Errorf("unknown history event type '%s'", t)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,205 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func initTestJournal(t *testing.T) string {
+	file := filepath.Join(t.TempDir(), "history.log")
+	Init(file)
+	t.Cleanup(func() { Init("") })
+	return file
+}
+
+func writeEvents(t *testing.T, file string, events ...Event) {
+	var sb strings.Builder
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb.Write(data)
+		sb.WriteString("\n")
+	}
+	if err := os.WriteFile(file, []byte(sb.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func messages(events []Event) []string {
+	ret := make([]string, 0, len(events))
+	for _, e := range events {
+		ret = append(ret, e.Message)
+	}
+	return ret
+}
+
+func TestAddRotation(t *testing.T) {
+	file := initTestJournal(t)
+
+	bigMessage := strings.Repeat("x", int(MaxFileSize)/4)
+	Add(EventVpnState, "first", "state", "CONNECTED", "empty", "")
+	for i := 0; i < 3; i++ {
+		Add(EventVpnState, bigMessage)
+	}
+	if _, err := os.Stat(backupFile(file)); !os.IsNotExist(err) {
+		t.Fatalf("backup file must not exist before the size limit is reached (err: %v)", err)
+	}
+
+	Add(EventFirewall, bigMessage) // exceeds the limit: the journal is moved to '.1'
+	fi, err := os.Stat(backupFile(file))
+	if err != nil {
+		t.Fatalf("backup file not created: %v", err)
+	}
+	if fi.Size() >= MaxFileSize {
+		t.Errorf("backup file size %d exceeds the limit %d", fi.Size(), MaxFileSize)
+	}
+	if events, err := readFile(file); err != nil || len(events) != 1 || events[0].Type != EventFirewall {
+		t.Fatalf("journal file must contain only the latest event (events: %d, err: %v)", len(events), err)
+	}
+
+	events, err := Query(time.Time{}, time.Time{}, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 5 || events[0].Message != "first" || events[4].Type != EventFirewall {
+		t.Fatalf("unexpected events after rotation: %d events, first %q, last %q", len(events), events[0].Message, events[len(events)-1].Type)
+	}
+	if want := map[string]string{"state": "CONNECTED"}; !reflect.DeepEqual(events[0].Fields, want) {
+		t.Errorf("Fields = %v, want %v (empty values must be ignored)", events[0].Fields, want)
+	}
+
+	// the next rotations replace the backup file: the oldest events are lost
+	for i := 0; i < 6; i++ {
+		Add(EventVpnState, bigMessage)
+	}
+	events, err = Query(time.Time{}, time.Time{}, []EventType{EventFirewall}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("events of the replaced backup file must be removed; got %d events", len(events))
+	}
+}
+
+func TestQuery(t *testing.T) {
+	file := initTestJournal(t)
+
+	t0 := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return t0.Add(time.Duration(minutes) * time.Minute) }
+
+	writeEvents(t, backupFile(file),
+		Event{Time: at(0), Type: EventVpnState, Message: "b0"},
+		Event{Time: at(1), Type: EventFirewall, Message: "b1"},
+		Event{Time: at(2), Type: EventPause, Message: "b2"},
+	)
+	writeEvents(t, file,
+		Event{Time: at(3), Type: EventResume, Message: "m3"},
+		Event{Time: at(4), Type: EventVpnState, Message: "m4"},
+		Event{Time: at(5), Type: EventFirewall, Message: "m5"},
+	)
+	// broken (partially written) record is skipped
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2025-01-01T10:06:00Z","type":"vpn`)
+	f.Close()
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		types    []EventType
+		maxCount int
+		want     []string
+	}{
+		{"all", time.Time{}, time.Time{}, nil, 0, []string{"b0", "b1", "b2", "m3", "m4", "m5"}},
+		{"from", at(2), time.Time{}, nil, 0, []string{"b2", "m3", "m4", "m5"}},
+		{"to", time.Time{}, at(3), nil, 0, []string{"b0", "b1", "b2", "m3"}},
+		{"range", at(1), at(4), nil, 0, []string{"b1", "b2", "m3", "m4"}},
+		{"types", time.Time{}, time.Time{}, []EventType{EventFirewall, EventVpnState}, 0, []string{"b0", "b1", "m4", "m5"}},
+		{"max count", time.Time{}, time.Time{}, nil, 2, []string{"m4", "m5"}},
+		{"max count across files", time.Time{}, time.Time{}, []EventType{EventFirewall}, 2, []string{"b1", "m5"}},
+		{"max count greater than result", at(4), time.Time{}, nil, 10, []string{"m4", "m5"}},
+		{"nothing matches", at(10), time.Time{}, nil, 0, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := Query(tt.from, tt.to, tt.types, tt.maxCount)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := messages(events); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryNotInitialized(t *testing.T) {
+	Init("")
+	if _, err := Query(time.Time{}, time.Time{}, nil, 0); err == nil {
+		t.Fatal("expected error when the journal is not initialized")
+	}
+	Add(EventVpnState, "ignored") // must not fail when the journal is disabled
+}
+
+func TestParseEventTypes(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []EventType
+		wantErr string
+	}{
+		{"", nil, ""},
+		{" , ", nil, ""},
+		{"vpn_state", []EventType{EventVpnState}, ""},
+		{"vpn_state, firewall,,transport ", []EventType{EventVpnState, EventFirewall, EventTransport}, ""},
+		{"vpn_state,unknown", nil, "unknown history event type 'unknown'"},
+		{"VPN_STATE", nil, "unknown history event type 'VPN_STATE'"},
+		{"vpn state", nil, "unknown history event type 'vpn state'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseEventTypes(tt.in)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseEventTypes(%q) error = %v, want error containing %q", tt.in, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseEventTypes(%q) unexpected error: %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseEventTypes(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -83,7 +83,11 @@ type IServiceEventsReceiver interface {
 	OnVpnStateChanged_SaveStateEarly(state vpn.StateInfo, saveAndProcess bool) // Save the VPN state. If saveAndProcess==true, also call OnVpnStateChanged_ProcessSavedState()
 	OnVpnStateChanged_ProcessSavedState()                                      // Process the last saved VPN state.
 	OnVpnPauseChanged()
+	OnPreferencesChanged() // preferences were changed not by a client request (e.g. by the headless configuration file)
 	NotifyClientsVpnConnecting()
+	OnConnectionFailover(info service_types.ConnectionFailoverInfo)     // the connection-quality monitor is reconnecting to another server
+	OnWireGuardMtuChanged()                                             // MTU of the WireGuard interface changed by path MTU discovery
+	OnAutoTransportAttempt(info service_types.AutoTransportAttemptInfo) // transport attempt in automatic transport mode
 
 	// called by a service when new connection is required (e.g. requested by 'trusted-wifi' functionality or 'auto-connect' on launch)
 	RegisterConnectionRequest(params service_types.ConnectionParams) error
@@ -0,0 +1,815 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+// Package managedcfg implements the headless daemon configuration file (declarative provisioning).
+//
+// The file is in YAML format. Only the keys defined in the file are managed: they are applied to the daemon
+// preferences at startup and on SIGHUP. When 'locked: true' - clients are not allowed to change the managed keys.
+//
+// Example:
+//
+//	version: 1
+//	locked: true
+//	firewall:
+//	  persistent: true
+//	  allow_lan: true
+//	  user_exceptions: ["192.168.1.0/24"]
+//	total_shield:
+//	  enabled: false
+//	  apps: ["/usr/bin/firefox"]
+//	  domains:
+//	    - domain: "*.corp.example.com"
+//	    - domain: "streaming.example.net"
+//	      bypass: true
+//	autoconnect:
+//	  on_launch: true
+//	  on_launch_daemon: true
+//	wifi:
+//	  trusted_networks_control: true
+//	  networks:
+//	    - ssid: "Office"
+//	      trusted: true
+//	dns:
+//	  servers: ["1.1.1.1"]
+//	  encryption: doh
+//	  template: "https://cloudflare-dns.com/dns-query"
+//	connection:
+//	  port: 51820
+//	  mtu: 1380
+//	unix_socket:
+//	  allowed_uids: [1000]
+//	  allowed_gids: [27]
+package managedcfg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/logger"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/splittun"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+var log *logger.Logger
+
+func init() {
+	log = logger.NewLogger("mngcfg")
+}
+
+// CurrentVersion - the latest supported version of the configuration file format
+const CurrentVersion = 1
+
+// Config - headless daemon configuration. Nil values are not managed.
+type Config struct {
+	Version      int                   `yaml:"version"`
+	Locked       bool                  `yaml:"locked"` // when 'true' - clients are not allowed to change the managed keys
+	Firewall     *FirewallConfig       `yaml:"firewall"`
+	TotalShield  *TotalShieldConfig    `yaml:"total_shield"`
+	Autoconnect  *AutoconnectConfig    `yaml:"autoconnect"`
+	WiFi         *WiFiConfig           `yaml:"wifi"`
+	NetworkRules *[]NetworkRuleConfig  `yaml:"network_rules"`
+	Schedule     *[]ScheduleRuleConfig `yaml:"schedule"`
+	Quality      *QualityConfig        `yaml:"connection_quality"`
+	Dns          *DnsConfig            `yaml:"dns"`
+	Connection   *ConnectionConfig     `yaml:"connection"`
+	UnixSocket   *UnixSocketConfig     `yaml:"unix_socket"`
+}
+
+type FirewallConfig struct {
+	Persistent        *bool     `yaml:"persistent"`
+	AllowLan          *bool     `yaml:"allow_lan"`
+	AllowLanMulticast *bool     `yaml:"allow_lan_multicast"`
+	AllowApiServers   *bool     `yaml:"allow_api_servers"`
+	UserExceptions    *[]string `yaml:"user_exceptions"` // IP addresses (masks) in format: x.x.x.x[/xx]
+}
+
+type TotalShieldConfig struct {
+	Enabled        *bool               `yaml:"enabled"`
+	AppWhitelist   *bool               `yaml:"app_whitelist"`
+	Apps           *[]string           `yaml:"apps"`
+	Domains        *[]DomainRuleConfig `yaml:"domains"`
+	IncludeSubnets *[]string           `yaml:"include_subnets"`
+	ExcludeSubnets *[]string           `yaml:"exclude_subnets"`
+}
+
+// DomainRuleConfig - per-domain split tunnelling rule
+type DomainRuleConfig struct {
+	Domain string `yaml:"domain"`
+	Bypass bool   `yaml:"bypass"` // false - route through the VPN tunnel; true - send directly
+}
+
+type AutoconnectConfig struct {
+	OnLaunch       *bool   `yaml:"on_launch"`
+	OnLaunchDaemon *bool   `yaml:"on_launch_daemon"`
+	Profile        *string `yaml:"profile"` // connection profile used for auto-connection
+}
+
+// QualityConfig - thresholds of the WireGuard connection-quality monitor (0 - the check is disabled)
+type QualityConfig struct {
+	Failover            *bool `yaml:"failover"`
+	CheckInterval       *int  `yaml:"check_interval"`    // seconds
+	MaxHandshakeAge     *int  `yaml:"max_handshake_age"` // seconds
+	MaxRtt              *int  `yaml:"max_rtt"`           // milliseconds
+	MaxPacketLoss       *int  `yaml:"max_packet_loss"`   // percent
+	StallTimeout        *int  `yaml:"stall_timeout"`     // seconds
+	BadChecksToFailover *int  `yaml:"bad_checks_to_failover"`
+}
+
+// apply sets the defined values to the thresholds
+func (q QualityConfig) apply(a *applier, p *preferences.ConnectionQualityParams) {
+	setValue(a, "connection_quality.failover", q.Failover, &p.FailoverEnabled)
+	setValue(a, "connection_quality.check_interval", q.CheckInterval, &p.CheckIntervalSec)
+	setValue(a, "connection_quality.max_handshake_age", q.MaxHandshakeAge, &p.MaxHandshakeAgeSec)
+	setValue(a, "connection_quality.max_rtt", q.MaxRtt, &p.MaxRttMs)
+	setValue(a, "connection_quality.max_packet_loss", q.MaxPacketLoss, &p.MaxPacketLossPercent)
+	setValue(a, "connection_quality.stall_timeout", q.StallTimeout, &p.StallTimeoutSec)
+	setValue(a, "connection_quality.bad_checks_to_failover", q.BadChecksToFailover, &p.BadChecksToFailover)
+}
+
+type WiFiNetworkConfig struct {
+	SSID    string `yaml:"ssid"`
+	Trusted bool   `yaml:"trusted"`
+	Profile string `yaml:"profile"` // connection profile used to connect VPN when joining the network
+
+	Actions *WiFiNetworkActionsConfig `yaml:"actions"` // actions for this network (override the common 'wifi.actions')
+}
+
+type WiFiNetworkActionsConfig struct {
+	ConnectVpn     *bool    `yaml:"connect_vpn"`
+	EnableFirewall *bool    `yaml:"enable_firewall"`
+	BlockLan       *bool    `yaml:"block_lan"`
+	TotalShield    *bool    `yaml:"total_shield"`
+	Location       string   `yaml:"location"` // gateway ID or country code
+	Protocol       string   `yaml:"protocol"` // "wireguard", "wireguard_v2ray_quic", "wireguard_v2ray_tcp" or "openvpn"
+	DnsServers     []string `yaml:"dns_servers"`
+}
+
+func (n WiFiNetworkConfig) networkActions() *preferences.WiFiNetworkActions {
+	if n.Actions == nil {
+		return nil
+	}
+	ret := n.Actions.toPreferences()
+	if ret.IsEmpty() {
+		return nil
+	}
+	return &ret
+}
+
+func (a WiFiNetworkActionsConfig) toPreferences() preferences.WiFiNetworkActions {
+	return preferences.WiFiNetworkActions{
+		ConnectVpn:     a.ConnectVpn,
+		EnableFirewall: a.EnableFirewall,
+		BlockLan:       a.BlockLan,
+		TotalShield:    a.TotalShield,
+		Location:       strings.TrimSpace(a.Location),
+		Protocol:       strings.ToLower(strings.TrimSpace(a.Protocol)),
+		DnsServers:     a.DnsServers,
+	}
+}
+
+// NetworkRuleConfig - network-condition rule (all defined conditions must match)
+type NetworkRuleConfig struct {
+	Name       string `yaml:"name"`
+	Disabled   bool   `yaml:"disabled"`
+	Conditions struct {
+		InterfaceName string `yaml:"interface_name"` // wildcards allowed (e.g. "enx*")
+		InterfaceType string `yaml:"interface_type"` // "ethernet", "wifi" or "other"
+		GatewayIP     string `yaml:"gateway_ip"`
+		GatewayMAC    string `yaml:"gateway_mac"`
+		DnsSuffix     string `yaml:"dns_suffix"`
+		ReachableHost string `yaml:"reachable_host"` // "host:port"
+	} `yaml:"conditions"`
+	Profile string                   `yaml:"profile"`
+	Actions WiFiNetworkActionsConfig `yaml:"actions"`
+}
+
+func (r NetworkRuleConfig) toPreferences() preferences.NetworkRule {
+	return preferences.NetworkRule{
+		Name:     strings.TrimSpace(r.Name),
+		Disabled: r.Disabled,
+		Conditions: preferences.NetworkRuleConditions{
+			InterfaceName: strings.TrimSpace(r.Conditions.InterfaceName),
+			InterfaceType: strings.ToLower(strings.TrimSpace(r.Conditions.InterfaceType)),
+			GatewayIP:     strings.TrimSpace(r.Conditions.GatewayIP),
+			GatewayMAC:    strings.ToLower(strings.TrimSpace(r.Conditions.GatewayMAC)),
+			DnsSuffix:     strings.TrimSpace(r.Conditions.DnsSuffix),
+			ReachableHost: strings.TrimSpace(r.Conditions.ReachableHost),
+		},
+		Profile: strings.TrimSpace(r.Profile),
+		Actions: r.Actions.toPreferences(),
+	}
+}
+
+// ScheduleRuleConfig - time-of-day rule (e.g. "VPN must be connected 08:00-18:00 on weekdays")
+type ScheduleRuleConfig struct {
+	Name     string   `yaml:"name"`
+	Disabled bool     `yaml:"disabled"`
+	Days     []string `yaml:"days"`   // "mon", "tue" ... "sun" (empty - every day)
+	Start    string   `yaml:"start"`  // "HH:MM"
+	End      string   `yaml:"end"`    // "HH:MM" (empty - one-shot action at the start time)
+	Action   string   `yaml:"action"` // "connect", "disconnect" or "resume"
+	Profile  string   `yaml:"profile"`
+}
+
+func (r ScheduleRuleConfig) toPreferences() preferences.ScheduleRule {
+	return preferences.ScheduleRule{
+		Name:     strings.TrimSpace(r.Name),
+		Disabled: r.Disabled,
+		Days:     r.Days,
+		Start:    strings.TrimSpace(r.Start),
+		End:      strings.TrimSpace(r.End),
+		Action:   preferences.ScheduleAction(strings.ToLower(strings.TrimSpace(r.Action))),
+		Profile:  strings.TrimSpace(r.Profile),
+	}
+}
+
+type WiFiActionsConfig struct {
+	UnTrustedConnectVpn     *bool `yaml:"untrusted_connect_vpn"`
+	UnTrustedEnableFirewall *bool `yaml:"untrusted_enable_firewall"`
+	UnTrustedBlockLan       *bool `yaml:"untrusted_block_lan"`
+	TrustedDisconnectVpn    *bool `yaml:"trusted_disconnect_vpn"`
+	TrustedDisableFirewall  *bool `yaml:"trusted_disable_firewall"`
+}
+
+type WiFiConfig struct {
+	CanApplyInBackground        *bool                `yaml:"can_apply_in_background"`
+	ConnectVPNOnInsecureNetwork *bool                `yaml:"connect_on_insecure_network"`
+	TrustedNetworksControl      *bool                `yaml:"trusted_networks_control"`
+	DefaultTrustStatus          *string              `yaml:"default_trust_status"` // "trusted", "untrusted" or "none"
+	Networks                    *[]WiFiNetworkConfig `yaml:"networks"`
+	Actions                     *WiFiActionsConfig   `yaml:"actions"`
+}
+
+type AntiTrackerConfig struct {
+	Enabled   *bool   `yaml:"enabled"`
+	Hardcore  *bool   `yaml:"hardcore"`
+	Blocklist *string `yaml:"blocklist"`
+}
+
+type DnsConfig struct {
+	Servers     *[]string          `yaml:"servers"`
+	Encryption  *string            `yaml:"encryption"` // "none", "doh" or "dot"
+	Template    *string            `yaml:"template"`   // DoH/DoT template URI
+	AntiTracker *AntiTrackerConfig `yaml:"antitracker"`
+}
+
+type ConnectionConfig struct {
+	VpnType *string `yaml:"vpn_type"` // "wireguard" or "openvpn"
+	IPv6    *bool   `yaml:"ipv6"`
+	Port    *int    `yaml:"port"` // WireGuard port
+	Mtu     *int    `yaml:"mtu"`  // WireGuard MTU (0 - default)
+}
+
+// UnixSocketConfig - access to the daemon Unix-domain socket (Linux, macOS).
+// The allow-lists are checked for each new client connection; 'disabled' takes effect after the daemon restart.
+type UnixSocketConfig struct {
+	Disabled    *bool     `yaml:"disabled"`
+	AllowedUIDs *[]uint32 `yaml:"allowed_uids"` // users allowed to connect (root is always allowed)
+	AllowedGIDs *[]uint32 `yaml:"allowed_gids"` // members of the groups (primary or supplementary) allowed to connect
+}
+
+// Change - description of a single change of a managed key
+type Change struct {
+	Key      string
+	OldValue string
+	NewValue string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Key, c.OldValue, c.NewValue)
+}
+
+// Status - information about the configuration file
+type Status struct {
+	File        string
+	IsLoaded    bool // configuration file exists and it is applied
+	Locked      bool
+	ManagedKeys []string
+	Changes     []Change // changes applied (or to be applied, in dry-run mode)
+}
+
+// Load reads and validates the configuration file.
+// Returns (nil, nil) if the file does not exist.
+func Load(file string) (*Config, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates the configuration data
+func Parse(data []byte) (*Config, error) {
+	cfg := &Config{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true) // unknown keys are errors
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks the configuration values. All errors are reported at once.
+func (c *Config) Validate() error {
+	var errs []string
+	addErr := func(key, format string, a ...any) {
+		errs = append(errs, fmt.Sprintf("%s: %s", key, fmt.Sprintf(format, a...)))
+	}
+
+	if c.Version != CurrentVersion {
+		addErr("version", "unsupported version %d (expected %d)", c.Version, CurrentVersion)
+	}
+
+	if c.Firewall != nil && c.Firewall.UserExceptions != nil {
+		for _, e := range *c.Firewall.UserExceptions {
+			if !isIPOrCIDR(e) {
+				addErr("firewall.user_exceptions", "bad IP address or mask '%s'", e)
+			}
+		}
+	}
+
+	if c.TotalShield != nil && c.TotalShield.Apps != nil {
+		for _, app := range *c.TotalShield.Apps {
+			if strings.TrimSpace(app) == "" {
+				addErr("total_shield.apps", "empty application path")
+			}
+		}
+	}
+
+	if c.TotalShield != nil && c.TotalShield.Domains != nil {
+		if len(*c.TotalShield.Domains) > splittun.MaxDomainRules {
+			addErr("total_shield.domains", "too many domain rules (maximum %d)", splittun.MaxDomainRules)
+		}
+		for _, d := range *c.TotalShield.Domains {
+			if _, err := splittun.NormalizeDomain(d.Domain); err != nil {
+				addErr("total_shield.domains", "%v", err)
+			}
+		}
+	}
+
+	if t := c.TotalShield; t != nil && (t.IncludeSubnets != nil || t.ExcludeSubnets != nil) {
+		if _, _, err := splittun.ValidateSubnets(derefStrings(t.IncludeSubnets), derefStrings(t.ExcludeSubnets), splittun.TunnelAddresses{}); err != nil {
+			addErr("total_shield.include_subnets/exclude_subnets", "%v", err)
+		}
+	}
+
+	if w := c.WiFi; w != nil {
+		if w.DefaultTrustStatus != nil {
+			if _, err := parseTrustStatus(*w.DefaultTrustStatus); err != nil {
+				addErr("wifi.default_trust_status", "%v", err)
+			}
+		}
+		if w.Networks != nil {
+			ssids := make(map[string]struct{})
+			for _, n := range *w.Networks {
+				if n.SSID == "" {
+					addErr("wifi.networks", "empty SSID")
+					continue
+				}
+				if _, ok := ssids[n.SSID]; ok {
+					addErr("wifi.networks", "duplicate SSID '%s'", n.SSID)
+				}
+				if len(strings.TrimSpace(n.Profile)) > 0 {
+					if _, err := preferences.NormalizeConnectionProfileName(n.Profile); err != nil {
+						addErr("wifi.networks", "network '%s': %v", n.SSID, err)
+					}
+				}
+				if act := n.networkActions(); act != nil {
+					if err := act.Validate(); err != nil {
+						addErr("wifi.networks", "network '%s' actions: %v", n.SSID, err)
+					}
+				}
+				ssids[n.SSID] = struct{}{}
+			}
+		}
+	}
+
+	if c.NetworkRules != nil {
+		if len(*c.NetworkRules) > preferences.MaxNetworkRules {
+			addErr("network_rules", "too many rules (maximum %d)", preferences.MaxNetworkRules)
+		}
+		names := make(map[string]struct{})
+		for _, rc := range *c.NetworkRules {
+			r := rc.toPreferences()
+			if r.Name == "" {
+				addErr("network_rules", "empty rule name")
+				continue
+			}
+			if _, ok := names[strings.ToLower(r.Name)]; ok {
+				addErr("network_rules", "duplicate rule '%s'", r.Name)
+			}
+			names[strings.ToLower(r.Name)] = struct{}{}
+			if err := r.Conditions.Validate(); err != nil {
+				addErr("network_rules", "rule '%s': %v", r.Name, err)
+			}
+			if err := r.Actions.Validate(); err != nil {
+				addErr("network_rules", "rule '%s' actions: %v", r.Name, err)
+			}
+			if len(r.Profile) > 0 {
+				if _, err := preferences.NormalizeConnectionProfileName(r.Profile); err != nil {
+					addErr("network_rules", "rule '%s': %v", r.Name, err)
+				}
+			}
+		}
+	}
+
+	if c.Schedule != nil {
+		if len(*c.Schedule) > preferences.MaxScheduleRules {
+			addErr("schedule", "too many rules (maximum %d)", preferences.MaxScheduleRules)
+		}
+		names := make(map[string]struct{})
+		for _, rc := range *c.Schedule {
+			r := rc.toPreferences()
+			if r.Name == "" {
+				addErr("schedule", "empty rule name")
+				continue
+			}
+			if _, ok := names[strings.ToLower(r.Name)]; ok {
+				addErr("schedule", "duplicate rule '%s'", r.Name)
+			}
+			names[strings.ToLower(r.Name)] = struct{}{}
+			if err := r.Validate(); err != nil {
+				addErr("schedule", "rule '%s': %v", r.Name, err)
+			}
+			if len(r.Profile) > 0 {
+				if _, err := preferences.NormalizeConnectionProfileName(r.Profile); err != nil {
+					addErr("schedule", "rule '%s': %v", r.Name, err)
+				}
+			}
+		}
+	}
+
+	if c.Quality != nil {
+		// check the defined values in combination with the default ones
+		params := preferences.ConnectionQualityParamsCreate()
+		c.Quality.apply(&applier{}, &params)
+		if err := params.Validate(); err != nil {
+			addErr("connection_quality", "%v", err)
+		}
+	}
+
+	if ac := c.Autoconnect; ac != nil && ac.Profile != nil && len(strings.TrimSpace(*ac.Profile)) > 0 {
+		if _, err := preferences.NormalizeConnectionProfileName(*ac.Profile); err != nil {
+			addErr("autoconnect.profile", "%v", err)
+		}
+	}
+
+	if d := c.Dns; d != nil {
+		if d.Servers != nil {
+			for _, s := range *d.Servers {
+				if net.ParseIP(strings.TrimSpace(s)) == nil {
+					addErr("dns.servers", "bad IP address '%s'", s)
+				}
+			}
+		}
+		if d.Encryption != nil {
+			enc, err := parseDnsEncryption(*d.Encryption)
+			if err != nil {
+				addErr("dns.encryption", "%v", err)
+			} else if enc != dns.EncryptionNone && (d.Template == nil || *d.Template == "") {
+				addErr("dns.template", "required for encrypted DNS")
+			}
+		}
+	}
+
+	if cn := c.Connection; cn != nil {
+		if cn.VpnType != nil {
+			if _, err := parseVpnType(*cn.VpnType); err != nil {
+				addErr("connection.vpn_type", "%v", err)
+			}
+		}
+		if cn.Port != nil && (*cn.Port <= 0 || *cn.Port > 65535) {
+			addErr("connection.port", "bad port number %d", *cn.Port)
+		}
+		if cn.Mtu != nil && *cn.Mtu != 0 && (*cn.Mtu < 1280 || *cn.Mtu > 65535) {
+			addErr("connection.mtu", "MTU must be in range 1280-65535 (or 0 for default)")
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("configuration validation failed:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// ManagedKeys returns sorted list of the keys managed by the configuration
+func (c *Config) ManagedKeys() []string {
+	if c == nil {
+		return nil
+	}
+	var prefs preferences.Preferences
+	a := &applier{}
+	c.apply(a, &prefs)
+	sort.Strings(a.keys)
+	return a.keys
+}
+
+// IsKeyLocked returns 'true' when the configuration is locked and the key is managed.
+// The key can be a prefix of managed keys (e.g. "wifi" matches all "wifi.*" keys).
+func (c *Config) IsKeyLocked(key string) bool {
+	if c == nil || !c.Locked {
+		return false
+	}
+	for _, k := range c.ManagedKeys() {
+		if k == key || strings.HasPrefix(k, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply applies the managed values to the preferences. Returns the list of changes.
+func (c *Config) Apply(prefs *preferences.Preferences) []Change {
+	if c == nil {
+		return nil
+	}
+	a := &applier{}
+	c.apply(a, prefs)
+	return a.changes
+}
+
+// ApplyToConnectionParams applies the managed connection and DNS values to the connection parameters
+func (c *Config) ApplyToConnectionParams(params *types.ConnectionParams) {
+	if c == nil {
+		return
+	}
+	c.applyConnectionParams(&applier{}, params)
+}
+
+func (c *Config) apply(a *applier, prefs *preferences.Preferences) {
+	if f := c.Firewall; f != nil {
+		setValue(a, "firewall.persistent", f.Persistent, &prefs.IsFwPersistent)
+		setValue(a, "firewall.allow_lan", f.AllowLan, &prefs.IsFwAllowLAN)
+		setValue(a, "firewall.allow_lan_multicast", f.AllowLanMulticast, &prefs.IsFwAllowLANMulticast)
+		setValue(a, "firewall.allow_api_servers", f.AllowApiServers, &prefs.IsFwAllowApiServers)
+		if f.UserExceptions != nil {
+			exceptions := strings.Join(*f.UserExceptions, ",")
+			setValue(a, "firewall.user_exceptions", &exceptions, &prefs.FwUserExceptions)
+		}
+	}
+
+	if t := c.TotalShield; t != nil {
+		setValue(a, "total_shield.enabled", t.Enabled, &prefs.IsTotalShieldOn)
+		setValue(a, "total_shield.app_whitelist", t.AppWhitelist, &prefs.EnableAppWhitelist)
+		setValue(a, "total_shield.apps", t.Apps, &prefs.SplitTunnelApps)
+		if t.Domains != nil {
+			rules := make([]splittun.DomainRule, 0, len(*t.Domains))
+			for _, d := range *t.Domains {
+				domain, _ := splittun.NormalizeDomain(d.Domain) // already validated
+				rules = append(rules, splittun.DomainRule{Domain: domain, Bypass: d.Bypass})
+			}
+			setValue(a, "total_shield.domains", &rules, &prefs.SplitTunnelDomains)
+		}
+		if t.IncludeSubnets != nil || t.ExcludeSubnets != nil {
+			include, exclude, _ := splittun.ValidateSubnets(derefStrings(t.IncludeSubnets), derefStrings(t.ExcludeSubnets), splittun.TunnelAddresses{}) // already validated
+			if t.IncludeSubnets != nil {
+				setValue(a, "total_shield.include_subnets", &include, &prefs.SplitTunnelIncludeSubnets)
+			}
+			if t.ExcludeSubnets != nil {
+				setValue(a, "total_shield.exclude_subnets", &exclude, &prefs.SplitTunnelExcludeSubnets)
+			}
+		}
+	}
+
+	if ac := c.Autoconnect; ac != nil {
+		setValue(a, "autoconnect.on_launch", ac.OnLaunch, &prefs.IsAutoconnectOnLaunch)
+		setValue(a, "autoconnect.on_launch_daemon", ac.OnLaunchDaemon, &prefs.IsAutoconnectOnLaunchDaemon)
+		if ac.Profile != nil {
+			profile := strings.TrimSpace(*ac.Profile)
+			setValue(a, "autoconnect.profile", &profile, &prefs.AutoconnectProfile)
+		}
+	}
+
+	if w := c.WiFi; w != nil {
+		wp := &prefs.WiFiControl
+		setValue(a, "wifi.can_apply_in_background", w.CanApplyInBackground, &wp.CanApplyInBackground)
+		setValue(a, "wifi.connect_on_insecure_network", w.ConnectVPNOnInsecureNetwork, &wp.ConnectVPNOnInsecureNetwork)
+		setValue(a, "wifi.trusted_networks_control", w.TrustedNetworksControl, &wp.TrustedNetworksControl)
+		if w.DefaultTrustStatus != nil {
+			trustStatus, _ := parseTrustStatus(*w.DefaultTrustStatus)
+			setValue(a, "wifi.default_trust_status", &trustStatus, &wp.DefaultTrustStatusTrusted)
+		}
+		if w.Networks != nil {
+			networks := make([]preferences.WiFiNetwork, 0, len(*w.Networks))
+			for _, n := range *w.Networks {
+				networks = append(networks, preferences.WiFiNetwork{SSID: n.SSID, IsTrusted: n.Trusted, Profile: strings.TrimSpace(n.Profile), Actions: n.networkActions()})
+			}
+			setValue(a, "wifi.networks", &networks, &wp.Networks)
+		}
+		if act := w.Actions; act != nil {
+			setValue(a, "wifi.actions.untrusted_connect_vpn", act.UnTrustedConnectVpn, &wp.Actions.UnTrustedConnectVpn)
+			setValue(a, "wifi.actions.untrusted_enable_firewall", act.UnTrustedEnableFirewall, &wp.Actions.UnTrustedEnableFirewall)
+			setValue(a, "wifi.actions.untrusted_block_lan", act.UnTrustedBlockLan, &wp.Actions.UnTrustedBlockLan)
+			setValue(a, "wifi.actions.trusted_disconnect_vpn", act.TrustedDisconnectVpn, &wp.Actions.TrustedDisconnectVpn)
+			setValue(a, "wifi.actions.trusted_disable_firewall", act.TrustedDisableFirewall, &wp.Actions.TrustedDisableFirewall)
+		}
+	}
+
+	if c.NetworkRules != nil {
+		rules := make([]preferences.NetworkRule, 0, len(*c.NetworkRules))
+		for _, r := range *c.NetworkRules {
+			rules = append(rules, r.toPreferences())
+		}
+		setValue(a, "network_rules", &rules, &prefs.NetworkRules)
+	}
+
+	if c.Schedule != nil {
+		rules := make([]preferences.ScheduleRule, 0, len(*c.Schedule))
+		for _, r := range *c.Schedule {
+			rules = append(rules, r.toPreferences())
+		}
+		setValue(a, "schedule", &rules, &prefs.ScheduleRules)
+	}
+
+	if c.Quality != nil {
+		c.Quality.apply(a, &prefs.ConnectionQuality)
+	}
+
+	if u := c.UnixSocket; u != nil {
+		setValue(a, "unix_socket.disabled", u.Disabled, &prefs.IsUnixSocketDisabled)
+		setValue(a, "unix_socket.allowed_uids", u.AllowedUIDs, &prefs.UnixSocketAllowedUIDs)
+		setValue(a, "unix_socket.allowed_gids", u.AllowedGIDs, &prefs.UnixSocketAllowedGIDs)
+	}
+
+	c.applyConnectionParams(a, &prefs.LastConnectionParams)
+}
+
+func (c *Config) applyConnectionParams(a *applier, params *types.ConnectionParams) {
+	if d := c.Dns; d != nil {
+		if d.Servers != nil {
+			servers := make([]net.IP, 0, len(*d.Servers))
+			for _, s := range *d.Servers {
+				servers = append(servers, net.ParseIP(strings.TrimSpace(s)))
+			}
+			setValue(a, "dns.servers", &servers, &params.ManualDNS.DnsServers)
+		}
+		if d.Encryption != nil {
+			enc, _ := parseDnsEncryption(*d.Encryption)
+			setValue(a, "dns.encryption", &enc, &params.ManualDNS.Encryption)
+		}
+		setValue(a, "dns.template", d.Template, &params.ManualDNS.DohTemplate)
+		if at := d.AntiTracker; at != nil {
+			setValue(a, "dns.antitracker.enabled", at.Enabled, &params.Metadata.AntiTracker.Enabled)
+			setValue(a, "dns.antitracker.hardcore", at.Hardcore, &params.Metadata.AntiTracker.Hardcore)
+			setValue(a, "dns.antitracker.blocklist", at.Blocklist, &params.Metadata.AntiTracker.AntiTrackerBlockListName)
+		}
+	}
+
+	if cn := c.Connection; cn != nil {
+		if cn.VpnType != nil {
+			vpnType, _ := parseVpnType(*cn.VpnType)
+			setValue(a, "connection.vpn_type", &vpnType, &params.VpnType)
+		}
+		setValue(a, "connection.ipv6", cn.IPv6, &params.IPv6)
+		setValue(a, "connection.port", cn.Port, &params.WireGuardParameters.Port.Port)
+		setValue(a, "connection.mtu", cn.Mtu, &params.WireGuardParameters.Mtu)
+	}
+}
+
+// applier collects the managed keys and the changes
+type applier struct {
+	keys    []string
+	changes []Change
+}
+
+func setValue[T any](a *applier, key string, val *T, target *T) {
+	if val == nil {
+		return
+	}
+	a.keys = append(a.keys, key)
+	if isEqual(*val, *target) {
+		return
+	}
+	a.changes = append(a.changes, Change{Key: key, OldValue: valueToString(*target), NewValue: valueToString(*val)})
+	*target = *val
+}
+
+func isEqual(a, b any) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() == reflect.Slice && vb.Kind() == reflect.Slice && va.Len() == 0 && vb.Len() == 0 {
+		return true // nil and empty slices are equal
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func valueToString(v any) string {
+	switch val := v.(type) {
+	case *bool:
+		if val == nil {
+			return "none"
+		}
+		return fmt.Sprint(*val)
+	case dns.DnsEncryption:
+		return dnsEncryptionToString(val)
+	case vpn.Type:
+		return val.String()
+	case []net.IP:
+		ips := make([]string, 0, len(val))
+		for _, ip := range val {
+			ips = append(ips, ip.String())
+		}
+		return fmt.Sprintf("[%s]", strings.Join(ips, ","))
+	case string:
+		return fmt.Sprintf("'%s'", val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func derefStrings(v *[]string) []string {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func isIPOrCIDR(s string) bool {
+	s = strings.TrimSpace(s)
+	if _, _, err := net.ParseCIDR(s); err == nil {
+		return true
+	}
+	return net.ParseIP(s) != nil
+}
+
+func parseTrustStatus(s string) (*bool, error) {
+	switch strings.ToLower(s) {
+	case "trusted":
+		v := true
+		return &v, nil
+	case "untrusted":
+		v := false
+		return &v, nil
+	case "none", "":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unexpected value '%s' (expected: trusted, untrusted or none)", s)
+}
+
+func parseDnsEncryption(s string) (dns.DnsEncryption, error) {
+	switch strings.ToLower(s) {
+	case "none", "":
+		return dns.EncryptionNone, nil
+	case "doh":
+		return dns.EncryptionDnsOverHttps, nil
+	case "dot":
+		return dns.EncryptionDnsOverTls, nil
+	}
+	return dns.EncryptionNone, fmt.Errorf("unexpected value '%s' (expected: none, doh or dot)", s)
+}
+
+func dnsEncryptionToString(e dns.DnsEncryption) string {
+	switch e {
+	case dns.EncryptionDnsOverHttps:
+		return "doh"
+	case dns.EncryptionDnsOverTls:
+		return "dot"
+	default:
+		return "none"
+	}
+}
+
+func parseVpnType(s string) (vpn.Type, error) {
+	switch strings.ToLower(s) {
+	case "wireguard":
+		return vpn.WireGuard, nil
+	case "openvpn":
+		return vpn.OpenVPN, nil
+	}
+	return vpn.WireGuard, fmt.Errorf("unexpected value '%s' (expected: wireguard or openvpn)", s)
+}
@@ -0,0 +1,217 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package managedcfg
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string // substring of the expected error ("" - no error)
+	}{
+		{"minimal", "version: 1", ""},
+		{"full example", `
+version: 1
+locked: true
+firewall:
+  persistent: true
+  allow_lan: true
+  user_exceptions: ["192.168.1.0/24", "10.0.0.1"]
+total_shield:
+  enabled: false
+  apps: ["/usr/bin/firefox"]
+  domains:
+    - domain: "*.corp.example.com"
+    - domain: "streaming.example.net"
+      bypass: true
+autoconnect:
+  on_launch: true
+  profile: "home"
+wifi:
+  default_trust_status: untrusted
+  networks:
+    - ssid: "Office"
+      trusted: true
+dns:
+  servers: ["1.1.1.1"]
+  encryption: doh
+  template: "https://cloudflare-dns.com/dns-query"
+connection:
+  vpn_type: wireguard
+  port: 51820
+  mtu: 1380
+unix_socket:
+  allowed_uids: [1000]
+`, ""},
+		{"empty file", "", "unsupported version 0"},
+		{"unsupported version", "version: 2", "unsupported version 2"},
+		{"unknown key", "version: 1\nfirewal:\n  persistent: true", "field firewal not found"},
+		{"bad yaml", "version: [", "failed to parse"},
+		{"bad exception", "version: 1\nfirewall:\n  user_exceptions: [\"1.2.3\"]", "firewall.user_exceptions"},
+		{"empty app", "version: 1\ntotal_shield:\n  apps: [\" \"]", "total_shield.apps"},
+		{"bad domain", "version: 1\ntotal_shield:\n  domains:\n    - domain: \"bad domain\"", "total_shield.domains"},
+		{"wildcard in the middle", "version: 1\ntotal_shield:\n  domains:\n    - domain: \"a.*.example.com\"", "not supported"},
+		{"bad trust status", "version: 1\nwifi:\n  default_trust_status: maybe", "wifi.default_trust_status"},
+		{"network disables firewall", "version: 1\nwifi:\n  networks:\n    - ssid: a\n      actions:\n        enable_firewall: false", "disabling Firewall is not supported"},
+		{"duplicate ssid", "version: 1\nwifi:\n  networks:\n    - ssid: a\n    - ssid: a", "duplicate SSID"},
+		{"empty ssid", "version: 1\nwifi:\n  networks:\n    - trusted: true", "empty SSID"},
+		{"bad dns server", "version: 1\ndns:\n  servers: [\"dns.example.com\"]", "dns.servers"},
+		{"encryption without template", "version: 1\ndns:\n  encryption: dot", "dns.template"},
+		{"bad encryption", "version: 1\ndns:\n  encryption: quic", "dns.encryption"},
+		{"bad vpn type", "version: 1\nconnection:\n  vpn_type: ipsec", "connection.vpn_type"},
+		{"bad port", "version: 1\nconnection:\n  port: 70000", "connection.port"},
+		{"bad mtu", "version: 1\nconnection:\n  mtu: 500", "connection.mtu"},
+		{"default mtu", "version: 1\nconnection:\n  mtu: 0", ""},
+		{"bad schedule action", "version: 1\nschedule:\n  - name: r\n    start: \"08:00\"\n    action: sleep", "schedule"},
+		{"duplicate network rule", "version: 1\nnetwork_rules:\n  - name: a\n    conditions:\n      interface_type: wifi\n  - name: A\n    conditions:\n      interface_type: wifi", "duplicate rule"},
+		{"bad quality threshold", "version: 1\nconnection_quality:\n  check_interval: -1", "connection_quality"},
+		{"negative uid", "version: 1\nunix_socket:\n  allowed_uids: [-1]", "cannot unmarshal"},
+		{"all errors reported", "version: 1\nconnection:\n  port: 0\n  mtu: 1", "connection.mtu"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	cfg, err := Parse([]byte(`
+version: 1
+locked: true
+firewall:
+  persistent: true
+  user_exceptions: ["10.0.0.0/8", "192.168.1.1"]
+dns:
+  servers: ["1.1.1.1"]
+connection:
+  port: 2049
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var prefs preferences.Preferences
+	prefs.IsFwPersistent = true // already equal: not a change
+
+	changes := cfg.Apply(&prefs)
+	var changedKeys []string
+	for _, c := range changes {
+		changedKeys = append(changedKeys, c.Key)
+	}
+	wantChanged := []string{"firewall.user_exceptions", "dns.servers", "connection.port"}
+	if !reflect.DeepEqual(changedKeys, wantChanged) {
+		t.Errorf("changed keys = %v, want %v", changedKeys, wantChanged)
+	}
+
+	if prefs.FwUserExceptions != "10.0.0.0/8,192.168.1.1" {
+		t.Errorf("FwUserExceptions = %q", prefs.FwUserExceptions)
+	}
+	if got := prefs.LastConnectionParams.ManualDNS.DnsServers; len(got) != 1 || !got[0].Equal(net.ParseIP("1.1.1.1")) {
+		t.Errorf("ManualDNS.DnsServers = %v", got)
+	}
+	if prefs.LastConnectionParams.WireGuardParameters.Port.Port != 2049 {
+		t.Errorf("WireGuard port = %d", prefs.LastConnectionParams.WireGuardParameters.Port.Port)
+	}
+
+	// the second apply changes nothing
+	if changes := cfg.Apply(&prefs); len(changes) != 0 {
+		t.Errorf("second Apply() changes = %v, want none", changes)
+	}
+
+	wantKeys := []string{"connection.port", "dns.servers", "firewall.persistent", "firewall.user_exceptions"}
+	if got := cfg.ManagedKeys(); !reflect.DeepEqual(got, wantKeys) {
+		t.Errorf("ManagedKeys() = %v, want %v", got, wantKeys)
+	}
+}
+
+func TestIsKeyLocked(t *testing.T) {
+	locked, _ := Parse([]byte("version: 1\nlocked: true\nwifi:\n  trusted_networks_control: true\n"))
+	unlocked, _ := Parse([]byte("version: 1\nwifi:\n  trusted_networks_control: true\n"))
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		key  string
+		want bool
+	}{
+		{"exact key", locked, "wifi.trusted_networks_control", true},
+		{"prefix", locked, "wifi", true},
+		{"not managed", locked, "wifi.networks", false},
+		{"not a prefix of a word", locked, "wi", false},
+		{"not locked", unlocked, "wifi", false},
+		{"no config", nil, "wifi", false},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.IsKeyLocked(tt.key); got != tt.want {
+			t.Errorf("%s: IsKeyLocked(%q) = %v, want %v", tt.name, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestApplyToConnectionParams(t *testing.T) {
+	cfg, err := Parse([]byte(`
+version: 1
+dns:
+  encryption: dot
+  template: "tls://dns.example.com"
+connection:
+  vpn_type: openvpn
+  ipv6: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := types.ConnectionParams{VpnType: vpn.WireGuard}
+	params.WireGuardParameters.Mtu = 1400 // not managed: must be kept
+	cfg.ApplyToConnectionParams(&params)
+
+	if params.VpnType != vpn.OpenVPN || !params.IPv6 {
+		t.Errorf("VpnType = %v, IPv6 = %v", params.VpnType, params.IPv6)
+	}
+	if params.ManualDNS.Encryption != dns.EncryptionDnsOverTls || params.ManualDNS.DohTemplate != "tls://dns.example.com" {
+		t.Errorf("ManualDNS = %+v", params.ManualDNS)
+	}
+	if params.WireGuardParameters.Mtu != 1400 {
+		t.Errorf("Mtu = %d, want 1400", params.WireGuardParameters.Mtu)
+	}
+}
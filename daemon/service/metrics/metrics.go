@@ -0,0 +1,284 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+// Package metrics implements an opt-in Prometheus/OpenMetrics exporter (text exposition format).
+// The HTTP endpoint is allowed to listen only on a loopback address.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/logger"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+var log *logger.Logger
+
+func init() {
+	log = logger.NewLogger("metric")
+}
+
+// DefaultListenAddress - address of the metrics endpoint when not defined in preferences
+const DefaultListenAddress = "127.0.0.1:9810"
+
+const metricsPath = "/metrics"
+
+// FirewallStateFunc returns the current firewall state (it is called on each metrics request)
+type FirewallStateFunc func() (isEnabled, weHaveTopFirewallPriority bool, err error)
+
+type values struct {
+	vpnState vpn.State
+
+	bytesSent     uint64
+	bytesReceived uint64
+	lastHandshake time.Time
+
+	healthchecks        uint64
+	healthcheckFailures uint64
+	reconnects          uint64
+
+	pingLatencies map[string]int // host -> latency (ms)
+}
+
+var (
+	valuesMutex sync.Mutex
+	_values     = values{pingLatencies: map[string]int{}}
+
+	serverMutex      sync.Mutex
+	_server          *http.Server
+	_serverAddress   string
+	_firewallStateFn FirewallStateFunc
+)
+
+// SetVpnState saves the current VPN state.
+// On disconnection the values of the finished connection (transferred data, handshake time) are reset.
+func SetVpnState(state vpn.State) {
+	valuesMutex.Lock()
+	defer valuesMutex.Unlock()
+	_values.vpnState = state
+	if state == vpn.DISCONNECTED {
+		_values.bytesSent = 0
+		_values.bytesReceived = 0
+		_values.lastHandshake = time.Time{}
+	}
+}
+
+// SetTransferredBytes saves the total amount of data transferred through the tunnel (for current connection)
+func SetTransferredBytes(sent, received uint64) {
+	valuesMutex.Lock()
+	defer valuesMutex.Unlock()
+	_values.bytesSent = sent
+	_values.bytesReceived = received
+}
+
+// SetLastHandshake saves the time of the latest tunnel handshake
+func SetLastHandshake(t time.Time) {
+	valuesMutex.Lock()
+	defer valuesMutex.Unlock()
+	_values.lastHandshake = t
+}
+
+// IncHealthchecks counts the connectivity healthcheck result
+func IncHealthchecks(isFailed bool) {
+	valuesMutex.Lock()
+	defer valuesMutex.Unlock()
+	_values.healthchecks++
+	if isFailed {
+		_values.healthcheckFailures++
+	}
+}
+
+// IncReconnects counts the automatic reconnection
+func IncReconnects() {
+	valuesMutex.Lock()
+	defer valuesMutex.Unlock()
+	_values.reconnects++
+}
+
+// SetPingLatencies saves the latest servers ping results (host -> latency in milliseconds)
+func SetPingLatencies(results map[string]int) {
+	valuesMutex.Lock()
+	defer valuesMutex.Unlock()
+	_values.pingLatencies = make(map[string]int, len(results))
+	for host, latency := range results {
+		_values.pingLatencies[host] = latency
+	}
+}
+
+// Apply starts, restarts or stops the metrics endpoint according to the configuration
+func Apply(isEnabled bool, listenAddress string, firewallStateFn FirewallStateFunc) error {
+	serverMutex.Lock()
+	defer serverMutex.Unlock()
+
+	if len(listenAddress) == 0 {
+		listenAddress = DefaultListenAddress
+	}
+	_firewallStateFn = firewallStateFn
+
+	if _server != nil && (!isEnabled || listenAddress != _serverAddress) {
+		doStop()
+	}
+	if !isEnabled || _server != nil {
+		return nil
+	}
+
+	if err := CheckListenAddress(listenAddress); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to start metrics listener: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(metricsPath, handleMetrics)
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: time.Second * 10}
+
+	_server = srv
+	_serverAddress = listenAddress
+
+	go func() {
+		if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Error(fmt.Errorf("metrics endpoint stopped: %w", err))
+		}
+	}()
+
+	log.Info(fmt.Sprintf("Metrics endpoint started: http://%s%s", listenAddress, metricsPath))
+	return nil
+}
+
+// Stop stops the metrics endpoint (if running)
+func Stop() {
+	serverMutex.Lock()
+	defer serverMutex.Unlock()
+	doStop()
+}
+
+// CheckListenAddress returns error if the address is not a loopback 'host:port'
+func CheckListenAddress(listenAddress string) error {
+	host, port, err := net.SplitHostPort(listenAddress)
+	if err != nil {
+		return fmt.Errorf("bad metrics listen address '%s': %w", listenAddress, err)
+	}
+	if len(port) == 0 {
+		return fmt.Errorf("bad metrics listen address '%s': port not defined", listenAddress)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("bad metrics listen address '%s': only loopback addresses are allowed", listenAddress)
+	}
+	return nil
+}
+
+func doStop() {
+	if _server == nil {
+		return
+	}
+	if err := _server.Close(); err != nil {
+		log.Error(fmt.Errorf("failed to stop metrics endpoint: %w", err))
+	}
+	_server = nil
+	_serverAddress = ""
+	log.Info("Metrics endpoint stopped")
+}
+
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	serverMutex.Lock()
+	firewallStateFn := _firewallStateFn
+	serverMutex.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeMetrics(w, firewallStateFn)
+}
+
+func writeMetrics(w io.Writer, firewallStateFn FirewallStateFunc) {
+	valuesMutex.Lock()
+	v := _values
+	pingHosts := make([]string, 0, len(v.pingLatencies))
+	for host := range v.pingLatencies {
+		pingHosts = append(pingHosts, host)
+	}
+	pingLatencies := v.pingLatencies
+	valuesMutex.Unlock()
+
+	connected := 0
+	if v.vpnState == vpn.CONNECTED {
+		connected = 1
+	}
+	writeMetric(w, "privateline_vpn_state", "gauge", "Current VPN state (numeric value of vpn.State; 0 - DISCONNECTED)", int(v.vpnState))
+	writeMetric(w, "privateline_vpn_connected", "gauge", "1 if VPN is connected", connected)
+
+	writeMetric(w, "privateline_tunnel_sent_bytes_total", "counter", "Bytes sent through the tunnel (reset on each new connection)", v.bytesSent)
+	writeMetric(w, "privateline_tunnel_received_bytes_total", "counter", "Bytes received through the tunnel (reset on each new connection)", v.bytesReceived)
+	if !v.lastHandshake.IsZero() {
+		writeMetric(w, "privateline_tunnel_last_handshake_age_seconds", "gauge", "Seconds since the latest tunnel handshake", int64(time.Since(v.lastHandshake).Seconds()))
+	}
+
+	if firewallStateFn != nil {
+		if isEnabled, isTopPriority, err := firewallStateFn(); err != nil {
+			log.Warning(fmt.Errorf("metrics: failed to get firewall state: %w", err))
+		} else {
+			writeMetric(w, "privateline_firewall_enabled", "gauge", "1 if firewall (kill-switch) is enabled", boolToInt(isEnabled))
+			writeMetric(w, "privateline_firewall_top_priority", "gauge", "1 if our firewall rules have top priority (VPN coexistence is good)", boolToInt(isTopPriority))
+		}
+	}
+
+	writeMetric(w, "privateline_healthchecks_total", "counter", "Connectivity healthchecks performed while connected", v.healthchecks)
+	writeMetric(w, "privateline_healthcheck_failures_total", "counter", "Failed connectivity healthchecks", v.healthcheckFailures)
+	writeMetric(w, "privateline_reconnects_total", "counter", "Automatic reconnections", v.reconnects)
+
+	if len(pingHosts) > 0 {
+		sort.Strings(pingHosts)
+		fmt.Fprintf(w, "# HELP privateline_ping_latency_milliseconds Latest servers ping results\n")
+		fmt.Fprintf(w, "# TYPE privateline_ping_latency_milliseconds gauge\n")
+		for _, host := range pingHosts {
+			fmt.Fprintf(w, "privateline_ping_latency_milliseconds{host=\"%s\"} %d\n", escapeLabelValue(host), pingLatencies[host])
+		}
+	}
+}
+
+func writeMetric(w io.Writer, name, metricType, help string, value any) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, metricType, name, value)
+}
+
+func boolToInt(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,135 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+func TestCheckListenAddress(t *testing.T) {
+	tests := []struct {
+		addr    string
+		wantErr string
+	}{
+		{"127.0.0.1:9810", ""},
+		{"127.1.2.3:80", ""},
+		{"localhost:9810", ""},
+		{"[::1]:9810", ""},
+		{"0.0.0.0:9810", "only loopback addresses are allowed"},
+		{":9810", "only loopback addresses are allowed"},
+		{"192.168.1.10:9810", "only loopback addresses are allowed"},
+		{"[::]:9810", "only loopback addresses are allowed"},
+		{"example.com:9810", "only loopback addresses are allowed"},
+		{"127.0.0.1:", "port not defined"},
+		{"127.0.0.1", "bad metrics listen address"},
+		{"", "bad metrics listen address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			err := CheckListenAddress(tt.addr)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckListenAddress(%q) unexpected error: %v", tt.addr, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("CheckListenAddress(%q) error = %v, want error containing %q", tt.addr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func resetValues() {
+	valuesMutex.Lock()
+	defer valuesMutex.Unlock()
+	_values = values{pingLatencies: map[string]int{}}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	resetValues()
+	defer resetValues()
+
+	SetVpnState(vpn.CONNECTED)
+	SetTransferredBytes(1024, 2048)
+	SetLastHandshake(time.Now().Add(-30 * time.Second))
+	IncHealthchecks(false)
+	IncHealthchecks(true)
+	IncReconnects()
+	SetPingLatencies(map[string]int{"b.example.com": 40, `a"\.example.com`: 15})
+
+	var buf bytes.Buffer
+	writeMetrics(&buf, func() (bool, bool, error) { return true, false, nil })
+	out := buf.String()
+
+	for _, want := range []string{
+		"# HELP privateline_vpn_connected 1 if VPN is connected\n# TYPE privateline_vpn_connected gauge\nprivateline_vpn_connected 1\n",
+		"# TYPE privateline_tunnel_sent_bytes_total counter\nprivateline_tunnel_sent_bytes_total 1024\n",
+		"privateline_tunnel_received_bytes_total 2048\n",
+		"privateline_tunnel_last_handshake_age_seconds 30\n",
+		"privateline_firewall_enabled 1\n",
+		"privateline_firewall_top_priority 0\n",
+		"privateline_healthchecks_total 2\n",
+		"privateline_healthcheck_failures_total 1\n",
+		"privateline_reconnects_total 1\n",
+		"# TYPE privateline_ping_latency_milliseconds gauge\n" +
+			"privateline_ping_latency_milliseconds{host=\"a\\\"\\\\.example.com\"} 15\n" +
+			"privateline_ping_latency_milliseconds{host=\"b.example.com\"} 40\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output does not contain:\n%s\noutput:\n%s", want, out)
+		}
+	}
+
+	// every sample line must be preceded by its HELP and TYPE lines
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, "#") || strings.HasPrefix(l, "privateline_ping_latency_milliseconds{") {
+			continue
+		}
+		name := strings.Fields(l)[0]
+		if i < 2 || !strings.HasPrefix(lines[i-2], "# HELP "+name+" ") || !strings.HasPrefix(lines[i-1], "# TYPE "+name+" ") {
+			t.Errorf("sample '%s' is not preceded by HELP and TYPE lines", l)
+		}
+	}
+
+	// on disconnection the values of the finished connection are reset; firewall state is not available
+	SetVpnState(vpn.DISCONNECTED)
+	buf.Reset()
+	writeMetrics(&buf, func() (bool, bool, error) { return false, false, errors.New("not available") })
+	out = buf.String()
+	for _, want := range []string{"privateline_vpn_state 0\n", "privateline_vpn_connected 0\n", "privateline_tunnel_sent_bytes_total 0\n", "privateline_tunnel_received_bytes_total 0\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output does not contain %q", want)
+		}
+	}
+	for _, notWant := range []string{"privateline_tunnel_last_handshake_age_seconds", "privateline_firewall_enabled"} {
+		if strings.Contains(out, notWant) {
+			t.Errorf("metrics output must not contain %q", notWant)
+		}
+	}
+}
@@ -41,12 +41,23 @@ var (
 	// This file should be accessible to read only for 'privilaged' user
 	paranoidModeSecretFile string
 
+	// accessTokensFile path to a file which contains per-client access tokens (with their roles).
+	// This file should be accessible to read only for 'privilaged' user
+	accessTokensFile string
+
+	// historyFile path to a file which contains the connection events history (JSON lines)
+	historyFile string
+
+	// managedConfigFile path to the headless daemon configuration file (YAML)
+	managedConfigFile string
+
 	osVersion string
 
-	settingsFile    string
-	servicePortFile string
-	serversFile     string
-	logFile         string
+	settingsFile      string
+	servicePortFile   string
+	serviceSocketFile string // Unix-domain socket for the daemon protocol (empty - not supported on this platform)
+	serversFile       string
+	logFile           string
 
 	openVpnBinaryPath     string
 	openvpnCaKeyFile      string
@@ -115,6 +126,12 @@ func Init() (warnings []string, errors []error, logInfo []string) {
 	if err := makeDir("paranoidModeSecretFile", filepath.Dir(paranoidModeSecretFile), os.ModePerm); err != nil {
 		errors = append(errors, err)
 	}
+	if err := makeDir("accessTokensFile", filepath.Dir(accessTokensFile), os.ModePerm); err != nil {
+		errors = append(errors, err)
+	}
+	if err := makeDir("historyFile", filepath.Dir(historyFile), os.ModePerm); err != nil {
+		errors = append(errors, err)
+	}
 	if err := makeDir("logFile", filepath.Dir(logFile), os.ModePerm); err != nil {
 		errors = append(errors, err)
 	}
@@ -161,10 +178,10 @@ func Init() (warnings []string, errors []error, logInfo []string) {
 	}
 
 	// checking availability of WireGuard binaries
-	if err := checkFileAccessRightsExecutable("wgBinaryPath", wgBinaryPath); err != nil {
+	if err := checkFileAccessRightsExecutable("wgBinaryPath", wgBinaryPath); err != nil && IsWgBinaryRequired() {
 		warnings = append(warnings, fmt.Errorf("WireGuard functionality not accessible: %w", err).Error())
 	}
-	if err := checkFileAccessRightsExecutable("wgToolBinaryPath", wgToolBinaryPath); err != nil {
+	if err := checkFileAccessRightsExecutable("wgToolBinaryPath", wgToolBinaryPath); err != nil && IsWgBinaryRequired() {
 		warnings = append(warnings, fmt.Errorf("WireGuard functionality not accessible: %w", err).Error())
 	}
 
@@ -274,6 +291,12 @@ func ServicePortFile() string {
 	return servicePortFile
 }
 
+// ServiceSocketFile path to the Unix-domain socket of the daemon protocol.
+// Empty string when the Unix socket transport is not supported on this platform.
+func ServiceSocketFile() string {
+	return serviceSocketFile
+}
+
 // ParanoidModeSecretFile path to a file which contains 'secret' (password) for 'Paranoid mode'
 // If 'paranoid mode' enabled - this 'secret' must be used in each request to a daemon.
 // This file should be accessible to read only for 'privilaged' user
@@ -281,6 +304,22 @@ func ParanoidModeSecretFile() string {
 	return paranoidModeSecretFile
 }
 
+// AccessTokensFile path to a file which contains per-client access tokens (with their roles)
+// This file should be accessible to read only for 'privilaged' user
+func AccessTokensFile() string {
+	return accessTokensFile
+}
+
+// HistoryFile path to a file which contains the connection events history (JSON lines)
+func HistoryFile() string {
+	return historyFile
+}
+
+// ManagedConfigFile path to the headless daemon configuration file (YAML)
+func ManagedConfigFile() string {
+	return managedConfigFile
+}
+
 // ServersFile path to servers.json
 func ServersFile() string {
 	return serversFile
@@ -358,6 +397,11 @@ func WgBinaryPath() string {
 	return wgBinaryPath
 }
 
+// IsWgBinaryRequired returns false when the WireGuard binaries (WgBinaryPath and WgToolBinaryPath) are not in use on this platform
+func IsWgBinaryRequired() bool {
+	return implIsWgBinaryRequired()
+}
+
 // WgToolBinaryPath path to WireGuard tools binary
 func WgToolBinaryPath() string {
 	return wgToolBinaryPath
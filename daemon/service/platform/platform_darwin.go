@@ -36,8 +36,12 @@ var (
 // initialize all constant values (e.g. servicePortFile) which can be used in external projects (IVPN CLI)
 func doInitConstants() {
 	servicePortFile = "/Library/Application Support/IVPN/port.txt"
+	serviceSocketFile = "/Library/Application Support/IVPN/privateline-connect.sock"
 	openvpnUserParamsFile = "/Library/Application Support/IVPN/OpenVPN/ovpn_extra_params.txt"
 	paranoidModeSecretFile = "/Library/Application Support/IVPN/eaa"
+	accessTokensFile = "/Library/Application Support/IVPN/access_tokens.json"
+	historyFile = "/Library/Application Support/IVPN/connection_history.jsonl"
+	managedConfigFile = "/Library/Application Support/IVPN/daemon-config.yaml"
 
 	logDir := "/Library/Logs/"
 	logFile = path.Join(logDir, helpers.ServiceName)
@@ -62,6 +66,10 @@ func doOsInit() (warnings []string, errors []error, logInfo []string) {
 	return warnings, errors, logInfo
 }
 
+func implIsWgBinaryRequired() bool {
+	return true
+}
+
 // FirewallScript returns path to firewal script
 func FirewallScript() string {
 	return firewallScript
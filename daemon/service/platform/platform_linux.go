@@ -151,7 +151,11 @@ func doInitConstants() {
 
 	serversFile = path.Join(tmpDir, "servers.json")
 	servicePortFile = path.Join(tmpDir, "port.txt")
+	serviceSocketFile = path.Join(tmpDir, "privateline-connect.sock")
 	paranoidModeSecretFile = path.Join(tmpDir, "eaa")
+	accessTokensFile = path.Join(tmpDir, "access_tokens.json")
+	historyFile = path.Join(tmpDir, "connection_history.jsonl")
+	managedConfigFile = path.Join(path.Dir(tmpDir), "daemon-config.yaml")
 
 	logFile = path.Join(logDir, helpers.ServiceName+".log")
 
@@ -270,6 +274,10 @@ func ResolvectlBinPath() string {
 	return resolvectlBinPath
 }
 
+func implIsWgBinaryRequired() bool {
+	return false // the WireGuard interface is configured and keys are generated in-process: neither 'wg-quick' nor 'wg' tool is in use
+}
+
 func implPLOtherAppsToAcceptIncomingConnections() (otherPlApps []string, err error) {
 	return []string{}, nil // Vlad - on Linux the list of PL apps is implemented in firewall-helper.sh so far
 }
@@ -55,6 +55,9 @@ func doInitConstants() {
 
 	openvpnUserParamsFile = path.Join(installDir, "mutable/ovpn_extra_params.txt")
 	paranoidModeSecretFile = path.Join(installDir, "etc/eaa") // file located in 'etc' will not be removed during app upgrade
+	accessTokensFile = path.Join(installDir, "etc/access_tokens.json")
+	historyFile = path.Join(installDir, "mutable/connection_history.jsonl")
+	managedConfigFile = path.Join(installDir, "etc/daemon-config.yaml")
 
 	// Set default MTU to 1280 - minimum value allowed on Windows
 	// According to Windows specification: "... For IPv4 the minimum value is 576 bytes. For IPv6 the minimum value is 1280 bytes... "
@@ -188,6 +191,10 @@ func getPLCommsPaths() (plCommsPaths []string, err error) {
 	return filepath.Glob(Public + "/../*/AppData/Local/p*-comms-desktop/app-*/*Comms.exe")
 }
 
+func implIsWgBinaryRequired() bool {
+	return true
+}
+
 func implPLOtherAppsToAcceptIncomingConnections() (otherPlApps []string, err error) {
 	return getPLCommsPaths()
 }
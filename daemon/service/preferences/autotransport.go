@@ -0,0 +1,58 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package preferences
+
+import "sort"
+
+// MaxAutoTransportNetworks - max number of networks for which the working transport is remembered
+const MaxAutoTransportNetworks = 64
+
+// AutoTransportRecord - the transport which successfully connected on the network (automatic transport mode)
+type AutoTransportRecord struct {
+	Transport   string `json:"transport"`   // e.g. "wireguard", "wireguard_v2ray_quic", "openvpn_obfs4"
+	LastSuccess int64  `json:"lastSuccess"` // unix time of the latest successful connection
+}
+
+// SetAutoTransport remembers the transport which worked on the network.
+// When the number of networks exceeds MaxAutoTransportNetworks, the oldest records are removed.
+func (p *Preferences) SetAutoTransport(networkKey, transport string, lastSuccess int64) {
+	if len(networkKey) == 0 || len(transport) == 0 {
+		return
+	}
+
+	records := make(map[string]AutoTransportRecord, len(p.AutoTransportNetworks)+1)
+	for k, v := range p.AutoTransportNetworks {
+		records[k] = v
+	}
+	records[networkKey] = AutoTransportRecord{Transport: transport, LastSuccess: lastSuccess}
+
+	if len(records) > MaxAutoTransportNetworks {
+		keys := make([]string, 0, len(records))
+		for k := range records {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return records[keys[i]].LastSuccess < records[keys[j]].LastSuccess })
+		for _, k := range keys[:len(records)-MaxAutoTransportNetworks] {
+			delete(records, k)
+		}
+	}
+
+	p.AutoTransportNetworks = records
+}
@@ -0,0 +1,321 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package preferences
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/splittun"
+	"github.com/swapnilsparsh/devsVPN/daemon/version"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+const (
+	SettingsBundleFormat  = "privateline-connect-settings"
+	SettingsBundleVersion = 1 // the latest supported version of the settings bundle format
+)
+
+// SettingsBundle - portable (exportable) part of the preferences.
+// Secrets (session tokens, WireGuard keys, proxy credentials) and device-specific data
+// (VPN entry server hosts registered for this device, user-imported servers) are never included.
+type SettingsBundle struct {
+	Format     string
+	Version    int
+	AppVersion string // version of the daemon which exported the settings
+	Created    time.Time
+
+	UserPrefs                 UserPreferences
+	WiFiControl               WiFiParams
+	SplitTunnelApps           []string
+	SplitTunnelDomains        []splittun.DomainRule
+	SplitTunnelIncludeSubnets []string
+	SplitTunnelExcludeSubnets []string
+	FwUserExceptions          string
+	HealthchecksType          types.HealthchecksTypeEnum
+	LastConnectionParams      types.ConnectionParams
+	ConnectionProfiles        []ConnectionProfile
+	NetworkRules              []NetworkRule
+	ScheduleRules             []ScheduleRule
+	ConnectionQuality         ConnectionQualityParams
+}
+
+// ExportSettings returns the exportable part of the preferences
+func (p *Preferences) ExportSettings() SettingsBundle {
+	b := SettingsBundle{
+		Format:     SettingsBundleFormat,
+		Version:    SettingsBundleVersion,
+		AppVersion: version.Version(),
+		Created:    time.Now().UTC().Truncate(time.Second),
+	}
+	b.setFrom(p)
+	return b
+}
+
+func (b *SettingsBundle) setFrom(p *Preferences) {
+	b.UserPrefs = p.UserPrefs
+	b.WiFiControl = p.WiFiControl
+	b.SplitTunnelApps = p.SplitTunnelApps
+	b.SplitTunnelDomains = p.SplitTunnelDomains
+	b.SplitTunnelIncludeSubnets = p.SplitTunnelIncludeSubnets
+	b.SplitTunnelExcludeSubnets = p.SplitTunnelExcludeSubnets
+	b.FwUserExceptions = p.FwUserExceptions
+	b.HealthchecksType = p.HealthchecksType
+	b.LastConnectionParams = exportableConnectionParams(p.LastConnectionParams)
+	b.ConnectionProfiles = make([]ConnectionProfile, 0, len(p.ConnectionProfiles))
+	for _, prof := range p.ConnectionProfiles {
+		prof.Params = exportableConnectionParams(prof.Params)
+		b.ConnectionProfiles = append(b.ConnectionProfiles, prof)
+	}
+	b.NetworkRules = p.NetworkRules
+	b.ScheduleRules = p.ScheduleRules
+	b.ConnectionQuality = p.ConnectionQuality
+}
+
+// exportableConnectionParams removes device-specific data and secrets from the connection parameters
+func exportableConnectionParams(params types.ConnectionParams) types.ConnectionParams {
+	params.WireGuardParameters.EntryVpnServer.Hosts = nil
+	params.OpenVpnParameters.EntryVpnServer.Hosts = nil
+	params.OpenVpnParameters.Proxy.Username = ""
+	params.OpenVpnParameters.Proxy.Password = ""
+	params.CanReconfigureOtherVpnsOnce = false
+	return params
+}
+
+// Validate checks the settings bundle
+func (b SettingsBundle) Validate() error {
+	if b.Format != SettingsBundleFormat {
+		return fmt.Errorf("unexpected settings format '%s'", b.Format)
+	}
+	if b.Version <= 0 || b.Version > SettingsBundleVersion {
+		return fmt.Errorf("unsupported settings version %d (the latest supported version is %d)", b.Version, SettingsBundleVersion)
+	}
+
+	for _, e := range strings.Split(b.FwUserExceptions, ",") {
+		if e = strings.TrimSpace(e); e == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(e); err != nil && net.ParseIP(e) == nil {
+			return fmt.Errorf("bad firewall exception '%s'", e)
+		}
+	}
+
+	if len(b.ConnectionProfiles) > MaxConnectionProfiles {
+		return fmt.Errorf("too many connection profiles (maximum %d)", MaxConnectionProfiles)
+	}
+	profileNames := make(map[string]struct{}, len(b.ConnectionProfiles))
+	for _, prof := range b.ConnectionProfiles {
+		name, err := NormalizeConnectionProfileName(prof.Name)
+		if err != nil {
+			return fmt.Errorf("bad connection profile '%s': %w", prof.Name, err)
+		}
+		if _, ok := profileNames[strings.ToLower(name)]; ok {
+			return fmt.Errorf("duplicate connection profile '%s'", name)
+		}
+		profileNames[strings.ToLower(name)] = struct{}{}
+	}
+
+	for _, n := range b.WiFiControl.Networks {
+		if n.SSID == "" {
+			return fmt.Errorf("empty SSID in the trusted WiFi networks list")
+		}
+		if _, ok := profileNames[strings.ToLower(strings.TrimSpace(n.Profile))]; len(n.Profile) > 0 && !ok {
+			return fmt.Errorf("WiFi network '%s' refers to unknown connection profile '%s'", n.SSID, n.Profile)
+		}
+		if n.Actions != nil {
+			if err := n.Actions.Validate(); err != nil {
+				return fmt.Errorf("WiFi network '%s' actions: %w", n.SSID, err)
+			}
+		}
+	}
+
+	profiles := Preferences{ConnectionProfiles: b.ConnectionProfiles}
+	if _, err := profiles.ValidateNetworkRules(b.NetworkRules); err != nil {
+		return err
+	}
+	if _, err := profiles.ValidateScheduleRules(b.ScheduleRules); err != nil {
+		return err
+	}
+	if b.ConnectionQuality != (ConnectionQualityParams{}) { // not defined in bundles of older versions
+		if err := b.ConnectionQuality.Validate(); err != nil {
+			return fmt.Errorf("connection quality: %w", err)
+		}
+	}
+
+	if b.HealthchecksType < 0 || int(b.HealthchecksType) >= len(types.HealthcheckTypeNames) {
+		return fmt.Errorf("unexpected healthchecks type %d", b.HealthchecksType)
+	}
+
+	if vpnType := b.LastConnectionParams.VpnType; vpnType != vpn.WireGuard && vpnType != vpn.OpenVPN {
+		return fmt.Errorf("unexpected VPN type %d", vpnType)
+	}
+
+	if mtu := b.LastConnectionParams.WireGuardParameters.Mtu; mtu != 0 && (mtu < 1280 || mtu > 65535) {
+		return fmt.Errorf("bad WireGuard MTU %d", mtu)
+	}
+
+	return nil
+}
+
+// ImportSettings applies the settings bundle to the preferences (the bundle must be validated before).
+// Device-specific data and secrets of the current preferences are kept.
+func (p *Preferences) ImportSettings(b SettingsBundle) {
+	params := b.LastConnectionParams
+	params.WireGuardParameters.EntryVpnServer.Hosts = p.LastConnectionParams.WireGuardParameters.EntryVpnServer.Hosts
+	params.OpenVpnParameters.EntryVpnServer.Hosts = p.LastConnectionParams.OpenVpnParameters.EntryVpnServer.Hosts
+	params.OpenVpnParameters.Proxy.Username = p.LastConnectionParams.OpenVpnParameters.Proxy.Username
+	params.OpenVpnParameters.Proxy.Password = p.LastConnectionParams.OpenVpnParameters.Proxy.Password
+	params.CanReconfigureOtherVpnsOnce = false
+	params.CustomServerID = p.existingCustomServerID(params.CustomServerID)
+
+	p.UserPrefs = b.UserPrefs
+	p.WiFiControl = b.WiFiControl
+	p.SplitTunnelApps = b.SplitTunnelApps
+	p.SplitTunnelDomains = b.SplitTunnelDomains
+	p.SplitTunnelIncludeSubnets = b.SplitTunnelIncludeSubnets
+	p.SplitTunnelExcludeSubnets = b.SplitTunnelExcludeSubnets
+	p.FwUserExceptions = b.FwUserExceptions
+	p.HealthchecksType = b.HealthchecksType
+	p.LastConnectionParams = params
+
+	p.ConnectionProfiles = make([]ConnectionProfile, 0, len(b.ConnectionProfiles))
+	for _, prof := range b.ConnectionProfiles {
+		prof.Params.CustomServerID = p.existingCustomServerID(prof.Params.CustomServerID)
+		p.ConnectionProfiles = append(p.ConnectionProfiles, prof)
+	}
+	p.NetworkRules = b.NetworkRules
+	p.ScheduleRules = b.ScheduleRules
+	if b.ConnectionQuality != (ConnectionQualityParams{}) {
+		p.ConnectionQuality = b.ConnectionQuality
+	}
+	if len(p.AutoconnectProfile) > 0 && p.ConnectionProfileIndex(p.AutoconnectProfile) < 0 {
+		p.AutoconnectProfile = "" // the profile does not exist anymore: auto-connect with the last connection parameters
+	}
+}
+
+// existingCustomServerID returns the ID if the custom server is defined in the preferences (otherwise - empty string).
+// Custom servers are not exported, so the references from the bundle imported on another device are removed.
+func (p *Preferences) existingCustomServerID(id string) string {
+	if len(id) == 0 {
+		return ""
+	}
+	for _, s := range p.CustomServers {
+		if s.ID == id {
+			return id
+		}
+	}
+	return ""
+}
+
+// SettingsDiff returns the list of changed exportable values in format "Key.SubKey: old -> new"
+func SettingsDiff(oldPrefs, newPrefs *Preferences) ([]string, error) {
+	var oldBundle, newBundle SettingsBundle
+	oldBundle.setFrom(oldPrefs)
+	newBundle.setFrom(newPrefs)
+	return settingsDiff(oldBundle, newBundle)
+}
+
+// settingsDiff returns the list of changed values in format "Key.SubKey: old -> new"
+func settingsDiff(oldBundle, newBundle SettingsBundle) ([]string, error) {
+	oldValues, err := flattenJSON(oldBundle)
+	if err != nil {
+		return nil, err
+	}
+	newValues, err := flattenJSON(newBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]struct{})
+	for k := range oldValues {
+		keys[k] = struct{}{}
+	}
+	for k := range newValues {
+		keys[k] = struct{}{}
+	}
+
+	changes := make([]string, 0)
+	for k := range keys {
+		oldVal, isOldExists := oldValues[k]
+		newVal, isNewExists := newValues[k]
+		if isOldExists && isNewExists && oldVal == newVal {
+			continue
+		}
+		if !isOldExists {
+			oldVal = "<none>"
+		}
+		if !isNewExists {
+			newVal = "<none>"
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s", k, oldVal, newVal))
+	}
+	sort.Strings(changes)
+	return changes, nil
+}
+
+// flattenJSON converts the object into a map of its JSON values: "Key.SubKey[idx]" -> "value"
+func flattenJSON(v any) (map[string]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var obj any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]string)
+	var flatten func(prefix string, v any)
+	flatten = func(prefix string, v any) {
+		switch val := v.(type) {
+		case map[string]any:
+			for k, sub := range val {
+				if prefix == "" {
+					flatten(k, sub)
+				} else {
+					flatten(prefix+"."+k, sub)
+				}
+			}
+		case []any:
+			for i, sub := range val {
+				flatten(fmt.Sprintf("%s[%d]", prefix, i), sub)
+			}
+		case nil:
+			// nil values are not included (no difference between nil and empty lists)
+		default:
+			ret[prefix] = fmt.Sprint(val)
+		}
+	}
+
+	// the bundle header is not a part of the settings
+	if m, ok := obj.(map[string]any); ok {
+		delete(m, "Format")
+		delete(m, "Version")
+		delete(m, "AppVersion")
+		delete(m, "Created")
+	}
+	flatten("", obj)
+	return ret, nil
+}
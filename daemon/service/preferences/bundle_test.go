@@ -0,0 +1,191 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package preferences
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	api_types "github.com/swapnilsparsh/devsVPN/daemon/api/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+func testHost(hostname string) api_types.WireGuardServerHostInfo {
+	return api_types.WireGuardServerHostInfo{HostInfoBase: api_types.HostInfoBase{Hostname: hostname, EndpointIP: "1.2.3.4"}}
+}
+
+func TestExportSettings(t *testing.T) {
+	p := Create()
+	p.Session.Session = "secret-session"
+	p.LastConnectionParams.WireGuardParameters.EntryVpnServer.Hosts = []api_types.WireGuardServerHostInfo{testHost("registered")}
+	p.LastConnectionParams.OpenVpnParameters.Proxy.Username = "proxy-user"
+	p.LastConnectionParams.OpenVpnParameters.Proxy.Password = "proxy-pass"
+	p.LastConnectionParams.CanReconfigureOtherVpnsOnce = true
+	p.ConnectionProfiles = []ConnectionProfile{{Name: "work"}}
+	p.ConnectionProfiles[0].Params.WireGuardParameters.EntryVpnServer.Hosts = []api_types.WireGuardServerHostInfo{testHost("registered")}
+
+	b := p.ExportSettings()
+	if err := b.Validate(); err != nil {
+		t.Fatalf("exported bundle is not valid: %v", err)
+	}
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"secret-session", "registered", "proxy-user", "proxy-pass"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("exported bundle contains '%s'", secret)
+		}
+	}
+	if b.LastConnectionParams.CanReconfigureOtherVpnsOnce {
+		t.Error("CanReconfigureOtherVpnsOnce is exported")
+	}
+	// the source preferences are not modified
+	if len(p.ConnectionProfiles[0].Params.WireGuardParameters.EntryVpnServer.Hosts) != 1 {
+		t.Error("export modified the profile of the source preferences")
+	}
+}
+
+func TestSettingsBundleValidate(t *testing.T) {
+	valid := func() SettingsBundle { return Create().ExportSettings() }
+
+	tests := []struct {
+		name    string
+		modify  func(b *SettingsBundle)
+		wantErr string // "" - valid
+	}{
+		{"valid", func(b *SettingsBundle) {}, ""},
+		{"wrong format", func(b *SettingsBundle) { b.Format = "something" }, "unexpected settings format"},
+		{"newer version", func(b *SettingsBundle) { b.Version = SettingsBundleVersion + 1 }, "unsupported settings version"},
+		{"zero version", func(b *SettingsBundle) { b.Version = 0 }, "unsupported settings version"},
+		{"firewall exceptions", func(b *SettingsBundle) { b.FwUserExceptions = "10.0.0.0/8, 192.168.0.1," }, ""},
+		{"bad firewall exception", func(b *SettingsBundle) { b.FwUserExceptions = "10.0.0.0/33" }, "bad firewall exception"},
+		{"duplicate profiles", func(b *SettingsBundle) {
+			b.ConnectionProfiles = []ConnectionProfile{{Name: "Home"}, {Name: "home "}}
+		}, "duplicate connection profile"},
+		{"empty profile name", func(b *SettingsBundle) { b.ConnectionProfiles = []ConnectionProfile{{Name: " "}} }, "bad connection profile"},
+		{"wifi network with unknown profile", func(b *SettingsBundle) {
+			b.WiFiControl.Networks = []WiFiNetwork{{SSID: "cafe", Profile: "travel"}}
+		}, "unknown connection profile"},
+		{"wifi network with known profile", func(b *SettingsBundle) {
+			b.ConnectionProfiles = []ConnectionProfile{{Name: "Travel"}}
+			b.WiFiControl.Networks = []WiFiNetwork{{SSID: "cafe", Profile: "travel"}}
+		}, ""},
+		{"empty SSID", func(b *SettingsBundle) { b.WiFiControl.Networks = []WiFiNetwork{{}} }, "empty SSID"},
+		{"bad healthchecks type", func(b *SettingsBundle) { b.HealthchecksType = 100 }, "unexpected healthchecks type"},
+		{"bad VPN type", func(b *SettingsBundle) { b.LastConnectionParams.VpnType = vpn.Type(7) }, "unexpected VPN type"},
+		{"bad MTU", func(b *SettingsBundle) { b.LastConnectionParams.WireGuardParameters.Mtu = 100 }, "bad WireGuard MTU"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := valid()
+			tt.modify(&b)
+			err := b.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportSettings(t *testing.T) {
+	// bundle exported on another device
+	src := Create()
+	src.FwUserExceptions = "10.0.0.0/8"
+	src.LastConnectionParams.CustomServerID = "remote01"
+	src.ConnectionProfiles = []ConnectionProfile{{Name: "byos"}, {Name: "local"}}
+	src.ConnectionProfiles[0].Params.CustomServerID = "remote01"
+	src.ConnectionProfiles[1].Params.CustomServerID = "local001"
+	b := src.ExportSettings()
+
+	dst := Create()
+	dst.Session.Session = "local-session"
+	dst.LastConnectionParams.WireGuardParameters.EntryVpnServer.Hosts = []api_types.WireGuardServerHostInfo{testHost("registered")}
+	dst.LastConnectionParams.OpenVpnParameters.Proxy.Password = "local-proxy-pass"
+	dst.CustomServers = []CustomServer{{ID: "local001", Name: "home router"}}
+	dst.AutoconnectProfile = "removed"
+
+	dst.ImportSettings(b)
+
+	if dst.FwUserExceptions != "10.0.0.0/8" {
+		t.Errorf("FwUserExceptions = %q", dst.FwUserExceptions)
+	}
+	if dst.Session.Session != "local-session" {
+		t.Error("session is not kept")
+	}
+	if hosts := dst.LastConnectionParams.WireGuardParameters.EntryVpnServer.Hosts; len(hosts) != 1 || hosts[0].Hostname != "registered" {
+		t.Errorf("registered entry server is not kept: %v", hosts)
+	}
+	if dst.LastConnectionParams.OpenVpnParameters.Proxy.Password != "local-proxy-pass" {
+		t.Error("proxy credentials are not kept")
+	}
+	if id := dst.LastConnectionParams.CustomServerID; id != "" {
+		t.Errorf("reference to unknown custom server is kept: %q", id)
+	}
+	if id := dst.ConnectionProfiles[0].Params.CustomServerID; id != "" {
+		t.Errorf("profile refers to unknown custom server %q", id)
+	}
+	if id := dst.ConnectionProfiles[1].Params.CustomServerID; id != "local001" {
+		t.Errorf("reference to existing custom server is removed (got %q)", id)
+	}
+	if len(dst.CustomServers) != 1 {
+		t.Error("custom servers are not kept")
+	}
+	if dst.AutoconnectProfile != "" {
+		t.Errorf("AutoconnectProfile refers to not existing profile %q", dst.AutoconnectProfile)
+	}
+	// the bundle is not modified
+	if b.ConnectionProfiles[0].Params.CustomServerID != "remote01" {
+		t.Error("import modified the bundle")
+	}
+}
+
+func TestSettingsDiff(t *testing.T) {
+	oldPrefs := Create()
+	oldPrefs.SplitTunnelApps = []string{"/usr/bin/a"}
+	newPrefs := Create()
+	newPrefs.SplitTunnelApps = []string{"/usr/bin/a", "/usr/bin/b"}
+	newPrefs.FwUserExceptions = "10.0.0.1"
+
+	changes, err := SettingsDiff(oldPrefs, newPrefs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"FwUserExceptions:  -> 10.0.0.1",
+		"SplitTunnelApps[1]: <none> -> /usr/bin/b",
+	}
+	if strings.Join(changes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("SettingsDiff() = %q, want %q", changes, want)
+	}
+
+	if changes, _ := SettingsDiff(oldPrefs, oldPrefs); len(changes) != 0 {
+		t.Errorf("SettingsDiff() of the same preferences = %v", changes)
+	}
+}
@@ -0,0 +1,169 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package preferences
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+const (
+	// MaxCustomServers - the maximum number of user-imported servers
+	MaxCustomServers = 32
+	// MaxCustomServerNameLength - the maximum length of the custom server name (in characters)
+	MaxCustomServerNameLength = 64
+	// MaxCustomServerConfigSize - the maximum size of the imported configuration file (bytes)
+	MaxCustomServerConfigSize = 64 * 1024
+)
+
+// CustomServer - VPN server configuration imported by the user ("bring your own server"):
+// standard WireGuard '.conf' or OpenVPN '.ovpn' file of a self-hosted server
+type CustomServer struct {
+	ID       string
+	Name     string
+	VpnType  vpn.Type
+	Config   string // content of the imported configuration file (contains private keys!)
+	Username string `json:",omitempty"` // (OpenVPN) credentials for 'auth-user-pass'
+	Password string `json:",omitempty"`
+	Imported time.Time
+	// the latest resolved IP address of the server host; in use when the host can not be resolved (e.g. DNS is blocked by the firewall)
+	EndpointIP string `json:",omitempty"`
+}
+
+// NormalizeCustomServerName validates the custom server name and returns it without leading and trailing spaces
+func NormalizeCustomServerName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		return "", fmt.Errorf("custom server name is empty")
+	}
+	if len([]rune(name)) > MaxCustomServerNameLength {
+		return "", fmt.Errorf("custom server name is too long (maximum %d characters)", MaxCustomServerNameLength)
+	}
+	for _, c := range name {
+		if unicode.IsControl(c) {
+			return "", fmt.Errorf("custom server name contains control characters")
+		}
+	}
+	return name, nil
+}
+
+// CustomServerIndex returns index of the custom server with the ID or name (case-insensitive) or -1 if not found
+func (p *Preferences) CustomServerIndex(idOrName string) int {
+	idOrName = strings.TrimSpace(idOrName)
+	for i, svr := range p.CustomServers {
+		if svr.ID == idOrName {
+			return i
+		}
+	}
+	for i, svr := range p.CustomServers {
+		if strings.EqualFold(svr.Name, idOrName) {
+			return i
+		}
+	}
+	return -1
+}
+
+// CustomServer returns the custom server with the ID or name (case-insensitive)
+func (p *Preferences) CustomServer(idOrName string) (CustomServer, error) {
+	if idx := p.CustomServerIndex(idOrName); idx >= 0 {
+		return p.CustomServers[idx], nil
+	}
+	return CustomServer{}, fmt.Errorf("custom server '%s' not found", strings.TrimSpace(idOrName))
+}
+
+// AddCustomServer adds new custom server. The ID is generated when it is not defined.
+func (p *Preferences) AddCustomServer(svr CustomServer) (CustomServer, error) {
+	name, err := NormalizeCustomServerName(svr.Name)
+	if err != nil {
+		return svr, err
+	}
+	svr.Name = name
+	if idx := p.CustomServerIndex(name); idx >= 0 {
+		return svr, fmt.Errorf("custom server '%s' already exists", p.CustomServers[idx].Name)
+	}
+	if len(p.CustomServers) >= MaxCustomServers {
+		return svr, fmt.Errorf("too many custom servers (maximum %d)", MaxCustomServers)
+	}
+	if len(svr.ID) == 0 {
+		id := make([]byte, 4)
+		if _, err := rand.Read(id); err != nil {
+			return svr, fmt.Errorf("failed to generate custom server ID: %w", err)
+		}
+		svr.ID = hex.EncodeToString(id)
+	}
+	if svr.Imported.IsZero() {
+		svr.Imported = time.Now().UTC().Truncate(time.Second)
+	}
+
+	servers := make([]CustomServer, 0, len(p.CustomServers)+1) // do not modify the slice which can be shared with a copy of the preferences
+	servers = append(servers, p.CustomServers...)
+	p.CustomServers = append(servers, svr)
+	return svr, nil
+}
+
+// SetCustomServerEndpointIP updates the latest resolved IP address of the custom server host
+func (p *Preferences) SetCustomServerEndpointIP(id, endpointIP string) {
+	idx := p.CustomServerIndex(id)
+	if idx < 0 {
+		return
+	}
+	servers := make([]CustomServer, len(p.CustomServers))
+	copy(servers, p.CustomServers)
+	servers[idx].EndpointIP = endpointIP
+	p.CustomServers = servers
+}
+
+// CustomServerReferences returns the list of connection profiles which refer to the custom server
+func (p *Preferences) CustomServerReferences(id string) (refs []string) {
+	for _, prof := range p.ConnectionProfiles {
+		if prof.Params.CustomServerID == id {
+			refs = append(refs, fmt.Sprintf("connection profile '%s'", prof.Name))
+		}
+	}
+	return refs
+}
+
+// DeleteCustomServer removes the custom server. It is not allowed to remove the server which is in use by connection profiles.
+// When the last connection was made to the server - the last connection parameters are switched back to privateLINE servers.
+func (p *Preferences) DeleteCustomServer(idOrName string) (CustomServer, error) {
+	idx := p.CustomServerIndex(idOrName)
+	if idx < 0 {
+		return CustomServer{}, fmt.Errorf("custom server '%s' not found", strings.TrimSpace(idOrName))
+	}
+	svr := p.CustomServers[idx]
+	if refs := p.CustomServerReferences(svr.ID); len(refs) > 0 {
+		return svr, fmt.Errorf("custom server '%s' is in use by: %s", svr.Name, strings.Join(refs, ", "))
+	}
+
+	servers := make([]CustomServer, 0, len(p.CustomServers)-1)
+	servers = append(servers, p.CustomServers[:idx]...)
+	p.CustomServers = append(servers, p.CustomServers[idx+1:]...)
+
+	if p.LastConnectionParams.CustomServerID == svr.ID {
+		p.LastConnectionParams.CustomServerID = ""
+	}
+	return svr, nil
+}
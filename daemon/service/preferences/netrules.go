@@ -0,0 +1,178 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package preferences
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"strings"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/netinfo"
+)
+
+// MaxNetworkRules - the maximum number of network-condition rules
+const MaxNetworkRules = 32
+
+// NetworkRuleConditions - conditions of the network rule.
+// Empty values are ignored; all defined conditions must match.
+type NetworkRuleConditions struct {
+	InterfaceName string `json:"interfaceName,omitempty"` // name of the default interface; wildcards allowed (e.g. "enx*")
+	InterfaceType string `json:"interfaceType,omitempty"` // type of the default interface: "ethernet", "wifi" or "other"
+	GatewayIP     string `json:"gatewayIP,omitempty"`     // IP address of the default gateway
+	GatewayMAC    string `json:"gatewayMAC,omitempty"`    // MAC address of the default gateway
+	DnsSuffix     string `json:"dnsSuffix,omitempty"`     // DNS search domain (e.g. provided by DHCP: "corp.example.com")
+	ReachableHost string `json:"reachableHost,omitempty"` // "host:port" which must be reachable over TCP (e.g. "intranet.corp.example.com:443")
+}
+
+// NetworkRule - the action set applied when the current network matches the rule conditions
+// (e.g. "connect VPN with the 'work' profile when docked to the office ethernet")
+type NetworkRule struct {
+	Name       string                `json:"name"`
+	Disabled   bool                  `json:"disabled,omitempty"`
+	Conditions NetworkRuleConditions `json:"conditions"`
+	Profile    string                `json:"profile,omitempty"` // connection profile used to connect VPN (empty - the default auto-connection parameters)
+	Actions    WiFiNetworkActions    `json:"actions"`
+}
+
+// IsEmpty returns true when no conditions defined
+func (c NetworkRuleConditions) IsEmpty() bool {
+	return len(c.InterfaceName) == 0 && len(c.InterfaceType) == 0 && len(c.GatewayIP) == 0 &&
+		len(c.GatewayMAC) == 0 && len(c.DnsSuffix) == 0 && len(c.ReachableHost) == 0
+}
+
+// Validate checks the conditions are correct
+func (c NetworkRuleConditions) Validate() error {
+	if c.IsEmpty() {
+		return fmt.Errorf("no conditions defined")
+	}
+	if len(c.InterfaceName) > 0 {
+		if _, err := path.Match(c.InterfaceName, ""); err != nil {
+			return fmt.Errorf("bad interface name pattern '%s'", c.InterfaceName)
+		}
+	}
+	switch netinfo.InterfaceType(c.InterfaceType) {
+	case netinfo.InterfaceTypeUnknown, netinfo.InterfaceTypeEthernet, netinfo.InterfaceTypeWiFi, netinfo.InterfaceTypeOther:
+	default:
+		return fmt.Errorf("unsupported interface type '%s' (acceptable values: %s, %s, %s)", c.InterfaceType,
+			netinfo.InterfaceTypeEthernet, netinfo.InterfaceTypeWiFi, netinfo.InterfaceTypeOther)
+	}
+	if len(c.GatewayIP) > 0 && net.ParseIP(c.GatewayIP) == nil {
+		return fmt.Errorf("bad gateway IP address '%s'", c.GatewayIP)
+	}
+	if len(c.GatewayMAC) > 0 {
+		if _, err := net.ParseMAC(c.GatewayMAC); err != nil {
+			return fmt.Errorf("bad gateway MAC address '%s'", c.GatewayMAC)
+		}
+	}
+	if len(c.ReachableHost) > 0 {
+		host, port, err := net.SplitHostPort(c.ReachableHost)
+		if err != nil || len(host) == 0 || len(port) == 0 {
+			return fmt.Errorf("bad reachable host '%s' (expected format: 'host:port')", c.ReachableHost)
+		}
+	}
+	return nil
+}
+
+// Match checks the conditions against the current default network.
+// 'isReachable' is called (only when all other conditions match) to check the 'ReachableHost' condition.
+func (c NetworkRuleConditions) Match(network netinfo.DefaultNetworkInfo, isReachable func(hostPort string) bool) bool {
+	if c.IsEmpty() {
+		return false
+	}
+	if len(c.InterfaceName) > 0 {
+		if ok, _ := path.Match(c.InterfaceName, network.InterfaceName); !ok {
+			return false
+		}
+	}
+	if len(c.InterfaceType) > 0 && netinfo.InterfaceType(c.InterfaceType) != network.InterfaceType {
+		return false
+	}
+	if len(c.GatewayIP) > 0 && !net.ParseIP(c.GatewayIP).Equal(network.GatewayIP) {
+		return false
+	}
+	if len(c.GatewayMAC) > 0 {
+		mac, _ := net.ParseMAC(c.GatewayMAC)
+		if network.GatewayMAC == nil || mac.String() != network.GatewayMAC.String() {
+			return false
+		}
+	}
+	if len(c.DnsSuffix) > 0 {
+		suffix := strings.TrimSuffix(strings.ToLower(c.DnsSuffix), ".")
+		found := false
+		for _, d := range network.DnsSearchDomains {
+			if d == suffix || strings.HasSuffix(d, "."+suffix) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(c.ReachableHost) > 0 && (isReachable == nil || !isReachable(c.ReachableHost)) {
+		return false
+	}
+	return true
+}
+
+// ValidateNetworkRules checks the rules are correct and returns them normalized
+func (p *Preferences) ValidateNetworkRules(rules []NetworkRule) ([]NetworkRule, error) {
+	if len(rules) > MaxNetworkRules {
+		return nil, fmt.Errorf("too many network rules (maximum %d)", MaxNetworkRules)
+	}
+
+	names := make(map[string]struct{}, len(rules))
+	ret := make([]NetworkRule, 0, len(rules))
+	for _, r := range rules {
+		r.Name = strings.TrimSpace(r.Name)
+		if len(r.Name) == 0 {
+			return nil, fmt.Errorf("network rule name is empty")
+		}
+		if _, ok := names[strings.ToLower(r.Name)]; ok {
+			return nil, fmt.Errorf("duplicate network rule '%s'", r.Name)
+		}
+		names[strings.ToLower(r.Name)] = struct{}{}
+
+		c := &r.Conditions
+		c.InterfaceName = strings.TrimSpace(c.InterfaceName)
+		c.InterfaceType = strings.ToLower(strings.TrimSpace(c.InterfaceType))
+		c.GatewayIP = strings.TrimSpace(c.GatewayIP)
+		c.GatewayMAC = strings.ToLower(strings.TrimSpace(c.GatewayMAC))
+		c.DnsSuffix = strings.TrimSpace(c.DnsSuffix)
+		c.ReachableHost = strings.TrimSpace(c.ReachableHost)
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("network rule '%s': %w", r.Name, err)
+		}
+		if err := r.Actions.Validate(); err != nil {
+			return nil, fmt.Errorf("network rule '%s' actions: %w", r.Name, err)
+		}
+
+		if r.Profile = strings.TrimSpace(r.Profile); len(r.Profile) > 0 {
+			prof, err := p.ConnectionProfile(r.Profile)
+			if err != nil {
+				return nil, fmt.Errorf("network rule '%s': %w", r.Name, err)
+			}
+			r.Profile = prof.Name
+		}
+		ret = append(ret, r)
+	}
+	return ret, nil
+}
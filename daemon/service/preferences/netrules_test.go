@@ -0,0 +1,151 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package preferences
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/netinfo"
+)
+
+func TestValidateNetworkRules(t *testing.T) {
+	p := Create()
+	p.ConnectionProfiles = []ConnectionProfile{{Name: "Work"}}
+
+	on, off := true, false
+
+	tests := []struct {
+		name    string
+		rules   []NetworkRule
+		wantErr string
+	}{
+		{"empty list", nil, ""},
+		{"valid", []NetworkRule{{Name: " office ", Conditions: NetworkRuleConditions{InterfaceType: " Ethernet ", GatewayMAC: "AA:BB:CC:DD:EE:FF"}, Profile: "work"}}, ""},
+		{"empty name", []NetworkRule{{Name: " ", Conditions: NetworkRuleConditions{InterfaceType: "wifi"}}}, "name is empty"},
+		{"duplicate name", []NetworkRule{
+			{Name: "a", Conditions: NetworkRuleConditions{InterfaceType: "wifi"}},
+			{Name: "A", Conditions: NetworkRuleConditions{InterfaceType: "other"}}}, "duplicate network rule"},
+		{"no conditions", []NetworkRule{{Name: "a"}}, "no conditions defined"},
+		{"bad interface pattern", []NetworkRule{{Name: "a", Conditions: NetworkRuleConditions{InterfaceName: "enx["}}}, "bad interface name pattern"},
+		{"bad interface type", []NetworkRule{{Name: "a", Conditions: NetworkRuleConditions{InterfaceType: "modem"}}}, "unsupported interface type"},
+		{"bad gateway ip", []NetworkRule{{Name: "a", Conditions: NetworkRuleConditions{GatewayIP: "192.168.1"}}}, "bad gateway IP"},
+		{"bad gateway mac", []NetworkRule{{Name: "a", Conditions: NetworkRuleConditions{GatewayMAC: "00:11:22"}}}, "bad gateway MAC"},
+		{"bad reachable host", []NetworkRule{{Name: "a", Conditions: NetworkRuleConditions{ReachableHost: "intranet"}}}, "bad reachable host"},
+		{"unknown profile", []NetworkRule{{Name: "a", Conditions: NetworkRuleConditions{InterfaceType: "wifi"}, Profile: "home"}}, "not found"},
+		{"firewall can not be disabled", []NetworkRule{{Name: "a", Conditions: NetworkRuleConditions{InterfaceType: "wifi"}, Actions: WiFiNetworkActions{EnableFirewall: &off}}}, "disabling Firewall is not supported"},
+		{"firewall enabled", []NetworkRule{{Name: "a", Conditions: NetworkRuleConditions{InterfaceType: "wifi"}, Actions: WiFiNetworkActions{EnableFirewall: &on, BlockLan: &on}}}, ""},
+		{"bad protocol", []NetworkRule{{Name: "a", Conditions: NetworkRuleConditions{InterfaceType: "wifi"}, Actions: WiFiNetworkActions{Protocol: "ikev2"}}}, "unsupported protocol"},
+		{"too many", make([]NetworkRule, MaxNetworkRules+1), "too many network rules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.ValidateNetworkRules(tt.rules)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("ValidateNetworkRules() unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("ValidateNetworkRules() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateNetworkRulesNormalizes(t *testing.T) {
+	p := Create()
+	p.ConnectionProfiles = []ConnectionProfile{{Name: "Work"}}
+
+	rules, err := p.ValidateNetworkRules([]NetworkRule{{
+		Name:       " office ",
+		Conditions: NetworkRuleConditions{InterfaceType: " Ethernet ", GatewayMAC: " AA:BB:CC:DD:EE:FF ", DnsSuffix: " corp.example.com "},
+		Profile:    "work",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rules[0]
+	if r.Name != "office" || r.Profile != "Work" {
+		t.Errorf("name/profile = %q/%q, want %q/%q", r.Name, r.Profile, "office", "Work")
+	}
+	if c := r.Conditions; c.InterfaceType != "ethernet" || c.GatewayMAC != "aa:bb:cc:dd:ee:ff" || c.DnsSuffix != "corp.example.com" {
+		t.Errorf("conditions are not normalized: %+v", c)
+	}
+}
+
+func TestNetworkRuleConditionsMatch(t *testing.T) {
+	mac, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	network := netinfo.DefaultNetworkInfo{
+		InterfaceName:    "enx001122334455",
+		InterfaceType:    netinfo.InterfaceTypeEthernet,
+		GatewayIP:        net.ParseIP("192.168.1.1"),
+		GatewayMAC:       mac,
+		DnsSearchDomains: []string{"office.corp.example.com", "lan"},
+	}
+	reachable := func(hostPort string) bool { return hostPort == "intranet:443" }
+
+	tests := []struct {
+		name    string
+		c       NetworkRuleConditions
+		network netinfo.DefaultNetworkInfo
+		want    bool
+	}{
+		{"no conditions", NetworkRuleConditions{}, network, false},
+		{"interface wildcard", NetworkRuleConditions{InterfaceName: "enx*"}, network, true},
+		{"interface mismatch", NetworkRuleConditions{InterfaceName: "wlan*"}, network, false},
+		{"interface type", NetworkRuleConditions{InterfaceType: "ethernet"}, network, true},
+		{"interface type mismatch", NetworkRuleConditions{InterfaceType: "wifi"}, network, false},
+		{"gateway ip", NetworkRuleConditions{GatewayIP: "192.168.1.1"}, network, true},
+		{"gateway ip mismatch", NetworkRuleConditions{GatewayIP: "192.168.0.1"}, network, false},
+		{"gateway mac other notation", NetworkRuleConditions{GatewayMAC: "AA-BB-CC-DD-EE-FF"}, network, true},
+		{"gateway mac mismatch", NetworkRuleConditions{GatewayMAC: "aa:bb:cc:dd:ee:00"}, network, false},
+		{"gateway mac unknown", NetworkRuleConditions{GatewayMAC: "aa:bb:cc:dd:ee:ff"}, netinfo.DefaultNetworkInfo{}, false},
+		{"dns suffix exact", NetworkRuleConditions{DnsSuffix: "lan"}, network, true},
+		{"dns suffix parent domain", NetworkRuleConditions{DnsSuffix: "Corp.Example.com."}, network, true},
+		{"dns suffix partial label", NetworkRuleConditions{DnsSuffix: "example.co"}, network, false},
+		{"reachable", NetworkRuleConditions{ReachableHost: "intranet:443"}, network, true},
+		{"not reachable", NetworkRuleConditions{ReachableHost: "intranet:80"}, network, false},
+		{"all conditions", NetworkRuleConditions{InterfaceName: "enx*", InterfaceType: "ethernet", GatewayIP: "192.168.1.1", DnsSuffix: "lan", ReachableHost: "intranet:443"}, network, true},
+		{"one condition fails", NetworkRuleConditions{InterfaceType: "ethernet", GatewayIP: "10.0.0.1"}, network, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.Match(tt.network, reachable); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("reachability is not checked when other conditions fail", func(t *testing.T) {
+		called := false
+		c := NetworkRuleConditions{InterfaceType: "wifi", ReachableHost: "intranet:443"}
+		c.Match(network, func(string) bool { called = true; return true })
+		if called {
+			t.Error("isReachable was called")
+		}
+	})
+	t.Run("no reachability checker", func(t *testing.T) {
+		if (NetworkRuleConditions{ReachableHost: "intranet:443"}).Match(network, nil) {
+			t.Error("Match() = true, want false")
+		}
+	})
+}
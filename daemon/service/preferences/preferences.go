@@ -26,6 +26,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -41,6 +42,7 @@ import (
 	"github.com/swapnilsparsh/devsVPN/daemon/obfsproxy"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/platform"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/splittun"
 	"github.com/swapnilsparsh/devsVPN/daemon/version"
 )
 
@@ -98,6 +100,9 @@ type Preferences struct {
 	// It allow to detect situations when settings was erased (created new Preferences object)
 	SettingsSessionUUID      string
 	IsLogging                bool
+	IsLogStructured          bool   // write log records as JSON lines (timestamp, level, component, caller, fields)
+	LogLevels                string // per-component log levels, e.g.: "frwl=trace,prefs=warn,*=info" (empty - log everything)
+	LogRotation              logger.RotationPolicy
 	IsFwPersistent           bool
 	IsFwAllowLAN             bool
 	IsFwAllowLANMulticast    bool
@@ -114,9 +119,23 @@ type Preferences struct {
 	//		-	after daemon initialization
 	//		-	on user session LogOn
 	IsAutoconnectOnLaunchDaemon    bool
+	AutoconnectProfile             string // name of the connection profile used for auto-connection (empty - the last connection parameters)
 	HealthchecksType               types.HealthchecksTypeEnum
 	PermissionReconfigureOtherVPNs bool
 
+	// Unix-domain socket transport for the daemon protocol (Linux, macOS).
+	// Clients connected over the socket are authorized by their peer credentials instead of the secret from the port file.
+	// The root user is always allowed; other users must be listed by UID or by GID of any of their groups (primary or supplementary).
+	// Configured by SetPreference request ('unix_socket_disabled', 'unix_socket_allowed_uids', 'unix_socket_allowed_gids')
+	// or by the 'unix_socket' section of the daemon configuration file (see package 'managedcfg').
+	IsUnixSocketDisabled  bool
+	UnixSocketAllowedUIDs []uint32
+	UnixSocketAllowedGIDs []uint32
+
+	// Prometheus/OpenMetrics endpoint (opt-in). Only loopback listen address is allowed.
+	IsMetricsEnabled     bool
+	MetricsListenAddress string // 'host:port'; empty - default address (metrics.DefaultListenAddress)
+
 	// split-tunnelling
 	IsTotalShieldOn           bool // note that privateLINE definition of Total Shield is the opposite of the IVPN definition of Split Tunnel
 	SplitTunnelApps           []string
@@ -125,6 +144,12 @@ type Preferences struct {
 	SplitTunnelAnyDns         bool // (only for Inverse Split Tunnel) When false: Allow only DNS servers specified by the IVPN application
 	SplitTunnelAllowWhenNoVpn bool // (only for Inverse Split Tunnel) Allow connectivity for Split Tunnel apps when VPN is disabled
 
+	// per-domain split-tunnelling rules: route the traffic to the domain through the VPN tunnel or bypass it
+	SplitTunnelDomains []splittun.DomainRule
+	// per-CIDR split-tunnelling rules (normalized IPv4 networks): route the traffic through the VPN tunnel ('include') or bypass it ('exclude')
+	SplitTunnelIncludeSubnets []string
+	SplitTunnelExcludeSubnets []string
+
 	// last known account status
 	Session SessionStatus
 	Account AccountStatus
@@ -135,10 +160,23 @@ type Preferences struct {
 	UserPrefs UserPreferences
 
 	LastConnectionParams types.ConnectionParams
+	ConnectionProfiles   []ConnectionProfile // named connection profiles
 	VpnEntryHostsParsed  []*VpnEntryHostParsed
 	AllDnsServersIPv4Set mapset.Set[string]
 
 	WiFiControl WiFiParams
+	// network-condition rules (wired interfaces, gateways, DNS suffixes ...); the first matching enabled rule is applied
+	NetworkRules []NetworkRule
+	// time-of-day rules (e.g. "VPN must be connected 08:00-18:00 on weekdays")
+	ScheduleRules []ScheduleRule
+	// thresholds of the WireGuard connection-quality monitor (and automatic server failover)
+	ConnectionQuality ConnectionQualityParams
+	// OpenVPN log verbosity ('verb' option, 1-11); 0 - default verbosity
+	OpenVpnLogVerbosity int
+	// automatic transport mode: the transport which worked on the network (key: "ssid:<SSID>" or "gw:<gateway MAC or IP>")
+	AutoTransportNetworks map[string]AutoTransportRecord
+	// VPN servers imported by the user from WireGuard/OpenVPN configuration files ("bring your own server")
+	CustomServers []CustomServer
 }
 
 type GetPrefsCallback func() Preferences
@@ -158,6 +196,8 @@ func Create() *Preferences {
 		HealthchecksType:               types.HealthchecksTypeDefault,
 		PermissionReconfigureOtherVPNs: false,
 		WiFiControl:                    WiFiParamsCreate(),
+		ConnectionQuality:              ConnectionQualityParamsCreate(),
+		LogRotation:                    logger.DefaultRotationPolicy(),
 	}
 }
 
@@ -206,6 +246,41 @@ func (p *Preferences) IsInverseSplitTunneling() bool {
 	return p.SplitTunnelInversed
 }
 
+// IsUnixSocketPeerAllowed returns 'true' if a client process with the given peer credentials is allowed to use the daemon Unix socket
+// (gids - the primary and the supplementary groups of the peer user)
+func (p *Preferences) IsUnixSocketPeerAllowed(uid uint32, gids []uint32) bool {
+	if uid == 0 {
+		return true
+	}
+	for _, allowedUID := range p.UnixSocketAllowedUIDs {
+		if uid == allowedUID {
+			return true
+		}
+	}
+	for _, allowedGID := range p.UnixSocketAllowedGIDs {
+		if slices.Contains(gids, allowedGID) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseIDList parses comma-separated list of user or group IDs (e.g. "1000,1001"); empty string - empty list
+func ParseIDList(val string) ([]uint32, error) {
+	var ret []uint32
+	for _, s := range strings.Split(val, ",") {
+		if s = strings.TrimSpace(s); len(s) == 0 {
+			continue
+		}
+		id, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("bad ID '%s'", s)
+		}
+		ret = append(ret, uint32(id))
+	}
+	return ret, nil
+}
+
 // SetSession save account credentials
 func (p *Preferences) SetSession(accountInfo AccountStatus,
 	accountID string,
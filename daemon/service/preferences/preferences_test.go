@@ -0,0 +1,80 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package preferences
+
+import "testing"
+
+func TestIsUnixSocketPeerAllowed(t *testing.T) {
+	p := Create()
+	p.UnixSocketAllowedUIDs = []uint32{1000}
+	p.UnixSocketAllowedGIDs = []uint32{27}
+
+	tests := []struct {
+		name string
+		uid  uint32
+		gids []uint32
+		want bool
+	}{
+		{"root", 0, []uint32{0}, true},
+		{"allowed uid", 1000, []uint32{1000}, true},
+		{"allowed primary group", 1001, []uint32{27}, true},
+		{"allowed supplementary group", 1001, []uint32{1001, 4, 27}, true},
+		{"not allowed", 1001, []uint32{1001, 4}, false},
+		{"no groups", 1001, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.IsUnixSocketPeerAllowed(tt.uid, tt.gids); got != tt.want {
+				t.Errorf("IsUnixSocketPeerAllowed(%d, %v) = %v, want %v", tt.uid, tt.gids, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIDList(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    []uint32
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"1000", []uint32{1000}, false},
+		{" 1000, 27 ,", []uint32{1000, 27}, false},
+		{"abc", nil, true},
+		{"-1", nil, true},
+		{"4294967296", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := ParseIDList(tt.val)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIDList(%q) error = %v, wantErr %v", tt.val, err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseIDList(%q) = %v, want %v", tt.val, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("ParseIDList(%q) = %v, want %v", tt.val, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package preferences
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+)
+
+const (
+	// MaxConnectionProfiles - the maximum number of named connection profiles
+	MaxConnectionProfiles = 64
+	// MaxConnectionProfileNameLength - the maximum length of the connection profile name (in characters)
+	MaxConnectionProfileNameLength = 64
+)
+
+// ConnectionProfile - named set of connection parameters
+// (e.g. "work multihop over V2Ray", "home fastest WireGuard", "travel obfs4 OpenVPN")
+type ConnectionProfile struct {
+	Name     string
+	Params   types.ConnectionParams
+	Created  time.Time
+	Modified time.Time
+}
+
+// NormalizeConnectionProfileName validates the profile name and returns it without leading and trailing spaces
+func NormalizeConnectionProfileName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		return "", fmt.Errorf("profile name is empty")
+	}
+	if len([]rune(name)) > MaxConnectionProfileNameLength {
+		return "", fmt.Errorf("profile name is too long (maximum %d characters)", MaxConnectionProfileNameLength)
+	}
+	for _, c := range name {
+		if unicode.IsControl(c) {
+			return "", fmt.Errorf("profile name contains control characters")
+		}
+	}
+	return name, nil
+}
+
+// ConnectionProfileIndex returns index of the connection profile with the name (case-insensitive) or -1 if not found
+func (p *Preferences) ConnectionProfileIndex(name string) int {
+	name = strings.TrimSpace(name)
+	for i, prof := range p.ConnectionProfiles {
+		if strings.EqualFold(prof.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ConnectionProfile returns the connection profile with the name (case-insensitive)
+func (p *Preferences) ConnectionProfile(name string) (ConnectionProfile, error) {
+	if idx := p.ConnectionProfileIndex(name); idx >= 0 {
+		return p.ConnectionProfiles[idx], nil
+	}
+	return ConnectionProfile{}, fmt.Errorf("connection profile '%s' not found", strings.TrimSpace(name))
+}
+
+// ConnectionProfileReferences returns the list of settings which refer to the connection profile
+// (auto-connect; WiFi networks; network rules; schedule rules)
+func (p *Preferences) ConnectionProfileReferences(name string) (refs []string) {
+	if len(p.AutoconnectProfile) > 0 && strings.EqualFold(p.AutoconnectProfile, name) {
+		refs = append(refs, "auto-connect")
+	}
+	for _, n := range p.WiFiControl.Networks {
+		if len(n.Profile) > 0 && strings.EqualFold(n.Profile, name) {
+			refs = append(refs, fmt.Sprintf("WiFi network '%s'", n.SSID))
+		}
+	}
+	for _, r := range p.NetworkRules {
+		if len(r.Profile) > 0 && strings.EqualFold(r.Profile, name) {
+			refs = append(refs, fmt.Sprintf("network rule '%s'", r.Name))
+		}
+	}
+	for _, r := range p.ScheduleRules {
+		if len(r.Profile) > 0 && strings.EqualFold(r.Profile, name) {
+			refs = append(refs, fmt.Sprintf("schedule rule '%s'", r.Name))
+		}
+	}
+	return refs
+}
+
+// renameConnectionProfileReferences updates (or removes, when newName is empty) references to the connection profile
+func (p *Preferences) renameConnectionProfileReferences(name, newName string) {
+	if len(p.AutoconnectProfile) > 0 && strings.EqualFold(p.AutoconnectProfile, name) {
+		p.AutoconnectProfile = newName
+	}
+	if len(p.WiFiControl.Networks) > 0 {
+		networks := make([]WiFiNetwork, len(p.WiFiControl.Networks))
+		copy(networks, p.WiFiControl.Networks) // do not modify the slice which can be shared with a copy of the preferences
+		for i, n := range networks {
+			if len(n.Profile) > 0 && strings.EqualFold(n.Profile, name) {
+				networks[i].Profile = newName
+			}
+		}
+		p.WiFiControl.Networks = networks
+	}
+	if len(p.NetworkRules) > 0 {
+		rules := make([]NetworkRule, len(p.NetworkRules))
+		copy(rules, p.NetworkRules)
+		for i, r := range rules {
+			if len(r.Profile) > 0 && strings.EqualFold(r.Profile, name) {
+				rules[i].Profile = newName
+			}
+		}
+		p.NetworkRules = rules
+	}
+	if len(p.ScheduleRules) > 0 {
+		rules := make([]ScheduleRule, len(p.ScheduleRules))
+		copy(rules, p.ScheduleRules)
+		for i, r := range rules {
+			if len(r.Profile) > 0 && strings.EqualFold(r.Profile, name) {
+				rules[i].Profile = newName
+			}
+		}
+		p.ScheduleRules = rules
+	}
+}
+
+// SaveConnectionProfile adds new connection profile or updates the existing one (only when 'overwrite' is true)
+func (p *Preferences) SaveConnectionProfile(name string, params types.ConnectionParams, overwrite bool) error {
+	name, err := NormalizeConnectionProfileName(name)
+	if err != nil {
+		return err
+	}
+	params.CanReconfigureOtherVpnsOnce = false
+
+	profiles := make([]ConnectionProfile, len(p.ConnectionProfiles))
+	copy(profiles, p.ConnectionProfiles)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if idx := p.ConnectionProfileIndex(name); idx >= 0 {
+		if !overwrite {
+			return fmt.Errorf("connection profile '%s' already exists", profiles[idx].Name)
+		}
+		profiles[idx].Params = params
+		profiles[idx].Modified = now
+	} else {
+		if len(profiles) >= MaxConnectionProfiles {
+			return fmt.Errorf("too many connection profiles (maximum %d)", MaxConnectionProfiles)
+		}
+		profiles = append(profiles, ConnectionProfile{Name: name, Params: params, Created: now, Modified: now})
+	}
+
+	p.ConnectionProfiles = profiles
+	return nil
+}
+
+// RenameConnectionProfile renames the connection profile; the references to it are updated
+func (p *Preferences) RenameConnectionProfile(name, newName string) error {
+	idx := p.ConnectionProfileIndex(name)
+	if idx < 0 {
+		return fmt.Errorf("connection profile '%s' not found", strings.TrimSpace(name))
+	}
+	newName, err := NormalizeConnectionProfileName(newName)
+	if err != nil {
+		return err
+	}
+	if i := p.ConnectionProfileIndex(newName); i >= 0 && i != idx {
+		return fmt.Errorf("connection profile '%s' already exists", p.ConnectionProfiles[i].Name)
+	}
+
+	profiles := make([]ConnectionProfile, len(p.ConnectionProfiles))
+	copy(profiles, p.ConnectionProfiles)
+	oldName := profiles[idx].Name
+	profiles[idx].Name = newName
+	profiles[idx].Modified = time.Now().UTC().Truncate(time.Second)
+
+	p.ConnectionProfiles = profiles
+	p.renameConnectionProfileReferences(oldName, newName)
+	return nil
+}
+
+// DeleteConnectionProfile removes the connection profile; the references to it are removed
+// (auto-connect, WiFi networks, network and schedule rules will use the last connection parameters)
+func (p *Preferences) DeleteConnectionProfile(name string) error {
+	idx := p.ConnectionProfileIndex(name)
+	if idx < 0 {
+		return fmt.Errorf("connection profile '%s' not found", strings.TrimSpace(name))
+	}
+
+	oldName := p.ConnectionProfiles[idx].Name
+	profiles := make([]ConnectionProfile, 0, len(p.ConnectionProfiles)-1)
+	profiles = append(profiles, p.ConnectionProfiles[:idx]...)
+	profiles = append(profiles, p.ConnectionProfiles[idx+1:]...)
+
+	p.ConnectionProfiles = profiles
+	p.renameConnectionProfileReferences(oldName, "")
+	return nil
+}
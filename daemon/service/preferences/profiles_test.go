@@ -0,0 +1,211 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package preferences
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+func TestNormalizeConnectionProfileName(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr string
+	}{
+		{"simple", "Work", "Work", ""},
+		{"trimmed", "  home fastest WireGuard ", "home fastest WireGuard", ""},
+		{"unicode", "Büro", "Büro", ""},
+		{"max length", strings.Repeat("ж", MaxConnectionProfileNameLength), strings.Repeat("ж", MaxConnectionProfileNameLength), ""},
+		{"empty", "   ", "", "name is empty"},
+		{"too long", strings.Repeat("a", MaxConnectionProfileNameLength+1), "", "too long"},
+		{"control characters", "work\tprofile", "", "control characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeConnectionProfileName(tt.in)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("NormalizeConnectionProfileName() unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("NormalizeConnectionProfileName() error = %v, want error containing %q", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeConnectionProfileName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveConnectionProfile(t *testing.T) {
+	p := Create()
+
+	params := types.ConnectionParams{VpnType: vpn.WireGuard, CanReconfigureOtherVpnsOnce: true}
+	if err := p.SaveConnectionProfile(" Work ", params, false); err != nil {
+		t.Fatalf("SaveConnectionProfile() unexpected error: %v", err)
+	}
+	prof, err := p.ConnectionProfile("WORK")
+	if err != nil {
+		t.Fatalf("ConnectionProfile() unexpected error: %v", err)
+	}
+	if prof.Name != "Work" {
+		t.Errorf("profile name = %q, want %q", prof.Name, "Work")
+	}
+	if prof.Params.CanReconfigureOtherVpnsOnce {
+		t.Error("transient CanReconfigureOtherVpnsOnce is saved in the profile")
+	}
+	if prof.Created.IsZero() || prof.Modified != prof.Created {
+		t.Errorf("unexpected profile times: created %v, modified %v", prof.Created, prof.Modified)
+	}
+
+	params.VpnType = vpn.OpenVPN
+	if err := p.SaveConnectionProfile("work", params, false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("SaveConnectionProfile() without overwrite: error = %v, want 'already exists'", err)
+	}
+	if err := p.SaveConnectionProfile("work", params, true); err != nil {
+		t.Fatalf("SaveConnectionProfile() with overwrite: unexpected error: %v", err)
+	}
+	if len(p.ConnectionProfiles) != 1 || p.ConnectionProfiles[0].Params.VpnType != vpn.OpenVPN || p.ConnectionProfiles[0].Name != "Work" {
+		t.Errorf("profile is not overwritten: %+v", p.ConnectionProfiles)
+	}
+
+	if err := p.SaveConnectionProfile(" ", params, false); err == nil {
+		t.Error("SaveConnectionProfile() with empty name: expected error")
+	}
+
+	if _, err := p.ConnectionProfile("home"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("ConnectionProfile() of unknown profile: error = %v, want 'not found'", err)
+	}
+}
+
+func TestSaveConnectionProfileLimit(t *testing.T) {
+	p := Create()
+	for i := 0; i < MaxConnectionProfiles; i++ {
+		if err := p.SaveConnectionProfile(strings.Repeat("p", i+1), types.ConnectionParams{}, false); err != nil {
+			t.Fatalf("SaveConnectionProfile() #%d unexpected error: %v", i, err)
+		}
+	}
+	if err := p.SaveConnectionProfile("one more", types.ConnectionParams{}, false); err == nil || !strings.Contains(err.Error(), "too many") {
+		t.Fatalf("SaveConnectionProfile() over the limit: error = %v, want 'too many'", err)
+	}
+	// updating an existing profile is still possible
+	if err := p.SaveConnectionProfile("p", types.ConnectionParams{}, true); err != nil {
+		t.Fatalf("SaveConnectionProfile() overwrite at the limit: unexpected error: %v", err)
+	}
+}
+
+// profilesWithReferences returns preferences with two profiles; the "Work" profile is used by auto-connect, WiFi network and rules
+func profilesWithReferences(t *testing.T) *Preferences {
+	t.Helper()
+	p := Create()
+	for _, name := range []string{"Work", "Home"} {
+		if err := p.SaveConnectionProfile(name, types.ConnectionParams{}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p.AutoconnectProfile = "work"
+	p.WiFiControl.Networks = []WiFiNetwork{{SSID: "office", Profile: "Work"}, {SSID: "cafe", Profile: "Home"}}
+	p.NetworkRules = []NetworkRule{{Name: "ethernet", Profile: "WORK"}}
+	p.ScheduleRules = []ScheduleRule{{Name: "weekdays", Profile: "Work"}}
+	return p
+}
+
+func TestConnectionProfileReferences(t *testing.T) {
+	p := profilesWithReferences(t)
+
+	want := []string{"auto-connect", "WiFi network 'office'", "network rule 'ethernet'", "schedule rule 'weekdays'"}
+	if got := p.ConnectionProfileReferences("Work"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ConnectionProfileReferences(Work) = %v, want %v", got, want)
+	}
+	want = []string{"WiFi network 'cafe'"}
+	if got := p.ConnectionProfileReferences("home"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ConnectionProfileReferences(home) = %v, want %v", got, want)
+	}
+}
+
+func TestRenameConnectionProfile(t *testing.T) {
+	p := profilesWithReferences(t)
+	networks := p.WiFiControl.Networks // the slice can be shared with a copy of the preferences
+
+	if err := p.RenameConnectionProfile("work", "home"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("RenameConnectionProfile() to existing name: error = %v, want 'already exists'", err)
+	}
+	if err := p.RenameConnectionProfile("office", "x"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("RenameConnectionProfile() of unknown profile: error = %v, want 'not found'", err)
+	}
+	if err := p.RenameConnectionProfile("work", " "); err == nil {
+		t.Fatal("RenameConnectionProfile() to empty name: expected error")
+	}
+
+	if err := p.RenameConnectionProfile("work", " Office "); err != nil {
+		t.Fatalf("RenameConnectionProfile() unexpected error: %v", err)
+	}
+	if _, err := p.ConnectionProfile("Office"); err != nil {
+		t.Errorf("renamed profile not found: %v", err)
+	}
+	if _, err := p.ConnectionProfile("Work"); err == nil {
+		t.Error("old profile name still exists")
+	}
+	if p.AutoconnectProfile != "Office" || p.WiFiControl.Networks[0].Profile != "Office" || p.NetworkRules[0].Profile != "Office" || p.ScheduleRules[0].Profile != "Office" {
+		t.Errorf("references are not renamed: auto-connect %q, WiFi %q, network rule %q, schedule rule %q",
+			p.AutoconnectProfile, p.WiFiControl.Networks[0].Profile, p.NetworkRules[0].Profile, p.ScheduleRules[0].Profile)
+	}
+	if p.WiFiControl.Networks[1].Profile != "Home" {
+		t.Errorf("reference to other profile is changed: %q", p.WiFiControl.Networks[1].Profile)
+	}
+	if networks[0].Profile != "Work" {
+		t.Error("WiFi networks slice of the original preferences is modified")
+	}
+
+	// changing the case of the name
+	if err := p.RenameConnectionProfile("office", "OFFICE"); err != nil {
+		t.Fatalf("RenameConnectionProfile() case change: unexpected error: %v", err)
+	}
+	if p.ConnectionProfiles[0].Name != "OFFICE" || p.AutoconnectProfile != "OFFICE" {
+		t.Errorf("case change is not applied: profile %q, auto-connect %q", p.ConnectionProfiles[0].Name, p.AutoconnectProfile)
+	}
+}
+
+func TestDeleteConnectionProfile(t *testing.T) {
+	p := profilesWithReferences(t)
+
+	if err := p.DeleteConnectionProfile("office"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("DeleteConnectionProfile() of unknown profile: error = %v, want 'not found'", err)
+	}
+	if err := p.DeleteConnectionProfile(" WORK "); err != nil {
+		t.Fatalf("DeleteConnectionProfile() unexpected error: %v", err)
+	}
+	if len(p.ConnectionProfiles) != 1 || p.ConnectionProfiles[0].Name != "Home" {
+		t.Errorf("unexpected profiles after delete: %+v", p.ConnectionProfiles)
+	}
+	if p.AutoconnectProfile != "" || p.WiFiControl.Networks[0].Profile != "" || p.NetworkRules[0].Profile != "" || p.ScheduleRules[0].Profile != "" {
+		t.Errorf("references are not removed: auto-connect %q, WiFi %q, network rule %q, schedule rule %q",
+			p.AutoconnectProfile, p.WiFiControl.Networks[0].Profile, p.NetworkRules[0].Profile, p.ScheduleRules[0].Profile)
+	}
+	if p.WiFiControl.Networks[1].Profile != "Home" {
+		t.Errorf("reference to other profile is removed: %q", p.WiFiControl.Networks[1].Profile)
+	}
+}
@@ -0,0 +1,72 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package preferences
+
+import "fmt"
+
+// ConnectionQualityParams - thresholds of the WireGuard connection-quality monitor.
+// Zero value of a threshold disables the corresponding check.
+type ConnectionQualityParams struct {
+	// true - when thresholds are crossed, reconnect to the next-best host of the registered server (according to the hosts latency)
+	FailoverEnabled bool `json:"failoverEnabled"`
+
+	CheckIntervalSec     int `json:"checkIntervalSec"`     // interval between quality checks
+	MaxHandshakeAgeSec   int `json:"maxHandshakeAgeSec"`   // maximum age of the latest WireGuard handshake
+	MaxRttMs             int `json:"maxRttMs"`             // maximum round-trip time to the VPN gateway (inside the tunnel)
+	MaxPacketLossPercent int `json:"maxPacketLossPercent"` // maximum packet loss to the VPN gateway (inside the tunnel)
+	StallTimeoutSec      int `json:"stallTimeoutSec"`      // maximum time when data is sent but nothing is received
+	BadChecksToFailover  int `json:"badChecksToFailover"`  // number of consecutive failed checks required to fail over
+}
+
+// ConnectionQualityParamsCreate returns the default thresholds of the connection-quality monitor
+func ConnectionQualityParamsCreate() ConnectionQualityParams {
+	return ConnectionQualityParams{
+		FailoverEnabled:      false,
+		CheckIntervalSec:     10,
+		MaxHandshakeAgeSec:   300, // 'PersistentKeepalive' is in use, so handshakes are renewed every 2 minutes
+		MaxRttMs:             1500,
+		MaxPacketLossPercent: 50,
+		StallTimeoutSec:      180, // must be longer than the interval of handshakes (the idle tunnel receives data only on handshakes)
+		BadChecksToFailover:  3,
+	}
+}
+
+// Validate checks the thresholds are correct
+func (p ConnectionQualityParams) Validate() error {
+	if p.CheckIntervalSec < 1 || p.CheckIntervalSec > 600 {
+		return fmt.Errorf("check interval must be in range 1-600 seconds")
+	}
+	if p.BadChecksToFailover < 1 || p.BadChecksToFailover > 100 {
+		return fmt.Errorf("number of failed checks to fail over must be in range 1-100")
+	}
+	if p.MaxHandshakeAgeSec < 0 || p.MaxRttMs < 0 || p.StallTimeoutSec < 0 {
+		return fmt.Errorf("thresholds must not be negative")
+	}
+	if p.MaxHandshakeAgeSec > 0 && p.MaxHandshakeAgeSec < 150 {
+		return fmt.Errorf("maximum handshake age must be at least 150 seconds (WireGuard renews handshakes every 2 minutes)")
+	}
+	if p.StallTimeoutSec > 0 && p.StallTimeoutSec < 150 {
+		return fmt.Errorf("stall timeout must be at least 150 seconds (the idle tunnel receives data only on handshakes)")
+	}
+	if p.MaxPacketLossPercent < 0 || p.MaxPacketLossPercent > 100 {
+		return fmt.Errorf("maximum packet loss must be in range 0-100 percent")
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package preferences
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxScheduleRules - the maximum number of schedule rules
+const MaxScheduleRules = 32
+
+// ScheduleAction - the action of the schedule rule
+type ScheduleAction string
+
+const (
+	ScheduleActionConnect    ScheduleAction = "connect"    // connect VPN at the start time (and disconnect at the end time, if VPN was connected by the schedule)
+	ScheduleActionDisconnect ScheduleAction = "disconnect" // disconnect VPN at the start time (and connect at the end time, if VPN was disconnected by the schedule)
+	ScheduleActionResume     ScheduleAction = "resume"     // resume the paused connection at the start time
+)
+
+// scheduleTimeLayout - layout of the 'Start' and 'End' time of the schedule rule
+const scheduleTimeLayout = "15:04"
+
+// scheduleDays - short names of week days (index is time.Weekday)
+var scheduleDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ScheduleRule - time-of-day rule (e.g. "VPN must be connected 08:00-18:00 on weekdays").
+// The rule with 'End' time defines a time window: the required VPN state is applied when the window starts
+// (or when the daemon starts within the window) and the original state is restored when the window ends.
+// The rule without 'End' time is a one-shot action at the 'Start' time.
+type ScheduleRule struct {
+	Name     string         `json:"name"`
+	Disabled bool           `json:"disabled,omitempty"`
+	Days     []string       `json:"days,omitempty"` // week days: "mon", "tue" ... "sun" (empty - every day)
+	Start    string         `json:"start"`          // local time "HH:MM"
+	End      string         `json:"end,omitempty"`  // local time "HH:MM"; when it is less than 'Start' - the window ends on the next day
+	Action   ScheduleAction `json:"action"`
+	Profile  string         `json:"profile,omitempty"` // connection profile used to connect VPN (empty - the default auto-connection parameters)
+}
+
+// ScheduleTransition - the moment when the schedule rule is applied
+type ScheduleTransition struct {
+	Time      time.Time
+	Rule      string
+	Action    ScheduleAction // action of the rule
+	WindowEnd bool           // true - the end of the rule time window (the state before the window has to be restored)
+}
+
+// IsWindow returns true when the rule defines a time window (not a one-shot action)
+func (r ScheduleRule) IsWindow() bool {
+	return len(r.End) > 0
+}
+
+// Validate checks the rule is correct
+func (r ScheduleRule) Validate() error {
+	switch r.Action {
+	case ScheduleActionConnect, ScheduleActionDisconnect, ScheduleActionResume:
+	default:
+		return fmt.Errorf("unsupported action '%s' (acceptable values: %s, %s, %s)", r.Action,
+			ScheduleActionConnect, ScheduleActionDisconnect, ScheduleActionResume)
+	}
+	for _, d := range r.Days {
+		if parseScheduleDay(d) < 0 {
+			return fmt.Errorf("bad week day '%s' (acceptable values: %s)", d, strings.Join(scheduleDays, ", "))
+		}
+	}
+	start, err := time.Parse(scheduleTimeLayout, r.Start)
+	if err != nil {
+		return fmt.Errorf("bad start time '%s' (expected format: 'HH:MM')", r.Start)
+	}
+	if r.IsWindow() {
+		end, err := time.Parse(scheduleTimeLayout, r.End)
+		if err != nil {
+			return fmt.Errorf("bad end time '%s' (expected format: 'HH:MM')", r.End)
+		}
+		if end.Equal(start) {
+			return fmt.Errorf("start and end time are equal")
+		}
+		if r.Action == ScheduleActionResume {
+			return fmt.Errorf("end time is not applicable for '%s' action", ScheduleActionResume)
+		}
+	}
+	if len(r.Profile) > 0 && r.Action == ScheduleActionResume {
+		return fmt.Errorf("connection profile is not applicable for '%s' action", ScheduleActionResume)
+	}
+	return nil
+}
+
+// isDayEnabled returns true when the rule is applicable for the week day
+func (r ScheduleRule) isDayEnabled(d time.Weekday) bool {
+	if len(r.Days) == 0 {
+		return true
+	}
+	for _, day := range r.Days {
+		if parseScheduleDay(day) == d {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionsForDay returns transitions of the rule which window starts on the day of 'date' (local time)
+func (r ScheduleRule) transitionsForDay(date time.Time) (ret []ScheduleTransition) {
+	if r.Disabled || !r.isDayEnabled(date.Weekday()) {
+		return nil
+	}
+	start, err := time.Parse(scheduleTimeLayout, r.Start)
+	if err != nil {
+		return nil
+	}
+	y, m, d := date.Date()
+	startTime := time.Date(y, m, d, start.Hour(), start.Minute(), 0, 0, date.Location())
+	ret = append(ret, ScheduleTransition{Time: startTime, Rule: r.Name, Action: r.Action})
+
+	if r.IsWindow() {
+		end, err := time.Parse(scheduleTimeLayout, r.End)
+		if err != nil {
+			return ret
+		}
+		endTime := time.Date(y, m, d, end.Hour(), end.Minute(), 0, 0, date.Location())
+		if !endTime.After(startTime) {
+			endTime = time.Date(y, m, d+1, end.Hour(), end.Minute(), 0, 0, date.Location())
+		}
+		ret = append(ret, ScheduleTransition{Time: endTime, Rule: r.Name, Action: r.Action, WindowEnd: true})
+	}
+	return ret
+}
+
+// ScheduleTransitions returns the transitions of enabled rules within the time range ('from' < t <= 'to'), sorted by time
+func ScheduleTransitions(rules []ScheduleRule, from, to time.Time) (ret []ScheduleTransition) {
+	if !to.After(from) {
+		return nil
+	}
+	// the window which starts on the previous day can end within the range
+	y, m, d := from.Date()
+	for day := time.Date(y, m, d-1, 0, 0, 0, 0, from.Location()); !day.After(to); day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, day.Location()) {
+		for _, r := range rules {
+			for _, t := range r.transitionsForDay(day) {
+				if t.Time.After(from) && !t.Time.After(to) {
+					ret = append(ret, t)
+				}
+			}
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].Time.Before(ret[j].Time) })
+	return ret
+}
+
+// NextScheduleTransition returns the first transition after 'now' (false - no enabled rules)
+func NextScheduleTransition(rules []ScheduleRule, now time.Time) (ScheduleTransition, bool) {
+	// all rules are repeated weekly: it is enough to check the next 8 days
+	transitions := ScheduleTransitions(rules, now, now.AddDate(0, 0, 8))
+	if len(transitions) == 0 {
+		return ScheduleTransition{}, false
+	}
+	return transitions[0], true
+}
+
+// ActiveScheduleWindows returns the start transitions of enabled rules which time window contains 'now'
+func ActiveScheduleWindows(rules []ScheduleRule, now time.Time) (ret []ScheduleTransition) {
+	y, m, d := now.Date()
+	for _, r := range rules {
+		if !r.IsWindow() {
+			continue
+		}
+		// the window could start today or yesterday (when it ends on the next day)
+		for _, day := range []time.Time{time.Date(y, m, d, 0, 0, 0, 0, now.Location()), time.Date(y, m, d-1, 0, 0, 0, 0, now.Location())} {
+			t := r.transitionsForDay(day)
+			if len(t) == 2 && !now.Before(t[0].Time) && now.Before(t[1].Time) {
+				ret = append(ret, t[0])
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// ValidateScheduleRules checks the rules are correct and returns them normalized
+func (p *Preferences) ValidateScheduleRules(rules []ScheduleRule) ([]ScheduleRule, error) {
+	if len(rules) > MaxScheduleRules {
+		return nil, fmt.Errorf("too many schedule rules (maximum %d)", MaxScheduleRules)
+	}
+
+	names := make(map[string]struct{}, len(rules))
+	ret := make([]ScheduleRule, 0, len(rules))
+	for _, r := range rules {
+		r = r.normalized()
+		if len(r.Name) == 0 {
+			return nil, fmt.Errorf("schedule rule name is empty")
+		}
+		if _, ok := names[strings.ToLower(r.Name)]; ok {
+			return nil, fmt.Errorf("duplicate schedule rule '%s'", r.Name)
+		}
+		names[strings.ToLower(r.Name)] = struct{}{}
+
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("schedule rule '%s': %w", r.Name, err)
+		}
+
+		if len(r.Profile) > 0 {
+			prof, err := p.ConnectionProfile(r.Profile)
+			if err != nil {
+				return nil, fmt.Errorf("schedule rule '%s': %w", r.Name, err)
+			}
+			r.Profile = prof.Name
+		}
+		ret = append(ret, r)
+	}
+	return ret, nil
+}
+
+// normalized returns the rule with trimmed values; week days are converted to short names ("Monday" -> "mon")
+func (r ScheduleRule) normalized() ScheduleRule {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Start = strings.TrimSpace(r.Start)
+	r.End = strings.TrimSpace(r.End)
+	r.Action = ScheduleAction(strings.ToLower(strings.TrimSpace(string(r.Action))))
+	r.Profile = strings.TrimSpace(r.Profile)
+
+	var days []string
+	for _, d := range r.Days {
+		if idx := parseScheduleDay(d); idx >= 0 {
+			d = scheduleDays[idx]
+		}
+		days = append(days, d)
+	}
+	r.Days = days
+	return r
+}
+
+// parseScheduleDay returns week day by its name ("mon", "Monday" ...); -1 - unknown name
+func parseScheduleDay(name string) time.Weekday {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) < 3 {
+		return -1
+	}
+	for i, d := range scheduleDays {
+		if strings.HasPrefix(name, d) && strings.HasPrefix(strings.ToLower(time.Weekday(i).String()), name) {
+			return time.Weekday(i)
+		}
+	}
+	return -1
+}
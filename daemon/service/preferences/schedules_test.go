@@ -0,0 +1,197 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package preferences
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 2026-10-12 is Monday
+func scheduleTestTime(day, hour, min int) time.Time {
+	return time.Date(2026, 10, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestScheduleRuleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    ScheduleRule
+		wantErr string
+	}{
+		{"window", ScheduleRule{Start: "08:00", End: "18:00", Action: ScheduleActionConnect, Days: []string{"mon", "fri"}}, ""},
+		{"overnight window", ScheduleRule{Start: "22:00", End: "06:00", Action: ScheduleActionDisconnect}, ""},
+		{"one-shot", ScheduleRule{Start: "09:30", Action: ScheduleActionResume}, ""},
+		{"bad action", ScheduleRule{Start: "08:00", Action: "pause"}, "unsupported action"},
+		{"bad day", ScheduleRule{Start: "08:00", Action: ScheduleActionConnect, Days: []string{"mo"}}, "bad week day"},
+		{"bad start", ScheduleRule{Start: "8am", Action: ScheduleActionConnect}, "bad start time"},
+		{"bad end", ScheduleRule{Start: "08:00", End: "25:00", Action: ScheduleActionConnect}, "bad end time"},
+		{"empty window", ScheduleRule{Start: "08:00", End: "08:00", Action: ScheduleActionConnect}, "are equal"},
+		{"resume window", ScheduleRule{Start: "08:00", End: "09:00", Action: ScheduleActionResume}, "end time is not applicable"},
+		{"resume with profile", ScheduleRule{Start: "08:00", Action: ScheduleActionResume, Profile: "work"}, "profile is not applicable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate() unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleTransitions(t *testing.T) {
+	weekdays := ScheduleRule{Name: "work", Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "08:00", End: "18:00", Action: ScheduleActionConnect}
+	overnight := ScheduleRule{Name: "night", Start: "22:00", End: "06:00", Action: ScheduleActionDisconnect}
+	oneShot := ScheduleRule{Name: "resume", Start: "12:00", Action: ScheduleActionResume}
+	disabled := ScheduleRule{Name: "off", Disabled: true, Start: "10:00", Action: ScheduleActionConnect}
+
+	type tr struct {
+		time      time.Time
+		rule      string
+		windowEnd bool
+	}
+	tests := []struct {
+		name     string
+		rules    []ScheduleRule
+		from, to time.Time
+		want     []tr
+	}{
+		{"weekday window", []ScheduleRule{weekdays}, scheduleTestTime(12, 0, 0), scheduleTestTime(13, 0, 0),
+			[]tr{{scheduleTestTime(12, 8, 0), "work", false}, {scheduleTestTime(12, 18, 0), "work", true}}},
+		{"weekend has no transitions", []ScheduleRule{weekdays}, scheduleTestTime(17, 0, 0), scheduleTestTime(19, 0, 0), nil},
+		{"range bounds: 'from' excluded, 'to' included", []ScheduleRule{weekdays}, scheduleTestTime(12, 8, 0), scheduleTestTime(12, 18, 0),
+			[]tr{{scheduleTestTime(12, 18, 0), "work", true}}},
+		{"overnight window ends next day", []ScheduleRule{overnight}, scheduleTestTime(12, 12, 0), scheduleTestTime(13, 12, 0),
+			[]tr{{scheduleTestTime(12, 22, 0), "night", false}, {scheduleTestTime(13, 6, 0), "night", true}}},
+		{"overnight window started the day before", []ScheduleRule{overnight}, scheduleTestTime(13, 0, 0), scheduleTestTime(13, 12, 0),
+			[]tr{{scheduleTestTime(13, 6, 0), "night", true}}},
+		{"sorted across rules; disabled ignored", []ScheduleRule{overnight, oneShot, disabled}, scheduleTestTime(12, 5, 0), scheduleTestTime(12, 23, 0),
+			[]tr{{scheduleTestTime(12, 6, 0), "night", true}, {scheduleTestTime(12, 12, 0), "resume", false}, {scheduleTestTime(12, 22, 0), "night", false}}},
+		{"empty range", []ScheduleRule{oneShot}, scheduleTestTime(12, 12, 0), scheduleTestTime(12, 12, 0), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []tr
+			for _, x := range ScheduleTransitions(tt.rules, tt.from, tt.to) {
+				got = append(got, tr{x.Time, x.Rule, x.WindowEnd})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ScheduleTransitions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextScheduleTransition(t *testing.T) {
+	weekend := ScheduleRule{Name: "weekend", Days: []string{"sat"}, Start: "10:00", Action: ScheduleActionConnect}
+
+	next, ok := NextScheduleTransition([]ScheduleRule{weekend}, scheduleTestTime(17, 10, 0)) // Saturday 10:00 (not after 'now')
+	if !ok || !next.Time.Equal(scheduleTestTime(24, 10, 0)) {
+		t.Errorf("NextScheduleTransition() = %v, %v; want %v", next.Time, ok, scheduleTestTime(24, 10, 0))
+	}
+
+	if _, ok := NextScheduleTransition([]ScheduleRule{{Name: "off", Disabled: true, Start: "10:00", Action: ScheduleActionConnect}}, scheduleTestTime(12, 0, 0)); ok {
+		t.Error("NextScheduleTransition() returned a transition for a disabled rule")
+	}
+}
+
+func TestActiveScheduleWindows(t *testing.T) {
+	rules := []ScheduleRule{
+		{Name: "work", Days: []string{"mon"}, Start: "08:00", End: "18:00", Action: ScheduleActionConnect},
+		{Name: "night", Days: []string{"mon"}, Start: "22:00", End: "06:00", Action: ScheduleActionDisconnect},
+		{Name: "resume", Start: "00:00", Action: ScheduleActionResume},
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want []string
+	}{
+		{"before window", scheduleTestTime(12, 7, 59), nil},
+		{"window start is included", scheduleTestTime(12, 8, 0), []string{"work"}},
+		{"window end is excluded", scheduleTestTime(12, 18, 0), nil},
+		{"overnight window on the same day", scheduleTestTime(12, 23, 0), []string{"night"}},
+		{"overnight window on the next day", scheduleTestTime(13, 5, 59), []string{"night"}},
+		{"overnight window ended", scheduleTestTime(13, 6, 0), nil},
+		{"other day", scheduleTestTime(13, 9, 0), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, w := range ActiveScheduleWindows(rules, tt.now) {
+				got = append(got, w.Rule)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ActiveScheduleWindows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateScheduleRules(t *testing.T) {
+	p := Create()
+	p.ConnectionProfiles = []ConnectionProfile{{Name: "Work"}}
+
+	rules, err := p.ValidateScheduleRules([]ScheduleRule{{Name: " office ", Days: []string{"Monday", " FRI"}, Start: " 08:00", End: "18:00 ", Action: " Connect", Profile: "work"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ScheduleRule{Name: "office", Days: []string{"mon", "fri"}, Start: "08:00", End: "18:00", Action: ScheduleActionConnect, Profile: "Work"}
+	if !reflect.DeepEqual(rules[0], want) {
+		t.Errorf("ValidateScheduleRules() = %+v, want %+v", rules[0], want)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		rules   []ScheduleRule
+		wantErr string
+	}{
+		{"empty name", []ScheduleRule{{Start: "08:00", Action: ScheduleActionConnect}}, "name is empty"},
+		{"duplicate name", []ScheduleRule{{Name: "a", Start: "08:00", Action: ScheduleActionConnect}, {Name: "A", Start: "09:00", Action: ScheduleActionConnect}}, "duplicate schedule rule"},
+		{"unknown profile", []ScheduleRule{{Name: "a", Start: "08:00", Action: ScheduleActionConnect, Profile: "home"}}, "not found"},
+		{"too many", make([]ScheduleRule, MaxScheduleRules+1), "too many schedule rules"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := p.ValidateScheduleRules(tt.rules); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateScheduleRules() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseScheduleDay(t *testing.T) {
+	tests := map[string]time.Weekday{
+		"mon": time.Monday, "Monday": time.Monday, " SUN ": time.Sunday, "thurs": time.Thursday, "sat": time.Saturday,
+		"mo": -1, "": -1, "monx": -1, "holiday": -1,
+	}
+	for name, want := range tests {
+		if got := parseScheduleDay(name); got != want {
+			t.Errorf("parseScheduleDay(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -22,9 +22,97 @@
 
 package preferences
 
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Protocols applicable for WiFiNetworkActions.Protocol
+const (
+	WiFiProtocolWireGuard          = "wireguard"
+	WiFiProtocolWireGuardV2RayQuic = "wireguard_v2ray_quic"
+	WiFiProtocolWireGuardV2RayTcp  = "wireguard_v2ray_tcp"
+	WiFiProtocolWireGuardV2RayWs   = "wireguard_v2ray_ws"
+	WiFiProtocolWireGuardV2RayGrpc = "wireguard_v2ray_grpc"
+	WiFiProtocolWireGuardV2RayH2   = "wireguard_v2ray_h2"
+	WiFiProtocolOpenVPN            = "openvpn"
+)
+
+// MaxWiFiNetworkDnsServers - max number of custom DNS servers defined for the WiFi network
+const MaxWiFiNetworkDnsServers = 4
+
 type WiFiNetwork struct {
 	SSID      string `json:"ssid"`
 	IsTrusted bool   `json:"isTrusted"`
+	Profile   string `json:"profile,omitempty"` // connection profile used to connect VPN when joining the network (empty - the last connection parameters)
+
+	// Actions for this network. When defined - they override the common actions (WiFiParams.Actions)
+	Actions *WiFiNetworkActions `json:"actions,omitempty"`
+}
+
+// WiFiNetworkActions - action set for the specific WiFi network.
+// The 'nil' (or empty) values mean the common action (according to the trust status of the network) is in use.
+type WiFiNetworkActions struct {
+	ConnectVpn     *bool `json:"connectVpn,omitempty"` // true - connect VPN; false - disconnect VPN
+	EnableFirewall *bool `json:"enableFirewall,omitempty"`
+	BlockLan       *bool `json:"blockLan,omitempty"`
+	TotalShield    *bool `json:"totalShield,omitempty"` // true - enable Total Shield; false - disable Total Shield
+
+	// Parameters of the VPN connection (in use when VPN gets connected on joining the network).
+	// They are applied on top of the connection profile (or the last connection parameters).
+	Location   string   `json:"location,omitempty"`   // gateway ID (e.g. "us-tx") or country code (e.g. "US"); for WireGuard - selects hosts of the registered server
+	Protocol   string   `json:"protocol,omitempty"`   // one of WiFiProtocol... values
+	DnsServers []string `json:"dnsServers,omitempty"` // custom DNS servers
+}
+
+// IsEmpty returns true when no any action defined
+func (a WiFiNetworkActions) IsEmpty() bool {
+	return a.ConnectVpn == nil && a.EnableFirewall == nil && a.BlockLan == nil && a.TotalShield == nil &&
+		len(a.Location) == 0 && len(a.Protocol) == 0 && len(a.DnsServers) == 0
+}
+
+// HasConnectionParams returns true when the actions modify VPN connection parameters
+func (a WiFiNetworkActions) HasConnectionParams() bool {
+	return len(a.Location) > 0 || len(a.Protocol) > 0 || len(a.DnsServers) > 0
+}
+
+// Validate checks the actions are consistent
+func (a WiFiNetworkActions) Validate() error {
+	if a.EnableFirewall != nil && !*a.EnableFirewall {
+		return fmt.Errorf("disabling Firewall is not supported (the Firewall is always enabled); use 'default' to keep the Firewall state unchanged")
+	}
+
+	if len(a.Protocol) > 0 {
+		switch a.Protocol {
+		case WiFiProtocolWireGuard, WiFiProtocolWireGuardV2RayQuic, WiFiProtocolWireGuardV2RayTcp,
+			WiFiProtocolWireGuardV2RayWs, WiFiProtocolWireGuardV2RayGrpc, WiFiProtocolWireGuardV2RayH2, WiFiProtocolOpenVPN:
+		default:
+			return fmt.Errorf("unsupported protocol '%s' (acceptable values: %s)", a.Protocol,
+				strings.Join([]string{WiFiProtocolWireGuard, WiFiProtocolWireGuardV2RayQuic, WiFiProtocolWireGuardV2RayTcp,
+					WiFiProtocolWireGuardV2RayWs, WiFiProtocolWireGuardV2RayGrpc, WiFiProtocolWireGuardV2RayH2, WiFiProtocolOpenVPN}, ", "))
+		}
+	}
+
+	if len(a.DnsServers) > MaxWiFiNetworkDnsServers {
+		return fmt.Errorf("too many DNS servers (max %d)", MaxWiFiNetworkDnsServers)
+	}
+	for _, d := range a.DnsServers {
+		if ip := net.ParseIP(strings.TrimSpace(d)); ip == nil || ip.IsUnspecified() {
+			return fmt.Errorf("bad DNS server address '%s'", d)
+		}
+	}
+	return nil
+}
+
+// DnsServerIPs returns parsed custom DNS servers
+func (a WiFiNetworkActions) DnsServerIPs() (ret []net.IP) {
+	for _, d := range a.DnsServers {
+		if ip := net.ParseIP(strings.TrimSpace(d)); ip != nil {
+			ret = append(ret, ip)
+		}
+	}
+	return ret
 }
 
 type WiFiParams struct {
@@ -57,3 +145,20 @@ func WiFiParamsCreate() WiFiParams {
 	p.Actions.TrustedDisableFirewall = true
 	return p
 }
+
+// IsBlockLanPossible returns true when joining any WiFi network can force blocking LAN
+// (by the common 'untrusted' actions or by the network-specific action)
+func (p WiFiParams) IsBlockLanPossible() bool {
+	if !p.TrustedNetworksControl {
+		return false
+	}
+	if p.Actions.UnTrustedBlockLan {
+		return true
+	}
+	for _, n := range p.Networks {
+		if n.Actions != nil && n.Actions.BlockLan != nil && *n.Actions.BlockLan {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package preferences
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWiFiNetworkActionsValidate(t *testing.T) {
+	on, off := true, false
+
+	tests := []struct {
+		name    string
+		actions WiFiNetworkActions
+		wantErr string
+	}{
+		{"empty", WiFiNetworkActions{}, ""},
+		{"connect and firewall", WiFiNetworkActions{ConnectVpn: &on, EnableFirewall: &on, BlockLan: &on, TotalShield: &off}, ""},
+		{"disconnect", WiFiNetworkActions{ConnectVpn: &off}, ""},
+		{"block LAN without firewall action", WiFiNetworkActions{BlockLan: &on}, ""},
+		{"firewall can not be disabled", WiFiNetworkActions{EnableFirewall: &off}, "disabling Firewall is not supported"},
+		{"wireguard", WiFiNetworkActions{Protocol: WiFiProtocolWireGuard}, ""},
+		{"v2ray websocket", WiFiNetworkActions{Protocol: WiFiProtocolWireGuardV2RayWs}, ""},
+		{"openvpn", WiFiNetworkActions{Protocol: WiFiProtocolOpenVPN}, ""},
+		{"bad protocol", WiFiNetworkActions{Protocol: "ikev2"}, "unsupported protocol 'ikev2'"},
+		{"protocol is case-sensitive", WiFiNetworkActions{Protocol: "WireGuard"}, "unsupported protocol"},
+		{"dns servers", WiFiNetworkActions{DnsServers: []string{"1.1.1.1", " 2606:4700:4700::1111 "}}, ""},
+		{"too many dns servers", WiFiNetworkActions{DnsServers: []string{"1.1.1.1", "1.0.0.1", "8.8.8.8", "8.8.4.4", "9.9.9.9"}}, "too many DNS servers"},
+		{"dns host name", WiFiNetworkActions{DnsServers: []string{"dns.example.com"}}, "bad DNS server address"},
+		{"unspecified dns", WiFiNetworkActions{DnsServers: []string{"0.0.0.0"}}, "bad DNS server address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.actions.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate() unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWiFiNetworkActionsState(t *testing.T) {
+	on := true
+
+	tests := []struct {
+		name                 string
+		actions              WiFiNetworkActions
+		wantEmpty            bool
+		wantConnectionParams bool
+	}{
+		{"empty", WiFiNetworkActions{}, true, false},
+		{"connect only", WiFiNetworkActions{ConnectVpn: &on}, false, false},
+		{"total shield only", WiFiNetworkActions{TotalShield: &on}, false, false},
+		{"location", WiFiNetworkActions{Location: "us-tx"}, false, true},
+		{"protocol", WiFiNetworkActions{Protocol: WiFiProtocolOpenVPN}, false, true},
+		{"dns", WiFiNetworkActions{DnsServers: []string{"1.1.1.1"}}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.actions.IsEmpty(); got != tt.wantEmpty {
+				t.Errorf("IsEmpty() = %v, want %v", got, tt.wantEmpty)
+			}
+			if got := tt.actions.HasConnectionParams(); got != tt.wantConnectionParams {
+				t.Errorf("HasConnectionParams() = %v, want %v", got, tt.wantConnectionParams)
+			}
+		})
+	}
+}
+
+func TestWiFiNetworkActionsDnsServerIPs(t *testing.T) {
+	a := WiFiNetworkActions{DnsServers: []string{" 1.1.1.1", "bad", "2606:4700:4700::1111"}}
+	want := []net.IP{net.ParseIP("1.1.1.1"), net.ParseIP("2606:4700:4700::1111")}
+	if got := a.DnsServerIPs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DnsServerIPs() = %v, want %v", got, want)
+	}
+}
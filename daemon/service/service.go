@@ -50,6 +50,9 @@ import (
 	"github.com/swapnilsparsh/devsVPN/daemon/rageshake"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/firewall"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/history"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/managedcfg"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/metrics"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/platform"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/platform/filerights"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
@@ -105,9 +108,11 @@ type Service struct {
 	_api               *api.API
 	_serversUpdater    IServersUpdater
 	_netChangeDetector INetChangeDetector
+	_netRulesDetector  INetChangeDetector // detects network changes for the network-condition rules (independent from VPN connection)
 	_wgKeysMgr         IWgKeysManager
 	_vpn               vpn.Process
 	_preferences       preferences.Preferences
+	_preferencesMutex  sync.Mutex // serializes preferences updates; use setPreferences()/updatePreferences()
 	_connectMutex      sync.Mutex
 
 	// Additional information about current VPN connection: outbound IP addresses, local VPN addresses
@@ -118,6 +123,8 @@ type Service struct {
 	// Required VPN state which service is going to reach (disconnect->keep connection->connect)
 	// When KeepConnection - reconnects immediately after disconnection
 	_requiredVpnState RequiredState
+	// number of Disconnect() calls; used to detect disconnection requests between the connection attempts of automatic transport mode
+	_disconnectRequestsCnt atomic.Uint64
 
 	// Note: Disconnect() function will wait until VPN fully disconnects
 	_done chan struct{}
@@ -126,6 +133,12 @@ type Service struct {
 	// to stop -> write to channel (it is synchronous channel)
 	_sessionCheckerStopChn chan struct{}
 
+	// nil - when the network rules monitor stopped
+	_netRulesMonitorStop chan struct{}
+
+	// nil - when the schedule monitor stopped
+	_scheduleMonitorStop chan struct{}
+
 	// when true - necessary to update account status as soon as it will be possible (e.g. on firewall disconnected)
 	_isNeedToUpdateSessionInfo bool
 
@@ -142,6 +155,34 @@ type Service struct {
 		_killSwitchState bool      // killswitch state before pause (to be able to restore it)
 	}
 
+	// state of the connection in automatic transport mode
+	_autoTransport struct {
+		_mutex       sync.Mutex
+		_active      bool                           // connection in automatic transport mode is in progress
+		_attempt     types.AutoTransportAttemptInfo // the current attempt
+		_isLast      bool                           // the current attempt is the last one
+		_isConnected bool                           // the current attempt reached CONNECTED state
+	}
+
+	// state of the WireGuard connection-quality monitor
+	_quality struct {
+		_mutex       sync.Mutex
+		_status      types.ConnectionQualityStatus
+		_failedHosts map[string]time.Time // endpoint IP -> time of the failover from this host
+	}
+
+	// state of the WireGuard path MTU discovery
+	_pmtu struct {
+		_mutex         sync.Mutex
+		_discoveredMtu int // 0 - not detected (yet)
+	}
+
+	// the user-imported server of the current connection
+	_customServer struct {
+		_mutex  sync.Mutex
+		_active *preferences.CustomServer // nil - privateLINE server
+	}
+
 	// variables related to connection test (e.g. ports accessibility test)
 	_connectionTest connTest
 
@@ -153,6 +194,10 @@ type Service struct {
 	_tmpParams      service_types.ConnectionParams
 	_tmpParamsMutex sync.Mutex
 
+	// headless daemon configuration file (declarative provisioning); nil - configuration file not defined
+	_managedCfg      *managedcfg.Config
+	_managedCfgMutex sync.Mutex
+
 	_statsCallbacks       protocol.StatsCallbacks
 	_vpnConnectedCallback protocolTypes.VpnConnectedCallback
 
@@ -171,6 +216,16 @@ type Service struct {
 	connectionAttemptTimeoutMonitorRunningMutex, connectionAttemptTimeoutMonitorStopFuncMutex sync.Mutex
 	connectionAttemptTimeoutMonitor_endchan                                                   chan bool
 
+	// connectionQualityBackgroundMonitor data
+	connectionQualityBackgroundMonitorDef                         *srvhelpers.ServiceBackgroundMonitor
+	connectionQualityRunningMutex, connectionQualityStopFuncMutex sync.Mutex
+	stopConnectionQualityMonitor                                  chan bool
+
+	// pmtuBackgroundMonitor data
+	pmtuBackgroundMonitorDef            *srvhelpers.ServiceBackgroundMonitor
+	pmtuRunningMutex, pmtuStopFuncMutex sync.Mutex
+	stopPmtuMonitor                     chan bool
+
 	// connectAttemptTimeout vars protected by connectionAttemptTimeoutMonitorRunningMutex
 
 	// whether we did one-time check for other VPNs present and reported to UI
@@ -231,6 +286,7 @@ func CreateService(evtReceiver IServiceEventsReceiver,
 	api *api.API,
 	updater IServersUpdater,
 	netChDetector INetChangeDetector,
+	netRulesDetector INetChangeDetector,
 	wgKeysMgr IWgKeysManager,
 	globalEvents <-chan ServiceEventType,
 	systemLog chan<- SystemLogMessage) (*Service, error) {
@@ -245,6 +301,7 @@ func CreateService(evtReceiver IServiceEventsReceiver,
 		_api:                  api,
 		_serversUpdater:       updater,
 		_netChangeDetector:    netChDetector,
+		_netRulesDetector:     netRulesDetector,
 		_wgKeysMgr:            wgKeysMgr,
 		_globalEvents:         globalEvents,
 		_systemLog:            systemLog,
@@ -271,6 +328,24 @@ func CreateService(evtReceiver IServiceEventsReceiver,
 		MonitorRunningMutex:  &serv.connectionAttemptTimeoutMonitorRunningMutex,
 		MonitorStopFuncMutex: &serv.connectionAttemptTimeoutMonitorStopFuncMutex}
 
+	// init connectionQualityBackgroundMonitorDef
+	serv.stopConnectionQualityMonitor = make(chan bool, 1)
+	serv.connectionQualityBackgroundMonitorDef = &srvhelpers.ServiceBackgroundMonitor{
+		MonitorName:          "connectionQualityBackgroundMonitor",
+		MonitorFunc:          serv.connectionQualityBackgroundMonitor,
+		MonitorEndChan:       serv.stopConnectionQualityMonitor,
+		MonitorRunningMutex:  &serv.connectionQualityRunningMutex,
+		MonitorStopFuncMutex: &serv.connectionQualityStopFuncMutex}
+
+	// init pmtuBackgroundMonitorDef
+	serv.stopPmtuMonitor = make(chan bool, 1)
+	serv.pmtuBackgroundMonitorDef = &srvhelpers.ServiceBackgroundMonitor{
+		MonitorName:          "pmtuBackgroundMonitor",
+		MonitorFunc:          serv.pmtuBackgroundMonitor,
+		MonitorEndChan:       serv.stopPmtuMonitor,
+		MonitorRunningMutex:  &serv.pmtuRunningMutex,
+		MonitorStopFuncMutex: &serv.pmtuStopFuncMutex}
+
 	// register the current service as a 'Connectivity checker' for API object
 	serv._api.SetConnectivityChecker(serv)
 
@@ -334,6 +409,9 @@ func (s *Service) init() error {
 		s._preferences.SavePreferences()
 	}
 
+	// apply the headless configuration file (if exists) before initializing functionality which depends on preferences
+	s.managedConfigInit()
+
 	// initialize firewall functionality
 	if err := firewall.Initialize(s.Preferences, s.setHealthchecksType, s.disableTotalShieldAsync, s._evtReceiver.OnKillSwitchStateChanged, s.ConnectedOrConnecting,
 		s._vpnConnectedCallback, s.IsDaemonStopping, s._api.GetRestApiHosts); err != nil {
@@ -349,6 +427,8 @@ func (s *Service) init() error {
 	}
 
 	// initialize split-tunnel functionality
+	splittun.SetDomainsFirewallNotifier(firewall.OnChangeSplitTunnelDomains)
+	splittun.SetSubnetsFirewallNotifier(firewall.OnChangeSplitTunnelSubnets)
 	if err := splittun.Initialize(); err != nil {
 		log.Warning(fmt.Errorf("Split-Tunnelling initialization error : %w", err))
 	} else {
@@ -363,6 +443,16 @@ func (s *Service) init() error {
 	// Init logger (if not initialized before)
 	//logger.Enable(s._preferences.IsLogging)
 
+	// apply logging format and per-component levels
+	logger.SetStructured(s._preferences.IsLogStructured)
+	if err := logger.SetLevels(s._preferences.LogLevels); err != nil {
+		log.Error("Failed to apply log levels configuration: ", err)
+	}
+	logger.SetRotationPolicy(s._preferences.LogRotation)
+
+	// connection events history
+	history.Init(platform.HistoryFile())
+
 	// firewall initial values
 	if err := firewall.AllowLAN(s._preferences.IsFwAllowLAN, s._preferences.IsFwAllowLANMulticast); err != nil {
 		log.Error("Failed to initialize firewall with AllowLAN preference value: ", err)
@@ -373,6 +463,11 @@ func (s *Service) init() error {
 		log.Error("Failed to apply firewall exceptions: ", err)
 	}
 
+	// start metrics endpoint (if enabled)
+	if err := s.applyMetricsConfig(); err != nil {
+		log.Error("Failed to start metrics endpoint: ", err)
+	}
+
 	if s._preferences.IsFwPersistent {
 		log.Info("Enabling firewall (persistent configuration)")
 		if err := firewall.SetPersistent(true); err != nil {
@@ -432,9 +527,13 @@ func (s *Service) init() error {
 
 	// 'Auto-connect on launch' functionality: auto-connect if necessary
 	// 'trusted-wifi' functionality: auto-connect if necessary
+	// 'network rules' functionality: start detecting network changes
+	// 'schedule' functionality: start checking the time-of-day rules (the active rule windows are applied)
 	go func() {
 		<-_ipStackInitializationWaiter // Wait for IP stack initialization
 		s.autoConnectIfRequired(OnDaemonStarted, nil)
+		s.netRulesMonitorUpdate()
+		s.scheduleMonitorUpdate()
 	}()
 
 	// Start processing power events in separate routine (Windows)
@@ -473,6 +572,10 @@ func (s *Service) unInitialise(isLogout bool) error {
 
 	// If not logging out - disable firewall. If logging out - parent callers will conditionally disable it.
 	if !isLogout {
+		metrics.Stop()
+		s.netRulesMonitorUpdate() // the daemon is stopping: stop network rules monitor
+		s.scheduleMonitorUpdate() // the daemon is stopping: stop schedule monitor
+
 		if err := firewall.SetEnabled(false, s._preferences.PermissionReconfigureOtherVPNs); err != nil {
 			log.ErrorFE("error disabling firewall: %w", err)
 			updateRetErr(err)
@@ -626,10 +729,10 @@ func (s *Service) GetDisabledFunctions() protocolTypes.DisabledFunctionality {
 		v2rayErr = fmt.Errorf("V2Ray config file path not defined")
 	}
 
-	if err := filerights.CheckFileAccessRightsExecutable(platform.WgBinaryPath()); err != nil {
-		wgErr = fmt.Errorf("WireGuard binary: %w", err)
-	} else {
-		if err := filerights.CheckFileAccessRightsExecutable(platform.WgToolBinaryPath()); err != nil {
+	if platform.IsWgBinaryRequired() {
+		if err := filerights.CheckFileAccessRightsExecutable(platform.WgBinaryPath()); err != nil {
+			wgErr = fmt.Errorf("WireGuard binary: %w", err)
+		} else if err := filerights.CheckFileAccessRightsExecutable(platform.WgToolBinaryPath()); err != nil {
 			wgErr = fmt.Errorf("WireGuard tools binary: %w", err)
 		}
 	}
@@ -692,6 +795,7 @@ func (s *Service) reconnect() error {
 // Disconnect disconnect vpn
 func (s *Service) Disconnect() error {
 	s._requiredVpnState = Disconnect
+	s._disconnectRequestsCnt.Add(1)
 	// Resume connection (but do not notify "Connection resumed" status)
 	if err := s.resume(); err != nil {
 		log.Error("Resume failed:", err)
@@ -797,6 +901,7 @@ func (s *Service) Pause(durationSeconds uint32) error {
 	// set pause time (to indicate that connection is paused)
 	s._pause._pauseTill = time.Now().Add(time.Second * time.Duration(durationSeconds))
 	log.Info(fmt.Sprintf("Paused on %v (till %v)", time.Second*time.Duration(durationSeconds), s._pause._pauseTill.Format(time.Stamp)))
+	history.Add(history.EventPause, "", "duration", (time.Second * time.Duration(durationSeconds)).String())
 
 	// Update SplitTunnel state (if enabled)
 	prefs := s._preferences
@@ -893,6 +998,7 @@ func (s *Service) resume() error {
 	if err := vpn.Resume(); err != nil {
 		return err
 	}
+	history.Add(history.EventResume, "")
 
 	fwStatus, err := s.KillSwitchState(true)
 	if err != nil {
@@ -1195,6 +1301,7 @@ func (s *Service) SetKillSwitchState(isEnabled, canReconfigureOtherVpns bool) er
 	err := firewall.SetEnabled(isEnabled, canReconfigureOtherVpns)
 	if err == nil {
 		s.onKillSwitchStateChanged(true)
+		history.Add(history.EventFirewall, "", "enabled", strconv.FormatBool(isEnabled))
 		// If no any clients connected - connection notification will not be passed to user
 		// In this case we are trying to save info message into system log
 		if !s._evtReceiver.IsClientConnected(false) {
@@ -1282,6 +1389,7 @@ func (s *Service) SetKillSwitchIsPersistent(isPersistent bool) error {
 	err := firewall.SetPersistent(isPersistent)
 	if err == nil {
 		s.onKillSwitchStateChanged(true)
+		history.Add(history.EventFirewall, "", "persistent", strconv.FormatBool(isPersistent))
 	}
 	return err
 }
@@ -1424,59 +1532,144 @@ func (s *Service) KillSwitchCleanup() error {
 
 // SetPreference set preference value
 func (s *Service) SetPreference(key protocolTypes.ServicePreference, val string) (isChanged bool, err error) {
-	prefs := s._preferences
 	isChanged = false
 
-	switch key {
-	case protocolTypes.Prefs_IsEnableLogging:
-		if val, err := strconv.ParseBool(val); err == nil {
-			isChanged = val != prefs.IsLogging
-			prefs.IsLogging = val
-			logger.Enable(val)
-		}
+	// the preferences are updated concurrently by the background monitors: read-modify-write under the lock
+	err = s.updatePreferences(func(prefs *preferences.Preferences) error {
+		switch key {
+		case protocolTypes.Prefs_IsEnableLogging:
+			if val, err := strconv.ParseBool(val); err == nil {
+				isChanged = val != prefs.IsLogging
+				prefs.IsLogging = val
+				logger.Enable(val)
+			}
 
-	case protocolTypes.Prefs_IsAutoconnectOnLaunch:
-		if val, err := strconv.ParseBool(val); err == nil {
-			isChanged = val != prefs.IsAutoconnectOnLaunch
-			prefs.IsAutoconnectOnLaunch = val
-		}
+		case protocolTypes.Prefs_IsLogStructured:
+			if isStructured, err := strconv.ParseBool(val); err == nil {
+				isChanged = isStructured != prefs.IsLogStructured
+				prefs.IsLogStructured = isStructured
+				logger.SetStructured(isStructured)
+			} else {
+				return fmt.Errorf("invalid IsLogStructured value: %s. Must be a boolean", val)
+			}
+
+		case protocolTypes.Prefs_LogLevels:
+			val = strings.TrimSpace(val)
+			if err := logger.SetLevels(val); err != nil {
+				return err
+			}
+			isChanged = val != prefs.LogLevels
+			prefs.LogLevels = val
 
-	case protocolTypes.Prefs_IsAutoconnectOnLaunch_Daemon:
-		if val, err := strconv.ParseBool(val); err == nil {
-			if val {
-				if e := prefs.LastConnectionParams.CheckIsDefined(); e != nil {
-					return false, srverrors.ErrorBackgroundConnectionNoParams{}
+		case protocolTypes.Prefs_LogRotation:
+			policy, err := logger.ParseRotationPolicy(val, prefs.LogRotation)
+			if err != nil {
+				return err
+			}
+			isChanged = policy != prefs.LogRotation
+			prefs.LogRotation = policy
+			logger.SetRotationPolicy(policy)
+
+		case protocolTypes.Prefs_IsAutoconnectOnLaunch:
+			if val, err := strconv.ParseBool(val); err == nil {
+				isChanged = val != prefs.IsAutoconnectOnLaunch
+				prefs.IsAutoconnectOnLaunch = val
+			}
+
+		case protocolTypes.Prefs_IsAutoconnectOnLaunch_Daemon:
+			if val, err := strconv.ParseBool(val); err == nil {
+				if val {
+					if e := prefs.LastConnectionParams.CheckIsDefined(); e != nil {
+						return srverrors.ErrorBackgroundConnectionNoParams{}
+					}
 				}
+				isChanged = val != prefs.IsAutoconnectOnLaunchDaemon
+				prefs.IsAutoconnectOnLaunchDaemon = val
 			}
-			isChanged = val != prefs.IsAutoconnectOnLaunchDaemon
-			prefs.IsAutoconnectOnLaunchDaemon = val
-		}
 
-	case protocolTypes.Prefs_HealthchecksType:
-		if healthchecksType, ok := service_types.HealthcheckTypesByName[val]; ok {
-			isChanged = healthchecksType != prefs.HealthchecksType
-			prefs.HealthchecksType = healthchecksType
-			log.Debug("SetPreference(): val=", val, "; prefs.HealthchecksType=", service_types.HealthcheckTypeNames[prefs.HealthchecksType])
-		} else {
-			return false, log.ErrorFE("invalid HealthchecksType value: %s. Must be one of: Ping, RestApiCall, Disabled", val)
-		}
+		case protocolTypes.Prefs_AutoconnectProfile:
+			if val = strings.TrimSpace(val); len(val) > 0 {
+				prof, err := prefs.ConnectionProfile(val)
+				if err != nil {
+					return err
+				}
+				val = prof.Name
+			}
+			isChanged = val != prefs.AutoconnectProfile
+			prefs.AutoconnectProfile = val
+
+		case protocolTypes.Prefs_HealthchecksType:
+			if healthchecksType, ok := service_types.HealthcheckTypesByName[val]; ok {
+				isChanged = healthchecksType != prefs.HealthchecksType
+				prefs.HealthchecksType = healthchecksType
+				log.Debug("SetPreference(): val=", val, "; prefs.HealthchecksType=", service_types.HealthcheckTypeNames[prefs.HealthchecksType])
+			} else {
+				return log.ErrorFE("invalid HealthchecksType value: %s. Must be one of: Ping, RestApiCall, Disabled", val)
+			}
 
-	case protocolTypes.Prefs_PermissionReconfigureOtherVPNs:
-		if val, err := strconv.ParseBool(val); err == nil {
-			isChanged = val != prefs.PermissionReconfigureOtherVPNs
-			prefs.PermissionReconfigureOtherVPNs = val
-		} else {
-			return false, fmt.Errorf("invalid PermissionReconfigureOtherVPNs value: %t. Must be a boolean", val)
-		}
+		case protocolTypes.Prefs_PermissionReconfigureOtherVPNs:
+			if val, err := strconv.ParseBool(val); err == nil {
+				isChanged = val != prefs.PermissionReconfigureOtherVPNs
+				prefs.PermissionReconfigureOtherVPNs = val
+			} else {
+				return fmt.Errorf("invalid PermissionReconfigureOtherVPNs value: %t. Must be a boolean", val)
+			}
 
-	default:
-		log.Warning(fmt.Sprintf("Preference key '%s' not supported", key))
-	}
+		case protocolTypes.Prefs_IsUnixSocketDisabled: // takes effect after the daemon restart
+			if isDisabled, err := strconv.ParseBool(val); err == nil {
+				isChanged = isDisabled != prefs.IsUnixSocketDisabled
+				prefs.IsUnixSocketDisabled = isDisabled
+			} else {
+				return fmt.Errorf("invalid IsUnixSocketDisabled value: %q. Must be a boolean", val)
+			}
 
-	s.setPreferences(prefs)
+		case protocolTypes.Prefs_UnixSocketAllowedUIDs, protocolTypes.Prefs_UnixSocketAllowedGIDs:
+			ids, err := preferences.ParseIDList(val)
+			if err != nil {
+				return fmt.Errorf("invalid '%s' value: %w", key, err)
+			}
+			allowList := &prefs.UnixSocketAllowedUIDs
+			if key == protocolTypes.Prefs_UnixSocketAllowedGIDs {
+				allowList = &prefs.UnixSocketAllowedGIDs
+			}
+			isChanged = !reflect.DeepEqual(ids, *allowList)
+			*allowList = ids
+
+		case protocolTypes.Prefs_IsMetricsEnabled:
+			if isEnabled, err := strconv.ParseBool(val); err == nil {
+				isChanged = isEnabled != prefs.IsMetricsEnabled
+				prefs.IsMetricsEnabled = isEnabled
+			} else {
+				return fmt.Errorf("invalid IsMetricsEnabled value: %s. Must be a boolean", val)
+			}
+
+		case protocolTypes.Prefs_MetricsListenAddress:
+			val = strings.TrimSpace(val)
+			if len(val) > 0 {
+				if err := metrics.CheckListenAddress(val); err != nil {
+					return err
+				}
+			}
+			isChanged = val != prefs.MetricsListenAddress
+			prefs.MetricsListenAddress = val
+
+		default:
+			log.Warning(fmt.Sprintf("Preference key '%s' not supported", key))
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
 
 	if isChanged {
 		log.Info(fmt.Sprintf("(prefs '%s' changed) %s", key, val))
+
+		if key == protocolTypes.Prefs_IsMetricsEnabled || key == protocolTypes.Prefs_MetricsListenAddress {
+			if err := s.applyMetricsConfig(); err != nil {
+				log.ErrorFE("failed to apply metrics configuration: %w", err)
+			}
+		}
 	}
 
 	return isChanged, nil
@@ -1538,6 +1731,9 @@ func (s *Service) ResetPreferences() error {
 
 	// erase ST config -  split tunnel config by default
 	s.SplitTunnelling_SetConfig(true, true, false, false, false, true)
+
+	// managed values (headless configuration file) are not affected by reset
+	s.managedConfigReapply()
 	return nil
 }
 
@@ -1546,6 +1742,8 @@ func (s *Service) GetConnectionParams() service_types.ConnectionParams {
 }
 
 func (s *Service) SetConnectionParams(params service_types.ConnectionParams) error {
+	params = s.managedConfigEnforceConnectionParams(params)
+
 	if s.ConnectedOrConnecting() {
 		s._tmpParamsMutex.Lock()
 		s._tmpParams = params
@@ -1602,6 +1800,21 @@ func (s *Service) SetWiFiSettings(params preferences.WiFiParams) error {
 	keys := make(map[string]struct{})
 	for _, n := range params.Networks {
 		if _, exists := keys[n.SSID]; !exists && len(n.SSID) > 0 {
+			if n.Profile = strings.TrimSpace(n.Profile); len(n.Profile) > 0 {
+				prof, err := s._preferences.ConnectionProfile(n.Profile)
+				if err != nil {
+					return fmt.Errorf("WiFi network '%s': %w", n.SSID, err)
+				}
+				n.Profile = prof.Name
+			}
+			if n.Actions != nil {
+				if err := n.Actions.Validate(); err != nil {
+					return fmt.Errorf("WiFi network '%s' actions: %w", n.SSID, err)
+				}
+				if n.Actions.IsEmpty() {
+					n.Actions = nil
+				}
+			}
 			newNets = append(newNets, n)
 			keys[n.SSID] = struct{}{}
 		}
@@ -1668,7 +1881,10 @@ func (s *Service) SplitTunnelling_GetStatus() (protocolTypes.SplitTunnelStatus,
 		IsAllowWhenNoVpn:            isAllowWhenNoVpn,
 		IsCanGetAppIconForBinary:    oshelpers.IsCanGetAppIconForBinary(),
 		SplitTunnelApps:             prefs.SplitTunnelApps,
-		RunningApps:                 runningProcesses}
+		RunningApps:                 runningProcesses,
+		Domains:                     splittun.GetDomainsStatus(),
+		IncludeSubnets:              prefs.SplitTunnelIncludeSubnets,
+		ExcludeSubnets:              prefs.SplitTunnelExcludeSubnets}
 
 	return ret, nil
 }
@@ -1768,6 +1984,9 @@ func (s *Service) splitTunnelling_Reset() error {
 	prefs.SplitTunnelAnyDns = false
 	prefs.SplitTunnelAllowWhenNoVpn = false
 	prefs.SplitTunnelApps = make([]string, 0)
+	prefs.SplitTunnelDomains = make([]splittun.DomainRule, 0)
+	prefs.SplitTunnelIncludeSubnets = make([]string, 0)
+	prefs.SplitTunnelExcludeSubnets = make([]string, 0)
 	s.setPreferences(prefs)
 
 	splittun.Reset()
@@ -1795,6 +2014,11 @@ func (s *Service) splitTunnelling_ApplyConfig(applyTotalShieldUnconditionally bo
 		s._evtReceiver.OnSplitTunnelStatusChanged()
 	}()
 
+	// per-domain split tunnelling rules depend on VPN state and DNS configuration
+	defer s.splitTunnelDomains_ApplyConfig()
+	// per-CIDR split tunnelling routes depend on VPN state
+	defer s.splitTunnelSubnets_ApplyConfig()
+
 	// log.Debug("splitTunnelling_ApplyConfig entered")
 	// defer log.Debug("splitTunnelling_ApplyConfig exited")
 
@@ -2853,6 +3077,11 @@ func (s *Service) stopSessionChecker() {
 // WireGuard keys
 //////////////////////////////////////////////////////////
 
+// wgCredentialsUpdater - WireGuard connection object which is able to apply new keys to the active tunnel
+type wgCredentialsUpdater interface {
+	UpdateCredentials(privateKey, presharedKey string, localIP net.IP) error
+}
+
 // WireGuardSaveNewKeys saves WG keys
 func (s *Service) WireGuardSaveNewKeys(wgPublicKey string, wgPrivateKey string, wgLocalIP string, wgPresharedKey string) {
 	s._preferences.UpdateWgCredentials(wgPublicKey, wgPrivateKey, wgLocalIP, wgPresharedKey)
@@ -2869,11 +3098,25 @@ func (s *Service) WireGuardSaveNewKeys(wgPublicKey string, wgPrivateKey string,
 		if vpnObj.Type() != vpn.WireGuard {
 			return
 		}
+		if _, isCustomServer := s.activeCustomServer(); isCustomServer {
+			return // the user-imported configuration has its own keys
+		}
 		if !s.ConnectedOrConnecting() || (s.ConnectedOrConnecting() && s.IsPaused()) {
 			// IMPORTANT! : WireGuard 'pause/resume' state is based on complete VPN disconnection and connection back (on all platforms)
 			// If this will be changed (e.g. just changing routing) - it will be necessary to implement reconnection even in 'pause' state
 			return
 		}
+
+		// apply new keys to the active tunnel without reconnection (if supported by the WireGuard implementation)
+		if u, ok := vpnObj.(wgCredentialsUpdater); ok && len(wgPrivateKey) > 0 {
+			err := u.UpdateCredentials(wgPrivateKey, wgPresharedKey, net.ParseIP(wgLocalIP))
+			if err == nil {
+				log.Info("WireGuard credentials updated without reconnection")
+				return
+			}
+			log.Info(fmt.Sprintf("Unable to update WireGuard credentials without reconnection: %v", err))
+		}
+
 		log.Info("Reconnecting WireGuard connection with new credentials...")
 		s.reconnect()
 	}()
@@ -2942,6 +3185,11 @@ func (s *Service) GetDiagnosticLogs() (logActive string, logPrevSession string,
 	return logCurr, logPrev, sysInfoVar, nil
 }
 
+// GetDiagnosticLogsTimeWindow returns log records in the time window (from all available logs, including compressed archives)
+func (s *Service) GetDiagnosticLogsTimeWindow(from, to time.Time) (string, error) {
+	return logger.GetLogTextTimeWindow(from, to, rageshake.MAX_LOG_SIZE)
+}
+
 func (s *Service) diagnosticGetCommandOutput(command string, args ...string) string {
 	outText, outErrText, _, isBufferTooSmall, err := shell.ExecAndGetOutput(nil, 1024*30, "", command, args...)
 	ret := fmt.Sprintf("[ $ %s %v ]:\n%s", command, args, outText)
@@ -2958,6 +3206,9 @@ func (s *Service) diagnosticGetCommandOutput(command string, args ...string) str
 }
 
 func (s *Service) SetStatsCallbacks(callbacks protocol.StatsCallbacks) {
+	// raw statistics values are reported to the metrics endpoint
+	callbacks.OnTransferBytesCallback = metrics.SetTransferredBytes
+	callbacks.OnHandshakeTimeCallback = metrics.SetLastHandshake
 	s._statsCallbacks = callbacks
 }
 
@@ -2970,6 +3221,27 @@ func (s *Service) GetStatsCallbacks() protocol.StatsCallbacks {
 //////////////////////////////////////////////////////////
 
 func (s *Service) setPreferences(p preferences.Preferences) {
+	s._preferencesMutex.Lock()
+	defer s._preferencesMutex.Unlock()
+	s.setPreferencesLocked(p)
+}
+
+// updatePreferences applies 'update' to a copy of the current preferences and saves the result;
+// nothing is saved when 'update' returns an error (the error is returned to the caller).
+// The read-modify-write is done under the preferences lock, so concurrent updates are not lost.
+func (s *Service) updatePreferences(update func(p *preferences.Preferences) error) error {
+	s._preferencesMutex.Lock()
+	defer s._preferencesMutex.Unlock()
+
+	prefs := s._preferences
+	if err := update(&prefs); err != nil {
+		return err
+	}
+	s.setPreferencesLocked(prefs)
+	return nil
+}
+
+func (s *Service) setPreferencesLocked(p preferences.Preferences) {
 	if !reflect.DeepEqual(s._preferences, p) {
 		//if s._preferences != p {
 		s._preferences = p
@@ -2979,7 +3251,7 @@ func (s *Service) setPreferences(p preferences.Preferences) {
 
 func (s *Service) listAllServiceBackgroundMonitors() (allBackgroundMonitors []*srvhelpers.ServiceBackgroundMonitor) {
 	allBackgroundMonitors = firewall.GetFirewallBackgroundMonitors()
-	allBackgroundMonitors = append(allBackgroundMonitors, s.connectivityHealthchecksBackgroundMonitorDef)
+	allBackgroundMonitors = append(allBackgroundMonitors, s.connectivityHealthchecksBackgroundMonitorDef, s.connectionQualityBackgroundMonitorDef, s.pmtuBackgroundMonitorDef)
 	return allBackgroundMonitors
 }
 
@@ -3005,6 +3277,12 @@ func (s *Service) SubmitRageshakeReport(crashType, app, version, text string, fi
 	if prefs.LastConnectionParams.OpenVpnParameters.Proxy.Password != "" {
 		prefs.LastConnectionParams.OpenVpnParameters.Proxy.Password = "***"
 	}
+	customServers := make([]preferences.CustomServer, 0, len(prefs.CustomServers))
+	for _, svr := range prefs.CustomServers { // imported configurations contain private keys
+		svr.Config, svr.Password = "***", ""
+		customServers = append(customServers, svr)
+	}
+	prefs.CustomServers = customServers
 	prefsJson, err := json.Marshal(&prefs)
 	if err != nil {
 		return nil, 0, log.ErrorFE("failed to marshal preferences: %w", err)
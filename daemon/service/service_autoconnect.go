@@ -28,9 +28,13 @@ import (
 	"strings"
 
 	apiTypes "github.com/swapnilsparsh/devsVPN/daemon/api/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/obfsproxy"
 	protocolTypes "github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/history"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/v2r"
 	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
 	"github.com/swapnilsparsh/devsVPN/daemon/wifiNotifier"
 )
@@ -42,6 +46,7 @@ const (
 	OnUiClientConnected autoConnectReason = iota
 	OnSessionLogon      autoConnectReason = iota
 	OnWifiChanged       autoConnectReason = iota
+	OnNetworkChanged    autoConnectReason = iota
 )
 
 func (cr autoConnectReason) ToString() string {
@@ -54,6 +59,8 @@ func (cr autoConnectReason) ToString() string {
 		return "WiFiChanged"
 	case OnSessionLogon:
 		return "UserSessionLogon"
+	case OnNetworkChanged:
+		return "NetworkChanged"
 	default:
 		return "<unknown>"
 	}
@@ -79,6 +86,10 @@ const (
 type automaticAction struct {
 	Vpn      actionTypeVpn
 	Firewall actionTypeFirewall
+	Profile  string // connection profile to use for 'VPN_On' action (empty - the default one)
+
+	TotalShield    *bool                           // nil - no action
+	NetworkActions *preferences.WiFiNetworkActions // actions of the WiFi network (in use to modify the connection parameters)
 }
 
 type lastProcessedWiFiInfo struct {
@@ -89,7 +100,35 @@ type lastProcessedWiFiInfo struct {
 var autoconnectLastProcessedWifi lastProcessedWiFiInfo
 
 func (a automaticAction) IsHasAction() bool {
-	return a.Firewall != FW_NoAction || a.Vpn != VPN_NoAction
+	return a.Firewall != FW_NoAction || a.Vpn != VPN_NoAction || a.TotalShield != nil
+}
+
+func (a actionTypeVpn) ToString() string {
+	switch a {
+	case VPN_NoAction:
+		return ""
+	case VPN_On:
+		return "On"
+	case VPN_Off:
+		return "Off"
+	default:
+		return "<unknown>"
+	}
+}
+
+func (a actionTypeFirewall) ToString() string {
+	switch a {
+	case FW_NoAction:
+		return ""
+	case FW_On:
+		return "On"
+	case FW_Off:
+		return "Off"
+	case FW_On_and_blockLan:
+		return "OnAndBlockLan"
+	default:
+		return "<unknown>"
+	}
 }
 
 func (s *Service) OnAuthenticatedClient(t protocolTypes.ClientTypeEnum) {
@@ -119,6 +158,10 @@ func (s *Service) isTrustedWifiForcingToBlockLan(wifiInfoPtr *wifiNotifier.WifiI
 	}
 
 	action := s.getActionForWifiNetwork(wifiInfo)
+	if !action.IsHasAction() {
+		// no WiFi actions: check the last applied network rule
+		action = autoconnectLastProcessedNetwork.action
+	}
 
 	return action.Firewall == FW_On_and_blockLan
 }
@@ -152,6 +195,7 @@ func (s *Service) autoConnectIfRequired(reason autoConnectReason, wifiInfoPtr *w
 
 	currWiFi := lastProcessedWiFiInfo{wifi: wifiInfo, params: prefs.WiFiControl}
 	lastWifi := autoconnectLastProcessedWifi
+	lastNetwork := autoconnectLastProcessedNetwork
 
 	if reflect.DeepEqual(lastWifi, currWiFi) {
 		// this wifi network change has been processed already
@@ -169,8 +213,8 @@ func (s *Service) autoConnectIfRequired(reason autoConnectReason, wifiInfoPtr *w
 		}
 		// Check if the untrusted WiFi settings were forced to block LAN.
 		// We have to restore LAN connectivity if there is not required to block LAN for current network
-		prevSettingsBlockLan := lastWifi.params.TrustedNetworksControl && lastWifi.params.Actions.UnTrustedBlockLan
-		currSettingsBlockLan := prefs.WiFiControl.TrustedNetworksControl && prefs.WiFiControl.Actions.UnTrustedBlockLan
+		prevSettingsBlockLan := lastWifi.params.IsBlockLanPossible() || isNetworkRulesBlockLanPossible(lastNetwork.rules)
+		currSettingsBlockLan := prefs.WiFiControl.IsBlockLanPossible() || isNetworkRulesBlockLanPossible(prefs.NetworkRules)
 		if prefs.IsFwAllowLAN && (prevSettingsBlockLan || currSettingsBlockLan) {
 			if err := s.applyKillSwitchAllowLAN(&wifiInfo); err != nil {
 				log.Info(fmt.Sprintf("Automatic connection manager: failed to restore Firewall rules to allow LAN: %s", err.Error()))
@@ -195,6 +239,18 @@ func (s *Service) autoConnectIfRequired(reason autoConnectReason, wifiInfoPtr *w
 
 	if action.IsHasAction() {
 		log.Info("Automatic connection manager: applying 'Trusted-WiFi' action...")
+	} else if !isVpnOffRequired {
+		// Check network-condition rules (wired interfaces, gateways, DNS suffixes ...).
+		// The rules are applied only when there are no actions for the WiFi network.
+		ruleAction, isRuleProcessedAlready := s.getActionForNetworkRules()
+		if isRuleProcessedAlready {
+			isVpnOffRequired = ruleAction.Vpn == VPN_Off
+		} else {
+			action = ruleAction
+			if action.IsHasAction() {
+				log.Info("Automatic connection manager: applying network rule action...")
+			}
+		}
 	}
 
 	// Check "Auto-connect on APP/daemon launch" action
@@ -223,17 +279,46 @@ func (s *Service) autoConnectIfRequired(reason autoConnectReason, wifiInfoPtr *w
 		}
 	}
 
+	// The active schedule window has priority over the VPN actions (e.g. "VPN must be connected 08:00-18:00")
+	if action.Vpn != VPN_NoAction {
+		if scheduleVpn, rule := s.scheduleRequiredVpnState(); scheduleVpn != VPN_NoAction && scheduleVpn != action.Vpn {
+			log.Info(fmt.Sprintf("Automatic connection manager: skipping 'VPN %s' action due to the active schedule rule '%s'", action.Vpn.ToString(), rule))
+			action.Vpn = VPN_NoAction
+		}
+	}
+
 	if !action.IsHasAction() {
 		// No actions defined. Nothing to do here.
 		return nil
 	}
 
+	// connection profile: defined for the WiFi network or the default one for auto-connection
+	profile := action.Profile
+	if len(profile) == 0 && action.Vpn == VPN_On {
+		profile = prefs.AutoconnectProfile
+	}
+
+	history.Add(history.EventAutoConnect, "", "reason", reason.ToString(), "ssid", wifiInfo.SSID, "vpn", action.Vpn.ToString(), "firewall", action.Firewall.ToString(), "profile", profile)
+
 	//
 	// Apply actions (Firewall, VPN ...)
 	//
 
 	var retErr error = nil
 	connParams := prefs.LastConnectionParams
+	var connParamsErr error
+	if action.Vpn == VPN_On && !s.ConnectedOrConnecting() {
+		connParams, connParamsErr = s.autoConnectParams(profile)
+	}
+
+	// Total Shield
+	if action.TotalShield != nil && *action.TotalShield != s._preferences.IsTotalShieldOn {
+		log.Info(fmt.Sprintf("Automatic connection manager: changing Total Shield state to %t", *action.TotalShield))
+		p := s._preferences
+		if retErr = s.SplitTunnelling_SetConfig(!*action.TotalShield, p.SplitTunnelInversed, p.EnableAppWhitelist, p.SplitTunnelAnyDns, p.SplitTunnelAllowWhenNoVpn, false); retErr != nil {
+			log.ErrorFE("Auto connection: changing Total Shield state: %w", retErr)
+		}
+	}
 
 	// Firewall
 	switch action.Firewall {
@@ -279,10 +364,10 @@ func (s *Service) autoConnectIfRequired(reason autoConnectReason, wifiInfoPtr *w
 		if !s.ConnectedOrConnecting() {
 			log.Info("Automatic connection manager: connecting VPN")
 
-			connParams, retErr = s.updateParamsAccordingToMetadata(connParams)
-			if retErr != nil {
-				log.Info("[WARNING] Auto connection: failed updating connection parameters: ", retErr)
+			if connParamsErr != nil {
+				log.Info("[WARNING] Auto connection: failed updating connection parameters: ", connParamsErr)
 			}
+			connParams = s.applyWiFiNetworkConnectionParams(connParams, action.NetworkActions)
 
 			const canFixParams bool = true
 			if connParams, retErr = s.ValidateConnectionParameters(connParams, canFixParams); retErr != nil {
@@ -327,6 +412,8 @@ func (s *Service) getActionForWifiNetwork(wifiInfo wifiNotifier.WifiInfo) (retAc
 	}
 
 	var isNetworkTrusted *bool // nil - no action
+	networkProfile := ""
+	var networkActions *preferences.WiFiNetworkActions
 
 	// get config for ssid
 	for _, w := range wifiParams.Networks {
@@ -335,6 +422,8 @@ func (s *Service) getActionForWifiNetwork(wifiInfo wifiNotifier.WifiInfo) (retAc
 		}
 
 		isNetworkTrusted = &w.IsTrusted
+		networkProfile = w.Profile
+		networkActions = w.Actions
 		break
 	}
 
@@ -351,6 +440,7 @@ func (s *Service) getActionForWifiNetwork(wifiInfo wifiNotifier.WifiInfo) (retAc
 		// UnTrusted
 		if wifiParams.Actions.UnTrustedConnectVpn {
 			retAction.Vpn = VPN_On
+			retAction.Profile = networkProfile
 		}
 		if wifiParams.Actions.UnTrustedEnableFirewall {
 			retAction.Firewall = FW_On
@@ -368,9 +458,149 @@ func (s *Service) getActionForWifiNetwork(wifiInfo wifiNotifier.WifiInfo) (retAc
 		}
 	}
 
+	if networkActions != nil {
+		applyWiFiNetworkActions(&retAction, *networkActions)
+		if retAction.Vpn == VPN_On {
+			retAction.Profile = networkProfile
+		}
+	}
+
 	return
 }
 
+// applyWiFiNetworkActions overrides the common 'trusted/untrusted' actions by the actions defined for the WiFi network
+func applyWiFiNetworkActions(action *automaticAction, netActions preferences.WiFiNetworkActions) {
+	if netActions.ConnectVpn != nil {
+		if *netActions.ConnectVpn {
+			action.Vpn = VPN_On
+		} else {
+			action.Vpn = VPN_Off
+		}
+	}
+
+	if netActions.EnableFirewall != nil {
+		if !*netActions.EnableFirewall {
+			action.Firewall = FW_Off
+		} else if action.Firewall != FW_On_and_blockLan {
+			action.Firewall = FW_On
+		}
+	}
+	if netActions.BlockLan != nil {
+		if *netActions.BlockLan {
+			action.Firewall = FW_On_and_blockLan
+		} else if action.Firewall == FW_On_and_blockLan {
+			action.Firewall = FW_On
+		}
+	}
+
+	action.TotalShield = netActions.TotalShield
+	if netActions.HasConnectionParams() {
+		action.NetworkActions = &netActions
+	}
+}
+
+// applyWiFiNetworkConnectionParams modifies the connection parameters according to the WiFi network actions (location, protocol, DNS)
+func (s *Service) applyWiFiNetworkConnectionParams(params types.ConnectionParams, netActions *preferences.WiFiNetworkActions) types.ConnectionParams {
+	if netActions == nil || !netActions.HasConnectionParams() {
+		return params
+	}
+
+	switch netActions.Protocol {
+	case preferences.WiFiProtocolWireGuard:
+		params.VpnType = vpn.WireGuard
+		params.WireGuardParameters.V2RayProxy = v2r.None
+		params.WireGuardParameters.Obfsproxy = obfsproxy.Config{}
+	case preferences.WiFiProtocolWireGuardV2RayQuic:
+		params.VpnType = vpn.WireGuard
+		params.WireGuardParameters.V2RayProxy = v2r.QUIC
+	case preferences.WiFiProtocolWireGuardV2RayTcp:
+		params.VpnType = vpn.WireGuard
+		params.WireGuardParameters.V2RayProxy = v2r.TCP
+	case preferences.WiFiProtocolWireGuardV2RayWs:
+		params.VpnType = vpn.WireGuard
+		params.WireGuardParameters.V2RayProxy = v2r.WebSocket
+	case preferences.WiFiProtocolWireGuardV2RayGrpc:
+		params.VpnType = vpn.WireGuard
+		params.WireGuardParameters.V2RayProxy = v2r.GRPC
+	case preferences.WiFiProtocolWireGuardV2RayH2:
+		params.VpnType = vpn.WireGuard
+		params.WireGuardParameters.V2RayProxy = v2r.HTTP2
+	case preferences.WiFiProtocolOpenVPN:
+		params.VpnType = vpn.OpenVPN
+	}
+	if len(netActions.Protocol) > 0 {
+		params.AutoTransport = false // the protocol defined for the network takes precedence over automatic transport mode
+	}
+
+	if len(netActions.Location) > 0 {
+		if err := s.setConnectionParamsLocation(&params, netActions.Location); err != nil {
+			log.Warning(fmt.Sprintf("Automatic connection manager: %s; using the registered server", err))
+		}
+	}
+
+	if dnsIPs := netActions.DnsServerIPs(); len(dnsIPs) > 0 {
+		params.ManualDNS = dns.DnsSettingsCreate(&dnsIPs)
+	}
+
+	log.Info(fmt.Sprintf("Automatic connection manager: using WiFi network connection parameters (location='%s' protocol='%s' DNS=%v)",
+		netActions.Location, netActions.Protocol, netActions.DnsServers))
+	return params
+}
+
+// setConnectionParamsLocation sets the entry server of the connection according to location: gateway ID (e.g. "us-tx") or country code (e.g. "US").
+// WireGuard connections always use the server this device is registered with: the location only selects the hosts of that server.
+func (s *Service) setConnectionParamsLocation(params *types.ConnectionParams, location string) error {
+	servers, err := s.ServersList()
+	if err != nil {
+		return fmt.Errorf("unable to obtain servers list: %w", err)
+	}
+	return setConnectionParamsLocationFromServers(params, location, servers, s._preferences.LastConnectionParams.WireGuardParameters.EntryVpnServer.Hosts)
+}
+
+// setConnectionParamsLocationFromServers sets the entry server of the connection according to location, using the servers list.
+// For WireGuard - only the registered hosts ('registeredHosts') which belong to the servers of the location are selected.
+func setConnectionParamsLocationFromServers(params *types.ConnectionParams, location string, servers *apiTypes.ServersInfoResponse, registeredHosts []apiTypes.WireGuardServerHostInfo) error {
+	// Remove everything after symbol '.': "us-tx.wg.ivpn.net" => "us-tx"
+	location = strings.Split(location, ".")[0]
+	isMatching := func(svr apiTypes.ServerInfoBase) bool {
+		return strings.EqualFold(strings.Split(svr.Gateway, ".")[0], location) || strings.EqualFold(svr.CountryCode, location)
+	}
+
+	if params.VpnType == vpn.OpenVPN {
+		for _, svr := range servers.OpenvpnServers {
+			if isMatching(svr.ServerInfoBase) && len(svr.Hosts) > 0 {
+				params.OpenVpnParameters.EntryVpnServer.Hosts = svr.Hosts
+				return nil
+			}
+		}
+	} else {
+		isLocationFound := false
+		var hosts []apiTypes.WireGuardServerHostInfo
+		for _, svr := range servers.WireguardServers {
+			if !isMatching(svr.ServerInfoBase) {
+				continue
+			}
+			isLocationFound = true
+			for _, h := range registeredHosts {
+				for _, sh := range svr.Hosts {
+					if strings.EqualFold(h.Hostname, sh.Hostname) {
+						hosts = append(hosts, h)
+						break
+					}
+				}
+			}
+		}
+		if len(hosts) > 0 {
+			params.WireGuardParameters.EntryVpnServer.Hosts = hosts
+			return nil
+		}
+		if isLocationFound {
+			return fmt.Errorf("location '%s' is not served by the server this device is registered with", location)
+		}
+	}
+	return fmt.Errorf("no %s server found for location '%s'", params.VpnType, location)
+}
+
 // updateParamsAccordingToMetadata - update Entry/Exit servers if connection requires 'Fastest' or 'Random'
 func (s *Service) updateParamsAccordingToMetadata(params types.ConnectionParams) (types.ConnectionParams, error) {
 	// TODO: FIXME: Vlad - unconditionally using the Wireguard entry server info saved in preferences (if we have one), not the passed parameter
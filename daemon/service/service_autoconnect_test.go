@@ -0,0 +1,188 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	apiTypes "github.com/swapnilsparsh/devsVPN/daemon/api/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/v2r"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+func TestApplyWiFiNetworkActions(t *testing.T) {
+	on, off := true, false
+
+	tests := []struct {
+		name       string
+		common     automaticAction
+		netActions preferences.WiFiNetworkActions
+		want       automaticAction
+	}{
+		{"no actions", automaticAction{Vpn: VPN_On, Firewall: FW_On}, preferences.WiFiNetworkActions{}, automaticAction{Vpn: VPN_On, Firewall: FW_On}},
+		{"connect", automaticAction{}, preferences.WiFiNetworkActions{ConnectVpn: &on}, automaticAction{Vpn: VPN_On}},
+		{"disconnect", automaticAction{Vpn: VPN_On}, preferences.WiFiNetworkActions{ConnectVpn: &off}, automaticAction{Vpn: VPN_Off}},
+		{"enable firewall", automaticAction{}, preferences.WiFiNetworkActions{EnableFirewall: &on}, automaticAction{Firewall: FW_On}},
+		{"enable firewall keeps LAN blocking", automaticAction{Firewall: FW_On_and_blockLan}, preferences.WiFiNetworkActions{EnableFirewall: &on}, automaticAction{Firewall: FW_On_and_blockLan}},
+		{"block LAN", automaticAction{Firewall: FW_On}, preferences.WiFiNetworkActions{BlockLan: &on}, automaticAction{Firewall: FW_On_and_blockLan}},
+		{"unblock LAN", automaticAction{Firewall: FW_On_and_blockLan}, preferences.WiFiNetworkActions{BlockLan: &off}, automaticAction{Firewall: FW_On}},
+		{"unblock LAN without firewall action", automaticAction{}, preferences.WiFiNetworkActions{BlockLan: &off}, automaticAction{}},
+		{"total shield", automaticAction{}, preferences.WiFiNetworkActions{TotalShield: &off}, automaticAction{TotalShield: &off}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.common
+			applyWiFiNetworkActions(&got, tt.netActions)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyWiFiNetworkActions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// the actions which modify the connection parameters are passed on
+	netActions := preferences.WiFiNetworkActions{Protocol: preferences.WiFiProtocolOpenVPN}
+	var got automaticAction
+	applyWiFiNetworkActions(&got, netActions)
+	if got.NetworkActions == nil || !reflect.DeepEqual(*got.NetworkActions, netActions) {
+		t.Errorf("NetworkActions = %v, want %+v", got.NetworkActions, netActions)
+	}
+}
+
+func TestApplyWiFiNetworkConnectionParams(t *testing.T) {
+	s := newRegisteredTestService(wgHost("us-tx1.wg.privateline.io", "1.1.1.1"))
+
+	var params types.ConnectionParams
+	params.VpnType = vpn.WireGuard
+	params.AutoTransport = true
+	params.WireGuardParameters.V2RayProxy = v2r.QUIC
+
+	// no connection parameters in actions
+	if got := s.applyWiFiNetworkConnectionParams(params, nil); !reflect.DeepEqual(got, params) {
+		t.Errorf("parameters are changed without network actions: %+v", got)
+	}
+	if got := s.applyWiFiNetworkConnectionParams(params, &preferences.WiFiNetworkActions{}); !reflect.DeepEqual(got, params) {
+		t.Errorf("parameters are changed by empty network actions: %+v", got)
+	}
+
+	tests := []struct {
+		protocol  string
+		wantType  vpn.Type
+		wantV2Ray v2r.V2RayTransportType
+	}{
+		{preferences.WiFiProtocolWireGuard, vpn.WireGuard, v2r.None},
+		{preferences.WiFiProtocolWireGuardV2RayTcp, vpn.WireGuard, v2r.TCP},
+		{preferences.WiFiProtocolWireGuardV2RayWs, vpn.WireGuard, v2r.WebSocket},
+		{preferences.WiFiProtocolWireGuardV2RayGrpc, vpn.WireGuard, v2r.GRPC},
+		{preferences.WiFiProtocolWireGuardV2RayH2, vpn.WireGuard, v2r.HTTP2},
+		{preferences.WiFiProtocolOpenVPN, vpn.OpenVPN, v2r.QUIC},
+	}
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			got := s.applyWiFiNetworkConnectionParams(params, &preferences.WiFiNetworkActions{Protocol: tt.protocol})
+			if got.VpnType != tt.wantType || got.WireGuardParameters.V2RayProxy != tt.wantV2Ray {
+				t.Errorf("VPN type = %v, V2Ray = %v; want %v, %v", got.VpnType, got.WireGuardParameters.V2RayProxy, tt.wantType, tt.wantV2Ray)
+			}
+			if got.AutoTransport {
+				t.Error("automatic transport mode is not disabled by the network protocol")
+			}
+		})
+	}
+
+	// DNS servers
+	got := s.applyWiFiNetworkConnectionParams(params, &preferences.WiFiNetworkActions{DnsServers: []string{"9.9.9.9", "149.112.112.112"}})
+	if want := []net.IP{net.ParseIP("9.9.9.9"), net.ParseIP("149.112.112.112")}; !reflect.DeepEqual(got.ManualDNS.DnsServers, want) {
+		t.Errorf("DNS servers = %v, want %v", got.ManualDNS.DnsServers, want)
+	}
+	if !got.AutoTransport || got.VpnType != vpn.WireGuard {
+		t.Error("DNS action changes the protocol")
+	}
+}
+
+func TestSetConnectionParamsLocationFromServers(t *testing.T) {
+	tx1, tx2 := wgHost("us-tx1.wg.privateline.io", "1.1.1.1"), wgHost("us-tx2.wg.privateline.io", "1.1.1.2")
+	ca1 := wgHost("us-ca1.wg.privateline.io", "1.1.2.1")
+	de1 := wgHost("de-fr1.wg.privateline.io", "2.2.2.1")
+	servers := &apiTypes.ServersInfoResponse{
+		WireguardServers: []apiTypes.WireGuardServerInfo{
+			{ServerInfoBase: apiTypes.ServerInfoBase{Gateway: "us-tx.wg.privateline.io", CountryCode: "US"}, Hosts: []apiTypes.WireGuardServerHostInfo{tx1, tx2}},
+			{ServerInfoBase: apiTypes.ServerInfoBase{Gateway: "us-ca.wg.privateline.io", CountryCode: "US"}, Hosts: []apiTypes.WireGuardServerHostInfo{ca1}},
+			{ServerInfoBase: apiTypes.ServerInfoBase{Gateway: "de-fr.wg.privateline.io", CountryCode: "DE"}, Hosts: []apiTypes.WireGuardServerHostInfo{de1}},
+		},
+		OpenvpnServers: []apiTypes.OpenvpnServerInfo{
+			{ServerInfoBase: apiTypes.ServerInfoBase{Gateway: "de-fr.gw.privateline.io", CountryCode: "DE"}, Hosts: []apiTypes.OpenVPNServerHostInfo{{HostInfoBase: apiTypes.HostInfoBase{Hostname: "de-fr1.gw.privateline.io"}}}},
+		},
+	}
+	// the device is registered with the server which hosts are in different locations
+	registered := []apiTypes.WireGuardServerHostInfo{tx1, ca1}
+
+	tests := []struct {
+		name      string
+		location  string
+		wantHosts []apiTypes.WireGuardServerHostInfo
+		wantErr   string
+	}{
+		{"gateway ID", "us-tx", []apiTypes.WireGuardServerHostInfo{tx1}, ""},
+		{"full gateway name", "us-ca.wg.privateline.io", []apiTypes.WireGuardServerHostInfo{ca1}, ""},
+		{"country code", "us", []apiTypes.WireGuardServerHostInfo{tx1, ca1}, ""},
+		{"location of not registered server", "DE", nil, "not served by the server this device is registered with"},
+		{"unknown location", "jp", nil, "no WireGuard server found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params types.ConnectionParams
+			params.VpnType = vpn.WireGuard
+			params.WireGuardParameters.EntryVpnServer.Hosts = registered
+
+			err := setConnectionParamsLocationFromServers(&params, tt.location, servers, registered)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want error containing %q", err, tt.wantErr)
+				}
+				if !reflect.DeepEqual(params.WireGuardParameters.EntryVpnServer.Hosts, registered) {
+					t.Errorf("hosts are changed on error: %v", params.WireGuardParameters.EntryVpnServer.Hosts)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(params.WireGuardParameters.EntryVpnServer.Hosts, tt.wantHosts) {
+				t.Errorf("hosts = %v, want %v", params.WireGuardParameters.EntryVpnServer.Hosts, tt.wantHosts)
+			}
+		})
+	}
+
+	// OpenVPN: the location selects the server
+	var params types.ConnectionParams
+	params.VpnType = vpn.OpenVPN
+	if err := setConnectionParamsLocationFromServers(&params, "de", servers, registered); err != nil {
+		t.Fatalf("OpenVPN location: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(params.OpenVpnParameters.EntryVpnServer.Hosts, servers.OpenvpnServers[0].Hosts) {
+		t.Errorf("OpenVPN hosts = %v, want %v", params.OpenVpnParameters.EntryVpnServer.Hosts, servers.OpenvpnServers[0].Hosts)
+	}
+}
@@ -0,0 +1,373 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	apiTypes "github.com/swapnilsparsh/devsVPN/daemon/api/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/netinfo"
+	"github.com/swapnilsparsh/devsVPN/daemon/obfsproxy"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/history"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/v2r"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+// Transports of the automatic transport mode
+const (
+	autoTransportWireGuard          = preferences.WiFiProtocolWireGuard
+	autoTransportWireGuardV2RayQuic = preferences.WiFiProtocolWireGuardV2RayQuic
+	autoTransportWireGuardV2RayTcp  = preferences.WiFiProtocolWireGuardV2RayTcp
+	autoTransportOpenVpnObfs4       = "openvpn_obfs4"
+)
+
+// the connection attempt of a transport is cancelled after this number of seconds (the last transport uses CONNECT_ATTEMPT_TIMEOUT2_DISCONNECT)
+const autoTransportAttemptTimeout = 15
+
+// default order of the transports: plain WireGuard first, then the transports which are harder to detect and block
+var autoTransportsOrder = []string{autoTransportWireGuard, autoTransportWireGuardV2RayQuic, autoTransportWireGuardV2RayTcp, autoTransportOpenVpnObfs4}
+
+type autoTransportCandidate struct {
+	transport  string
+	params     types.ConnectionParams
+	ports      []apiTypes.PortInfo // ports required by the transport (at least one of them must be accessible)
+	skipReason string              // not empty - the transport is not applicable
+}
+
+// connectAttemptTimeout returns the number of seconds after which the connection attempt is cancelled (used by connectionAttemptTimeoutMonitor)
+func (s *Service) connectAttemptTimeout() int {
+	s._autoTransport._mutex.Lock()
+	defer s._autoTransport._mutex.Unlock()
+
+	if s._autoTransport._active && !s._autoTransport._isLast && !s._autoTransport._isConnected {
+		return autoTransportAttemptTimeout
+	}
+	return CONNECT_ATTEMPT_TIMEOUT2_DISCONNECT
+}
+
+// connectAutoTransport tries the transports one by one until one of them connects (automatic transport mode).
+// The transport which worked on the current network last time is tried first.
+// The function blocks until the connection ends.
+func (s *Service) connectAutoTransport(params types.ConnectionParams, canReconfigureOtherVpns bool) error {
+	networkKey := s.autoTransportNetworkKey()
+	candidates, err := s.autoTransportCandidates(params, networkKey)
+	if err != nil {
+		return log.ErrorFE("automatic transport mode: %w", err)
+	}
+
+	defer func() {
+		s._autoTransport._mutex.Lock()
+		defer s._autoTransport._mutex.Unlock()
+		s._autoTransport._active, s._autoTransport._attempt, s._autoTransport._isLast, s._autoTransport._isConnected = false, types.AutoTransportAttemptInfo{}, false, false
+	}()
+
+	lastAttemptIdx := -1
+	for i, c := range candidates {
+		if len(c.skipReason) == 0 {
+			lastAttemptIdx = i
+		}
+	}
+
+	var lastErr error
+	for i, c := range candidates {
+		if s.IsDaemonStopping() {
+			return log.ErrorFE("automatic transport mode: daemon is stopping")
+		}
+
+		info := types.AutoTransportAttemptInfo{Network: networkKey, Transport: c.transport, Attempt: i + 1, Total: len(candidates)}
+		if len(c.skipReason) > 0 {
+			info.Result, info.Error = types.AutoTransportAttemptSkipped, c.skipReason
+			s.notifyAutoTransportAttempt(info)
+			continue
+		}
+
+		info.Result = types.AutoTransportAttemptStarted
+		s.notifyAutoTransportAttempt(info)
+
+		s._autoTransport._mutex.Lock()
+		s._autoTransport._active, s._autoTransport._attempt, s._autoTransport._isLast, s._autoTransport._isConnected = true, info, i == lastAttemptIdx, false
+		s._autoTransport._mutex.Unlock()
+
+		disconnectRequestsCnt := s._disconnectRequestsCnt.Load()
+		err := s.connectTransport(c.params, canReconfigureOtherVpns)
+
+		s._autoTransport._mutex.Lock()
+		isConnected := s._autoTransport._isConnected
+		s._autoTransport._mutex.Unlock()
+		if isConnected {
+			// the transport worked (the connection is finished now)
+			return err
+		}
+
+		// The timed-out attempt is cancelled by Disconnect() call, so only the other disconnection requests stop trying the transports
+		var connectionAttemptFailedError *ConnectionAttemptFailedError
+		if !errors.As(err, &connectionAttemptFailedError) && s._disconnectRequestsCnt.Load() != disconnectRequestsCnt {
+			log.Info("Automatic transport mode: disconnection requested. Not trying other transports.")
+			return err
+		}
+
+		if err == nil {
+			err = fmt.Errorf("connection not established")
+		}
+		info.Result, info.Error = types.AutoTransportAttemptFailed, err.Error()
+		s.notifyAutoTransportAttempt(info)
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no applicable transports")
+	}
+	return log.ErrorFE("automatic transport mode: failed to connect using any transport: %w", lastErr)
+}
+
+// onAutoTransportConnected is called when VPN reached CONNECTED state.
+// In automatic transport mode it remembers the transport which worked on the current network.
+func (s *Service) onAutoTransportConnected() {
+	s._autoTransport._mutex.Lock()
+	if !s._autoTransport._active || s._autoTransport._isConnected {
+		s._autoTransport._mutex.Unlock()
+		return
+	}
+	s._autoTransport._isConnected = true
+	info := s._autoTransport._attempt
+	s._autoTransport._mutex.Unlock()
+
+	if len(info.Network) > 0 {
+		s.updatePreferences(func(p *preferences.Preferences) error {
+			p.SetAutoTransport(info.Network, info.Transport, time.Now().Unix())
+			return nil
+		})
+	}
+
+	info.Result = types.AutoTransportAttemptConnected
+	s.notifyAutoTransportAttempt(info)
+}
+
+func (s *Service) notifyAutoTransportAttempt(info types.AutoTransportAttemptInfo) {
+	info.Time = time.Now()
+
+	msg := fmt.Sprintf("Automatic transport mode: transport '%s' (%d of %d) %s", info.Transport, info.Attempt, info.Total, info.Result)
+	if len(info.Error) > 0 {
+		msg += ": " + info.Error
+	}
+	log.With("network", info.Network).Info(msg)
+
+	history.Add(history.EventTransport, msg, "network", info.Network, "transport", info.Transport, "result", info.Result)
+	s._evtReceiver.OnAutoTransportAttempt(info)
+}
+
+// autoTransportNetworkKey returns the key of the current network: "ssid:<SSID>" for WiFi networks, otherwise "gw:<gateway MAC or IP>".
+// Empty string - the network is unknown.
+func (s *Service) autoTransportNetworkKey() string {
+	if wifi, err := s.GetWiFiCurrentState(); err == nil && len(wifi.SSID) > 0 {
+		return "ssid:" + wifi.SSID
+	}
+
+	defNet, err := netinfo.DefaultNetwork()
+	if err != nil {
+		log.Warning(fmt.Sprintf("Automatic transport mode: unable to detect the current network: %v", err))
+		return ""
+	}
+	if len(defNet.GatewayMAC) > 0 {
+		return "gw:" + defNet.GatewayMAC.String()
+	}
+	if defNet.GatewayIP != nil {
+		return "gw:" + defNet.GatewayIP.String()
+	}
+	return ""
+}
+
+// autoTransportCandidates returns the list of transports to try (the transport remembered for the network goes first).
+// The transports which ports are not accessible (according to the echo-server test) are marked as skipped.
+func (s *Service) autoTransportCandidates(params types.ConnectionParams, networkKey string) ([]autoTransportCandidate, error) {
+	svrs, err := s.ServersList()
+	if err != nil {
+		return nil, fmt.Errorf("unable to obtain servers list: %w", err)
+	}
+	if len(params.WireGuardParameters.EntryVpnServer.Hosts) == 0 {
+		return nil, fmt.Errorf("WireGuard VPN host not defined")
+	}
+
+	order := autoTransportsOrder
+	if rec, ok := s._preferences.AutoTransportNetworks[networkKey]; ok && len(networkKey) > 0 {
+		order = autoTransportOrder(rec.Transport)
+	}
+
+	disabledFuncs := s.GetDisabledFunctions()
+	candidates := make([]autoTransportCandidate, 0, len(order))
+	for _, transport := range order {
+		c := autoTransportCandidate{transport: transport, params: params}
+		c.params.VpnType = vpn.WireGuard
+		c.params.WireGuardParameters.V2RayProxy = v2r.None
+		c.params.WireGuardParameters.Obfsproxy = obfsproxy.Config{}
+
+		switch transport {
+		case autoTransportWireGuard:
+			c.params.WireGuardParameters.Port.Protocol = 0 // UDP
+			for _, h := range params.WireGuardParameters.EntryVpnServer.Hosts {
+				if h.EndpointPort > 0 {
+					c.ports = append(c.ports, apiTypes.PortInfo{PortInfoBase: apiTypes.PortInfoBase{Type: "UDP", Port: h.EndpointPort}})
+				}
+			}
+		case autoTransportWireGuardV2RayQuic, autoTransportWireGuardV2RayTcp:
+			if len(disabledFuncs.V2RayError) > 0 {
+				c.skipReason = disabledFuncs.V2RayError
+				break
+			}
+			port := apiTypes.PortInfoBase{Type: "UDP", Port: 443} // the preferred port for V2Ray/QUIC
+			c.params.WireGuardParameters.V2RayProxy = v2r.QUIC
+			c.params.WireGuardParameters.Port.Protocol = 0
+			if transport == autoTransportWireGuardV2RayTcp {
+				port = apiTypes.PortInfoBase{Type: "TCP", Port: 80} // the preferred port for V2Ray/TCP
+				c.params.WireGuardParameters.V2RayProxy = v2r.TCP
+				c.params.WireGuardParameters.Port.Protocol = 1
+			}
+			c.params.WireGuardParameters.Port.Port = port.Port
+			c.ports = []apiTypes.PortInfo{{PortInfoBase: port}}
+		case autoTransportOpenVpnObfs4:
+			if len(disabledFuncs.OpenVPNError) > 0 {
+				c.skipReason = disabledFuncs.OpenVPNError
+				break
+			}
+			if len(disabledFuncs.ObfsproxyError) > 0 {
+				c.skipReason = disabledFuncs.ObfsproxyError
+				break
+			}
+			gateway := autoTransportWireGuardGateway(svrs, params.WireGuardParameters.EntryVpnServer.Hosts)
+			if len(gateway) == 0 {
+				c.skipReason = "unable to detect the location of the WireGuard server"
+				break
+			}
+			c.params.VpnType = vpn.OpenVPN
+			c.params.OpenVpnParameters.V2RayProxy = v2r.None
+			c.params.OpenVpnParameters.Obfs4proxy = obfsproxy.Config{Version: obfsproxy.OBFS4}
+			c.params.OpenVpnParameters.MultihopExitServer = types.MultiHopExitServer_OpenVpn{}
+			c.params.OpenVpnParameters.Port.Protocol = 1 // obfsproxy uses TCP
+			c.params.OpenVpnParameters.Port.Port = svrs.Config.Ports.Obfs4.Port
+			if err := s.setConnectionParamsLocation(&c.params, gateway); err != nil {
+				c.skipReason = err.Error()
+				break
+			}
+			c.ports = []apiTypes.PortInfo{{PortInfoBase: apiTypes.PortInfoBase{Type: "TCP", Port: svrs.Config.Ports.Obfs4.Port}}}
+		}
+		candidates = append(candidates, c)
+	}
+
+	s.autoTransportFilterByAccessiblePorts(svrs, candidates)
+	return candidates, nil
+}
+
+// autoTransportFilterByAccessiblePorts marks as skipped the candidates which ports are not accessible.
+// The test is performed only when the echo-server is defined; if no candidates left after the test - all of them will be tried.
+func (s *Service) autoTransportFilterByAccessiblePorts(svrs *apiTypes.ServersInfoResponse, candidates []autoTransportCandidate) {
+	if len(svrs.Config.Ports.Test) == 0 || len(svrs.Config.Ports.Test[0].EchoServer) == 0 {
+		return
+	}
+
+	var portsToTest []apiTypes.PortInfo
+	for _, c := range candidates {
+		if len(c.skipReason) == 0 {
+			portsToTest = append(portsToTest, c.ports...)
+		}
+	}
+	if len(portsToTest) == 0 {
+		return
+	}
+
+	accessiblePorts, err := s.DetectAccessiblePorts(portsToTest)
+	if err != nil {
+		log.Warning(fmt.Sprintf("Automatic transport mode: failed to detect accessible ports: %v", err))
+		return
+	}
+
+	autoTransportMarkInaccessible(candidates, accessiblePorts)
+}
+
+// autoTransportMarkInaccessible marks as skipped the candidates which have none of the required ports in 'accessiblePorts'.
+// If no candidates left - nothing is marked (all of them will be tried).
+func autoTransportMarkInaccessible(candidates []autoTransportCandidate, accessiblePorts []apiTypes.PortInfo) {
+	isAccessible := func(ports []apiTypes.PortInfo) bool {
+		if len(ports) == 0 {
+			return true // nothing to test
+		}
+		for _, p := range ports {
+			for _, ap := range accessiblePorts {
+				if p.Equal(ap) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	skipped := make([]bool, len(candidates))
+	hasAccessible := false
+	for i, c := range candidates {
+		if len(c.skipReason) > 0 {
+			continue
+		}
+		skipped[i] = !isAccessible(c.ports)
+		hasAccessible = hasAccessible || !skipped[i]
+	}
+	if !hasAccessible {
+		log.Info("Automatic transport mode: no accessible ports detected. Trying all transports.")
+		return
+	}
+	for i := range candidates {
+		if skipped[i] {
+			candidates[i].skipReason = fmt.Sprintf("ports %v are not accessible", candidates[i].ports)
+		}
+	}
+}
+
+// autoTransportOrder returns the order of the transports to try: the remembered transport first, then the rest in the default order.
+// Unknown remembered transport (e.g. from the preferences of other version) is ignored.
+func autoTransportOrder(remembered string) []string {
+	if !slices.Contains(autoTransportsOrder, remembered) {
+		return autoTransportsOrder
+	}
+	order := []string{remembered}
+	for _, t := range autoTransportsOrder {
+		if t != remembered {
+			order = append(order, t)
+		}
+	}
+	return order
+}
+
+// autoTransportWireGuardGateway returns the gateway ID (e.g. "us-tx") of the WireGuard server which contains the hosts
+func autoTransportWireGuardGateway(svrs *apiTypes.ServersInfoResponse, hosts []apiTypes.WireGuardServerHostInfo) string {
+	for _, svr := range svrs.WireguardServers {
+		for _, svrHost := range svr.Hosts {
+			for _, h := range hosts {
+				if ip := net.ParseIP(h.EndpointIP); ip != nil && ip.Equal(net.ParseIP(svrHost.EndpointIP)) {
+					return svr.Gateway
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	apiTypes "github.com/swapnilsparsh/devsVPN/daemon/api/types"
+)
+
+func TestAutoTransportOrder(t *testing.T) {
+	tests := []struct {
+		name       string
+		remembered string
+		want       []string
+	}{
+		{"nothing remembered", "", autoTransportsOrder},
+		{"default first transport", autoTransportWireGuard, autoTransportsOrder},
+		{"v2ray tcp", autoTransportWireGuardV2RayTcp,
+			[]string{autoTransportWireGuardV2RayTcp, autoTransportWireGuard, autoTransportWireGuardV2RayQuic, autoTransportOpenVpnObfs4}},
+		{"last transport", autoTransportOpenVpnObfs4,
+			[]string{autoTransportOpenVpnObfs4, autoTransportWireGuard, autoTransportWireGuardV2RayQuic, autoTransportWireGuardV2RayTcp}},
+		{"unknown transport", "openvpn_udp", autoTransportsOrder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := autoTransportOrder(tt.remembered)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("autoTransportOrder(%q) = %v, want %v", tt.remembered, got, tt.want)
+			}
+		})
+	}
+
+	// the default order must not be modified
+	autoTransportOrder(autoTransportOpenVpnObfs4)
+	if autoTransportsOrder[0] != autoTransportWireGuard || len(autoTransportsOrder) != 4 {
+		t.Errorf("default order modified: %v", autoTransportsOrder)
+	}
+}
+
+func testPort(portType string, p int) apiTypes.PortInfo {
+	return apiTypes.PortInfo{PortInfoBase: apiTypes.PortInfoBase{Type: portType, Port: p}}
+}
+
+func testAutoTransportCandidates() []autoTransportCandidate {
+	return []autoTransportCandidate{
+		{transport: autoTransportWireGuard, ports: []apiTypes.PortInfo{testPort("UDP", 51820), testPort("UDP", 2049)}},
+		{transport: autoTransportWireGuardV2RayQuic, ports: []apiTypes.PortInfo{testPort("UDP", 443)}},
+		{transport: autoTransportWireGuardV2RayTcp, ports: []apiTypes.PortInfo{testPort("TCP", 80)}},
+		{transport: autoTransportOpenVpnObfs4, skipReason: "OpenVPN binary not found", ports: []apiTypes.PortInfo{testPort("TCP", 5145)}},
+	}
+}
+
+func skipReasons(candidates []autoTransportCandidate) []string {
+	ret := make([]string, len(candidates))
+	for i, c := range candidates {
+		ret[i] = c.skipReason
+	}
+	return ret
+}
+
+func TestAutoTransportMarkInaccessible(t *testing.T) {
+	tests := []struct {
+		name       string
+		accessible []apiTypes.PortInfo
+		wantSkip   []bool
+	}{
+		{"all accessible", []apiTypes.PortInfo{testPort("UDP", 51820), testPort("UDP", 443), testPort("TCP", 80)}, []bool{false, false, false, true}},
+		{"one of the wireguard ports", []apiTypes.PortInfo{testPort("udp", 2049)}, []bool{false, true, true, true}},
+		{"only tcp", []apiTypes.PortInfo{testPort("TCP", 80)}, []bool{true, true, false, true}},
+		{"port type differs", []apiTypes.PortInfo{testPort("TCP", 443), testPort("TCP", 80)}, []bool{true, true, false, true}},
+		// an already skipped candidate does not become applicable when its port is accessible
+		{"skipped candidate port", []apiTypes.PortInfo{testPort("TCP", 5145), testPort("UDP", 443)}, []bool{true, false, true, true}},
+		// no accessible ports detected: all the applicable candidates will be tried
+		{"nothing accessible", nil, []bool{false, false, false, true}},
+		{"only skipped candidate port", []apiTypes.PortInfo{testPort("TCP", 5145)}, []bool{false, false, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := testAutoTransportCandidates()
+			autoTransportMarkInaccessible(candidates, tt.accessible)
+			for i, c := range candidates {
+				if isSkipped := len(c.skipReason) > 0; isSkipped != tt.wantSkip[i] {
+					t.Errorf("%s: skipped = %v (%q), want %v", c.transport, isSkipped, c.skipReason, tt.wantSkip[i])
+				}
+			}
+			if reason := candidates[3].skipReason; reason != "OpenVPN binary not found" {
+				t.Errorf("skip reason of the skipped candidate changed: %q", reason)
+			}
+			for _, c := range candidates[:3] {
+				if len(c.skipReason) > 0 && !strings.Contains(c.skipReason, "are not accessible") {
+					t.Errorf("%s: unexpected skip reason %q", c.transport, c.skipReason)
+				}
+			}
+		})
+	}
+
+	// a candidate without ports is always applicable
+	candidates := []autoTransportCandidate{{transport: autoTransportWireGuard}, {transport: autoTransportWireGuardV2RayTcp, ports: []apiTypes.PortInfo{testPort("TCP", 80)}}}
+	autoTransportMarkInaccessible(candidates, []apiTypes.PortInfo{testPort("UDP", 443)})
+	if reasons := skipReasons(candidates); len(reasons[0]) > 0 || len(reasons[1]) == 0 {
+		t.Errorf("skip reasons = %q, want only the candidate with inaccessible ports skipped", reasons)
+	}
+}
+
+func TestAutoTransportFilterByAccessiblePorts_NoTest(t *testing.T) {
+	s := &Service{}
+
+	// no echo-server defined: the ports are not tested
+	var svrs apiTypes.ServersInfoResponse
+	candidates := testAutoTransportCandidates()
+	s.autoTransportFilterByAccessiblePorts(&svrs, candidates)
+	if !reflect.DeepEqual(candidates, testAutoTransportCandidates()) {
+		t.Errorf("candidates modified without echo-server: %v", skipReasons(candidates))
+	}
+
+	// all candidates are skipped: nothing to test
+	svrs.Config.Ports.Test = []apiTypes.EchoServer{{EchoServer: "198.51.100.1"}}
+	candidates = testAutoTransportCandidates()
+	for i := range candidates {
+		candidates[i].skipReason = "disabled"
+	}
+	s.autoTransportFilterByAccessiblePorts(&svrs, candidates)
+	for _, c := range candidates {
+		if c.skipReason != "disabled" {
+			t.Errorf("%s: skip reason = %q, want %q", c.transport, c.skipReason, "disabled")
+		}
+	}
+}
@@ -43,6 +43,7 @@ import (
 	"github.com/swapnilsparsh/devsVPN/daemon/protocol"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/firewall"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/metrics"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/platform"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/platform/filerights"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/srverrors"
@@ -59,9 +60,27 @@ type svrConnInfo struct {
 	Port           int
 	PortType       int // UDP(0), TCP(1)
 	V2RayProxyType v2r.V2RayTransportType
+	Obfsproxy      obfsproxy.Config // pluggable transport in use for WireGuard connection
+}
+
+// obfsLocalProxy - local proxy which carries VPN traffic to the remote obfuscation server (V2Ray or pluggable transport).
+// When it is in use, the VPN connects to the local proxy port ('127.0.0.1:local_port') instead of the VPN server.
+type obfsLocalProxy interface {
+	// GetRemoteHosts returns IP addresses the proxy connects to (they must be allowed by firewall)
+	GetRemoteHosts() ([]net.IP, error)
+	// UpdateMainRoute updates routes to the remote hosts when the default gateway changed
+	UpdateMainRoute() error
+	Stop() error
 }
 
 func (s *Service) ValidateConnectionParameters(params types.ConnectionParams, isCanFix bool) (types.ConnectionParams, error) {
+	if params.IsCustomServer() {
+		// user-imported server: the imported configuration is in use
+		if _, err := s._preferences.CustomServer(params.CustomServerID); err != nil {
+			return params, log.ErrorFE("%w", err)
+		}
+		return params, nil
+	}
 	if params.VpnType == vpn.WireGuard {
 		// WireGuard connection parameters
 		if len(params.WireGuardParameters.EntryVpnServer.Hosts) <= 0 {
@@ -119,6 +138,9 @@ func (s *Service) Connect(params types.ConnectionParams) (err error) {
 		}
 	}()
 
+	// values defined by the locked configuration file can not be overridden
+	params = s.managedConfigEnforceConnectionParams(params)
+
 	// keep last used connection params
 	s.setConnectionParams(params)
 
@@ -141,6 +163,23 @@ func (s *Service) Connect(params types.ConnectionParams) (err error) {
 		}
 	}
 
+	if params.IsCustomServer() {
+		return s.connectCustomServer(params, canReconfigureOtherVpns)
+	}
+
+	if params.AutoTransport {
+		if !params.IsMultiHop() {
+			return s.connectAutoTransport(params, canReconfigureOtherVpns)
+		}
+		log.Info("Automatic transport mode is not applicable for Multi-Hop connections. Using the defined transport.")
+	}
+
+	return s.connectTransport(params, canReconfigureOtherVpns)
+}
+
+// connectTransport establishes the VPN connection using the VPN type and transport defined by the parameters.
+// The function blocks until the connection ends.
+func (s *Service) connectTransport(params types.ConnectionParams, canReconfigureOtherVpns bool) (err error) {
 	// Normalize hosts list
 	// - in case of multiple entry hosts - take one random host from the list
 	// - in case of multiple exit hosts - take one random host from the list
@@ -159,28 +198,46 @@ func (s *Service) Connect(params types.ConnectionParams) (err error) {
 	// ------------------------ Inverse Split Tunnel block end --------------------------
 
 	// ------------------------ V2RAY block start ------------------------
-	// 'originalEntryServerInfo' - will contain original info about EntryServer/Port (it is not 'nil' for V2Ray connections).
+	// 'originalEntryServerInfo' - will contain original info about EntryServer/Port (it is not 'nil' for V2Ray and WireGuard pluggable transport connections).
 	//  We need this info to notify correct data about vpn.CONNECTED state: for V2Ray connection the original parameters are overwriten by local V2Ray proxy params ('127.0.0.1:local_port')
 	var originalEntryServerInfo *svrConnInfo
-	var v2RayWrapper *v2r.V2RayWrapper
-	if params.V2Ray() == v2r.QUIC || params.V2Ray() == v2r.TCP {
+	var localProxy obfsLocalProxy
+	if params.V2Ray() != v2r.None {
 		disabledFuncs := s.GetDisabledFunctions()
 		if len(disabledFuncs.V2RayError) > 0 {
-			return log.ErrorFE(disabledFuncs.V2RayError)
+			return log.ErrorFE("%s", disabledFuncs.V2RayError)
 		}
 
 		log.Info("Starting V2Ray...")
 		// Note! the startV2Ray() modifies original params!
+		var v2RayWrapper *v2r.V2RayWrapper
 		params, v2RayWrapper, originalEntryServerInfo, err = s.startV2Ray(params, params.V2Ray())
 		if err != nil {
 			return log.ErrorFE("failed to start V2Ray: %w", err)
 		}
+		if v2RayWrapper != nil {
+			localProxy = v2RayWrapper
+		}
+	} else if vpn.Type(params.VpnType) == vpn.WireGuard && params.WireGuardParameters.Obfsproxy.IsObfsproxy() {
+		disabledFuncs := s.GetDisabledFunctions()
+		if len(disabledFuncs.ObfsproxyError) > 0 {
+			return log.ErrorFE("%s", disabledFuncs.ObfsproxyError)
+		}
+
+		log.Info("Starting obfsproxy tunnel for WireGuard...")
+		// Note! the startWireGuardObfsproxy() modifies original params!
+		var ptTunnel *obfsproxy.UdpTunnel
+		params, ptTunnel, originalEntryServerInfo, err = s.startWireGuardObfsproxy(params)
+		if err != nil {
+			return log.ErrorFE("failed to start obfsproxy: %w", err)
+		}
+		localProxy = ptTunnel
+	}
+	if localProxy != nil {
 		defer func() {
-			if v2RayWrapper != nil {
-				// stop V2Ray
-				if err := v2RayWrapper.Stop(); err != nil {
-					log.Error(fmt.Errorf("failed to stop V2Ray: %w", err))
-				}
+			// stop V2Ray (or obfsproxy tunnel)
+			if err := localProxy.Stop(); err != nil {
+				log.Error(fmt.Errorf("failed to stop obfuscation proxy: %w", err))
 			}
 		}()
 	}
@@ -247,12 +304,12 @@ func (s *Service) Connect(params types.ConnectionParams) (err error) {
 				proxyPassword)
 		}
 
-		if v2RayWrapper != nil {
+		if localProxy != nil {
 			// if V2Ray enabled - ignore obfsproxy option
 			params.OpenVpnParameters.Obfs4proxy = obfsproxy.Config{}
 		}
 
-		return s.connectOpenVPN(originalEntryServerInfo, connectionParams, params.ManualDNS, params.Metadata.AntiTracker, params.FirewallOn, params.FirewallOnDuringConnection, params.OpenVpnParameters.Obfs4proxy, v2RayWrapper, canReconfigureOtherVpns)
+		return s.connectOpenVPN(originalEntryServerInfo, connectionParams, params.ManualDNS, params.Metadata.AntiTracker, params.FirewallOn, params.FirewallOnDuringConnection, params.OpenVpnParameters.Obfs4proxy, localProxy, canReconfigureOtherVpns)
 
 	} else if vpn.Type(params.VpnType) == vpn.WireGuard {
 		if len(params.WireGuardParameters.EntryVpnServer.Hosts) < 1 {
@@ -314,14 +371,14 @@ func (s *Service) Connect(params types.ConnectionParams) (err error) {
 			)
 		}
 
-		return s.connectWireGuard(originalEntryServerInfo, connectionParams, params.ManualDNS, params.Metadata.AntiTracker, params.FirewallOn, params.FirewallOnDuringConnection, v2RayWrapper, canReconfigureOtherVpns)
+		return s.connectWireGuard(originalEntryServerInfo, connectionParams, params.ManualDNS, params.Metadata.AntiTracker, params.FirewallOn, params.FirewallOnDuringConnection, localProxy, canReconfigureOtherVpns)
 	}
 
 	return log.ErrorFE("unexpected VPN type to connect (%v)", params.VpnType)
 }
 
 // connectOpenVPN start OpenVPN connection
-func (s *Service) connectOpenVPN(originalEntryServerInfo *svrConnInfo, connectionParams openvpn.ConnectionParams, manualDNS dns.DnsSettings, antiTracker types.AntiTrackerMetadata, firewallOn bool, firewallDuringConnection bool, obfsproxyConfig obfsproxy.Config, v2rayWrapper *v2r.V2RayWrapper, canReconfigureOtherVpns bool) error {
+func (s *Service) connectOpenVPN(originalEntryServerInfo *svrConnInfo, connectionParams openvpn.ConnectionParams, manualDNS dns.DnsSettings, antiTracker types.AntiTrackerMetadata, firewallOn bool, firewallDuringConnection bool, obfsproxyConfig obfsproxy.Config, localProxy obfsLocalProxy, canReconfigureOtherVpns bool) error {
 
 	createVpnObjfunc := func() (vpn.Process, error) {
 		prefs := s.Preferences()
@@ -329,10 +386,10 @@ func (s *Service) connectOpenVPN(originalEntryServerInfo *svrConnInfo, connectio
 		// checking if functionality accessible
 		disabledFuncs := s.GetDisabledFunctions()
 		if len(disabledFuncs.OpenVPNError) > 0 {
-			return nil, fmt.Errorf(disabledFuncs.OpenVPNError)
+			return nil, errors.New(disabledFuncs.OpenVPNError)
 		}
 		if obfsproxyConfig.IsObfsproxy() && len(disabledFuncs.ObfsproxyError) > 0 {
-			return nil, fmt.Errorf(disabledFuncs.ObfsproxyError)
+			return nil, errors.New(disabledFuncs.ObfsproxyError)
 		}
 
 		connectionParams.SetCredentials(prefs.Session.OpenVPNUser, prefs.Session.OpenVPNPass)
@@ -439,7 +496,9 @@ func (s *Service) connectOpenVPN(originalEntryServerInfo *svrConnInfo, connectio
 			"",
 			obfsParams,
 			openVpnExtraParameters,
-			connectionParams)
+			connectionParams,
+			s._statsCallbacks,
+			prefs.OpenVpnLogVerbosity)
 
 		if err != nil {
 			return nil, fmt.Errorf("failed to create new openVPN object: %w", err)
@@ -447,11 +506,11 @@ func (s *Service) connectOpenVPN(originalEntryServerInfo *svrConnInfo, connectio
 		return vpnObj, nil
 	}
 
-	return s.keepConnection(originalEntryServerInfo, createVpnObjfunc, manualDNS, antiTracker, firewallOn, firewallDuringConnection, v2rayWrapper, canReconfigureOtherVpns)
+	return s.keepConnection(originalEntryServerInfo, createVpnObjfunc, manualDNS, antiTracker, firewallOn, firewallDuringConnection, localProxy, canReconfigureOtherVpns)
 }
 
 // connectWireGuard start WireGuard connection
-func (s *Service) connectWireGuard(originalEntryServerInfo *svrConnInfo, connectionParams wireguard.ConnectionParams, manualDNS dns.DnsSettings, antiTracker types.AntiTrackerMetadata, firewallOn bool, firewallDuringConnection bool, v2rayWrapper *v2r.V2RayWrapper, canReconfigureOtherVpns bool) error {
+func (s *Service) connectWireGuard(originalEntryServerInfo *svrConnInfo, connectionParams wireguard.ConnectionParams, manualDNS dns.DnsSettings, antiTracker types.AntiTrackerMetadata, firewallOn bool, firewallDuringConnection bool, localProxy obfsLocalProxy, canReconfigureOtherVpns bool) error {
 	// stop active connection (if exists)
 	if err := s.Disconnect(); err != nil {
 		return log.ErrorFE("failed to connect. Unable to stop active connection: %w", err)
@@ -460,7 +519,7 @@ func (s *Service) connectWireGuard(originalEntryServerInfo *svrConnInfo, connect
 	// checking if functionality accessible
 	disabledFuncs := s.GetDisabledFunctions()
 	if len(disabledFuncs.WireGuardError) > 0 {
-		return log.ErrorFE(disabledFuncs.WireGuardError)
+		return log.ErrorFE("%s", disabledFuncs.WireGuardError)
 	}
 
 	// Update WG keys, if necessary
@@ -506,10 +565,10 @@ func (s *Service) connectWireGuard(originalEntryServerInfo *svrConnInfo, connect
 		return vpnObj, nil
 	}
 
-	return s.keepConnection(originalEntryServerInfo, createVpnObjfunc, manualDNS, antiTracker, firewallOn, firewallDuringConnection, v2rayWrapper, canReconfigureOtherVpns)
+	return s.keepConnection(originalEntryServerInfo, createVpnObjfunc, manualDNS, antiTracker, firewallOn, firewallDuringConnection, localProxy, canReconfigureOtherVpns)
 }
 
-func (s *Service) keepConnection(originalEntryServerInfo *svrConnInfo, createVpnObj func() (vpn.Process, error), initialManualDNS dns.DnsSettings, initialAntiTracker types.AntiTrackerMetadata, firewallOn bool, firewallDuringConnection bool, v2rayWrapper *v2r.V2RayWrapper, canReconfigureOtherVpns bool) (retError error) {
+func (s *Service) keepConnection(originalEntryServerInfo *svrConnInfo, createVpnObj func() (vpn.Process, error), initialManualDNS dns.DnsSettings, initialAntiTracker types.AntiTrackerMetadata, firewallOn bool, firewallDuringConnection bool, localProxy obfsLocalProxy, canReconfigureOtherVpns bool) (retError error) {
 	prefs := s.Preferences()
 	if !prefs.Session.IsLoggedIn() {
 		return srverrors.ErrorNotLoggedIn{}
@@ -563,7 +622,7 @@ func (s *Service) keepConnection(originalEntryServerInfo *svrConnInfo, createVpn
 			antitracker,
 			firewallOn,               /* && !isInverseSplitTun */
 			firewallDuringConnection, /* && !isInverseSplitTun */
-			v2rayWrapper,
+			localProxy,
 			canReconfigureOtherVpns || s._preferences.PermissionReconfigureOtherVPNs)
 		if connErr != nil {
 			log.ErrorFE("s._requiredVpnState=%d. Connection error: '%w'", s._requiredVpnState, connErr)
@@ -577,6 +636,8 @@ func (s *Service) keepConnection(originalEntryServerInfo *svrConnInfo, createVpn
 
 		// retry, if reconnection requested
 		if s._requiredVpnState == KeepConnection {
+			metrics.IncReconnects()
+
 			// notifying clients about reconnection
 			s._evtReceiver.OnVpnStateChanged_SaveStateEarly(vpn.NewStateInfo(vpn.RECONNECTING, "Reconnecting due to disconnection"), true)
 
@@ -623,7 +684,7 @@ func (s *Service) keepConnection(originalEntryServerInfo *svrConnInfo, createVpn
 //     We need this info to notify correct data about vpn.CONNECTED state: for V2Ray connection the original parameters are overwriten by local V2Ray proxy params ('127.0.0.1:local_port')
 //   - Param 'firewallOn' - unconditionally reenable firewall before connection (if true - the parameter 'firewallDuringConnection' will be ignored).
 //   - Param 'firewallDuringConnection' - unconditionally reenable firewall before connection, and disable after disconnection
-func (s *Service) connect(originalEntryServerInfo *svrConnInfo, vpnProc vpn.Process, manualDNS dns.DnsSettings, antiTracker types.AntiTrackerMetadata, firewallOn bool, firewallDuringConnection bool, v2rayWrapper *v2r.V2RayWrapper, canReconfigureOtherVpns bool) (err error) {
+func (s *Service) connect(originalEntryServerInfo *svrConnInfo, vpnProc vpn.Process, manualDNS dns.DnsSettings, antiTracker types.AntiTrackerMetadata, firewallOn bool, firewallDuringConnection bool, localProxy obfsLocalProxy, canReconfigureOtherVpns bool) (err error) {
 	var connectRoutinesWaiter sync.WaitGroup
 
 	log.Debug("Service.connect() entered")
@@ -733,13 +794,13 @@ func (s *Service) connect(originalEntryServerInfo *svrConnInfo, vpnProc vpn.Proc
 	// Add VPN server IP to firewall exceptions
 	destinationIpAddresses = append(destinationIpAddresses, vpnProc.DestinationIP())
 
-	if v2rayWrapper != nil {
-		// Configure firewall to allow V2Ray remote IP
-		v2RayRemoteHost, _, err := v2rayWrapper.GetRemoteEndpoint()
+	if localProxy != nil {
+		// Configure firewall to allow remote IP of V2Ray (or obfsproxy tunnel)
+		remoteHosts, err := localProxy.GetRemoteHosts()
 		if err != nil {
-			return fmt.Errorf("failed to get V2Ray remote endpoint: %w", err)
+			return fmt.Errorf("failed to get obfuscation proxy remote endpoint: %w", err)
 		}
-		destinationIpAddresses = append(destinationIpAddresses, v2RayRemoteHost)
+		destinationIpAddresses = append(destinationIpAddresses, remoteHosts...)
 	}
 
 	// if firewall background monitors are available on the platform - start them all in the background
@@ -784,6 +845,9 @@ func (s *Service) connect(originalEntryServerInfo *svrConnInfo, vpnProc vpn.Proc
 					state.ServerPort = originalEntryServerInfo.Port // because state.ServerPort contains local port (port of local V2Ray proxy)
 					state.IsTCP = originalEntryServerInfo.PortType > 0
 					state.V2RayProxy = originalEntryServerInfo.V2RayProxyType
+					if originalEntryServerInfo.Obfsproxy.IsObfsproxy() {
+						state.Obfsproxy = originalEntryServerInfo.Obfsproxy
+					}
 				}
 
 				//  using the inline function to process state. It is required for a correct functioning of the "defer" statement
@@ -859,6 +923,9 @@ func (s *Service) connect(originalEntryServerInfo *svrConnInfo, vpnProc vpn.Proc
 						// send to connection timeout monitor stop chan - signal for it to stop, now that VPN is CONNECTED
 						go s.connectionAttemptTimeoutMonitorDef.StopServiceBackgroundMonitor() // async
 
+						// automatic transport mode: remember the transport which worked on the current network
+						s.onAutoTransportConnected()
+
 						// since we are connected - keep connection (reconnect if unexpected disconnection)
 						if s._requiredVpnState == Connect {
 							s._requiredVpnState = KeepConnection
@@ -914,6 +981,35 @@ func (s *Service) connect(originalEntryServerInfo *svrConnInfo, vpnProc vpn.Proc
 							<-stopChannel // triggered when the stopChannel is closed
 						}(s.connectivityHealthchecksBackgroundMonitorDef)
 
+						// ... and the connection-quality and path MTU monitors (WireGuard only)
+						if vpnProc.Type() == vpn.WireGuard {
+							connectRoutinesWaiter.Add(1)
+							go func(cqbm *srvhelpers.ServiceBackgroundMonitor) {
+								defer func() {
+									go cqbm.StopServiceBackgroundMonitor() // async
+									connectRoutinesWaiter.Done()
+								}()
+
+								go cqbm.MonitorFunc()
+								log.Debug("Monitor '", cqbm.MonitorName, "' started")
+
+								<-stopChannel // triggered when the stopChannel is closed
+							}(s.connectionQualityBackgroundMonitorDef)
+
+							connectRoutinesWaiter.Add(1)
+							go func(pmbm *srvhelpers.ServiceBackgroundMonitor) {
+								defer func() {
+									go pmbm.StopServiceBackgroundMonitor() // async
+									connectRoutinesWaiter.Done()
+								}()
+
+								go pmbm.MonitorFunc()
+								log.Debug("Monitor '", pmbm.MonitorName, "' started")
+
+								<-stopChannel // triggered when the stopChannel is closed
+							}(s.pmtuBackgroundMonitorDef)
+						}
+
 					default:
 					}
 				}()
@@ -956,9 +1052,9 @@ func (s *Service) connect(originalEntryServerInfo *svrConnInfo, vpnProc vpn.Proc
 					isRuning = false
 				}
 			case <-routingUpdateChan: // there were some routing changes but 'interfaceToProtect' is still is the default route
-				// If V2Ray is in use - we must update route to V2Ray server each time when default gateway IP was chnaged
-				if v2rayWrapper != nil {
-					if err := v2rayWrapper.UpdateMainRoute(); err != nil {
+				// If V2Ray (or obfsproxy tunnel) is in use - we must update route to its server each time when default gateway IP was chnaged
+				if localProxy != nil {
+					if err := localProxy.UpdateMainRoute(); err != nil {
 						log.Error(err)
 					}
 				}
@@ -1112,7 +1208,7 @@ func (s *Service) startV2Ray(params types.ConnectionParams, v2RayType v2r.V2RayT
 	originalEntryServerInfo *svrConnInfo,
 	err error) {
 
-	if v2RayType != v2r.QUIC && v2RayType != v2r.TCP {
+	if v2RayType == v2r.None {
 		return params, nil, nil, nil
 	}
 
@@ -1122,10 +1218,7 @@ func (s *Service) startV2Ray(params types.ConnectionParams, v2RayType v2r.V2RayT
 	}
 	outboundUserId := svrs.Config.Ports.V2Ray.ID
 
-	v2RayOutboundType := v2r.QUIC
-	if v2RayType == v2r.TCP {
-		v2RayOutboundType = v2r.TCP
-	}
+	v2RayOutboundType := v2RayType
 
 	remoteSvrDnsName := ""
 
@@ -1136,14 +1229,14 @@ func (s *Service) startV2Ray(params types.ConnectionParams, v2RayType v2r.V2RayT
 	if v2RayType == v2r.QUIC && isTcpOutboundPort {
 		return params, nil, nil, fmt.Errorf("not acceptable port type for V2Ray-QUIC connection (UDP is expected)")
 	}
-	if v2RayType == v2r.TCP && !isTcpOutboundPort {
-		return params, nil, nil, fmt.Errorf("not acceptable port type for V2Ray-TCP connection (TCP is expected)")
+	if v2RayType.IsTcpOutbound() && !isTcpOutboundPort {
+		return params, nil, nil, fmt.Errorf("not acceptable port type for V2Ray-%s connection (TCP is expected)", v2RayType.ToString())
 	}
 
 	if outboundPort == 0 {
 		// the preferred (but not mandatory) ports for outbound connection are:
 		// - 80 for HTTP/VMess/TCP
-		// - 443 for HTTPS/VMess/QUIC
+		// - 443 for HTTPS/VMess/QUIC and for VMess over TLS (WebSocket, gRPC, HTTP/2)
 		// (but it can be any other normal port which applicable for the selected VPN type)
 		outboundPort = 443
 		if v2RayOutboundType == v2r.TCP {
@@ -1194,7 +1287,7 @@ func (s *Service) startV2Ray(params types.ConnectionParams, v2RayType v2r.V2RayT
 		}
 	}
 
-	// TlsServerName required for QUIC connection
+	// TlsServerName required for QUIC connection (and it is the default SNI for WebSocket, gRPC and HTTP/2 transports)
 	outboundTlsSvrName = strings.Replace(remoteSvrDnsName, "ivpn.net", "inet-telecom.com", 1)
 
 	// Filter PORTS: TCP or UDP: the inbound port type should be similat to the local port type
@@ -1222,11 +1315,12 @@ func (s *Service) startV2Ray(params types.ConnectionParams, v2RayType v2r.V2RayT
 	// Start V2Ray process
 	v, err := v2r.Start(platform.V2RayBinaryPath(), platform.V2RayConfigFile(),
 		isTcpLocalPort,
-		v2RayOutboundType, // QUIC uses UDP outbound port; TCP uses TCP outbound port
+		v2RayOutboundType, // QUIC uses UDP outbound port; all others use TCP outbound port
 		outboundIp, outboundPort,
 		inboundIp, inboundPort,
 		outboundUserId,
-		outboundTlsSvrName)
+		outboundTlsSvrName,
+		params.V2RayOptions())
 	if err != nil {
 		return params, nil, nil, fmt.Errorf("failed to start v2ray: %w", err)
 	}
@@ -1330,9 +1424,9 @@ func (s *Service) connectionAttemptTimeoutMonitor() {
 			if !alreadyEstablishedConnection && !s.connectAttemptTimeout2Reached_CancelledConnectionAttempt { // if didn't establish connection yet and didn't reach the last deadline(s) yet
 				secondsWaited = secondsWaited + 1
 
-				if secondsWaited > CONNECT_ATTEMPT_TIMEOUT2_DISCONNECT { // if waited 30 sec - cancel the connection attempt
+				if timeout := s.connectAttemptTimeout(); secondsWaited > timeout { // if waited 30 sec (less in automatic transport mode) - cancel the connection attempt
 					s.connectAttemptTimeout2Reached_CancelledConnectionAttempt = true
-					log.Error("Connection attempt timed out after waiting for ", CONNECT_ATTEMPT_TIMEOUT2_DISCONNECT, " seconds - disconnecting")
+					log.Error("Connection attempt timed out after waiting for ", timeout, " seconds - disconnecting")
 					go s.Disconnect() // Fork a disconnect request. TODO: Vlad - when CHR HA support is implemented, switch to another server instead
 				} else if !s.connectAttemptTimeout1Reached_UserNotificationCheckDone && secondsWaited > CONNECT_ATTEMPT_TIMEOUT1_NOTIFY_USER { // if waited 10 sec - show VPN Coexistence status "FAILED|Fix" in UI
 					s.connectAttemptTimeout1Reached_UserNotificationCheckDone = true
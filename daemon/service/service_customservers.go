@@ -0,0 +1,312 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/platform"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn/openvpn"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn/wireguard"
+)
+
+var wgConfigSectionRegexp = regexp.MustCompile(`(?im)^\s*\[(Interface|Peer)\]\s*$`)
+
+// CustomServers returns the list of user-imported servers (secrets are not included)
+func (s *Service) CustomServers() []types.CustomServerInfo {
+	servers := s._preferences.CustomServers
+	ret := make([]types.CustomServerInfo, 0, len(servers))
+	for _, svr := range servers {
+		info, err := customServerInfo(svr)
+		if err != nil {
+			log.Warning(fmt.Sprintf("Custom server '%s': %v", svr.Name, err))
+		}
+		ret = append(ret, info)
+	}
+	return ret
+}
+
+// CustomServerImport imports WireGuard ('.conf') or OpenVPN ('.ovpn') configuration file as a custom server.
+// The type of the configuration is detected by its content.
+//
+//	username, password: (OpenVPN) credentials, required when the configuration contains 'auth-user-pass'
+func (s *Service) CustomServerImport(name, config, username, password string) (types.CustomServerInfo, error) {
+	if len(config) > preferences.MaxCustomServerConfigSize {
+		return types.CustomServerInfo{}, fmt.Errorf("configuration is too large (maximum %d bytes)", preferences.MaxCustomServerConfigSize)
+	}
+
+	svr := preferences.CustomServer{Name: name, Config: config}
+	var host string
+	if wgConfigSectionRegexp.MatchString(config) {
+		svr.VpnType = vpn.WireGuard
+		cfg, err := wireguard.ParseCustomConfig(config)
+		if err != nil {
+			return types.CustomServerInfo{}, fmt.Errorf("bad WireGuard configuration: %w", err)
+		}
+		host = cfg.EndpointHost
+	} else {
+		svr.VpnType = vpn.OpenVPN
+		cfg, err := openvpn.ParseCustomConfig(config)
+		if err != nil {
+			return types.CustomServerInfo{}, fmt.Errorf("bad OpenVPN configuration: %w", err)
+		}
+		if cfg.IsAuthUserPass {
+			if len(username) == 0 || len(password) == 0 {
+				return types.CustomServerInfo{}, fmt.Errorf("the configuration requires username and password ('auth-user-pass')")
+			}
+			// only one-line parameters are allowed
+			svr.Username, svr.Password = strings.Split(username, "\n")[0], strings.Split(password, "\n")[0]
+		}
+		host = cfg.RemoteHost
+	}
+
+	// resolve the host now: DNS can be blocked by the firewall when connecting
+	if ip, err := resolveCustomServerHost(host); err != nil {
+		log.Warning(fmt.Sprintf("Custom server '%s': %v", strings.TrimSpace(name), err))
+	} else {
+		svr.EndpointIP = ip.String()
+	}
+
+	if err := s.updatePreferences(func(p *preferences.Preferences) (err error) {
+		svr, err = p.AddCustomServer(svr)
+		return err
+	}); err != nil {
+		return types.CustomServerInfo{}, err
+	}
+
+	log.Info(fmt.Sprintf("Custom server imported: '%s' (%s, %s)", svr.Name, svr.VpnType, host))
+	return customServerInfo(svr)
+}
+
+// CustomServerDelete removes the user-imported server
+func (s *Service) CustomServerDelete(idOrName string) error {
+	var svr preferences.CustomServer
+	if err := s.updatePreferences(func(p *preferences.Preferences) (err error) {
+		svr, err = p.DeleteCustomServer(idOrName)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	log.Info(fmt.Sprintf("Custom server removed: '%s'", svr.Name))
+	return nil
+}
+
+// ConnectedCustomServer returns the name of the custom server of the current connection (empty - privateLINE server)
+func (s *Service) ConnectedCustomServer() string {
+	if svr, ok := s.activeCustomServer(); ok {
+		return svr.Name
+	}
+	return ""
+}
+
+func (s *Service) activeCustomServer() (preferences.CustomServer, bool) {
+	s._customServer._mutex.Lock()
+	defer s._customServer._mutex.Unlock()
+	if s._customServer._active == nil {
+		return preferences.CustomServer{}, false
+	}
+	return *s._customServer._active, true
+}
+
+// connectCustomServer establishes the VPN connection to the user-imported server.
+// The connection goes through the same firewall (kill-switch, Total Shield) and DNS configuration as the connection to privateLINE servers;
+// the server host is added to the firewall exceptions.
+// The DNS servers defined by the configuration are in use (WireGuard: 'DNS' option; OpenVPN: DNS pushed by the server).
+// The function blocks until the connection ends.
+func (s *Service) connectCustomServer(params types.ConnectionParams, canReconfigureOtherVpns bool) error {
+	svr, err := s._preferences.CustomServer(params.CustomServerID)
+	if err != nil {
+		return log.ErrorFE("%w", err)
+	}
+
+	active := &svr
+	s._customServer._mutex.Lock()
+	s._customServer._active = active
+	s._customServer._mutex.Unlock()
+	defer func() {
+		s._customServer._mutex.Lock()
+		if s._customServer._active == active { // the next connection can be already started
+			s._customServer._active = nil
+		}
+		s._customServer._mutex.Unlock()
+	}()
+
+	disabledFuncs := s.GetDisabledFunctions()
+
+	switch svr.VpnType {
+	case vpn.WireGuard:
+		if len(disabledFuncs.WireGuardError) > 0 {
+			return log.ErrorFE("%s", disabledFuncs.WireGuardError)
+		}
+		cfg, err := wireguard.ParseCustomConfig(svr.Config)
+		if err != nil {
+			return log.ErrorFE("custom server '%s': bad WireGuard configuration: %w", svr.Name, err)
+		}
+		hostIP, err := s.customServerEndpointIP(svr, cfg.EndpointHost)
+		if err != nil {
+			return log.ErrorFE("custom server '%s': %w", svr.Name, err)
+		}
+		if len(cfg.Ignored) > 0 {
+			log.Info(fmt.Sprintf("Custom server '%s': ignored options: %s", svr.Name, strings.Join(cfg.Ignored, "; ")))
+		}
+		connectionParams := wireguard.CreateCustomConnectionParams(cfg, hostIP)
+
+		// stop active connection (if exists)
+		if err := s.Disconnect(); err != nil {
+			return log.ErrorFE("failed to connect. Unable to stop active connection: %w", err)
+		}
+
+		log.Info(fmt.Sprintf("Connecting to custom WireGuard server '%s' (%s:%d)...", svr.Name, hostIP, cfg.EndpointPort))
+		createVpnObjfunc := func() (vpn.Process, error) {
+			vpnObj, err := wireguard.NewWireGuardObject(
+				platform.WgBinaryPath(),
+				platform.WgToolBinaryPath(),
+				platform.WGConfigFilePath(),
+				connectionParams,
+				s._statsCallbacks)
+			if err != nil {
+				return nil, log.ErrorFE("failed to create new WireGuard object: %w", err)
+			}
+			return vpnObj, nil
+		}
+		manualDNS := dns.DnsSettings{DnsServers: cfg.DnsServers}
+		return s.keepConnection(nil, createVpnObjfunc, manualDNS, params.Metadata.AntiTracker, params.FirewallOn, params.FirewallOnDuringConnection, nil, canReconfigureOtherVpns)
+
+	case vpn.OpenVPN:
+		if len(disabledFuncs.OpenVPNError) > 0 {
+			return log.ErrorFE("%s", disabledFuncs.OpenVPNError)
+		}
+		cfg, err := openvpn.ParseCustomConfig(svr.Config)
+		if err != nil {
+			return log.ErrorFE("custom server '%s': bad OpenVPN configuration: %w", svr.Name, err)
+		}
+		hostIP, err := s.customServerEndpointIP(svr, cfg.RemoteHost)
+		if err != nil {
+			return log.ErrorFE("custom server '%s': %w", svr.Name, err)
+		}
+		if len(cfg.Ignored) > 0 {
+			log.Info(fmt.Sprintf("Custom server '%s': ignored options: %s", svr.Name, strings.Join(cfg.Ignored, "; ")))
+		}
+		connectionParams := openvpn.CreateCustomConnectionParams(cfg, hostIP, svr.Username, svr.Password)
+
+		log.Info(fmt.Sprintf("Connecting to custom OpenVPN server '%s' (%s:%d)...", svr.Name, hostIP, cfg.RemotePort))
+		createVpnObjfunc := func() (vpn.Process, error) {
+			vpnObj, err := openvpn.NewOpenVpnObject(
+				platform.OpenVpnBinaryPath(),
+				platform.OpenvpnConfigFile(),
+				"",
+				openvpn.ObfsParams{},
+				"",
+				connectionParams,
+				s._statsCallbacks,
+				s.Preferences().OpenVpnLogVerbosity)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create new openVPN object: %w", err)
+			}
+			return vpnObj, nil
+		}
+		// no manual DNS: the DNS pushed by the server is in use
+		return s.keepConnection(nil, createVpnObjfunc, dns.DnsSettings{}, params.Metadata.AntiTracker, params.FirewallOn, params.FirewallOnDuringConnection, nil, canReconfigureOtherVpns)
+	}
+
+	return log.ErrorFE("custom server '%s': unexpected VPN type (%v)", svr.Name, svr.VpnType)
+}
+
+// customServerEndpointIP resolves the custom server host.
+// When the host can not be resolved (e.g. DNS is blocked by the firewall) - the latest resolved address is in use.
+func (s *Service) customServerEndpointIP(svr preferences.CustomServer, host string) (net.IP, error) {
+	ip, err := resolveCustomServerHost(host)
+	if err != nil {
+		if lastIP := net.ParseIP(svr.EndpointIP); lastIP != nil {
+			log.Warning(fmt.Sprintf("Custom server '%s': %v; using the latest resolved address %s", svr.Name, err, lastIP))
+			return lastIP, nil
+		}
+		return nil, err
+	}
+
+	if ip.String() != svr.EndpointIP {
+		s.updatePreferences(func(p *preferences.Preferences) error {
+			p.SetCustomServerEndpointIP(svr.ID, ip.String())
+			return nil
+		})
+	}
+	return ip, nil
+}
+
+// resolveCustomServerHost returns IP address of the host (IPv4 addresses are preferred)
+func resolveCustomServerHost(host string) (net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip, nil
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve '%s': %w", host, err)
+	}
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip, nil
+		}
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no IP address for '%s'", host)
+	}
+	return ips[0], nil
+}
+
+// customServerInfo returns information about the custom server (secrets are not included)
+func customServerInfo(svr preferences.CustomServer) (types.CustomServerInfo, error) {
+	info := types.CustomServerInfo{
+		ID:         svr.ID,
+		Name:       svr.Name,
+		VpnType:    svr.VpnType,
+		EndpointIP: svr.EndpointIP,
+		Imported:   svr.Imported,
+	}
+
+	switch svr.VpnType {
+	case vpn.WireGuard:
+		cfg, err := wireguard.ParseCustomConfig(svr.Config)
+		if err != nil {
+			return info, err
+		}
+		info.Host, info.Port = cfg.EndpointHost, cfg.EndpointPort
+		for _, ip := range cfg.DnsServers {
+			info.DnsServers = append(info.DnsServers, ip.String())
+		}
+		info.AllowedIPs = cfg.AllowedIPs
+		info.Ignored = cfg.Ignored
+	case vpn.OpenVPN:
+		cfg, err := openvpn.ParseCustomConfig(svr.Config)
+		if err != nil {
+			return info, err
+		}
+		info.Host, info.Port, info.IsTCP = cfg.RemoteHost, cfg.RemotePort, cfg.TCP
+		info.Ignored = cfg.Ignored
+	}
+	return info, nil
+}
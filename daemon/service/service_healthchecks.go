@@ -24,6 +24,8 @@ import (
 	"time"
 
 	"github.com/swapnilsparsh/devsVPN/daemon/service/firewall"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/history"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/metrics"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
 )
 
@@ -55,7 +57,9 @@ func (s *Service) whileConnectedCheckConnectivityFixAsNeeded() (retErr error) {
 		return nil
 	}
 
-	if backendReachable, err := s.CheckBackendConnectivity(); backendReachable && err == nil {
+	backendReachable, err := s.CheckBackendConnectivity()
+	metrics.IncHealthchecks(!backendReachable || err != nil)
+	if backendReachable && err == nil {
 		s.backendConnectivityCheckPhase = PHASE0_CLEAN
 		s.backendConnectivityCheckBad.Store(false)
 		if notificationsAfterReconnect < MAX_CLIENT_NOTIFICATIONS {
@@ -95,8 +99,14 @@ func (s *Service) whileConnectedCheckConnectivityFixAsNeeded() (retErr error) {
 		}
 	}
 
+	healthcheckType := ""
+	if hcType := s._preferences.HealthchecksType; hcType >= 0 && int(hcType) < len(types.HealthcheckTypeNames) {
+		healthcheckType = types.HealthcheckTypeNames[hcType]
+	}
+
 	switch s.backendConnectivityCheckPhase {
 	case PHASE0_CLEAN: // phase 0: fully redeploy firewall and VPN coexistence rules
+		history.Add(history.EventHealthcheck, "backend not reachable: redeploying firewall rules", "healthcheck", healthcheckType, "action", "firewall_reregister")
 		s.backendConnectivityCheckPhase = PHASE1_TRY_RECONNECT // if backend again not reachable on next try - don't try firewall reconfig, try VPN disconnect-reconnect
 		log.Debug("PHASE0_CLEAN: about to fully redeploy firewall and VPN coexistence rules")
 		if err := firewall.TryReregisterFirewallAtTopPriority(true, true); err != nil {
@@ -105,6 +115,7 @@ func (s *Service) whileConnectedCheckConnectivityFixAsNeeded() (retErr error) {
 	case PHASE1_TRY_RECONNECT: // phase 1: disable Total Shield and disconnect-reconnect the VPN
 		s.backendConnectivityCheckPhase = PHASE0_CLEAN // next time don't try to reconnect, reset to phase0
 		log.Debug("PHASE1_TRY_RECONNECT: about to disable Total Shield and disconnect-reconnect the VPN")
+		history.Add(history.EventHealthcheck, "backend not reachable: reconnecting", "healthcheck", healthcheckType, "action", "reconnect")
 		prefs := s._preferences // disable Total Shield in preferences
 		if prefs.IsTotalShieldOn {
 			prefs.IsTotalShieldOn = false
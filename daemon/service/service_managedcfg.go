@@ -0,0 +1,171 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/managedcfg"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/platform"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	service_types "github.com/swapnilsparsh/devsVPN/daemon/service/types"
+)
+
+// managedConfigInit reads the headless configuration file and applies the managed values to the preferences.
+// Called on service initialization, so it is enough to update preferences: the functionality will be initialized according to them.
+func (s *Service) managedConfigInit() {
+	s._managedCfgMutex.Lock()
+	defer s._managedCfgMutex.Unlock()
+
+	file := platform.ManagedConfigFile()
+	cfg, err := managedcfg.Load(file)
+	if err != nil {
+		log.Error(fmt.Sprintf("Failed to load configuration file '%s' (ignored): %v", file, err))
+		return
+	}
+	if cfg == nil {
+		return
+	}
+
+	var changes []managedcfg.Change
+	s.updatePreferences(func(p *preferences.Preferences) error {
+		changes = cfg.Apply(p)
+		return nil
+	})
+	s._managedCfg = cfg
+
+	log.Info(fmt.Sprintf("Configuration file '%s' applied (locked: %t; changes: %d)", file, cfg.Locked, len(changes)))
+	for _, c := range changes {
+		log.Info("  ", c.String())
+	}
+}
+
+// ManagedConfigApply (re)reads the headless configuration file and applies it (e.g. on SIGHUP).
+// If dryRun is 'true' - the configuration is only validated and the list of changes which would be applied is returned.
+// On error, the previously applied configuration stays active.
+func (s *Service) ManagedConfigApply(dryRun bool) (status managedcfg.Status, err error) {
+	s._managedCfgMutex.Lock()
+	defer s._managedCfgMutex.Unlock()
+
+	file := platform.ManagedConfigFile()
+	status = managedcfg.Status{File: file, IsLoaded: s._managedCfg != nil}
+
+	cfg, err := managedcfg.Load(file)
+	if err != nil {
+		return status, err
+	}
+	if cfg == nil {
+		if !dryRun && s._managedCfg != nil {
+			log.Info(fmt.Sprintf("Configuration file '%s' removed: no managed preferences anymore", file))
+			s._managedCfg = nil
+			status.IsLoaded = false
+		}
+		return status, nil
+	}
+
+	status.Locked = cfg.Locked
+	status.ManagedKeys = cfg.ManagedKeys()
+	if dryRun {
+		newPrefs := s._preferences
+		status.Changes = cfg.Apply(&newPrefs)
+		return status, nil
+	}
+
+	var oldPrefs, newPrefs preferences.Preferences
+	s.updatePreferences(func(p *preferences.Preferences) error {
+		oldPrefs = *p
+		status.Changes = cfg.Apply(p)
+		newPrefs = *p
+		return nil
+	})
+
+	s._managedCfg = cfg
+	status.IsLoaded = true
+
+	log.Info(fmt.Sprintf("Configuration file '%s' applied (locked: %t; changes: %d)", file, cfg.Locked, len(status.Changes)))
+	for _, c := range status.Changes {
+		log.Info("  ", c.String())
+	}
+	if len(status.Changes) > 0 {
+		err = s.applyChangedPreferences(oldPrefs, newPrefs)
+		s._evtReceiver.OnPreferencesChanged()
+	}
+	return status, err
+}
+
+// managedConfigReapply applies the managed values of the currently active configuration to the preferences (e.g. after preferences reset)
+func (s *Service) managedConfigReapply() {
+	s._managedCfgMutex.Lock()
+	defer s._managedCfgMutex.Unlock()
+
+	if s._managedCfg == nil {
+		return
+	}
+	var oldPrefs, newPrefs preferences.Preferences
+	var changes []managedcfg.Change
+	s.updatePreferences(func(p *preferences.Preferences) error {
+		oldPrefs = *p
+		changes = s._managedCfg.Apply(p)
+		newPrefs = *p
+		return nil
+	})
+	if len(changes) > 0 {
+		if err := s.applyChangedPreferences(oldPrefs, newPrefs); err != nil {
+			log.Error("Failed to apply managed preferences: ", err)
+		}
+	}
+}
+
+// ManagedConfigLockedKey returns the first of the keys which is managed by the locked configuration file ("" - none of the keys are locked).
+// The key can be a section name (e.g. "wifi" matches all "wifi.*" keys).
+func (s *Service) ManagedConfigLockedKey(keys ...string) string {
+	s._managedCfgMutex.Lock()
+	defer s._managedCfgMutex.Unlock()
+
+	for _, k := range keys {
+		if s._managedCfg.IsKeyLocked(k) {
+			return k
+		}
+	}
+	return ""
+}
+
+// managedConfigLocked returns the active configuration if it is locked (nil - no locked configuration).
+// The configuration is not modified after loading, so it can be applied without holding the lock.
+func (s *Service) managedConfigLocked() *managedcfg.Config {
+	s._managedCfgMutex.Lock()
+	defer s._managedCfgMutex.Unlock()
+
+	if s._managedCfg != nil && s._managedCfg.Locked {
+		return s._managedCfg
+	}
+	return nil
+}
+
+// managedConfigEnforceConnectionParams overrides the connection parameters by the values from the locked configuration file
+func (s *Service) managedConfigEnforceConnectionParams(params service_types.ConnectionParams) service_types.ConnectionParams {
+	s._managedCfgMutex.Lock()
+	defer s._managedCfgMutex.Unlock()
+
+	if s._managedCfg != nil && s._managedCfg.Locked {
+		s._managedCfg.ApplyToConnectionParams(&params)
+	}
+	return params
+}
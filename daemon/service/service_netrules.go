@@ -0,0 +1,210 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/netinfo"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+)
+
+// netRulesReachabilityTimeout - timeout for checking the 'reachable host' condition of the network rules
+const netRulesReachabilityTimeout = time.Second * 2
+
+type lastProcessedNetworkInfo struct {
+	network     netinfo.DefaultNetworkInfo
+	rules       []preferences.NetworkRule
+	matchedRule string
+	action      automaticAction
+}
+
+var autoconnectLastProcessedNetwork lastProcessedNetworkInfo
+
+var netRulesMonitorMutex sync.Mutex
+
+// NetworkRules returns the network-condition rules
+func (s *Service) NetworkRules() []preferences.NetworkRule {
+	return s._preferences.NetworkRules
+}
+
+// SetNetworkRules validates and saves the network-condition rules; the rules are applied immediately
+func (s *Service) SetNetworkRules(rules []preferences.NetworkRule) error {
+	if err := s.updatePreferences(func(p *preferences.Preferences) error {
+		validRules, err := p.ValidateNetworkRules(rules)
+		if err != nil {
+			return err
+		}
+		p.NetworkRules = validRules
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	s.netRulesMonitorUpdate()
+	return s.autoConnectIfRequired(OnNetworkChanged, nil)
+}
+
+// NetworkRulesStatus returns information about the current default network and the name of the rule which matches it
+func (s *Service) NetworkRulesStatus() (network netinfo.DefaultNetworkInfo, matchedRule string, err error) {
+	rule, network, err := matchNetworkRule(s._preferences.NetworkRules)
+	if rule != nil {
+		matchedRule = rule.Name
+	}
+	return network, matchedRule, err
+}
+
+// matchNetworkRule returns the first enabled rule which matches the current default network (nil - no matching rules)
+func matchNetworkRule(rules []preferences.NetworkRule) (*preferences.NetworkRule, netinfo.DefaultNetworkInfo, error) {
+	network, err := netinfo.DefaultNetwork()
+	if err != nil {
+		return nil, network, fmt.Errorf("unable to obtain default network info: %w", err)
+	}
+
+	isReachable := func(hostPort string) bool {
+		conn, err := net.DialTimeout("tcp", hostPort, netRulesReachabilityTimeout)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}
+
+	for i, r := range rules {
+		if !r.Disabled && r.Conditions.Match(network, isReachable) {
+			return &rules[i], network, nil
+		}
+	}
+	return nil, network, nil
+}
+
+func hasEnabledNetworkRules(rules []preferences.NetworkRule) bool {
+	for _, r := range rules {
+		if !r.Disabled {
+			return true
+		}
+	}
+	return false
+}
+
+// isNetworkRulesBlockLanPossible returns true when any of the rules can force blocking LAN
+func isNetworkRulesBlockLanPossible(rules []preferences.NetworkRule) bool {
+	for _, r := range rules {
+		if !r.Disabled && r.Actions.BlockLan != nil && *r.Actions.BlockLan {
+			return true
+		}
+	}
+	return false
+}
+
+// getActionForNetworkRules returns the action of the network rule which matches the current network
+//
+//	isProcessedAlready: true - the same network (and the same rule) has been processed already
+func (s *Service) getActionForNetworkRules() (retAction automaticAction, isProcessedAlready bool) {
+	prefs := s.Preferences()
+	if !prefs.Session.IsLoggedIn() || !hasEnabledNetworkRules(prefs.NetworkRules) {
+		autoconnectLastProcessedNetwork = lastProcessedNetworkInfo{}
+		return
+	}
+
+	rule, network, err := matchNetworkRule(prefs.NetworkRules)
+	if err != nil {
+		log.Warning(fmt.Sprintf("Automatic connection manager: network rules: %v", err))
+	}
+
+	curr := lastProcessedNetworkInfo{network: network, rules: prefs.NetworkRules}
+	if rule != nil {
+		applyWiFiNetworkActions(&retAction, rule.Actions)
+		if retAction.Vpn == VPN_On {
+			retAction.Profile = rule.Profile
+		}
+		curr.matchedRule = rule.Name
+		curr.action = retAction
+	}
+
+	isProcessedAlready = reflect.DeepEqual(autoconnectLastProcessedNetwork, curr)
+	autoconnectLastProcessedNetwork = curr
+
+	if rule != nil && !isProcessedAlready {
+		log.Info(fmt.Sprintf("Automatic connection manager: network rule '%s' matches the current network (interface '%s'; gateway %s)",
+			rule.Name, network.InterfaceName, network.GatewayIP))
+	}
+	return retAction, isProcessedAlready
+}
+
+// netRulesMonitorUpdate starts (when there are enabled network rules) or stops detecting network changes for the network rules
+func (s *Service) netRulesMonitorUpdate() {
+	netRulesMonitorMutex.Lock()
+	defer netRulesMonitorMutex.Unlock()
+
+	if s._netRulesDetector == nil {
+		return
+	}
+
+	isRequired := hasEnabledNetworkRules(s._preferences.NetworkRules) && !s._daemonStopping.Load()
+	if isRequired == (s._netRulesMonitorStop != nil) {
+		return // nothing to change
+	}
+
+	if !isRequired {
+		s._netRulesDetector.UnInit()
+		close(s._netRulesMonitorStop)
+		s._netRulesMonitorStop = nil
+		return
+	}
+
+	routingChangeChan := make(chan struct{}, 1)
+	noTotalShieldCallback := func(bool) error { return nil } // Total Shield is processed by the detector of the VPN connection
+	if err := s._netRulesDetector.Init(routingChangeChan, routingChangeChan, nil, noTotalShieldCallback, s.Preferences); err != nil {
+		log.Error(fmt.Sprintf("Network rules: unable to initialise network changes detection: %v", err))
+		return
+	}
+	if err := s._netRulesDetector.Start(); err != nil {
+		log.Error(fmt.Sprintf("Network rules: unable to start network changes detection: %v", err))
+		return
+	}
+
+	stop := make(chan struct{})
+	s._netRulesMonitorStop = stop
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error("PANIC in network rules monitor: ", r)
+				log.Error(string(debug.Stack()))
+			}
+		}()
+
+		log.Info("Network rules monitor started")
+		defer log.Info("Network rules monitor stopped")
+		for {
+			select {
+			case <-stop:
+				return
+			case <-routingChangeChan:
+				s.autoConnectIfRequired(OnNetworkChanged, nil)
+			}
+		}
+	}()
+}
@@ -0,0 +1,106 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"net"
+
+	api_types "github.com/swapnilsparsh/devsVPN/daemon/api/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/obfsproxy"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/platform"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+)
+
+// startWireGuardObfsproxy starts the pluggable transport tunnel for WireGuard connection.
+// WireGuard UDP datagrams are carried over obfs4 (TCP) or HTTP transports (meek, WebTunnel) to the pluggable transports bridge
+// on the entry server, which forwards them to WireGuard.
+// The connection parameters are updated to connect WireGuard to the local tunnel port ('127.0.0.1:local_port').
+func (s *Service) startWireGuardObfsproxy(params types.ConnectionParams) (
+	updatedParams types.ConnectionParams,
+	tunnel *obfsproxy.UdpTunnel,
+	originalEntryServerInfo *svrConnInfo,
+	err error) {
+
+	cfg := params.WireGuardParameters.Obfsproxy
+	if err := cfg.Validate(); err != nil {
+		return params, nil, nil, err
+	}
+	if len(params.WireGuardParameters.MultihopExitServer.Hosts) > 0 {
+		// the bridge forwards traffic only to the WireGuard server on the same host
+		return params, nil, nil, fmt.Errorf("obfsproxy is not applicable for WireGuard Multi-Hop connections")
+	}
+
+	host := params.WireGuardParameters.EntryVpnServer.Hosts[0]
+	hostIp := net.ParseIP(host.EndpointIP)
+
+	remotePort := 0
+	if !cfg.IsHttpTransport() {
+		svrs, err := s.ServersList()
+		if err != nil {
+			return params, nil, nil, err
+		}
+		switch cfg.Version {
+		case obfsproxy.OBFS3:
+			remotePort = svrs.Config.Ports.Obfs3.Port
+		case obfsproxy.OBFS4:
+			remotePort = svrs.Config.Ports.Obfs4.Port
+			if len(host.Obfs.Obfs4Key) == 0 {
+				return params, nil, nil, fmt.Errorf("server '%s' does not provide obfs4 parameters for WireGuard", host.Hostname)
+			}
+		}
+	}
+
+	tunnel, err = obfsproxy.CreateUdpTunnel(platform.ObfsproxyStartScript(), cfg, hostIp, remotePort, host.Obfs.Obfs4Key)
+	if err != nil {
+		return params, nil, nil, err
+	}
+	localPort, err := tunnel.Start()
+	if err != nil {
+		return params, nil, nil, err
+	}
+
+	// If pluggable transport stopped unexpectedly - disconnect VPN (the tunnel can not be restored for the current connection)
+	go func() {
+		if err := tunnel.Wait(); err != nil {
+			log.Error("Obfsproxy stopped unexpectedly. Disconnecting VPN...")
+			if err := s.Disconnect(); err != nil {
+				log.Error(err)
+			}
+		}
+	}()
+
+	// We have to return the original information about EntryServer
+	origEntrySvr := &svrConnInfo{
+		IP:        hostIp,
+		Port:      params.WireGuardParameters.Port.Port,
+		PortType:  params.WireGuardParameters.Port.Protocol,
+		Obfsproxy: cfg,
+	}
+
+	// Specify connection parameters to local tunnel
+	updatedParams = params
+	updatedParams.WireGuardParameters.EntryVpnServer.Hosts = append([]api_types.WireGuardServerHostInfo{}, params.WireGuardParameters.EntryVpnServer.Hosts...)
+	updatedParams.WireGuardParameters.EntryVpnServer.Hosts[0].EndpointIP = "127.0.0.1"
+	updatedParams.WireGuardParameters.EntryVpnServer.Hosts[0].EndpointPort = localPort
+	updatedParams.WireGuardParameters.Port.Port = localPort
+
+	return updatedParams, tunnel, origEntrySvr, nil
+}
@@ -0,0 +1,84 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn/openvpn"
+)
+
+// ovpnDetailsProvider - OpenVPN connection object which provides the details obtained from the OpenVPN management interface
+type ovpnDetailsProvider interface {
+	Details() (vpn.OpenVpnDetails, error)
+	SetLogVerbosity(verbosity int) error
+}
+
+func (s *Service) ovpnDetailsProvider() ovpnDetailsProvider {
+	vpnObj := s._vpn
+	if vpnObj == nil || vpnObj.Type() != vpn.OpenVPN {
+		return nil
+	}
+	ovpn, ok := vpnObj.(ovpnDetailsProvider)
+	if !ok {
+		return nil
+	}
+	return ovpn
+}
+
+// OpenVpnDetails returns the details of the current OpenVPN connection: latest state, options pushed by the server and traffic statistics.
+// Returns nil when there is no OpenVPN connection.
+func (s *Service) OpenVpnDetails() *vpn.OpenVpnDetails {
+	ovpn := s.ovpnDetailsProvider()
+	if ovpn == nil {
+		return nil
+	}
+	details, err := ovpn.Details()
+	if err != nil {
+		return nil
+	}
+	return &details
+}
+
+// OpenVpnLogVerbosity returns OpenVPN log verbosity ('verb' option) used for connections (0 - default)
+func (s *Service) OpenVpnLogVerbosity() int {
+	return s._preferences.OpenVpnLogVerbosity
+}
+
+// SetOpenVpnLogVerbosity saves OpenVPN log verbosity ('verb' option) and applies it to the current OpenVPN connection (0 - default)
+func (s *Service) SetOpenVpnLogVerbosity(verbosity int) error {
+	if verbosity < 0 || verbosity > openvpn.MaxLogVerbosity {
+		return fmt.Errorf("OpenVPN log verbosity must be in range 0-%d (0 - default)", openvpn.MaxLogVerbosity)
+	}
+
+	s.updatePreferences(func(p *preferences.Preferences) error {
+		p.OpenVpnLogVerbosity = verbosity
+		return nil
+	})
+
+	if ovpn := s.ovpnDetailsProvider(); ovpn != nil {
+		if err := ovpn.SetLogVerbosity(verbosity); err != nil {
+			return fmt.Errorf("failed to apply OpenVPN log verbosity to the current connection: %w", err)
+		}
+	}
+	return nil
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/swapnilsparsh/devsVPN/daemon/helpers"
 	protocolTypes "github.com/swapnilsparsh/devsVPN/daemon/protocol/types"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/firewall"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/metrics"
 	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
 )
 
@@ -501,6 +502,7 @@ func (p *pingSet) ping_resultNotify(s *Service, retMap map[string]int) {
 	if p._notifyClients && len(retMap) > 0 {
 		p.ping_saveLastResults(retMap)
 		if p == &s._pingServers { // notify only if pinging servers
+			metrics.SetPingLatencies(retMap)
 			s._evtReceiver.OnPingStatus(retMap)
 		}
 	}
@@ -0,0 +1,222 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/netinfo"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/history"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+const (
+	pmtuCheckInterval   = time.Minute * 10
+	pmtuFirstCheckDelay = time.Second * 5 // let the connection settle (DNS, firewall rules) before the first probe
+	// WireGuard overhead on the path to the VPN server: outer IP header (IPv4: 20; IPv6: 40) + UDP header (8) + WireGuard header (32)
+	pmtuWireGuardOverheadIPv4 = 20 + 8 + 32
+	pmtuWireGuardOverheadIPv6 = 40 + 8 + 32
+	pmtuMinMtu                = 1280
+)
+
+// wgMtuController - WireGuard connection object which allows to change the MTU of the active tunnel
+type wgMtuController interface {
+	IsCustomMtu() bool
+	TunnelMTU() (int, error)
+	SetTunnelMTU(mtu int) error
+	DestinationIP() net.IP
+	GatewayLocalIP() net.IP
+	OutsideTunnelFwMark() (int, bool)
+}
+
+// pmtuDiscoverFunc detects the largest IP packet which reaches the host without fragmentation (netinfo.DiscoverPathMtu)
+type pmtuDiscoverFunc func(ip net.IP, minSize, maxSize int, fwMark int) (int, error)
+
+// DiscoveredMtu returns the MTU of the WireGuard interface detected by path MTU discovery (0 - not detected)
+func (s *Service) DiscoveredMtu() int {
+	s._pmtu._mutex.Lock()
+	defer s._pmtu._mutex.Unlock()
+	return s._pmtu._discoveredMtu
+}
+
+// pmtuBackgroundMonitor runs asynchronously as a forked thread (only for WireGuard connections).
+// It probes the path MTU (ICMP probes with 'Don't Fragment' bit) at connect time and periodically afterwards,
+// and adjusts the MTU of the WireGuard interface without reconnection.
+// The MTU detected at connect time (platform default or the value recommended when other VPNs are running) is never exceeded.
+// To stop this thread - send to stopPmtuMonitor chan.
+func (s *Service) pmtuBackgroundMonitor() {
+	if s.IsDaemonStopping() {
+		return
+	}
+
+	s.pmtuRunningMutex.Lock() // to ensure there's only one instance of pmtuBackgroundMonitor
+	defer s.pmtuRunningMutex.Unlock()
+
+	log.Debug("pmtuBackgroundMonitor entered")
+	defer log.Debug("pmtuBackgroundMonitor exited")
+
+	vpnObj := s._vpn
+	if vpnObj == nil || vpnObj.Type() != vpn.WireGuard {
+		return
+	}
+	wg, ok := vpnObj.(wgMtuController)
+	if !ok {
+		return
+	}
+	if wg.IsCustomMtu() {
+		log.Info("Path MTU discovery: skipped (custom MTU is defined)")
+		return
+	}
+
+	maxMtu, err := wg.TunnelMTU()
+	if err != nil {
+		log.Warning(fmt.Sprintf("Path MTU discovery: %v", err))
+		return
+	}
+
+	serverIP := wg.DestinationIP()
+	if serverIP != nil && serverIP.IsLoopback() {
+		serverIP = nil // connected through the local V2Ray proxy: only probes inside the tunnel are applicable
+	}
+
+	defer func() {
+		s._pmtu._mutex.Lock()
+		s._pmtu._discoveredMtu = 0
+		s._pmtu._mutex.Unlock()
+	}()
+
+	nextCheck := time.Now().Add(pmtuFirstCheckDelay)
+	for {
+		select {
+		case <-s.stopPmtuMonitor:
+			log.Debug("pmtuBackgroundMonitor exiting on stop signal")
+			return
+		default: // no message received
+			if s.IsDaemonStopping() {
+				return
+			}
+
+			time.Sleep(time.Second) // sleep 1 second per each loop iteration
+			if time.Now().Before(nextCheck) {
+				continue
+			}
+			nextCheck = time.Now().Add(pmtuCheckInterval)
+
+			if s.IsPaused() || !s._vpnConnectedCallback() {
+				continue
+			}
+			s.pmtuCheck(wg, serverIP, maxMtu)
+		}
+	}
+}
+
+// pmtuCheck detects the path MTU and applies it to the WireGuard interface (if changed)
+func (s *Service) pmtuCheck(wg wgMtuController, serverIP net.IP, maxMtu int) {
+	currentMtu, err := wg.TunnelMTU()
+	if err != nil {
+		log.Warning(fmt.Sprintf("Path MTU discovery: %v", err))
+		return
+	}
+	mtu := currentMtu
+
+	// Probe the path to the VPN server (outside the tunnel): the WireGuard packets are larger by the protocol overhead.
+	// It allows to increase the MTU back when the path conditions improved.
+	if serverIP != nil {
+		if serverMtu, ok := pmtuServerPathMtu(wg, serverIP, maxMtu, netinfo.DiscoverPathMtu); ok {
+			mtu = serverMtu
+		}
+	}
+	if mtu != currentMtu {
+		if err := s.pmtuApply(wg, currentMtu, mtu, "path to the VPN server"); err != nil {
+			log.ErrorFE("Path MTU discovery: %w", err)
+			return
+		}
+	}
+
+	// Verify through the tunnel: the largest packet which reaches the VPN gateway inside the tunnel
+	// (detects the paths which silently drop large packets)
+	if gatewayIP := wg.GatewayLocalIP(); gatewayIP != nil {
+		tunnelMtu, err := netinfo.DiscoverPathMtu(gatewayIP, pmtuMinMtu, mtu, 0)
+		if err != nil {
+			log.Debug(fmt.Sprintf("Path MTU discovery: unable to probe the VPN gateway: %v", err))
+		} else if tunnelMtu < mtu {
+			if err := s.pmtuApply(wg, mtu, tunnelMtu, "probe through the tunnel"); err != nil {
+				log.ErrorFE("Path MTU discovery: %w", err)
+				return
+			}
+			mtu = tunnelMtu
+		}
+	}
+
+	s._pmtu._mutex.Lock()
+	isChanged := s._pmtu._discoveredMtu != mtu
+	s._pmtu._discoveredMtu = mtu
+	s._pmtu._mutex.Unlock()
+
+	if isChanged {
+		s._evtReceiver.OnWireGuardMtuChanged()
+	}
+}
+
+// pmtuServerPathMtu probes the path to the VPN server outside the tunnel and returns the MTU of the WireGuard interface for this path.
+// The probe packets must not go through the tunnel: otherwise the current MTU of the tunnel is detected instead of the path MTU
+// (and the WireGuard overhead is subtracted once again).
+// Returns 'false' when the path can not be probed.
+func pmtuServerPathMtu(wg wgMtuController, serverIP net.IP, maxMtu int, discover pmtuDiscoverFunc) (int, bool) {
+	fwMark, ok := wg.OutsideTunnelFwMark()
+	if !ok {
+		log.Debug("Path MTU discovery: the VPN server is reachable only through the tunnel; probe skipped")
+		return 0, false
+	}
+
+	minSize, maxSize, overhead := pmtuServerProbeRange(serverIP, maxMtu)
+	pathMtu, err := discover(serverIP, minSize, maxSize, fwMark)
+	if err != nil {
+		log.Info(fmt.Sprintf("Path MTU discovery: unable to probe the VPN server: %v", err))
+		return 0, false
+	}
+	return pathMtu - overhead, true
+}
+
+// pmtuServerProbeRange returns the range of IP packet sizes to probe on the path to the VPN server
+// and the WireGuard overhead to subtract from the detected path MTU (to get the MTU of the WireGuard interface)
+func pmtuServerProbeRange(serverIP net.IP, maxMtu int) (minSize, maxSize, overhead int) {
+	overhead = pmtuWireGuardOverheadIPv4
+	if serverIP.To4() == nil {
+		overhead = pmtuWireGuardOverheadIPv6
+	}
+	return pmtuMinMtu + overhead, maxMtu + overhead, overhead
+}
+
+// pmtuApply sets the new MTU on the WireGuard interface
+func (s *Service) pmtuApply(wg wgMtuController, oldMtu, newMtu int, reason string) error {
+	if s.IsPaused() || !s._vpnConnectedCallback() {
+		return fmt.Errorf("VPN is not connected")
+	}
+	if err := wg.SetTunnelMTU(newMtu); err != nil {
+		return err
+	}
+
+	log.With("old_mtu", oldMtu, "new_mtu", newMtu).Info("Path MTU discovery: WireGuard MTU changed (", reason, ")")
+	history.Add(history.EventMtu, fmt.Sprintf("WireGuard MTU changed from %d to %d", oldMtu, newMtu), "reason", reason)
+	return nil
+}
@@ -0,0 +1,145 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestPmtuServerProbeRange(t *testing.T) {
+	tests := []struct {
+		name         string
+		serverIP     string
+		maxMtu       int
+		wantMin      int
+		wantMax      int
+		wantOverhead int
+	}{
+		{"ipv4 default mtu", "203.0.113.1", 1420, 1340, 1480, 60},
+		{"ipv4 reduced mtu", "203.0.113.1", 1380, 1340, 1440, 60},
+		{"ipv4-mapped ipv6", "::ffff:203.0.113.1", 1420, 1340, 1480, 60},
+		{"ipv6 default mtu", "2001:db8::1", 1420, 1360, 1500, 80},
+		{"ipv6 minimal mtu", "2001:db8::1", 1280, 1360, 1360, 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minSize, maxSize, overhead := pmtuServerProbeRange(net.ParseIP(tt.serverIP), tt.maxMtu)
+			if minSize != tt.wantMin || maxSize != tt.wantMax || overhead != tt.wantOverhead {
+				t.Errorf("pmtuServerProbeRange() = (%d, %d, %d), want (%d, %d, %d)",
+					minSize, maxSize, overhead, tt.wantMin, tt.wantMax, tt.wantOverhead)
+			}
+			// the detected path MTU converted back must stay within [pmtuMinMtu - maxMtu]
+			if minSize-overhead != pmtuMinMtu || maxSize-overhead != tt.maxMtu {
+				t.Errorf("tunnel MTU range = [%d - %d], want [%d - %d]", minSize-overhead, maxSize-overhead, pmtuMinMtu, tt.maxMtu)
+			}
+		})
+	}
+}
+
+func TestDiscoveredMtu(t *testing.T) {
+	s := &Service{}
+	if got := s.DiscoveredMtu(); got != 0 {
+		t.Errorf("DiscoveredMtu() = %d, want 0 (not detected)", got)
+	}
+	s._pmtu._discoveredMtu = 1392
+	if got := s.DiscoveredMtu(); got != 1392 {
+		t.Errorf("DiscoveredMtu() = %d, want 1392", got)
+	}
+}
+
+const testFwMark = 51820
+
+// testWgTunnel - WireGuard tunnel on a host with policy routing: in full-tunnel mode all the packets without 'testFwMark'
+// are routed through the tunnel (the same way as the Linux implementation does)
+type testWgTunnel struct {
+	mtu          int
+	isFullTunnel bool
+	canMark      bool // the platform can route the packets to the VPN server outside the tunnel
+	underlayMtu  int  // path MTU between the host and the VPN server outside the tunnel
+	probes       int
+}
+
+func (t *testWgTunnel) IsCustomMtu() bool          { return false }
+func (t *testWgTunnel) TunnelMTU() (int, error)    { return t.mtu, nil }
+func (t *testWgTunnel) SetTunnelMTU(mtu int) error { t.mtu = mtu; return nil }
+func (t *testWgTunnel) DestinationIP() net.IP      { return net.ParseIP("203.0.113.1") }
+func (t *testWgTunnel) GatewayLocalIP() net.IP     { return net.ParseIP("10.0.0.1") }
+func (t *testWgTunnel) OutsideTunnelFwMark() (int, bool) {
+	if !t.canMark {
+		return 0, false
+	}
+	if t.isFullTunnel {
+		return testFwMark, true
+	}
+	return 0, true
+}
+
+// discover simulates the path MTU discovery: the largest packet which fits the route selected for the probe
+func (t *testWgTunnel) discover(ip net.IP, minSize, maxSize int, fwMark int) (int, error) {
+	t.probes++
+	pathMtu := t.underlayMtu
+	if t.isFullTunnel && fwMark != testFwMark {
+		pathMtu = t.mtu // routed through the tunnel: limited by the MTU of the WireGuard interface
+	}
+	if pathMtu < minSize {
+		return 0, fmt.Errorf("no reply (packet size %d)", minSize)
+	}
+	return min(pathMtu, maxSize), nil
+}
+
+func TestPmtuServerPathMtu(t *testing.T) {
+	const maxMtu = 1420
+	tests := []struct {
+		name   string
+		tunnel testWgTunnel
+		want   []int // MTU of the WireGuard interface after each check (0 - probe skipped)
+	}{
+		{"full tunnel", testWgTunnel{mtu: maxMtu, isFullTunnel: true, canMark: true, underlayMtu: 1500}, []int{1420, 1420, 1420}},
+		{"full tunnel: recovers after reduced MTU", testWgTunnel{mtu: 1300, isFullTunnel: true, canMark: true, underlayMtu: 1500}, []int{1420, 1420}},
+		{"full tunnel: small path MTU", testWgTunnel{mtu: maxMtu, isFullTunnel: true, canMark: true, underlayMtu: 1432}, []int{1372, 1372, 1372}},
+		{"split tunnel", testWgTunnel{mtu: 1300, canMark: true, underlayMtu: 1500}, []int{1420, 1420}},
+		{"server reachable only through the tunnel", testWgTunnel{mtu: maxMtu, isFullTunnel: true, underlayMtu: 1500}, []int{0, 0}},
+		{"path below the minimal MTU", testWgTunnel{mtu: maxMtu, isFullTunnel: true, canMark: true, underlayMtu: 1300}, []int{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wg := tt.tunnel
+			for i, want := range tt.want {
+				mtu, ok := pmtuServerPathMtu(&wg, wg.DestinationIP(), maxMtu, wg.discover)
+				if !ok {
+					mtu = 0
+				}
+				if mtu != want {
+					t.Fatalf("check %d: pmtuServerPathMtu() = %d (ok=%v), want %d", i+1, mtu, ok, want)
+				}
+				if ok {
+					wg.SetTunnelMTU(mtu)
+				}
+			}
+			if !tt.tunnel.canMark && wg.probes > 0 {
+				t.Errorf("probe sent through the tunnel (%d probes)", wg.probes)
+			}
+		})
+	}
+}
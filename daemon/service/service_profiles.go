@@ -0,0 +1,121 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+)
+
+// ConnectionProfiles returns the list of named connection profiles
+func (s *Service) ConnectionProfiles() []preferences.ConnectionProfile {
+	return s._preferences.ConnectionProfiles
+}
+
+// ConnectionProfileSave adds (or updates, when 'overwrite' is true) named connection profile
+//
+//	fromLastConnection: true - store the last connection parameters instead of 'params'
+func (s *Service) ConnectionProfileSave(name string, params types.ConnectionParams, fromLastConnection, overwrite bool) error {
+	if err := s.updatePreferences(func(p *preferences.Preferences) error {
+		if fromLastConnection {
+			params = p.LastConnectionParams
+		}
+		if err := params.CheckIsDefined(); err != nil {
+			return fmt.Errorf("connection parameters are not defined: %w", err)
+		}
+		return p.SaveConnectionProfile(name, params, overwrite)
+	}); err != nil {
+		return err
+	}
+	s._evtReceiver.OnPreferencesChanged()
+	return nil
+}
+
+// ConnectionProfileRename renames named connection profile (auto-connect and WiFi settings are updated accordingly)
+func (s *Service) ConnectionProfileRename(name, newName string) error {
+	if err := s.updatePreferences(func(p *preferences.Preferences) error {
+		return p.RenameConnectionProfile(name, newName)
+	}); err != nil {
+		return err
+	}
+	s._evtReceiver.OnPreferencesChanged() // references to the profile (auto-connect, WiFi networks, rules) could be changed
+	return nil
+}
+
+// ConnectionProfileDelete removes named connection profile.
+// Auto-connect and WiFi networks which refer to the profile will use the last connection parameters.
+func (s *Service) ConnectionProfileDelete(name string) error {
+	if err := s.updatePreferences(func(p *preferences.Preferences) error {
+		if refs := p.ConnectionProfileReferences(name); len(refs) > 0 {
+			log.Info(fmt.Sprintf("Connection profile '%s' is removed; it was used by: %s", strings.TrimSpace(name), strings.Join(refs, ", ")))
+		}
+		return p.DeleteConnectionProfile(name)
+	}); err != nil {
+		return err
+	}
+	s._evtReceiver.OnPreferencesChanged() // references to the profile (auto-connect, WiFi networks, rules) could be changed
+	return nil
+}
+
+// ConnectionProfileParams returns connection parameters of named connection profile, ready to connect:
+// the entry server is replaced by the server this device is registered with (see pinRegisteredServer)
+func (s *Service) ConnectionProfileParams(name string) (types.ConnectionParams, error) {
+	prof, err := s._preferences.ConnectionProfile(name)
+	if err != nil {
+		return types.ConnectionParams{}, err
+	}
+	return s.pinRegisteredServer(prof.Params)
+}
+
+// pinRegisteredServer replaces the entry server of the connection parameters by the server this device is registered with.
+// Unlike updateParamsAccordingToMetadata(), the port (when defined) and the DNS settings of the parameters are kept.
+func (s *Service) pinRegisteredServer(params types.ConnectionParams) (types.ConnectionParams, error) {
+	if params.IsCustomServer() {
+		return params, nil // the connection is defined by the custom server configuration
+	}
+	last := s._preferences.LastConnectionParams
+	if len(last.WireGuardParameters.EntryVpnServer.Hosts) <= 0 {
+		return params, fmt.Errorf("error - this device was not yet registered with the privateLINE server, please login first")
+	}
+	params.WireGuardParameters.EntryVpnServer = last.WireGuardParameters.EntryVpnServer
+	if params.WireGuardParameters.Port.Port == 0 {
+		params.WireGuardParameters.Port = last.WireGuardParameters.Port
+	}
+	return params, nil
+}
+
+// autoConnectParams returns connection parameters for automatic connection:
+// parameters of the connection profile (if defined and exists), otherwise - the last connection parameters.
+// In both cases the entry server is the server this device is registered with.
+func (s *Service) autoConnectParams(profileName string) (types.ConnectionParams, error) {
+	prefs := s._preferences
+	if len(profileName) > 0 {
+		if _, err := prefs.ConnectionProfile(profileName); err != nil {
+			log.Warning(fmt.Sprintf("Automatic connection manager: %s; using the last connection parameters", err))
+		} else {
+			log.Info(fmt.Sprintf("Automatic connection manager: using connection profile '%s'", profileName))
+			return s.ConnectionProfileParams(profileName)
+		}
+	}
+	return s.updateParamsAccordingToMetadata(prefs.LastConnectionParams)
+}
@@ -0,0 +1,136 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	apiTypes "github.com/swapnilsparsh/devsVPN/daemon/api/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+// newRegisteredTestService returns service object with preferences of the device registered with the server which has 'hosts'
+// (the last connection parameters use port 51820 and DNS 10.0.0.1)
+func newRegisteredTestService(hosts ...apiTypes.WireGuardServerHostInfo) *Service {
+	s := &Service{_preferences: *preferences.Create()}
+	last := &s._preferences.LastConnectionParams
+	last.VpnType = vpn.WireGuard
+	last.WireGuardParameters.EntryVpnServer.Hosts = hosts
+	last.WireGuardParameters.Port.Port = 51820
+	last.ManualDNS = dns.DnsSettings{DnsServers: []net.IP{net.ParseIP("10.0.0.1")}}
+	return s
+}
+
+func wgHost(hostname, endpointIP string) apiTypes.WireGuardServerHostInfo {
+	return apiTypes.WireGuardServerHostInfo{HostInfoBase: apiTypes.HostInfoBase{Hostname: hostname, EndpointIP: endpointIP}}
+}
+
+func TestPinRegisteredServer(t *testing.T) {
+	registered := []apiTypes.WireGuardServerHostInfo{wgHost("us-tx1.wg.privateline.io", "1.1.1.1"), wgHost("us-tx2.wg.privateline.io", "1.1.1.2")}
+	s := newRegisteredTestService(registered...)
+
+	profileDns := dns.DnsSettings{DnsServers: []net.IP{net.ParseIP("9.9.9.9")}}
+
+	var params types.ConnectionParams
+	params.VpnType = vpn.WireGuard
+	params.ManualDNS = profileDns
+	params.WireGuardParameters.EntryVpnServer.Hosts = []apiTypes.WireGuardServerHostInfo{wgHost("de-fr1.wg.privateline.io", "2.2.2.2")}
+
+	// the port of the profile is kept
+	params.WireGuardParameters.Port.Port = 2049
+	got, err := s.pinRegisteredServer(params)
+	if err != nil {
+		t.Fatalf("pinRegisteredServer() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got.WireGuardParameters.EntryVpnServer.Hosts, registered) {
+		t.Errorf("entry server hosts = %v, want registered hosts %v", got.WireGuardParameters.EntryVpnServer.Hosts, registered)
+	}
+	if got.WireGuardParameters.Port.Port != 2049 {
+		t.Errorf("port = %d, want the profile port 2049", got.WireGuardParameters.Port.Port)
+	}
+	if !reflect.DeepEqual(got.ManualDNS, profileDns) {
+		t.Errorf("DNS = %v, want the profile DNS %v", got.ManualDNS, profileDns)
+	}
+
+	// the port of the last connection is used when the profile does not define it
+	params.WireGuardParameters.Port.Port = 0
+	if got, err = s.pinRegisteredServer(params); err != nil {
+		t.Fatalf("pinRegisteredServer() unexpected error: %v", err)
+	}
+	if got.WireGuardParameters.Port.Port != 51820 {
+		t.Errorf("port = %d, want the last connection port 51820", got.WireGuardParameters.Port.Port)
+	}
+
+	// custom server parameters are not changed
+	custom := params
+	custom.CustomServerID = "my-server"
+	if got, err = s.pinRegisteredServer(custom); err != nil {
+		t.Fatalf("pinRegisteredServer() of custom server: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, custom) {
+		t.Errorf("custom server parameters are changed: %+v", got)
+	}
+
+	// the device is not registered
+	if _, err = newRegisteredTestService().pinRegisteredServer(params); err == nil || !strings.Contains(err.Error(), "not yet registered") {
+		t.Errorf("pinRegisteredServer() of not registered device: error = %v, want 'not yet registered'", err)
+	}
+}
+
+func TestAutoConnectParams(t *testing.T) {
+	registered := []apiTypes.WireGuardServerHostInfo{wgHost("us-tx1.wg.privateline.io", "1.1.1.1")}
+	s := newRegisteredTestService(registered...)
+
+	var profileParams types.ConnectionParams
+	profileParams.VpnType = vpn.WireGuard
+	profileParams.ManualDNS = dns.DnsSettings{DnsServers: []net.IP{net.ParseIP("9.9.9.9")}}
+	profileParams.WireGuardParameters.Port.Port = 2049
+	if err := s._preferences.SaveConnectionProfile("Work", profileParams, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// connection profile
+	got, err := s.autoConnectParams("work")
+	if err != nil {
+		t.Fatalf("autoConnectParams(work) unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got.WireGuardParameters.EntryVpnServer.Hosts, registered) || got.WireGuardParameters.Port.Port != 2049 || !got.ManualDNS.Equal(profileParams.ManualDNS) {
+		t.Errorf("autoConnectParams(work) = hosts %v, port %d, DNS %v; want registered hosts, port 2049 and the profile DNS",
+			got.WireGuardParameters.EntryVpnServer.Hosts, got.WireGuardParameters.Port.Port, got.ManualDNS)
+	}
+
+	// unknown profile and no profile: the last connection parameters
+	for _, name := range []string{"home", ""} {
+		got, err := s.autoConnectParams(name)
+		if err != nil {
+			t.Fatalf("autoConnectParams(%q) unexpected error: %v", name, err)
+		}
+		if !reflect.DeepEqual(got.WireGuardParameters.EntryVpnServer.Hosts, registered) || got.WireGuardParameters.Port.Port != 51820 || !got.ManualDNS.Equal(s._preferences.LastConnectionParams.ManualDNS) {
+			t.Errorf("autoConnectParams(%q) = hosts %v, port %d, DNS %v; want the last connection parameters",
+				name, got.WireGuardParameters.EntryVpnServer.Hosts, got.WireGuardParameters.Port.Port, got.ManualDNS)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	probing "github.com/prometheus-community/pro-bing"
+	apiTypes "github.com/swapnilsparsh/devsVPN/daemon/api/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/history"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn/wireguard"
+)
+
+const (
+	qualityPingCount   = 4
+	qualityPingTimeout = time.Second * 3
+	// the host which we failed over from is not used as a failover target during this time
+	qualityFailedHostBanTime = time.Minute * 10
+)
+
+// wgQualityInfoProvider - WireGuard connection object which provides the connection statistics
+type wgQualityInfoProvider interface {
+	PeerStats() (wireguard.PeerStats, error)
+	GatewayLocalIP() net.IP
+}
+
+// ConnectionQualityParams returns the thresholds of the connection-quality monitor
+func (s *Service) ConnectionQualityParams() preferences.ConnectionQualityParams {
+	return s._preferences.ConnectionQuality
+}
+
+// SetConnectionQualityParams validates and saves the thresholds of the connection-quality monitor (applied on the next check)
+func (s *Service) SetConnectionQualityParams(params preferences.ConnectionQualityParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	return s.updatePreferences(func(p *preferences.Preferences) error {
+		p.ConnectionQuality = params
+		return nil
+	})
+}
+
+// ConnectionQualityStatus returns the latest results of the connection-quality monitor
+func (s *Service) ConnectionQualityStatus() types.ConnectionQualityStatus {
+	s._quality._mutex.Lock()
+	defer s._quality._mutex.Unlock()
+
+	ret := s._quality._status
+	ret.Problems = append([]string{}, s._quality._status.Problems...)
+	return ret
+}
+
+// connectionQualityBackgroundMonitor runs asynchronously as a forked thread (only for WireGuard connections).
+// It tracks the connection quality (handshake age, RTT and packet loss to the VPN gateway, throughput stalls)
+// and fails over to the next-best host of the registered server when the thresholds are crossed.
+// To stop this thread - send to stopConnectionQualityMonitor chan.
+func (s *Service) connectionQualityBackgroundMonitor() {
+	if s.IsDaemonStopping() {
+		return
+	}
+
+	s.connectionQualityRunningMutex.Lock() // to ensure there's only one instance of connectionQualityBackgroundMonitor
+	defer s.connectionQualityRunningMutex.Unlock()
+
+	log.Debug("connectionQualityBackgroundMonitor entered")
+	defer log.Debug("connectionQualityBackgroundMonitor exited")
+
+	vpnObj := s._vpn
+	if vpnObj == nil || vpnObj.Type() != vpn.WireGuard {
+		return
+	}
+	wg, ok := vpnObj.(wgQualityInfoProvider)
+	if !ok {
+		return
+	}
+
+	host := ""
+	if ip := vpnObj.DestinationIP(); ip != nil && !ip.IsLoopback() { // loopback - connected through the local V2Ray proxy
+		host = ip.String()
+	}
+
+	s._quality._mutex.Lock()
+	s._quality._status = types.ConnectionQualityStatus{IsActive: true, Host: host, LastFailover: s._quality._status.LastFailover}
+	s._quality._mutex.Unlock()
+	defer func() {
+		s._quality._mutex.Lock()
+		s._quality._status.IsActive = false
+		s._quality._mutex.Unlock()
+	}()
+
+	var (
+		lastRx, lastTx  int64
+		lastRxChange    = time.Now()
+		gatewayAnswered bool // true - the VPN gateway responded to pings at least once (otherwise RTT and packet loss are not checked)
+		nextCheck       = time.Now().Add(time.Second * time.Duration(s._preferences.ConnectionQuality.CheckIntervalSec))
+	)
+
+	for {
+		select {
+		case <-s.stopConnectionQualityMonitor:
+			log.Debug("connectionQualityBackgroundMonitor exiting on stop signal")
+			return
+		default: // no message received
+			if s.IsDaemonStopping() {
+				return
+			}
+
+			time.Sleep(time.Second) // sleep 1 second per each loop iteration
+			if time.Now().Before(nextCheck) {
+				continue
+			}
+			params := s.Preferences().ConnectionQuality
+			nextCheck = time.Now().Add(time.Second * time.Duration(params.CheckIntervalSec))
+
+			if s.IsPaused() || !s._vpnConnectedCallback() {
+				lastRxChange = time.Now()
+				continue
+			}
+
+			stats, err := wg.PeerStats()
+			if err != nil {
+				log.Warning(fmt.Sprintf("Connection quality: %v", err))
+				continue
+			}
+			now := time.Now()
+			if stats.ReceiveBytes != lastRx || stats.TransmitBytes == lastTx {
+				lastRxChange = now // data received (or nothing sent): not stalled
+			}
+			lastRx, lastTx = stats.ReceiveBytes, stats.TransmitBytes
+
+			st := types.ConnectionQualityStatus{
+				IsActive:      true,
+				Host:          host,
+				LastCheck:     now,
+				StalledSec:    int64(now.Sub(lastRxChange) / time.Second),
+				ReceivedBytes: stats.ReceiveBytes,
+				SentBytes:     stats.TransmitBytes,
+			}
+			if !stats.LastHandshakeTime.IsZero() {
+				st.HandshakeAgeSec = int64(now.Sub(stats.LastHandshakeTime) / time.Second)
+			}
+			var received int
+			st.RttMs, st.PacketLossPercent, received = pingVpnGateway(wg.GatewayLocalIP())
+			gatewayAnswered = gatewayAnswered || received > 0
+
+			st.Problems = qualityProblems(st, params, gatewayAnswered)
+
+			s._quality._mutex.Lock()
+			if len(st.Problems) > 0 {
+				st.BadChecks = s._quality._status.BadChecks + 1
+			}
+			st.LastFailover = s._quality._status.LastFailover
+			s._quality._status = st
+			s._quality._mutex.Unlock()
+
+			if len(st.Problems) == 0 {
+				continue
+			}
+			log.Info(fmt.Sprintf("Connection quality: check failed (%d/%d): %s", st.BadChecks, params.BadChecksToFailover, strings.Join(st.Problems, "; ")))
+
+			if _, isCustomServer := s.activeCustomServer(); isCustomServer {
+				continue // failover to privateLINE servers is not applicable for the user-imported server
+			}
+			if params.FailoverEnabled && st.BadChecks >= params.BadChecksToFailover {
+				if err := s.connectionFailover(host, strings.Join(st.Problems, "; ")); err != nil {
+					log.ErrorFE("Connection quality: failover: %w", err)
+					continue
+				}
+				return // the connection is being re-established; the monitor will be restarted when connected
+			}
+		}
+	}
+}
+
+// qualityProblems returns the list of thresholds crossed by the results of the quality check.
+// RTT and packet loss are checked only when the VPN gateway responded to pings at least once ('gatewayAnswered'):
+// some gateways do not respond to pings at all.
+func qualityProblems(st types.ConnectionQualityStatus, params preferences.ConnectionQualityParams, gatewayAnswered bool) (problems []string) {
+	if params.MaxHandshakeAgeSec > 0 && st.HandshakeAgeSec > int64(params.MaxHandshakeAgeSec) {
+		problems = append(problems, fmt.Sprintf("latest handshake %d seconds ago", st.HandshakeAgeSec))
+	}
+	if gatewayAnswered && params.MaxPacketLossPercent > 0 && st.PacketLossPercent > params.MaxPacketLossPercent {
+		problems = append(problems, fmt.Sprintf("packet loss %d%%", st.PacketLossPercent))
+	}
+	if gatewayAnswered && params.MaxRttMs > 0 && st.RttMs > params.MaxRttMs {
+		problems = append(problems, fmt.Sprintf("RTT %dms", st.RttMs))
+	}
+	if params.StallTimeoutSec > 0 && st.StalledSec > int64(params.StallTimeoutSec) {
+		problems = append(problems, fmt.Sprintf("no data received for %d seconds", st.StalledSec))
+	}
+	return problems
+}
+
+// pingVpnGateway returns average RTT (ms), packet loss (percent) and the number of received replies from the VPN gateway inside the tunnel
+func pingVpnGateway(gatewayIP net.IP) (rttMs, packetLossPercent, received int) {
+	if gatewayIP == nil {
+		return 0, 0, 0
+	}
+	pinger, err := probing.NewPinger(gatewayIP.String())
+	if err != nil {
+		return 0, 0, 0
+	}
+	pinger.SetPrivileged(true)
+	pinger.Count = qualityPingCount
+	pinger.Interval = time.Millisecond * 250
+	pinger.Timeout = qualityPingTimeout
+	if err := pinger.Run(); err != nil {
+		log.Warning(fmt.Sprintf("Connection quality: unable to ping VPN gateway: %v", err))
+		return 0, 0, 0
+	}
+	stat := pinger.Statistics()
+	return int(stat.AvgRtt / time.Millisecond), int(stat.PacketLoss), stat.PacketsRecv
+}
+
+// connectionFailover reconnects to the next-best host of the registered server (according to the servers latency).
+// The device is registered with a single entry server (its peer exists only there), so the same rule as for the 'Connect'
+// request and connection profiles is applied: the entry server is the registered one; only the host is changed.
+func (s *Service) connectionFailover(fromHost, reason string) error {
+	gateway, host, err := s.failoverTarget(fromHost)
+	if err != nil {
+		return err
+	}
+
+	params, err := s.pinRegisteredServer(s._preferences.LastConnectionParams)
+	if err != nil {
+		return err
+	}
+	params.WireGuardParameters.EntryVpnServer.Hosts = []apiTypes.WireGuardServerHostInfo{host}
+
+	const canFixParams bool = true
+	if params, err = s.ValidateConnectionParameters(params, canFixParams); err != nil {
+		return fmt.Errorf("error validating connection parameters: %w", err)
+	}
+
+	info := types.ConnectionFailoverInfo{
+		Time:      time.Now(),
+		Reason:    reason,
+		FromHost:  fromHost,
+		ToHost:    host.EndpointIP,
+		ToGateway: gateway,
+	}
+	s._quality._mutex.Lock()
+	if len(fromHost) > 0 {
+		if s._quality._failedHosts == nil {
+			s._quality._failedHosts = make(map[string]time.Time)
+		}
+		s._quality._failedHosts[fromHost] = info.Time
+	}
+	s._quality._status.LastFailover = &info
+	s._quality._status.BadChecks = 0
+	s._quality._mutex.Unlock()
+
+	log.With("from", fromHost, "to", info.ToHost, "gateway", info.ToGateway).Info("Connection quality: failing over to another host: ", reason)
+	history.Add(history.EventFailover, reason, "from", fromHost, "to", info.ToHost, "gateway", info.ToGateway)
+	s._evtReceiver.OnConnectionFailover(info)
+
+	return s._evtReceiver.RegisterConnectionRequest(params)
+}
+
+// failoverTarget returns the host of the registered WireGuard server with the lowest latency (according to the latest 'PingServers' results)
+// and the gateway ID of the host (informational; empty if not known).
+func (s *Service) failoverTarget(currentHost string) (gateway string, host apiTypes.WireGuardServerHostInfo, err error) {
+	registeredHosts := s._preferences.LastConnectionParams.WireGuardParameters.EntryVpnServer.Hosts
+	if len(registeredHosts) == 0 {
+		return "", host, fmt.Errorf("this device was not yet registered with the privateLINE server")
+	}
+
+	latencies, err := s.PingServers(int(Ping_MaxHostTimeoutFirstPhase/time.Millisecond), vpn.WireGuard, true)
+	if err != nil {
+		log.Info(fmt.Sprintf("Connection quality: servers latency is not known (%v)", err))
+	}
+
+	hostGateways := make(map[string]string) // host name -> gateway ID
+	if servers, err := s.ServersList(); err == nil {
+		for _, sv := range servers.WireguardServers {
+			for _, h := range sv.Hosts {
+				hostGateways[strings.ToLower(h.Hostname)] = sv.Gateway
+			}
+		}
+	}
+
+	candidates := failoverCandidates(registeredHosts, currentHost, s.qualityBannedHosts(), s._preferences.LastConnectionParams.Metadata.FastestGatewaysExcludeList, hostGateways, latencies)
+	if len(candidates) == 0 {
+		return "", host, fmt.Errorf("no other hosts of the registered server available to fail over to")
+	}
+	host = candidates[0]
+	return hostGateways[strings.ToLower(host.Hostname)], host, nil
+}
+
+// qualityBannedHosts returns the hosts which we recently failed over from (they are not used as a failover target)
+func (s *Service) qualityBannedHosts() map[string]struct{} {
+	s._quality._mutex.Lock()
+	defer s._quality._mutex.Unlock()
+
+	banned := make(map[string]struct{}, len(s._quality._failedHosts))
+	for h, t := range s._quality._failedHosts {
+		if time.Since(t) > qualityFailedHostBanTime {
+			delete(s._quality._failedHosts, h)
+			continue
+		}
+		banned[h] = struct{}{}
+	}
+	return banned
+}
+
+// failoverCandidates returns the hosts to fail over to, the best first: hosts with known latency (the fastest first), then hosts with unknown latency.
+// Skipped: the current host, the banned hosts (by endpoint IP) and the hosts of gateways from 'excludedGateways'
+// ('FastestGatewaysExcludeList'; the gateway of a host is taken from 'hostGateways': lowercase host name -> gateway ID).
+func failoverCandidates(hosts []apiTypes.WireGuardServerHostInfo, currentHost string, banned map[string]struct{},
+	excludedGateways []string, hostGateways map[string]string, latencies map[string]int) []apiTypes.WireGuardServerHostInfo {
+
+	// Remove everything after symbol '.': "us-tx.wg.ivpn.net" => "us-tx"
+	normalizeGwId := func(gwId string) string {
+		return strings.ToLower(strings.Split(gwId, ".")[0])
+	}
+	excluded := make(map[string]struct{}, len(excludedGateways))
+	for _, gw := range excludedGateways {
+		excluded[normalizeGwId(gw)] = struct{}{}
+	}
+
+	type candidate struct {
+		host    apiTypes.WireGuardServerHostInfo
+		latency int // -1 - unknown
+	}
+	var candidates []candidate
+	for _, h := range hosts {
+		if _, ok := banned[h.EndpointIP]; ok || h.EndpointIP == currentHost {
+			continue
+		}
+		if gw, ok := hostGateways[strings.ToLower(h.Hostname)]; ok {
+			if _, ok := excluded[normalizeGwId(gw)]; ok {
+				continue
+			}
+		}
+		latency, ok := latencies[h.EndpointIP]
+		if !ok {
+			latency = -1
+		}
+		candidates = append(candidates, candidate{host: h, latency: latency})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		li, lj := candidates[i].latency, candidates[j].latency
+		if li < 0 || lj < 0 {
+			return lj < 0 && li >= 0
+		}
+		return li < lj
+	})
+
+	ret := make([]apiTypes.WireGuardServerHostInfo, 0, len(candidates))
+	for _, c := range candidates {
+		ret = append(ret, c.host)
+	}
+	return ret
+}
@@ -0,0 +1,134 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	apiTypes "github.com/swapnilsparsh/devsVPN/daemon/api/types"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/types"
+)
+
+func TestQualityProblems(t *testing.T) {
+	params := preferences.ConnectionQualityParams{MaxHandshakeAgeSec: 180, MaxRttMs: 500, MaxPacketLossPercent: 50, StallTimeoutSec: 120}
+
+	tests := []struct {
+		name            string
+		st              types.ConnectionQualityStatus
+		params          preferences.ConnectionQualityParams
+		gatewayAnswered bool
+		want            []string
+	}{
+		{"good", types.ConnectionQualityStatus{HandshakeAgeSec: 100, RttMs: 40, PacketLossPercent: 0, StalledSec: 10}, params, true, nil},
+		{"at thresholds", types.ConnectionQualityStatus{HandshakeAgeSec: 180, RttMs: 500, PacketLossPercent: 50, StalledSec: 120}, params, true, nil},
+		{"old handshake", types.ConnectionQualityStatus{HandshakeAgeSec: 181}, params, true, []string{"latest handshake 181 seconds ago"}},
+		{"high packet loss", types.ConnectionQualityStatus{PacketLossPercent: 75}, params, true, []string{"packet loss 75%"}},
+		{"high RTT", types.ConnectionQualityStatus{RttMs: 900}, params, true, []string{"RTT 900ms"}},
+		{"stalled", types.ConnectionQualityStatus{StalledSec: 121}, params, true, []string{"no data received for 121 seconds"}},
+		{"all", types.ConnectionQualityStatus{HandshakeAgeSec: 200, RttMs: 900, PacketLossPercent: 100, StalledSec: 300}, params, true,
+			[]string{"latest handshake 200 seconds ago", "packet loss 100%", "RTT 900ms", "no data received for 300 seconds"}},
+		{"gateway never answered pings", types.ConnectionQualityStatus{RttMs: 900, PacketLossPercent: 100}, params, false, nil},
+		{"checks disabled", types.ConnectionQualityStatus{HandshakeAgeSec: 200, RttMs: 900, PacketLossPercent: 100, StalledSec: 300}, preferences.ConnectionQualityParams{}, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := qualityProblems(tt.st, tt.params, tt.gatewayAnswered); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("qualityProblems() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailoverCandidates(t *testing.T) {
+	h1 := wgHost("us-tx1.wg.privateline.io", "1.1.1.1")
+	h2 := wgHost("us-tx2.wg.privateline.io", "1.1.1.2")
+	h3 := wgHost("us-tx3.wg.privateline.io", "1.1.1.3")
+	h4 := wgHost("us-ca1.wg.privateline.io", "1.1.2.1")
+	hosts := []apiTypes.WireGuardServerHostInfo{h1, h2, h3, h4}
+	hostGateways := map[string]string{
+		"us-tx1.wg.privateline.io": "us-tx.wg.privateline.io",
+		"us-tx2.wg.privateline.io": "us-tx.wg.privateline.io",
+		"us-tx3.wg.privateline.io": "us-tx.wg.privateline.io",
+		"us-ca1.wg.privateline.io": "us-ca.wg.privateline.io",
+	}
+
+	tests := []struct {
+		name      string
+		current   string
+		banned    map[string]struct{}
+		excluded  []string
+		latencies map[string]int
+		want      []apiTypes.WireGuardServerHostInfo
+	}{
+		{"fastest first", "", nil, nil, map[string]int{"1.1.1.1": 90, "1.1.1.2": 30, "1.1.1.3": 60, "1.1.2.1": 10},
+			[]apiTypes.WireGuardServerHostInfo{h4, h2, h3, h1}},
+		{"current host skipped", "1.1.2.1", nil, nil, map[string]int{"1.1.1.1": 90, "1.1.1.2": 30, "1.1.1.3": 60, "1.1.2.1": 10},
+			[]apiTypes.WireGuardServerHostInfo{h2, h3, h1}},
+		{"unknown latency last, in original order", "", nil, nil, map[string]int{"1.1.1.3": 60},
+			[]apiTypes.WireGuardServerHostInfo{h3, h1, h2, h4}},
+		{"latency not known at all", "1.1.1.1", nil, nil, nil,
+			[]apiTypes.WireGuardServerHostInfo{h2, h3, h4}},
+		{"banned hosts skipped", "1.1.1.1", map[string]struct{}{"1.1.1.2": {}, "1.1.2.1": {}}, nil, map[string]int{"1.1.1.2": 10},
+			[]apiTypes.WireGuardServerHostInfo{h3}},
+		{"excluded gateway", "", nil, []string{"US-TX"}, map[string]int{"1.1.1.2": 10},
+			[]apiTypes.WireGuardServerHostInfo{h4}},
+		{"excluded gateway full name", "", nil, []string{"us-ca.wg.privateline.io"}, map[string]int{"1.1.1.2": 10},
+			[]apiTypes.WireGuardServerHostInfo{h2, h1, h3}},
+		{"no candidates", "1.1.2.1", map[string]struct{}{"1.1.1.3": {}}, []string{"us-tx"}, nil,
+			[]apiTypes.WireGuardServerHostInfo{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := failoverCandidates(hosts, tt.current, tt.banned, tt.excluded, hostGateways, tt.latencies)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("failoverCandidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// the gateway of the host is not known: the host is not excluded
+	got := failoverCandidates(hosts, "", nil, []string{"us-tx", "us-ca"}, nil, nil)
+	if !reflect.DeepEqual(got, hosts) {
+		t.Errorf("failoverCandidates() without gateways info = %v, want %v", got, hosts)
+	}
+}
+
+func TestQualityBannedHosts(t *testing.T) {
+	s := newRegisteredTestService()
+	if banned := s.qualityBannedHosts(); len(banned) != 0 {
+		t.Errorf("qualityBannedHosts() = %v, want empty", banned)
+	}
+
+	s._quality._failedHosts = map[string]time.Time{
+		"1.1.1.1": time.Now().Add(-time.Minute),
+		"1.1.1.2": time.Now().Add(-qualityFailedHostBanTime - time.Minute),
+	}
+	if banned, want := s.qualityBannedHosts(), map[string]struct{}{"1.1.1.1": {}}; !reflect.DeepEqual(banned, want) {
+		t.Errorf("qualityBannedHosts() = %v, want %v", banned, want)
+	}
+	if _, ok := s._quality._failedHosts["1.1.1.2"]; ok {
+		t.Error("expired failed host is not removed")
+	}
+}
@@ -0,0 +1,292 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/history"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+)
+
+// scheduleCheckInterval - how often the schedule rules are checked.
+// Info: We can not use 'time.AfterFunc()' because
+// it does not take into account the time when the system was in sleep mode.
+const scheduleCheckInterval = time.Second * 5
+
+// scheduleMaxMissedInterval - transitions missed earlier than this interval (e.g. the system was in sleep mode) are ignored
+const scheduleMaxMissedInterval = time.Hour * 24
+
+var scheduleMonitorMutex sync.Mutex
+
+var (
+	scheduleMutex sync.Mutex
+	// rule name -> start time of the rule window which has been applied already
+	// (the window is applied only once: user is able to change the VPN state manually within the window)
+	scheduleAppliedWindows = map[string]time.Time{}
+	// rule name -> true when the VPN state was changed at the window start (it has to be restored at the window end)
+	scheduleRestoreRequired = map[string]bool{}
+)
+
+// ScheduleRules returns the time-of-day rules
+func (s *Service) ScheduleRules() []preferences.ScheduleRule {
+	return s._preferences.ScheduleRules
+}
+
+// SetScheduleRules validates and saves the time-of-day rules; the rules are applied immediately
+func (s *Service) SetScheduleRules(rules []preferences.ScheduleRule) error {
+	if err := s.updatePreferences(func(p *preferences.Preferences) error {
+		validRules, err := p.ValidateScheduleRules(rules)
+		if err != nil {
+			return err
+		}
+		p.ScheduleRules = validRules
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	s.scheduleMonitorUpdate()
+	s.scheduleApplyActiveWindows()
+	return nil
+}
+
+// ScheduleStatus returns the next scheduled transition (nil - no enabled rules) and the names of rules which windows are active now
+func (s *Service) ScheduleStatus() (next *preferences.ScheduleTransition, activeRules []string) {
+	rules := s._preferences.ScheduleRules
+	now := time.Now()
+	if t, ok := preferences.NextScheduleTransition(rules, now); ok {
+		next = &t
+	}
+	for _, t := range preferences.ActiveScheduleWindows(rules, now) {
+		activeRules = append(activeRules, t.Rule)
+	}
+	return next, activeRules
+}
+
+func hasEnabledScheduleRules(rules []preferences.ScheduleRule) bool {
+	for _, r := range rules {
+		if !r.Disabled {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleRequiredVpnState returns the VPN state required by the active schedule windows
+// (VPN_NoAction - no active windows)
+func (s *Service) scheduleRequiredVpnState() (state actionTypeVpn, rule string) {
+	for _, t := range preferences.ActiveScheduleWindows(s._preferences.ScheduleRules, time.Now()) {
+		switch t.Action {
+		case preferences.ScheduleActionConnect:
+			return VPN_On, t.Rule
+		case preferences.ScheduleActionDisconnect:
+			return VPN_Off, t.Rule
+		}
+	}
+	return VPN_NoAction, ""
+}
+
+// scheduleMonitorUpdate starts (when there are enabled schedule rules) or stops the schedule monitor
+func (s *Service) scheduleMonitorUpdate() {
+	scheduleMonitorMutex.Lock()
+	defer scheduleMonitorMutex.Unlock()
+
+	isRequired := hasEnabledScheduleRules(s._preferences.ScheduleRules) && !s._daemonStopping.Load()
+	if isRequired == (s._scheduleMonitorStop != nil) {
+		return // nothing to change
+	}
+
+	if !isRequired {
+		close(s._scheduleMonitorStop)
+		s._scheduleMonitorStop = nil
+		return
+	}
+
+	stop := make(chan struct{})
+	s._scheduleMonitorStop = stop
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error("PANIC in schedule monitor: ", r)
+				log.Error(string(debug.Stack()))
+			}
+		}()
+
+		log.Info("Schedule monitor started")
+		defer log.Info("Schedule monitor stopped")
+
+		lastCheck := time.Now()
+		s.scheduleCheck(lastCheck, lastCheck)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(scheduleCheckInterval):
+				now := time.Now()
+				s.scheduleCheck(lastCheck, now)
+				lastCheck = now
+			}
+		}
+	}()
+}
+
+// scheduleApplyActiveWindows applies the active rule windows which were not applied yet
+func (s *Service) scheduleApplyActiveWindows() {
+	now := time.Now()
+	s.scheduleCheck(now, now)
+}
+
+// scheduleCheck applies the schedule transitions which happened within the time range ('from' < t <= 'to')
+// and the active rule windows which were not applied yet
+func (s *Service) scheduleCheck(from, to time.Time) {
+	prefs := s.Preferences()
+	if !prefs.Session.IsLoggedIn() || !s._evtReceiver.IsCanDoBackgroundAction() {
+		return
+	}
+
+	scheduleMutex.Lock()
+	defer scheduleMutex.Unlock()
+
+	if to.Sub(from) > scheduleMaxMissedInterval {
+		from = to.Add(-scheduleMaxMissedInterval)
+	}
+
+	rulesByName := make(map[string]preferences.ScheduleRule, len(prefs.ScheduleRules))
+	for _, r := range prefs.ScheduleRules {
+		rulesByName[r.Name] = r
+	}
+	for name := range scheduleAppliedWindows {
+		if _, ok := rulesByName[name]; !ok {
+			delete(scheduleAppliedWindows, name) // the rule was removed
+		}
+	}
+	for name := range scheduleRestoreRequired {
+		if _, ok := rulesByName[name]; !ok {
+			delete(scheduleRestoreRequired, name)
+		}
+	}
+
+	// Window ends and one-shot actions.
+	// When several transitions of the rule were missed (e.g. the system was in sleep mode) - only the last one is applied.
+	lastTransitions := make(map[string]preferences.ScheduleTransition)
+	var order []string
+	for _, t := range preferences.ScheduleTransitions(prefs.ScheduleRules, from, to) {
+		if rulesByName[t.Rule].IsWindow() && !t.WindowEnd {
+			continue // window starts are processed below
+		}
+		if _, ok := lastTransitions[t.Rule]; !ok {
+			order = append(order, t.Rule)
+		}
+		lastTransitions[t.Rule] = t
+	}
+	for _, name := range order {
+		s.applyScheduleTransition(rulesByName[name], lastTransitions[name])
+	}
+
+	// Window starts (including windows which were active when the daemon started or the rules were changed)
+	for _, t := range preferences.ActiveScheduleWindows(prefs.ScheduleRules, to) {
+		if applied, ok := scheduleAppliedWindows[t.Rule]; ok && applied.Equal(t.Time) {
+			continue
+		}
+		scheduleAppliedWindows[t.Rule] = t.Time
+		s.applyScheduleTransition(rulesByName[t.Rule], t)
+	}
+}
+
+// applyScheduleTransition applies the action of the schedule rule.
+// Note: scheduleMutex must be locked.
+func (s *Service) applyScheduleTransition(rule preferences.ScheduleRule, t preferences.ScheduleTransition) {
+	var vpn actionTypeVpn
+	switch t.Action {
+	case preferences.ScheduleActionResume:
+		if !s.IsPaused() {
+			return
+		}
+		log.Info(fmt.Sprintf("Schedule: rule '%s': resuming connection", rule.Name))
+		history.Add(history.EventSchedule, "", "rule", rule.Name, "action", string(t.Action))
+		if err := s.Resume(); err != nil {
+			log.ErrorFE("Schedule: resuming connection: %w", err)
+		}
+		return
+	case preferences.ScheduleActionConnect:
+		vpn = VPN_On
+	case preferences.ScheduleActionDisconnect:
+		vpn = VPN_Off
+	default:
+		return
+	}
+
+	if t.WindowEnd {
+		// restore the VPN state only if it was changed at the window start
+		if !scheduleRestoreRequired[rule.Name] {
+			return
+		}
+		delete(scheduleRestoreRequired, rule.Name)
+		delete(scheduleAppliedWindows, rule.Name)
+		if vpn == VPN_On {
+			vpn = VPN_Off
+		} else {
+			vpn = VPN_On
+		}
+	}
+
+	isConnected := s.ConnectedOrConnecting()
+	if (vpn == VPN_On) == isConnected {
+		return // nothing to change
+	}
+	if rule.IsWindow() && !t.WindowEnd {
+		scheduleRestoreRequired[rule.Name] = true
+	}
+
+	if vpn == VPN_On {
+		log.Info(fmt.Sprintf("Schedule: rule '%s' (%s): connecting VPN", rule.Name, t.Time.Format("Mon 15:04")))
+	} else {
+		log.Info(fmt.Sprintf("Schedule: rule '%s' (%s): disconnecting VPN", rule.Name, t.Time.Format("Mon 15:04")))
+	}
+	history.Add(history.EventSchedule, "", "rule", rule.Name, "action", string(t.Action), "window_end", fmt.Sprint(t.WindowEnd), "vpn", vpn.ToString())
+
+	if vpn == VPN_Off {
+		if err := s.Disconnect(); err != nil {
+			log.ErrorFE("Schedule: disconnecting: %w", err)
+		}
+		return
+	}
+
+	profile := rule.Profile
+	if len(profile) == 0 {
+		profile = s._preferences.AutoconnectProfile
+	}
+	connParams, err := s.autoConnectParams(profile)
+	if err != nil {
+		log.Info("[WARNING] Schedule: failed updating connection parameters: ", err)
+	}
+	const canFixParams bool = true
+	if connParams, err = s.ValidateConnectionParameters(connParams, canFixParams); err != nil {
+		log.ErrorFE("Schedule: error validating connection parameters: %w", err)
+		return
+	}
+	if err := s._evtReceiver.RegisterConnectionRequest(connParams); err != nil {
+		log.ErrorFE("Schedule: connecting: %w", err)
+	}
+}
@@ -0,0 +1,159 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/firewall"
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+)
+
+// SettingsExport returns the portable (exportable) part of the preferences
+func (s *Service) SettingsExport() preferences.SettingsBundle {
+	prefs := s._preferences
+	return prefs.ExportSettings()
+}
+
+// SettingsImport validates the settings bundle and applies it atomically: if the new settings can not be applied - the previous settings are restored.
+// Values defined by the locked configuration file are not changed.
+// If dryRun is 'true' - the settings are not applied, only the list of changes is returned.
+func (s *Service) SettingsImport(bundle preferences.SettingsBundle, dryRun bool) (changes []string, err error) {
+	if err := bundle.Validate(); err != nil {
+		return nil, fmt.Errorf("settings validation failed: %w", err)
+	}
+	if err := s.implIsCanApplyUserPreferences(bundle.UserPrefs); err != nil {
+		return nil, err
+	}
+
+	managedCfg := s.managedConfigLocked()
+	var oldPrefs, newPrefs preferences.Preferences
+	if err := s.updatePreferences(func(p *preferences.Preferences) error {
+		oldPrefs = *p
+		p.ImportSettings(bundle)
+		if managedCfg != nil {
+			managedCfg.Apply(p)
+		}
+
+		var err error
+		if changes, err = preferences.SettingsDiff(&oldPrefs, p); err != nil {
+			return err
+		}
+		if dryRun {
+			*p = oldPrefs // nothing to save
+		}
+		newPrefs = *p
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	log.Info(fmt.Sprintf("Importing settings (changes: %d)", len(changes)))
+	if err := s.applyChangedPreferences(oldPrefs, newPrefs); err != nil {
+		log.Error("Failed to apply imported settings. Restoring previous settings...")
+		s.setPreferences(oldPrefs)
+		if e := s.applyChangedPreferences(newPrefs, oldPrefs); e != nil {
+			log.Error("Failed to restore previous settings: ", e)
+		}
+		s._evtReceiver.OnPreferencesChanged()
+		return nil, fmt.Errorf("failed to apply settings (previous settings restored): %w", err)
+	}
+
+	s._evtReceiver.OnPreferencesChanged()
+	return changes, nil
+}
+
+// applyChangedPreferences applies the changed values to the running functionality (newPrefs must be saved already)
+func (s *Service) applyChangedPreferences(oldPrefs, newPrefs preferences.Preferences) (retErr error) {
+	saveErr := func(err error) {
+		if err != nil {
+			log.Error(err)
+			if retErr == nil {
+				retErr = err
+			}
+		}
+	}
+
+	// firewall
+	isFirewallChanged := false
+	if oldPrefs.IsFwPersistent != newPrefs.IsFwPersistent {
+		saveErr(firewall.SetPersistent(newPrefs.IsFwPersistent))
+		isFirewallChanged = true
+	}
+	if oldPrefs.IsFwAllowLAN != newPrefs.IsFwAllowLAN || oldPrefs.IsFwAllowLANMulticast != newPrefs.IsFwAllowLANMulticast {
+		saveErr(s.applyKillSwitchAllowLAN(nil))
+		isFirewallChanged = true
+	}
+	if oldPrefs.FwUserExceptions != newPrefs.FwUserExceptions {
+		saveErr(firewall.SetUserExceptions(newPrefs.FwUserExceptions, false))
+		isFirewallChanged = true
+	}
+	if oldPrefs.IsFwAllowApiServers != newPrefs.IsFwAllowApiServers {
+		s.updateAPIAddrInFWExceptions()
+		isFirewallChanged = true
+	}
+	if isFirewallChanged {
+		s.onKillSwitchStateChanged(true)
+	}
+
+	// Total Shield
+	if oldPrefs.IsTotalShieldOn != newPrefs.IsTotalShieldOn ||
+		oldPrefs.EnableAppWhitelist != newPrefs.EnableAppWhitelist ||
+		!reflect.DeepEqual(oldPrefs.SplitTunnelApps, newPrefs.SplitTunnelApps) {
+		saveErr(s.splitTunnelling_ApplyConfig(true))
+	}
+	if !reflect.DeepEqual(oldPrefs.SplitTunnelDomains, newPrefs.SplitTunnelDomains) {
+		s.splitTunnelDomains_ApplyConfig()
+	}
+	if !reflect.DeepEqual(oldPrefs.SplitTunnelIncludeSubnets, newPrefs.SplitTunnelIncludeSubnets) ||
+		!reflect.DeepEqual(oldPrefs.SplitTunnelExcludeSubnets, newPrefs.SplitTunnelExcludeSubnets) {
+		s.splitTunnelSubnets_ApplyConfig()
+	}
+
+	// DNS (apply for current connection)
+	oldParams, newParams := oldPrefs.LastConnectionParams, newPrefs.LastConnectionParams
+	if !oldParams.ManualDNS.Equal(newParams.ManualDNS) || !oldParams.Metadata.AntiTracker.Equal(newParams.Metadata.AntiTracker) {
+		_, err := s.SetManualDNS(newParams.ManualDNS, newParams.Metadata.AntiTracker)
+		saveErr(err)
+	}
+
+	// Trusted WiFi
+	if !reflect.DeepEqual(oldPrefs.WiFiControl, newPrefs.WiFiControl) {
+		saveErr(s.autoConnectIfRequired(OnWifiChanged, nil))
+	}
+
+	// Network rules
+	if !reflect.DeepEqual(oldPrefs.NetworkRules, newPrefs.NetworkRules) {
+		s.netRulesMonitorUpdate()
+		saveErr(s.autoConnectIfRequired(OnNetworkChanged, nil))
+	}
+
+	// Schedule rules
+	if !reflect.DeepEqual(oldPrefs.ScheduleRules, newPrefs.ScheduleRules) {
+		s.scheduleMonitorUpdate()
+		s.scheduleApplyActiveWindows()
+	}
+
+	return retErr
+}
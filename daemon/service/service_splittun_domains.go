@@ -0,0 +1,135 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	"github.com/swapnilsparsh/devsVPN/daemon/splittun"
+)
+
+// SplitTunnelling_GetDomains returns per-domain split tunnelling rules with the resolving status
+func (s *Service) SplitTunnelling_GetDomains() (rules []splittun.DomainStatus, funcNotAvailableErr error) {
+	return splittun.GetDomainsStatus(), splittun.GetDomainsFuncNotAvailableError()
+}
+
+// SplitTunnelling_AddDomain adds (or updates) per-domain split tunnelling rule
+//
+//	bypass: false - always route the traffic to the domain through the VPN tunnel; true - send it directly (bypass the VPN tunnel)
+func (s *Service) SplitTunnelling_AddDomain(domain string, bypass bool) error {
+	if err := splittun.GetDomainsFuncNotAvailableError(); err != nil {
+		return err
+	}
+	domain, err := splittun.NormalizeDomain(domain)
+	if err != nil {
+		return err
+	}
+
+	isChanged := false
+	if err := s.updatePreferences(func(p *preferences.Preferences) error {
+		rules := make([]splittun.DomainRule, 0, len(p.SplitTunnelDomains)+1)
+		isUpdated := false
+		for _, r := range p.SplitTunnelDomains {
+			if r.Domain == domain {
+				if r.Bypass == bypass {
+					return nil // nothing changed
+				}
+				r.Bypass = bypass
+				isUpdated = true
+			}
+			rules = append(rules, r)
+		}
+		if !isUpdated {
+			if len(rules) >= splittun.MaxDomainRules {
+				return fmt.Errorf("too many domain rules (maximum %d)", splittun.MaxDomainRules)
+			}
+			rules = append(rules, splittun.DomainRule{Domain: domain, Bypass: bypass})
+		}
+
+		p.SplitTunnelDomains = rules
+		isChanged = true
+		return nil
+	}); err != nil || !isChanged {
+		return err
+	}
+
+	s.splitTunnelDomains_ApplyConfig()
+	s._evtReceiver.OnSplitTunnelStatusChanged()
+	return nil
+}
+
+// SplitTunnelling_RemoveDomain removes per-domain split tunnelling rule
+func (s *Service) SplitTunnelling_RemoveDomain(domain string) error {
+	domain, err := splittun.NormalizeDomain(domain)
+	if err != nil {
+		return err
+	}
+
+	if err := s.updatePreferences(func(p *preferences.Preferences) error {
+		rules := make([]splittun.DomainRule, 0, len(p.SplitTunnelDomains))
+		for _, r := range p.SplitTunnelDomains {
+			if r.Domain != domain {
+				rules = append(rules, r)
+			}
+		}
+		if len(rules) == len(p.SplitTunnelDomains) {
+			return fmt.Errorf("domain rule '%s' not found", domain)
+		}
+		p.SplitTunnelDomains = rules
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	s.splitTunnelDomains_ApplyConfig()
+	s._evtReceiver.OnSplitTunnelStatusChanged()
+	return nil
+}
+
+// splitTunnelDomains_ApplyConfig applies per-domain split tunnelling rules according to the current VPN connection state.
+// The domains are resolved through the DNS server which is in use for the VPN connection.
+func (s *Service) splitTunnelDomains_ApplyConfig() {
+	prefs := s._preferences
+
+	cfg := splittun.DomainsConfig{
+		Rules:          prefs.SplitTunnelDomains,
+		IsVpnConnected: s.ConnectedOrConnecting() && !s.IsPaused(),
+	}
+
+	if cfg.IsVpnConnected && len(cfg.Rules) > 0 {
+		if dnsCfg, err := s.GetActiveDNS(); err != nil {
+			log.Error(fmt.Errorf("per-domain split tunnelling: failed to get active DNS: %w", err))
+		} else if !dnsCfg.IsEmpty() && len(dnsCfg.DnsServers) > 0 {
+			cfg.DnsServer = dnsCfg.DnsServers[0]
+		}
+
+		cfg.TunnelIP = s.GetVpnSessionInfo().VpnLocalIPv4
+		if hosts := prefs.LastConnectionParams.WireGuardParameters.EntryVpnServer.Hosts; len(hosts) > 0 {
+			cfg.Endpoint = net.ParseIP(strings.TrimSpace(hosts[0].EndpointIP))
+		}
+	}
+
+	if err := splittun.ApplyDomainRules(cfg); err != nil {
+		log.Error(fmt.Errorf("failed to apply per-domain split tunnelling rules: %w", err))
+	}
+}
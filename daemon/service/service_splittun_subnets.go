@@ -0,0 +1,112 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/service/preferences"
+	"github.com/swapnilsparsh/devsVPN/daemon/splittun"
+)
+
+// SplitTunnelling_GetSubnets returns per-CIDR split tunnelling rules
+func (s *Service) SplitTunnelling_GetSubnets() (include, exclude []string, funcNotAvailableErr error) {
+	prefs := s._preferences
+	return prefs.SplitTunnelIncludeSubnets, prefs.SplitTunnelExcludeSubnets, splittun.GetSubnetsFuncNotAvailableError()
+}
+
+// SplitTunnelling_SetSubnets sets per-CIDR split tunnelling rules
+//
+//	include: networks to route through the VPN tunnel
+//	exclude: networks to route bypassing the VPN tunnel
+func (s *Service) SplitTunnelling_SetSubnets(include, exclude []string) error {
+	if err := splittun.GetSubnetsFuncNotAvailableError(); err != nil && (len(include) > 0 || len(exclude) > 0) {
+		return err
+	}
+
+	include, exclude, err := splittun.ValidateSubnets(include, exclude, s.splitTunnelSubnets_tunnelAddresses())
+	if err != nil {
+		return err
+	}
+
+	s.updatePreferences(func(p *preferences.Preferences) error {
+		p.SplitTunnelIncludeSubnets = include
+		p.SplitTunnelExcludeSubnets = exclude
+		return nil
+	})
+
+	s.splitTunnelSubnets_ApplyConfig()
+	s._evtReceiver.OnSplitTunnelStatusChanged()
+	return nil
+}
+
+// splitTunnelSubnets_ApplyConfig applies per-CIDR split tunnelling rules according to the current VPN connection state
+func (s *Service) splitTunnelSubnets_ApplyConfig() {
+	prefs := s._preferences
+
+	cfg := splittun.SubnetsConfig{
+		Include:        prefs.SplitTunnelIncludeSubnets,
+		Exclude:        prefs.SplitTunnelExcludeSubnets,
+		IsVpnConnected: s.ConnectedOrConnecting() && !s.IsPaused(),
+	}
+
+	if cfg.IsVpnConnected && (len(cfg.Include) > 0 || len(cfg.Exclude) > 0) {
+		cfg.TunnelIP = s.GetVpnSessionInfo().VpnLocalIPv4
+		if hosts := prefs.LastConnectionParams.WireGuardParameters.EntryVpnServer.Hosts; len(hosts) > 0 {
+			cfg.Endpoint = net.ParseIP(strings.TrimSpace(hosts[0].EndpointIP))
+		}
+	}
+
+	if err := splittun.ApplySubnetRules(cfg); err != nil {
+		log.Error(fmt.Errorf("failed to apply per-CIDR split tunnelling rules: %w", err))
+	}
+}
+
+// splitTunnelSubnets_tunnelAddresses returns addresses of the VPN connection which must not be affected by per-CIDR rules
+func (s *Service) splitTunnelSubnets_tunnelAddresses() (ret splittun.TunnelAddresses) {
+	prefs := s._preferences
+
+	for _, h := range prefs.VpnEntryHostsParsed {
+		if h == nil {
+			continue
+		}
+		if h.VpnEntryHostIP != nil {
+			ret.Endpoints = append(ret.Endpoints, h.VpnEntryHostIP)
+		}
+	}
+
+	if localIP := strings.TrimSpace(prefs.Session.WGLocalIP); len(localIP) > 0 {
+		if ip, _, err := net.ParseCIDR(localIP); err == nil {
+			ret.LocalIPs = append(ret.LocalIPs, ip)
+		} else if ip := net.ParseIP(localIP); ip != nil {
+			ret.LocalIPs = append(ret.LocalIPs, ip)
+		}
+	}
+	sInfo := s.GetVpnSessionInfo()
+	for _, ip := range []net.IP{sInfo.VpnLocalIPv4, sInfo.VpnLocalIPv6} {
+		if ip != nil {
+			ret.LocalIPs = append(ret.LocalIPs, ip)
+		}
+	}
+
+	return ret
+}
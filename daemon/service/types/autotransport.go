@@ -0,0 +1,41 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package types
+
+import "time"
+
+// Results of the transport attempt (automatic transport mode)
+const (
+	AutoTransportAttemptStarted   = "started"
+	AutoTransportAttemptSkipped   = "skipped"   // the ports required for the transport are not accessible
+	AutoTransportAttemptFailed    = "failed"    // the connection attempt failed or timed out
+	AutoTransportAttemptConnected = "connected" // VPN connected using the transport
+)
+
+// AutoTransportAttemptInfo - information about the transport attempt in automatic transport mode
+type AutoTransportAttemptInfo struct {
+	Time      time.Time
+	Network   string // key of the current network (e.g. "ssid:MyWiFi", "gw:aa:bb:cc:dd:ee:ff")
+	Transport string // e.g. "wireguard", "wireguard_v2ray_quic", "openvpn_obfs4"
+	Attempt   int    // index of the transport in the list of candidates (starting from 1)
+	Total     int    // number of candidates
+	Result    string // AutoTransportAttempt... constants
+	Error     string `json:",omitempty"`
+}
@@ -88,6 +88,14 @@ type ConnectionParams struct {
 	FirewallOnDuringConnection  bool
 	CanReconfigureOtherVpnsOnce bool // it's a transient value, only good for one connection request. Always stored as false in preferences.
 
+	// Automatic transport mode (Single-Hop only): try plain WireGuard, WireGuard over V2Ray (QUIC, TCP) and OpenVPN over obfs4
+	// until one of them connects. The transport which worked is remembered for the current network and tried first next time.
+	AutoTransport bool
+
+	// ID of the user-imported server ("bring your own server") to connect to.
+	// When defined - the WireGuard/OpenVPN parameters below are ignored: the imported configuration is in use.
+	CustomServerID string `json:",omitempty"`
+
 	WireGuardParameters struct {
 		// Port in use only for Single-Hop connections
 		Port struct {
@@ -103,7 +111,9 @@ type ConnectionParams struct {
 
 		Mtu int // Set 0 to use default MTU value
 
-		V2RayProxy v2r.V2RayTransportType // V2Ray config
+		V2RayProxy   v2r.V2RayTransportType // V2Ray config
+		V2RayOptions v2r.TransportOptions   // V2Ray WebSocket/gRPC/HTTP2 transport options (SNI, path, REALITY ...)
+		Obfsproxy    obfsproxy.Config       // Pluggable transport which carries WireGuard UDP over obfs4/HTTP stream (ignored when 'V2RayProxy' defined)
 	}
 
 	OpenVpnParameters struct {
@@ -127,8 +137,9 @@ type ConnectionParams struct {
 			Port int
 		}
 
-		Obfs4proxy obfsproxy.Config       // Obfsproxy config (ignored when 'V2RayProxy' defined)
-		V2RayProxy v2r.V2RayTransportType // V2Ray config (this option takes precedence over the 'Obfs4proxy')
+		Obfs4proxy   obfsproxy.Config       // Obfsproxy config (ignored when 'V2RayProxy' defined)
+		V2RayProxy   v2r.V2RayTransportType // V2Ray config (this option takes precedence over the 'Obfs4proxy')
+		V2RayOptions v2r.TransportOptions   // V2Ray WebSocket/gRPC/HTTP2 transport options (SNI, path, REALITY ...)
 	}
 }
 
@@ -139,7 +150,15 @@ func (p ConnectionParams) IsMultiHop() bool {
 	return len(p.WireGuardParameters.MultihopExitServer.Hosts) > 0
 }
 
+// IsCustomServer returns 'true' when the connection is made to the user-imported server
+func (p ConnectionParams) IsCustomServer() bool {
+	return len(p.CustomServerID) > 0
+}
+
 func (p ConnectionParams) CheckIsDefined() error {
+	if p.IsCustomServer() {
+		return nil
+	}
 	if p.VpnType == vpn.WireGuard {
 		if len(p.WireGuardParameters.EntryVpnServer.Hosts) <= 0 {
 			return fmt.Errorf("no hosts defined for WireGuard connection")
@@ -166,6 +185,13 @@ func (p ConnectionParams) V2Ray() v2r.V2RayTransportType {
 	return p.OpenVpnParameters.V2RayProxy
 }
 
+func (p ConnectionParams) V2RayOptions() v2r.TransportOptions {
+	if p.VpnType == vpn.WireGuard {
+		return p.WireGuardParameters.V2RayOptions
+	}
+	return p.OpenVpnParameters.V2RayOptions
+}
+
 // NormalizeHosts - normalize hosts list
 // 1) in case of multiple entry hosts - take random host from the list
 // 2) in case of multiple exit hosts - take random host from the list
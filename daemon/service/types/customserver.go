@@ -0,0 +1,42 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+// CustomServerInfo - information about the user-imported server ("bring your own server").
+// Secrets of the imported configuration (keys, credentials) are not included.
+type CustomServerInfo struct {
+	ID         string
+	Name       string
+	VpnType    vpn.Type
+	Host       string // hostname or IP address of the server (as defined in the configuration)
+	Port       int
+	IsTCP      bool
+	EndpointIP string   `json:",omitempty"` // the latest resolved IP address of the host
+	DnsServers []string `json:",omitempty"` // (WireGuard) 'DNS' of the [Interface] section
+	AllowedIPs string   `json:",omitempty"` // (WireGuard) 'AllowedIPs' of the [Peer] section
+	Imported   time.Time
+	Ignored    []string `json:",omitempty"` // options of the configuration which are not in use (e.g. scripts)
+}
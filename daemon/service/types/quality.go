@@ -0,0 +1,50 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package types
+
+import "time"
+
+// ConnectionQualityStatus - the latest results of the WireGuard connection-quality monitor
+type ConnectionQualityStatus struct {
+	IsActive bool   // true - the monitor is running (WireGuard connected)
+	Host     string // VPN server host (endpoint IP)
+
+	LastCheck         time.Time
+	HandshakeAgeSec   int64
+	RttMs             int
+	PacketLossPercent int
+	StalledSec        int64 // how long data is sent but nothing is received
+	ReceivedBytes     int64
+	SentBytes         int64
+
+	Problems  []string // thresholds crossed on the latest check
+	BadChecks int      // number of consecutive failed checks
+
+	LastFailover *ConnectionFailoverInfo // nil - no failovers since the daemon started
+}
+
+// ConnectionFailoverInfo - information about the automatic server failover
+type ConnectionFailoverInfo struct {
+	Time      time.Time
+	Reason    string
+	FromHost  string // endpoint IP of the previous VPN server host
+	ToHost    string // endpoint IP of the new VPN server host
+	ToGateway string // gateway ID of the new VPN server host
+}
@@ -0,0 +1,471 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux
+// +build linux
+
+package splittun
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/shell"
+)
+
+// Native implementation of App Whitelist for systems with cgroup v2 (unified hierarchy) only.
+// (the net_cls controller of cgroup v1 is not available there, so the external split-tunnel script can not be used)
+//
+// Processes are moved to a dedicated cgroup. Sockets created by these processes are classified by nftables
+// ('socket cgroupv2' match): the packets (and their connections) get the fwmark, which is in use by:
+//   - the firewall: accepts the marked packets (see VPN coexistence chains in firewall_linux_nft.go);
+//   - the policy routing: the marked packets use the separate routing table, which contains the default routes (IPv4 and IPv6)
+//     via the physical default gateway (so, whitelisted apps still have connectivity when Total Shield changes the main routing table).
+
+const (
+	cgroup2Root     = "/sys/fs/cgroup"
+	cgroup2Name     = "privateline-app-whitelist.slice"
+	cgroup2Level    = 1 // depth of our cgroup in the hierarchy (required for 'socket cgroupv2' match)
+	cgroup2Dir      = cgroup2Root + "/" + cgroup2Name
+	cgroup2PidsFile = cgroup2Dir + "/cgroup.procs"
+
+	// fwmark for packets of whitelisted apps.
+	// Must be equal to firewall.PL_CGROUP_ID (the same value is in use as net_cls.classid for cgroup v1)
+	cgroup2FwMark uint32 = 0x70561e1d
+
+	cgroup2RoutingTable = 17   // routing table for packets of whitelisted apps (the same as in split-tunnel script)
+	cgroup2RulePriority = 5170 // priority of the routing policy rule: must be less than the priority of 'main' table rule (32766)
+
+	cgroup2NftTable           = "privateline_app_whitelist"
+	cgroup2NftChainOut        = "output"
+	cgroup2NftChainPrerouting = "prerouting"
+	cgroup2SysctlValidMarks   = "net.ipv4.conf.all.src_valid_mark"
+)
+
+// default apps to be included in App Whitelist (the same list as in split-tunnel script)
+var cgroup2DefaultWhitelistedApps = []string{
+	"/usr/bin/privateline-connect-svc",
+	"/usr/bin/privateline-connect-cli",
+	"/opt/privateline-connect/ui/bin/privateline-connect-ui",
+	"/etc/alternatives/privateline-comms-desktop",
+	"/etc/alternatives/pl-comms-desktop",
+	"/usr/bin/privateline-comms-desktop",
+	"/opt/privateLINE-Comms/privateline-comms-desktop",
+	"/opt/PL-Comms/pl-comms-desktop",
+}
+
+// original cgroups of processes added to our cgroup (map[<PID>]<cgroup path relative to cgroup2Root>)
+// Used to return process back to its original cgroup on RemovePid()
+var (
+	cgroup2OriginalCgroups      = map[int]string{}
+	cgroup2OriginalCgroupsMutex sync.Mutex
+)
+
+// isCgroup2Unified returns true when only cgroup v2 (unified hierarchy) is mounted at /sys/fs/cgroup
+func isCgroup2Unified() bool {
+	var st unix.Statfs_t
+	if err := unix.Statfs(cgroup2Root, &st); err != nil {
+		return false
+	}
+	return st.Type == unix.CGROUP2_SUPER_MAGIC
+}
+
+// cgroup2Test checks if native cgroup v2 App Whitelist implementation can be used
+func cgroup2Test() error {
+	if !isCgroup2Unified() {
+		return fmt.Errorf("cgroup v2 (unified hierarchy) is not mounted at '%s'", cgroup2Root)
+	}
+	if err := os.MkdirAll(cgroup2Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cgroup '%s': %w", cgroup2Dir, err)
+	}
+	if _, err := (&nftables.Conn{}).ListTablesOfFamily(nftables.TableFamilyINet); err != nil {
+		return fmt.Errorf("nftables is not available: %w", err)
+	}
+	return nil
+}
+
+// cgroup2Id returns ID of our cgroup (it is the inode number of the cgroup folder)
+func cgroup2Id() (uint64, error) {
+	info, err := os.Stat(cgroup2Dir)
+	if err != nil {
+		return 0, err
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("unable to get cgroup ID")
+	}
+	return st.Ino, nil
+}
+
+func cgroup2NftObjects() (table *nftables.Table, chainOut *nftables.Chain, chainPrerouting *nftables.Chain) {
+	table = &nftables.Table{Family: nftables.TableFamilyINet, Name: cgroup2NftTable}
+	chainOut = &nftables.Chain{Name: cgroup2NftChainOut, Table: table, Type: nftables.ChainTypeRoute, Hooknum: nftables.ChainHookOutput, Priority: nftables.ChainPriorityMangle}
+	chainPrerouting = &nftables.Chain{Name: cgroup2NftChainPrerouting, Table: table, Type: nftables.ChainTypeFilter, Hooknum: nftables.ChainHookPrerouting, Priority: nftables.ChainPriorityMangle}
+	return table, chainOut, chainPrerouting
+}
+
+// cgroup2IsEnabled returns true when App Whitelist classification rules are active
+func cgroup2IsEnabled() (bool, error) {
+	tables, err := (&nftables.Conn{}).ListTablesOfFamily(nftables.TableFamilyINet)
+	if err != nil {
+		return false, err
+	}
+	for _, t := range tables {
+		if t.Name == cgroup2NftTable {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// cgroup2Enable creates (if not exists) the cgroup, classification rules and policy routing
+// (analog of 'start -inverse' command of the split-tunnel script)
+func cgroup2Enable() error {
+	if err := os.MkdirAll(cgroup2Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cgroup '%s': %w", cgroup2Dir, err)
+	}
+
+	cgroupID, err := cgroup2Id()
+	if err != nil {
+		return fmt.Errorf("failed to get cgroup ID: %w", err)
+	}
+
+	// nftables: mark packets (and connections) of processes from our cgroup
+	conn := &nftables.Conn{}
+	table, chainOut, chainPrerouting := cgroup2NftObjects()
+	conn.DelTable(table) // ensure previous configuration erased
+	conn.Flush()         // ignore error: the table may not exist
+
+	table = conn.AddTable(table)
+	chainOut = conn.AddChain(chainOut)
+	chainPrerouting = conn.AddChain(chainPrerouting)
+
+	mark := binaryutil.NativeEndian.PutUint32(cgroup2FwMark)
+	conn.AddRule(&nftables.Rule{
+		Table: table,
+		Chain: chainOut,
+		Exprs: []expr.Any{
+			// [ socket load cgroupv2 level 1 => reg 1 ]
+			&expr.Socket{Key: expr.SocketKeyCgroupv2, Level: cgroup2Level, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: binaryutil.NativeEndian.PutUint64(cgroupID)},
+			// [ meta mark set; ct mark set ]
+			&expr.Immediate{Register: 1, Data: mark},
+			&expr.Meta{Key: expr.MetaKeyMARK, SourceRegister: true, Register: 1},
+			&expr.Ct{Key: expr.CtKeyMARK, SourceRegister: true, Register: 1},
+			&expr.Counter{},
+		},
+	})
+	// restore the mark for incoming packets of marked connections (required for the reverse path filter)
+	conn.AddRule(&nftables.Rule{
+		Table: table,
+		Chain: chainPrerouting,
+		Exprs: []expr.Any{
+			&expr.Ct{Key: expr.CtKeyMARK, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: mark},
+			&expr.Meta{Key: expr.MetaKeyMARK, SourceRegister: true, Register: 1},
+		},
+	})
+	if err := conn.Flush(); err != nil {
+		return fmt.Errorf("failed to create nftables rules: %w", err)
+	}
+
+	// policy routing
+	if err := cgroup2UpdateRoutes(); err != nil {
+		log.Warning(fmt.Errorf("failed to update App Whitelist routing table: %w", err))
+	}
+	for _, rule := range cgroup2RoutingRules() {
+		netlink.RuleDel(rule) // ensure no duplicates (ignore error: the rule may not exist)
+		if err := netlink.RuleAdd(rule); err != nil {
+			return fmt.Errorf("failed to add routing policy rule (family %d): %w", rule.Family, err)
+		}
+	}
+
+	// the reverse path filter has to take into account the fwmark of incoming packets
+	if _, outErrText, _, _, err := shell.ExecAndGetOutput(log, 1024, "", sysctlPath, "-w", cgroup2SysctlValidMarks+"=1"); err != nil {
+		log.Warning(fmt.Errorf("failed to set '%s': %w (%s)", cgroup2SysctlValidMarks, err, outErrText))
+	}
+
+	cgroup2AddDefaultApps()
+	log.Info("App whitelist enabled (cgroup v2)")
+	return nil
+}
+
+// cgroup2Disable removes classification rules and policy routing. The cgroup keeps only the default apps.
+// (analog of 'stop' command of the split-tunnel script)
+func cgroup2Disable() error {
+	var retErr error
+
+	conn := &nftables.Conn{}
+	table, _, _ := cgroup2NftObjects()
+	if isEnabled, err := cgroup2IsEnabled(); err == nil && isEnabled {
+		conn.DelTable(table)
+		if err := conn.Flush(); err != nil {
+			retErr = fmt.Errorf("failed to remove nftables rules: %w", err)
+		}
+	}
+
+	for _, rule := range cgroup2RoutingRules() {
+		if err := netlink.RuleDel(rule); err != nil && !errors.Is(err, syscall.ENOENT) {
+			log.Warning(fmt.Errorf("failed to remove routing policy rule (family %d): %w", rule.Family, err))
+		}
+	}
+	for _, family := range []int{netlink.FAMILY_V4, netlink.FAMILY_V6} {
+		if routes, err := netlink.RouteListFiltered(family, &netlink.Route{Table: cgroup2RoutingTable}, netlink.RT_FILTER_TABLE); err == nil {
+			for _, r := range routes {
+				netlink.RouteDel(&r)
+			}
+		}
+	}
+
+	cgroup2Reset()
+	if retErr == nil {
+		log.Info("App whitelist disabled (cgroup v2)")
+	}
+	return retErr
+}
+
+// cgroup2Reset restores the cgroup to contain only the default applications
+func cgroup2Reset() error {
+	pids, err := cgroup2Pids()
+	if err != nil {
+		return err
+	}
+	for _, pid := range pids {
+		cgroup2RemovePid(pid)
+	}
+	cgroup2AddDefaultApps()
+	return nil
+}
+
+// cgroup2RoutingRules returns the routing policy rules (IPv4 and IPv6) for the marked packets
+func cgroup2RoutingRules() []*netlink.Rule {
+	var rules []*netlink.Rule
+	for _, family := range []int{netlink.FAMILY_V4, netlink.FAMILY_V6} {
+		rule := netlink.NewRule()
+		rule.Family = family
+		rule.Mark = cgroup2FwMark
+		rule.Table = cgroup2RoutingTable
+		rule.Priority = cgroup2RulePriority
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// cgroup2UpdateRoutes updates the default routes in routing table of App Whitelist.
+// The routes are copies of the physical default routes of the main routing table.
+// The IPv4 default route is required; the IPv6 one is optional (there may be no IPv6 connectivity).
+// Note: When Total Shield is active, the main default route is replaced by the route to VPN endpoint (via the same gateway).
+func cgroup2UpdateRoutes() error {
+	if err := cgroup2UpdateRoute(netlink.FAMILY_V4, AF_INET); err != nil {
+		return err
+	}
+	if err := cgroup2UpdateRoute(netlink.FAMILY_V6, AF_INET6); err != nil {
+		// no IPv6 default gateway: remove the route which can remain from the previous network
+		if routes, e := netlink.RouteListFiltered(netlink.FAMILY_V6, &netlink.Route{Table: cgroup2RoutingTable}, netlink.RT_FILTER_TABLE); e == nil {
+			for _, r := range routes {
+				netlink.RouteDel(&r)
+			}
+		}
+		log.Debug(fmt.Errorf("App Whitelist IPv6 route not updated: %w", err))
+	}
+	return nil
+}
+
+// cgroup2UpdateRoute updates the default route of the IP family in routing table of App Whitelist
+func cgroup2UpdateRoute(family int, afFamily uint16) error {
+	routes, err := netlink.RouteList(nil, family)
+	if err != nil {
+		return fmt.Errorf("netlink.RouteList failed: %w", err)
+	}
+
+	var defRoute *netlink.Route
+	for i, r := range routes {
+		if r.Gw == nil {
+			continue
+		}
+		if r.Dst == nil || (r.Dst.IP.IsUnspecified() && isZeroMask(r.Dst.Mask)) {
+			defRoute = &routes[i]
+			break
+		}
+		if endpoint := lastConfiguredEndpoints[afFamily]; endpoint != nil && r.Dst.IP.Equal(*endpoint) && defRoute == nil {
+			defRoute = &routes[i]
+		}
+	}
+	if defRoute == nil {
+		return fmt.Errorf("default gateway not found")
+	}
+
+	return netlink.RouteReplace(&netlink.Route{
+		Dst:       DefaultRoutesByIpFamily[afFamily],
+		Gw:        defRoute.Gw,
+		LinkIndex: defRoute.LinkIndex,
+		Table:     cgroup2RoutingTable,
+	})
+}
+
+func isZeroMask(m net.IPMask) bool {
+	ones, _ := m.Size()
+	return ones == 0
+}
+
+// cgroup2AddPid moves the process to our cgroup (the children processes will be started in our cgroup too)
+func cgroup2AddPid(pid int) error {
+	if origCgroup, err := cgroup2ProcessCgroup(pid); err == nil {
+		cgroup2SaveOriginalCgroup(pid, origCgroup)
+	}
+	return cgroup2WritePid(cgroup2PidsFile, pid)
+}
+
+// cgroup2RemovePid moves the process back to its original cgroup (or to the root cgroup, if original one is unknown)
+func cgroup2RemovePid(pid int) error {
+	targetDir := cgroup2PopOriginalCgroupDir(cgroup2Root, pid)
+	err := cgroup2WritePid(filepath.Join(targetDir, "cgroup.procs"), pid)
+	if err != nil && targetDir != cgroup2Root {
+		// the original cgroup may not accept processes (e.g. it has children cgroups) - use root cgroup
+		err = cgroup2WritePid(filepath.Join(cgroup2Root, "cgroup.procs"), pid)
+	}
+	return err
+}
+
+// cgroup2SaveOriginalCgroup remembers the original cgroup of the process (the processes which are already in our cgroup are skipped)
+func cgroup2SaveOriginalCgroup(pid int, origCgroup string) {
+	if origCgroup == "/"+cgroup2Name {
+		return
+	}
+	cgroup2OriginalCgroupsMutex.Lock()
+	cgroup2OriginalCgroups[pid] = origCgroup
+	cgroup2OriginalCgroupsMutex.Unlock()
+}
+
+// cgroup2PopOriginalCgroupDir forgets the original cgroup of the process and returns its folder.
+// Returns 'root' when the original cgroup is unknown or does not exist anymore.
+func cgroup2PopOriginalCgroupDir(root string, pid int) string {
+	cgroup2OriginalCgroupsMutex.Lock()
+	origCgroup, ok := cgroup2OriginalCgroups[pid]
+	delete(cgroup2OriginalCgroups, pid)
+	cgroup2OriginalCgroupsMutex.Unlock()
+	if ok {
+		if dir := filepath.Join(root, origCgroup); isDirExists(dir) {
+			return dir
+		}
+	}
+	return root
+}
+
+func cgroup2WritePid(procsFile string, pid int) error {
+	f, err := os.OpenFile(procsFile, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err = f.WriteString(strconv.Itoa(pid)); err != nil {
+		return fmt.Errorf("failed to move PID %d to '%s': %w", pid, procsFile, err)
+	}
+	return nil
+}
+
+// cgroup2ProcessCgroup returns cgroup v2 path of the process (relative to cgroup2Root)
+func cgroup2ProcessCgroup(pid int) (string, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	path, found := parseCgroup2Path(f)
+	if !found {
+		return "", fmt.Errorf("cgroup v2 entry not found for PID %d", pid)
+	}
+	return path, nil
+}
+
+// parseCgroup2Path returns cgroup v2 path from the content of '/proc/<PID>/cgroup' file
+func parseCgroup2Path(r io.Reader) (path string, found bool) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// cgroup v2 entry format: "0::<path>"
+		if path, found := strings.CutPrefix(scanner.Text(), "0::"); found {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// cgroup2Pids returns PIDs of all processes in our cgroup
+func cgroup2Pids() ([]int, error) {
+	data, err := os.ReadFile(cgroup2PidsFile)
+	if err != nil {
+		return nil, err
+	}
+	pids := make([]int, 0)
+	for _, s := range strings.Fields(string(data)) {
+		if pid, err := strconv.Atoi(s); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// cgroup2AddDefaultApps adds already running default apps to our cgroup
+// (processes which command line starts with the path of a default app)
+func cgroup2AddDefaultApps() {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		log.Warning(err)
+		return
+	}
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || pid <= 0 {
+			continue
+		}
+		cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+		if err != nil || len(cmdline) == 0 {
+			continue
+		}
+		for _, app := range cgroup2DefaultWhitelistedApps {
+			if strings.HasPrefix(string(cmdline), app) {
+				if err := cgroup2AddPid(pid); err != nil {
+					log.Warning(fmt.Errorf("failed to add default app '%s' (PID %d) to App Whitelist: %w", app, pid, err))
+				}
+				break
+			}
+		}
+	}
+}
+
+func isDirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
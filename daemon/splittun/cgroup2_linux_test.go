@@ -0,0 +1,89 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package splittun
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCgroup2Path(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantPath  string
+		wantFound bool
+	}{
+		{"unified", "0::/user.slice/user-1000.slice/session-2.scope\n", "/user.slice/user-1000.slice/session-2.scope", true},
+		{"hybrid", "12:net_cls,net_prio:/\n1:name=systemd:/user.slice\n0::/user.slice\n", "/user.slice", true},
+		{"root cgroup", "0::/\n", "/", true},
+		{"cgroup v1 only", "12:net_cls,net_prio:/\n1:name=systemd:/user.slice\n", "", false},
+		{"empty", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, found := parseCgroup2Path(strings.NewReader(tt.content))
+			if path != tt.wantPath || found != tt.wantFound {
+				t.Errorf("parseCgroup2Path() = (%q, %v), want (%q, %v)", path, found, tt.wantPath, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestCgroup2OriginalCgroups(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "user.slice", "app.scope"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		cgroup2OriginalCgroupsMutex.Lock()
+		cgroup2OriginalCgroups = map[int]string{}
+		cgroup2OriginalCgroupsMutex.Unlock()
+	}()
+
+	cgroup2SaveOriginalCgroup(100, "/user.slice/app.scope")
+	cgroup2SaveOriginalCgroup(101, "/"+cgroup2Name)    // already in our cgroup: not saved
+	cgroup2SaveOriginalCgroup(102, "/removed.slice/x") // original cgroup does not exist anymore
+
+	if got, want := cgroup2PopOriginalCgroupDir(root, 100), filepath.Join(root, "user.slice", "app.scope"); got != want {
+		t.Errorf("original cgroup of PID 100 = %q, want %q", got, want)
+	}
+	if got := cgroup2PopOriginalCgroupDir(root, 100); got != root {
+		t.Errorf("original cgroup of PID 100 after removal = %q, want root %q", got, root)
+	}
+	if got := cgroup2PopOriginalCgroupDir(root, 101); got != root {
+		t.Errorf("original cgroup of PID 101 = %q, want root %q", got, root)
+	}
+	if got := cgroup2PopOriginalCgroupDir(root, 102); got != root {
+		t.Errorf("original cgroup of PID 102 = %q, want root %q", got, root)
+	}
+	if got := cgroup2PopOriginalCgroupDir(root, 103); got != root {
+		t.Errorf("original cgroup of unknown PID = %q, want root %q", got, root)
+	}
+
+	cgroup2OriginalCgroupsMutex.Lock()
+	defer cgroup2OriginalCgroupsMutex.Unlock()
+	if len(cgroup2OriginalCgroups) != 0 {
+		t.Errorf("original cgroups are not removed: %v", cgroup2OriginalCgroups)
+	}
+}
@@ -0,0 +1,535 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package splittun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Per-domain split tunnelling.
+//
+// The domain names from the rules are resolved through the DNS server which is currently in use for the VPN connection
+// (privateLINE DNS, AntiTracker or the manually configured DNS). The resolved IPv4 addresses are:
+//   - routed through the VPN tunnel ('tunnel' rules) or through the default gateway of the physical interface ('bypass' rules);
+//   - passed to the firewall (nft sets with timeouts), so the traffic to them is allowed even when Total Shield is on.
+//
+// Each resolved address is kept until its DNS TTL expires; the domains are re-resolved before the shortest TTL expires.
+// If the same address belongs to both 'tunnel' and 'bypass' domains - the 'tunnel' rule wins.
+//
+// Subdomains of wildcard rules ('*.example.com') can not be enumerated through DNS, so they are not resolved in advance.
+// Instead, the DNS responses received from the DNS server in use (over the VPN tunnel or from a local resolver)
+// are monitored: the IPv4 addresses from the answers to queries which match the suffix of the wildcard rule are handled
+// the same way as the addresses of the regular rules (until their DNS TTL expires).
+
+const (
+	domainMinTTL        = 30 * time.Second
+	domainMaxTTL        = time.Hour
+	domainDefaultTTL    = 5 * time.Minute // used when the TTL is unknown (resolved by the system resolver)
+	domainRetryInterval = 30 * time.Second
+	domainQueryTimeout  = 3 * time.Second
+
+	// MaxDomainRules - the maximum number of per-domain rules
+	MaxDomainRules = 256
+)
+
+// DomainRule - per-domain split tunnelling rule
+type DomainRule struct {
+	Domain string // fully qualified domain name; '*.example.com' - any subdomain of 'example.com' (detected from DNS responses)
+	Bypass bool   // false - always route the traffic through the VPN tunnel; true - send the traffic directly (bypass the VPN tunnel)
+}
+
+// DomainStatus - resolving status of the per-domain rule
+type DomainStatus struct {
+	DomainRule
+	IPs      []net.IP
+	Resolved time.Time // time of the last successful resolving
+	Expires  time.Time // time when the resolved addresses expire (DNS TTL)
+	Error    string    // last resolving error (if any)
+}
+
+// DomainsConfig - configuration for per-domain split tunnelling
+type DomainsConfig struct {
+	Rules          []DomainRule
+	IsVpnConnected bool
+	DnsServer      net.IP // DNS server to resolve the domains (nil - use the system resolver)
+	TunnelIP       net.IP // local IPv4 address of the VPN interface
+	Endpoint       net.IP // VPN server endpoint (to detect the default gateway of the physical interface)
+}
+
+// FuncDomainAddrsFirewallNotify - callback to update the firewall with the resolved addresses (map[<IP>]<expiration time>)
+type FuncDomainAddrsFirewallNotify func(tunnelAddrs, bypassAddrs map[string]time.Time) error
+
+var (
+	domainsMutex       sync.Mutex
+	domainsConfig      DomainsConfig
+	domainsStatus      = map[string]*DomainStatus{} // key - normalized domain name
+	domainsAddrs       = map[string]domainAddr{}    // key - IP address string
+	domainsTimer       *time.Timer
+	domainsFwNotify    FuncDomainAddrsFirewallNotify
+	domainsApplyCalled uint64 // incremented on each ApplyDomainRules() call; used to drop results of outdated resolving
+	domainsMonitorStop func() // stops monitoring DNS responses for wildcard rules; nil - not running
+)
+
+type domainAddr struct {
+	IP      net.IP
+	Bypass  bool
+	Domain  string // the rule which the address belongs to
+	Expires time.Time
+}
+
+// SetDomainsFirewallNotifier sets the callback to update the firewall with the addresses resolved for per-domain rules
+func SetDomainsFirewallNotifier(f FuncDomainAddrsFirewallNotify) {
+	domainsMutex.Lock()
+	defer domainsMutex.Unlock()
+	domainsFwNotify = f
+}
+
+// GetDomainsFuncNotAvailableError returns non-nil error if per-domain split tunnelling is not available
+func GetDomainsFuncNotAvailableError() error {
+	return implDomainsFuncNotAvailableError()
+}
+
+// NormalizeDomain validates the domain name of the rule and returns it in lowercase without trailing dot.
+// The wildcard is allowed only as the first label ('*.example.com').
+func NormalizeDomain(domain string) (string, error) {
+	d := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if isWildcardDomain(d) && !strings.Contains(d[2:], "*") {
+		base, err := NormalizeDomain(d[2:])
+		if err != nil {
+			return "", fmt.Errorf("invalid wildcard domain '%s': %w", domain, err)
+		}
+		return "*." + base, nil
+	}
+	if strings.Contains(d, "*") {
+		return "", fmt.Errorf("wildcard domain '%s' is not supported: the wildcard is allowed only as the first label (e.g. '*.example.com')", domain)
+	}
+	if len(d) == 0 {
+		return "", fmt.Errorf("domain name is empty")
+	}
+	if len(d) > 253 {
+		return "", fmt.Errorf("domain name '%s' is too long", domain)
+	}
+	if net.ParseIP(d) != nil {
+		return "", fmt.Errorf("'%s' is an IP address, expected a domain name", domain)
+	}
+	labels := strings.Split(d, ".")
+	if len(labels) < 2 {
+		return "", fmt.Errorf("'%s' is not a fully qualified domain name", domain)
+	}
+	for _, l := range labels {
+		if len(l) == 0 || len(l) > 63 || strings.HasPrefix(l, "-") || strings.HasSuffix(l, "-") {
+			return "", fmt.Errorf("invalid domain name '%s'", domain)
+		}
+		for _, c := range l {
+			if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '-' && c != '_' {
+				return "", fmt.Errorf("invalid domain name '%s'", domain)
+			}
+		}
+	}
+	return d, nil
+}
+
+func isWildcardDomain(domain string) bool {
+	return strings.HasPrefix(domain, "*.")
+}
+
+// domainMatchesRule returns 'true' if the domain name (normalized) belongs to the rule.
+// Wildcard rule '*.example.com' matches any subdomain of 'example.com' (but not 'example.com' itself).
+func domainMatchesRule(ruleDomain, domain string) bool {
+	if isWildcardDomain(ruleDomain) {
+		return strings.HasSuffix(domain, ruleDomain[1:])
+	}
+	return domain == ruleDomain
+}
+
+// ApplyDomainRules applies per-domain split tunnelling configuration.
+// Resolving is performed in background; when VPN is not connected - all routes and firewall entries are removed.
+func ApplyDomainRules(cfg DomainsConfig) error {
+	domainsMutex.Lock()
+	defer domainsMutex.Unlock()
+
+	domainsConfig = cfg
+	domainsApplyCalled++
+
+	// forget status of removed rules
+	actual := map[string]struct{}{}
+	for _, r := range cfg.Rules {
+		actual[r.Domain] = struct{}{}
+	}
+	for d := range domainsStatus {
+		if _, ok := actual[d]; !ok {
+			delete(domainsStatus, d)
+		}
+	}
+
+	if domainsTimer != nil {
+		domainsTimer.Stop()
+		domainsTimer = nil
+	}
+	if domainsMonitorStop != nil {
+		domainsMonitorStop()
+		domainsMonitorStop = nil
+	}
+
+	if !cfg.IsVpnConnected || len(cfg.Rules) == 0 || implDomainsFuncNotAvailableError() != nil {
+		for _, s := range domainsStatus {
+			s.IPs, s.Expires = nil, time.Time{}
+		}
+		domainsAddrs = map[string]domainAddr{}
+		return domainsApplyAddrs()
+	}
+
+	domainsStartMonitor(cfg)
+	go domainsRefresh(domainsApplyCalled)
+	return nil
+}
+
+// domainsStartMonitor starts monitoring DNS responses if there are wildcard rules. Must be called under domainsMutex.
+func domainsStartMonitor(cfg DomainsConfig) {
+	var wildcardRules []DomainRule
+	for _, r := range cfg.Rules {
+		if isWildcardDomain(r.Domain) {
+			wildcardRules = append(wildcardRules, r)
+		}
+	}
+	if len(wildcardRules) == 0 {
+		return
+	}
+
+	stop, err := implStartDnsResponsesMonitor(cfg, onDnsResponse)
+	if err != nil {
+		log.Error(fmt.Errorf("failed to start monitoring DNS responses (wildcard domain rules are not applied): %w", err))
+		for _, r := range wildcardRules {
+			domainsGetStatus(r).Error = err.Error()
+		}
+		return
+	}
+	domainsMonitorStop = stop
+}
+
+// onDnsResponse processes the DNS response captured by the DNS responses monitor:
+// the addresses from the answer are added to the wildcard rules which match the queried domain name
+func onDnsResponse(packet []byte) {
+	var resp dnsmessage.Message
+	if err := resp.Unpack(packet); err != nil || !resp.Response || resp.RCode != dnsmessage.RCodeSuccess || len(resp.Questions) != 1 {
+		return
+	}
+	name := strings.TrimSuffix(strings.ToLower(resp.Questions[0].Name.String()), ".")
+	ips, ttl := answerIPv4Addrs(resp)
+	if len(ips) == 0 {
+		return
+	}
+
+	domainsMutex.Lock()
+	defer domainsMutex.Unlock()
+
+	if domainsMonitorStop == nil {
+		return // the monitor is stopped
+	}
+
+	now := time.Now()
+	expires := now.Add(min(max(ttl, domainMinTTL), domainMaxTTL))
+	isChanged := false
+	for _, r := range domainsConfig.Rules {
+		if !isWildcardDomain(r.Domain) || !domainMatchesRule(r.Domain, name) {
+			continue
+		}
+		if domainsAddAddrs(r, ips, expires) {
+			isChanged = true
+		}
+		s := domainsGetStatus(r)
+		s.Error = ""
+		s.Resolved = now
+		if s.Expires.Before(expires) {
+			s.Expires = expires
+		}
+		s.IPs = domainsRuleAddrs(r.Domain)
+	}
+
+	if isChanged {
+		if err := domainsApplyAddrs(); err != nil {
+			log.Error(fmt.Errorf("failed to apply per-domain split tunnelling: %w", err))
+		}
+	}
+}
+
+// domainsGetStatus returns the status object of the rule (creates it if not exists). Must be called under domainsMutex.
+func domainsGetStatus(r DomainRule) *DomainStatus {
+	s, ok := domainsStatus[r.Domain]
+	if !ok {
+		s = &DomainStatus{}
+		domainsStatus[r.Domain] = s
+	}
+	s.DomainRule = r
+	return s
+}
+
+// domainsAddAddrs adds the addresses of the rule (or prolongs their expiration time).
+// Returns 'true' if the routes and the firewall have to be updated. Must be called under domainsMutex.
+func domainsAddAddrs(r DomainRule, ips []net.IP, expires time.Time) (isChanged bool) {
+	for _, ip := range ips {
+		key := ip.String()
+		a, exists := domainsAddrs[key]
+		if !exists || a.Domain == r.Domain || (a.Bypass && !r.Bypass) || domainsStatus[a.Domain] == nil {
+			if !exists || a.Bypass != r.Bypass {
+				isChanged = true
+			}
+			a = domainAddr{IP: ip, Bypass: r.Bypass, Domain: r.Domain, Expires: a.Expires} // 'tunnel' rule wins
+		}
+		if a.Expires.Before(expires) {
+			if a.Expires.Add(domainMinTTL).Before(expires) {
+				isChanged = true // the firewall entries have to be prolonged
+			}
+			a.Expires = expires
+		}
+		domainsAddrs[key] = a
+	}
+	return isChanged
+}
+
+// domainsRuleAddrs returns the current addresses of the rule (sorted). Must be called under domainsMutex.
+func domainsRuleAddrs(domain string) []net.IP {
+	var ips []net.IP
+	for _, a := range domainsAddrs {
+		if a.Domain == domain {
+			ips = append(ips, a.IP)
+		}
+	}
+	sort.Slice(ips, func(i, j int) bool { return ips[i].String() < ips[j].String() })
+	return ips
+}
+
+// GetDomainsStatus returns status of per-domain rules (in order of the rules)
+func GetDomainsStatus() []DomainStatus {
+	domainsMutex.Lock()
+	defer domainsMutex.Unlock()
+
+	ret := make([]DomainStatus, 0, len(domainsConfig.Rules))
+	for _, r := range domainsConfig.Rules {
+		if s, ok := domainsStatus[r.Domain]; ok {
+			st := *s
+			st.DomainRule = r
+			ret = append(ret, st)
+		} else {
+			ret = append(ret, DomainStatus{DomainRule: r})
+		}
+	}
+	return ret
+}
+
+// domainsRefresh resolves all domains, updates routes and firewall and schedules the next refresh
+func domainsRefresh(applyId uint64) {
+	domainsMutex.Lock()
+	cfg := domainsConfig
+	domainsMutex.Unlock()
+
+	type result struct {
+		ips []net.IP
+		ttl time.Duration
+		err error
+	}
+	results := make(map[string]result, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		if isWildcardDomain(r.Domain) {
+			continue // addresses are detected from DNS responses (see onDnsResponse)
+		}
+		ips, ttl, err := resolveDomain(r.Domain, cfg.DnsServer)
+		results[r.Domain] = result{ips, ttl, err}
+	}
+
+	domainsMutex.Lock()
+	defer domainsMutex.Unlock()
+
+	if applyId != domainsApplyCalled {
+		return // configuration changed while resolving; newer refresh is already scheduled
+	}
+
+	now := time.Now()
+	nextRefresh := domainMaxTTL
+	for _, r := range cfg.Rules {
+		s := domainsGetStatus(r)
+		if isWildcardDomain(r.Domain) {
+			continue
+		}
+
+		res := results[r.Domain]
+		if res.err != nil {
+			s.Error = res.err.Error()
+			log.Warning(fmt.Sprintf("failed to resolve '%s': %s", r.Domain, res.err))
+			nextRefresh = min(nextRefresh, domainRetryInterval)
+			continue
+		}
+
+		ttl := min(max(res.ttl, domainMinTTL), domainMaxTTL)
+		s.Error = ""
+		s.IPs = res.ips
+		s.Resolved = now
+		s.Expires = now.Add(ttl)
+		domainsAddAddrs(r, res.ips, s.Expires)
+		// re-resolve a bit before the TTL expires
+		nextRefresh = min(nextRefresh, max(ttl-ttl/10, domainMinTTL))
+	}
+
+	// remove addresses with expired TTL and addresses of removed rules
+	for key, a := range domainsAddrs {
+		if !a.Expires.After(now) || domainsStatus[a.Domain] == nil {
+			delete(domainsAddrs, key)
+		} else if isWildcardDomain(a.Domain) {
+			// addresses of wildcard rules are not re-resolved: remove them when expired
+			nextRefresh = min(nextRefresh, max(a.Expires.Sub(now), domainMinTTL))
+		}
+	}
+	for _, r := range cfg.Rules {
+		if isWildcardDomain(r.Domain) {
+			domainsStatus[r.Domain].IPs = domainsRuleAddrs(r.Domain)
+		}
+	}
+
+	if err := domainsApplyAddrs(); err != nil {
+		log.Error(fmt.Errorf("failed to apply per-domain split tunnelling: %w", err))
+		nextRefresh = min(nextRefresh, domainRetryInterval)
+	}
+
+	domainsTimer = time.AfterFunc(nextRefresh, func() { domainsRefresh(applyId) })
+}
+
+// domainsApplyAddrs applies current addresses to routes and firewall. Must be called under domainsMutex.
+func domainsApplyAddrs() error {
+	tunnelAddrs := map[string]time.Time{}
+	bypassAddrs := map[string]time.Time{}
+	for key, a := range domainsAddrs {
+		if a.Bypass {
+			bypassAddrs[key] = a.Expires
+		} else {
+			tunnelAddrs[key] = a.Expires
+		}
+	}
+
+	var retErr error
+	if domainsFwNotify != nil {
+		if err := domainsFwNotify(tunnelAddrs, bypassAddrs); err != nil {
+			retErr = fmt.Errorf("failed to update firewall: %w", err)
+		}
+	}
+	if err := implApplyDomainRoutes(domainsConfig, tunnelAddrs, bypassAddrs); err != nil {
+		retErr = errors.Join(retErr, fmt.Errorf("failed to update routes: %w", err))
+	}
+	return retErr
+}
+
+// resolveDomain resolves IPv4 addresses of the domain.
+// When the DNS server is defined - it is queried directly (to know the TTL), otherwise the system resolver is used.
+func resolveDomain(domain string, dnsServer net.IP) (ips []net.IP, ttl time.Duration, err error) {
+	if dnsServer == nil || dnsServer.IsUnspecified() {
+		ctx, cancel := context.WithTimeout(context.Background(), domainQueryTimeout)
+		defer cancel()
+		ips, err = net.DefaultResolver.LookupIP(ctx, "ip4", domain)
+		return ips, domainDefaultTTL, err
+	}
+
+	name, err := dnsmessage.NewName(domain + ".")
+	if err != nil {
+		return nil, 0, err
+	}
+	id := uint16(rand.Intn(0x10000))
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}},
+	}
+	packet, err := query.Pack()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	conn, err := net.DialTimeout("udp", net.JoinHostPort(dnsServer.String(), "53"), domainQueryTimeout)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(domainQueryTimeout))
+
+	if _, err = conn.Write(packet); err != nil {
+		return nil, 0, err
+	}
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var resp dnsmessage.Message
+	if err = resp.Unpack(buf[:n]); err != nil {
+		return nil, 0, err
+	}
+	if resp.ID != id {
+		return nil, 0, fmt.Errorf("unexpected DNS response ID")
+	}
+	if resp.RCode != dnsmessage.RCodeSuccess {
+		return nil, 0, fmt.Errorf("DNS server %s responded: %s", dnsServer, resp.RCode)
+	}
+
+	if ips, ttl = answerIPv4Addrs(resp); len(ips) == 0 {
+		return nil, 0, fmt.Errorf("no IPv4 addresses")
+	}
+	return ips, ttl, nil
+}
+
+// parseUdpPacket returns the source address and the payload of IPv4 UDP packet with the source port 53 (DNS response)
+func parseUdpPacket(packet []byte) (src net.IP, payload []byte, ok bool) {
+	if len(packet) < 20 || packet[0]>>4 != 4 || packet[9] != 17 { // IPv4, UDP
+		return nil, nil, false
+	}
+	ihl := int(packet[0]&0x0f) * 4
+	if ihl < 20 || len(packet) < ihl+8 {
+		return nil, nil, false
+	}
+	udp := packet[ihl:]
+	udpLen := int(udp[4])<<8 | int(udp[5])
+	if int(udp[0])<<8|int(udp[1]) != 53 || udpLen < 8 || udpLen > len(udp) {
+		return nil, nil, false
+	}
+	return net.IPv4(packet[12], packet[13], packet[14], packet[15]).To4(), udp[8:udpLen], true
+}
+
+// answerIPv4Addrs returns IPv4 addresses from the answer of DNS response (sorted) and the minimal TTL of the answer records.
+// CNAME chain is followed by the DNS server: all A records from the answer belong to the queried domain.
+func answerIPv4Addrs(resp dnsmessage.Message) (ips []net.IP, ttl time.Duration) {
+	var minTTL uint32
+	for _, a := range resp.Answers {
+		if minTTL == 0 || a.Header.TTL < minTTL {
+			minTTL = a.Header.TTL
+		}
+		if r, ok := a.Body.(*dnsmessage.AResource); ok {
+			ips = append(ips, net.IPv4(r.A[0], r.A[1], r.A[2], r.A[3]).To4())
+		}
+	}
+	sort.Slice(ips, func(i, j int) bool { return ips[i].String() < ips[j].String() })
+	return ips, time.Duration(minTTL) * time.Second
+}
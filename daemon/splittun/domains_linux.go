@@ -0,0 +1,192 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux
+// +build linux
+
+package splittun
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/net/bpf"
+	"golang.org/x/sys/unix"
+)
+
+// routing protocol identifier of the routes added for per-domain rules (allows to find and remove them after daemon restart)
+const domainRoutesProtocol netlink.RouteProtocol = 0x70
+
+// routes added for per-domain rules (map[<destination IP>]<route>). Protected by domainsMutex.
+var domainRoutes = map[string]*netlink.Route{}
+
+func implDomainsFuncNotAvailableError() error {
+	return nil
+}
+
+// removeStaleDomainRoutes removes routes for per-domain rules which could remain after the daemon crash
+func removeStaleDomainRoutes() {
+	removeRoutesByProtocol(domainRoutesProtocol)
+}
+
+func implApplyDomainRoutes(cfg DomainsConfig, tunnelAddrs, bypassAddrs map[string]time.Time) error {
+	var retErr error
+
+	wanted := map[string]*netlink.Route{}
+	if !cfg.IsVpnConnected {
+		clearPeerExtraAllowedIPs(peerAllowedIPsSourceDomains)
+	} else if len(tunnelAddrs) > 0 || hasPeerExtraAllowedIPs(peerAllowedIPsSourceDomains) {
+		if linkIndex, err := linkIndexByAddr(cfg.TunnelIP); err != nil {
+			retErr = errors.Join(retErr, fmt.Errorf("VPN interface not found: %w", err))
+		} else {
+			var peerNets []net.IPNet
+			for ip := range tunnelAddrs {
+				wanted[ip] = &netlink.Route{Dst: hostIPNet(ip), LinkIndex: linkIndex, Src: cfg.TunnelIP.To4(), Protocol: domainRoutesProtocol}
+				peerNets = append(peerNets, *hostIPNet(ip))
+			}
+			if err := setPeerExtraAllowedIPs(linkIndex, peerAllowedIPsSourceDomains, peerNets); err != nil {
+				retErr = errors.Join(retErr, err)
+			}
+		}
+	}
+	if len(bypassAddrs) > 0 {
+		if gw, err := physicalGateway(cfg.Endpoint); err != nil {
+			retErr = errors.Join(retErr, fmt.Errorf("default gateway not found: %w", err))
+		} else {
+			for ip := range bypassAddrs {
+				if _, ok := wanted[ip]; !ok {
+					wanted[ip] = &netlink.Route{Dst: hostIPNet(ip), LinkIndex: gw.LinkIndex, Gw: gw.Gw, Protocol: domainRoutesProtocol}
+				}
+			}
+		}
+	}
+
+	if err := syncRoutes(domainRoutes, wanted); err != nil {
+		retErr = errors.Join(retErr, err)
+	}
+
+	return retErr
+}
+
+// implStartDnsResponsesMonitor captures the DNS responses (UDP, source port 53) received on the VPN interface from the DNS server in use
+// and on the loopback interface from local resolvers (e.g. systemd-resolved or dnscrypt-proxy); their payload is passed to 'onResponse'.
+// Note: DNS over TCP is not monitored.
+func implStartDnsResponsesMonitor(cfg DomainsConfig, onResponse func(packet []byte)) (stop func(), err error) {
+	tunIndex, err := linkIndexByAddr(cfg.TunnelIP)
+	if err != nil {
+		return nil, fmt.Errorf("VPN interface not found: %w", err)
+	}
+	lo, err := netlink.LinkByName("lo")
+	if err != nil {
+		return nil, fmt.Errorf("loopback interface not found: %w", err)
+	}
+	loIndex := lo.Attrs().Index
+
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_DGRAM|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, int(htons(unix.ETH_P_IP)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open packet socket: %w", err)
+	}
+	if err := attachDnsResponsesFilter(fd); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to attach socket filter: %w", err)
+	}
+
+	file := os.NewFile(uintptr(fd), "dns-responses")
+	rawConn, err := file.SyscallConn()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	go func() {
+		buf := make([]byte, 1<<16)
+		for {
+			var (
+				n       int
+				from    unix.Sockaddr
+				recvErr error
+			)
+			if err := rawConn.Read(func(fd uintptr) bool {
+				n, from, recvErr = unix.Recvfrom(int(fd), buf, 0)
+				return recvErr != unix.EAGAIN
+			}); err != nil {
+				return // the socket is closed
+			}
+			if recvErr != nil {
+				continue
+			}
+
+			ll, ok := from.(*unix.SockaddrLinklayer)
+			if !ok || ll.Pkttype == unix.PACKET_OUTGOING {
+				continue
+			}
+			src, payload, ok := parseUdpPacket(buf[:n])
+			if !ok {
+				continue
+			}
+			switch int(ll.Ifindex) {
+			case tunIndex:
+				if cfg.DnsServer != nil && !cfg.DnsServer.IsUnspecified() && !cfg.DnsServer.Equal(src) {
+					continue
+				}
+			case loIndex:
+				if !src.IsLoopback() {
+					continue
+				}
+			default:
+				continue
+			}
+			onResponse(payload)
+		}
+	}()
+
+	return func() { file.Close() }, nil
+}
+
+// attachDnsResponsesFilter attaches the socket filter which passes only IPv4 UDP packets (not fragments) with the source port 53
+func attachDnsResponsesFilter(fd int) error {
+	prog, err := bpf.Assemble([]bpf.Instruction{
+		bpf.LoadAbsolute{Off: 9, Size: 1}, // IP protocol
+		bpf.JumpIf{Cond: bpf.JumpNotEqual, Val: unix.IPPROTO_UDP, SkipTrue: 6},
+		bpf.LoadAbsolute{Off: 6, Size: 2}, // fragment offset
+		bpf.JumpIf{Cond: bpf.JumpBitsSet, Val: 0x1fff, SkipTrue: 4},
+		bpf.LoadMemShift{Off: 0},          // X = IP header length
+		bpf.LoadIndirect{Off: 0, Size: 2}, // UDP source port
+		bpf.JumpIf{Cond: bpf.JumpNotEqual, Val: 53, SkipTrue: 1},
+		bpf.RetConstant{Val: 0xffff},
+		bpf.RetConstant{Val: 0},
+	})
+	if err != nil {
+		return err
+	}
+
+	filter := make([]unix.SockFilter, len(prog))
+	for i, ins := range prog {
+		filter[i] = unix.SockFilter{Code: ins.Op, Jt: ins.Jt, Jf: ins.Jf, K: ins.K}
+	}
+	return unix.SetsockoptSockFprog(fd, unix.SOL_SOCKET, unix.SO_ATTACH_FILTER, &unix.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]})
+}
+
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
@@ -0,0 +1,197 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package splittun
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestNormalizeDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  string
+		want    string
+		wantErr string
+	}{
+		{"simple", "example.com", "example.com", ""},
+		{"uppercase and spaces", "  WWW.Example.COM ", "www.example.com", ""},
+		{"trailing dot", "example.com.", "example.com", ""},
+		{"underscore and hyphen", "_srv.my-host.example.com", "_srv.my-host.example.com", ""},
+		{"empty", "", "", "empty"},
+		{"only dot", ".", "", "empty"},
+		{"wildcard", " *.Example.COM. ", "*.example.com", ""},
+		{"wildcard in the middle", "a.*.example.com", "", "not supported"},
+		{"double wildcard", "*.*.example.com", "", "not supported"},
+		{"wildcard without dot", "*example.com", "", "not supported"},
+		{"wildcard top-level domain", "*.com", "", "not a fully qualified"},
+		{"wildcard invalid base", "*.a b.example.com", "", "invalid wildcard domain"},
+		{"ipv4 address", "10.0.0.1", "", "IP address"},
+		{"ipv6 address", "fd00::1", "", "IP address"},
+		{"single label", "localhost", "", "not a fully qualified"},
+		{"empty label", "a..example.com", "", "invalid domain name"},
+		{"leading hyphen", "-a.example.com", "", "invalid domain name"},
+		{"trailing hyphen", "a-.example.com", "", "invalid domain name"},
+		{"invalid character", "a b.example.com", "", "invalid domain name"},
+		{"label too long", strings.Repeat("a", 64) + ".com", "", "invalid domain name"},
+		{"name too long", strings.Repeat(strings.Repeat("a", 63)+".", 4) + "com", "", "too long"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeDomain(tt.domain)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NormalizeDomain(%q) error = %v, want error containing %q", tt.domain, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeDomain(%q) unexpected error: %v", tt.domain, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeDomain(%q) = %q, want %q", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDomainMatchesRule(t *testing.T) {
+	tests := []struct {
+		rule, domain string
+		want         bool
+	}{
+		{"example.com", "example.com", true},
+		{"example.com", "www.example.com", false},
+		{"*.corp.example.com", "app.corp.example.com", true},
+		{"*.corp.example.com", "a.b.corp.example.com", true},
+		{"*.corp.example.com", "corp.example.com", false},
+		{"*.corp.example.com", "evilcorp.example.com", false},
+		{"*.corp.example.com", "corp.example.com.evil.net", false},
+	}
+	for _, tt := range tests {
+		if got := domainMatchesRule(tt.rule, tt.domain); got != tt.want {
+			t.Errorf("domainMatchesRule(%q, %q) = %v, want %v", tt.rule, tt.domain, got, tt.want)
+		}
+	}
+}
+
+func TestParseUdpPacket(t *testing.T) {
+	payload := []byte{1, 2, 3, 4}
+	packet := func(ihl int, proto byte, srcPort, udpLen int) []byte {
+		p := make([]byte, ihl*4+8+len(payload))
+		p[0] = 0x40 | byte(ihl)
+		p[9] = proto
+		copy(p[12:16], []byte{10, 0, 0, 1})
+		udp := p[ihl*4:]
+		udp[0], udp[1] = byte(srcPort>>8), byte(srcPort)
+		udp[4], udp[5] = byte(udpLen>>8), byte(udpLen)
+		copy(udp[8:], payload)
+		return p
+	}
+
+	tests := []struct {
+		name   string
+		packet []byte
+		wantOk bool
+	}{
+		{"DNS response", packet(5, 17, 53, 12), true},
+		{"IP options", packet(6, 17, 53, 12), true},
+		{"not UDP", packet(5, 6, 53, 12), false},
+		{"not DNS", packet(5, 17, 5353, 12), false},
+		{"bad UDP length", packet(5, 17, 53, 100), false},
+		{"truncated", packet(5, 17, 53, 12)[:24], false},
+		{"IPv6", append([]byte{0x60}, packet(5, 17, 53, 12)[1:]...), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, got, ok := parseUdpPacket(tt.packet)
+			if ok != tt.wantOk {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && (!src.Equal(net.IPv4(10, 0, 0, 1)) || !reflect.DeepEqual(got, payload)) {
+				t.Errorf("src = %v, payload = %v", src, got)
+			}
+		})
+	}
+}
+
+func TestAnswerIPv4Addrs(t *testing.T) {
+	name := dnsmessage.MustNewName("app.corp.example.com.")
+	target := dnsmessage.MustNewName("cdn.example.net.")
+	resp := dnsmessage.Message{
+		Header:    dnsmessage.Header{Response: true},
+		Questions: []dnsmessage.Question{{Name: name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}},
+		Answers: []dnsmessage.Resource{
+			{Header: dnsmessage.ResourceHeader{Name: name, Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET, TTL: 300}, Body: &dnsmessage.CNAMEResource{CNAME: target}},
+			{Header: dnsmessage.ResourceHeader{Name: target, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: 60}, Body: &dnsmessage.AResource{A: [4]byte{192, 0, 2, 20}}},
+			{Header: dnsmessage.ResourceHeader{Name: target, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: 120}, Body: &dnsmessage.AResource{A: [4]byte{192, 0, 2, 10}}},
+		},
+	}
+
+	ips, ttl := answerIPv4Addrs(resp)
+	want := []net.IP{net.IPv4(192, 0, 2, 10).To4(), net.IPv4(192, 0, 2, 20).To4()}
+	if !reflect.DeepEqual(ips, want) || ttl != 60*time.Second {
+		t.Errorf("answerIPv4Addrs() = %v, %v; want %v, 60s", ips, ttl, want)
+	}
+}
+
+func TestDomainsAddAddrs(t *testing.T) {
+	defer func() {
+		domainsStatus, domainsAddrs = map[string]*DomainStatus{}, map[string]domainAddr{}
+	}()
+
+	wildcard := DomainRule{Domain: "*.corp.example.com"}
+	bypass := DomainRule{Domain: "streaming.example.net", Bypass: true}
+	domainsStatus = map[string]*DomainStatus{wildcard.Domain: {}, bypass.Domain: {}}
+	domainsAddrs = map[string]domainAddr{}
+
+	now := time.Now()
+	ip1, ip2 := net.IPv4(192, 0, 2, 1).To4(), net.IPv4(192, 0, 2, 2).To4()
+
+	if !domainsAddAddrs(bypass, []net.IP{ip1}, now.Add(time.Minute)) {
+		t.Error("new address: changes expected")
+	}
+	if domainsAddAddrs(bypass, []net.IP{ip1}, now.Add(time.Minute+time.Second)) {
+		t.Error("slightly prolonged address: no changes expected")
+	}
+	if !domainsAddAddrs(bypass, []net.IP{ip1}, now.Add(time.Hour)) {
+		t.Error("prolonged address: changes expected (firewall entries have to be prolonged)")
+	}
+
+	// 'tunnel' rule wins
+	if !domainsAddAddrs(wildcard, []net.IP{ip2, ip1}, now.Add(time.Minute)) {
+		t.Error("addresses of the wildcard rule: changes expected")
+	}
+	if a := domainsAddrs[ip1.String()]; a.Bypass || a.Domain != wildcard.Domain || !a.Expires.Equal(now.Add(time.Hour)) {
+		t.Errorf("address shared with 'bypass' rule: %+v", a)
+	}
+	if got := domainsRuleAddrs(wildcard.Domain); !reflect.DeepEqual(got, []net.IP{ip1, ip2}) {
+		t.Errorf("domainsRuleAddrs() = %v", got)
+	}
+	if got := domainsRuleAddrs(bypass.Domain); len(got) != 0 {
+		t.Errorf("domainsRuleAddrs() of 'bypass' rule = %v, want empty", got)
+	}
+}
@@ -0,0 +1,109 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux
+// +build linux
+
+package splittun
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+
+	"github.com/vishvananda/netlink"
+)
+
+// removeRoutesByProtocol removes all IPv4 routes marked with the routing protocol identifier
+// (used to clean up routes which could remain after the daemon crash)
+func removeRoutesByProtocol(protocol netlink.RouteProtocol) {
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_V4, &netlink.Route{Protocol: protocol}, netlink.RT_FILTER_PROTOCOL)
+	if err != nil {
+		log.Warning(fmt.Errorf("failed to list routes: %w", err))
+		return
+	}
+	for _, r := range routes {
+		if err := netlink.RouteDel(&r); err != nil {
+			log.Warning(fmt.Errorf("failed to remove stale route %s: %w", r.Dst, err))
+		}
+	}
+}
+
+// syncRoutes brings the system routes in line with 'wanted': removes installed routes which are not wanted anymore (or changed)
+// and adds the missing ones. The 'installed' map is updated accordingly.
+func syncRoutes(installed, wanted map[string]*netlink.Route) (retErr error) {
+	for dst, r := range installed {
+		if w, ok := wanted[dst]; ok && w.LinkIndex == r.LinkIndex && w.Gw.Equal(r.Gw) {
+			continue
+		}
+		if err := netlink.RouteDel(r); err != nil && !errors.Is(err, syscall.ESRCH) {
+			retErr = errors.Join(retErr, fmt.Errorf("failed to remove route %s: %w", dst, err))
+		}
+		delete(installed, dst)
+	}
+	for dst, w := range wanted {
+		if _, ok := installed[dst]; ok {
+			continue
+		}
+		if err := netlink.RouteReplace(w); err != nil {
+			retErr = errors.Join(retErr, fmt.Errorf("failed to add route %s: %w", dst, err))
+			continue
+		}
+		installed[dst] = w
+	}
+	return retErr
+}
+
+func hostIPNet(ip string) *net.IPNet {
+	return &net.IPNet{IP: net.ParseIP(ip).To4(), Mask: net.CIDRMask(32, 32)}
+}
+
+// linkIndexByAddr returns index of the network interface which has the IP address assigned
+func linkIndexByAddr(ip net.IP) (int, error) {
+	if ip == nil {
+		return 0, fmt.Errorf("local IP address of the VPN interface is not defined")
+	}
+	addrs, err := netlink.AddrList(nil, netlink.FAMILY_V4)
+	if err != nil {
+		return 0, err
+	}
+	for _, a := range addrs {
+		if a.IP.Equal(ip) {
+			return a.LinkIndex, nil
+		}
+	}
+	return 0, fmt.Errorf("no interface with address %s", ip)
+}
+
+// physicalGateway returns the route to the VPN server endpoint: it goes through the default gateway of the physical interface
+// (also when Total Shield is on - the default route is replaced by the route to the endpoint)
+func physicalGateway(endpoint net.IP) (netlink.Route, error) {
+	if endpoint == nil {
+		return netlink.Route{}, fmt.Errorf("VPN endpoint is not defined")
+	}
+	routes, err := netlink.RouteGet(endpoint)
+	if err != nil {
+		return netlink.Route{}, err
+	}
+	if len(routes) == 0 {
+		return netlink.Route{}, fmt.Errorf("no route to %s", endpoint)
+	}
+	return routes[0], nil
+}
@@ -24,6 +24,8 @@ package splittun
 
 import (
 	"fmt"
+	"net"
+	"time"
 )
 
 var (
@@ -57,3 +59,23 @@ func implRemovePid(pid int) error {
 func implGetRunningApps() ([]RunningApp, error) {
 	return nil, notImplementedError
 }
+
+func implDomainsFuncNotAvailableError() error {
+	return fmt.Errorf("per-domain Split-Tunnelling is not implemented for this platform")
+}
+
+func implApplyDomainRoutes(cfg DomainsConfig, tunnelAddrs, bypassAddrs map[string]time.Time) error {
+	return nil
+}
+
+func implStartDnsResponsesMonitor(cfg DomainsConfig, onResponse func(packet []byte)) (stop func(), err error) {
+	return nil, implDomainsFuncNotAvailableError()
+}
+
+func implSubnetsFuncNotAvailableError() error {
+	return fmt.Errorf("per-CIDR Split-Tunnelling is not implemented for this platform")
+}
+
+func implApplySubnetRoutes(cfg SubnetsConfig, include, exclude []net.IPNet) error {
+	return nil
+}
@@ -53,6 +53,9 @@ var (
 	sysctlPath        string = "/sbin/sysctl"
 	fullTunnelEnabled bool   = false
 
+	// true - when the native cgroup v2 implementation is in use (see cgroup2_linux.go); false - cgroup v1 (net_cls) via the split-tunnel script
+	isCgroup2 bool
+
 	// map from INET type (IPv4 or IPv6) to default routes (all zeroes)
 	DefaultRoutesByIpFamily = map[uint16]*net.IPNet{}
 )
@@ -73,6 +76,42 @@ func implInitialize() error {
 		return funcNotAvailableError
 	}
 
+	isCgroup2 = isCgroup2Unified()
+	if isCgroup2 {
+		// cgroup v1 'net_cls' controller is not available on systems with the unified hierarchy - use native implementation
+		log.Info("cgroup v2 (unified hierarchy) detected: using native App Whitelist implementation")
+		if err := cgroup2Test(); err != nil {
+			funcNotAvailableError = err
+		}
+	} else if err := initScript(); err != nil {
+		return err
+	}
+
+	removeStaleDomainRoutes()
+	removeStaleSubnetRoutes()
+
+	// gotta initialize DefaultRoutesByIpFamily before calling implApplyConfig()
+	if _, defaultRouteIPv4IPNet, err := net.ParseCIDR(defaultRouteIPv4); err != nil {
+		return log.ErrorE(fmt.Errorf("error net.ParseCIDR(%s): %w", defaultRouteIPv4, err), 0)
+	} else {
+		DefaultRoutesByIpFamily[AF_INET] = defaultRouteIPv4IPNet
+	}
+	if _, defaultRouteIPv6IPNet, err := net.ParseCIDR(defaultRouteIPv6); err != nil {
+		return log.ErrorE(fmt.Errorf("error net.ParseCIDR(%s): %w", defaultRouteIPv6, err), 0)
+	} else {
+		DefaultRoutesByIpFamily[AF_INET6] = defaultRouteIPv6IPNet
+	}
+
+	if err := implApplyConfig(true, true, true, false, false, ConfigAddresses{}, []string{}); err != nil {
+		log.Error(fmt.Errorf("error implApplyConfig() on startup: %w", err))
+	}
+
+	return funcNotAvailableError
+}
+
+// initScript checks if the split-tunnel script (cgroup v1 'net_cls') functionality is accessible.
+// Returns error only if the script is not defined.
+func initScript() error {
 	stScriptPath = platform.SplitTunScript()
 	if len(stScriptPath) <= 0 {
 		funcNotAvailableError = fmt.Errorf("App Whitelist script is not defined")
@@ -100,22 +139,6 @@ func implInitialize() error {
 		funcNotAvailableError = err
 	}
 
-	// gotta initialize DefaultRoutesByIpFamily before calling implApplyConfig()
-	if _, defaultRouteIPv4IPNet, err := net.ParseCIDR(defaultRouteIPv4); err != nil {
-		return log.ErrorE(fmt.Errorf("error net.ParseCIDR(%s): %w", defaultRouteIPv4, err), 0)
-	} else {
-		DefaultRoutesByIpFamily[AF_INET] = defaultRouteIPv4IPNet
-	}
-	if _, defaultRouteIPv6IPNet, err := net.ParseCIDR(defaultRouteIPv6); err != nil {
-		return log.ErrorE(fmt.Errorf("error net.ParseCIDR(%s): %w", defaultRouteIPv6, err), 0)
-	} else {
-		DefaultRoutesByIpFamily[AF_INET6] = defaultRouteIPv6IPNet
-	}
-
-	if err := implApplyConfig(true, true, true, false, false, ConfigAddresses{}, []string{}); err != nil {
-		log.Error(fmt.Errorf("error implApplyConfig() on startup: %w", err))
-	}
-
 	// Register network change detector - we're using net_change_detector_linux now
 	/*//
 	// The OS is erasing routing rules for ST each time when main network interface disappears
@@ -146,7 +169,7 @@ func implInitialize() error {
 		}()
 	}*/
 
-	return funcNotAvailableError
+	return nil // the result of the test is saved to funcNotAvailableError
 }
 
 func implFuncNotAvailableError() (generalStError, inversedStError error) {
@@ -156,6 +179,9 @@ func implFuncNotAvailableError() (generalStError, inversedStError error) {
 func implReset() error {
 	log.Info("Restoring App Whitelist to contain only the default applications")
 
+	if isCgroup2 {
+		return cgroup2Reset()
+	}
 	return shell.Exec(nil, stScriptPath, "reset")
 }
 
@@ -297,7 +323,11 @@ func implAddPid(pid int, commandToExecute string) error {
 	// 	return fmt.Errorf("the Split Tunnel is disabled")
 	// }
 
-	err = shell.Exec(nil, stScriptPath, "addpid", strconv.Itoa(pid))
+	if isCgroup2 {
+		err = cgroup2AddPid(pid)
+	} else {
+		err = shell.Exec(nil, stScriptPath, "addpid", strconv.Itoa(pid))
+	}
 	if err == nil {
 		_addedRootProcesses[pid] = commandToExecute
 	}
@@ -341,7 +371,12 @@ func implRemovePid(pid int) error {
 	// remove all required pids
 	for pidToRemove := range pids {
 		log.Info(fmt.Sprintf("Removing PID:%d", pidToRemove))
-		err := shell.Exec(nil, stScriptPath, "removepid", strconv.Itoa(pidToRemove))
+		var err error
+		if isCgroup2 {
+			err = cgroup2RemovePid(pidToRemove)
+		} else {
+			err = shell.Exec(nil, stScriptPath, "removepid", strconv.Itoa(pidToRemove))
+		}
 		if err != nil && retErr == nil {
 			retErr = err
 		}
@@ -364,7 +399,11 @@ func implGetRunningApps() (allProcesses []RunningApp, err error) {
 	// https://man7.org/linux/man-pages/man5/proc.5.html
 
 	// read all PIDs which are active in ST environment
-	bytes, err := os.ReadFile(stPidsFile)
+	pidsFile := stPidsFile
+	if isCgroup2 {
+		pidsFile = cgroup2PidsFile
+	}
+	bytes, err := os.ReadFile(pidsFile)
 	if err != nil {
 		return nil, err
 	}
@@ -514,6 +553,10 @@ func implGetRunningApps() (allProcesses []RunningApp, err error) {
 }
 
 func appWhitelistEnabled() (bool, error) {
+	if isCgroup2 {
+		return cgroup2IsEnabled()
+	}
+
 	retCode, err := shell.ExecGetExitCode(nil, stScriptPath, "appWhitelistEnabled")
 	switch retCode {
 	case 0:
@@ -578,9 +621,29 @@ func enableDisableAppWhitelist(isEnable bool) error {
 		return fmt.Errorf("error checking app whitelist status: %w", err)
 	}
 	if appWhitelistEnabled == isEnable {
+		if isEnable && isCgroup2 {
+			// default gateway could be changed (e.g. after reconnecting WiFi)
+			if err := cgroup2UpdateRoutes(); err != nil {
+				log.Warning(fmt.Errorf("failed to update App Whitelist routing table: %w", err))
+			}
+		}
 		return nil // already enabled or disabled
 	}
 
+	if isCgroup2 {
+		if isEnable {
+			if err := cgroup2Enable(); err != nil {
+				cgroup2Disable()
+				return fmt.Errorf("failed to enable app whitelist: %w", err)
+			}
+			return nil
+		}
+		if err := cgroup2Disable(); err != nil {
+			return fmt.Errorf("failed to disable app whitelist: %w", err)
+		}
+		return nil
+	}
+
 	if isEnable {
 		if _, outErrText, exitCode, _, err := shell.ExecAndGetOutput(log, 1024, "", stScriptPath, "start", "-inverse"); err != nil {
 			if len(outErrText) > 0 {
@@ -645,9 +708,14 @@ func readProcEnvVarIvpnId(pid int) (int, error) {
 	if err != nil {
 		return 0, err
 	}
+	return parseEnvVarIvpnId(bytes)
+}
 
+// parseEnvVarIvpnId returns the value of 'PRIVATELINE_STARTED_ST_ID' variable from the process environment
+// (content of '/proc/<PID>/environ' file: NUL-separated list of variables). Returns 0 if the variable is not defined.
+func parseEnvVarIvpnId(environ []byte) (int, error) {
 	id := 0
-	vars := strings.Split(string(bytes), string(0))
+	vars := strings.Split(string(environ), "\x00")
 	for _, line := range vars {
 		cols := strings.Split(line, "=")
 		if len(cols) != 2 {
@@ -0,0 +1,50 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package splittun
+
+import "testing"
+
+func TestParseEnvVarIvpnId(t *testing.T) {
+	tests := []struct {
+		name    string
+		environ string
+		want    int
+		wantErr bool
+	}{
+		{"defined", "HOME=/root\x00PRIVATELINE_STARTED_ST_ID=1234\x00PATH=/usr/bin\x00", 1234, false},
+		{"last variable", "HOME=/root\x00PRIVATELINE_STARTED_ST_ID=42", 42, false},
+		{"not defined", "HOME=/root\x00PATH=/usr/bin\x00", 0, false},
+		{"similar name", "XPRIVATELINE_STARTED_ST_ID=5\x00PRIVATELINE_STARTED_ST_ID_X=6\x00", 0, false},
+		{"empty", "", 0, false},
+		{"not a number", "PRIVATELINE_STARTED_ST_ID=abc\x00", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEnvVarIvpnId([]byte(tt.environ))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseEnvVarIvpnId() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseEnvVarIvpnId() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -34,6 +34,7 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 	"unsafe"
 
 	"github.com/swapnilsparsh/devsVPN/daemon/netinfo"
@@ -970,3 +971,23 @@ func implStop() (err error) {
 
 	return nil
 }*/
+
+func implDomainsFuncNotAvailableError() error {
+	return fmt.Errorf("per-domain Split-Tunnelling is not implemented for this platform")
+}
+
+func implApplyDomainRoutes(cfg DomainsConfig, tunnelAddrs, bypassAddrs map[string]time.Time) error {
+	return nil
+}
+
+func implStartDnsResponsesMonitor(cfg DomainsConfig, onResponse func(packet []byte)) (stop func(), err error) {
+	return nil, implDomainsFuncNotAvailableError()
+}
+
+func implSubnetsFuncNotAvailableError() error {
+	return fmt.Errorf("per-CIDR Split-Tunnelling is not implemented for this platform")
+}
+
+func implApplySubnetRoutes(cfg SubnetsConfig, include, exclude []net.IPNet) error {
+	return nil
+}
@@ -0,0 +1,241 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package splittun
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Per-CIDR split tunnelling.
+//
+// 'Include' networks are routed through the VPN tunnel (in addition to the networks routed by the VPN server configuration);
+// 'exclude' networks are routed through the default gateway of the physical interface (bypass the VPN tunnel).
+// Both are passed to the firewall, so the traffic to them is allowed even when Total Shield is on.
+//
+// The rules must not break the VPN connection itself: an 'include' network can not contain the VPN server endpoint
+// (routing loop) and an 'exclude' network can not contain the local address of the VPN interface.
+// The rules are checked when they are set (against all hosts of the registered server) and again each time they are applied
+// (against the current connection): the rules which conflict with the current connection are skipped.
+
+// MaxSubnetRules - the maximum number of networks in each of 'include' and 'exclude' lists
+const MaxSubnetRules = 256
+
+// SubnetsConfig - configuration for per-CIDR split tunnelling
+type SubnetsConfig struct {
+	Include        []string // networks to route through the VPN tunnel (normalized CIDR)
+	Exclude        []string // networks to route bypassing the VPN tunnel (normalized CIDR)
+	IsVpnConnected bool
+	TunnelIP       net.IP // local IPv4 address of the VPN interface
+	Endpoint       net.IP // VPN server endpoint (to detect the default gateway of the physical interface)
+}
+
+// TunnelAddresses - addresses of the VPN connection which must not be affected by per-CIDR rules (IPv4 and IPv6)
+type TunnelAddresses struct {
+	LocalIPs  []net.IP // local addresses of the VPN interface
+	Endpoints []net.IP // VPN server endpoints
+}
+
+// FuncSubnetsFirewallNotify - callback to update the firewall with the networks of per-CIDR rules
+type FuncSubnetsFirewallNotify func(subnets []net.IPNet) error
+
+var (
+	subnetsMutex    sync.Mutex
+	subnetsFwNotify FuncSubnetsFirewallNotify
+)
+
+// SetSubnetsFirewallNotifier sets the callback to update the firewall with the networks of per-CIDR rules
+func SetSubnetsFirewallNotifier(f FuncSubnetsFirewallNotify) {
+	subnetsMutex.Lock()
+	defer subnetsMutex.Unlock()
+	subnetsFwNotify = f
+}
+
+// GetSubnetsFuncNotAvailableError returns non-nil error if per-CIDR split tunnelling is not available
+func GetSubnetsFuncNotAvailableError() error {
+	return implSubnetsFuncNotAvailableError()
+}
+
+// NormalizeSubnet parses IPv4 network in CIDR notation (or single IPv4 address) and returns it with the host bits cleared
+func NormalizeSubnet(subnet string) (net.IPNet, error) {
+	s := strings.TrimSpace(subnet)
+	if len(s) == 0 {
+		return net.IPNet{}, fmt.Errorf("network is empty")
+	}
+	if !strings.Contains(s, "/") {
+		s += "/32"
+	}
+	ip, n, err := net.ParseCIDR(s)
+	if err != nil {
+		return net.IPNet{}, fmt.Errorf("invalid network '%s'", subnet)
+	}
+	if ip.To4() == nil {
+		return net.IPNet{}, fmt.Errorf("'%s' is not an IPv4 network (only IPv4 is supported)", subnet)
+	}
+	if ones, _ := n.Mask.Size(); ones == 0 {
+		return net.IPNet{}, fmt.Errorf("'%s' covers all addresses: use Total Shield or disconnect the VPN instead", subnet)
+	}
+	return net.IPNet{IP: n.IP.To4(), Mask: n.Mask}, nil
+}
+
+// ValidateSubnets normalizes 'include' and 'exclude' lists and checks that they do not overlap each other
+// and do not break the VPN connection. Returns the normalized lists.
+func ValidateSubnets(include, exclude []string, tunnel TunnelAddresses) (retInclude, retExclude []string, err error) {
+	if len(include) > MaxSubnetRules || len(exclude) > MaxSubnetRules {
+		return nil, nil, fmt.Errorf("too many networks (maximum %d in each list)", MaxSubnetRules)
+	}
+
+	type entry struct {
+		net  net.IPNet
+		list string
+	}
+	var all []entry
+
+	parse := func(list []string, listName string) ([]string, error) {
+		ret := make([]string, 0, len(list))
+		for _, s := range list {
+			n, err := NormalizeSubnet(s)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range all {
+				if netsOverlap(n, e.net) {
+					if e.list == listName {
+						return nil, fmt.Errorf("%s network %s overlaps with %s", listName, n.String(), e.net.String())
+					}
+					return nil, fmt.Errorf("%s network %s overlaps with %s network %s", listName, n.String(), e.list, e.net.String())
+				}
+			}
+			all = append(all, entry{net: n, list: listName})
+			ret = append(ret, n.String())
+		}
+		return ret, nil
+	}
+
+	if retInclude, err = parse(include, "include"); err != nil {
+		return nil, nil, err
+	}
+	if retExclude, err = parse(exclude, "exclude"); err != nil {
+		return nil, nil, err
+	}
+
+	for _, e := range all {
+		if err := checkSubnetConflict(e.net, e.list == "exclude", tunnel); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return retInclude, retExclude, nil
+}
+
+// checkSubnetConflict returns an error if the network of per-CIDR rule breaks the VPN connection:
+// 'include' network contains the VPN server endpoint or 'exclude' network contains the local address of the VPN interface
+func checkSubnetConflict(n net.IPNet, isExclude bool, tunnel TunnelAddresses) error {
+	if !isExclude {
+		for _, ip := range tunnel.Endpoints {
+			if ip != nil && n.Contains(ip) {
+				return fmt.Errorf("include network %s contains the VPN server address %s", n.String(), ip)
+			}
+		}
+		return nil
+	}
+	for _, ip := range tunnel.LocalIPs {
+		if ip != nil && n.Contains(ip) {
+			return fmt.Errorf("exclude network %s contains the local VPN address %s", n.String(), ip)
+		}
+	}
+	return nil
+}
+
+// skipConflictingSubnets returns the networks which do not conflict with the current VPN connection (the conflicting ones are logged)
+func skipConflictingSubnets(nets []net.IPNet, isExclude bool, tunnel TunnelAddresses) []net.IPNet {
+	ret := make([]net.IPNet, 0, len(nets))
+	for _, n := range nets {
+		if err := checkSubnetConflict(n, isExclude, tunnel); err != nil {
+			log.Warning(fmt.Errorf("per-CIDR rule skipped: %w", err))
+			continue
+		}
+		ret = append(ret, n)
+	}
+	return ret
+}
+
+// ApplySubnetRules applies per-CIDR split tunnelling configuration.
+// The firewall is always updated; routes exist only when VPN is connected.
+func ApplySubnetRules(cfg SubnetsConfig) error {
+	subnetsMutex.Lock()
+	defer subnetsMutex.Unlock()
+
+	include := parseSubnets(cfg.Include)
+	exclude := parseSubnets(cfg.Exclude)
+
+	var retErr error
+	if subnetsFwNotify != nil {
+		if err := subnetsFwNotify(append(append([]net.IPNet{}, include...), exclude...)); err != nil {
+			retErr = fmt.Errorf("failed to update firewall: %w", err)
+		}
+	}
+
+	if !cfg.IsVpnConnected || implSubnetsFuncNotAvailableError() != nil {
+		include, exclude = nil, nil
+	} else {
+		// the rules were validated for the server which was in use when they were set; the current one can differ
+		tunnel := TunnelAddresses{LocalIPs: []net.IP{cfg.TunnelIP}, Endpoints: []net.IP{cfg.Endpoint}}
+		include = skipConflictingSubnets(include, false, tunnel)
+		exclude = skipConflictingSubnets(exclude, true, tunnel)
+	}
+	if err := implApplySubnetRoutes(cfg, include, exclude); err != nil {
+		retErr = errors.Join(retErr, fmt.Errorf("failed to update routes: %w", err))
+	}
+	return retErr
+}
+
+// parseSubnets converts the list of normalized networks to []net.IPNet (invalid entries are skipped)
+func parseSubnets(subnets []string) []net.IPNet {
+	ret := make([]net.IPNet, 0, len(subnets))
+	for _, s := range subnets {
+		if n, err := NormalizeSubnet(s); err == nil {
+			ret = append(ret, n)
+		} else {
+			log.Warning(err)
+		}
+	}
+	return ret
+}
+
+// netsOverlap returns true if the networks have common addresses
+func netsOverlap(a, b net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// isNetCovered returns true if the network 'n' is entirely inside one of 'nets'
+func isNetCovered(n net.IPNet, nets []net.IPNet) bool {
+	nOnes, nBits := n.Mask.Size()
+	for _, c := range nets {
+		cOnes, cBits := c.Mask.Size()
+		if cBits == nBits && cOnes <= nOnes && c.Contains(n.IP) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux
+// +build linux
+
+package splittun
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+)
+
+// routing protocol identifier of the routes added for per-CIDR rules (allows to find and remove them after daemon restart)
+const subnetRoutesProtocol netlink.RouteProtocol = 0x71
+
+// routes added for per-CIDR rules (map[<destination network>]<route>). Protected by subnetsMutex.
+var subnetRoutes = map[string]*netlink.Route{}
+
+func implSubnetsFuncNotAvailableError() error {
+	return nil
+}
+
+// removeStaleSubnetRoutes removes routes for per-CIDR rules which could remain after the daemon crash
+func removeStaleSubnetRoutes() {
+	removeRoutesByProtocol(subnetRoutesProtocol)
+}
+
+func implApplySubnetRoutes(cfg SubnetsConfig, include, exclude []net.IPNet) error {
+	var retErr error
+
+	wanted := map[string]*netlink.Route{}
+	if !cfg.IsVpnConnected {
+		clearPeerExtraAllowedIPs(peerAllowedIPsSourceSubnets)
+	} else if len(include) > 0 || hasPeerExtraAllowedIPs(peerAllowedIPsSourceSubnets) {
+		if linkIndex, err := linkIndexByAddr(cfg.TunnelIP); err != nil {
+			retErr = errors.Join(retErr, fmt.Errorf("VPN interface not found: %w", err))
+		} else {
+			for _, n := range include {
+				dst := n
+				wanted[n.String()] = &netlink.Route{Dst: &dst, LinkIndex: linkIndex, Src: cfg.TunnelIP.To4(), Protocol: subnetRoutesProtocol}
+			}
+			if err := setPeerExtraAllowedIPs(linkIndex, peerAllowedIPsSourceSubnets, include); err != nil {
+				retErr = errors.Join(retErr, err)
+			}
+		}
+	}
+	if len(exclude) > 0 {
+		if gw, err := physicalGateway(cfg.Endpoint); err != nil {
+			retErr = errors.Join(retErr, fmt.Errorf("default gateway not found: %w", err))
+		} else {
+			for _, n := range exclude {
+				dst := n
+				wanted[n.String()] = &netlink.Route{Dst: &dst, LinkIndex: gw.LinkIndex, Gw: gw.Gw, Protocol: subnetRoutesProtocol}
+			}
+		}
+	}
+
+	if err := syncRoutes(subnetRoutes, wanted); err != nil {
+		retErr = errors.Join(retErr, err)
+	}
+
+	return retErr
+}
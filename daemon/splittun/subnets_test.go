@@ -0,0 +1,135 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package splittun
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustCIDR(t *testing.T, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("invalid CIDR %q: %v", s, err)
+	}
+	return *n
+}
+
+func TestValidateSubnets(t *testing.T) {
+	tunnel := TunnelAddresses{
+		LocalIPs:  []net.IP{net.ParseIP("10.0.0.2"), net.ParseIP("fd00::2")},
+		Endpoints: []net.IP{net.ParseIP("198.51.100.10")},
+	}
+
+	tests := []struct {
+		name        string
+		include     []string
+		exclude     []string
+		tunnel      TunnelAddresses
+		wantInclude []string
+		wantExclude []string
+		wantErr     string
+	}{
+		{"empty", nil, nil, tunnel, []string{}, []string{}, ""},
+		{"normalized", []string{" 192.168.10.5/24", "172.16.0.1"}, []string{"203.0.113.0/24"}, tunnel,
+			[]string{"192.168.10.0/24", "172.16.0.1/32"}, []string{"203.0.113.0/24"}, ""},
+		// the tunnel routes everything (0.0.0.0/0): excluding a network must still be possible
+		{"exclude with full tunnel", nil, []string{"192.168.0.0/16", "8.8.8.8"}, tunnel, []string{}, []string{"192.168.0.0/16", "8.8.8.8/32"}, ""},
+		{"exclude contains endpoint", nil, []string{"198.51.100.0/24"}, tunnel, []string{}, []string{"198.51.100.0/24"}, ""},
+		{"include contains endpoint", []string{"198.51.100.0/24"}, nil, tunnel, nil, nil, "VPN server address"},
+		{"exclude contains local address", nil, []string{"10.0.0.0/8"}, tunnel, nil, nil, "local VPN address"},
+		{"include contains local address", []string{"10.0.0.0/8"}, nil, tunnel, []string{"10.0.0.0/8"}, []string{}, ""},
+		{"no tunnel addresses", []string{"198.51.100.0/24"}, []string{"10.0.0.0/8"}, TunnelAddresses{},
+			[]string{"198.51.100.0/24"}, []string{"10.0.0.0/8"}, ""},
+		{"overlap inside include", []string{"10.1.0.0/16", "10.1.2.0/24"}, nil, tunnel, nil, nil, "include network 10.1.2.0/24 overlaps with 10.1.0.0/16"},
+		{"overlap include and exclude", []string{"10.1.0.0/16"}, []string{"10.1.2.3"}, tunnel, nil, nil, "overlaps with include network"},
+		{"invalid network", []string{"10.1.0.0/33"}, nil, tunnel, nil, nil, "invalid network"},
+		{"ipv6 network", []string{"fd00::/64"}, nil, tunnel, nil, nil, "not an IPv4 network"},
+		{"all addresses", nil, []string{"0.0.0.0/0"}, tunnel, nil, nil, "covers all addresses"},
+		{"too many", make([]string, MaxSubnetRules+1), nil, tunnel, nil, nil, "too many networks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			include, exclude, err := ValidateSubnets(tt.include, tt.exclude, tt.tunnel)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ValidateSubnets() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateSubnets() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(include, tt.wantInclude) || !reflect.DeepEqual(exclude, tt.wantExclude) {
+				t.Errorf("ValidateSubnets() = %v, %v; want %v, %v", include, exclude, tt.wantInclude, tt.wantExclude)
+			}
+		})
+	}
+}
+
+func TestSkipConflictingSubnets(t *testing.T) {
+	tunnel := TunnelAddresses{LocalIPs: []net.IP{net.ParseIP("10.0.0.2")}, Endpoints: []net.IP{net.ParseIP("198.51.100.10")}}
+	nets := []net.IPNet{mustCIDR(t, "10.0.0.0/8"), mustCIDR(t, "198.51.100.0/24"), mustCIDR(t, "192.168.0.0/16")}
+
+	if got, want := skipConflictingSubnets(nets, false, tunnel), []net.IPNet{nets[0], nets[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("include: got %v, want %v", got, want)
+	}
+	if got, want := skipConflictingSubnets(nets, true, tunnel), []net.IPNet{nets[1], nets[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("exclude: got %v, want %v", got, want)
+	}
+	if got := skipConflictingSubnets(nets, false, TunnelAddresses{LocalIPs: []net.IP{nil}, Endpoints: []net.IP{nil}}); len(got) != len(nets) {
+		t.Errorf("unknown tunnel addresses: got %v, want all networks", got)
+	}
+}
+
+func TestIsNetCovered(t *testing.T) {
+	tests := []struct {
+		name string
+		n    string
+		nets []string
+		want bool
+	}{
+		{"no networks", "10.0.0.0/24", nil, false},
+		{"same network", "10.0.0.0/24", []string{"10.0.0.0/24"}, true},
+		{"inside larger network", "10.0.1.0/24", []string{"192.168.0.0/16", "10.0.0.0/8"}, true},
+		{"default route covers all", "203.0.113.7/32", []string{"0.0.0.0/0"}, true},
+		{"larger than covering network", "10.0.0.0/8", []string{"10.0.0.0/24"}, false},
+		{"partial overlap", "10.0.0.0/23", []string{"10.0.1.0/24"}, false},
+		{"disjoint", "172.16.0.0/12", []string{"10.0.0.0/8"}, false},
+		{"different family", "fd00::/64", []string{"0.0.0.0/0"}, false},
+		{"ipv6 inside ipv6", "fd00::/64", []string{"::/0"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nets []net.IPNet
+			for _, s := range tt.nets {
+				nets = append(nets, mustCIDR(t, s))
+			}
+			if got := isNetCovered(mustCIDR(t, tt.n), nets); got != tt.want {
+				t.Errorf("isNetCovered(%s, %v) = %v, want %v", tt.n, tt.nets, got, tt.want)
+			}
+		})
+	}
+}
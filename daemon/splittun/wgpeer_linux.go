@@ -0,0 +1,142 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux
+// +build linux
+
+package splittun
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/vishvananda/netlink"
+	"golang.zx2c4.com/wireguard/wgctrl"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// Sources of the additional AllowedIPs of the WireGuard peer
+const (
+	peerAllowedIPsSourceDomains = "domains"
+	peerAllowedIPsSourceSubnets = "subnets"
+)
+
+// WireGuard accepts only packets from (and sends only packets to) the peer's AllowedIPs.
+// Destinations routed into the tunnel by split tunnel rules must be added to the peer's AllowedIPs as well.
+var (
+	peerAllowedIPsMutex   sync.Mutex
+	peerExtraAllowedIPs   = map[string][]net.IPNet{} // map[<source>]<networks>
+	peerAppliedAllowedIPs = map[string]struct{}{}    // networks added to the peer by us
+	peerAppliedLink       string                     // name of the interface 'peerAppliedAllowedIPs' were added to
+)
+
+// clearPeerExtraAllowedIPs forgets the additional AllowedIPs of the source without touching the interface
+// (used when the VPN is disconnected - the interface does not exist anymore)
+func clearPeerExtraAllowedIPs(source string) {
+	peerAllowedIPsMutex.Lock()
+	defer peerAllowedIPsMutex.Unlock()
+	delete(peerExtraAllowedIPs, source)
+}
+
+// hasPeerExtraAllowedIPs returns true if the source has additional AllowedIPs set
+func hasPeerExtraAllowedIPs(source string) bool {
+	peerAllowedIPsMutex.Lock()
+	defer peerAllowedIPsMutex.Unlock()
+	return len(peerExtraAllowedIPs[source]) > 0
+}
+
+// setPeerExtraAllowedIPs sets the additional AllowedIPs of the source and updates the peer of WireGuard interface.
+// The original AllowedIPs of the peer are kept; networks which are already covered by them are not added.
+func setPeerExtraAllowedIPs(linkIndex int, source string, nets []net.IPNet) error {
+	peerAllowedIPsMutex.Lock()
+	defer peerAllowedIPsMutex.Unlock()
+
+	peerExtraAllowedIPs[source] = nets
+
+	link, err := netlink.LinkByIndex(linkIndex)
+	if err != nil {
+		return fmt.Errorf("VPN interface not found: %w", err)
+	}
+	linkName := link.Attrs().Name
+	if linkName != peerAppliedLink {
+		// new interface (reconnection): nothing added yet
+		peerAppliedAllowedIPs = map[string]struct{}{}
+		peerAppliedLink = linkName
+	}
+
+	client, err := wgctrl.New()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	dev, err := client.Device(linkName)
+	if err != nil {
+		return fmt.Errorf("failed to get WireGuard interface info: %w", err)
+	}
+	if len(dev.Peers) == 0 {
+		return fmt.Errorf("WireGuard interface '%s' has no peers", linkName)
+	}
+	peer := dev.Peers[0]
+
+	var base []net.IPNet
+	for _, n := range peer.AllowedIPs {
+		if _, ok := peerAppliedAllowedIPs[n.String()]; !ok {
+			base = append(base, n)
+		}
+	}
+
+	allowedIPs := append([]net.IPNet{}, base...)
+	applied := map[string]struct{}{}
+	for _, srcNets := range peerExtraAllowedIPs {
+		for _, n := range srcNets {
+			if _, ok := applied[n.String()]; ok || isNetCovered(n, base) {
+				continue
+			}
+			applied[n.String()] = struct{}{}
+			allowedIPs = append(allowedIPs, n)
+		}
+	}
+
+	if len(applied) == len(peerAppliedAllowedIPs) {
+		changed := false
+		for n := range applied {
+			if _, ok := peerAppliedAllowedIPs[n]; !ok {
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			return nil
+		}
+	}
+
+	err = client.ConfigureDevice(linkName, wgtypes.Config{Peers: []wgtypes.PeerConfig{{
+		PublicKey:         peer.PublicKey,
+		UpdateOnly:        true,
+		ReplaceAllowedIPs: true,
+		AllowedIPs:        allowedIPs,
+	}}})
+	if err != nil {
+		return fmt.Errorf("failed to update AllowedIPs of WireGuard peer: %w", err)
+	}
+	peerAppliedAllowedIPs = applied
+	return nil
+}
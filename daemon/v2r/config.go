@@ -153,6 +153,11 @@ const defaultConfigTemplate = `{
 // * [ VMESS-PROTOCOL ] - protocol/obfuscation type
 //   - quick for VMESS/QUICK
 //   - tcp for VMESS/TCP
+//   - ws for VMESS/WebSocket over TLS (can be fronted by CDN: SNI and Host header are configurable)
+//   - grpc for VMESS/gRPC over TLS or REALITY
+//   - http for VMESS/HTTP2 over TLS or REALITY
+//     WebSocket, gRPC and HTTP/2 require TCP port for [VMESS-server PORT] (443 is preferred).
+//     REALITY and TLS fingerprints (uTLS) require Xray-core compatible binary.
 //
 // Additional info:
 // * V2Ray data flow:
@@ -212,9 +217,35 @@ type V2RayConfig struct {
 			} `json:"quicSettings,omitempty"`
 
 			TlsSettings *struct {
-				ServerName string `json:"serverName"`
+				ServerName  string   `json:"serverName"`
+				Fingerprint string   `json:"fingerprint,omitempty"`
+				Alpn        []string `json:"alpn,omitempty"`
 			} `json:"tlsSettings,omitempty"`
 
+			RealitySettings *struct {
+				ServerName  string `json:"serverName"`
+				Fingerprint string `json:"fingerprint"`
+				PublicKey   string `json:"publicKey"`
+				ShortId     string `json:"shortId,omitempty"`
+				SpiderX     string `json:"spiderX,omitempty"`
+			} `json:"realitySettings,omitempty"`
+
+			WsSettings *struct {
+				Path    string `json:"path"`
+				Headers struct {
+					Host string `json:"Host,omitempty"`
+				} `json:"headers"`
+			} `json:"wsSettings,omitempty"`
+
+			GrpcSettings *struct {
+				ServiceName string `json:"serviceName"`
+			} `json:"grpcSettings,omitempty"`
+
+			HttpSettings *struct {
+				Host []string `json:"host,omitempty"`
+				Path string   `json:"path"`
+			} `json:"httpSettings,omitempty"`
+
 			TcpSettings *struct {
 				Header struct {
 					Type    string `json:"type"`
@@ -236,6 +267,30 @@ type V2RayConfig struct {
 	} `json:"outbounds"`
 }
 
+// TransportOptions - parameters of the TLS-based transports (WebSocket, gRPC, HTTP/2)
+// All fields are optional: empty values are replaced by defaults
+type TransportOptions struct {
+	ServerName  string          `json:",omitempty"` // TLS SNI (default: DNS name of the V2Ray server)
+	Host        string          `json:",omitempty"` // HTTP Host header for WebSocket and HTTP/2 (default: ServerName)
+	Path        string          `json:",omitempty"` // WebSocket/HTTP2 path or gRPC service name
+	Fingerprint string          `json:",omitempty"` // uTLS client fingerprint ("chrome", "firefox", "safari", "random" ...)
+	Reality     *RealityOptions `json:",omitempty"` // when defined - REALITY is used instead of TLS (gRPC and HTTP/2 only)
+}
+
+// RealityOptions - REALITY parameters of the server
+type RealityOptions struct {
+	PublicKey string
+	ShortId   string `json:",omitempty"`
+	SpiderX   string `json:",omitempty"`
+}
+
+const (
+	defaultWebSocketPath   = "/"
+	defaultHttp2Path       = "/"
+	defaultGrpcServiceName = "v2ray"
+	defaultFingerprint     = "chrome" // REALITY can not work without fingerprint
+)
+
 // GetLocalPort function returns local port and protocol
 func (c *V2RayConfig) GetLocalPort() (port int, isTcp bool) {
 	port, _ = strconv.Atoi(c.Inbounds[0].Port)
@@ -286,6 +341,101 @@ func CreateConfig_OutboundsTcp(outboundIp string, outboundPort int, inboundIp st
 	return config
 }
 
+// CreateConfig_OutboundsWebSocket creates configuration for VMESS/WebSocket over TLS
+func CreateConfig_OutboundsWebSocket(outboundIp string, outboundPort int, inboundIp string, inboundPort int, outboundUserId string, opts TransportOptions) *V2RayConfig {
+	config := createTlsTransportConfig("ws", outboundIp, outboundPort, inboundIp, inboundPort, outboundUserId, opts, "http/1.1")
+
+	path := opts.Path
+	if path == "" {
+		path = defaultWebSocketPath
+	}
+	config.Outbounds[0].StreamSettings.WsSettings = &struct {
+		Path    string `json:"path"`
+		Headers struct {
+			Host string `json:"Host,omitempty"`
+		} `json:"headers"`
+	}{Path: path}
+	config.Outbounds[0].StreamSettings.WsSettings.Headers.Host = opts.hostOrServerName()
+	return config
+}
+
+// CreateConfig_OutboundsGrpc creates configuration for VMESS/gRPC over TLS or REALITY
+func CreateConfig_OutboundsGrpc(outboundIp string, outboundPort int, inboundIp string, inboundPort int, outboundUserId string, opts TransportOptions) *V2RayConfig {
+	config := createTlsTransportConfig("grpc", outboundIp, outboundPort, inboundIp, inboundPort, outboundUserId, opts, "h2")
+
+	serviceName := opts.Path
+	if serviceName == "" {
+		serviceName = defaultGrpcServiceName
+	}
+	config.Outbounds[0].StreamSettings.GrpcSettings = &struct {
+		ServiceName string `json:"serviceName"`
+	}{ServiceName: serviceName}
+	return config
+}
+
+// CreateConfig_OutboundsHttp2 creates configuration for VMESS/HTTP2 over TLS or REALITY
+func CreateConfig_OutboundsHttp2(outboundIp string, outboundPort int, inboundIp string, inboundPort int, outboundUserId string, opts TransportOptions) *V2RayConfig {
+	config := createTlsTransportConfig("http", outboundIp, outboundPort, inboundIp, inboundPort, outboundUserId, opts, "h2")
+
+	path := opts.Path
+	if path == "" {
+		path = defaultHttp2Path
+	}
+	config.Outbounds[0].StreamSettings.HttpSettings = &struct {
+		Host []string `json:"host,omitempty"`
+		Path string   `json:"path"`
+	}{Path: path}
+	if host := opts.hostOrServerName(); host != "" {
+		config.Outbounds[0].StreamSettings.HttpSettings.Host = []string{host}
+	}
+	return config
+}
+
+// createTlsTransportConfig creates base configuration for the TLS-based transports and applies TLS or REALITY security settings
+func createTlsTransportConfig(network string, outboundIp string, outboundPort int, inboundIp string, inboundPort int, outboundUserId string, opts TransportOptions, alpn string) *V2RayConfig {
+	config := createConfigFromTemplate(outboundIp, outboundPort, inboundIp, inboundPort, outboundUserId)
+	ss := &config.Outbounds[0].StreamSettings
+	ss.Network = network
+	ss.QuicSettings = nil
+	ss.TcpSettings = nil
+
+	if opts.Reality != nil {
+		fingerprint := opts.Fingerprint
+		if fingerprint == "" {
+			fingerprint = defaultFingerprint
+		}
+		ss.Security = "reality"
+		ss.TlsSettings = nil
+		ss.RealitySettings = &struct {
+			ServerName  string `json:"serverName"`
+			Fingerprint string `json:"fingerprint"`
+			PublicKey   string `json:"publicKey"`
+			ShortId     string `json:"shortId,omitempty"`
+			SpiderX     string `json:"spiderX,omitempty"`
+		}{
+			ServerName:  opts.ServerName,
+			Fingerprint: fingerprint,
+			PublicKey:   opts.Reality.PublicKey,
+			ShortId:     opts.Reality.ShortId,
+			SpiderX:     opts.Reality.SpiderX,
+		}
+		return config
+	}
+
+	ss.Security = "tls"
+	ss.TlsSettings.ServerName = opts.ServerName
+	ss.TlsSettings.Fingerprint = opts.Fingerprint
+	ss.TlsSettings.Alpn = []string{alpn}
+	return config
+}
+
+func (o TransportOptions) hostOrServerName() string {
+	if o.Host != "" {
+		return o.Host
+	}
+	return o.ServerName
+}
+
 // function checks if configuration fields of config are defined
 func (c *V2RayConfig) isValid() error {
 	if c == nil {
@@ -309,5 +459,56 @@ func (c *V2RayConfig) isValid() error {
 	if strings.TrimSpace(c.Outbounds[0].Settings.Vnext[0].Users[0].Id) == "" {
 		return fmt.Errorf("config.Outbounds[0].Settings.Vnext[0].Users[0].Id is empty")
 	}
+	return c.isStreamSettingsValid()
+}
+
+// function checks if transport-specific configuration fields of config are consistent
+func (c *V2RayConfig) isStreamSettingsValid() error {
+	ss := c.Outbounds[0].StreamSettings
+
+	switch ss.Network {
+	case "quic", "tcp":
+		return nil
+	case "ws":
+		if ss.WsSettings == nil || !strings.HasPrefix(ss.WsSettings.Path, "/") {
+			return fmt.Errorf("config.Outbounds[0].StreamSettings.WsSettings.Path must start with '/'")
+		}
+		if ss.Security == "reality" {
+			return fmt.Errorf("REALITY is not applicable for WebSocket transport")
+		}
+	case "grpc":
+		if ss.GrpcSettings == nil || strings.TrimSpace(ss.GrpcSettings.ServiceName) == "" {
+			return fmt.Errorf("config.Outbounds[0].StreamSettings.GrpcSettings.ServiceName is empty")
+		}
+	case "http":
+		if ss.HttpSettings == nil || !strings.HasPrefix(ss.HttpSettings.Path, "/") {
+			return fmt.Errorf("config.Outbounds[0].StreamSettings.HttpSettings.Path must start with '/'")
+		}
+		if ss.Security != "tls" && ss.Security != "reality" {
+			return fmt.Errorf("HTTP/2 transport requires TLS or REALITY")
+		}
+	default:
+		return fmt.Errorf("config.Outbounds[0].StreamSettings.Network has unknown value '%s'", ss.Network)
+	}
+
+	switch ss.Security {
+	case "tls":
+		if ss.TlsSettings == nil || strings.TrimSpace(ss.TlsSettings.ServerName) == "" {
+			return fmt.Errorf("config.Outbounds[0].StreamSettings.TlsSettings.ServerName is empty")
+		}
+	case "reality":
+		r := ss.RealitySettings
+		if r == nil || strings.TrimSpace(r.ServerName) == "" {
+			return fmt.Errorf("config.Outbounds[0].StreamSettings.RealitySettings.ServerName is empty")
+		}
+		if strings.TrimSpace(r.PublicKey) == "" {
+			return fmt.Errorf("config.Outbounds[0].StreamSettings.RealitySettings.PublicKey is empty")
+		}
+		if strings.TrimSpace(r.Fingerprint) == "" {
+			return fmt.Errorf("config.Outbounds[0].StreamSettings.RealitySettings.Fingerprint is empty")
+		}
+	default:
+		return fmt.Errorf("transport '%s' requires TLS or REALITY security", ss.Network)
+	}
 	return nil
 }
@@ -0,0 +1,240 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package v2r
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type createFunc func(outboundIp string, outboundPort int, inboundIp string, inboundPort int, outboundUserId string, opts TransportOptions) *V2RayConfig
+
+func TestCreateConfig_TlsTransports(t *testing.T) {
+	reality := &RealityOptions{PublicKey: "pbk", ShortId: "sid"}
+
+	tests := []struct {
+		name         string
+		create       createFunc
+		opts         TransportOptions
+		wantNetwork  string
+		wantSecurity string
+		wantPath     string // WebSocket/HTTP2 path or gRPC service name
+		wantHost     string
+		wantFp       string
+		wantErr      string
+	}{
+		{"ws defaults", CreateConfig_OutboundsWebSocket, TransportOptions{ServerName: "srv.example.com"}, "ws", "tls", "/", "srv.example.com", "", ""},
+		{"ws custom", CreateConfig_OutboundsWebSocket, TransportOptions{ServerName: "srv.example.com", Host: "cdn.example.com", Path: "/ws", Fingerprint: "firefox"}, "ws", "tls", "/ws", "cdn.example.com", "firefox", ""},
+		{"ws bad path", CreateConfig_OutboundsWebSocket, TransportOptions{ServerName: "srv.example.com", Path: "ws"}, "ws", "tls", "ws", "srv.example.com", "", "WsSettings.Path must start with '/'"},
+		{"ws reality", CreateConfig_OutboundsWebSocket, TransportOptions{ServerName: "srv.example.com", Reality: reality}, "ws", "reality", "/", "srv.example.com", "chrome", "REALITY is not applicable for WebSocket"},
+		{"ws no server name", CreateConfig_OutboundsWebSocket, TransportOptions{}, "ws", "tls", "/", "", "", "TlsSettings.ServerName is empty"},
+		{"grpc defaults", CreateConfig_OutboundsGrpc, TransportOptions{ServerName: "srv.example.com"}, "grpc", "tls", "v2ray", "", "", ""},
+		{"grpc service name", CreateConfig_OutboundsGrpc, TransportOptions{ServerName: "srv.example.com", Path: "tun"}, "grpc", "tls", "tun", "", "", ""},
+		{"grpc reality", CreateConfig_OutboundsGrpc, TransportOptions{ServerName: "srv.example.com", Reality: reality}, "grpc", "reality", "v2ray", "", "chrome", ""},
+		{"grpc reality fingerprint", CreateConfig_OutboundsGrpc, TransportOptions{ServerName: "srv.example.com", Fingerprint: "safari", Reality: reality}, "grpc", "reality", "v2ray", "", "safari", ""},
+		{"grpc reality no public key", CreateConfig_OutboundsGrpc, TransportOptions{ServerName: "srv.example.com", Reality: &RealityOptions{}}, "grpc", "reality", "v2ray", "", "chrome", "RealitySettings.PublicKey is empty"},
+		{"h2 defaults", CreateConfig_OutboundsHttp2, TransportOptions{ServerName: "srv.example.com"}, "http", "tls", "/", "srv.example.com", "", ""},
+		{"h2 reality", CreateConfig_OutboundsHttp2, TransportOptions{ServerName: "srv.example.com", Host: "h.example.com", Path: "/h2", Reality: reality}, "http", "reality", "/h2", "h.example.com", "chrome", ""},
+		{"h2 bad path", CreateConfig_OutboundsHttp2, TransportOptions{ServerName: "srv.example.com", Path: "h2"}, "http", "tls", "h2", "srv.example.com", "", "HttpSettings.Path must start with '/'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.create("1.2.3.4", 443, "127.0.0.1", 1080, "user-id", tt.opts)
+			cfg.SetLocalPort(12345, true)
+			ss := cfg.Outbounds[0].StreamSettings
+
+			if ss.Network != tt.wantNetwork {
+				t.Errorf("Network = %q, want %q", ss.Network, tt.wantNetwork)
+			}
+			if ss.Security != tt.wantSecurity {
+				t.Errorf("Security = %q, want %q", ss.Security, tt.wantSecurity)
+			}
+			if ss.QuicSettings != nil || ss.TcpSettings != nil {
+				t.Errorf("QUIC/TCP settings must be removed")
+			}
+
+			var path, host, fp string
+			switch {
+			case ss.WsSettings != nil:
+				path, host = ss.WsSettings.Path, ss.WsSettings.Headers.Host
+			case ss.GrpcSettings != nil:
+				path = ss.GrpcSettings.ServiceName
+			case ss.HttpSettings != nil:
+				path = ss.HttpSettings.Path
+				if len(ss.HttpSettings.Host) > 0 {
+					host = ss.HttpSettings.Host[0]
+				}
+			}
+			switch ss.Security {
+			case "tls":
+				if ss.RealitySettings != nil {
+					t.Errorf("RealitySettings must not be defined for TLS")
+				}
+				fp = ss.TlsSettings.Fingerprint
+			case "reality":
+				if ss.TlsSettings != nil {
+					t.Errorf("TlsSettings must not be defined for REALITY")
+				}
+				fp = ss.RealitySettings.Fingerprint
+				if ss.RealitySettings.ServerName != tt.opts.ServerName || ss.RealitySettings.PublicKey != tt.opts.Reality.PublicKey || ss.RealitySettings.ShortId != tt.opts.Reality.ShortId {
+					t.Errorf("RealitySettings = %+v, want values from %+v", *ss.RealitySettings, tt.opts)
+				}
+			}
+			if path != tt.wantPath {
+				t.Errorf("path = %q, want %q", path, tt.wantPath)
+			}
+			if host != tt.wantHost {
+				t.Errorf("host = %q, want %q", host, tt.wantHost)
+			}
+			if fp != tt.wantFp {
+				t.Errorf("fingerprint = %q, want %q", fp, tt.wantFp)
+			}
+
+			err := cfg.isValid()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("isValid() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("isValid() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsStreamSettingsValid(t *testing.T) {
+	opts := TransportOptions{ServerName: "srv.example.com"}
+
+	tests := []struct {
+		name    string
+		config  func() *V2RayConfig
+		wantErr string
+	}{
+		{"quic", func() *V2RayConfig {
+			return CreateConfig_OutboundsQuick("1.2.3.4", 443, "127.0.0.1", 1080, "user-id", "srv.example.com")
+		}, ""},
+		{"tcp", func() *V2RayConfig { return CreateConfig_OutboundsTcp("1.2.3.4", 443, "127.0.0.1", 1080, "user-id") }, ""},
+		{"unknown network", func() *V2RayConfig {
+			c := CreateConfig_OutboundsGrpc("1.2.3.4", 443, "127.0.0.1", 1080, "user-id", opts)
+			c.Outbounds[0].StreamSettings.Network = "kcp"
+			return c
+		}, "Network has unknown value 'kcp'"},
+		{"ws without settings", func() *V2RayConfig {
+			c := CreateConfig_OutboundsWebSocket("1.2.3.4", 443, "127.0.0.1", 1080, "user-id", opts)
+			c.Outbounds[0].StreamSettings.WsSettings = nil
+			return c
+		}, "WsSettings.Path must start with '/'"},
+		{"grpc empty service name", func() *V2RayConfig {
+			c := CreateConfig_OutboundsGrpc("1.2.3.4", 443, "127.0.0.1", 1080, "user-id", opts)
+			c.Outbounds[0].StreamSettings.GrpcSettings.ServiceName = " "
+			return c
+		}, "GrpcSettings.ServiceName is empty"},
+		{"grpc without security", func() *V2RayConfig {
+			c := CreateConfig_OutboundsGrpc("1.2.3.4", 443, "127.0.0.1", 1080, "user-id", opts)
+			c.Outbounds[0].StreamSettings.Security = ""
+			return c
+		}, "transport 'grpc' requires TLS or REALITY security"},
+		{"h2 without security", func() *V2RayConfig {
+			c := CreateConfig_OutboundsHttp2("1.2.3.4", 443, "127.0.0.1", 1080, "user-id", opts)
+			c.Outbounds[0].StreamSettings.Security = ""
+			return c
+		}, "HTTP/2 transport requires TLS or REALITY"},
+		{"reality empty fingerprint", func() *V2RayConfig {
+			c := CreateConfig_OutboundsHttp2("1.2.3.4", 443, "127.0.0.1", 1080, "user-id",
+				TransportOptions{ServerName: "srv.example.com", Reality: &RealityOptions{PublicKey: "pbk"}})
+			c.Outbounds[0].StreamSettings.RealitySettings.Fingerprint = ""
+			return c
+		}, "RealitySettings.Fingerprint is empty"},
+		{"reality empty server name", func() *V2RayConfig {
+			return CreateConfig_OutboundsGrpc("1.2.3.4", 443, "127.0.0.1", 1080, "user-id", TransportOptions{Reality: &RealityOptions{PublicKey: "pbk"}})
+		}, "RealitySettings.ServerName is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config().isStreamSettingsValid()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckTransportOptionsSupported(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses shell scripts as fake binaries")
+	}
+
+	dir := t.TempDir()
+	fakeBinary := func(name, versionOutput string) string {
+		file := filepath.Join(dir, name)
+		script := "#!/bin/sh\necho '" + versionOutput + "'\n"
+		if err := os.WriteFile(file, []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+	xray := fakeBinary("xray", "Xray 1.8.4 (Xray, Penetrates Everything.) Custom (go1.21.0 linux/amd64)")
+	v2fly := fakeBinary("v2ray", "V2Ray 5.7.0 (V2Fly, a community-driven edition of V2Ray.) Custom (go1.20.4 linux/amd64)")
+	missing := filepath.Join(dir, "missing")
+
+	reality := &RealityOptions{PublicKey: "pbk"}
+
+	tests := []struct {
+		name    string
+		binary  string
+		opts    TransportOptions
+		wantErr string
+	}{
+		{"tls on v2fly", v2fly, TransportOptions{ServerName: "srv"}, ""},
+		{"fingerprint on v2fly", v2fly, TransportOptions{Fingerprint: "chrome"}, "TLS client fingerprint requires Xray-core"},
+		{"reality on v2fly", v2fly, TransportOptions{Reality: reality}, "REALITY requires Xray-core"},
+		{"reality on missing binary", missing, TransportOptions{Reality: reality}, "REALITY requires Xray-core"},
+		{"fingerprint on xray", xray, TransportOptions{Fingerprint: "chrome"}, ""},
+		{"reality on xray", xray, TransportOptions{Fingerprint: "firefox", Reality: reality}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTransportOptionsSupported(tt.binary, tt.opts)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
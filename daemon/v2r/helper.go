@@ -43,6 +43,8 @@ import (
 //	inboundIp - IP address of Dokodemo server
 //	inboundPort - port of Dokodemo server
 //	vnextUserId - user ID
+//	quicTlsSvrName - TLS server name (used as default SNI for WebSocket, gRPC and HTTP/2 transports)
+//	opts - options of WebSocket, gRPC and HTTP/2 transports (ignored for QUIC and TCP)
 func Start(binary string,
 	tmpConfigFile string,
 	isTcpLocalPort bool,
@@ -52,7 +54,8 @@ func Start(binary string,
 	inboundIp string,
 	inboundPort int,
 	outboundUserId string,
-	quicTlsSvrName string) (*V2RayWrapper, error) {
+	quicTlsSvrName string,
+	opts TransportOptions) (*V2RayWrapper, error) {
 	var cfg *V2RayConfig
 	if outboundType == QUIC {
 		if quicTlsSvrName == "" {
@@ -61,6 +64,24 @@ func Start(binary string,
 		cfg = CreateConfig_OutboundsQuick(outboundIp, outboundPort, inboundIp, inboundPort, outboundUserId, quicTlsSvrName)
 	} else if outboundType == TCP {
 		cfg = CreateConfig_OutboundsTcp(outboundIp, outboundPort, inboundIp, inboundPort, outboundUserId)
+	} else if outboundType.IsTlsTransport() {
+		if opts.ServerName == "" {
+			opts.ServerName = quicTlsSvrName
+		}
+		if opts.ServerName == "" {
+			return nil, errors.New("TLS server name is empty")
+		}
+		if err := CheckTransportOptionsSupported(binary, opts); err != nil {
+			return nil, err
+		}
+		switch outboundType {
+		case WebSocket:
+			cfg = CreateConfig_OutboundsWebSocket(outboundIp, outboundPort, inboundIp, inboundPort, outboundUserId, opts)
+		case GRPC:
+			cfg = CreateConfig_OutboundsGrpc(outboundIp, outboundPort, inboundIp, inboundPort, outboundUserId, opts)
+		case HTTP2:
+			cfg = CreateConfig_OutboundsHttp2(outboundIp, outboundPort, inboundIp, inboundPort, outboundUserId, opts)
+		}
 	} else {
 		return nil, errors.New("unknown outbound type")
 	}
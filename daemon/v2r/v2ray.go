@@ -52,6 +52,12 @@ const (
 	None V2RayTransportType = iota
 	QUIC V2RayTransportType = iota
 	TCP  V2RayTransportType = iota
+
+	// Transports below are carried over TLS (or REALITY) on a TCP outbound port.
+	// New values must be appended to keep the numeric values of the existing ones (they are stored in settings).
+	WebSocket V2RayTransportType = iota
+	GRPC      V2RayTransportType = iota
+	HTTP2     V2RayTransportType = iota
 )
 
 func (t V2RayTransportType) ToString() string {
@@ -62,11 +68,65 @@ func (t V2RayTransportType) ToString() string {
 		return "QUIC"
 	case TCP:
 		return "TCP"
+	case WebSocket:
+		return "WebSocket"
+	case GRPC:
+		return "gRPC"
+	case HTTP2:
+		return "HTTP/2"
 	default:
 		return "unknown"
 	}
 }
 
+// IsTcpOutbound returns true when the transport requires TCP port for the outbound connection (VMess server port)
+func (t V2RayTransportType) IsTcpOutbound() bool {
+	return t == TCP || t == WebSocket || t == GRPC || t == HTTP2
+}
+
+// IsTlsTransport returns true when the transport is carried over TLS (or REALITY) and accepts TransportOptions
+func (t V2RayTransportType) IsTlsTransport() bool {
+	return t == WebSocket || t == GRPC || t == HTTP2
+}
+
+var (
+	xrayBinariesMutex sync.Mutex
+	xrayBinaries      = map[string]bool{} // binary path -> true when the binary is Xray-core
+)
+
+// IsXrayCompatibleBinary returns true when the binary is Xray-core (the result is cached per binary path).
+// REALITY and uTLS client fingerprints are supported only by Xray-core, not by the V2Ray (v2fly) binary.
+func IsXrayCompatibleBinary(binary string) bool {
+	xrayBinariesMutex.Lock()
+	defer xrayBinariesMutex.Unlock()
+
+	if isXray, ok := xrayBinaries[binary]; ok {
+		return isXray
+	}
+
+	// Xray-core prints "Xray <version> ..."; V2Ray prints "V2Ray <version> ..."
+	out, err := exec.Command(binary, "version").Output()
+	isXray := err == nil && bytes.HasPrefix(bytes.TrimSpace(out), []byte("Xray "))
+	if err == nil {
+		xrayBinaries[binary] = isXray
+	}
+	return isXray
+}
+
+// CheckTransportOptionsSupported returns error when the options require features which are not supported by the binary
+func CheckTransportOptionsSupported(binary string, opts TransportOptions) error {
+	if opts.Reality == nil && opts.Fingerprint == "" {
+		return nil
+	}
+	if IsXrayCompatibleBinary(binary) {
+		return nil
+	}
+	if opts.Reality != nil {
+		return fmt.Errorf("REALITY requires Xray-core compatible V2Ray binary ('%s' is not)", binary)
+	}
+	return fmt.Errorf("TLS client fingerprint requires Xray-core compatible V2Ray binary ('%s' is not)", binary)
+}
+
 type V2RayWrapper struct {
 	binary         string
 	tempConfigFile string
@@ -123,6 +183,15 @@ func (v *V2RayWrapper) getRemoteEndpoint() (host net.IP, port int, err error) {
 	return host, port, nil
 }
 
+// GetRemoteHosts returns IP address of the V2Ray server (VMess server) as a list
+func (v *V2RayWrapper) GetRemoteHosts() ([]net.IP, error) {
+	host, _, err := v.GetRemoteEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	return []net.IP{host}, nil
+}
+
 func (v *V2RayWrapper) Stop() error {
 	v.mutex.Lock()
 	defer v.mutex.Unlock()
@@ -50,6 +50,9 @@ type ConnectionParams struct {
 	proxyPassword        string
 	proxyAuthFileData    string // required for for obfs4 socks(!) proxy `--socks-proxy server [port] [authfile]`. If this parameter is defined - `proxyUsername` and `proxyPassword`` will be ignored.
 	// (e.g. the obfs4 requires the key to be stored in 'authfile': `cert=E50PjFC...6R7jzP0gYQ;iat-mode=0`)
+
+	customOptions      []string // options of the user-imported configuration (custom server); when defined - privateLINE-specific options are not in use
+	customAuthUserPass bool     // (custom server) the server requires username/password
 }
 
 func (c *ConnectionParams) IsMultihop() bool {
@@ -94,6 +97,24 @@ func CreateConnectionParams(
 		proxyPassword:        proxyPassword}
 }
 
+// CreateCustomConnectionParams creates OpenVPN connection parameters for the user-imported configuration.
+// 'hostIP' - resolved IP address of the remote server (it is allowed by the firewall)
+func CreateCustomConnectionParams(cfg CustomConfig, hostIP net.IP, username, password string) ConnectionParams {
+	return ConnectionParams{
+		username:           username,
+		password:           password,
+		tcp:                cfg.TCP,
+		hostPort:           cfg.RemotePort,
+		hostIP:             hostIP,
+		customOptions:      cfg.Options,
+		customAuthUserPass: cfg.IsAuthUserPass}
+}
+
+// IsCustom returns 'true' for the connection to the user-imported configuration (custom server)
+func (c *ConnectionParams) IsCustom() bool {
+	return len(c.customOptions) > 0
+}
+
 // WriteConfigFile saves OpenVPN connection parameters into a config file
 func (c *ConnectionParams) WriteConfigFile(
 	localPort int,
@@ -117,9 +138,13 @@ func (c *ConnectionParams) WriteConfigFile(
 		return fmt.Errorf("failed to save OpenVPN configuration into a file: %w", err)
 	}
 
+	configToLog := configText
+	if c.IsCustom() {
+		configToLog = strings.Join(redactInlineBlocks(cfg), "\n") // do not write keys of the imported configuration into the log
+	}
 	log.Info("Configuring OpenVPN...\n",
 		"=====================\n",
-		configText,
+		configToLog,
 		"\n=====================\n")
 
 	return nil
@@ -134,6 +159,10 @@ func (c *ConnectionParams) generateConfiguration(
 	isCanUseV24Params bool,
 	upDownScriptArgs string) (cfg []string, err error) {
 
+	if c.IsCustom() {
+		return c.generateCustomConfiguration(miAddr, miPort, logFile, upDownScriptArgs)
+	}
+
 	cfg = make([]string, 0, 32)
 
 	cfg = append(cfg, "client")
@@ -239,7 +268,7 @@ func (c *ConnectionParams) generateConfiguration(
 
 	cfg = append(cfg, "cipher AES-256-CBC")
 	cfg = append(cfg, "remote-cert-tls server")
-	cfg = append(cfg, "verb 4")
+	cfg = append(cfg, fmt.Sprintf("verb %d", DefaultLogVerbosity))
 
 	if upCmd := platform.OpenvpnUpScript(); upCmd != "" {
 		// (Linux) info: the 'upDownScriptArgs' controls the way of changing DNS ('resolvectl' or 'resolv.conf')
@@ -278,6 +307,75 @@ func (c *ConnectionParams) generateConfiguration(
 	return cfg, nil
 }
 
+// generateCustomConfiguration generates the configuration for the user-imported configuration (custom server):
+// the daemon defines the management interface, the tunnel device, the remote server and the DNS scripts; the rest of the options are taken from the imported configuration
+func (c *ConnectionParams) generateCustomConfiguration(miAddr string, miPort int, logFile string, upDownScriptArgs string) (cfg []string, err error) {
+	if c.hostIP == nil || c.hostIP.IsUnspecified() {
+		return nil, errors.New("unable to connect. Host IP not defined")
+	}
+	if c.hostPort <= 0 || c.hostPort > 65535 {
+		return nil, errors.New("unable to connect. Invalid port")
+	}
+
+	cfg = make([]string, 0, len(c.customOptions)+24)
+
+	cfg = append(cfg, "client")
+	cfg = append(cfg, fmt.Sprintf("management %s %d", miAddr, miPort))
+	cfg = append(cfg, "management-client")
+	cfg = append(cfg, "management-hold")
+	if c.customAuthUserPass {
+		cfg = append(cfg, "auth-user-pass")
+		cfg = append(cfg, "auth-nocache")
+	}
+	cfg = append(cfg, "management-query-passwords")
+	cfg = append(cfg, "management-signal")
+
+	if len(logFile) > 0 && logger.IsEnabled() {
+		cfg = append(cfg, fmt.Sprintf(`log "%s"`, logFile))
+	}
+
+	cfg = append(cfg, "dev tun")
+	if c.tcp {
+		cfg = append(cfg, "proto tcp-client")
+	} else {
+		cfg = append(cfg, "proto udp")
+	}
+	cfg = append(cfg, fmt.Sprintf("remote %s %d", c.hostIP, c.hostPort))
+	cfg = append(cfg, "resolv-retry infinite")
+	cfg = append(cfg, "nobind")
+	cfg = append(cfg, fmt.Sprintf("verb %d", DefaultLogVerbosity))
+
+	if upCmd := platform.OpenvpnUpScript(); upCmd != "" {
+		cfg = append(cfg, "up \""+upCmd+" "+upDownScriptArgs+"\"")
+	}
+	if downCmd := platform.OpenvpnDownScript(); downCmd != "" {
+		cfg = append(cfg, "down \""+downCmd+" "+upDownScriptArgs+"\"")
+	}
+	cfg = append(cfg, "script-security 2")
+
+	return append(cfg, c.customOptions...), nil
+}
+
+// redactInlineBlocks replaces the content of inline blocks (e.g. '<key>...</key>') by '***'
+func redactInlineBlocks(cfg []string) []string {
+	ret := make([]string, 0, len(cfg))
+	closingTag := ""
+	for _, line := range cfg {
+		if len(closingTag) > 0 {
+			if strings.ToLower(line) == closingTag {
+				ret = append(ret, "***", line)
+				closingTag = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "<") && strings.HasSuffix(line, ">") && !strings.HasPrefix(line, "</") {
+			closingTag = "</" + strings.ToLower(line[1:])
+		}
+		ret = append(ret, line)
+	}
+	return ret
+}
+
 // merge current parameters with user-defined parameters
 func addUserDefinedParameters(currParams []string, userParams string) ([]string, error) {
 	if len(userParams) <= 0 {
@@ -0,0 +1,200 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package openvpn
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CustomConfig - OpenVPN configuration imported by the user (standard '.ovpn' file of a self-hosted server)
+type CustomConfig struct {
+	RemoteHost     string // hostname or IP address of the server (the first 'remote' option)
+	RemotePort     int
+	TCP            bool
+	IsAuthUserPass bool     // the server requires username/password ('auth-user-pass')
+	Options        []string // options and inline blocks (e.g. '<ca>...</ca>') which are passed to OpenVPN as is
+	Ignored        []string // options which are defined by the daemon (e.g. 'up'/'down' scripts are never executed)
+}
+
+// customDaemonDefinedOptions - options of the imported configuration which are always defined by the daemon (they are ignored)
+var customDaemonDefinedOptions = map[string]struct{}{
+	"client": {}, "remote-random": {}, "resolv-retry": {}, "lport": {}, "nobind": {}, "bind": {}, "auth-nocache": {}, "verb": {}, "mute": {},
+	"log": {}, "log-append": {}, "status": {}, "writepid": {}, "daemon": {}, "syslog": {}, "up": {}, "down": {},
+	"script-security": {}, "http-proxy": {}, "http-proxy-retry": {}, "socks-proxy": {}, "socks-proxy-retry": {},
+}
+
+// customAllowedOptions - client options of the imported configuration which are passed to OpenVPN as is.
+// OpenVPN is running with root privileges: only the options which do not execute anything,
+// do not load any modules and do not access local files are allowed. Any other option is rejected.
+var customAllowedOptions = map[string]struct{}{
+	// connection
+	"float": {}, "connect-retry": {}, "connect-retry-max": {}, "connect-timeout": {}, "server-poll-timeout": {},
+	"explicit-exit-notify": {}, "persist-key": {}, "persist-tun": {}, "persist-remote-ip": {}, "keepalive": {},
+	"ping": {}, "ping-restart": {}, "ping-exit": {}, "inactive": {}, "pull": {}, "pull-filter": {}, "topology": {},
+	// MTU and buffers
+	"tun-mtu": {}, "tun-mtu-extra": {}, "link-mtu": {}, "mssfix": {}, "fragment": {}, "mtu-disc": {}, "sndbuf": {}, "rcvbuf": {},
+	// routing and DNS
+	"route": {}, "route-ipv6": {}, "route-metric": {}, "route-delay": {}, "redirect-gateway": {}, "block-outside-dns": {},
+	"dhcp-option": {}, "tun-ipv6": {}, "ifconfig-nowarn": {},
+	// data channel and TLS
+	"cipher": {}, "data-ciphers": {}, "data-ciphers-fallback": {}, "ncp-ciphers": {}, "ncp-disable": {}, "auth": {},
+	"key-direction": {}, "tls-client": {}, "tls-version-min": {}, "tls-version-max": {}, "tls-cipher": {}, "tls-ciphersuites": {},
+	"tls-groups": {}, "tls-timeout": {}, "remote-cert-tls": {}, "remote-cert-ku": {}, "remote-cert-eku": {}, "verify-x509-name": {},
+	"ns-cert-type": {}, "reneg-sec": {}, "reneg-bytes": {}, "reneg-pkts": {}, "hand-window": {}, "tran-window": {},
+	"replay-window": {}, "mute-replay-warnings": {}, "auth-retry": {},
+	// compression
+	"compress": {}, "comp-lzo": {}, "allow-compression": {},
+}
+
+// customInlineFileOptions - options which can refer a file: only the inline content is supported (e.g. '<ca>...</ca>')
+var customInlineFileOptions = map[string]struct{}{
+	"ca": {}, "cert": {}, "key": {}, "tls-auth": {}, "tls-crypt": {}, "tls-crypt-v2": {}, "extra-certs": {},
+	"crl-verify": {}, "pkcs12": {}, "secret": {}, "dh": {},
+}
+
+// ParseCustomConfig parses the content of OpenVPN client configuration file.
+// The files (certificates, keys) must be embedded into the configuration as inline blocks; <connection> blocks are not supported.
+// Options which are not known to be safe (see customAllowedOptions) are rejected.
+func ParseCustomConfig(text string) (cfg CustomConfig, err error) {
+	var defaultPort int
+	var defaultProto string
+	var remoteProto string
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		// inline block: <tag> ... </tag>
+		if strings.HasPrefix(line, "<") && strings.HasSuffix(line, ">") && !strings.HasPrefix(line, "</") {
+			tag := strings.ToLower(line[1 : len(line)-1])
+			if _, ok := customInlineFileOptions[tag]; !ok {
+				return cfg, fmt.Errorf("line %d: inline block '%s' is not supported", lineNo, line)
+			}
+			block := []string{line}
+			isClosed := false
+			for scanner.Scan() {
+				lineNo++
+				blockLine := strings.TrimSpace(scanner.Text())
+				block = append(block, blockLine)
+				if strings.ToLower(blockLine) == "</"+tag+">" {
+					isClosed = true
+					break
+				}
+			}
+			if !isClosed {
+				return cfg, fmt.Errorf("inline block '%s' is not closed", line)
+			}
+			cfg.Options = append(cfg.Options, block...)
+			continue
+		}
+
+		fields := strings.Fields(line)
+		option := strings.ToLower(strings.TrimPrefix(fields[0], "--"))
+		args := fields[1:]
+
+		switch option {
+		case "remote":
+			if len(cfg.RemoteHost) > 0 {
+				cfg.Ignored = append(cfg.Ignored, line) // only the first remote is in use
+				continue
+			}
+			if len(args) < 1 {
+				return cfg, fmt.Errorf("line %d: remote host not defined", lineNo)
+			}
+			cfg.RemoteHost = args[0]
+			if len(args) > 1 {
+				if cfg.RemotePort, err = strconv.Atoi(args[1]); err != nil || cfg.RemotePort <= 0 || cfg.RemotePort > 65535 {
+					return cfg, fmt.Errorf("line %d: bad remote port '%s'", lineNo, args[1])
+				}
+			}
+			if len(args) > 2 {
+				remoteProto = args[2]
+			}
+			continue
+		case "port", "rport":
+			if len(args) < 1 {
+				return cfg, fmt.Errorf("line %d: port not defined", lineNo)
+			}
+			if defaultPort, err = strconv.Atoi(args[0]); err != nil || defaultPort <= 0 || defaultPort > 65535 {
+				return cfg, fmt.Errorf("line %d: bad port '%s'", lineNo, args[0])
+			}
+			continue
+		case "proto":
+			if len(args) > 0 {
+				defaultProto = args[0]
+			}
+			continue
+		case "dev":
+			if len(args) > 0 && !strings.HasPrefix(strings.ToLower(args[0]), "tun") {
+				return cfg, fmt.Errorf("line %d: only 'dev tun' is supported", lineNo)
+			}
+			continue
+		case "auth-user-pass":
+			if len(args) > 0 {
+				cfg.Ignored = append(cfg.Ignored, line) // credentials are passed through the management interface
+			}
+			cfg.IsAuthUserPass = true
+			continue
+		}
+
+		if _, ok := customDaemonDefinedOptions[option]; ok {
+			cfg.Ignored = append(cfg.Ignored, line)
+			continue
+		}
+		if _, ok := customInlineFileOptions[option]; ok {
+			if len(args) == 0 || args[0] != "[inline]" {
+				return cfg, fmt.Errorf("line %d: '%s' refers to a file; embed the content into the configuration: <%s>...</%s>", lineNo, option, option, option)
+			}
+		} else if _, ok := customAllowedOptions[option]; !ok {
+			return cfg, fmt.Errorf("line %d: option '%s' is not supported", lineNo, option)
+		}
+
+		cfg.Options = append(cfg.Options, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return cfg, err
+	}
+
+	if len(cfg.RemoteHost) == 0 {
+		return cfg, fmt.Errorf("remote server is not defined")
+	}
+	if cfg.RemotePort == 0 {
+		cfg.RemotePort = defaultPort
+	}
+	if cfg.RemotePort == 0 {
+		cfg.RemotePort = 1194 // OpenVPN default port
+	}
+	if len(remoteProto) == 0 {
+		remoteProto = defaultProto
+	}
+	cfg.TCP = strings.HasPrefix(strings.ToLower(remoteProto), "tcp")
+
+	// prevent user-defined data injection: the host is stored in the 'remote' option
+	if strings.ContainsAny(cfg.RemoteHost, "\"'\\") {
+		return cfg, fmt.Errorf("bad remote host '%s'", cfg.RemoteHost)
+	}
+	return cfg, nil
+}
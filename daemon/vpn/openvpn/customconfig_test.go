@@ -0,0 +1,143 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package openvpn
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCustomConfig(t *testing.T) {
+	text := `# exported from the server
+client
+dev tun
+proto tcp-client
+--remote vpn.example.com 443
+remote backup.example.com 1194 udp
+resolv-retry infinite
+nobind
+auth-user-pass /etc/openvpn/creds.txt
+cipher AES-256-GCM
+; commented out
+up /etc/openvpn/up.sh
+key-direction 1
+<ca>
+-----BEGIN CERTIFICATE-----
+MIIB
+-----END CERTIFICATE-----
+</ca>
+tls-auth [inline] 1
+`
+	cfg, err := ParseCustomConfig(text)
+	if err != nil {
+		t.Fatalf("ParseCustomConfig() error: %v", err)
+	}
+
+	want := CustomConfig{
+		RemoteHost:     "vpn.example.com",
+		RemotePort:     443,
+		TCP:            true,
+		IsAuthUserPass: true,
+		Options: []string{
+			"cipher AES-256-GCM",
+			"key-direction 1",
+			"<ca>", "-----BEGIN CERTIFICATE-----", "MIIB", "-----END CERTIFICATE-----", "</ca>",
+			"tls-auth [inline] 1",
+		},
+		Ignored: []string{
+			"client",
+			"remote backup.example.com 1194 udp",
+			"resolv-retry infinite",
+			"nobind",
+			"auth-user-pass /etc/openvpn/creds.txt",
+			"up /etc/openvpn/up.sh",
+		},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("ParseCustomConfig() =\n%+v\nwant\n%+v", cfg, want)
+	}
+}
+
+func TestParseCustomConfigDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		wantPort int
+		wantTCP  bool
+	}{
+		{"OpenVPN defaults", "remote 192.0.2.1", 1194, false},
+		{"port option", "port 443\nproto tcp\nremote 192.0.2.1", 443, true},
+		{"remote overrides port and proto", "port 443\nproto tcp\nremote 192.0.2.1 53 udp", 53, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseCustomConfig(tt.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.RemotePort != tt.wantPort || cfg.TCP != tt.wantTCP {
+				t.Errorf("port=%d tcp=%v, want port=%d tcp=%v", cfg.RemotePort, cfg.TCP, tt.wantPort, tt.wantTCP)
+			}
+		})
+	}
+}
+
+func TestParseCustomConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{"no remote", "client\ndev tun\n", "remote server is not defined"},
+		{"remote without host", "remote\n", "remote host not defined"},
+		{"bad remote port", "remote 192.0.2.1 70000\n", "bad remote port"},
+		{"bad port", "port abc\nremote 192.0.2.1\n", "bad port"},
+		{"port without value", "port\nremote 192.0.2.1\n", "port not defined"},
+		{"tap device", "dev tap\nremote 192.0.2.1\n", "only 'dev tun' is supported"},
+		{"file reference", "remote 192.0.2.1\nca /etc/openvpn/ca.crt\n", "refers to a file"},
+		{"unsupported inline block", "remote 192.0.2.1\n<connection>\nremote 192.0.2.2\n</connection>\n", "inline block '<connection>' is not supported"},
+		{"inline block not closed", "remote 192.0.2.1\n<ca>\nMIIB\n", "inline block '<ca>' is not closed"},
+		{"quote in host", "remote \"192.0.2.1\n", "bad remote host"},
+		{"inline option without [inline]", "remote 192.0.2.1\ntls-auth\n", "refers to a file"},
+		{"unknown option", "remote 192.0.2.1\nfoo-bar 1\n", "option 'foo-bar' is not supported"},
+		// options which execute code, load modules or access local files with root privileges
+		{"pkcs11-providers", "remote 192.0.2.1\npkcs11-providers /home/u/x.so\n", "option 'pkcs11-providers' is not supported"},
+		{"engine", "remote 192.0.2.1\nengine /home/u/x.so\n", "option 'engine' is not supported"},
+		{"providers", "remote 192.0.2.1\nproviders legacy default\n", "option 'providers' is not supported"},
+		{"replay-persist", "remote 192.0.2.1\nreplay-persist /etc/passwd\n", "option 'replay-persist' is not supported"},
+		{"capath", "remote 192.0.2.1\ncapath /home/u\n", "option 'capath' is not supported"},
+		{"plugin", "remote 192.0.2.1\nplugin /home/u/x.so\n", "option 'plugin' is not supported"},
+		{"route-up script", "remote 192.0.2.1\nroute-up /tmp/x.sh\n", "option 'route-up' is not supported"},
+		{"config", "remote 192.0.2.1\n--config /home/u/other.ovpn\n", "option 'config' is not supported"},
+		{"management", "remote 192.0.2.1\nmanagement 0.0.0.0 7505\n", "option 'management' is not supported"},
+		{"setenv", "remote 192.0.2.1\nsetenv opt pkcs11-providers /home/u/x.so\n", "option 'setenv' is not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCustomConfig(tt.text)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -34,11 +34,23 @@ import (
 	"sync"
 	"time"
 
+	"github.com/swapnilsparsh/devsVPN/daemon/helpers"
 	"github.com/swapnilsparsh/devsVPN/daemon/logger"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/platform"
 	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
 )
 
+const (
+	// DefaultLogVerbosity - OpenVPN log verbosity ('verb' option) defined in the configuration file
+	DefaultLogVerbosity = 4
+	// MaxLogVerbosity - max value of the OpenVPN 'verb' option
+	MaxLogVerbosity = 11
+
+	miBytecountIntervalSec = 1                // interval of '>BYTECOUNT:' notifications
+	miStatusInterval       = time.Second * 10 // interval of 'status 3' requests (when connected)
+)
+
 // ManagementInterface structure
 type ManagementInterface struct {
 	log *logger.Logger
@@ -60,18 +72,33 @@ type ManagementInterface struct {
 
 	pushReplyCmds []string
 	pushReplyDNS  net.IP
+
+	statsCallbacks protocol.StatsCallbacks
+	logVerbosity   int // 0 - default verbosity (defined by configuration file)
+
+	detailsMutex  sync.Mutex
+	details       vpn.OpenVpnDetails
+	statusReading bool // 'status' command result is being received
 }
 
 // StartManagementInterface - starts TCP interface to communicate with IVPN application (server to listen incoming connections)
-func StartManagementInterface(miSecret string, username string, password string, stateChan chan<- vpn.StateInfo) (mi *ManagementInterface, err error) {
+//   - Param 'logVerbosity' - OpenVPN log verbosity ('verb' option); 0 - use the value from configuration file
+func StartManagementInterface(miSecret string, username string, password string, stateChan chan<- vpn.StateInfo, statsCallbacks protocol.StatsCallbacks, logVerbosity int) (mi *ManagementInterface, err error) {
 	ret := &ManagementInterface{
 		secret:         miSecret,
 		isConnVerified: make(chan struct{}),
 
-		log:       logger.NewLogger("ovpnmi"),
-		stateChan: stateChan,
-		username:  username,
-		password:  password}
+		log:            logger.NewLogger("ovpnmi"),
+		stateChan:      stateChan,
+		username:       username,
+		password:       password,
+		statsCallbacks: statsCallbacks,
+		logVerbosity:   logVerbosity}
+
+	ret.details.LogVerbosity = DefaultLogVerbosity
+	if logVerbosity > 0 {
+		ret.details.LogVerbosity = logVerbosity
+	}
 
 	if err = ret.start(); err != nil {
 		return nil, fmt.Errorf("failed to start MI: %w", err)
@@ -151,6 +178,41 @@ func (i *ManagementInterface) GetRouteAddCommands() []string {
 	return ret
 }
 
+// Details returns the details of the connection: latest state, options pushed by the server and traffic statistics
+func (i *ManagementInterface) Details() vpn.OpenVpnDetails {
+	i.detailsMutex.Lock()
+	defer i.detailsMutex.Unlock()
+
+	ret := i.details
+	ret.PushedOptions.DnsServers = append([]string{}, i.details.PushedOptions.DnsServers...)
+	ret.PushedOptions.DnsDomains = append([]string{}, i.details.PushedOptions.DnsDomains...)
+	ret.PushedOptions.Routes = append([]vpn.OpenVpnRoute{}, i.details.PushedOptions.Routes...)
+	ret.PushedOptions.RoutesIPv6 = append([]string{}, i.details.PushedOptions.RoutesIPv6...)
+	ret.PushedOptions.Other = append([]string{}, i.details.PushedOptions.Other...)
+	return ret
+}
+
+// SetLogVerbosity changes OpenVPN log verbosity ('verb' option) of the running OpenVPN process.
+// Value 0 restores the default verbosity.
+func (i *ManagementInterface) SetLogVerbosity(verbosity int) error {
+	if verbosity < 0 || verbosity > MaxLogVerbosity {
+		return fmt.Errorf("OpenVPN log verbosity must be in range 0-%d", MaxLogVerbosity)
+	}
+	i.logVerbosity = verbosity
+	if verbosity == 0 {
+		verbosity = DefaultLogVerbosity
+	}
+
+	if err := i.sendResponse(fmt.Sprintf("verb %d", verbosity)); err != nil {
+		return err
+	}
+
+	i.detailsMutex.Lock()
+	i.details.LogVerbosity = verbosity
+	i.detailsMutex.Unlock()
+	return nil
+}
+
 func (i *ManagementInterface) HasRouteAddCommands() bool {
 	i.routeAddCmdsMutex.Lock()
 	defer i.routeAddCmdsMutex.Unlock()
@@ -267,6 +329,11 @@ func (i *ManagementInterface) miCommunication() {
 	// request version info
 	i.sendResponse("version")
 
+	// request the traffic statistics periodically (when connected)
+	stopStatusPoller := make(chan struct{})
+	defer close(stopStatusPoller)
+	go i.statusPoller(stopStatusPoller)
+
 	reader := bufio.NewReader(i.miConn)
 	for {
 		// will listen for message to process ending in newline (\n)
@@ -283,7 +350,14 @@ func (i *ManagementInterface) miCommunication() {
 			continue
 		}
 
-		i.log.Info("[<-]: ", message)
+		// result of 'status' command (it is not prefixed by '>')
+		if i.processStatusLine(message) {
+			continue
+		}
+
+		if !strings.HasPrefix(message, ">BYTECOUNT:") { // do not flood the log by statistics notifications
+			i.log.Info("[<-]: ", message)
+		}
 
 		columns := mesRegexp.FindStringSubmatch(message)
 		if len(columns) <= 2 {
@@ -325,7 +399,14 @@ func (i *ManagementInterface) miCommunication() {
 		case "INFO":
 
 		case "HOLD":
-			i.sendResponse("state on", "log on", "hold off", "hold release")
+			cmds := []string{"state on", "log on", fmt.Sprintf("bytecount %d", miBytecountIntervalSec)}
+			if i.logVerbosity > 0 {
+				cmds = append(cmds, fmt.Sprintf("verb %d", i.logVerbosity))
+			}
+			i.sendResponse(append(cmds, "hold off", "hold release")...)
+
+		case "BYTECOUNT":
+			i.onByteCount(msgText)
 
 		case "PASSWORD":
 			if strings.HasPrefix(msgText, "Verification Failed: 'Auth'") {
@@ -365,6 +446,7 @@ func (i *ManagementInterface) miCommunication() {
 				continue
 			}
 			stateStr := params[1]
+			i.onStateDetails(params)
 
 			state, err := vpn.ParseState(stateStr)
 			if err != nil {
@@ -424,6 +506,11 @@ func (i *ManagementInterface) miCommunication() {
 }
 func (i *ManagementInterface) onPushReplyCommands(cmds []string) {
 	// LOG:1586341059,,PUSH: Received control message: 'PUSH_REPLY,redirect-gateway def1,explicit-exit-notify 3,comp-lzo no,route-gateway 10.34.44.1,topology subnet,ping 10,ping-restart 60,dhcp-option DNS 10.34.44.1,ifconfig 10.34.44.19 255.255.252.0,peer-id 17,cipher AES-256-GCM'
+	pushedOptions := parsePushedOptions(cmds)
+	i.detailsMutex.Lock()
+	i.details.PushedOptions = pushedOptions
+	i.detailsMutex.Unlock()
+
 	var dns net.IP = nil
 	for idx, cmd := range cmds {
 		cmd = strings.ToLower(strings.TrimSpace(cmd))
@@ -445,6 +532,198 @@ func (i *ManagementInterface) onPushReplyCommands(cmds []string) {
 	i.pushReplyCmds = cmds
 }
 
+// onByteCount processes the '>BYTECOUNT:{BYTES_IN},{BYTES_OUT}' notification
+func (i *ManagementInterface) onByteCount(msgText string) {
+	cols := strings.Split(strings.TrimSpace(msgText), ",")
+	if len(cols) != 2 {
+		i.log.Error("BYTECOUNT format error: ", msgText)
+		return
+	}
+	bytesIn, errIn := strconv.ParseInt(cols[0], 10, 64)
+	bytesOut, errOut := strconv.ParseInt(cols[1], 10, 64)
+	if errIn != nil || errOut != nil {
+		i.log.Error("BYTECOUNT format error: ", msgText)
+		return
+	}
+
+	i.detailsMutex.Lock()
+	i.details.Statistics.BytesIn, i.details.Statistics.BytesOut = bytesIn, bytesOut
+	i.detailsMutex.Unlock()
+
+	if i.statsCallbacks.OnTransferDataCallback != nil {
+		i.statsCallbacks.OnTransferDataCallback(helpers.FormatBytes(bytesOut), helpers.FormatBytes(bytesIn))
+	}
+	if i.statsCallbacks.OnTransferBytesCallback != nil {
+		i.statsCallbacks.OnTransferBytesCallback(uint64(bytesOut), uint64(bytesIn))
+	}
+}
+
+// onStateDetails saves the fields of '>STATE:' notification:
+// (a) time, (b) state name, (c) description, (d) TUN/TAP local IPv4, (e) remote server address,
+// (f) remote server port, (g) local address, (h) local port, (i) TUN/TAP local IPv6
+func (i *ManagementInterface) onStateDetails(params []string) {
+	param := func(idx int) string {
+		if len(params) > idx {
+			return strings.TrimSpace(params[idx])
+		}
+		return ""
+	}
+
+	d := vpn.OpenVpnStateDetails{
+		State:        param(1),
+		Description:  param(2),
+		LocalTunIP:   param(3),
+		RemoteIP:     param(4),
+		LocalIP:      param(6),
+		LocalTunIPv6: param(8),
+	}
+	d.Time, _ = strconv.ParseInt(param(0), 10, 64)
+	d.RemotePort, _ = strconv.Atoi(param(5))
+	d.LocalPort, _ = strconv.Atoi(param(7))
+
+	i.detailsMutex.Lock()
+	i.details.State = d
+	i.detailsMutex.Unlock()
+}
+
+// statusPoller periodically requests the traffic statistics ('status 3' command) while the connection is established
+func (i *ManagementInterface) statusPoller(stop <-chan struct{}) {
+	ticker := time.NewTicker(miStatusInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			i.detailsMutex.Lock()
+			isConnected := i.details.State.State == "CONNECTED"
+			i.detailsMutex.Unlock()
+
+			if isConnected && !i.isDisconnectRequested {
+				i.sendResp(false, "status 3")
+			}
+		}
+	}
+}
+
+// processStatusLine processes the line of 'status 3' command result. Returns 'true' if the line belongs to the result.
+//
+//	OpenVPN STATISTICS
+//	Updated	2024-01-01 10:00:00
+//	TUN/TAP read bytes	1234
+//	...
+//	END
+func (i *ManagementInterface) processStatusLine(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "OpenVPN STATISTICS" {
+		i.statusReading = true
+		return true
+	}
+	if !i.statusReading || strings.HasPrefix(line, ">") { // real-time notifications can be received in the middle of the result
+		return false
+	}
+
+	i.detailsMutex.Lock()
+	defer i.detailsMutex.Unlock()
+
+	if line == "END" {
+		i.statusReading = false
+		i.details.Statistics.Updated = time.Now().Unix()
+		return true
+	}
+
+	// fields are separated by TAB ('status 3') or by comma ('status 1')
+	idx := strings.IndexAny(line, "\t,")
+	if idx < 0 {
+		return true
+	}
+	value, err := strconv.ParseInt(strings.TrimSpace(line[idx+1:]), 10, 64)
+	if err != nil {
+		return true // e.g. 'Updated' field
+	}
+
+	s := &i.details.Statistics
+	switch strings.ToLower(strings.TrimSpace(line[:idx])) {
+	case "tun/tap read bytes":
+		s.TunReadBytes = value
+	case "tun/tap write bytes":
+		s.TunWriteBytes = value
+	case "tcp/udp read bytes":
+		s.LinkReadBytes = value
+	case "tcp/udp write bytes":
+		s.LinkWriteBytes = value
+	case "auth read bytes":
+		s.AuthReadBytes = value
+	case "pre-compress bytes":
+		s.PreCompressBytes = value
+	case "post-compress bytes":
+		s.PostCompressBytes = value
+	case "pre-decompress bytes":
+		s.PreDecompressBytes = value
+	case "post-decompress bytes":
+		s.PostDecompressBytes = value
+	}
+	return true
+}
+
+// parsePushedOptions parses the options pushed by the server (PUSH_REPLY)
+func parsePushedOptions(cmds []string) vpn.OpenVpnPushedOptions {
+	var ret vpn.OpenVpnPushedOptions
+	for _, cmd := range cmds {
+		fields := strings.Fields(cmd)
+		if len(fields) == 0 {
+			continue
+		}
+		arg := func(idx int) string {
+			if len(fields) > idx {
+				return fields[idx]
+			}
+			return ""
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case "dhcp-option":
+			switch strings.ToUpper(arg(1)) {
+			case "DNS", "DNS6":
+				ret.DnsServers = append(ret.DnsServers, arg(2))
+			case "DOMAIN", "DOMAIN-SEARCH":
+				ret.DnsDomains = append(ret.DnsDomains, arg(2))
+			default:
+				ret.Other = append(ret.Other, strings.Join(fields, " "))
+			}
+		case "route":
+			route := vpn.OpenVpnRoute{Network: arg(1), Netmask: arg(2), Gateway: arg(3)}
+			route.Metric, _ = strconv.Atoi(arg(4))
+			ret.Routes = append(ret.Routes, route)
+		case "route-ipv6":
+			ret.RoutesIPv6 = append(ret.RoutesIPv6, strings.Join(fields[1:], " "))
+		case "route-gateway":
+			ret.RouteGateway = arg(1)
+		case "redirect-gateway":
+			ret.RedirectGateway = true
+			ret.RedirectFlags = strings.Join(fields[1:], " ")
+		case "ifconfig":
+			ret.IfconfigLocal, ret.IfconfigNetmask = arg(1), arg(2)
+		case "ifconfig-ipv6":
+			ret.IfconfigIPv6 = strings.Join(fields[1:], " ")
+		case "topology":
+			ret.Topology = arg(1)
+		case "cipher":
+			ret.Cipher = arg(1)
+		case "peer-id":
+			ret.PeerID, _ = strconv.Atoi(arg(1))
+		case "ping":
+			ret.PingSec, _ = strconv.Atoi(arg(1))
+		case "ping-restart":
+			ret.PingRestartSec, _ = strconv.Atoi(arg(1))
+		default:
+			ret.Other = append(ret.Other, strings.Join(fields, " "))
+		}
+	}
+	return ret
+}
+
 func (i *ManagementInterface) sendResponse(commands ...string) error {
 	for _, cmd := range commands {
 
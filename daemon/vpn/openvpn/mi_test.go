@@ -0,0 +1,181 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package openvpn
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/helpers"
+	"github.com/swapnilsparsh/devsVPN/daemon/logger"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol"
+	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+)
+
+func newTestMI() *ManagementInterface {
+	return &ManagementInterface{log: logger.NewLogger("ovpnmi")}
+}
+
+func TestParsePushedOptions(t *testing.T) {
+	cmds := []string{
+		"dhcp-option DNS 10.34.44.1",
+		"dhcp-option dns6 fd00::1",
+		"dhcp-option DOMAIN corp.example.com",
+		"dhcp-option DOMAIN-SEARCH example.com",
+		"dhcp-option NTP 10.0.0.1",
+		"route 10.0.0.0 255.0.0.0 10.34.44.1 100",
+		"route 192.168.0.0 255.255.0.0",
+		"route-ipv6 2000::/3 fd00::1",
+		"route-gateway 10.34.44.1",
+		"redirect-gateway def1 bypass-dhcp",
+		"ifconfig 10.34.44.10 255.255.255.0",
+		"ifconfig-ipv6 fd00::10/64 fd00::1",
+		"topology subnet",
+		"cipher AES-256-GCM",
+		"peer-id 7",
+		"ping 10",
+		"ping-restart 60",
+		"  ",
+		"tun-ipv6",
+	}
+
+	want := vpn.OpenVpnPushedOptions{
+		DnsServers: []string{"10.34.44.1", "fd00::1"},
+		DnsDomains: []string{"corp.example.com", "example.com"},
+		Routes: []vpn.OpenVpnRoute{
+			{Network: "10.0.0.0", Netmask: "255.0.0.0", Gateway: "10.34.44.1", Metric: 100},
+			{Network: "192.168.0.0", Netmask: "255.255.0.0"},
+		},
+		RoutesIPv6:      []string{"2000::/3 fd00::1"},
+		RouteGateway:    "10.34.44.1",
+		RedirectGateway: true,
+		RedirectFlags:   "def1 bypass-dhcp",
+		IfconfigLocal:   "10.34.44.10",
+		IfconfigNetmask: "255.255.255.0",
+		IfconfigIPv6:    "fd00::10/64 fd00::1",
+		Topology:        "subnet",
+		Cipher:          "AES-256-GCM",
+		PeerID:          7,
+		PingSec:         10,
+		PingRestartSec:  60,
+		Other:           []string{"dhcp-option NTP 10.0.0.1", "tun-ipv6"},
+	}
+
+	if got := parsePushedOptions(cmds); !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePushedOptions() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := parsePushedOptions(nil); !reflect.DeepEqual(got, vpn.OpenVpnPushedOptions{}) {
+		t.Errorf("parsePushedOptions(nil) = %+v, want empty", got)
+	}
+}
+
+func TestProcessStatusLine(t *testing.T) {
+	i := newTestMI()
+
+	if i.processStatusLine("TUN/TAP read bytes\t100") {
+		t.Fatal("line outside of the 'status' result must not be processed")
+	}
+
+	lines := []struct {
+		line string
+		want bool
+	}{
+		{"OpenVPN STATISTICS", true},
+		{"Updated\t2024-01-01 10:00:00", true},
+		{"TUN/TAP read bytes\t100", true},
+		{"TUN/TAP write bytes\t200", true},
+		{">BYTECOUNT:5,6", false}, // real-time notification in the middle of the result
+		{"TCP/UDP read bytes\t300", true},
+		{"TCP/UDP write bytes,400", true}, // 'status 1' format
+		{"Auth read bytes\t500", true},
+		{"pre-compress bytes\t1", true},
+		{"post-compress bytes\t2", true},
+		{"pre-decompress bytes\t3", true},
+		{"post-decompress bytes\t4", true},
+		{"Unknown field\t999", true},
+		{"no separator", true},
+		{"END", true},
+		{"TUN/TAP read bytes\t700", false}, // after the end of the result
+	}
+	for _, l := range lines {
+		if got := i.processStatusLine(l.line); got != l.want {
+			t.Errorf("processStatusLine(%q) = %v, want %v", l.line, got, l.want)
+		}
+	}
+
+	got := i.details.Statistics
+	if got.Updated == 0 {
+		t.Error("Updated time is not set after the end of the result")
+	}
+	got.Updated = 0
+	want := vpn.OpenVpnStatistics{
+		TunReadBytes:        100,
+		TunWriteBytes:       200,
+		LinkReadBytes:       300,
+		LinkWriteBytes:      400,
+		AuthReadBytes:       500,
+		PreCompressBytes:    1,
+		PostCompressBytes:   2,
+		PreDecompressBytes:  3,
+		PostDecompressBytes: 4,
+	}
+	if got != want {
+		t.Errorf("statistics = %+v, want %+v", got, want)
+	}
+}
+
+func TestOnByteCount(t *testing.T) {
+	var cbSent, cbReceived string
+	var cbBytesSent, cbBytesReceived uint64
+	cbCalls := 0
+
+	i := newTestMI()
+	i.statsCallbacks = protocol.StatsCallbacks{
+		OnTransferDataCallback: func(sent, received string) {
+			cbSent, cbReceived = sent, received
+			cbCalls++
+		},
+		OnTransferBytesCallback: func(sent, received uint64) {
+			cbBytesSent, cbBytesReceived = sent, received
+		},
+	}
+
+	i.onByteCount("2048,1024\n")
+	if s := i.details.Statistics; s.BytesIn != 2048 || s.BytesOut != 1024 {
+		t.Errorf("BytesIn/BytesOut = %d/%d, want 2048/1024", s.BytesIn, s.BytesOut)
+	}
+	if cbCalls != 1 || cbSent != helpers.FormatBytes(1024) || cbReceived != helpers.FormatBytes(2048) {
+		t.Errorf("callback: calls=%d sent=%q received=%q", cbCalls, cbSent, cbReceived)
+	}
+	if cbBytesSent != 1024 || cbBytesReceived != 2048 {
+		t.Errorf("bytes callback: sent=%d received=%d, want 1024/2048", cbBytesSent, cbBytesReceived)
+	}
+
+	for _, msg := range []string{"", "1", "1,2,3", "a,2", "1,b"} {
+		i.onByteCount(msg)
+		if s := i.details.Statistics; s.BytesIn != 2048 || s.BytesOut != 1024 {
+			t.Errorf("onByteCount(%q) changed statistics: %+v", msg, s)
+		}
+	}
+	if cbCalls != 1 {
+		t.Errorf("callback called %d times, want 1 (malformed notifications must be ignored)", cbCalls)
+	}
+}
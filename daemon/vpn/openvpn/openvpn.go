@@ -37,6 +37,7 @@ import (
 
 	"github.com/swapnilsparsh/devsVPN/daemon/logger"
 	"github.com/swapnilsparsh/devsVPN/daemon/obfsproxy"
+	"github.com/swapnilsparsh/devsVPN/daemon/protocol"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/platform"
 	"github.com/swapnilsparsh/devsVPN/daemon/shell"
@@ -111,6 +112,8 @@ type OpenVPN struct {
 	obfsProxyParams ObfsParams
 	extraParameters string // user-defined extra-parameters of OpenVPN configuration
 	connectParams   ConnectionParams
+	statsCallbacks  protocol.StatsCallbacks
+	logVerbosity    int // OpenVPN log verbosity ('verb' option); 0 - default
 
 	managementInterface *ManagementInterface
 	obfsproxy           *obfsproxy.Obfsproxy
@@ -142,9 +145,15 @@ func NewOpenVpnObject(
 	logFile string,
 	obfsoroxy ObfsParams,
 	extraParameters string,
-	connectionParams ConnectionParams) (*OpenVPN, error) {
+	connectionParams ConnectionParams,
+	statsCallbacks protocol.StatsCallbacks,
+	logVerbosity int) (*OpenVPN, error) {
 
-	if len(connectionParams.username) == 0 || len(connectionParams.password) == 0 {
+	if connectionParams.IsCustom() {
+		if connectionParams.customAuthUserPass && (len(connectionParams.username) == 0 || len(connectionParams.password) == 0) {
+			return nil, fmt.Errorf("OpenVPN user credentials not defined (required by 'auth-user-pass' option of the imported configuration)")
+		}
+	} else if len(connectionParams.username) == 0 || len(connectionParams.password) == 0 {
 		return nil, fmt.Errorf("OpenVPN user credentials not defined")
 	}
 
@@ -155,7 +164,9 @@ func NewOpenVpnObject(
 			logFile:         logFile,
 			obfsProxyParams: obfsoroxy,
 			extraParameters: extraParameters,
-			connectParams:   connectionParams},
+			connectParams:   connectionParams,
+			statsCallbacks:  statsCallbacks,
+			logVerbosity:    logVerbosity},
 		nil
 }
 
@@ -355,7 +366,7 @@ func (o *OpenVPN) Connect(stateChan chan<- vpn.StateInfo) (retErr error) {
 	miSecret := fmt.Sprintf("[IVPN_SECRET_%X%X]", rnd1, rnd2)
 
 	// start new management interface
-	mi, err := StartManagementInterface(miSecret, o.connectParams.username, o.connectParams.password, internalStateChan)
+	mi, err := StartManagementInterface(miSecret, o.connectParams.username, o.connectParams.password, internalStateChan, o.statsCallbacks, o.logVerbosity)
 	if err != nil {
 		return fmt.Errorf("failed to start MI: %w", err)
 	}
@@ -477,6 +488,25 @@ func (o *OpenVPN) doDisconnect() error {
 	return mi.SendDisconnect()
 }
 
+// Details returns the details of the connection obtained from the OpenVPN management interface
+func (o *OpenVPN) Details() (vpn.OpenVpnDetails, error) {
+	mi := o.managementInterface
+	if mi == nil {
+		return vpn.OpenVpnDetails{}, errors.New("OpenVPN MI is nil")
+	}
+	return mi.Details(), nil
+}
+
+// SetLogVerbosity changes OpenVPN log verbosity ('verb' option) of the running connection (0 - default verbosity)
+func (o *OpenVPN) SetLogVerbosity(verbosity int) error {
+	mi := o.managementInterface
+	if mi == nil {
+		return errors.New("OpenVPN MI is nil")
+	}
+	o.logVerbosity = verbosity
+	return mi.SetLogVerbosity(verbosity)
+}
+
 // Pause doing required operation for Pause (temporary restoring default DNS)
 func (o *OpenVPN) Pause() error {
 	o.pauseLocker.Lock()
@@ -142,7 +142,7 @@ type StateInfo struct {
 	ServerIP     net.IP                 // applicable only for 'CONNECTED' state
 	ServerPort   int                    // applicable only for 'CONNECTED' state (destination port)
 	V2RayProxy   v2r.V2RayTransportType // applicable only for 'CONNECTED' state
-	Obfsproxy    obfsproxy.Config       // applicable only for 'CONNECTED' state (OpenVPN)
+	Obfsproxy    obfsproxy.Config       // applicable only for 'CONNECTED' state (OpenVPN; WireGuard with pluggable transport)
 	ExitHostname string                 // applicable only for 'CONNECTED' state
 	Mtu          int                    // applicable only for 'CONNECTED' state (WireGuard)
 	IsAuthError  bool                   // applicable only for 'EXITING' state
@@ -224,3 +224,68 @@ func (e *ReconnectionRequiredError) Error() string {
 
 // Unwrap returns inner error
 func (e *ReconnectionRequiredError) Unwrap() error { return e.Err }
+
+// OpenVpnDetails - details of the OpenVPN connection obtained from the OpenVPN management interface
+type OpenVpnDetails struct {
+	State         OpenVpnStateDetails
+	PushedOptions OpenVpnPushedOptions
+	Statistics    OpenVpnStatistics
+	LogVerbosity  int // current OpenVPN log verbosity ('verb' option)
+}
+
+// OpenVpnStateDetails - fields of the latest '>STATE:' notification
+type OpenVpnStateDetails struct {
+	Time         int64 // unix time (seconds)
+	State        string
+	Description  string // e.g. reason of RECONNECTING or EXITING
+	LocalTunIP   string
+	RemoteIP     string
+	RemotePort   int
+	LocalIP      string
+	LocalPort    int
+	LocalTunIPv6 string
+}
+
+// OpenVpnRoute - route pushed by the server (e.g. 'route 10.0.0.0 255.0.0.0 vpn_gateway')
+type OpenVpnRoute struct {
+	Network string
+	Netmask string `json:",omitempty"`
+	Gateway string `json:",omitempty"`
+	Metric  int    `json:",omitempty"`
+}
+
+// OpenVpnPushedOptions - options pushed by the server (PUSH_REPLY)
+type OpenVpnPushedOptions struct {
+	DnsServers      []string
+	DnsDomains      []string // 'dhcp-option DOMAIN' and 'dhcp-option DOMAIN-SEARCH'
+	Routes          []OpenVpnRoute
+	RoutesIPv6      []string
+	RouteGateway    string
+	RedirectGateway bool   // 'redirect-gateway' option pushed: all traffic goes through the tunnel
+	RedirectFlags   string // flags of 'redirect-gateway' option (e.g. "def1")
+	IfconfigLocal   string
+	IfconfigNetmask string // netmask ('topology subnet') or remote endpoint ('topology net30', 'p2p')
+	IfconfigIPv6    string
+	Topology        string
+	Cipher          string
+	PeerID          int
+	PingSec         int
+	PingRestartSec  int
+	Other           []string // options which are not parsed
+}
+
+// OpenVpnStatistics - traffic statistics of the OpenVPN connection ('>BYTECOUNT:' notifications and 'status 3' command)
+type OpenVpnStatistics struct {
+	Updated             int64 // unix time of the latest 'status' result (seconds)
+	BytesIn             int64 // from '>BYTECOUNT:'
+	BytesOut            int64 // from '>BYTECOUNT:'
+	TunReadBytes        int64
+	TunWriteBytes       int64
+	LinkReadBytes       int64 // 'TCP/UDP read bytes'
+	LinkWriteBytes      int64 // 'TCP/UDP write bytes'
+	AuthReadBytes       int64
+	PreCompressBytes    int64
+	PostCompressBytes   int64
+	PreDecompressBytes  int64
+	PostDecompressBytes int64
+}
@@ -0,0 +1,190 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package wireguard
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// CustomConfig - WireGuard configuration imported by the user (standard '.conf' file of a self-hosted server)
+type CustomConfig struct {
+	PrivateKey    string
+	LocalIP       net.IP // IPv4 address of the interface ('Address')
+	DnsServers    []net.IP
+	Mtu           int // 0 - default MTU
+	PeerPublicKey string
+	PresharedKey  string
+	EndpointHost  string // hostname or IP address of the server
+	EndpointPort  int
+	AllowedIPs    string
+	Ignored       []string // options which are not supported (e.g. 'PostUp' scripts are never executed)
+}
+
+// ParseCustomConfig parses the content of WireGuard configuration file ('wg-quick' format).
+// Only one [Peer] section is supported.
+func ParseCustomConfig(text string) (cfg CustomConfig, err error) {
+	section := ""
+	peers := 0
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if idx := strings.IndexAny(line, "#;"); idx >= 0 {
+			line = line[:idx] // comment
+		}
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			switch section {
+			case "interface":
+			case "peer":
+				if peers++; peers > 1 {
+					return cfg, fmt.Errorf("only one [Peer] section is supported")
+				}
+			default:
+				return cfg, fmt.Errorf("line %d: unknown section '%s'", lineNo, line)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return cfg, fmt.Errorf("line %d: 'Key = Value' expected", lineNo)
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+		switch section + "." + key {
+		case "interface.privatekey":
+			cfg.PrivateKey = value
+		case "interface.address":
+			for _, a := range strings.Split(value, ",") {
+				ip, _, err := net.ParseCIDR(strings.TrimSpace(a))
+				if err != nil {
+					if ip = net.ParseIP(strings.TrimSpace(a)); ip == nil {
+						return cfg, fmt.Errorf("line %d: bad Address '%s'", lineNo, a)
+					}
+				}
+				if ip.To4() != nil && cfg.LocalIP == nil {
+					cfg.LocalIP = ip.To4()
+				} else if ip.To4() == nil {
+					cfg.Ignored = append(cfg.Ignored, "Address = "+strings.TrimSpace(a))
+				}
+			}
+		case "interface.dns":
+			for _, d := range strings.Split(value, ",") {
+				if ip := net.ParseIP(strings.TrimSpace(d)); ip != nil {
+					cfg.DnsServers = append(cfg.DnsServers, ip)
+				} else {
+					cfg.Ignored = append(cfg.Ignored, "DNS = "+strings.TrimSpace(d)) // search domains
+				}
+			}
+		case "interface.mtu":
+			if cfg.Mtu, err = strconv.Atoi(value); err != nil || cfg.Mtu < 1280 || cfg.Mtu > 65535 {
+				return cfg, fmt.Errorf("line %d: bad MTU value (acceptable interval is: [1280 - 65535])", lineNo)
+			}
+		case "peer.publickey":
+			cfg.PeerPublicKey = value
+		case "peer.presharedkey":
+			cfg.PresharedKey = value
+		case "peer.endpoint":
+			host, port, err := net.SplitHostPort(value)
+			if err != nil {
+				return cfg, fmt.Errorf("line %d: bad Endpoint '%s': %w", lineNo, value, err)
+			}
+			if cfg.EndpointPort, err = strconv.Atoi(port); err != nil || cfg.EndpointPort <= 0 || cfg.EndpointPort > 65535 {
+				return cfg, fmt.Errorf("line %d: bad Endpoint port '%s'", lineNo, port)
+			}
+			cfg.EndpointHost = host
+		case "peer.allowedips":
+			var nets []string
+			for _, n := range strings.Split(value, ",") {
+				if _, _, err := net.ParseCIDR(strings.TrimSpace(n)); err != nil {
+					return cfg, fmt.Errorf("line %d: bad AllowedIPs value '%s'", lineNo, n)
+				}
+				nets = append(nets, strings.TrimSpace(n))
+			}
+			if len(cfg.AllowedIPs) > 0 {
+				nets = append([]string{cfg.AllowedIPs}, nets...)
+			}
+			cfg.AllowedIPs = strings.Join(nets, ", ")
+		case "interface.listenport", "peer.persistentkeepalive":
+			// the local port and keepalive interval are defined by the daemon
+		default:
+			if len(section) == 0 {
+				return cfg, fmt.Errorf("line %d: option outside of [Interface] or [Peer] section", lineNo)
+			}
+			cfg.Ignored = append(cfg.Ignored, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return cfg, err
+	}
+
+	// prevent user-defined data injection: ensure that nothing except the base64 keys will be stored in the configuration
+	for _, k := range []struct {
+		name, value string
+		optional    bool
+	}{
+		{"PrivateKey", cfg.PrivateKey, false},
+		{"PublicKey", cfg.PeerPublicKey, false},
+		{"PresharedKey", cfg.PresharedKey, true},
+	} {
+		if len(k.value) == 0 && k.optional {
+			continue
+		}
+		if key, err := base64.StdEncoding.DecodeString(k.value); err != nil || len(key) != 32 {
+			return cfg, fmt.Errorf("%s is not defined or it is not a valid WireGuard key", k.name)
+		}
+	}
+	if cfg.LocalIP == nil {
+		return cfg, fmt.Errorf("IPv4 address of the interface is not defined")
+	}
+	if len(cfg.EndpointHost) == 0 {
+		return cfg, fmt.Errorf("endpoint of the peer is not defined")
+	}
+	if len(cfg.AllowedIPs) == 0 {
+		return cfg, fmt.Errorf("AllowedIPs of the peer are not defined")
+	}
+	return cfg, nil
+}
+
+// CreateCustomConnectionParams initializes connection parameters for the user-imported configuration.
+// 'endpointIP' - resolved IP address of the peer endpoint (it is allowed by the firewall)
+func CreateCustomConnectionParams(cfg CustomConfig, endpointIP net.IP) ConnectionParams {
+	dnsServers := make([]string, 0, len(cfg.DnsServers))
+	for _, ip := range cfg.DnsServers {
+		dnsServers = append(dnsServers, ip.String())
+	}
+
+	// The address of the server inside the tunnel is not known for the custom configurations:
+	// using the local interface address (point-to-point routes are bound to the tunnel interface)
+	cp := CreateConnectionParams("", cfg.EndpointPort, endpointIP, cfg.PeerPublicKey, cfg.LocalIP, "", cfg.Mtu, strings.Join(dnsServers, ","), cfg.AllowedIPs)
+	cp.SetCredentials("", cfg.PrivateKey, "", cfg.PresharedKey, cfg.LocalIP)
+	return cp
+}
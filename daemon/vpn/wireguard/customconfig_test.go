@@ -0,0 +1,118 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package wireguard
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testKey1 = "yAnz5TF+lXXJte14tji3zlMNq+hd2rYUIgJBgB3fBmk="
+	testKey2 = "xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg="
+	testKey3 = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+)
+
+func TestParseCustomConfig(t *testing.T) {
+	text := `# exported from the server
+[Interface]
+PrivateKey = ` + testKey1 + `
+Address = 10.8.0.2/24, fd00::2/64
+DNS = 1.1.1.1, 9.9.9.9, corp.example.com
+MTU = 1380
+ListenPort = 51820
+PostUp = iptables -A FORWARD -j ACCEPT ; comment
+
+[Peer]
+PublicKey = ` + testKey2 + `
+PresharedKey = ` + testKey3 + `
+Endpoint = vpn.example.com:51820
+AllowedIPs = 0.0.0.0/0
+AllowedIPs = 10.0.0.0/8 # second line
+PersistentKeepalive = 25
+`
+	cfg, err := ParseCustomConfig(text)
+	if err != nil {
+		t.Fatalf("ParseCustomConfig() error: %v", err)
+	}
+
+	want := CustomConfig{
+		PrivateKey:    testKey1,
+		LocalIP:       net.ParseIP("10.8.0.2").To4(),
+		DnsServers:    []net.IP{net.ParseIP("1.1.1.1"), net.ParseIP("9.9.9.9")},
+		Mtu:           1380,
+		PeerPublicKey: testKey2,
+		PresharedKey:  testKey3,
+		EndpointHost:  "vpn.example.com",
+		EndpointPort:  51820,
+		AllowedIPs:    "0.0.0.0/0, 10.0.0.0/8",
+		Ignored:       []string{"Address = fd00::2/64", "DNS = corp.example.com", "PostUp = iptables -A FORWARD -j ACCEPT"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("ParseCustomConfig() =\n%+v\nwant\n%+v", cfg, want)
+	}
+}
+
+func TestParseCustomConfigErrors(t *testing.T) {
+	iface := "[Interface]\nPrivateKey = " + testKey1 + "\nAddress = 10.8.0.2/32\n"
+	peer := "[Peer]\nPublicKey = " + testKey2 + "\nEndpoint = 192.0.2.1:51820\nAllowedIPs = 0.0.0.0/0\n"
+
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{"valid", iface + peer, ""},
+		{"IPv6 endpoint", iface + strings.Replace(peer, "192.0.2.1:51820", "[2001:db8::1]:51820", 1), ""},
+		{"two peers", iface + peer + peer, "only one [Peer] section"},
+		{"unknown section", iface + "[Proxy]\n" + peer, "unknown section"},
+		{"no separator", iface + "SaveConfig\n" + peer, "'Key = Value' expected"},
+		{"option outside of section", "PrivateKey = " + testKey1 + "\n" + iface + peer, "outside of [Interface] or [Peer]"},
+		{"bad address", strings.Replace(iface, "10.8.0.2/32", "10.8.0", 1) + peer, "bad Address"},
+		{"bad MTU", iface + "MTU = 500\n" + peer, "bad MTU"},
+		{"endpoint without port", iface + strings.Replace(peer, "192.0.2.1:51820", "192.0.2.1", 1), "bad Endpoint"},
+		{"bad endpoint port", iface + strings.Replace(peer, ":51820", ":70000", 1), "bad Endpoint port"},
+		{"bad AllowedIPs", iface + strings.Replace(peer, "0.0.0.0/0", "0.0.0.0", 1), "bad AllowedIPs"},
+		{"bad private key", strings.Replace(iface, testKey1, "key", 1) + peer, "PrivateKey is not defined"},
+		{"no public key", iface + strings.Replace(peer, "PublicKey = "+testKey2+"\n", "", 1), "PublicKey is not defined"},
+		{"bad preshared key", iface + peer + "PresharedKey = c2hvcnQ=\n", "PresharedKey is not defined"},
+		{"data after key", iface + strings.Replace(peer, testKey2, testKey2+" x", 1), "PublicKey is not defined"},
+		{"no IPv4 address", strings.Replace(iface, "10.8.0.2/32", "fd00::2/64", 1) + peer, "IPv4 address of the interface is not defined"},
+		{"no endpoint", iface + strings.Replace(peer, "Endpoint = 192.0.2.1:51820\n", "", 1), "endpoint of the peer is not defined"},
+		{"no AllowedIPs", iface + strings.Replace(peer, "AllowedIPs = 0.0.0.0/0\n", "", 1), "AllowedIPs of the peer are not defined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCustomConfig(tt.text)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -24,26 +24,13 @@ package wireguard
 
 import (
 	"fmt"
-	"math"
 	"time"
 
+	"github.com/swapnilsparsh/devsVPN/daemon/helpers"
 	"github.com/swapnilsparsh/devsVPN/daemon/protocol"
 	"golang.zx2c4.com/wireguard/wgctrl"
 )
 
-var byteUnits = []string{"Bytes", "KB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"}
-
-func formatBytes(bytes int64) string {
-	if bytes == 0 {
-		return "0 Bytes"
-	}
-
-	magnitude := int(math.Floor(math.Log(float64(bytes)) / math.Log(1024)))
-	value := float64(bytes) / math.Pow(1024, float64(magnitude))
-
-	return fmt.Sprintf("%.2f %s", value, byteUnits[magnitude])
-}
-
 // WaitForMultipleHandshakes waits for a handshake during 'timeout' time.
 // It returns channel that will be closed when handshake detected. In case of error, channel will contain error.
 // if stopTriggers is defined and at least one of it's elements == true: function stops and channel closes.
@@ -93,10 +80,13 @@ func WaitForWireguardMultipleHandshakesChan(tunnelName string, stopTriggers []*b
 				currentTxBytes := int64(peer.TransmitBytes)
 
 				// Convert bytes to a readable format
-				received := formatBytes(currentRxBytes)
-				sent := formatBytes(currentTxBytes)
+				received := helpers.FormatBytes(currentRxBytes)
+				sent := helpers.FormatBytes(currentTxBytes)
 
 				statisticsCallbacks.OnTransferDataCallback(sent, received)
+				if statisticsCallbacks.OnTransferBytesCallback != nil {
+					statisticsCallbacks.OnTransferBytesCallback(uint64(peer.TransmitBytes), uint64(peer.ReceiveBytes))
+				}
 
 				// Log the transfer speed
 				if logFunc != nil {
@@ -110,6 +100,9 @@ func WaitForWireguardMultipleHandshakesChan(tunnelName string, stopTriggers []*b
 							logFunc(fmt.Sprintf("New handshake detected for peer %s at %s", peer.PublicKey, peer.LastHandshakeTime))
 						}
 						statisticsCallbacks.OnHandshakeCallback(peer.LastHandshakeTime.String())
+						if statisticsCallbacks.OnHandshakeTimeCallback != nil {
+							statisticsCallbacks.OnHandshakeTimeCallback(peer.LastHandshakeTime)
+						}
 						previousHandshakeTimes[peer.PublicKey.String()] = peer.LastHandshakeTime
 
 						// Non-blocking send to retChan
@@ -135,6 +128,36 @@ func WaitForWireguardMultipleHandshakesChan(tunnelName string, stopTriggers []*b
 	return retChan
 }
 
+// PeerStats - statistics of the connection to the WireGuard peer (VPN server)
+type PeerStats struct {
+	LastHandshakeTime time.Time
+	ReceiveBytes      int64
+	TransmitBytes     int64
+}
+
+// GetPeerStats returns statistics of the first peer of the WireGuard interface
+func GetPeerStats(tunnelName string) (PeerStats, error) {
+	client, err := wgctrl.New()
+	if err != nil {
+		return PeerStats{}, err
+	}
+	defer client.Close()
+
+	dev, err := client.Device(tunnelName)
+	if err != nil {
+		return PeerStats{}, fmt.Errorf("failed to get info for '%s': %w", tunnelName, err)
+	}
+	if len(dev.Peers) == 0 {
+		return PeerStats{}, fmt.Errorf("no peers defined for '%s'", tunnelName)
+	}
+	peer := dev.Peers[0]
+	return PeerStats{
+		LastHandshakeTime: peer.LastHandshakeTime,
+		ReceiveBytes:      peer.ReceiveBytes,
+		TransmitBytes:     peer.TransmitBytes,
+	}, nil
+}
+
 func WaitForDisconnectChan(tunnelName string, isStop []*bool) <-chan error {
 	return waitForWgInterfaceChan(tunnelName, true, isStop)
 }
@@ -0,0 +1,388 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package wireguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/netinfo"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	"golang.zx2c4.com/wireguard/conn"
+	"golang.zx2c4.com/wireguard/device"
+	"golang.zx2c4.com/wireguard/ipc"
+	"golang.zx2c4.com/wireguard/tun"
+	"golang.zx2c4.com/wireguard/wgctrl"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// Policy routing parameters in use when the peer's AllowedIPs contain the default route (same values as 'wg-quick' uses):
+// all packets (except the encrypted WireGuard packets, marked by fwmark) are routed through the tunnel
+const (
+	nativeFwMark       = 51820
+	nativeRoutingTable = 51820
+)
+
+// nativeTunnel - WireGuard interface configured in-process (without 'wg-quick' and 'wg' tools):
+// the kernel WireGuard module is configured through netlink; when the module is not available
+// the embedded userspace implementation (wireguard-go) with a TUN device is used.
+type nativeTunnel struct {
+	name        string
+	isUserspace bool
+	device      *device.Device // userspace implementation only
+	uapi        net.Listener   // userspace implementation only: configuration socket (used by wgctrl)
+	rules       []*netlink.Rule
+	fwMark      int // full tunnel only: the mark of the packets which are routed outside the tunnel (0 - not in use)
+}
+
+// nativeUp creates and configures the WireGuard interface
+func (wg *WireGuard) nativeUp() (retErr error) {
+	name := wg.getTunnelName()
+
+	privateKey, err := wgtypes.ParseKey(wg.connectParams.clientPrivateKey)
+	if err != nil {
+		return fmt.Errorf("bad WG private key: %w", err)
+	}
+	publicKey, err := wgtypes.ParseKey(wg.connectParams.hostPublicKey)
+	if err != nil {
+		return fmt.Errorf("bad WG public key: %w", err)
+	}
+	var presharedKey *wgtypes.Key
+	if len(wg.connectParams.presharedKey) > 0 {
+		psk, err := wgtypes.ParseKey(wg.connectParams.presharedKey)
+		if err != nil {
+			return fmt.Errorf("bad WG PresharedKey: %w", err)
+		}
+		presharedKey = &psk
+	}
+	allowedIPs, err := parseAllowedIPs(wg.connectParams.allowedIPs)
+	if err != nil {
+		return err
+	}
+	if wg.localPort, err = netinfo.GetFreeUDPPort(); err != nil {
+		return fmt.Errorf("unable to obtain free local port: %w", err)
+	}
+
+	mtu := wg.linuxMtu()
+	tunnel, err := nativeCreateInterface(name, mtu)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			tunnel.down()
+		}
+	}()
+
+	keepalive := time.Second * 25
+	cfg := wgtypes.Config{
+		PrivateKey:   &privateKey,
+		ListenPort:   &wg.localPort,
+		ReplacePeers: true,
+		Peers: []wgtypes.PeerConfig{{
+			PublicKey:                   publicKey,
+			PresharedKey:                presharedKey,
+			Endpoint:                    &net.UDPAddr{IP: wg.connectParams.hostIP, Port: wg.connectParams.hostPort},
+			PersistentKeepaliveInterval: &keepalive,
+			ReplaceAllowedIPs:           true,
+			AllowedIPs:                  allowedIPs,
+		}},
+	}
+	if isFullTunnel(allowedIPs) {
+		fwMark := nativeFwMark
+		cfg.FirewallMark = &fwMark
+		tunnel.fwMark = nativeFwMark
+	}
+
+	client, err := wgctrl.New()
+	if err != nil {
+		return fmt.Errorf("failed to configure WireGuard interface: %w", err)
+	}
+	defer client.Close()
+	if err := client.ConfigureDevice(name, cfg); err != nil {
+		return fmt.Errorf("failed to configure WireGuard interface: %w", err)
+	}
+
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return fmt.Errorf("WireGuard interface not found: %w", err)
+	}
+	addr := &netlink.Addr{IPNet: &net.IPNet{IP: wg.connectParams.clientLocalIP, Mask: net.CIDRMask(32, 32)}}
+	if err := netlink.AddrReplace(link, addr); err != nil {
+		return fmt.Errorf("failed to set WireGuard interface address: %w", err)
+	}
+	if err := netlink.LinkSetMTU(link, mtu); err != nil {
+		return fmt.Errorf("failed to set MTU (%d): %w", mtu, err)
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		return fmt.Errorf("failed to bring WireGuard interface up: %w", err)
+	}
+	if err := tunnel.addRoutes(link, allowedIPs); err != nil {
+		return err
+	}
+
+	wg.internals.mutex.Lock()
+	wg.internals.native = tunnel
+	wg.internals.mutex.Unlock()
+
+	implementation := "kernel module"
+	if tunnel.isUserspace {
+		implementation = "userspace (wireguard-go)"
+	}
+	log.Info(fmt.Sprintf("WireGuard interface '%s' configured (%s): MTU=%d; ListenPort=%d; Address=%s; Endpoint=%s:%d; AllowedIPs=%s",
+		name, implementation, mtu, wg.localPort, wg.connectParams.clientLocalIP, wg.connectParams.hostIP, wg.connectParams.hostPort, wg.connectParams.allowedIPs))
+	return nil
+}
+
+// nativeDown removes the WireGuard interface
+func (wg *WireGuard) nativeDown() error {
+	wg.internals.mutex.Lock()
+	tunnel := wg.internals.native
+	wg.internals.native = nil
+	wg.internals.mutex.Unlock()
+
+	if tunnel == nil {
+		return nil
+	}
+	return tunnel.down()
+}
+
+// UpdateCredentials applies the new WireGuard keys to the active tunnel without reconnection
+// (only possible when the local IP address in the tunnel is not changed)
+func (wg *WireGuard) UpdateCredentials(privateKey, presharedKey string, localIP net.IP) error {
+	wg.internals.mutex.Lock()
+	defer wg.internals.mutex.Unlock()
+
+	if wg.internals.native == nil || !wg.isRunning() || wg.isPaused() {
+		return fmt.Errorf("WireGuard is not connected")
+	}
+	if !localIP.Equal(wg.connectParams.clientLocalIP) {
+		return fmt.Errorf("local IP address changed")
+	}
+
+	pk, err := wgtypes.ParseKey(privateKey)
+	if err != nil {
+		return fmt.Errorf("bad WG private key: %w", err)
+	}
+	hostPublicKey, err := wgtypes.ParseKey(wg.connectParams.hostPublicKey)
+	if err != nil {
+		return fmt.Errorf("bad WG public key: %w", err)
+	}
+	peer := wgtypes.PeerConfig{PublicKey: hostPublicKey, UpdateOnly: true}
+	if len(presharedKey) > 0 || len(wg.connectParams.presharedKey) > 0 {
+		var psk wgtypes.Key // zero key - removes the PresharedKey
+		if len(presharedKey) > 0 {
+			if psk, err = wgtypes.ParseKey(presharedKey); err != nil {
+				return fmt.Errorf("bad WG PresharedKey: %w", err)
+			}
+		}
+		peer.PresharedKey = &psk
+	}
+
+	client, err := wgctrl.New()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	// private key and peer are applied in a single configuration request
+	if err := client.ConfigureDevice(wg.internals.native.name, wgtypes.Config{PrivateKey: &pk, Peers: []wgtypes.PeerConfig{peer}}); err != nil {
+		return fmt.Errorf("failed to update WireGuard credentials: %w", err)
+	}
+
+	wg.connectParams.clientPrivateKey = privateKey
+	wg.connectParams.presharedKey = presharedKey
+	return nil
+}
+
+// nativeCreateInterface creates the WireGuard interface: kernel module (netlink) or userspace implementation (if the kernel module is not available)
+func nativeCreateInterface(name string, mtu int) (*nativeTunnel, error) {
+	la := netlink.NewLinkAttrs()
+	la.Name = name
+	la.MTU = mtu
+	kernelErr := netlink.LinkAdd(&netlink.Wireguard{LinkAttrs: la})
+	if kernelErr == nil {
+		return &nativeTunnel{name: name}, nil
+	}
+	log.Warning(fmt.Sprintf("Unable to create WireGuard interface using the kernel module (%v). Using userspace implementation...", kernelErr))
+
+	tunDev, err := tun.CreateTUN(name, mtu)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TUN device: %w", err)
+	}
+	logger := &device.Logger{
+		Verbosef: func(format string, args ...any) {},
+		Errorf:   func(format string, args ...any) { log.Error("wireguard-go: " + fmt.Sprintf(format, args...)) },
+	}
+	dev := device.NewDevice(tunDev, conn.NewDefaultBind(), logger)
+
+	// configuration socket (/var/run/wireguard/<name>.sock): wgctrl is using it to configure the userspace device and read its statistics
+	fileUAPI, err := ipc.UAPIOpen(name)
+	if err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("failed to open WireGuard configuration socket: %w", err)
+	}
+	uapi, err := ipc.UAPIListen(name, fileUAPI)
+	if err != nil {
+		fileUAPI.Close()
+		dev.Close()
+		return nil, fmt.Errorf("failed to listen on WireGuard configuration socket: %w", err)
+	}
+	go func() {
+		for {
+			c, err := uapi.Accept()
+			if err != nil {
+				return // socket closed
+			}
+			go dev.IpcHandle(c)
+		}
+	}()
+
+	if err := dev.Up(); err != nil {
+		uapi.Close()
+		dev.Close()
+		return nil, fmt.Errorf("failed to start userspace WireGuard device: %w", err)
+	}
+	return &nativeTunnel{name: name, isUserspace: true, device: dev, uapi: uapi}, nil
+}
+
+// addRoutes routes the peer's AllowedIPs through the WireGuard interface (the same way as 'wg-quick' does)
+func (t *nativeTunnel) addRoutes(link netlink.Link, allowedIPs []net.IPNet) error {
+	for _, n := range allowedIPs {
+		if n.IP.To4() == nil {
+			continue // IPv6 is not configured in the tunnel
+		}
+		dst := n
+		if !isDefaultRoute(dst) {
+			if err := netlink.RouteReplace(&netlink.Route{LinkIndex: link.Attrs().Index, Dst: &dst, Scope: netlink.SCOPE_LINK}); err != nil {
+				return fmt.Errorf("failed to add route %s: %w", dst.String(), err)
+			}
+			continue
+		}
+
+		// default route: policy routing
+		//	ip -4 route add 0.0.0.0/0 dev wgprivateline table 51820
+		//	ip -4 rule add not fwmark 51820 table 51820
+		//	ip -4 rule add table main suppress_prefixlength 0
+		if err := netlink.RouteReplace(&netlink.Route{LinkIndex: link.Attrs().Index, Dst: &dst, Scope: netlink.SCOPE_LINK, Table: nativeRoutingTable}); err != nil {
+			return fmt.Errorf("failed to add default route: %w", err)
+		}
+		ruleNotMarked := netlink.NewRule()
+		ruleNotMarked.Family = netlink.FAMILY_V4
+		ruleNotMarked.Mark = nativeFwMark
+		ruleNotMarked.Invert = true
+		ruleNotMarked.Table = nativeRoutingTable
+		ruleSuppress := netlink.NewRule()
+		ruleSuppress.Family = netlink.FAMILY_V4
+		ruleSuppress.Table = unix.RT_TABLE_MAIN
+		ruleSuppress.SuppressPrefixlen = 0
+		for _, r := range []*netlink.Rule{ruleNotMarked, ruleSuppress} {
+			if err := netlink.RuleAdd(r); err != nil {
+				if errors.Is(err, syscall.EEXIST) {
+					continue // the rule belongs to someone else (e.g. 'wg-quick'): it must not be removed on disconnect
+				}
+				return fmt.Errorf("failed to add routing rule: %w", err)
+			}
+			t.rules = append(t.rules, r)
+		}
+		// the reverse path filter has to take into account the fwmark of incoming packets
+		if err := os.WriteFile("/proc/sys/net/ipv4/conf/all/src_valid_mark", []byte("1"), 0644); err != nil {
+			log.Warning(fmt.Sprintf("failed to enable 'src_valid_mark': %v", err))
+		}
+	}
+	return nil
+}
+
+// down removes the interface (routes through the interface are removed by the kernel) and the routing rules
+func (t *nativeTunnel) down() (retErr error) {
+	for _, r := range t.rules {
+		if err := netlink.RuleDel(r); err != nil && !errors.Is(err, syscall.ENOENT) {
+			retErr = errors.Join(retErr, fmt.Errorf("failed to remove routing rule: %w", err))
+		}
+	}
+	t.rules = nil
+
+	if t.isUserspace {
+		t.uapi.Close()
+		t.device.Close() // closes the TUN device (the interface is removed)
+		return retErr
+	}
+
+	link, err := netlink.LinkByName(t.name)
+	if err != nil {
+		var notFound netlink.LinkNotFoundError
+		if errors.As(err, &notFound) {
+			return retErr // already removed
+		}
+		return errors.Join(retErr, fmt.Errorf("WireGuard interface not found: %w", err))
+	}
+	if err := netlink.LinkDel(link); err != nil {
+		return errors.Join(retErr, fmt.Errorf("failed to remove WireGuard interface: %w", err))
+	}
+	return retErr
+}
+
+// parseAllowedIPs parses comma-separated list of networks in CIDR notation
+func parseAllowedIPs(allowedIPs string) (ret []net.IPNet, err error) {
+	for _, s := range strings.Split(allowedIPs, ",") {
+		if s = strings.TrimSpace(s); len(s) == 0 {
+			continue
+		}
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("bad AllowedIPs value '%s': %w", s, err)
+		}
+		ret = append(ret, *n)
+	}
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("AllowedIPs not defined")
+	}
+	return ret, nil
+}
+
+// isDefaultRoute returns 'true' when the network is the default route (0.0.0.0/0 or ::/0)
+func isDefaultRoute(n net.IPNet) bool {
+	ones, bits := n.Mask.Size()
+	return ones == 0 && bits > 0
+}
+
+// isFullTunnel returns 'true' when the AllowedIPs contain the default route (all the traffic is routed through the tunnel)
+func isFullTunnel(allowedIPs []net.IPNet) bool {
+	for _, n := range allowedIPs {
+		if isDefaultRoute(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// implGenerateKeys generates new WireGuard keys pair in-process ('wg' tool is not in use)
+func implGenerateKeys(_ string) (publicKey string, privateKey string, err error) {
+	key, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate WireGuard private key: %w", err)
+	}
+	return key.PublicKey().String(), key.String(), nil
+}
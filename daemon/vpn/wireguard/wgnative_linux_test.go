@@ -0,0 +1,104 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+package wireguard
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestParseAllowedIPs(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		wantErr string
+	}{
+		{name: "full tunnel", in: "0.0.0.0/0", want: []string{"0.0.0.0/0"}},
+		{name: "dual stack", in: "0.0.0.0/0, ::/0", want: []string{"0.0.0.0/0", "::/0"}},
+		{name: "split tunnel", in: "10.0.0.0/8,192.168.1.0/24", want: []string{"10.0.0.0/8", "192.168.1.0/24"}},
+		{name: "host bits are masked", in: "10.0.0.1/24", want: []string{"10.0.0.0/24"}},
+		{name: "single host", in: "10.0.0.1/32", want: []string{"10.0.0.1/32"}},
+		{name: "spaces and empty items", in: " 10.0.0.0/8 ,, 172.16.0.0/12 ,", want: []string{"10.0.0.0/8", "172.16.0.0/12"}},
+		{name: "empty", in: "", wantErr: "AllowedIPs not defined"},
+		{name: "only separators", in: " , ,", wantErr: "AllowedIPs not defined"},
+		{name: "no prefix length", in: "10.0.0.1", wantErr: "bad AllowedIPs value '10.0.0.1'"},
+		{name: "bad address", in: "0.0.0.0/0, 300.0.0.0/8", wantErr: "bad AllowedIPs value '300.0.0.0/8'"},
+		{name: "bad prefix length", in: "10.0.0.0/33", wantErr: "bad AllowedIPs value '10.0.0.0/33'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAllowedIPs(tt.in)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseAllowedIPs() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAllowedIPs() unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseAllowedIPs() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].String() != tt.want[i] {
+					t.Errorf("parseAllowedIPs()[%d] = %s, want %s", i, got[i].String(), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIsFullTunnel(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowedIPs string
+		want       bool
+	}{
+		{"ipv4 default route", "0.0.0.0/0", true},
+		{"ipv6 default route", "::/0", true},
+		{"default route among others", "10.0.0.0/8, 0.0.0.0/0", true},
+		{"split default route", "0.0.0.0/1, 128.0.0.0/1", false},
+		{"private networks", "10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16", false},
+		{"single host", "0.0.0.0/32", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowedIPs, err := parseAllowedIPs(tt.allowedIPs)
+			if err != nil {
+				t.Fatalf("parseAllowedIPs() unexpected error: %v", err)
+			}
+			if got := isFullTunnel(allowedIPs); got != tt.want {
+				t.Errorf("isFullTunnel(%s) = %v, want %v", tt.allowedIPs, got, tt.want)
+			}
+		})
+	}
+
+	if isFullTunnel(nil) {
+		t.Errorf("isFullTunnel(nil) = true, want false")
+	}
+	if isDefaultRoute(net.IPNet{}) {
+		t.Errorf("isDefaultRoute() = true for a network without mask")
+	}
+}
@@ -25,13 +25,8 @@ package wireguard
 import (
 	"fmt"
 	"net"
-	"os"
-	"strconv"
-	"strings"
 
-	"github.com/swapnilsparsh/devsVPN/daemon/helpers"
 	"github.com/swapnilsparsh/devsVPN/daemon/logger"
-	"github.com/swapnilsparsh/devsVPN/daemon/netinfo"
 	"github.com/swapnilsparsh/devsVPN/daemon/protocol"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
 	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
@@ -148,6 +143,43 @@ func (wg *WireGuard) GetTunnelName() string {
 func (wg *WireGuard) DestinationIP() net.IP {
 	return wg.connectParams.hostIP
 }
+
+// GatewayLocalIP - IP address of the VPN server inside the tunnel
+func (wg *WireGuard) GatewayLocalIP() net.IP {
+	return wg.connectParams.hostLocalIP
+}
+
+// PeerStats returns statistics of the connection to the VPN server (latest handshake, transferred data)
+func (wg *WireGuard) PeerStats() (PeerStats, error) {
+	return GetPeerStats(wg.GetTunnelName())
+}
+
+// IsCustomMtu returns 'true' when the MTU value is defined in the connection parameters
+func (wg *WireGuard) IsCustomMtu() bool {
+	return wg.connectParams.mtu > 0
+}
+
+// TunnelMTU returns the current MTU of the WireGuard interface
+func (wg *WireGuard) TunnelMTU() (int, error) {
+	ifc, err := net.InterfaceByName(wg.getTunnelName())
+	if err != nil {
+		return 0, fmt.Errorf("unable to get WireGuard interface: %w", err)
+	}
+	return ifc.MTU, nil
+}
+
+// SetTunnelMTU changes the MTU of the active WireGuard interface (no reconnection required)
+func (wg *WireGuard) SetTunnelMTU(mtu int) error {
+	// Using the same limitations as for custom MTU value
+	if mtu < 1280 || mtu > 65535 {
+		return fmt.Errorf("bad MTU value (acceptable interval is: [1280 - 65535])")
+	}
+	if wg.isDisconnected {
+		return fmt.Errorf("WireGuard is not connected")
+	}
+	return wg.setTunnelMtu(mtu)
+}
+
 func (wg *WireGuard) DefaultDNS() *[]net.IP {
 	if wg.isDisconnected {
 		return nil
@@ -243,86 +275,6 @@ func (wg *WireGuard) ResetManualDNS() error {
 	return wg.resetManualDNS()
 }
 
-func (wg *WireGuard) generateAndSaveConfigFile(cfgFilePath string) error {
-	cfg, err := wg.generateConfig()
-	if err != nil {
-		return fmt.Errorf("failed to generate WireGuard configuration: %w", err)
-	}
-
-	// write configuration into temporary file
-	configText := strings.Join(cfg, "\n")
-
-	err = os.WriteFile(cfgFilePath, []byte(configText), 0600)
-	if err != nil {
-		return fmt.Errorf("failed to save WireGuard configuration into a file: %w", err)
-	}
-
-	configToLog := strings.ReplaceAll(configText, wg.connectParams.clientPrivateKey, "***")
-	if len(wg.connectParams.presharedKey) > 0 {
-		configToLog = strings.ReplaceAll(configToLog, wg.connectParams.presharedKey, "***")
-	}
-	log.Info("WireGuard  configuration:",
-		"\n=====================\n",
-		configToLog,
-		"\n=====================\n")
-
-	return nil
-}
-
-func (wg *WireGuard) generateConfig() ([]string, error) {
-	log.Debug("================= generateConfig logs =======================")
-	localPort, err := netinfo.GetFreeUDPPort()
-	if err != nil {
-		return nil, fmt.Errorf("unable to obtain free local port: %w", err)
-	}
-
-	wg.localPort = localPort
-
-	// prevent user-defined data injection: ensure that nothing except the base64 public key will be stored in the configuration
-	if !helpers.ValidateBase64(wg.connectParams.hostPublicKey) {
-		len := len(wg.connectParams.hostPublicKey)
-		return nil, log.ErrorFE("WG public key '...%s', %d characters long, is not base64 string", helpers.Substring(wg.connectParams.hostPublicKey, len-4, 4), len)
-	}
-	if !helpers.ValidateBase64(wg.connectParams.clientPrivateKey) {
-		len := len(wg.connectParams.clientPrivateKey)
-		return nil, log.ErrorFE("WG private key '...%s', %d characters long, is not base64 string", helpers.Substring(wg.connectParams.clientPrivateKey, len-4, 4), len)
-	}
-	if len(wg.connectParams.presharedKey) > 0 && !helpers.ValidateBase64(wg.connectParams.presharedKey) {
-		len := len(wg.connectParams.presharedKey)
-		return nil, log.ErrorFE("WG PresharedKey '...%s', %d characters long, is not base64 string", helpers.Substring(wg.connectParams.presharedKey, len-4, 4), len)
-	}
-
-	interfaceCfg := []string{
-		"[Interface]",
-		"PrivateKey = " + wg.connectParams.clientPrivateKey,
-		"ListenPort = " + strconv.Itoa(wg.localPort)}
-
-	peerCfg := []string{
-		"", // newline between sections
-		"[Peer]",
-		"PublicKey = " + wg.connectParams.hostPublicKey,
-		"Endpoint = " + wg.connectParams.hostIP.String() + ":" + strconv.Itoa(wg.connectParams.hostPort),
-		"PersistentKeepalive = 25"}
-
-	if len(wg.connectParams.presharedKey) > 0 {
-		peerCfg = append(peerCfg, "PresharedKey = "+wg.connectParams.presharedKey)
-	}
-
-	// add some OS-specific configurations (if necessary)
-	iCfg, pCgf, err := wg.getOSSpecificConfigParams()
-	if err != nil {
-		log.Error(err)
-		return append(interfaceCfg, peerCfg...), err
-	}
-
-	interfaceCfg = append(interfaceCfg, iCfg...)
-	peerCfg = append(peerCfg, pCgf...)
-
-	log.Debug("============== generateConfig logs end =======================")
-
-	return append(interfaceCfg, peerCfg...), nil
-}
-
 func logFunc(mes string) {
 	log.Info(mes)
 }
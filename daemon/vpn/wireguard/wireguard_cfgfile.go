@@ -0,0 +1,115 @@
+//  Daemon for privateLINE Connect Desktop
+//  https://github.com/swapnilsparsh/devsVPN
+//
+//  Copyright (c) 2025 privateLINE, LLC.
+//
+//  This file is part of the Daemon for privateLINE Connect Desktop.
+//
+//  The Daemon for privateLINE Connect Desktop is free software: you can redistribute it and/or
+//  modify it under the terms of the GNU General Public License as published by the Free
+//  Software Foundation, either version 3 of the License, or (at your option) any later version.
+//
+//  The Daemon for privateLINE Connect Desktop is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+//  or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for more
+//  details.
+//
+//  You should have received a copy of the GNU General Public License
+//  along with the Daemon for privateLINE Connect Desktop. If not, see <https://www.gnu.org/licenses/>.
+
+//go:build darwin || windows
+// +build darwin windows
+
+package wireguard
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/swapnilsparsh/devsVPN/daemon/helpers"
+	"github.com/swapnilsparsh/devsVPN/daemon/netinfo"
+)
+
+// The WireGuard configuration file is in use only on macOS and Windows (Linux configures the interface in-process).
+
+func (wg *WireGuard) generateAndSaveConfigFile(cfgFilePath string) error {
+	cfg, err := wg.generateConfig()
+	if err != nil {
+		return fmt.Errorf("failed to generate WireGuard configuration: %w", err)
+	}
+
+	// write configuration into temporary file
+	configText := strings.Join(cfg, "\n")
+
+	err = os.WriteFile(cfgFilePath, []byte(configText), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to save WireGuard configuration into a file: %w", err)
+	}
+
+	configToLog := strings.ReplaceAll(configText, wg.connectParams.clientPrivateKey, "***")
+	if len(wg.connectParams.presharedKey) > 0 {
+		configToLog = strings.ReplaceAll(configToLog, wg.connectParams.presharedKey, "***")
+	}
+	log.Info("WireGuard  configuration:",
+		"\n=====================\n",
+		configToLog,
+		"\n=====================\n")
+
+	return nil
+}
+
+func (wg *WireGuard) generateConfig() ([]string, error) {
+	log.Debug("================= generateConfig logs =======================")
+	localPort, err := netinfo.GetFreeUDPPort()
+	if err != nil {
+		return nil, fmt.Errorf("unable to obtain free local port: %w", err)
+	}
+
+	wg.localPort = localPort
+
+	// prevent user-defined data injection: ensure that nothing except the base64 public key will be stored in the configuration
+	if !helpers.ValidateBase64(wg.connectParams.hostPublicKey) {
+		len := len(wg.connectParams.hostPublicKey)
+		return nil, log.ErrorFE("WG public key '...%s', %d characters long, is not base64 string", helpers.Substring(wg.connectParams.hostPublicKey, len-4, 4), len)
+	}
+	if !helpers.ValidateBase64(wg.connectParams.clientPrivateKey) {
+		len := len(wg.connectParams.clientPrivateKey)
+		return nil, log.ErrorFE("WG private key '...%s', %d characters long, is not base64 string", helpers.Substring(wg.connectParams.clientPrivateKey, len-4, 4), len)
+	}
+	if len(wg.connectParams.presharedKey) > 0 && !helpers.ValidateBase64(wg.connectParams.presharedKey) {
+		len := len(wg.connectParams.presharedKey)
+		return nil, log.ErrorFE("WG PresharedKey '...%s', %d characters long, is not base64 string", helpers.Substring(wg.connectParams.presharedKey, len-4, 4), len)
+	}
+
+	interfaceCfg := []string{
+		"[Interface]",
+		"PrivateKey = " + wg.connectParams.clientPrivateKey,
+		"ListenPort = " + strconv.Itoa(wg.localPort)}
+
+	peerCfg := []string{
+		"", // newline between sections
+		"[Peer]",
+		"PublicKey = " + wg.connectParams.hostPublicKey,
+		"Endpoint = " + wg.connectParams.hostIP.String() + ":" + strconv.Itoa(wg.connectParams.hostPort),
+		"PersistentKeepalive = 25"}
+
+	if len(wg.connectParams.presharedKey) > 0 {
+		peerCfg = append(peerCfg, "PresharedKey = "+wg.connectParams.presharedKey)
+	}
+
+	// add some OS-specific configurations (if necessary)
+	iCfg, pCgf, err := wg.getOSSpecificConfigParams()
+	if err != nil {
+		log.Error(err)
+		return append(interfaceCfg, peerCfg...), err
+	}
+
+	interfaceCfg = append(interfaceCfg, iCfg...)
+	peerCfg = append(peerCfg, pCgf...)
+
+	log.Debug("============== generateConfig logs end =======================")
+
+	return append(interfaceCfg, peerCfg...), nil
+}
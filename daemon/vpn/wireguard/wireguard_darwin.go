@@ -576,3 +576,21 @@ func (wg *WireGuard) getOSSpecificConfigParams() (interfaceCfg []string, peerCfg
 
 	return interfaceCfg, peerCfg, nil
 }
+
+// OutsideTunnelFwMark returns the firewall mark which routes the packets to the VPN server outside the tunnel (0 - the mark is not required).
+// The route to the VPN server through the default gateway is added on connection, so the mark is not required.
+func (wg *WireGuard) OutsideTunnelFwMark() (int, bool) {
+	return 0, true
+}
+
+// setTunnelMtu changes MTU of the WireGuard interface
+func (wg *WireGuard) setTunnelMtu(mtu int) error {
+	if err := shell.Exec(log, "/sbin/ifconfig", wg.getTunnelName(), "mtu", strconv.Itoa(mtu)); err != nil {
+		return fmt.Errorf("failed to set MTU (%d): %w", mtu, err)
+	}
+	return nil
+}
+
+func implGenerateKeys(wgToolBinaryPath string) (publicKey string, privateKey string, err error) {
+	return generateKeysByWgTool(wgToolBinaryPath)
+}
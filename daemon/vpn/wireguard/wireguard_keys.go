@@ -29,7 +29,13 @@ import (
 )
 
 // GenerateKeys generates new WireGuard keys pair
+// (wgToolBinaryPath is in use only on platforms where keys are generated by the 'wg' tool)
 func GenerateKeys(wgToolBinaryPath string) (publicKey string, privateKey string, err error) {
+	return implGenerateKeys(wgToolBinaryPath)
+}
+
+// generateKeysByWgTool generates new WireGuard keys pair using 'wg genkey' and 'wg pubkey'
+func generateKeysByWgTool(wgToolBinaryPath string) (publicKey string, privateKey string, err error) {
 	// private key
 	privCmd := exec.Command(wgToolBinaryPath, "genkey")
 	out, err1 := privCmd.Output()
@@ -25,8 +25,6 @@ package wireguard
 import (
 	"fmt"
 	"net"
-	"os/exec"
-	"path"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -36,8 +34,8 @@ import (
 	"github.com/swapnilsparsh/devsVPN/daemon/service/dns"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/firewall"
 	"github.com/swapnilsparsh/devsVPN/daemon/service/platform"
-	"github.com/swapnilsparsh/devsVPN/daemon/shell"
 	"github.com/swapnilsparsh/devsVPN/daemon/vpn"
+	"github.com/vishvananda/netlink"
 )
 
 type operation int
@@ -81,6 +79,7 @@ type internalVariables struct {
 	isPaused             atomic.Bool
 	resumeDisconnectChan chan *operationRequest // control connection pause\resume or disconnect from paused state
 	lastOpRequest        *operationRequest
+	native               *nativeTunnel // active WireGuard interface (protected by 'mutex')
 }
 
 func (wg *WireGuard) init() error {
@@ -93,18 +92,11 @@ func (wg *WireGuard) init() error {
 	// (e.g. process was terminated)
 	// In such situation, the 'wgprivateline' keeps active.
 	// We should close it in this case. Otherwise, new connection would not be established
-	wgInterfaceName := filepath.Base(wg.configFilePath)
-	wgInterfaceName = strings.TrimSuffix(wgInterfaceName, path.Ext(wgInterfaceName))
+	wgInterfaceName := wg.getTunnelName()
 	// stop current WG connection (if exists)
-	i, _ := net.InterfaceByName(wgInterfaceName)
-	if i != nil {
+	if link, err := netlink.LinkByName(wgInterfaceName); err == nil {
 		log.Info(fmt.Sprintf("Stopping WireGuard interface ('%s' expected to be stopped before the new connection)...", wgInterfaceName))
-		err := shell.Exec(log, "ip", "link", "set", "down", wgInterfaceName) // command: sudo ip link set down wgprivateline
-		if err != nil {
-			log.Warning(err)
-		}
-		err = shell.Exec(log, "ip", "link", "delete", wgInterfaceName) // command: sudo ip link delete wgprivateline
-		if err != nil {
+		if err := netlink.LinkDel(link); err != nil {
 			log.Warning(err)
 		}
 	}
@@ -148,8 +140,7 @@ func (wg *WireGuard) connect(stateChan chan<- vpn.StateInfo) error {
 		}
 	}
 	internalDisconnectFunc := func() error {
-		err := shell.Exec(log, wg.binaryPath, "down", wg.configFilePath)
-		if err != nil {
+		if err := wg.nativeDown(); err != nil {
 			return fmt.Errorf("failed to stop WireGuard: %w", err)
 		}
 		return nil
@@ -161,24 +152,12 @@ func (wg *WireGuard) connect(stateChan chan<- vpn.StateInfo) error {
 	for {
 		isResumeRequested := false
 
-		// generate configuration
-		err := wg.generateAndSaveConfigFile(wg.configFilePath)
-		if err != nil {
-			return fmt.Errorf("failed to save WG config file: %w", err)
-		}
-
-		// start WG
-		log.Info("Shell exec: ", wg.binaryPath, " up ", wg.configFilePath)
-		cmd := exec.Command(wg.binaryPath, "up", wg.configFilePath)
-		outBytes, err := cmd.CombinedOutput()
-		if err != nil {
-			if len(outBytes) > 0 {
-				log.Error(fmt.Sprintf("'%s' error. Output: %s", wg.binaryPath, string(outBytes)))
-			}
+		// start WG: create and configure the interface (kernel module or userspace implementation)
+		if err := wg.nativeUp(); err != nil {
 			return fmt.Errorf("failed to start WireGuard: %w", err)
 		}
 
-		err = func() error {
+		err := func() error {
 			// do not forget to restore DNS
 			defer func() {
 				internalRestoreDNSFunc()
@@ -202,8 +181,7 @@ func (wg *WireGuard) connect(stateChan chan<- vpn.StateInfo) error {
 				return err
 			}
 
-			wgInterfaceName := filepath.Base(wg.configFilePath)
-			wgInterfaceName = strings.TrimSuffix(wgInterfaceName, path.Ext(wgInterfaceName))
+			wgInterfaceName := wg.getTunnelName()
 
 			// wait until wireguard interface is available
 			func() {
@@ -370,63 +348,41 @@ func (wg *WireGuard) resetManualDNS() error {
 	return dns.DeleteManual(nil, wg.connectParams.clientLocalIP)
 }
 
-func (wg *WireGuard) getOSSpecificConfigParams() (interfaceCfg []string, peerCfg []string, err error) {
-	var MTU int
-	// if wg.connectParams.mtu > 0 {
-	// 	MTU = wg.connectParams.mtu
-	// } else {
-
-	if MTU, err = firewall.BestWireguardMtuForConditions(); err != nil {
-		err = log.ErrorFE("error firewall.BestWireguardMtuForConditions(): %w", err)
-		MTU = platform.WGDefaultMTU()
-	}
-	// }
-	interfaceCfg = append(interfaceCfg, fmt.Sprintf("MTU = %d", MTU))
-
-	interfaceCfg = append(interfaceCfg, "Address = "+wg.connectParams.clientLocalIP.String()+"/32")
-	interfaceCfg = append(interfaceCfg, "SaveConfig = true")
-
-	// Vlad: disabling per https://bugs.launchpad.net/ubuntu/+source/wireguard/+bug/1992491
-	// interfaceCfg = append(interfaceCfg, "DNS = "+wg.connectParams.dnsServers)
-	// // And per comment #9 there: https://bugs.launchpad.net/ubuntu/+source/wireguard/+bug/1992491/comments/9
-	// // 	PostUp = resolvectl dns %i <ip1> <ip2> ...; resolvectl domain %i \~domain1 \~domain2 ...
-	// postUpCmd := "PostUp = resolvectl dns %i"
-	// for _, dnsSrv := range strings.Split(wg.connectParams.dnsServers, ",") {
-	// 	postUpCmd += " " + strings.TrimSpace(dnsSrv)
-	// }
-	// postUpCmd += "; resolvectl domain %i"
-	// for _, plInternalDomain := range helpers.PrivatelineInternalDomains {
-	// 	postUpCmd += " \\~" + plInternalDomain
-	// }
-	// interfaceCfg = append(interfaceCfg, postUpCmd)
-
-	peerCfg = append(peerCfg, "AllowedIPs = "+wg.connectParams.allowedIPs)
-	return interfaceCfg, peerCfg, err
+func (wg *WireGuard) onRoutingChanged() error {
+	// do nothing for Linux
+	return nil
 }
 
-// TODO: Vlad - this was original IVPN version
-/*
-func (wg *WireGuard) getOSSpecificConfigParams() (interfaceCfg []string, peerCfg []string) {
-	ipv6LocalIP := wg.connectParams.GetIPv6ClientLocalIP()
-	ipv6LocalIPStr := ""
-	allowedIPsV6 := ""
-	if ipv6LocalIP != nil {
-		ipv6LocalIPStr = ", " + ipv6LocalIP.String()
-		allowedIPsV6 = ", ::/0"
+// linuxMtu returns the MTU for the WireGuard interface (considering other VPNs running on this machine)
+func (wg *WireGuard) linuxMtu() int {
+	MTU, err := firewall.BestWireguardMtuForConditions()
+	if err != nil {
+		log.ErrorFE("error firewall.BestWireguardMtuForConditions(): %w", err)
+		return platform.WGDefaultMTU()
 	}
+	return MTU
+}
 
-	if wg.connectParams.mtu > 0 {
-		interfaceCfg = append(interfaceCfg, fmt.Sprintf("MTU = %d", wg.connectParams.mtu))
+// OutsideTunnelFwMark returns the firewall mark which routes the packets to the VPN server outside the tunnel (0 - the mark is not required).
+// In full-tunnel mode all the packets without the mark are routed through the tunnel.
+// Returns 'false' when the tunnel is not active.
+func (wg *WireGuard) OutsideTunnelFwMark() (int, bool) {
+	wg.internals.mutex.Lock()
+	defer wg.internals.mutex.Unlock()
+	if wg.internals.native == nil {
+		return 0, false
 	}
-	interfaceCfg = append(interfaceCfg, "Address = "+wg.connectParams.clientLocalIP.String()+"/32"+ipv6LocalIPStr)
-	interfaceCfg = append(interfaceCfg, "SaveConfig = true")
-
-	peerCfg = append(peerCfg, "AllowedIPs = 0.0.0.0/0"+allowedIPsV6)
-	return interfaceCfg, peerCfg
+	return wg.internals.native.fwMark, true
 }
-*/
 
-func (wg *WireGuard) onRoutingChanged() error {
-	// do nothing for Linux
+// setTunnelMtu changes MTU of the WireGuard interface
+func (wg *WireGuard) setTunnelMtu(mtu int) error {
+	link, err := netlink.LinkByName(wg.getTunnelName())
+	if err != nil {
+		return fmt.Errorf("WireGuard interface not found: %w", err)
+	}
+	if err := netlink.LinkSetMTU(link, mtu); err != nil {
+		return fmt.Errorf("failed to set MTU (%d): %w", mtu, err)
+	}
 	return nil
 }
@@ -24,6 +24,7 @@ package wireguard
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"path"
 	"path/filepath"
@@ -665,3 +666,29 @@ func (wg *WireGuard) onRoutingChanged() error {
 	// do nothing for Windows
 	return nil
 }
+
+// OutsideTunnelFwMark returns the firewall mark which routes the packets to the VPN server outside the tunnel (0 - the mark is not required).
+// There is no route exception for the VPN server: returns 'false' when the VPN server is in the AllowedIPs
+// (e.g. full tunnel), so the packets to the VPN server are routed through the tunnel.
+func (wg *WireGuard) OutsideTunnelFwMark() (int, bool) {
+	for _, s := range strings.Split(wg.connectParams.allowedIPs, ",") {
+		if _, n, err := net.ParseCIDR(strings.TrimSpace(s)); err == nil && n.Contains(wg.connectParams.hostIP) {
+			return 0, false
+		}
+	}
+	return 0, true
+}
+
+// setTunnelMtu changes MTU of the WireGuard interface (the change is not persistent: it is reset when the interface is recreated)
+func (wg *WireGuard) setTunnelMtu(mtu int) error {
+	// command: netsh interface ipv4 set subinterface "privateLINE" mtu=1380 store=active
+	cmd := []string{"interface", "ipv4", "set", "subinterface", wg.getTunnelName(), fmt.Sprintf("mtu=%d", mtu), "store=active"}
+	if err := shell.Exec(log, netshBinaryPath, cmd...); err != nil {
+		return fmt.Errorf("failed to set MTU (%d): %w", mtu, err)
+	}
+	return nil
+}
+
+func implGenerateKeys(wgToolBinaryPath string) (publicKey string, privateKey string, err error) {
+	return generateKeysByWgTool(wgToolBinaryPath)
+}